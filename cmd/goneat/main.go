@@ -0,0 +1,515 @@
+// The goneat command line tool allows to run standard NEAT experiments with given configuration, to inspect and to
+// render genomes, to generate standalone Go code of genomes, and to resume experiments from population dumps.
+package main
+
+import (
+	"os"
+	"io"
+	"fmt"
+	"log"
+	"flag"
+	"time"
+	"errors"
+	"strings"
+	"math/rand"
+	"path/filepath"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/experiments/xor"
+	"github.com/yaricom/goNEAT/experiments/pole"
+	"github.com/yaricom/goNEAT/experiments/maze"
+	"github.com/yaricom/goNEAT/experiments/retina"
+	"github.com/yaricom/goNEAT/experiments/dataset"
+)
+
+const usage = `goneat is a tool to run NEAT experiments and to work with genomes.
+
+Usage:
+
+	goneat <command> [arguments]
+
+The commands are:
+
+	run      run experiment with given configuration and start genome
+	resume   resume experiment from population dump
+	inspect  print statistics of genome
+	render   export genome graph in DOT format or render its network as SVG or PNG image
+	codegen  generate standalone Go source file with Predict function of genome network
+
+Use "goneat <command> -h" for more information about a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(args)
+	case "resume":
+		err = resumeCommand(args)
+	case "inspect":
+		err = inspectCommand(args, os.Stdout)
+	case "render":
+		err = renderCommand(args, os.Stdout)
+	case "codegen":
+		err = codegenCommand(args, os.Stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "goneat: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// The experiment options shared by run and resume commands
+type experimentOptions struct {
+	out_dir     string
+	context     string
+	experiment  string
+	trials      int
+	log_level   int
+
+	// The options of dataset experiment
+	dataset            string
+	dataset_targets    int
+	dataset_header     bool
+	dataset_loss       string
+	dataset_validation float64
+	dataset_folds      int
+	dataset_patience   int
+	dataset_refine     int
+}
+
+func (o *experimentOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.out_dir, "out", "./out", "The output directory to store results.")
+	fs.StringVar(&o.context, "context", "./data/xor.neat", "The execution context configuration file (plain or YAML).")
+	fs.StringVar(&o.experiment, "experiment", "XOR", "The name of experiment to run. [XOR, cart_pole, cart_2pole_markov, cart_2pole_non-markov, maze_medium, maze_hard, maze_medium_novelty, maze_hard_novelty, retina, retina_or, dataset]")
+	fs.IntVar(&o.trials, "trials", 0, "The number of trials for experiment. Overrides the one set in configuration.")
+	fs.IntVar(&o.log_level, "log_level", -1, "The logger level to be used. Overrides the one set in configuration.")
+	fs.StringVar(&o.dataset, "dataset", "", "The CSV or LibSVM (*.svm, *.libsvm) file with samples for dataset experiment.")
+	fs.IntVar(&o.dataset_targets, "dataset_targets", 1, "The number of target columns at the end of each CSV record for dataset experiment.")
+	fs.BoolVar(&o.dataset_header, "dataset_header", false, "If set than the first record of CSV file is skipped for dataset experiment.")
+	fs.StringVar(&o.dataset_loss, "dataset_loss", "mse", "The loss function for dataset experiment. [mse, cross_entropy, accuracy]")
+	fs.Float64Var(&o.dataset_validation, "dataset_validation", 0.2, "The fraction of samples held out for validation in dataset experiment.")
+	fs.IntVar(&o.dataset_folds, "dataset_folds", 0, "The number of cross-validation folds rotated per generation in dataset experiment. Overrides validation fraction.")
+	fs.IntVar(&o.dataset_patience, "dataset_patience", 0, "The number of generations without improvement of champion's validation fitness to stop the trial of dataset experiment. Zero disables early stopping.")
+	fs.IntVar(&o.dataset_refine, "dataset_refine", 0, "The number of epochs of gradient descent to refine weights of feed-forward champion after dataset experiment. The refined genome is saved into output directory.")
+}
+
+// Loads context and applies command line overrides
+func (o *experimentOptions) loadContext() (*neat.NeatContext, error) {
+	context, err := loadContext(o.context)
+	if err != nil {
+		return nil, err
+	}
+	if o.trials > 0 {
+		context.NumRuns = o.trials
+	}
+	if o.log_level >= 0 {
+		neat.LogLevel = neat.LoggerLevel(o.log_level)
+	}
+	return context, nil
+}
+
+// Runs experiment with given start genome
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	opts := experimentOptions{}
+	opts.register(fs)
+	genome_path := fs.String("genome", "./data/xorstartgenes", "The seed genome to start with.")
+	fs.Parse(args)
+
+	context, err := opts.loadContext()
+	if err != nil {
+		return err
+	}
+	start_genome, err := readGenome(*genome_path)
+	if err != nil {
+		return err
+	}
+	experiment, evaluator, err := prepareExperiment(&opts, context)
+	if err != nil {
+		return err
+	}
+
+	context = seedRandom(context)
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		return errors.New(fmt.Sprintf("Failed to perform %s experiment: %s", opts.experiment, err))
+	}
+	return finishExperiment(&opts, experiment, evaluator)
+}
+
+// Resumes experiment from population dump
+func resumeCommand(args []string) error {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	opts := experimentOptions{}
+	opts.register(fs)
+	pop_path := fs.String("population", "", "The population dump file to resume from, e.g. ./out/0/gen_50")
+	generation := fs.Int("generation", -1, "The generation of population dump. If negative it will be taken from the dump file name.")
+	fs.Parse(args)
+
+	if len(*pop_path) == 0 {
+		return errors.New("The population dump file is not set")
+	}
+	start_generation := *generation
+	if start_generation < 0 {
+		if _, err := fmt.Sscanf(filepath.Base(*pop_path), "gen_%d", &start_generation); err != nil {
+			return errors.New(fmt.Sprintf(
+				"Failed to get generation from population dump name: %s, use -generation", *pop_path))
+		}
+	}
+
+	context, err := opts.loadContext()
+	if err != nil {
+		return err
+	}
+	pop_file, err := os.Open(*pop_path)
+	if err != nil {
+		return err
+	}
+	defer pop_file.Close()
+	pop, err := genetics.ReadPopulation(pop_file, context)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Failed to read population: %s", err))
+	}
+
+	experiment, evaluator, err := prepareExperiment(&opts, context)
+	if err != nil {
+		return err
+	}
+
+	context = seedRandom(context)
+	// the dump saved at the end of generation, thus continue from the next one
+	if err = experiment.Resume(context, pop, start_generation + 1, evaluator); err != nil {
+		return errors.New(fmt.Sprintf("Failed to resume %s experiment: %s", opts.experiment, err))
+	}
+	return finishExperiment(&opts, experiment, evaluator)
+}
+
+// Prints statistics of genome
+func inspectCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	full := fs.Bool("full", false, "Print all genome's nodes and genes in addition to statistics.")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return errors.New("The genome file is not set")
+	}
+	for _, path := range fs.Args() {
+		gnome, err := readGenome(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s:\n%s\n", path, gnome.Summary())
+		if *full {
+			fmt.Fprintln(out, gnome)
+		}
+	}
+	return nil
+}
+
+// Renders genome graph
+func renderCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	format := fs.String("format", "dot", "The output format [dot, svg, png]. The SVG and PNG images show phenotype network of genome.")
+	out_path := fs.String("o", "", "The output file. If not set the standard output is used.")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("The single genome file expected")
+	}
+	gnome, err := readGenome(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if len(*out_path) > 0 {
+		file, err := os.Create(*out_path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch *format {
+	case "dot":
+		return gnome.WriteDOT(out)
+	case "svg", "png":
+		net, err := gnome.Genesis(gnome.Id)
+		if err != nil {
+			return err
+		}
+		if *format == "svg" {
+			return net.RenderSVG(out, nil)
+		}
+		return net.RenderPNG(out, nil)
+	default:
+		return errors.New(fmt.Sprintf("Unsupported render format: %s", *format))
+	}
+}
+
+// Generates standalone Go source code of genome network
+func codegenCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("codegen", flag.ExitOnError)
+	package_name := fs.String("package", "champion", "The name of package of generated code.")
+	steps := fs.Int("steps", 0, "The number of activation steps per prediction. If not set the depth of network is used.")
+	out_path := fs.String("o", "", "The output file. If not set the standard output is used.")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("The single genome file expected")
+	}
+	gnome, err := readGenome(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if len(*out_path) > 0 {
+		file, err := os.Create(*out_path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	return gnome.WriteGoCode(out, *package_name, *steps)
+}
+
+// Loads context configuration from file, the YAML format is assumed for files with .yml or .yaml extension
+func loadContext(path string) (*neat.NeatContext, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Failed to open context configuration file: %s", err))
+	}
+	defer file.Close()
+
+	if isYAML(path) {
+		context := neat.NewNeatContext()
+		if err = context.LoadContext(file); err != nil {
+			return nil, errors.New(fmt.Sprintf("Failed to load context configuration: %s", err))
+		}
+		return context, nil
+	}
+	return neat.LoadContext(file), nil
+}
+
+// Reads genome from file, the YAML encoding is assumed for files with .yml or .yaml extension and the versioned JSON
+// encoding for files with .json extension
+func readGenome(path string) (*genetics.Genome, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Failed to open genome file: %s", err))
+	}
+	defer file.Close()
+
+	encoding := genetics.PlainGenomeEncoding
+	if isYAML(path) {
+		encoding = genetics.YAMLGenomeEncoding
+	} else if strings.ToLower(filepath.Ext(path)) == ".json" {
+		encoding = genetics.JSONGenomeEncoding
+	}
+	reader, err := genetics.NewGenomeReader(file, encoding)
+	if err != nil {
+		return nil, err
+	}
+	gnome, err := reader.Read()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Failed to read genome from %s: %s", path, err))
+	}
+	return gnome, nil
+}
+
+// Returns copy of given context with random number generator seeded by the seed of the run or by current time if it is
+// not set
+func seedRandom(context *neat.NeatContext) *neat.NeatContext {
+	seed := context.RandomSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return context.WithRand(rand.New(rand.NewSource(seed)))
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// Creates output directory and the experiment with generation evaluator for its name
+func prepareExperiment(opts *experimentOptions, context *neat.NeatContext) (*experiments.Experiment, experiments.GenerationEvaluator, error) {
+	// Check if output dir exists
+	if _, err := os.Stat(opts.out_dir); err == nil {
+		// backup it
+		back_up_dir := fmt.Sprintf("%s-%s", opts.out_dir, time.Now().Format("2006-01-02T15_04_05"))
+		if err = os.Rename(opts.out_dir, back_up_dir); err != nil {
+			return nil, nil, errors.New(fmt.Sprintf("Failed to do previous results backup: %s", err))
+		}
+	}
+	if err := os.MkdirAll(opts.out_dir, os.ModePerm); err != nil {
+		return nil, nil, errors.New(fmt.Sprintf("Failed to create output directory: %s", err))
+	}
+
+	experiment := &experiments.Experiment{
+		Id:0,
+		Trials:make(experiments.Trials, context.NumRuns),
+	}
+	var evaluator experiments.GenerationEvaluator
+	var max_fitness float64
+	var err error
+	if opts.experiment == "dataset" {
+		evaluator, max_fitness, err = opts.datasetEvaluator()
+	} else {
+		evaluator, max_fitness, err = generationEvaluatorForName(opts.experiment, opts.out_dir)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	experiment.MaxFintessScore = max_fitness
+	return experiment, evaluator, nil
+}
+
+// Returns generation evaluator of standard experiment with given name and the maximal fitness score it produces
+func generationEvaluatorForName(name, out_dir string) (experiments.GenerationEvaluator, float64, error) {
+	switch name {
+	case "XOR":
+		// as given by fitness function definition
+		return xor.XORGenerationEvaluator{OutputPath:out_dir}, 16.0, nil
+	case "cart_pole":
+		return pole.CartPoleGenerationEvaluator{
+			OutputPath:out_dir,
+			WinBalancingSteps:500000,
+			RandomStart:true,
+		}, 1.0, nil
+	case "cart_2pole_markov":
+		return pole.CartDoublePoleGenerationEvaluator{
+			OutputPath:out_dir,
+			Markov:true,
+			ActionType:experiments.ContinuousAction,
+		}, 1.0, nil
+	case "cart_2pole_non-markov":
+		return pole.CartDoublePoleGenerationEvaluator{
+			OutputPath:out_dir,
+			Markov:false,
+			ActionType:experiments.ContinuousAction,
+		}, 1.0, nil
+	case "maze_medium", "maze_hard", "maze_medium_novelty", "maze_hard_novelty":
+		evaluator := &maze.MazeGenerationEvaluator{
+			OutputPath:out_dir,
+			Maze:maze.MediumMaze(),
+			NoveltySearch:strings.HasSuffix(name, "_novelty"),
+		}
+		if strings.HasPrefix(name, "maze_hard") {
+			evaluator.Maze = maze.HardMaze()
+		}
+		if evaluator.NoveltySearch {
+			// the novelty is not bounded
+			return evaluator, 0.0, nil
+		}
+		return evaluator, 1.0, nil
+	case "retina":
+		return retina.RetinaGenerationEvaluator{OutputPath:out_dir, Task:retina.AndRetinaTask}, 1.0, nil
+	case "retina_or":
+		return retina.RetinaGenerationEvaluator{OutputPath:out_dir, Task:retina.OrRetinaTask}, 1.0, nil
+	default:
+		return nil, 0, errors.New(fmt.Sprintf("Unsupported experiment: %s", name))
+	}
+}
+
+// Creates evaluator of dataset experiment with samples loaded from dataset file. The samples are shuffled and split
+// into training and validation parts unless cross-validation folds requested. Returns evaluator along with the maximal
+// fitness score it produces.
+func (o *experimentOptions) datasetEvaluator() (*dataset.Evaluator, float64, error) {
+	loss, err := dataset.LossTypeByName(o.dataset_loss)
+	if err != nil {
+		return nil, 0, err
+	}
+	file, err := os.Open(o.dataset)
+	if err != nil {
+		return nil, 0, errors.New(fmt.Sprintf("Failed to open dataset file: %s", err))
+	}
+	defer file.Close()
+	var data *dataset.Dataset
+	if ext := strings.ToLower(filepath.Ext(o.dataset)); ext == ".svm" || ext == ".libsvm" {
+		data, err = dataset.ReadLibSVM(file, 0)
+	} else {
+		data, err = dataset.ReadCSV(file, o.dataset_targets, o.dataset_header)
+	}
+	if err != nil {
+		return nil, 0, errors.New(fmt.Sprintf("Failed to read dataset from %s: %s", o.dataset, err))
+	}
+	data.Shuffle(nil)
+	evaluator := &dataset.Evaluator{Train:data, Loss:loss, Folds:o.dataset_folds, Patience:o.dataset_patience}
+	if o.dataset_folds <= 1 && o.dataset_validation > 0 {
+		if evaluator.Train, evaluator.Validation, err = data.Split(o.dataset_validation); err != nil {
+			return nil, 0, err
+		}
+	}
+	return evaluator, 1.0, nil
+}
+
+// Prints experiment statistics and saves experiment data into output directory. The champion of dataset experiment is
+// refined by gradient descent if requested.
+func finishExperiment(opts *experimentOptions, experiment *experiments.Experiment, evaluator experiments.GenerationEvaluator) error {
+	experiment.PrintStatistics()
+
+	if data_evaluator, ok := evaluator.(*dataset.Evaluator); ok && opts.dataset_refine > 0 {
+		if err := refineChampion(opts, experiment, data_evaluator); err != nil {
+			return err
+		}
+	}
+
+	exp_res_path := fmt.Sprintf("%s/%s.dat", opts.out_dir, opts.experiment)
+	exp_res_file, err := os.Create(exp_res_path)
+	if err == nil {
+		defer exp_res_file.Close()
+		err = experiment.Write(exp_res_file)
+	}
+	if err != nil {
+		return errors.New(fmt.Sprintf("Failed to save experiment results: %s", err))
+	}
+	return nil
+}
+
+// Refines weights of the champion of dataset experiment by gradient descent over training dataset and saves refined
+// genome into output directory. The champion selected by validation fitness is preferred if validation was done.
+func refineChampion(opts *experimentOptions, experiment *experiments.Experiment, evaluator *dataset.Evaluator) error {
+	var champion *genetics.Organism
+	if epoch, found := experiment.BestValidatedGeneration(); found {
+		champion = epoch.Best
+	} else if org, _, found := experiment.BestOrganism(false); found {
+		champion = org
+	} else {
+		return errors.New("No champion found to refine")
+	}
+	refiner := dataset.Refiner{Loss:evaluator.Loss, Epochs:opts.dataset_refine}
+	before, after, err := refiner.Refine(champion, evaluator.Train)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Failed to refine champion: %s", err))
+	}
+	fmt.Printf("Champion refined by %d epochs\n\tTraining loss:\t\t%f -> %f\n", opts.dataset_refine, before, after)
+	if evaluator.Validation != nil {
+		if _, loss, err := evaluator.Evaluate(champion, evaluator.Validation); err == nil {
+			fmt.Printf("\tValidation loss:\t%f\n", loss)
+		}
+	}
+
+	genome_path := fmt.Sprintf("%s/%s_refined_genome", opts.out_dir, opts.experiment)
+	genome_file, err := os.Create(genome_path)
+	if err == nil {
+		defer genome_file.Close()
+		err = champion.Genotype.Write(genome_file)
+	}
+	if err != nil {
+		return errors.New(fmt.Sprintf("Failed to save refined genome: %s", err))
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"testing"
+	"bytes"
+	"strings"
+	"os"
+	"path/filepath"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/experiments/dataset"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func TestInspectCommand(t *testing.T) {
+	var buf bytes.Buffer
+	if err := inspectCommand([]string{"-full", "../../data/xorstartgenes"}, &buf); err != nil {
+		t.Error(err)
+		return
+	}
+	out := buf.String()
+	if !strings.Contains(out, "../../data/xorstartgenes:") {
+		t.Error("Genome path not printed")
+	}
+	if !strings.Contains(out, "GENOME START") {
+		t.Error("Full genome not printed")
+	}
+
+	if err := inspectCommand([]string{}, &buf); err == nil {
+		t.Error("Error expected when genome file not set")
+	}
+}
+
+func TestRenderCommand(t *testing.T) {
+	var buf bytes.Buffer
+	if err := renderCommand([]string{"../../data/test_seed_genome.yml"}, &buf); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.HasPrefix(buf.String(), "digraph genome_") {
+		t.Error("DOT graph expected", buf.String())
+	}
+
+	buf.Reset()
+	if err := renderCommand([]string{"-format", "svg", "../../data/xorstartgenes"}, &buf); err != nil {
+		t.Error(err)
+	} else if !strings.HasPrefix(buf.String(), "<svg ") {
+		t.Error("SVG image expected", buf.String())
+	}
+
+	if err := renderCommand([]string{"-format", "jpeg", "../../data/xorstartgenes"}, &buf); err == nil {
+		t.Error("Error expected for unsupported format")
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	for _, path := range []string{"../../data/xor_test.neat", "../../data/xor_test.neat.yml"} {
+		context, err := loadContext(path)
+		if err != nil {
+			t.Error(path, err)
+			continue
+		}
+		if context.PopSize != 200 {
+			t.Error(path, "Wrong population size", context.PopSize)
+		}
+	}
+}
+
+func TestSeedRandom(t *testing.T) {
+	context := &neat.NeatContext{RandomSeed:42}
+	seeded, other := seedRandom(context), seedRandom(context)
+	if seeded == context || seeded.Rand() == context.Rand() {
+		t.Error("The generator should be installed into copy of context")
+	}
+	// the generators seeded by the same seed of the run should give the same stream
+	for i := 0; i < 10; i++ {
+		if v1, v2 := seeded.Rand().Int63(), other.Rand().Int63(); v1 != v2 {
+			t.Error("The streams of the same seed differ", i, v1, v2)
+			return
+		}
+	}
+}
+
+func TestGenerationEvaluatorForName(t *testing.T) {
+	for _, name := range []string{"XOR", "cart_pole", "cart_2pole_markov", "cart_2pole_non-markov", "maze_medium", "maze_hard",
+		"retina", "retina_or"} {
+		if evaluator, max_fitness, err := generationEvaluatorForName(name, "out"); err != nil || evaluator == nil || max_fitness <= 0 {
+			t.Error(name, err)
+		}
+	}
+	// the novelty is not bounded
+	for _, name := range []string{"maze_medium_novelty", "maze_hard_novelty"} {
+		if evaluator, max_fitness, err := generationEvaluatorForName(name, "out"); err != nil || evaluator == nil || max_fitness != 0 {
+			t.Error(name, err)
+		}
+	}
+	if _, _, err := generationEvaluatorForName("unknown", "out"); err == nil {
+		t.Error("Error expected for unknown experiment")
+	}
+}
+
+func TestCodegenCommand(t *testing.T) {
+	var buf bytes.Buffer
+	if err := codegenCommand([]string{"-package", "xor", "../../data/xorstartgenes"}, &buf); err != nil {
+		t.Error(err)
+		return
+	}
+	if out := buf.String(); !strings.Contains(out, "package xor") || !strings.Contains(out, "func Predict(") {
+		t.Error("Go code with Predict function expected", out)
+	}
+
+	if err := codegenCommand([]string{"-package", "func", "../../data/xorstartgenes"}, &buf); err == nil {
+		t.Error("Error expected for invalid package name")
+	}
+	if err := codegenCommand([]string{}, &buf); err == nil {
+		t.Error("Error expected when genome file not set")
+	}
+}
+
+func TestExperimentOptions_datasetEvaluator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xor.csv")
+	if err := os.WriteFile(path, []byte("x1,x2,y\n0,0,0\n0,1,1\n1,0,1\n1,1,0\n0,0,0\n"), 0644); err != nil {
+		t.Error(err)
+		return
+	}
+	opts := experimentOptions{dataset:path, dataset_targets:1, dataset_header:true, dataset_loss:"accuracy",
+		dataset_validation:0.4}
+	evaluator, max_fitness, err := opts.datasetEvaluator()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if max_fitness != 1.0 {
+		t.Error("Wrong maximal fitness", max_fitness)
+	}
+	if evaluator.Train.Len() != 3 || evaluator.Validation.Len() != 2 || evaluator.Loss != dataset.AccuracyLoss {
+		t.Error("Wrong dataset evaluator", evaluator.Train.Len(), evaluator.Validation.Len(), evaluator.Loss)
+	}
+
+	opts.dataset_folds, opts.dataset_patience = 5, 10
+	if evaluator, _, err = opts.datasetEvaluator(); err != nil {
+		t.Error(err)
+	} else if evaluator.Train.Len() != 5 || evaluator.Validation != nil || evaluator.Folds != 5 || evaluator.Patience != 10 {
+		t.Error("The whole dataset expected for cross-validation", evaluator.Train.Len(), evaluator.Validation)
+	}
+
+	opts.dataset_loss = "unknown"
+	if _, _, err = opts.datasetEvaluator(); err == nil {
+		t.Error("Error expected for unknown loss")
+	}
+	opts.dataset_loss, opts.dataset = "mse", filepath.Join(t.TempDir(), "missing.csv")
+	if _, _, err = opts.datasetEvaluator(); err == nil {
+		t.Error("Error expected for missing dataset file")
+	}
+}
+
+func TestRefineChampion(t *testing.T) {
+	genome, err := readGenome("../../data/xorstartgenes")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	champion, err := genetics.NewOrganism(0.5, genome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	experiment := &experiments.Experiment{Trials:experiments.Trials{
+		{Generations:[]experiments.Generation{{Best:champion}}},
+	}}
+	data := &dataset.Dataset{
+		Features:[][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+		Targets:[][]float64{{0}, {1}, {1}, {1}},
+	}
+	evaluator := &dataset.Evaluator{Train:data, Validation:data, Loss:dataset.MeanSquaredErrorLoss}
+	opts := &experimentOptions{experiment:"dataset", out_dir:t.TempDir(), dataset_refine:50}
+	if err = refineChampion(opts, experiment, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = os.Stat(filepath.Join(opts.out_dir, "dataset_refined_genome")); err != nil {
+		t.Error("The refined genome should be saved", err)
+	}
+	if champion.Genotype.Genes[0].Link.Weight == 0.0 {
+		t.Error("The weights of champion genome should be refined")
+	}
+
+	if err = refineChampion(opts, &experiments.Experiment{}, evaluator); err == nil {
+		t.Error("Error expected for experiment without champion")
+	}
+}
@@ -7,11 +7,15 @@ import (
 	"log"
 	"flag"
 	"math/rand"
+	"net/http"
 	"github.com/yaricom/goNEAT/experiments"
 	"github.com/yaricom/goNEAT/neat"
 	"github.com/yaricom/goNEAT/neat/genetics"
 	"github.com/yaricom/goNEAT/experiments/xor"
 	"github.com/yaricom/goNEAT/experiments/pole"
+	"github.com/yaricom/goNEAT/experiments/maze"
+	"github.com/yaricom/goNEAT/experiments/retina"
+	"github.com/yaricom/goNEAT/monitor"
 )
 
 // The experiment runner boilerplate code
@@ -19,9 +23,12 @@ func main() {
 	var out_dir_path = flag.String("out", "./out", "The output directory to store results.")
 	var context_path = flag.String("context", "./data/xor.neat", "The execution context configuration file.")
 	var genome_path = flag.String("genome", "./data/xorstartgenes", "The seed genome to start with.")
-	var experiment_name = flag.String("experiment", "XOR", "The name of experiment to run. [XOR, cart_pole, cart_2pole_markov, cart_2pole_non-markov]")
+	var experiment_name = flag.String("experiment", "XOR", "The name of experiment to run. [XOR, cart_pole, cart_2pole_markov, cart_2pole_non-markov, maze_medium, maze_hard, maze_medium_novelty, maze_hard_novelty, retina, retina_or]")
 	var trials_count = flag.Int("trials", 0, "The numbar of trials for experiment. Overrides the one set in configuration.")
 	var log_level = flag.Int("log_level", -1, "The logger level to be used. Overrides the one set in configuration.")
+	var metrics_addr = flag.String("metrics", "", "The address to serve experiment metrics at, e.g. :8080. Metrics will be available at /metrics")
+	var monitor_addr = flag.String("monitor", "", "The address to serve live dashboard of experiment at, e.g. :8081")
+	var html_report = flag.Bool("report", false, "If set than HTML report of each trial will be written into its output directory.")
 
 	flag.Parse()
 
@@ -78,6 +85,20 @@ func main() {
 		Id:0,
 		Trials:make(experiments.Trials, context.NumRuns),
 	}
+	if len(*metrics_addr) > 0 {
+		experiment.Metrics = experiments.NewMetricsExporter("goneat")
+		http.Handle("/metrics", experiment.Metrics.Handler())
+		go func() {
+			log.Println(http.ListenAndServe(*metrics_addr, nil))
+		}()
+	}
+	if len(*monitor_addr) > 0 {
+		server := monitor.NewServer()
+		experiment.Observers = append(experiment.Observers, server)
+		go func() {
+			log.Println(http.ListenAndServe(*monitor_addr, server.Handler()))
+		}()
+	}
 	var generationEvaluator experiments.GenerationEvaluator
 	if *experiment_name == "XOR" {
 		experiment.MaxFintessScore = 16.0 // as given by fitness function definition
@@ -102,6 +123,24 @@ func main() {
 			Markov:false,
 			ActionType:experiments.ContinuousAction,
 		}
+	} else if *experiment_name == "maze_medium" || *experiment_name == "maze_medium_novelty" {
+		generationEvaluator = &maze.MazeGenerationEvaluator{
+			OutputPath:out_dir,
+			Maze:maze.MediumMaze(),
+			NoveltySearch:*experiment_name == "maze_medium_novelty",
+		}
+	} else if *experiment_name == "maze_hard" || *experiment_name == "maze_hard_novelty" {
+		generationEvaluator = &maze.MazeGenerationEvaluator{
+			OutputPath:out_dir,
+			Maze:maze.HardMaze(),
+			NoveltySearch:*experiment_name == "maze_hard_novelty",
+		}
+	} else if *experiment_name == "retina" || *experiment_name == "retina_or" {
+		experiment.MaxFintessScore = 1.0 // as given by fitness function definition
+		generationEvaluator = retina.RetinaGenerationEvaluator{OutputPath:out_dir, Task:retina.AndRetinaTask}
+		if *experiment_name == "retina_or" {
+			generationEvaluator = retina.RetinaGenerationEvaluator{OutputPath:out_dir, Task:retina.OrRetinaTask}
+		}
 	}
 
 	err = experiment.Execute(context, start_genome, generationEvaluator)
@@ -124,4 +163,11 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to save experiment results", err)
 	}
+
+	// Write HTML reports
+	if *html_report {
+		if err = experiment.WriteHTMLReports(out_dir, context); err != nil {
+			log.Fatal("Failed to write HTML reports", err)
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,96 @@
+package experiments
+
+import (
+	"math"
+)
+
+// The characterization of organism behavior, e.g. the final location of agent in maze or the sequence of actions
+// taken during evaluation. The behaviors are compared by pluggable distance metrics, thus the same characterization
+// may be used by different behavioral diversity methods, such as novelty search.
+type BehaviorDescriptor interface {
+	// Returns the values characterizing behavior, which are compared by distance metrics
+	Values() []float64
+}
+
+// The behavior characterized by vector of real values, e.g. coordinates of agent location
+type VectorBehavior []float64
+
+func (b VectorBehavior) Values() []float64 {
+	return b
+}
+
+// The behavior characterized by string of symbols, e.g. actions taken by agent. Its values are the code points of
+// symbols in order.
+type StringBehavior string
+
+func (b StringBehavior) Values() []float64 {
+	values := make([]float64, 0, len(b))
+	for _, r := range b {
+		values = append(values, float64(r))
+	}
+	return values
+}
+
+// The distance metric between two behaviors. The custom metric may be defined for custom behavior descriptor types,
+// e.g. to compare behaviors which are not well characterized by values.
+type BehaviorMetric func(a, b BehaviorDescriptor) float64
+
+// The Euclidean distance metric between values of behaviors, see EuclideanDistance
+func EuclideanMetric(a, b BehaviorDescriptor) float64 {
+	return EuclideanDistance(a.Values(), b.Values())
+}
+
+// The Hamming distance metric, i.e. the number of positions where values of behaviors differ. The missing values of
+// shorter behavior are regarded as mismatches.
+func HammingMetric(a, b BehaviorDescriptor) float64 {
+	av, bv := a.Values(), b.Values()
+	if len(av) < len(bv) {
+		av, bv = bv, av
+	}
+	distance := len(av) - len(bv)
+	for i, v := range bv {
+		if v != av[i] {
+			distance++
+		}
+	}
+	return float64(distance)
+}
+
+// The dynamic time warping distance metric, i.e. the minimal total absolute difference between values of behaviors
+// aligned with stretching in time. It is suitable for behaviors characterized by time series of different length
+// or pace. If one of behaviors has no values, the distance is the sum of absolute values of another one.
+func DTWMetric(a, b BehaviorDescriptor) float64 {
+	av, bv := a.Values(), b.Values()
+	if len(av) == 0 || len(bv) == 0 {
+		sum := 0.0
+		for _, values := range [][]float64{av, bv} {
+			for _, v := range values {
+				sum += math.Abs(v)
+			}
+		}
+		return sum
+	}
+	// the costs of alignment of previous and current values of a with all values of b
+	prev, curr := make([]float64, len(bv) + 1), make([]float64, len(bv) + 1)
+	for j := range prev {
+		prev[j] = math.Inf(1)
+	}
+	prev[0] = 0.0
+	for _, v := range av {
+		curr[0] = math.Inf(1)
+		for j, w := range bv {
+			curr[j + 1] = math.Abs(v - w) + math.Min(prev[j], math.Min(prev[j + 1], curr[j]))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(bv)]
+}
+
+// Returns copy of given behavior to be stored, i.e. the values of vector behavior are copied. Other behaviors are
+// regarded as immutable and returned as is.
+func copyBehavior(behavior BehaviorDescriptor) BehaviorDescriptor {
+	if vector, ok := behavior.(VectorBehavior); ok {
+		return append(VectorBehavior(nil), vector...)
+	}
+	return behavior
+}
@@ -0,0 +1,59 @@
+package experiments
+
+import (
+	"testing"
+)
+
+func TestStringBehavior_Values(t *testing.T) {
+	values := StringBehavior("ab").Values()
+	if len(values) != 2 || values[0] != 97.0 || values[1] != 98.0 {
+		t.Error("Wrong values of string behavior", values)
+	}
+}
+
+func TestEuclideanMetric(t *testing.T) {
+	if d := EuclideanMetric(VectorBehavior{0.0, 0.0}, VectorBehavior{3.0, 4.0}); d != 5.0 {
+		t.Error("d != 5.0", d)
+	}
+}
+
+func TestHammingMetric(t *testing.T) {
+	if d := HammingMetric(StringBehavior("karolin"), StringBehavior("kathrin")); d != 3.0 {
+		t.Error("d != 3.0", d)
+	}
+	// the missing values are mismatches
+	if d := HammingMetric(VectorBehavior{1.0, 2.0}, VectorBehavior{1.0, 3.0, 4.0, 5.0}); d != 3.0 {
+		t.Error("d != 3.0", d)
+	}
+	if d := HammingMetric(StringBehavior(""), StringBehavior("")); d != 0.0 {
+		t.Error("d != 0.0", d)
+	}
+}
+
+func TestDTWMetric(t *testing.T) {
+	// the same shape with different pace
+	if d := DTWMetric(VectorBehavior{0.0, 1.0, 2.0}, VectorBehavior{0.0, 0.0, 1.0, 1.0, 2.0}); d != 0.0 {
+		t.Error("d != 0.0", d)
+	}
+	if d := DTWMetric(VectorBehavior{0.0, 1.0}, VectorBehavior{0.0, 3.0}); d != 2.0 {
+		t.Error("d != 2.0", d)
+	}
+	if d := DTWMetric(VectorBehavior{1.0, 2.0}, VectorBehavior{2.0, 1.0}); d != 2.0 {
+		t.Error("d != 2.0", d)
+	}
+	if d := DTWMetric(VectorBehavior{-1.0, 2.0}, VectorBehavior{}); d != 3.0 {
+		t.Error("d != 3.0", d)
+	}
+}
+
+func TestCopyBehavior(t *testing.T) {
+	vector := VectorBehavior{1.0}
+	copied := copyBehavior(vector)
+	vector[0] = 2.0
+	if copied.Values()[0] != 1.0 {
+		t.Error("The values of vector behavior should be copied")
+	}
+	if copyBehavior(StringBehavior("a")) != StringBehavior("a") {
+		t.Error("The string behavior should be returned as is")
+	}
+}
@@ -4,6 +4,7 @@ package experiments
 import (
 	"github.com/yaricom/goNEAT/neat/genetics"
 	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
 	"fmt"
 	"time"
 	"os"
@@ -35,6 +36,12 @@ type TrialRunObserver interface {
 	TrialRunStarted(trial *Trial)
 }
 
+// The interface to describe observer interested to receive notifications about generations evaluated by experiment
+type GenerationObserver interface {
+	// Invoked after generation evaluated and its statistics collected, but before population turnover to the next epoch
+	OnGenerationEvaluated(epoch *Generation, pop *genetics.Population)
+}
+
 // Returns appropriate executor type from given context
 func epochExecutorForContext(context *neat.NeatContext) (genetics.PopulationEpochExecutor, error) {
 	switch genetics.EpochExecutorType(context.EpochExecutorType) {
@@ -42,6 +49,8 @@ func epochExecutorForContext(context *neat.NeatContext) (genetics.PopulationEpoc
 		return &genetics.SequentialPopulationEpochExecutor{}, nil
 	case genetics.ParallelExecutorType:
 		return &genetics.ParallelPopulationEpochExecutor{}, nil
+	case genetics.SteadyStateExecutorType:
+		return &genetics.SteadyStatePopulationEpochExecutor{}, nil
 	default:
 		return nil, errors.New("Unsupported epoch executor type requested")
 	}
@@ -55,8 +64,6 @@ func (ex *Experiment) Execute(context *neat.NeatContext, start_genome *genetics.
 
 	var pop *genetics.Population
 	for run := 0; run < context.NumRuns; run++ {
-		trial_start_time := time.Now()
-
 		neat.InfoLog("\n>>>>> Spawning new population ")
 		pop, err = genetics.NewPopulation(start_genome, context)
 		if err != nil {
@@ -65,75 +72,202 @@ func (ex *Experiment) Execute(context *neat.NeatContext, start_genome *genetics.
 		} else {
 			neat.InfoLog("OK <<<<<")
 		}
-		neat.InfoLog(">>>>> Verifying spawned population ")
-		_, err = pop.Verify()
+
+		// store trial into experiment
+		ex.Trials[run], err = ex.executeTrial(run, 0, pop, context, executor)
 		if err != nil {
-			neat.ErrorLog("\n!!!!! Population verification failed !!!!!")
 			return err
-		} else {
-			neat.InfoLog("OK <<<<<")
 		}
+	}
+
+	return nil
+}
+
+// Resumes the Experiment execution from provided population, e.g. restored from the population dump saved at the end of
+// start_generation. The population will be evolved for remaining generations in the single trial.
+func (ex *Experiment) Resume(context *neat.NeatContext, pop *genetics.Population, start_generation int, executor interface{}) (err error) {
+	if start_generation >= context.NumGenerations {
+		return errors.New(fmt.Sprintf("Start generation [%d] is beyond number of generations [%d]",
+			start_generation, context.NumGenerations))
+	}
+	trial, err := ex.executeTrial(0, start_generation, pop, context, executor)
+	ex.Trials = Trials{trial}
+	return err
+}
 
-		// create appropriate population's epoch executor
-		epoch_executor, err := epochExecutorForContext(context)
+// Executes the trial with given ID evolving provided population starting from specified generation
+func (ex *Experiment) executeTrial(run, start_generation int, pop *genetics.Population, context *neat.NeatContext,
+executor interface{}) (trial Trial, err error) {
+	trial_start_time := time.Now()
+
+	neat.InfoLog(">>>>> Verifying population ")
+	_, err = pop.Verify()
+	if err != nil {
+		neat.ErrorLog("\n!!!!! Population verification failed !!!!!")
+		return trial, err
+	} else {
+		neat.InfoLog("OK <<<<<")
+	}
+
+	// create appropriate population's epoch executor
+	epoch_executor, err := epochExecutorForContext(context)
+	if err != nil {
+		return trial, err
+	}
+
+	// start new trial
+	trial = Trial {
+		Id:run,
+		ObjectiveDirection:genetics.ObjectiveDirection(context.ObjectiveDirection),
+	}
+
+	if trial_observer, ok := executor.(TrialRunObserver); ok {
+		trial_observer.TrialRunStarted(&trial) // optional
+	}
+
+	generation_evaluator := executor.(GenerationEvaluator) // mandatory
+
+	// The number of organism evaluations done in this trial
+	evaluations := 0
+
+	for generation_id := start_generation; generation_id < context.NumGenerations; generation_id++ {
+		neat.InfoLog(fmt.Sprintf(">>>>> Generation:%3d\tRun: %d\n", generation_id, run))
+		generation := Generation{
+			Id:generation_id,
+			TrialId:run,
+		}
+		gen_start_time := time.Now()
+		activations_start := network.ActivationsTotal()
+		if err = ex.attachInputPreprocessor(pop); err != nil {
+			return trial, err
+		}
+		err = generation_evaluator.GenerationEvaluate(pop, &generation, context)
 		if err != nil {
-			return err
+			neat.InfoLog(fmt.Sprintf("!!!!! Generation [%d] evaluation failed !!!!!\n", generation_id))
+			return trial, err
 		}
-
-		// start new trial
-		trial := Trial {
-			Id:run,
+		generation.Executed = time.Now()
+		if trial.ObjectiveDirection != genetics.MaximizeObjective {
+			// the statistics collected by evaluator assume maximized objective
+			generation.fillPopulationStatistics(pop, trial.ObjectiveDirection)
 		}
-
-		if trial_observer, ok := executor.(TrialRunObserver); ok {
-			trial_observer.TrialRunStarted(&trial) // optional
+		generation.Activations = network.ActivationsTotal() - activations_start
+		if generation.Evaluations == 0 {
+			generation.Evaluations = countEvaluatedOrganisms(pop)
+		}
+		if generation.Solved && context.ChampionRevalidations > 0 {
+			// re-validate the winner to make sure that it was not just lucky during noisy evaluation
+			if err = ex.revalidateWinner(&generation, executor, context); err != nil {
+				return trial, err
+			}
+		}
+		if champion := pop.PreservedChampion(); champion != nil && !generation.Solved {
+			// re-evaluate preserved champion to detect regression of population against its actual fitness
+			if organism_evaluator, ok := executor.(OrganismEvaluator); ok {
+				if _, err = EvaluateOrganism(champion, organism_evaluator, context); err != nil {
+					return trial, err
+				}
+				generation.Evaluations++
+			}
+		}
+		evaluations += generation.Evaluations
+		if generation.EvaluationErrors = countEvaluationErrors(pop); generation.EvaluationErrors > 0 {
+			neat.WarnLog(fmt.Sprintf("%d organisms failed evaluation in generation [%d]\n",
+				generation.EvaluationErrors, generation_id))
+		}
+		if ex.CollectDiversity {
+			generation.FillDiversityStatistics(pop, context)
+		}
+		if ex.Metrics != nil {
+			ex.Metrics.GenerationEvaluated(&generation, generation.Evaluations, generation.Executed.Sub(gen_start_time))
+		}
+		for _, observer := range ex.Observers {
+			observer.OnGenerationEvaluated(&generation, pop)
 		}
 
-		generation_evaluator := executor.(GenerationEvaluator) // mandatory
+		// Check whether evaluation budget of the trial is exhausted
+		budget_exhausted := context.MaxEvaluations > 0 && evaluations >= context.MaxEvaluations
 
-		for generation_id := 0; generation_id < context.NumGenerations; generation_id++ {
-			neat.InfoLog(fmt.Sprintf(">>>>> Generation:%3d\tRun: %d\n", generation_id, run))
-			generation := Generation{
-				Id:generation_id,
-				TrialId:run,
+		// Run the final tournament among species champions before population turnover if trial is finishing
+		finishing := generation.Solved || generation.Stopped || budget_exhausted ||
+			generation_id == context.NumGenerations - 1
+		if finishing && context.FinalTournamentEvaluations > 0 {
+			if organism_evaluator, ok := executor.(OrganismEvaluator); ok {
+				if trial.Tournament, err = RunTournament(pop, organism_evaluator, context, trial.ObjectiveDirection); err != nil {
+					return trial, err
+				}
+				generation.Evaluations += len(trial.Tournament.Contenders) * context.FinalTournamentEvaluations
+			} else {
+				neat.WarnLog("The final tournament skipped, the evaluator is not able to evaluate single organism\n")
 			}
-			gen_start_time := time.Now()
-			err = generation_evaluator.GenerationEvaluate(pop, &generation, context)
+		}
+
+		// Turnover population of organisms to the next epoch if appropriate
+		if !generation.Solved && !generation.Stopped && !budget_exhausted {
+			neat.DebugLog(">>>>> start next generation")
+			err = epoch_executor.NextEpoch(generation_id, pop, context)
 			if err != nil {
-				neat.InfoLog(fmt.Sprintf("!!!!! Generation [%d] evaluation failed !!!!!\n", generation_id))
-				return err
+				neat.InfoLog(fmt.Sprintf("!!!!! Epoch execution failed in generation [%d] !!!!!\n", generation_id))
+				return trial, err
 			}
-			generation.Executed = time.Now()
-
-			// Turnover population of organisms to the next epoch if appropriate
-			if !generation.Solved {
-				neat.DebugLog(">>>>> start next generation")
-				err = epoch_executor.NextEpoch(generation_id, pop, context)
-				if err != nil {
-					neat.InfoLog(fmt.Sprintf("!!!!! Epoch execution failed in generation [%d] !!!!!\n", generation_id))
-					return err
-				}
+			generation.Reproduction = pop.ReproductionStats()
+			if ex.Metrics != nil {
+				ex.Metrics.EpochCompleted(pop)
 			}
+		}
 
-			// Set generation duration, which also includes preparation for the next epoch
-			generation.Duration = generation.Executed.Sub(gen_start_time)
-			trial.Generations = append(trial.Generations, generation)
-
-			if generation.Solved {
-				// stop further evaluation if already solved
-				neat.InfoLog(fmt.Sprintf(">>>>> The winner organism found in [%d] generation, fitness: %f <<<<<\n",
-					generation_id, generation.Best.Fitness))
-				break
-			}
+		// Set generation duration, which also includes preparation for the next epoch
+		generation.Duration = generation.Executed.Sub(gen_start_time)
+		trial.Generations = append(trial.Generations, generation)
 
+		if generation.Solved {
+			// stop further evaluation if already solved
+			neat.InfoLog(fmt.Sprintf(">>>>> The winner organism found in [%d] generation, fitness: %f <<<<<\n",
+				generation_id, generation.Best.Fitness))
+			break
+		}
+		if generation.Stopped {
+			neat.InfoLog(fmt.Sprintf(">>>>> The trial stopped by evaluator in [%d] generation, fitness: %f <<<<<\n",
+				generation_id, generation.Best.Fitness))
+			break
+		}
+		if budget_exhausted {
+			neat.InfoLog(fmt.Sprintf(">>>>> The evaluation budget exhausted in [%d] generation after %d evaluations <<<<<\n",
+				generation_id, evaluations))
+			break
 		}
-		// holds trial duration
-		trial.Duration = time.Now().Sub(trial_start_time)
 
-		// store trial into experiment
-		ex.Trials[run] = trial
 	}
+	// holds trial duration
+	trial.Duration = time.Now().Sub(trial_start_time)
 
+	return trial, nil
+}
+
+// Re-validates the winner organism of solved generation with ValidateChampion and clears the solved flag along with
+// winner's statistics if winner failed re-validation
+func (ex *Experiment) revalidateWinner(generation *Generation, executor interface{}, context *neat.NeatContext) error {
+	organism_evaluator, ok := executor.(OrganismEvaluator)
+	if !ok {
+		neat.WarnLog("The champion re-validation skipped, the evaluator is not able to evaluate single organism\n")
+		return nil
+	}
+	winner := generation.Best
+	if winner == nil {
+		return errors.New(fmt.Sprintf("The generation [%d] solved without winner organism", generation.Id))
+	}
+	valid, err := ValidateChampion(winner, organism_evaluator, context)
+	if err != nil {
+		return err
+	}
+	generation.Evaluations += context.ChampionRevalidations
+	if !valid {
+		neat.InfoLog(fmt.Sprintf("The winner organism [%d] of generation [%d] rejected after re-validation\n",
+			winner.Genotype.Id, generation.Id))
+		winner.IsWinner = false
+		generation.Solved = false
+		generation.WinnerNodes, generation.WinnerGenes, generation.WinnerEvals = 0, 0, 0
+	}
 	return nil
 }
 
@@ -0,0 +1,408 @@
+package experiments
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The generation evaluator assigning random fitness to organisms and counting evaluated generations
+type randomGenerationEvaluator struct {
+	generations []int
+	trials      int
+}
+
+func (e *randomGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, context *neat.NeatContext) error {
+	for _, org := range pop.Organisms {
+		org.Fitness = rand.Float64()
+	}
+	epoch.FillPopulationStatistics(pop)
+	e.generations = append(e.generations, epoch.Id)
+	return nil
+}
+
+func (e *randomGenerationEvaluator) TrialRunStarted(trial *Trial) {
+	e.trials++
+}
+
+// The generation evaluator activating network of each organism given number of times per evaluation and evaluating
+// each organism twice
+type activatingGenerationEvaluator struct {
+	steps int
+}
+
+func (e *activatingGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, context *neat.NeatContext) error {
+	for _, org := range pop.Organisms {
+		phenotype, err := org.Phenotype()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 2; i++ {
+			if _, err = phenotype.ForwardSteps(e.steps); err != nil {
+				return err
+			}
+		}
+		org.Fitness = rand.Float64()
+	}
+	epoch.FillPopulationStatistics(pop)
+	epoch.Evaluations = len(pop.Organisms) * 2
+	return nil
+}
+
+// The generation evaluator which is able to evaluate single organism as well, e.g. the preserved champion
+type championGenerationEvaluator struct {
+	randomGenerationEvaluator
+	champions []*genetics.Organism
+}
+
+func (e *championGenerationEvaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (float64, bool, error) {
+	e.champions = append(e.champions, org)
+	return 0.5, false, nil
+}
+
+func testExperimentContext() *neat.NeatContext {
+	return &neat.NeatContext{
+		PopSize:20,
+		CompatThreshold:3.0,
+		DropOffAge:15,
+		SurvivalThresh:0.2,
+		MutateOnlyProb:0.25,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NumRuns:2,
+		NumGenerations:5,
+	}
+}
+
+func TestExperiment_Execute(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &randomGenerationEvaluator{}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	if evaluator.trials != 2 {
+		t.Error("Wrong number of trials started", evaluator.trials)
+	}
+	if len(evaluator.generations) != 10 {
+		t.Error("Wrong number of generations evaluated", len(evaluator.generations))
+	}
+	for i, trial := range experiment.Trials {
+		if trial.Id != i || len(trial.Generations) != 5 {
+			t.Error("Wrong trial", trial.Id, len(trial.Generations))
+		}
+		if trial.Generations[0].GeneticDiversity != nil {
+			t.Error("Diversity should not be collected")
+		}
+		for _, gen := range trial.Generations {
+			offspring := 0
+			for _, stats := range gen.Reproduction {
+				offspring += stats.Offspring
+			}
+			if offspring != context.PopSize {
+				t.Error("Wrong reproduction statistics of generation", gen.Id, offspring)
+			}
+		}
+	}
+}
+
+// The generation observer recording notified generations
+type testGenerationObserver struct {
+	generations []int
+}
+
+func (o *testGenerationObserver) OnGenerationEvaluated(epoch *Generation, pop *genetics.Population) {
+	if pop != nil && epoch.Reproduction == nil {
+		// notified before epoch turnover
+		o.generations = append(o.generations, epoch.Id)
+	}
+}
+
+func TestExperiment_Execute_observers(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	observer := &testGenerationObserver{}
+	experiment := Experiment{Observers:[]GenerationObserver{observer}}
+	if err = experiment.Execute(context, start_genome, &randomGenerationEvaluator{}); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(observer.generations) != context.NumGenerations {
+		t.Error("Wrong number of generations notified", observer.generations)
+	}
+	for i, id := range observer.generations {
+		if id != i {
+			t.Error("Wrong generation notified", i, id)
+		}
+	}
+}
+
+func TestExperiment_Execute_collectDiversity(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	experiment := Experiment{CollectDiversity:true}
+	if err = experiment.Execute(context, start_genome, &randomGenerationEvaluator{}); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, gen := range experiment.Trials[0].Generations {
+		if gen.GeneticDiversity == nil {
+			t.Error("Diversity not collected for generation", gen.Id)
+		} else if gen.GeneticDiversity.UniqueTopologies == 0 {
+			t.Error("Wrong diversity", gen.GeneticDiversity)
+		}
+	}
+}
+
+func TestExperiment_Resume(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pop, err := genetics.NewPopulation(start_genome, context)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	evaluator := &randomGenerationEvaluator{}
+	experiment := Experiment{}
+	if err = experiment.Resume(context, pop, 3, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(experiment.Trials) != 1 {
+		t.Error("Single trial expected", len(experiment.Trials))
+		return
+	}
+	gens := experiment.Trials[0].Generations
+	if len(gens) != 2 || gens[0].Id != 3 || gens[1].Id != 4 {
+		t.Error("Wrong generations evaluated", evaluator.generations)
+	}
+
+	// start generation beyond limit
+	if err = experiment.Resume(context, pop, 5, evaluator); err == nil {
+		t.Error("Error expected for start generation beyond limit")
+	}
+}
+
+func TestExperiment_Execute_evaluationBudget(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	context.MaxEvaluations = 50
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &randomGenerationEvaluator{}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	// the budget exhausted after third generation of 20 organisms
+	trial := experiment.Trials[0]
+	if len(trial.Generations) != 3 {
+		t.Error("Wrong number of generations evaluated", len(trial.Generations))
+	}
+	if evals := trial.Evaluations(); evals != 60 {
+		t.Error("Wrong number of evaluations", evals)
+	}
+	if evals := experiment.AvgEvaluationsPerTrial(); evals != 60 {
+		t.Error("Wrong average number of evaluations", evals)
+	}
+}
+
+// The generation evaluator requesting to stop the trial at given generation
+type stoppingGenerationEvaluator struct {
+	randomGenerationEvaluator
+	stop int
+}
+
+func (e *stoppingGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, context *neat.NeatContext) error {
+	if err := e.randomGenerationEvaluator.GenerationEvaluate(pop, epoch, context); err != nil {
+		return err
+	}
+	epoch.Stopped = epoch.Id == e.stop
+	return nil
+}
+
+func TestExperiment_Execute_stopped(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &stoppingGenerationEvaluator{stop:2}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, trial := range experiment.Trials {
+		if len(trial.Generations) != 3 || !trial.Generations[2].Stopped {
+			t.Error("The trial should be stopped at third generation", len(trial.Generations))
+		} else if trial.Generations[2].Reproduction != nil {
+			t.Error("The epoch should not be executed after stop")
+		}
+		if trial.Solved() {
+			t.Error("The stopped trial should not be solved")
+		}
+	}
+}
+
+func TestExperiment_Execute_activations(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	context.NumGenerations = 2
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	experiment := Experiment{}
+	start := network.ActivationsTotal()
+	if err = experiment.Execute(context, start_genome, &activatingGenerationEvaluator{steps:3}); err != nil {
+		t.Error(err)
+		return
+	}
+	trial := experiment.Trials[0]
+	for _, gen := range trial.Generations {
+		if gen.Evaluations != context.PopSize * 2 {
+			t.Error("Wrong number of evaluations set by evaluator", gen.Evaluations)
+		}
+		if gen.Activations < int64(context.PopSize * 6) {
+			t.Error("Wrong number of activations", gen.Activations)
+		}
+	}
+	if activations := trial.Activations(); activations > network.ActivationsTotal() - start {
+		t.Error("Trial activations exceed total activations", activations)
+	}
+	if experiment.AvgActivationsPerTrial() != float64(trial.Activations()) {
+		t.Error("Wrong average number of activations", experiment.AvgActivationsPerTrial())
+	}
+}
+
+func TestExperiment_Execute_championRegression(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	context.ChampionRegressionGenerations = 2
+	context.ChampionRegressionTolerance = 0.1
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &championGenerationEvaluator{}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	// the champion is preserved after the first epoch and re-evaluated in each following generation
+	if len(evaluator.champions) != context.NumGenerations - 1 {
+		t.Error("Wrong number of champion evaluations", len(evaluator.champions))
+	}
+	for _, champion := range evaluator.champions {
+		if champion.Fitness != 0.5 {
+			t.Error("The fitness of re-evaluated champion should be updated", champion.Fitness)
+		}
+	}
+	for i, gen := range experiment.Trials[0].Generations {
+		expected := context.PopSize
+		if i > 0 {
+			expected++
+		}
+		if gen.Evaluations != expected {
+			t.Error("Wrong number of evaluations in generation", gen.Id, gen.Evaluations)
+		}
+	}
+}
+
+// The generation evaluator which solves the task once during regular evaluation and fails each re-validation of winner
+type luckyGenerationEvaluator struct {
+	randomGenerationEvaluator
+	revalidations int
+}
+
+func (e *luckyGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, context *neat.NeatContext) error {
+	if err := e.randomGenerationEvaluator.GenerationEvaluate(pop, epoch, context); err != nil {
+		return err
+	}
+	epoch.Best.IsWinner = true
+	epoch.Solved = true
+	epoch.WinnerNodes = len(epoch.Best.Genotype.Nodes)
+	return nil
+}
+
+func (e *luckyGenerationEvaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (float64, bool, error) {
+	e.revalidations++
+	return org.Fitness, false, nil
+}
+
+func TestExperiment_Execute_championRevalidation(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	context.ChampionRevalidations = 3
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &luckyGenerationEvaluator{}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	trial := experiment.Trials[0]
+	if trial.Solved() {
+		t.Error("The trial with lucky winner should not be solved")
+	}
+	if len(trial.Generations) != context.NumGenerations {
+		t.Error("The trial should continue after winner rejected", len(trial.Generations))
+	}
+	for _, gen := range trial.Generations {
+		if gen.Solved || gen.WinnerNodes != 0 {
+			t.Error("The generation with rejected winner should not be solved", gen.Id)
+		}
+		if gen.Best.IsWinner {
+			t.Error("The rejected winner should not be marked as winner", gen.Id)
+		}
+	}
+	// the winner fails the first re-validation in each generation
+	if evaluator.revalidations < context.NumGenerations {
+		t.Error("Wrong number of re-validations", evaluator.revalidations)
+	}
+}
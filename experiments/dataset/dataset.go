@@ -0,0 +1,259 @@
+// The dataset package provides evaluation of organisms against supervised datasets, which turns NEAT into classifier
+// or regressor out of the box. The datasets can be loaded from CSV or LibSVM files and split into training and
+// validation parts or into folds for cross-validation. The organisms are evaluated by loss function computed over
+// outputs of their networks for all samples of dataset.
+package dataset
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The supervised dataset holding features and targets of samples
+type Dataset struct {
+	// The features per sample, to be loaded into sensors of network
+	Features [][]float64
+	// The targets per sample, to be compared with outputs of network
+	Targets  [][]float64
+}
+
+// The fold of dataset for cross-validation
+type Fold struct {
+	// The training part of dataset
+	Train      *Dataset
+	// The validation part of dataset held out from training
+	Validation *Dataset
+}
+
+// Creates new dataset with provided features and targets. Returns error if number of features and targets differ or
+// samples have different number of features or targets.
+func New(features, targets [][]float64) (*Dataset, error) {
+	if len(features) != len(targets) {
+		return nil, errors.New(fmt.Sprintf("The number of features rows %d differs from the number of targets rows %d",
+			len(features), len(targets)))
+	}
+	for i := range features {
+		if len(features[i]) != len(features[0]) || len(targets[i]) != len(targets[0]) {
+			return nil, errors.New(fmt.Sprintf("The sample %d has wrong number of features or targets", i))
+		}
+	}
+	return &Dataset{Features:features, Targets:targets}, nil
+}
+
+// Reads dataset from CSV data with numeric values. The last targets columns of each record are targets and other
+// columns are features. If header is true the first record is skipped.
+func ReadCSV(r io.Reader, targets int, header bool) (*Dataset, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if header && len(records) > 0 {
+		records = records[1:]
+	}
+	features_rows, targets_rows := make([][]float64, len(records)), make([][]float64, len(records))
+	for i, record := range records {
+		if targets <= 0 || targets >= len(record) {
+			return nil, errors.New(fmt.Sprintf("The number of target columns %d is out of range [1, %d) at record: %d",
+				targets, len(record), i))
+		}
+		values := make([]float64, len(record))
+		for j, field := range record {
+			if values[j], err = strconv.ParseFloat(strings.TrimSpace(field), 64); err != nil {
+				return nil, errors.New(fmt.Sprintf("Failed to parse value at record: %d, column: %d, reason: %s",
+					i, j, err))
+			}
+		}
+		features_rows[i], targets_rows[i] = values[:len(record) - targets], values[len(record) - targets:]
+	}
+	return New(features_rows, targets_rows)
+}
+
+// Reads dataset from LibSVM data, where each line is the label followed by the sparse features as index:value pairs
+// with indexes starting from one. The label is the single target of sample and the missing features are zeros. If
+// features is zero or negative, the number of features is the maximal index found in data. The empty lines and lines
+// starting with '#' are skipped.
+func ReadLibSVM(r io.Reader, features int) (*Dataset, error) {
+	type sample struct {
+		label   float64
+		indexes []int
+		values  []float64
+	}
+	samples := make([]sample, 0)
+	max_index := 0
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(text)
+		label, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Failed to parse label at line: %d, reason: %s", line, err))
+		}
+		s := sample{label:label}
+		for _, field := range fields[1:] {
+			parts := strings.SplitN(field, ":", 2)
+			if len(parts) != 2 {
+				return nil, errors.New(fmt.Sprintf("Malformed feature [%s] at line: %d", field, line))
+			}
+			index, err := strconv.Atoi(parts[0])
+			if err != nil || index < 1 {
+				return nil, errors.New(fmt.Sprintf("Wrong feature index [%s] at line: %d", parts[0], line))
+			}
+			value, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("Failed to parse feature value at line: %d, reason: %s", line, err))
+			}
+			if features > 0 && index > features {
+				return nil, errors.New(fmt.Sprintf("The feature index %d exceeds the number of features %d at line: %d",
+					index, features, line))
+			}
+			if index > max_index {
+				max_index = index
+			}
+			s.indexes, s.values = append(s.indexes, index - 1), append(s.values, value)
+		}
+		samples = append(samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if features <= 0 {
+		features = max_index
+	}
+	features_rows, targets_rows := make([][]float64, len(samples)), make([][]float64, len(samples))
+	for i, s := range samples {
+		features_rows[i] = make([]float64, features)
+		for j, index := range s.indexes {
+			features_rows[i][index] = s.values[j]
+		}
+		targets_rows[i] = []float64{s.label}
+	}
+	return New(features_rows, targets_rows)
+}
+
+// Returns the number of samples in dataset
+func (d *Dataset) Len() int {
+	return len(d.Features)
+}
+
+// Returns the number of features per sample or zero if dataset is empty
+func (d *Dataset) FeatureCount() int {
+	if len(d.Features) == 0 {
+		return 0
+	}
+	return len(d.Features[0])
+}
+
+// Returns the number of targets per sample or zero if dataset is empty
+func (d *Dataset) TargetCount() int {
+	if len(d.Targets) == 0 {
+		return 0
+	}
+	return len(d.Targets[0])
+}
+
+// Returns dataset with samples at given indexes of this dataset. The samples are shared between datasets.
+func (d *Dataset) Subset(indexes []int) *Dataset {
+	subset := Dataset{Features:make([][]float64, len(indexes)), Targets:make([][]float64, len(indexes))}
+	for i, index := range indexes {
+		subset.Features[i], subset.Targets[i] = d.Features[index], d.Targets[index]
+	}
+	return &subset
+}
+
+// Shuffles samples of this dataset in place with provided random numbers source, or with default source if nil
+func (d *Dataset) Shuffle(rng *rand.Rand) {
+	swap := func(i, j int) {
+		d.Features[i], d.Features[j] = d.Features[j], d.Features[i]
+		d.Targets[i], d.Targets[j] = d.Targets[j], d.Targets[i]
+	}
+	if rng == nil {
+		rand.Shuffle(d.Len(), swap)
+	} else {
+		rng.Shuffle(d.Len(), swap)
+	}
+}
+
+// Splits this dataset into training and validation parts. The validation part holds the last given fraction of
+// samples, thus dataset should be shuffled before split if samples are ordered. The samples are shared between
+// datasets. Returns error if fraction is out of range (0, 1) or any of parts is empty.
+func (d *Dataset) Split(validation float64) (train, valid *Dataset, err error) {
+	if validation <= 0 || validation >= 1 {
+		return nil, nil, errors.New(fmt.Sprintf("The validation fraction is out of range (0, 1): %f", validation))
+	}
+	count := int(float64(d.Len()) * validation + 0.5)
+	if count == 0 || count == d.Len() {
+		return nil, nil, errors.New(fmt.Sprintf("The split of %d samples with validation fraction %f has empty part",
+			d.Len(), validation))
+	}
+	split := d.Len() - count
+	return &Dataset{Features:d.Features[:split], Targets:d.Targets[:split]},
+		&Dataset{Features:d.Features[split:], Targets:d.Targets[split:]}, nil
+}
+
+// Splits this dataset into k folds of contiguous samples for cross-validation, each fold holds out different part of
+// samples as validation. The sizes of validation parts differ at most by one. Returns error if k is less than two or
+// greater than the number of samples.
+func (d *Dataset) KFold(k int) ([]Fold, error) {
+	if k < 2 || k > d.Len() {
+		return nil, errors.New(fmt.Sprintf("The number of folds %d is out of range [2, %d]", k, d.Len()))
+	}
+	folds := make([]Fold, k)
+	start := 0
+	for i := range folds {
+		end := start + d.Len() / k
+		if i < d.Len() % k {
+			end++
+		}
+		train, valid := make([]int, 0, d.Len() - end + start), make([]int, 0, end - start)
+		for j := 0; j < d.Len(); j++ {
+			if j >= start && j < end {
+				valid = append(valid, j)
+			} else {
+				train = append(train, j)
+			}
+		}
+		folds[i] = Fold{Train:d.Subset(train), Validation:d.Subset(valid)}
+		start = end
+	}
+	return folds, nil
+}
+
+// Returns copy of this dataset with the single target of each sample replaced by one-hot encoding of its class. The
+// classes are the distinct target values sorted in ascending order, the class values are returned along with dataset.
+// Returns error if samples have more than one target.
+func (d *Dataset) OneHot() (*Dataset, []float64, error) {
+	if d.TargetCount() != 1 {
+		return nil, nil, errors.New(fmt.Sprintf("The single target expected for one-hot encoding, found: %d",
+			d.TargetCount()))
+	}
+	index := make(map[float64]int)
+	for _, target := range d.Targets {
+		index[target[0]] = 0
+	}
+	classes := make([]float64, 0, len(index))
+	for class := range index {
+		classes = append(classes, class)
+	}
+	sort.Float64s(classes)
+	for i, class := range classes {
+		index[class] = i
+	}
+	encoded := Dataset{Features:d.Features, Targets:make([][]float64, d.Len())}
+	for i, target := range d.Targets {
+		encoded.Targets[i] = make([]float64, len(classes))
+		encoded.Targets[i][index[target[0]]] = 1.0
+	}
+	return &encoded, classes, nil
+}
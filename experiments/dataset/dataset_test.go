@@ -0,0 +1,192 @@
+package dataset
+
+import (
+	"testing"
+	"strings"
+	"math/rand"
+)
+
+func buildDataset(size int) *Dataset {
+	features, targets := make([][]float64, size), make([][]float64, size)
+	for i := 0; i < size; i++ {
+		features[i] = []float64{float64(i), float64(i) * 0.5}
+		targets[i] = []float64{float64(i % 2)}
+	}
+	return &Dataset{Features:features, Targets:targets}
+}
+
+func TestNew(t *testing.T) {
+	data, err := New([][]float64{{1, 2}, {3, 4}}, [][]float64{{0}, {1}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if data.Len() != 2 || data.FeatureCount() != 2 || data.TargetCount() != 1 {
+		t.Error("Wrong dataset dimensions", data.Len(), data.FeatureCount(), data.TargetCount())
+	}
+	if _, err = New([][]float64{{1, 2}}, [][]float64{{0}, {1}}); err == nil {
+		t.Error("Error expected for different number of features and targets rows")
+	}
+	if _, err = New([][]float64{{1, 2}, {3}}, [][]float64{{0}, {1}}); err == nil {
+		t.Error("Error expected for different number of features")
+	}
+	if empty := (&Dataset{}); empty.FeatureCount() != 0 || empty.TargetCount() != 0 {
+		t.Error("Zero dimensions expected for empty dataset")
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	csv := "x1, x2, y1, y2\n0.5, 1, 0, 1\n-2, 3.25, 1, 0\n"
+	data, err := ReadCSV(strings.NewReader(csv), 2, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if data.Len() != 2 || data.FeatureCount() != 2 || data.TargetCount() != 2 {
+		t.Error("Wrong dataset dimensions", data.Len(), data.FeatureCount(), data.TargetCount())
+		return
+	}
+	if data.Features[1][0] != -2 || data.Features[1][1] != 3.25 || data.Targets[0][1] != 1 {
+		t.Error("Wrong values", data.Features, data.Targets)
+	}
+
+	if _, err = ReadCSV(strings.NewReader(csv), 2, false); err == nil {
+		t.Error("Error expected for header parsed as values")
+	}
+	if _, err = ReadCSV(strings.NewReader("1,2\n"), 2, false); err == nil {
+		t.Error("Error expected for no feature columns")
+	}
+	if _, err = ReadCSV(strings.NewReader("1,2\n1,2,3\n"), 1, false); err == nil {
+		t.Error("Error expected for different number of columns")
+	}
+}
+
+func TestReadLibSVM(t *testing.T) {
+	svm := "# comment\n+1 1:0.5 3:2\n\n-1 2:1.5\n"
+	data, err := ReadLibSVM(strings.NewReader(svm), 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if data.Len() != 2 || data.FeatureCount() != 3 || data.TargetCount() != 1 {
+		t.Error("Wrong dataset dimensions", data.Len(), data.FeatureCount(), data.TargetCount())
+		return
+	}
+	expected := [][]float64{{0.5, 0, 2}, {0, 1.5, 0}}
+	for i, row := range expected {
+		for j, v := range row {
+			if data.Features[i][j] != v {
+				t.Error("Wrong feature at:", i, j, data.Features[i][j])
+			}
+		}
+	}
+	if data.Targets[0][0] != 1 || data.Targets[1][0] != -1 {
+		t.Error("Wrong labels", data.Targets)
+	}
+
+	if data, err = ReadLibSVM(strings.NewReader(svm), 5); err != nil {
+		t.Error(err)
+	} else if data.FeatureCount() != 5 {
+		t.Error("Wrong number of features", data.FeatureCount())
+	}
+	for _, malformed := range []string{"a 1:1", "1 1-1", "1 0:1", "1 1:x", "1 9:1"} {
+		if _, err = ReadLibSVM(strings.NewReader(malformed), 5); err == nil {
+			t.Error("Error expected for malformed line:", malformed)
+		}
+	}
+}
+
+func TestDataset_Shuffle(t *testing.T) {
+	data := buildDataset(20)
+	data.Shuffle(rand.New(rand.NewSource(42)))
+	moved := 0
+	for i, features := range data.Features {
+		// the samples are moved together with targets
+		if float64(int(features[0]) % 2) != data.Targets[i][0] || features[1] != features[0] * 0.5 {
+			t.Error("Features and targets are mixed at:", i)
+		}
+		if features[0] != float64(i) {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("Samples should be shuffled")
+	}
+}
+
+func TestDataset_Split(t *testing.T) {
+	train, valid, err := buildDataset(10).Split(0.3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if train.Len() != 7 || valid.Len() != 3 {
+		t.Error("Wrong split", train.Len(), valid.Len())
+	}
+	if valid.Features[0][0] != 7 {
+		t.Error("The last samples expected in validation", valid.Features)
+	}
+	for _, fraction := range []float64{0, 1, 0.01, 0.99} {
+		if _, _, err = buildDataset(10).Split(fraction); err == nil {
+			t.Error("Error expected for validation fraction:", fraction)
+		}
+	}
+}
+
+func TestDataset_KFold(t *testing.T) {
+	folds, err := buildDataset(10).KFold(3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(folds) != 3 {
+		t.Error("Wrong number of folds", len(folds))
+		return
+	}
+	seen := make(map[float64]int)
+	for i, fold := range folds {
+		if fold.Train.Len() + fold.Validation.Len() != 10 {
+			t.Error("Wrong fold size at:", i, fold.Train.Len(), fold.Validation.Len())
+		}
+		if fold.Validation.Len() < 3 || fold.Validation.Len() > 4 {
+			t.Error("Unbalanced validation at:", i, fold.Validation.Len())
+		}
+		for _, features := range fold.Validation.Features {
+			seen[features[0]]++
+		}
+	}
+	if len(seen) != 10 {
+		t.Error("Each sample should be validated once", seen)
+	}
+	for _, k := range []int{1, 11} {
+		if _, err = buildDataset(10).KFold(k); err == nil {
+			t.Error("Error expected for number of folds:", k)
+		}
+	}
+}
+
+func TestDataset_OneHot(t *testing.T) {
+	data := &Dataset{Features:[][]float64{{1}, {2}, {3}}, Targets:[][]float64{{5}, {-1}, {5}}}
+	encoded, classes, err := data.OneHot()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(classes) != 2 || classes[0] != -1 || classes[1] != 5 {
+		t.Error("Wrong classes", classes)
+	}
+	expected := [][]float64{{0, 1}, {1, 0}, {0, 1}}
+	for i, row := range expected {
+		for j, v := range row {
+			if encoded.Targets[i][j] != v {
+				t.Error("Wrong encoding at:", i, encoded.Targets[i])
+			}
+		}
+	}
+	if data.Targets[0][0] != 5 {
+		t.Error("The original dataset should not change")
+	}
+	if _, _, err = encoded.OneHot(); err == nil {
+		t.Error("Error expected for multiple targets")
+	}
+}
@@ -0,0 +1,214 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The key of organism tag holding fitness of organism on validation dataset
+const ValidationFitnessTag = "dataset_validation_fitness"
+
+// The key of organism tag holding loss of organism on validation dataset
+const ValidationLossTag = "dataset_validation_loss"
+
+// The evaluator of organisms against supervised dataset. The fitness of organism is computed by loss function over
+// outputs of its network for all samples of training dataset, and the loss value is stored as organism error. If
+// validation dataset is provided, the organism is evaluated against it as well and the validation fitness and loss are
+// stored in organism tags, but they don't affect selection. The validation fitness of the champion of each generation
+// is reported in generation statistics, which allows to select the champion by validation fitness and to stop the
+// trial early when it stops improving. It implements experiments.GenerationEvaluator, experiments.OrganismEvaluator and
+// experiments.TrialRunObserver.
+type Evaluator struct {
+	// The training dataset
+	Train           *Dataset
+	// The validation dataset, may be nil
+	Validation      *Dataset
+	// The number of folds for cross-validation. If it is greater than one the training dataset is split into folds
+	// and each generation is evaluated against different fold, i.e. the generation N is trained on samples out of fold
+	// N mod Folds and validated on samples of this fold, thus the Validation dataset is not used.
+	Folds           int
+	// The loss function
+	Loss            LossType
+	// The number of forward steps of network activation per sample, if zero the depth of network is used
+	ActivationSteps int
+	// The minimal training fitness to consider organism as solver, if zero no organism is solver
+	SolvedFitness   float64
+	// The number of generations without improvement of champion's validation fitness after which the trial is
+	// stopped, if zero the trial is not stopped early. Requires validation dataset or folds.
+	Patience        int
+
+	// The folds of training dataset split for cross-validation
+	folds           []Fold
+	// The current training and validation datasets
+	train, valid    *Dataset
+	// The best validation fitness of champions in current trial, and the number of generations since it was found
+	bestValidation  float64
+	stale           int
+	// The flag to indicate whether any champion was validated in current trial
+	validated       bool
+}
+
+// Resets tracking of champions validation fitness at the start of new trial
+func (e *Evaluator) TrialRunStarted(trial *experiments.Trial) {
+	e.bestValidation, e.stale, e.validated = 0, 0, false
+}
+
+// Evaluates one generation of population against training dataset and validation dataset if any
+func (e *Evaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiments.Generation, context *neat.NeatContext) (err error) {
+	if e.train, e.valid, err = e.datasets(epoch.Id); err != nil {
+		return err
+	}
+	for _, org := range pop.Organisms {
+		res, err := experiments.EvaluateWithErrorPolicy(org, e.orgEvaluate, context)
+		if err != nil {
+			return err
+		}
+		if res && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness) {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(org.Genotype.Nodes)
+			epoch.WinnerGenes = org.Genotype.Extrons()
+			epoch.WinnerEvals = context.PopSize * epoch.Id + org.Genotype.Id
+			epoch.Best = org
+		}
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+	if e.valid != nil && epoch.Best != nil {
+		e.validateChampion(epoch)
+	}
+	return nil
+}
+
+// Stores validation fitness of the champion of given epoch and requests to stop the trial if it was not improved for
+// the number of generations set by Patience. With cross-validation folds the champions are validated against
+// different folds, thus patience should account for this noise.
+func (e *Evaluator) validateChampion(epoch *experiments.Generation) {
+	fitness, ok := epoch.Best.Tags[ValidationFitnessTag].(float64)
+	if !ok {
+		// the champion failed evaluation
+		return
+	}
+	epoch.ValidationFitness, epoch.Validated = fitness, true
+	if !e.validated || fitness > e.bestValidation {
+		e.bestValidation, e.stale, e.validated = fitness, 0, true
+	} else {
+		e.stale++
+	}
+	if e.Patience > 0 && e.stale >= e.Patience && !epoch.Solved {
+		neat.InfoLog(fmt.Sprintf("Validation fitness of champion was not improved for %d generations, best: %f",
+			e.stale, e.bestValidation))
+		epoch.Stopped = true
+	}
+}
+
+// Evaluates organism against training dataset and returns its fitness. The validation dataset is not used.
+func (e *Evaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (fitness float64, solved bool, err error) {
+	if e.Train == nil {
+		return 0.0, false, errors.New("Training dataset is not set")
+	}
+	fitness, _, err = e.Evaluate(org, e.Train)
+	if err != nil {
+		return 0.0, false, err
+	}
+	return fitness, e.SolvedFitness > 0 && fitness >= e.SolvedFitness, nil
+}
+
+// Evaluates organism against training and validation datasets of current generation
+func (e *Evaluator) orgEvaluate(org *genetics.Organism) (bool, error) {
+	fitness, loss, err := e.Evaluate(org, e.train)
+	if err != nil {
+		return false, err
+	}
+	org.Fitness, org.Error = fitness, loss
+	org.IsWinner = e.SolvedFitness > 0 && fitness >= e.SolvedFitness
+	if e.valid != nil {
+		valid_fitness, valid_loss, err := e.Evaluate(org, e.valid)
+		if err != nil {
+			return false, err
+		}
+		if org.Tags == nil {
+			org.Tags = make(genetics.OrganismTags)
+		}
+		org.Tags[ValidationFitnessTag], org.Tags[ValidationLossTag] = valid_fitness, valid_loss
+	}
+
+	if neat.LogLevel == neat.LogLevelDebug {
+		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f\tloss: %f", org.Genotype.Id, fitness, loss))
+	}
+	return org.IsWinner, nil
+}
+
+// Evaluates organism against provided dataset. Returns fitness and loss value of organism.
+func (e *Evaluator) Evaluate(org *genetics.Organism, data *Dataset) (fitness, loss float64, err error) {
+	phenotype, err := org.Phenotype()
+	if err != nil {
+		return 0, 0, err
+	}
+	outputs, err := e.Outputs(phenotype, data)
+	if err != nil {
+		return 0, 0, err
+	}
+	return e.Loss.Fitness(outputs, data.Targets)
+}
+
+// Activates network with features of each sample of dataset and returns network outputs per sample. The network is
+// flushed before each sample. Returns error if the number of features differs from the number of network inputs.
+func (e *Evaluator) Outputs(net *network.Network, data *Dataset) ([][]float64, error) {
+	inputs := 0
+	for _, node := range net.AllNodes() {
+		if node.NeuronType == network.InputNeuron {
+			inputs++
+		}
+	}
+	if data.Len() > 0 && data.FeatureCount() != inputs {
+		return nil, errors.New(fmt.Sprintf("The number of features %d differs from the number of network inputs %d",
+			data.FeatureCount(), inputs))
+	}
+	steps := e.ActivationSteps
+	if steps <= 0 {
+		depth, err := net.MaxDepth()
+		if err != nil {
+			neat.WarnLog(fmt.Sprintf("Failed to estimate maximal depth of the network with loop, using depth: %d", depth))
+		}
+		steps = depth
+		if steps <= 0 {
+			steps = 1
+		}
+	}
+	outputs := make([][]float64, data.Len())
+	for i, features := range data.Features {
+		if _, err := net.Flush(); err != nil {
+			return nil, err
+		}
+		if err := net.LoadSensors(features); err != nil {
+			return nil, err
+		}
+		if _, err := net.ForwardSteps(steps); err != nil {
+			return nil, err
+		}
+		outputs[i] = net.ReadOutputs()
+	}
+	return outputs, nil
+}
+
+// Returns training and validation datasets for given generation
+func (e *Evaluator) datasets(generation int) (train, valid *Dataset, err error) {
+	if e.Train == nil {
+		return nil, nil, errors.New("Training dataset is not set")
+	}
+	if e.Folds <= 1 {
+		return e.Train, e.Validation, nil
+	}
+	if e.folds == nil {
+		if e.folds, err = e.Train.KFold(e.Folds); err != nil {
+			return nil, nil, err
+		}
+	}
+	fold := e.folds[generation % len(e.folds)]
+	return fold.Train, fold.Validation, nil
+}
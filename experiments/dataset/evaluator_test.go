@@ -0,0 +1,209 @@
+package dataset
+
+import (
+	"testing"
+	"math"
+	"math/rand"
+	"os"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The XOR dataset
+var xorDataset = &Dataset{
+	Features:[][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	Targets:[][]float64{{0}, {1}, {1}, {0}},
+}
+
+func readStartGenome(t *testing.T) *genetics.Genome {
+	file, err := os.Open("../../data/xorstartgenes")
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	defer file.Close()
+	genome, err := genetics.ReadGenome(file, 1)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	return genome
+}
+
+func TestEvaluator_Outputs(t *testing.T) {
+	genome := readStartGenome(t)
+	if genome == nil {
+		return
+	}
+	org, err := genetics.NewOrganism(0.0, genome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	phenotype, _ := org.Phenotype()
+	evaluator := Evaluator{}
+	outputs, err := evaluator.Outputs(phenotype, xorDataset)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(outputs) != xorDataset.Len() {
+		t.Error("Wrong number of outputs", len(outputs))
+		return
+	}
+	// all weights of start genome are zero
+	for i, out := range outputs {
+		if len(out) != 1 || out[0] != 0.5 {
+			t.Error("Wrong output at:", i, out)
+		}
+	}
+
+	if _, err = evaluator.Outputs(phenotype, &Dataset{Features:[][]float64{{1}}, Targets:[][]float64{{1}}}); err == nil {
+		t.Error("Error expected for wrong number of features")
+	}
+}
+
+func TestEvaluator_OrganismEvaluate(t *testing.T) {
+	genome := readStartGenome(t)
+	if genome == nil {
+		return
+	}
+	org, err := genetics.NewOrganism(0.0, genome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := Evaluator{Train:xorDataset, Loss:MeanSquaredErrorLoss, SolvedFitness:0.8}
+	fitness, solved, err := evaluator.OrganismEvaluate(org, &neat.NeatContext{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if math.Abs(fitness - 1.0 / 1.25) > 1e-12 {
+		t.Error("Wrong fitness", fitness)
+	}
+	if !solved {
+		t.Error("The organism should be solver with fitness", fitness)
+	}
+	if _, _, err = (&Evaluator{}).OrganismEvaluate(org, &neat.NeatContext{}); err == nil {
+		t.Error("Error expected for missing training dataset")
+	}
+}
+
+func TestEvaluator_datasets(t *testing.T) {
+	data := buildDataset(9)
+	evaluator := Evaluator{Train:data, Validation:xorDataset}
+	if train, valid, err := evaluator.datasets(5); err != nil {
+		t.Error(err)
+	} else if train != data || valid != xorDataset {
+		t.Error("The configured datasets expected")
+	}
+
+	evaluator.Folds = 3
+	for generation := 0; generation < 6; generation++ {
+		train, valid, err := evaluator.datasets(generation)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		// the folds of contiguous samples rotate by generation
+		if train.Len() != 6 || valid.Len() != 3 || valid.Features[0][0] != float64(3 * (generation % 3)) {
+			t.Error("Wrong fold at generation:", generation, train.Len(), valid.Features)
+		}
+	}
+	if _, _, err := (&Evaluator{Train:data, Folds:10}).datasets(0); err == nil {
+		t.Error("Error expected for too many folds")
+	}
+}
+
+func TestEvaluator_GenerationEvaluate(t *testing.T) {
+	rand.Seed(42)
+	start_genome := readStartGenome(t)
+	if start_genome == nil {
+		return
+	}
+	context := &neat.NeatContext{
+		PopSize:30,
+		CompatThreshold:3.0,
+		DropOffAge:15,
+		SurvivalThresh:0.2,
+		MutateOnlyProb:0.25,
+		MutateLinkWeightsProb:0.9,
+		MutateAddNodeProb:0.03,
+		MutateAddLinkProb:0.08,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		PrintEvery:2,
+		NumRuns:1,
+		NumGenerations:3,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	experiment := experiments.Experiment{}
+	evaluator := &Evaluator{Train:xorDataset, Validation:xorDataset, Loss:CrossEntropyLoss}
+	if err := experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	generations := experiment.Trials[0].Generations
+	if len(generations) != 3 {
+		t.Error("Wrong number of generations", len(generations))
+		return
+	}
+	best := generations[len(generations) - 1].Best
+	if best == nil || best.Error <= 0.0 {
+		t.Error("Wrong best organism", best)
+		return
+	}
+	fitness, ok := best.Tags[ValidationFitnessTag].(float64)
+	if !ok {
+		t.Error("Validation fitness expected", best.Tags)
+	} else if math.Abs(fitness - 1.0 / (1.0 + best.Error)) > 1e-12 {
+		t.Error("The validation fitness should be equal to training fitness for the same dataset", fitness, best.Error)
+	}
+	if _, ok = best.Tags[ValidationLossTag].(float64); !ok {
+		t.Error("Validation loss expected", best.Tags)
+	}
+	for _, epoch := range generations {
+		if !epoch.Validated || epoch.ValidationFitness != epoch.Best.Tags[ValidationFitnessTag] {
+			t.Error("Validation fitness of champion expected at generation:", epoch.Id, epoch.ValidationFitness)
+		}
+	}
+}
+
+func TestEvaluator_validateChampion(t *testing.T) {
+	evaluator := Evaluator{Patience:2}
+	validate := func(fitness interface{}) *experiments.Generation {
+		epoch := &experiments.Generation{Best:&genetics.Organism{Tags:genetics.OrganismTags{}}}
+		if fitness != nil {
+			epoch.Best.Tags[ValidationFitnessTag] = fitness
+		}
+		evaluator.validateChampion(epoch)
+		return epoch
+	}
+	// the first validation is always the best, even with zero fitness
+	for i, fitness := range []float64{0.0, 0.5, 0.6, 0.6} {
+		if epoch := validate(fitness); !epoch.Validated || epoch.ValidationFitness != fitness || epoch.Stopped {
+			t.Error("The trial should not be stopped at:", i, epoch.ValidationFitness)
+		}
+	}
+	if epoch := validate(nil); epoch.Validated || epoch.Stopped {
+		t.Error("The champion failed evaluation should be skipped")
+	}
+	if epoch := validate(0.55); !epoch.Stopped {
+		t.Error("The trial should be stopped after patience exhausted")
+	}
+
+	evaluator.TrialRunStarted(&experiments.Trial{})
+	if epoch := validate(0.1); epoch.Stopped {
+		t.Error("The tracking should be reset in new trial")
+	}
+	evaluator.Patience = 0
+	for i := 0; i < 5; i++ {
+		if epoch := validate(0.05); epoch.Stopped {
+			t.Error("The trial should not be stopped without patience")
+		}
+	}
+}
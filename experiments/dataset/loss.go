@@ -0,0 +1,179 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The loss function to compare outputs of network with targets of dataset
+type LossType int
+
+// The supported loss functions
+const (
+	// The mean squared error of outputs over all samples and targets
+	MeanSquaredErrorLoss LossType = iota
+	// The cross-entropy of outputs, which is binary cross-entropy of single output or categorical cross-entropy of
+	// softmax over outputs with one-hot targets
+	CrossEntropyLoss
+	// The fraction of misclassified samples, the class of single output is output > 0.5, otherwise it is the index of
+	// the maximal output, and the class of targets is determined the same way
+	AccuracyLoss
+)
+
+// The outputs of network are clipped to the range [crossEntropyEpsilon, 1 - crossEntropyEpsilon] to keep finite
+// cross-entropy of saturated outputs
+const crossEntropyEpsilon = 1e-7
+
+// Returns loss function type by its name. Returns error if name is unknown.
+func LossTypeByName(name string) (LossType, error) {
+	switch name {
+	case "mse":
+		return MeanSquaredErrorLoss, nil
+	case "cross_entropy":
+		return CrossEntropyLoss, nil
+	case "accuracy":
+		return AccuracyLoss, nil
+	default:
+		return 0, errors.New(fmt.Sprintf("Unsupported loss function: %s", name))
+	}
+}
+
+// Returns the name of loss function
+func (l LossType) String() string {
+	switch l {
+	case MeanSquaredErrorLoss:
+		return "mse"
+	case CrossEntropyLoss:
+		return "cross_entropy"
+	case AccuracyLoss:
+		return "accuracy"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(l))
+	}
+}
+
+// Computes value of this loss function for provided outputs and targets per sample, the lower value is better. Returns
+// error if number of outputs and targets differ or loss function is not supported.
+func (l LossType) Loss(outputs, targets [][]float64) (float64, error) {
+	if len(outputs) != len(targets) || len(outputs) == 0 {
+		return 0, errors.New(fmt.Sprintf("The number of outputs rows %d differs from the number of targets rows %d or zero",
+			len(outputs), len(targets)))
+	}
+	loss := 0.0
+	for i, out := range outputs {
+		if len(out) != len(targets[i]) {
+			return 0, errors.New(fmt.Sprintf("The number of outputs %d differs from the number of targets %d at sample: %d",
+				len(out), len(targets[i]), i))
+		}
+		switch l {
+		case MeanSquaredErrorLoss:
+			for j, o := range out {
+				loss += (o - targets[i][j]) * (o - targets[i][j]) / float64(len(out))
+			}
+		case CrossEntropyLoss:
+			loss += crossEntropy(out, targets[i])
+		case AccuracyLoss:
+			if class(out) != class(targets[i]) {
+				loss++
+			}
+		default:
+			return 0, errors.New(fmt.Sprintf("Unsupported loss function: %d", l))
+		}
+	}
+	return loss / float64(len(outputs)), nil
+}
+
+// Computes fitness for provided outputs and targets per sample, the higher value is better. The fitness is in range
+// (0, 1], which is 1 / (1 + loss) for mean squared error and cross-entropy, and the fraction of correctly classified
+// samples for accuracy. Returns fitness along with loss value.
+func (l LossType) Fitness(outputs, targets [][]float64) (fitness, loss float64, err error) {
+	if loss, err = l.Loss(outputs, targets); err != nil {
+		return 0, 0, err
+	}
+	if l == AccuracyLoss {
+		return 1.0 - loss, loss, nil
+	}
+	return 1.0 / (1.0 + loss), loss, nil
+}
+
+// Computes derivatives of this loss function for single sample by network outputs, i.e. the gradient to be propagated
+// backward through network (see network.GradientNetwork). The loss of dataset is the mean over samples, thus the
+// gradients of samples should be averaged as well. Returns error if number of outputs and targets differ or loss
+// function is not differentiable.
+func (l LossType) Gradient(out, target []float64) ([]float64, error) {
+	if len(out) != len(target) {
+		return nil, errors.New(fmt.Sprintf("The number of outputs %d differs from the number of targets %d",
+			len(out), len(target)))
+	}
+	grad := make([]float64, len(out))
+	switch l {
+	case MeanSquaredErrorLoss:
+		for j, o := range out {
+			grad[j] = 2.0 * (o - target[j]) / float64(len(out))
+		}
+	case CrossEntropyLoss:
+		if len(out) == 1 {
+			o := math.Max(crossEntropyEpsilon, math.Min(1.0 - crossEntropyEpsilon, out[0]))
+			grad[0] = (o - target[0]) / (o * (1.0 - o))
+			break
+		}
+		// the gradient of categorical cross-entropy of softmax is the difference of softmax and targets
+		max := math.Inf(-1)
+		for _, o := range out {
+			max = math.Max(max, o)
+		}
+		sum, total := 0.0, 0.0
+		for j, o := range out {
+			grad[j] = math.Exp(o - max)
+			sum += grad[j]
+			total += target[j]
+		}
+		for j := range grad {
+			grad[j] = grad[j] / sum * total - target[j]
+		}
+	default:
+		return nil, errors.New(fmt.Sprintf("The loss function is not differentiable: %s", l))
+	}
+	return grad, nil
+}
+
+// Computes cross-entropy of outputs with targets of single sample
+func crossEntropy(out, target []float64) float64 {
+	if len(out) == 1 {
+		o := math.Max(crossEntropyEpsilon, math.Min(1.0 - crossEntropyEpsilon, out[0]))
+		return -(target[0] * math.Log(o) + (1.0 - target[0]) * math.Log(1.0 - o))
+	}
+	// the log of softmax computed with shift by maximal output to avoid overflow
+	max := math.Inf(-1)
+	for _, o := range out {
+		max = math.Max(max, o)
+	}
+	sum := 0.0
+	for _, o := range out {
+		sum += math.Exp(o - max)
+	}
+	log_sum := max + math.Log(sum)
+	res := 0.0
+	for j, o := range out {
+		res -= target[j] * (o - log_sum)
+	}
+	return res
+}
+
+// Returns the class of values, which is 0 or 1 for single value thresholded at 0.5, or the index of the maximal value
+func class(values []float64) int {
+	if len(values) == 1 {
+		if values[0] > 0.5 {
+			return 1
+		}
+		return 0
+	}
+	res := 0
+	for i, v := range values {
+		if v > values[res] {
+			res = i
+		}
+	}
+	return res
+}
@@ -0,0 +1,127 @@
+package dataset
+
+import (
+	"testing"
+	"math"
+)
+
+func TestLossTypeByName(t *testing.T) {
+	for _, loss := range []LossType{MeanSquaredErrorLoss, CrossEntropyLoss, AccuracyLoss} {
+		if res, err := LossTypeByName(loss.String()); err != nil {
+			t.Error(err)
+		} else if res != loss {
+			t.Error("Wrong loss type", res, loss)
+		}
+	}
+	if _, err := LossTypeByName("unknown"); err == nil {
+		t.Error("Error expected for unknown loss")
+	}
+}
+
+func TestLossType_Loss(t *testing.T) {
+	outputs := [][]float64{{0.9}, {0.2}, {0.6}, {0.4}}
+	targets := [][]float64{{1}, {0}, {0}, {1}}
+	if loss, err := MeanSquaredErrorLoss.Loss(outputs, targets); err != nil {
+		t.Error(err)
+	} else if expected := (0.01 + 0.04 + 0.36 + 0.36) / 4; math.Abs(loss - expected) > 1e-12 {
+		t.Error("Wrong MSE", loss, expected)
+	}
+	if loss, err := AccuracyLoss.Loss(outputs, targets); err != nil {
+		t.Error(err)
+	} else if loss != 0.5 {
+		t.Error("Wrong accuracy loss", loss)
+	}
+	if loss, err := CrossEntropyLoss.Loss(outputs, targets); err != nil {
+		t.Error(err)
+	} else if expected := -(math.Log(0.9) + math.Log(0.8) + math.Log(0.4) + math.Log(0.4)) / 4; math.Abs(loss - expected) > 1e-12 {
+		t.Error("Wrong binary cross-entropy", loss, expected)
+	}
+
+	// the categorical cross-entropy of softmax with one-hot targets
+	multi := [][]float64{{2, 0, 0}, {0, 1000, 0}}
+	one_hot := [][]float64{{1, 0, 0}, {0, 1, 0}}
+	if loss, err := CrossEntropyLoss.Loss(multi, one_hot); err != nil {
+		t.Error(err)
+	} else if expected := -math.Log(math.Exp(2) / (math.Exp(2) + 2)) / 2; math.Abs(loss - expected) > 1e-12 {
+		t.Error("Wrong categorical cross-entropy", loss, expected)
+	}
+	if loss, err := AccuracyLoss.Loss(multi, [][]float64{{1, 0, 0}, {0, 0, 1}}); err != nil {
+		t.Error(err)
+	} else if loss != 0.5 {
+		t.Error("Wrong accuracy loss", loss)
+	}
+
+	// the saturated outputs have finite cross-entropy
+	if loss, err := CrossEntropyLoss.Loss([][]float64{{0}}, [][]float64{{1}}); err != nil {
+		t.Error(err)
+	} else if math.IsInf(loss, 0) || math.IsNaN(loss) {
+		t.Error("Finite cross-entropy expected", loss)
+	}
+
+	if _, err := MeanSquaredErrorLoss.Loss(outputs, targets[1:]); err == nil {
+		t.Error("Error expected for different number of rows")
+	}
+	if _, err := MeanSquaredErrorLoss.Loss([][]float64{{1, 2}}, [][]float64{{1}}); err == nil {
+		t.Error("Error expected for different number of outputs and targets")
+	}
+	if _, err := LossType(100).Loss(outputs, targets); err == nil {
+		t.Error("Error expected for unsupported loss")
+	}
+}
+
+func TestLossType_Fitness(t *testing.T) {
+	outputs := [][]float64{{0.9}, {0.2}}
+	targets := [][]float64{{1}, {1}}
+	if fitness, loss, err := MeanSquaredErrorLoss.Fitness(outputs, targets); err != nil {
+		t.Error(err)
+	} else if fitness != 1.0 / (1.0 + loss) {
+		t.Error("Wrong MSE fitness", fitness, loss)
+	}
+	if fitness, _, err := AccuracyLoss.Fitness(outputs, targets); err != nil {
+		t.Error(err)
+	} else if fitness != 0.5 {
+		t.Error("Wrong accuracy fitness", fitness)
+	}
+	if fitness, _, err := MeanSquaredErrorLoss.Fitness(targets, targets); err != nil {
+		t.Error(err)
+	} else if fitness != 1.0 {
+		t.Error("The perfect fitness expected", fitness)
+	}
+}
+
+func TestLossType_Gradient(t *testing.T) {
+	samples := []struct {
+		out, target []float64
+	}{
+		{[]float64{0.7}, []float64{1}},
+		{[]float64{0.3}, []float64{0}},
+		{[]float64{0.2, -0.5, 1.3}, []float64{0, 0, 1}},
+		{[]float64{0.4, 0.1}, []float64{1, 0}},
+	}
+	h := 1e-6
+	for _, loss := range []LossType{MeanSquaredErrorLoss, CrossEntropyLoss} {
+		for i, sample := range samples {
+			grad, err := loss.Gradient(sample.out, sample.target)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for j := range sample.out {
+				out := append([]float64(nil), sample.out...)
+				out[j] += h
+				upper, _ := loss.Loss([][]float64{out}, [][]float64{sample.target})
+				out[j] -= 2 * h
+				lower, _ := loss.Loss([][]float64{out}, [][]float64{sample.target})
+				if numeric := (upper - lower) / (2 * h); math.Abs(numeric - grad[j]) > 1e-6 {
+					t.Error("Wrong gradient of", loss, "at sample:", i, "output:", j, grad[j], numeric)
+				}
+			}
+		}
+	}
+	if _, err := AccuracyLoss.Gradient([]float64{0.5}, []float64{1}); err == nil {
+		t.Error("Error expected for not differentiable loss")
+	}
+	if _, err := MeanSquaredErrorLoss.Gradient([]float64{0.5}, []float64{1, 0}); err == nil {
+		t.Error("Error expected for wrong number of targets")
+	}
+}
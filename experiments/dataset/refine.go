@@ -0,0 +1,100 @@
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The default learning rate of Adam optimizer used by refiner
+const DefaultRefineLearningRate = 0.01
+
+// The refiner of network weights of organism by gradient descent over dataset, which fine-tunes the weights of
+// champion found by evolution, i.e. the hybrid of evolution and backpropagation. The topology of network is kept and
+// only weights of its links are optimized. Only feed-forward networks can be refined.
+type Refiner struct {
+	// The loss function to be minimized, should be differentiable
+	Loss      LossType
+	// The optimizer of weights, if nil the Adam optimizer with DefaultRefineLearningRate is used
+	Optimizer network.Optimizer
+	// The number of passes over dataset
+	Epochs    int
+	// The number of samples per update of weights, if zero or negative all samples of dataset are used per update
+	BatchSize int
+}
+
+// Refines weights of organism's phenotype with samples of provided dataset and saves refined weights into its genotype
+// (see genetics.Organism.SavePhenotypeWeights). Returns the loss over dataset before and after refinement. Returns
+// error if network is not feed-forward or loss function is not differentiable.
+func (r *Refiner) Refine(org *genetics.Organism, data *Dataset) (before, after float64, err error) {
+	if data.Len() == 0 {
+		return 0, 0, errors.New("The dataset for refinement is empty")
+	}
+	phenotype, err := org.Phenotype()
+	if err != nil {
+		return 0, 0, err
+	}
+	grad, err := phenotype.GradientNetwork()
+	if err != nil {
+		return 0, 0, err
+	}
+	optimizer := r.Optimizer
+	if optimizer == nil {
+		optimizer = network.NewAdamOptimizer(DefaultRefineLearningRate)
+	}
+	batch := r.BatchSize
+	if batch <= 0 || batch > data.Len() {
+		batch = data.Len()
+	}
+
+	if before, err = r.loss(grad, data); err != nil {
+		return 0, 0, err
+	}
+	for epoch := 0; epoch < r.Epochs; epoch++ {
+		for start := 0; start < data.Len(); start += batch {
+			end := start + batch
+			if end > data.Len() {
+				end = data.Len()
+			}
+			for i := start; i < end; i++ {
+				out, err := grad.Forward(data.Features[i])
+				if err != nil {
+					return 0, 0, err
+				}
+				out_grad, err := r.Loss.Gradient(out, data.Targets[i])
+				if err != nil {
+					return 0, 0, err
+				}
+				if err = grad.Backward(out_grad); err != nil {
+					return 0, 0, err
+				}
+			}
+			if err = grad.Step(optimizer, 1.0 / float64(end - start)); err != nil {
+				return 0, 0, err
+			}
+		}
+		if neat.LogLevel == neat.LogLevelDebug {
+			loss, _ := r.loss(grad, data)
+			neat.DebugLog(fmt.Sprintf("Organism #%3d\trefinement epoch: %d\tloss: %f", org.Genotype.Id, epoch, loss))
+		}
+	}
+	if after, err = r.loss(grad, data); err != nil {
+		return 0, 0, err
+	}
+	return before, after, org.SavePhenotypeWeights()
+}
+
+// Computes the loss of gradient network over dataset
+func (r *Refiner) loss(grad *network.GradientNetwork, data *Dataset) (float64, error) {
+	outputs := make([][]float64, data.Len())
+	for i, features := range data.Features {
+		out, err := grad.Forward(features)
+		if err != nil {
+			return 0, err
+		}
+		outputs[i] = out
+	}
+	return r.Loss.Loss(outputs, data.Targets)
+}
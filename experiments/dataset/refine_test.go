@@ -0,0 +1,62 @@
+package dataset
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The OR dataset, which is linearly separable and can be fitted by network without hidden neurons
+var orDataset = &Dataset{
+	Features:[][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+	Targets:[][]float64{{0}, {1}, {1}, {1}},
+}
+
+func TestRefiner_Refine(t *testing.T) {
+	for _, refiner := range []*Refiner{
+		{Loss:MeanSquaredErrorLoss, Epochs:200},
+		{Loss:CrossEntropyLoss, Epochs:100, BatchSize:1, Optimizer:network.NewSGDOptimizer(0.5, 0.5)},
+	} {
+		genome := readStartGenome(t)
+		if genome == nil {
+			return
+		}
+		org, err := genetics.NewOrganism(0.0, genome, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before, after, err := refiner.Refine(org, orDataset)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if after >= before * 0.5 {
+			t.Error("The loss should decrease by refinement", refiner.Loss, before, after)
+		}
+		// the refined weights are saved in genome and give the same loss after phenotype rebuilt
+		if err = org.UpdatePhenotype(); err != nil {
+			t.Error(err)
+			return
+		}
+		evaluator := Evaluator{Loss:refiner.Loss}
+		if _, loss, err := evaluator.Evaluate(org, orDataset); err != nil {
+			t.Error(err)
+		} else if math.Abs(loss - after) > 1e-9 {
+			t.Error("The loss of rebuilt phenotype differs from refined", loss, after)
+		}
+	}
+
+	genome := readStartGenome(t)
+	if genome == nil {
+		return
+	}
+	org, _ := genetics.NewOrganism(0.0, genome, 1)
+	if _, _, err := (&Refiner{Loss:AccuracyLoss, Epochs:1}).Refine(org, orDataset); err == nil {
+		t.Error("Error expected for not differentiable loss")
+	}
+	if _, _, err := (&Refiner{Loss:MeanSquaredErrorLoss}).Refine(org, &Dataset{}); err == nil {
+		t.Error("Error expected for empty dataset")
+	}
+}
@@ -0,0 +1,147 @@
+package experiments
+
+import (
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"errors"
+	"fmt"
+)
+
+// The interface describing simulated environment which is stepped by actions of agent controlled by network, e.g.
+// Gym-style environments for embodied experiments.
+type Environment interface {
+	// Invoked to reset environment to the initial state before new episode. Returns the initial observations.
+	Reset() (obs []float64)
+	// Invoked to apply given action to environment and advance simulation one step. Returns observations after
+	// the step, reward received for the step and flag indicating whether episode is finished.
+	Step(action []float64) (obs []float64, reward float64, done bool)
+}
+
+// The optional interface of environment which may fail during simulation, e.g. remote environment. The episode
+// runner checks the error after each step and aborts episode if it was reported.
+type EnvironmentErrorReporter interface {
+	// Returns the last error of environment or nil
+	Err() error
+}
+
+// The default maximal number of activation attempts per episode step if not set in runner
+const defaultEpisodeActivationSteps = 20
+
+// The runner of episodes which wires network to environment: at each step observations are loaded into network
+// sensors, the network is activated and its outputs are applied to environment as action.
+type EpisodeRunner struct {
+	// The maximal number of steps per episode, zero means that episode runs until environment reports it is done
+	MaxSteps        int
+	// The maximal number of activation attempts per step, zero means default
+	ActivationSteps int
+}
+
+// Runs one episode in given environment with provided network as agent. The network is flushed and environment
+// reset before episode. Returns total reward received during episode and the number of steps made. If environment
+// implements EnvironmentErrorReporter the error reported by it is returned.
+func (r EpisodeRunner) RunEpisode(net *network.Network, env Environment) (total_reward float64, steps int, err error) {
+	return r.runEpisode(net, env, nil)
+}
+
+// Runs one episode like RunEpisode and records observations loaded into network, outputs of network and rewards at
+// each step into returned episode. The episode can be replayed later by ReplayRunner.
+func (r EpisodeRunner) RecordEpisode(net *network.Network, env Environment) (*Episode, error) {
+	episode := &Episode{}
+	if _, _, err := r.runEpisode(net, env, episode); err != nil {
+		return nil, err
+	}
+	return episode, nil
+}
+
+// Runs one episode recording its steps into provided episode if it is not nil
+func (r EpisodeRunner) runEpisode(net *network.Network, env Environment, episode *Episode) (total_reward float64, steps int, err error) {
+	if _, err = net.Flush(); err != nil {
+		return 0.0, 0, err
+	}
+	activation_steps := r.ActivationSteps
+	if activation_steps <= 0 {
+		activation_steps = defaultEpisodeActivationSteps
+	}
+	reporter, _ := env.(EnvironmentErrorReporter)
+
+	obs := env.Reset()
+	if reporter != nil && reporter.Err() != nil {
+		return 0.0, 0, reporter.Err()
+	}
+	action := make([]float64, len(net.Outputs))
+	for steps = 0; r.MaxSteps <= 0 || steps < r.MaxSteps; {
+		if err = net.LoadSensors(obs); err != nil {
+			return total_reward, steps, err
+		}
+		if res, err := net.ActivateSteps(activation_steps); err != nil {
+			return total_reward, steps, err
+		} else if !res {
+			return total_reward, steps, errors.New(
+				fmt.Sprintf("Failed to activate network at episode step: %d", steps))
+		}
+		action = net.ReadOutputsInto(action)
+		if episode != nil {
+			episode.Inputs = append(episode.Inputs, append([]float64(nil), obs...))
+			episode.Outputs = append(episode.Outputs, append([]float64(nil), action...))
+		}
+
+		var reward float64
+		var done bool
+		obs, reward, done = env.Step(action)
+		if reporter != nil && reporter.Err() != nil {
+			return total_reward, steps, reporter.Err()
+		}
+		if episode != nil {
+			episode.Rewards = append(episode.Rewards, reward)
+		}
+		total_reward += reward
+		steps++
+		if done {
+			break
+		}
+	}
+	return total_reward, steps, nil
+}
+
+// The evaluator of organisms by running episodes in environment. The fitness of organism is total reward of episode
+// and organism considered as solver if it reached SolvedReward. It implements OrganismEvaluator, thus repeated episodes
+// can be aggregated with EvaluateNoisyFitness.
+type EnvironmentEvaluator struct {
+	// The factory of environment, invoked per evaluation to allow concurrent evaluation of organisms
+	NewEnvironment func() Environment
+	// The runner of episodes
+	Runner         EpisodeRunner
+	// The minimal total reward of episode to consider organism as solver
+	SolvedReward   float64
+}
+
+// Evaluates organism running one episode in new environment
+func (e *EnvironmentEvaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (fitness float64, solved bool, err error) {
+	if e.NewEnvironment == nil {
+		return 0.0, false, errors.New("Environment factory is not set")
+	}
+	phenotype, err := org.Phenotype()
+	if err != nil {
+		return 0.0, false, err
+	}
+	reward, steps, err := e.Runner.RunEpisode(phenotype, e.NewEnvironment())
+	if err != nil {
+		return 0.0, false, err
+	}
+	neat.DebugLog(fmt.Sprintf("Organism [%d] received reward: %f in %d steps\n", org.Genotype.Id, reward, steps))
+	return reward, reward >= e.SolvedReward, nil
+}
+
+// Records one episode of organism in new environment, which can be replayed later against the same or other genome,
+// e.g. for regression testing of champion after code or configuration changes
+func (e *EnvironmentEvaluator) RecordEpisode(org *genetics.Organism) (*Episode, error) {
+	if e.NewEnvironment == nil {
+		return nil, errors.New("Environment factory is not set")
+	}
+	phenotype, err := org.Phenotype()
+	if err != nil {
+		return nil, err
+	}
+	return e.Runner.RecordEpisode(phenotype, e.NewEnvironment())
+}
@@ -0,0 +1,178 @@
+package experiments
+
+import (
+	"testing"
+	"errors"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The environment rewarding the first action value at each step, the episode is done after given number of steps
+type rewardEnvironment struct {
+	length  int
+	steps   int
+	resets  int
+	actions [][]float64
+}
+
+func (e *rewardEnvironment) Reset() []float64 {
+	e.steps = 0
+	e.resets++
+	return []float64{0.5, 1.0}
+}
+
+func (e *rewardEnvironment) Step(action []float64) ([]float64, float64, bool) {
+	e.steps++
+	e.actions = append(e.actions, append([]float64(nil), action...))
+	return []float64{float64(e.steps), 1.0}, action[0], e.steps >= e.length
+}
+
+func buildEnvironmentOrganism(t *testing.T) *genetics.Organism {
+	genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	org, err := genetics.NewOrganism(0.0, genome, 1)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	return org
+}
+
+func TestEpisodeRunner_RunEpisode(t *testing.T) {
+	org := buildEnvironmentOrganism(t)
+	if org == nil {
+		return
+	}
+	net, err := org.Phenotype()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// runs until environment is done
+	env := &rewardEnvironment{length:5}
+	reward, steps, err := EpisodeRunner{}.RunEpisode(net, env)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if steps != 5 {
+		t.Error("steps != 5", steps)
+	}
+	if env.resets != 1 {
+		t.Error("Environment should be reset once", env.resets)
+	}
+	expected := 0.0
+	for _, action := range env.actions {
+		if len(action) != 1 {
+			t.Error("Wrong action size", len(action))
+			return
+		}
+		expected += action[0]
+	}
+	if reward != expected {
+		t.Error("Wrong total reward", reward, expected)
+	}
+
+	// the episode is limited by the maximal number of steps
+	env = &rewardEnvironment{length:5}
+	if _, steps, err = (EpisodeRunner{MaxSteps:3}).RunEpisode(net, env); err != nil {
+		t.Error(err)
+	} else if steps != 3 {
+		t.Error("steps != 3", steps)
+	}
+}
+
+func TestEnvironmentEvaluator_OrganismEvaluate(t *testing.T) {
+	org := buildEnvironmentOrganism(t)
+	if org == nil {
+		return
+	}
+	context := &neat.NeatContext{FitnessEvalRepeats:3}
+	envs := make([]*rewardEnvironment, 0)
+	evaluator := &EnvironmentEvaluator{
+		NewEnvironment:func() Environment {
+			env := &rewardEnvironment{length:4}
+			envs = append(envs, env)
+			return env
+		},
+		SolvedReward:0.0,
+	}
+	fitness, solved, err := EvaluateNoisyFitness(org, evaluator, context)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(envs) != 3 {
+		t.Error("New environment expected per evaluation", len(envs))
+	}
+	if !solved {
+		t.Error("Organism should be solver")
+	}
+	// the network is deterministic and flushed before each episode
+	for _, f := range fitness {
+		if f != fitness[0] {
+			t.Error("The same reward expected for each episode", fitness)
+		}
+	}
+	if org.Fitness != fitness[0] {
+		t.Error("Wrong organism fitness", org.Fitness, fitness[0])
+	}
+
+	// not solved
+	evaluator.SolvedReward = 5.0
+	if _, solved, err = evaluator.OrganismEvaluate(org, context); err != nil {
+		t.Error(err)
+	} else if solved {
+		t.Error("Organism should not be solver")
+	}
+
+	// no environment factory
+	evaluator.NewEnvironment = nil
+	if _, _, err = evaluator.OrganismEvaluate(org, context); err == nil {
+		t.Error("Error expected for missing environment factory")
+	}
+}
+
+// The environment which fails after given number of steps
+type failingEnvironment struct {
+	rewardEnvironment
+	fail_at int
+	err     error
+}
+
+func (e *failingEnvironment) Step(action []float64) ([]float64, float64, bool) {
+	obs, reward, done := e.rewardEnvironment.Step(action)
+	if e.steps >= e.fail_at {
+		e.err = errors.New("simulation failed")
+		return nil, 0.0, true
+	}
+	return obs, reward, done
+}
+
+func (e *failingEnvironment) Err() error {
+	return e.err
+}
+
+func TestEpisodeRunner_RunEpisode_error(t *testing.T) {
+	org := buildEnvironmentOrganism(t)
+	if org == nil {
+		return
+	}
+	net, err := org.Phenotype()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	env := &failingEnvironment{rewardEnvironment:rewardEnvironment{length:5}, fail_at:2}
+	_, steps, err := EpisodeRunner{}.RunEpisode(net, env)
+	if err == nil || err != env.err {
+		t.Error("Environment error expected", err)
+	}
+	if steps != 1 {
+		t.Error("steps != 1", steps)
+	}
+}
@@ -0,0 +1,108 @@
+package experiments
+
+import (
+	"fmt"
+	"sync"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The statistics of evaluation cache
+type EvaluationCacheStats struct {
+	// The number of evaluations answered from cache
+	Hits   int
+	// The number of evaluations passed to the wrapped evaluator
+	Misses int
+	// The number of cached results
+	Size   int
+}
+
+// Returns the fraction of evaluations answered from cache
+func (s EvaluationCacheStats) HitRate() float64 {
+	if s.Hits + s.Misses == 0 {
+		return 0.0
+	}
+	return float64(s.Hits) / float64(s.Hits + s.Misses)
+}
+
+// The result of organism evaluation stored in cache
+type cachedEvaluation struct {
+	fitness float64
+	solved  bool
+}
+
+// The organism evaluator which caches results of the wrapped evaluator keyed by genome hash (see
+// genetics.Genome.Hash), thus organisms with the same genome, e.g. champion clones, are not evaluated again during
+// the trial. It should wrap only deterministic evaluators, as cached fitness is returned for all repeated evaluations.
+// The failed evaluations are not cached. It implements OrganismEvaluator and TrialRunObserver, the cache is cleared at
+// the start of each trial.
+type CachingEvaluator struct {
+	// The wrapped evaluator
+	Evaluator OrganismEvaluator
+
+	// The cached results by genome hash
+	cache     map[uint64]cachedEvaluation
+	// The number of cache hits and misses
+	hits      int
+	misses    int
+	// The mutex to guard against concurrent evaluations
+	mutex     sync.Mutex
+}
+
+// Creates new caching evaluator wrapping provided one
+func NewCachingEvaluator(evaluator OrganismEvaluator) *CachingEvaluator {
+	return &CachingEvaluator{Evaluator:evaluator, cache:make(map[uint64]cachedEvaluation)}
+}
+
+// Evaluates organism by the wrapped evaluator or returns cached result if organism with the same genome hash was
+// already evaluated
+func (e *CachingEvaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (float64, bool, error) {
+	hash := org.Genotype.Hash()
+	e.mutex.Lock()
+	cached, ok := e.cache[hash]
+	if ok {
+		e.hits++
+	} else {
+		e.misses++
+	}
+	e.mutex.Unlock()
+	if ok {
+		return cached.fitness, cached.solved, nil
+	}
+
+	fitness, solved, err := e.Evaluator.OrganismEvaluate(org, context)
+	if err != nil {
+		return fitness, solved, err
+	}
+	e.mutex.Lock()
+	e.cache[hash] = cachedEvaluation{fitness:fitness, solved:solved}
+	e.mutex.Unlock()
+	return fitness, solved, nil
+}
+
+// Clears the cache at the start of new trial and reports statistics of the previous one
+func (e *CachingEvaluator) TrialRunStarted(trial *Trial) {
+	if stats := e.Stats(); stats.Hits + stats.Misses > 0 {
+		neat.InfoLog(fmt.Sprintf("Evaluation cache: %d hits, %d misses, hit rate: %.3f\n", stats.Hits, stats.Misses,
+			stats.HitRate()))
+	}
+	e.Reset()
+	if observer, ok := e.Evaluator.(TrialRunObserver); ok {
+		observer.TrialRunStarted(trial)
+	}
+}
+
+// Clears cached results and statistics
+func (e *CachingEvaluator) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.cache = make(map[uint64]cachedEvaluation)
+	e.hits, e.misses = 0, 0
+}
+
+// Returns the statistics of cache since the last reset
+func (e *CachingEvaluator) Stats() EvaluationCacheStats {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return EvaluationCacheStats{Hits:e.hits, Misses:e.misses, Size:len(e.cache)}
+}
@@ -0,0 +1,93 @@
+package experiments
+
+import (
+	"testing"
+	"errors"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The evaluator counting evaluations, the fitness is the number of evaluations done so far
+type countingEvaluator struct {
+	evaluations int
+	trials      int
+	fail        bool
+}
+
+func (e *countingEvaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (float64, bool, error) {
+	if e.fail {
+		return 0, false, errors.New("evaluation failed")
+	}
+	e.evaluations++
+	return float64(e.evaluations), e.evaluations == 1, nil
+}
+
+func (e *countingEvaluator) TrialRunStarted(trial *Trial) {
+	e.trials++
+}
+
+func TestCachingEvaluator_OrganismEvaluate(t *testing.T) {
+	rand.Seed(42)
+	org := buildEnvironmentOrganism(t)
+	other := buildEnvironmentOrganism(t)
+	if org == nil || other == nil {
+		return
+	}
+	clone_genome, err := org.Genotype.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	clone, _ := genetics.NewOrganism(0.0, clone_genome, 2)
+
+	counter := &countingEvaluator{}
+	evaluator := NewCachingEvaluator(counter)
+	conf := &neat.NeatContext{}
+	fitness, solved, err := evaluator.OrganismEvaluate(org, conf)
+	if err != nil || fitness != 1 || !solved {
+		t.Error("Wrong result of evaluation", fitness, solved, err)
+	}
+	// the clone is not evaluated again
+	if fitness, solved, err = evaluator.OrganismEvaluate(clone, conf); err != nil || fitness != 1 || !solved {
+		t.Error("The cached result expected for clone", fitness, solved, err)
+	}
+	if fitness, _, _ = evaluator.OrganismEvaluate(other, conf); fitness != 2 || counter.evaluations != 2 {
+		t.Error("The different genome should be evaluated", fitness, counter.evaluations)
+	}
+	stats := evaluator.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Size != 2 || stats.HitRate() != 1.0 / 3.0 {
+		t.Error("Wrong cache statistics", stats)
+	}
+
+	// the cache is cleared at the start of trial
+	evaluator.TrialRunStarted(&Trial{Id:1})
+	if stats = evaluator.Stats(); stats.Hits != 0 || stats.Misses != 0 || stats.Size != 0 || counter.trials != 1 {
+		t.Error("The cache should be cleared", stats, counter.trials)
+	}
+	if _, err = EvaluateOrganism(clone, evaluator, conf); err != nil || clone.Fitness != 3 {
+		t.Error("The clone should be evaluated in new trial", clone.Fitness, err)
+	}
+}
+
+func TestCachingEvaluator_OrganismEvaluate_error(t *testing.T) {
+	org := buildEnvironmentOrganism(t)
+	if org == nil {
+		return
+	}
+	counter := &countingEvaluator{fail:true}
+	evaluator := NewCachingEvaluator(counter)
+	if _, _, err := evaluator.OrganismEvaluate(org, &neat.NeatContext{}); err == nil {
+		t.Error("Error expected")
+	}
+	counter.fail = false
+	if fitness, _, err := evaluator.OrganismEvaluate(org, &neat.NeatContext{}); err != nil || fitness != 1 {
+		t.Error("The failed evaluation should not be cached", fitness, err)
+	}
+	if stats := evaluator.Stats(); stats.Misses != 2 || stats.Size != 1 {
+		t.Error("Wrong cache statistics", stats)
+	}
+	if rate := (EvaluationCacheStats{}).HitRate(); rate != 0 {
+		t.Error("Zero hit rate expected for empty statistics", rate)
+	}
+}
@@ -0,0 +1,129 @@
+package experiments
+
+import (
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The policy to handle errors of organism evaluation, e.g. simulation crash or invalid network outputs
+type EvaluationErrorPolicy int
+
+// The supported evaluation error policies
+const (
+	// The evaluation error aborts the whole run
+	AbortOnEvaluationError EvaluationErrorPolicy = iota
+	// The failed organism gets minimal fitness and the evaluation error is attached to it, thus it will be
+	// eliminated by selection
+	MinimalFitnessOnEvaluationError
+	// The failed evaluation is retried up to context.EvalErrorRetries times and the run is aborted if all retries
+	// failed
+	RetryOnEvaluationError
+)
+
+// The fitness assigned to organisms which failed evaluation if objective is maximized, the maximal finite value is
+// assigned if objective is minimized (see genetics.ObjectiveDirection.RejectedFitness)
+const MinimalEvaluationFitness = 0.0
+
+// The default number of evaluation retries if not set in context
+const defaultEvaluationRetries = 1
+
+// The error returned when evaluation produced fitness which is not a number or infinite
+var ErrInvalidFitness = errors.New("organism evaluation produced invalid fitness value")
+
+// Evaluates organism with provided evaluation function applying evaluation error policy configured by context. The
+// evaluate function should set fitness of organism and return true if organism solved the task. The NaN or infinite
+// fitness set by evaluation is regarded as evaluation error. The evaluation errors caused by values which are not
+// finite numbers are not retried if reject or error policy for non-finite values configured by context, the organism
+// gets minimal fitness or run aborted respectively. The organisms survived steady-state epoch unchanged are not
+// evaluated again and keep their fitness. Returns error only if the run should be aborted.
+func EvaluateWithErrorPolicy(org *genetics.Organism, evaluate func(org *genetics.Organism) (bool, error),
+context *neat.NeatContext) (bool, error) {
+	if org.IsSurvivor() {
+		return org.IsWinner, nil
+	}
+	org.EvaluationError = nil
+	non_finite := network.NonFinitePolicy(context.NonFinitePolicy)
+	if phenotype, err := org.Phenotype(); err == nil {
+		phenotype.NonFinitePolicy = non_finite
+	}
+	policy := EvaluationErrorPolicy(context.EvalErrorPolicy)
+	attempts := 1
+	switch policy {
+	case AbortOnEvaluationError, MinimalFitnessOnEvaluationError:
+	case RetryOnEvaluationError:
+		retries := context.EvalErrorRetries
+		if retries <= 0 {
+			retries = defaultEvaluationRetries
+		}
+		attempts += retries
+	default:
+		return false, errors.New(fmt.Sprintf("Unsupported evaluation error policy: %d", context.EvalErrorPolicy))
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		var solved bool
+		if solved, err = evaluate(org); err == nil {
+			if math.IsNaN(org.Fitness) || math.IsInf(org.Fitness, 0) {
+				err = ErrInvalidFitness
+			} else {
+				return solved, nil
+			}
+		}
+		neat.WarnLog(fmt.Sprintf("Evaluation of organism [%d] failed at attempt: %d, reason: %s\n",
+			org.Genotype.Id, i + 1, err))
+		if (non_finite == network.RejectNonFinite || non_finite == network.ErrorOnNonFinite) && isNonFiniteError(err) {
+			// the non-finite values are deterministic, no need to retry
+			break
+		}
+	}
+
+	if policy == MinimalFitnessOnEvaluationError || non_finite == network.RejectNonFinite && isNonFiniteError(err) {
+		org.Fitness = genetics.ObjectiveDirection(context.ObjectiveDirection).RejectedFitness()
+		org.IsWinner = false
+		org.EvaluationError = err
+		return false, nil
+	}
+	return false, errors.New(fmt.Sprintf("Evaluation of organism [%d] failed, reason: %s", org.Genotype.Id, err))
+}
+
+// Checks whether evaluation error caused by values which are not finite numbers
+func isNonFiniteError(err error) bool {
+	return err == ErrInvalidFitness || err == network.NetErrNonFiniteActivation
+}
+
+// Evaluates organism with given evaluator like EvaluateNoisyFitness applying evaluation error policy configured
+// by context. Returns true if organism solved the task.
+func EvaluateOrganism(org *genetics.Organism, evaluator OrganismEvaluator, context *neat.NeatContext) (bool, error) {
+	return EvaluateWithErrorPolicy(org, func(org *genetics.Organism) (bool, error) {
+		_, solved, err := EvaluateNoisyFitness(org, evaluator, context)
+		return solved, err
+	}, context)
+}
+
+// Returns the number of organisms in population which are evaluated in generation, i.e. which are not survivors of
+// steady-state epoch
+func countEvaluatedOrganisms(pop *genetics.Population) int {
+	count := 0
+	for _, org := range pop.Organisms {
+		if !org.IsSurvivor() {
+			count++
+		}
+	}
+	return count
+}
+
+// Returns the number of organisms in population which failed evaluation and got minimal fitness
+func countEvaluationErrors(pop *genetics.Population) int {
+	count := 0
+	for _, org := range pop.Organisms {
+		if org.EvaluationError != nil {
+			count++
+		}
+	}
+	return count
+}
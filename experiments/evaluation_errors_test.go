@@ -0,0 +1,265 @@
+package experiments
+
+import (
+	"testing"
+	"errors"
+	"math"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The evaluation function failing given number of times before successful evaluation
+type failingEvaluation struct {
+	failures int
+	calls    int
+	fitness  float64
+}
+
+func (e *failingEvaluation) evaluate(org *genetics.Organism) (bool, error) {
+	e.calls++
+	if e.calls <= e.failures {
+		return false, errors.New("simulation crashed")
+	}
+	org.Fitness = e.fitness
+	return true, nil
+}
+
+func TestEvaluateWithErrorPolicy_abort(t *testing.T) {
+	org := &genetics.Organism{Genotype:buildTestGenome(1)}
+	context := neat.NeatContext{EvalErrorPolicy:int(AbortOnEvaluationError)}
+
+	eval := &failingEvaluation{fitness:5.0}
+	if solved, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context); err != nil || !solved {
+		t.Error("Successful evaluation expected", solved, err)
+	}
+	if org.Fitness != 5.0 || org.EvaluationError != nil {
+		t.Error("Wrong evaluation result", org.Fitness, org.EvaluationError)
+	}
+
+	eval = &failingEvaluation{failures:1, fitness:5.0}
+	if _, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context); err == nil {
+		t.Error("Error expected")
+	}
+	if eval.calls != 1 {
+		t.Error("Evaluation should not be retried", eval.calls)
+	}
+
+	context.EvalErrorPolicy = 100
+	if _, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context); err == nil {
+		t.Error("Error expected for unsupported policy")
+	}
+}
+
+func TestEvaluateWithErrorPolicy_minimalFitness(t *testing.T) {
+	org := &genetics.Organism{Genotype:buildTestGenome(1), Fitness:10.0, IsWinner:true}
+	context := neat.NeatContext{EvalErrorPolicy:int(MinimalFitnessOnEvaluationError)}
+
+	eval := &failingEvaluation{failures:1, fitness:5.0}
+	solved, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context)
+	if err != nil || solved {
+		t.Error("Failed evaluation should be tolerated", solved, err)
+	}
+	if org.Fitness != MinimalEvaluationFitness || org.IsWinner {
+		t.Error("Minimal fitness expected", org.Fitness, org.IsWinner)
+	}
+	if org.EvaluationError == nil {
+		t.Error("Evaluation error should be attached to organism")
+	}
+
+	// the error is cleared by successful evaluation
+	if _, err = EvaluateWithErrorPolicy(org, eval.evaluate, &context); err != nil || org.EvaluationError != nil {
+		t.Error("Evaluation error should be cleared", err, org.EvaluationError)
+	}
+
+	// NaN fitness
+	eval = &failingEvaluation{fitness:math.NaN()}
+	if _, err = EvaluateWithErrorPolicy(org, eval.evaluate, &context); err != nil {
+		t.Error(err)
+	}
+	if org.EvaluationError != ErrInvalidFitness || org.Fitness != MinimalEvaluationFitness {
+		t.Error("Invalid fitness should be regarded as evaluation error", org.EvaluationError, org.Fitness)
+	}
+
+	// the worst fitness is assigned if objective is minimized
+	context.ObjectiveDirection = int(genetics.MinimizeObjective)
+	eval = &failingEvaluation{failures:1, fitness:5.0}
+	if _, err = EvaluateWithErrorPolicy(org, eval.evaluate, &context); err != nil {
+		t.Error(err)
+	}
+	if org.Fitness != network.MaxFiniteValue {
+		t.Error("Maximal fitness expected for minimized objective", org.Fitness)
+	}
+}
+
+func TestEvaluateWithErrorPolicy_retry(t *testing.T) {
+	org := &genetics.Organism{Genotype:buildTestGenome(1)}
+	context := neat.NeatContext{EvalErrorPolicy:int(RetryOnEvaluationError), EvalErrorRetries:2}
+
+	eval := &failingEvaluation{failures:2, fitness:5.0}
+	if solved, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context); err != nil || !solved {
+		t.Error("Evaluation should succeed after retries", solved, err)
+	}
+	if eval.calls != 3 || org.Fitness != 5.0 {
+		t.Error("Wrong number of evaluation attempts", eval.calls, org.Fitness)
+	}
+
+	eval = &failingEvaluation{failures:3, fitness:5.0}
+	if _, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context); err == nil {
+		t.Error("Error expected when all retries failed")
+	}
+	if eval.calls != 3 {
+		t.Error("Wrong number of evaluation attempts", eval.calls)
+	}
+}
+
+func TestEvaluateWithErrorPolicy_nonFinite(t *testing.T) {
+	org := &genetics.Organism{Genotype:buildTestGenome(1), Fitness:10.0}
+	context := neat.NeatContext{EvalErrorPolicy:int(RetryOnEvaluationError), EvalErrorRetries:2,
+		NonFinitePolicy:int(network.RejectNonFinite)}
+
+	calls := 0
+	activation_failure := func(org *genetics.Organism) (bool, error) {
+		calls++
+		return false, network.NetErrNonFiniteActivation
+	}
+	if solved, err := EvaluateWithErrorPolicy(org, activation_failure, &context); err != nil || solved {
+		t.Error("Organism should be rejected without error", solved, err)
+	}
+	if calls != 1 {
+		t.Error("Non-finite activation should not be retried", calls)
+	}
+	if org.Fitness != MinimalEvaluationFitness || org.EvaluationError != network.NetErrNonFiniteActivation {
+		t.Error("Rejected organism should get minimal fitness", org.Fitness, org.EvaluationError)
+	}
+	if phenotype, err := org.Phenotype(); err != nil {
+		t.Error(err)
+	} else if phenotype.NonFinitePolicy != network.RejectNonFinite {
+		t.Error("Phenotype should use policy from context", phenotype.NonFinitePolicy)
+	}
+
+	eval := &failingEvaluation{fitness:math.NaN()}
+	context.NonFinitePolicy = int(network.ErrorOnNonFinite)
+	if _, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context); err == nil {
+		t.Error("Error expected")
+	}
+	if eval.calls != 1 {
+		t.Error("NaN fitness should not be retried", eval.calls)
+	}
+
+	// with clamp policy NaN fitness handled by evaluation error policy
+	eval = &failingEvaluation{fitness:math.NaN()}
+	context.NonFinitePolicy = int(network.ClampNonFinite)
+	if _, err := EvaluateWithErrorPolicy(org, eval.evaluate, &context); err == nil {
+		t.Error("Error expected")
+	}
+	if eval.calls != 3 {
+		t.Error("Wrong number of evaluation attempts", eval.calls)
+	}
+}
+
+func TestEvaluateWithErrorPolicy_survivor(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.ReplacementFraction = 0.25
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pop, err := genetics.NewPopulation(start_genome, context)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, org := range pop.Organisms {
+		org.Fitness = rand.Float64()
+	}
+	ex := genetics.SteadyStatePopulationEpochExecutor{}
+	if err = ex.NextEpoch(1, pop, context); err != nil {
+		t.Error(err)
+		return
+	}
+
+	eval := &failingEvaluation{fitness:5.0}
+	for _, org := range pop.Organisms {
+		fitness := org.Fitness
+		if _, err = EvaluateWithErrorPolicy(org, eval.evaluate, context); err != nil {
+			t.Error(err)
+			return
+		}
+		if org.IsSurvivor() && org.Fitness != fitness {
+			t.Error("Survivor should keep its fitness", org.Fitness, fitness)
+		}
+	}
+	if eval.calls != 5 {
+		t.Error("Only offspring should be evaluated", eval.calls)
+	}
+	if evaluated := countEvaluatedOrganisms(pop); evaluated != 5 {
+		t.Error("Wrong number of evaluated organisms", evaluated)
+	}
+}
+
+func TestEvaluateOrganism(t *testing.T) {
+	org := &genetics.Organism{Genotype:buildTestGenome(1)}
+	evaluator := &sequenceEvaluator{fitness:[]float64{1.0, 3.0}, threshold:1.0}
+	context := neat.NeatContext{FitnessEvalRepeats:2}
+	solved, err := EvaluateOrganism(org, evaluator, &context)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !solved || org.Fitness != 2.0 {
+		t.Error("Wrong evaluation result", solved, org.Fitness)
+	}
+}
+
+// The generation evaluator which fails evaluation of every second organism
+type failingGenerationEvaluator struct{}
+
+func (e *failingGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, context *neat.NeatContext) error {
+	for i, org := range pop.Organisms {
+		fail := i % 2 == 0
+		_, err := EvaluateWithErrorPolicy(org, func(org *genetics.Organism) (bool, error) {
+			if fail {
+				return false, errors.New("simulation crashed")
+			}
+			org.Fitness = rand.Float64()
+			return false, nil
+		}, context)
+		if err != nil {
+			return err
+		}
+	}
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
+
+func TestExperiment_Execute_evaluationErrors(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	context.EvalErrorPolicy = int(MinimalFitnessOnEvaluationError)
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, &failingGenerationEvaluator{}); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, gen := range experiment.Trials[0].Generations {
+		if gen.EvaluationErrors != context.PopSize / 2 {
+			t.Error("Wrong number of evaluation errors", gen.EvaluationErrors)
+		}
+	}
+
+	// abort run
+	context.EvalErrorPolicy = int(AbortOnEvaluationError)
+	if err = experiment.Execute(context, start_genome, &failingGenerationEvaluator{}); err == nil {
+		t.Error("Error expected")
+	}
+}
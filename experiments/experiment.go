@@ -2,8 +2,8 @@ package experiments
 
 import (
 	"time"
-	"sort"
 	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
 	"io"
 	"encoding/gob"
 	"fmt"
@@ -20,6 +20,19 @@ type Experiment struct {
 	// It is used to normalize fitness score value used in efficiency score calculation. If this value
 	// is not set, than fitness score will not be normalized during efficiency score estimation.
 	MaxFintessScore float64
+
+	// The optional exporter of per-generation metrics. If set, it will be updated after each generation evaluated
+	// and each epoch executed.
+	Metrics         *MetricsExporter
+	// The optional observers of evaluated generations, e.g. the live monitoring dashboard
+	Observers       []GenerationObserver
+	// If true than genetic diversity of population will be collected after each generation evaluated. Note that it
+	// requires pairwise genomes comparison which may be expensive for large populations.
+	CollectDiversity bool
+	// The optional fixed preprocessor of sensor values attached in front of the phenotypes of all organisms before
+	// each generation evaluated, thus evaluators can load raw sensor values into networks. It is encoded with
+	// experiment data.
+	InputPreprocessor *network.InputPreprocessor
 }
 
 // Calculates average duration of experiment's trial
@@ -53,6 +66,25 @@ func (e *Experiment) AvgGenerationsPerTrial() float64 {
 	return total / float64(len(e.Trials))
 }
 
+// Calculates average number of organism evaluations per trial during this experiment. The results of neuroevolution
+// methods are usually compared by the number of evaluations rather than the number of generations.
+func (e *Experiment) AvgEvaluationsPerTrial() float64 {
+	total := 0.0
+	for _, t := range e.Trials {
+		total += float64(t.Evaluations())
+	}
+	return total / float64(len(e.Trials))
+}
+
+// Calculates average number of network activation steps per trial during this experiment
+func (e *Experiment) AvgActivationsPerTrial() float64 {
+	total := 0.0
+	for _, t := range e.Trials {
+		total += float64(t.Activations())
+	}
+	return total / float64(len(e.Trials))
+}
+
 // Returns time of last trial's execution
 func (e *Experiment) LastExecuted() time.Time {
 	var u time.Time
@@ -79,14 +111,34 @@ func (e *Experiment) BestOrganism(onlySolvers bool) (*genetics.Organism, int, bo
 
 	}
 	if len(orgs) > 0 {
-		sort.Sort(sort.Reverse(orgs))
-		return orgs[0], orgs[0].Flag, true
+		best := e.objectiveDirection().BestOrganism(orgs)
+		return best, best.Flag, true
 	} else {
 		return nil, -1, false
 	}
 
 }
 
+// Finds the epoch among all trials which best organism has the highest fitness against validation data held out from
+// training. Returns false if no epoch was validated.
+func (e *Experiment) BestValidatedGeneration() (*Generation, bool) {
+	var best *Generation
+	for _, t := range e.Trials {
+		if epoch, found := t.BestValidatedGeneration(); found && (best == nil || epoch.ValidationFitness > best.ValidationFitness) {
+			best = epoch
+		}
+	}
+	return best, best != nil
+}
+
+// Returns the direction of fitness objective of trials in this experiment, all trials are assumed to use the same one
+func (e *Experiment) objectiveDirection() genetics.ObjectiveDirection {
+	if len(e.Trials) == 0 {
+		return genetics.MaximizeObjective
+	}
+	return e.Trials[0].ObjectiveDirection
+}
+
 func (e *Experiment) Solved() bool {
 	for _, t := range e.Trials {
 		if t.Solved() {
@@ -123,7 +175,7 @@ func (e *Experiment) BestComplexity() Floats {
 	var x Floats = make([]float64, len(e.Trials))
 	for i, t := range e.Trials {
 		if org, ok := t.BestOrganism(false); ok {
-			x[i] = float64(org.Phenotype.Complexity())
+			x[i] = float64(org.Complexity())
 		}
 	}
 	return x
@@ -204,7 +256,7 @@ func (ex *Experiment) EfficiencyScore() float64 {
 					t.Winner()
 				}
 
-				mean_complexity += float64(t.WinnerGeneration.Best.Phenotype.Complexity())
+				mean_complexity += float64(t.WinnerGeneration.Best.Complexity())
 				mean_fitness += t.WinnerGeneration.Best.Fitness
 
 				count++
@@ -234,16 +286,32 @@ func (ex *Experiment) PrintStatistics() {
 	fmt.Printf("\nSolved %d trials from %d, success rate: %f\n", ex.TrialsSolved(), len(ex.Trials), ex.SuccessRate())
 	fmt.Printf("Average\n\tTrial duration:\t\t%s\n\tEpoch duration:\t\t%s\n\tGenerations/trial:\t%.1f\n",
 		ex.AvgTrialDuration(), ex.AvgEpochDuration(), ex.AvgGenerationsPerTrial())
+	fmt.Printf("\tEvaluations/trial:\t%.1f\n\tActivations/trial:\t%.1f\n",
+		ex.AvgEvaluationsPerTrial(), ex.AvgActivationsPerTrial())
 	// Print absolute champion statistics
 	if org, trid, found := ex.BestOrganism(true); found {
 		nodes, genes, evals, divers := ex.Trials[trid].Winner()
 		fmt.Printf("\nChampion found in %d trial run\n\tWinner Nodes:\t\t%d\n\tWinner Genes:\t\t%d\n\tWinner Evals:\t\t%d\n\n\tDiversity:\t\t%d",
 			trid, nodes, genes, evals, divers)
 		fmt.Printf("\n\tComplexity:\t\t%d\n\tAge:\t\t\t%d\n\tFitness:\t\t%f\n",
-			org.Phenotype.Complexity(), org.Species.Age, org.Fitness)
+			org.Complexity(), org.Species.Age, org.Fitness)
 	} else {
 		fmt.Println("\nNo winner found in the experiment!!!")
 	}
+	// Print winners of the final tournaments
+	for _, t := range ex.Trials {
+		if t.Tournament == nil || t.Tournament.Winner() == nil {
+			continue
+		}
+		winner := t.Tournament.Winner()
+		fmt.Printf("\nTournament winner of %d trial run among %d species champions\n\tSolved:\t\t\t%t\n\tTournament fitness:\t%f\n\tGeneration fitness:\t%f\n",
+			t.Id, len(t.Tournament.Contenders), winner.Solved, winner.TournamentFitness, winner.GenerationFitness)
+	}
+	// Print champion selected by validation fitness
+	if epoch, found := ex.BestValidatedGeneration(); found {
+		fmt.Printf("\nValidation champion found in %d trial run, %d generation\n\tComplexity:\t\t%d\n\tFitness:\t\t%f\n\tValidation fitness:\t%f\n",
+			epoch.TrialId, epoch.Id, epoch.Best.Complexity(), epoch.Best.Fitness, epoch.ValidationFitness)
+	}
 
 	// Print average winner statistics
 	mean_complexity, mean_diversity, mean_age, mean_fitness := 0.0, 0.0, 0.0, 0.0
@@ -261,7 +329,7 @@ func (ex *Experiment) PrintStatistics() {
 				avg_divers += float64(diversity)
 				avg_generations += float64(len(t.Generations))
 
-				mean_complexity += float64(t.WinnerGeneration.Best.Phenotype.Complexity())
+				mean_complexity += float64(t.WinnerGeneration.Best.Complexity())
 				mean_age += float64(t.WinnerGeneration.Best.Species.Age)
 				mean_fitness += t.WinnerGeneration.Best.Fitness
 
@@ -326,6 +394,14 @@ func (ex *Experiment) Encode(enc *gob.Encoder) error {
 			return err
 		}
 	}
+
+	// encode optional input preprocessor
+	if err == nil {
+		err = enc.Encode(ex.InputPreprocessor != nil)
+	}
+	if err == nil && ex.InputPreprocessor != nil {
+		err = enc.Encode(ex.InputPreprocessor)
+	}
 	return err
 }
 
@@ -353,9 +429,47 @@ func (ex *Experiment) Decode(dec *gob.Decoder) error {
 		err = trial.Decode(dec)
 		ex.Trials[i] = trial
 	}
+	if err != nil {
+		return err
+	}
+
+	// decode optional input preprocessor
+	var has_preprocessor bool
+	if err = dec.Decode(&has_preprocessor); err == io.EOF {
+		// the experiment data encoded without input preprocessor
+		return nil
+	} else if err == nil && has_preprocessor {
+		ex.InputPreprocessor = &network.InputPreprocessor{}
+		err = dec.Decode(ex.InputPreprocessor)
+	}
 	return err
 }
 
+// Attaches input preprocessor of this experiment to the phenotypes of all organisms in population including preserved
+// champion if any
+func (ex *Experiment) attachInputPreprocessor(pop *genetics.Population) error {
+	if ex.InputPreprocessor == nil {
+		return nil
+	}
+	organisms := pop.Organisms
+	if champion := pop.PreservedChampion(); champion != nil {
+		organisms = append(organisms[:len(organisms):len(organisms)], champion)
+	}
+	for _, org := range organisms {
+		phenotype, err := org.Phenotype()
+		if err != nil {
+			return err
+		}
+		if phenotype.Preprocessor == ex.InputPreprocessor {
+			continue
+		}
+		if err = phenotype.SetPreprocessor(ex.InputPreprocessor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Experiments is a sortable list of experiments by execution time and Id
 type Experiments []Experiment
 
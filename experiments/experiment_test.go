@@ -3,6 +3,13 @@ package experiments
 import (
 	"testing"
 	"bytes"
+	"reflect"
+	"math/rand"
+	"encoding/gob"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
 )
 
 func TestExperiment_Write_Read(t *testing.T) {
@@ -44,3 +51,95 @@ func TestExperiment_Write_Read(t *testing.T) {
 		deepCompareTrials(&ex.Trials[i], &new_ex.Trials[i], t)
 	}
 }
+
+func TestExperiment_Write_Read_preprocessor(t *testing.T) {
+	prep, err := network.NewNormalizationPreprocessor([]float64{0.5, 1.0}, []float64{2.0, 4.0})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ex := Experiment{Id:1, Name:"Test Encode Decode", Trials:Trials{*buildTestTrial(1, 2)}, InputPreprocessor:prep}
+	var buff bytes.Buffer
+	if err = ex.Write(&buff); err != nil {
+		t.Error(err)
+		return
+	}
+	new_ex := Experiment{}
+	if err = new_ex.Read(&buff); err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(new_ex.InputPreprocessor, prep) {
+		t.Error("Wrong input preprocessor", new_ex.InputPreprocessor)
+	}
+
+	// the experiment data encoded without input preprocessor
+	buff.Reset()
+	enc := gob.NewEncoder(&buff)
+	for _, v := range []interface{}{ex.Id, ex.Name, 0} {
+		if err = enc.Encode(v); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	new_ex = Experiment{}
+	if err = new_ex.Read(&buff); err != nil {
+		t.Error(err)
+		return
+	}
+	if new_ex.InputPreprocessor != nil || new_ex.Name != ex.Name {
+		t.Error("No input preprocessor expected", new_ex.InputPreprocessor)
+	}
+}
+
+// The generation evaluator checking that input preprocessor is attached to the phenotypes of all organisms
+type preprocessedGenerationEvaluator struct {
+	randomGenerationEvaluator
+	preprocessor *network.InputPreprocessor
+	missing      int
+}
+
+func (e *preprocessedGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, context *neat.NeatContext) error {
+	for _, org := range pop.Organisms {
+		if phenotype, err := org.Phenotype(); err != nil {
+			return err
+		} else if phenotype.Preprocessor != e.preprocessor {
+			e.missing++
+		} else if err = phenotype.LoadSensors([]float64{1.0, 2.0, 3.0}); err != nil {
+			return err
+		}
+	}
+	return e.randomGenerationEvaluator.GenerationEvaluate(pop, epoch, context)
+}
+
+func TestExperiment_Execute_inputPreprocessor(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the feature extractor of three raw inputs into two network sensors
+	prep := &network.InputPreprocessor{Layers:[]network.PreprocessingLayer{{
+		Weights:[][]float64{{1.0, 1.0, 0.0}, {0.0, 1.0, 1.0}},
+		Biases:[]float64{0.0, 0.0},
+		Activation:utils.LinearActivation,
+	}}}
+	evaluator := &preprocessedGenerationEvaluator{preprocessor:prep}
+	experiment := Experiment{InputPreprocessor:prep}
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	if evaluator.missing != 0 {
+		t.Error("The input preprocessor is missing in phenotypes of organisms", evaluator.missing)
+	}
+
+	// the preprocessor not matching network sensors
+	experiment = Experiment{InputPreprocessor:&network.InputPreprocessor{Layers:[]network.PreprocessingLayer{{
+		Weights:[][]float64{{1.0}}, Biases:[]float64{0.0}, Activation:utils.LinearActivation}}}}
+	if err = experiment.Execute(context, start_genome, evaluator); err == nil {
+		t.Error("Error expected for wrong number of preprocessor outputs")
+	}
+}
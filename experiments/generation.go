@@ -3,11 +3,10 @@ package experiments
 import (
 	"time"
 	"github.com/yaricom/goNEAT/neat/genetics"
-	"math"
+	"github.com/yaricom/goNEAT/neat"
 	"encoding/gob"
 	"bytes"
 	"reflect"
-	"sort"
 )
 
 // The structure to represent execution results of one generation
@@ -22,6 +21,15 @@ type Generation struct {
 	Best        *genetics.Organism
 	// The flag to indicate whether experiment was solved in this epoch
 	Solved      bool
+	// The flag to indicate that evaluator requested to stop the trial in this epoch, e.g. when fitness of the best
+	// organism against validation data stopped improving. It is not stored by Encode.
+	Stopped     bool
+
+	// The fitness of the best organism against validation data held out from training, if Validated. It is not
+	// stored by Encode.
+	ValidationFitness float64
+	// The flag to indicate whether the best organism was evaluated against validation data in this epoch
+	Validated   bool
 
 	// The list of organisms fitness values per species in population
 	Fitness     Floats
@@ -32,6 +40,11 @@ type Generation struct {
 
 	// The number of species in population at the end of this epoch
 	Diversity   int
+	// The genetic diversity metrics of population if collected. It is not stored by Encode.
+	GeneticDiversity *genetics.PopulationDiversity
+	// The reproduction statistics of species in epoch following evaluation of this generation, if epoch was
+	// executed. It is not stored by Encode.
+	Reproduction []genetics.ReproductionStats
 
 	// The number of evaluations done before winner found
 	WinnerEvals int
@@ -40,32 +53,65 @@ type Generation struct {
 	// The numbers of genes (links) in winner genome or zero if not solved
 	WinnerGenes int
 
+	// The number of organism evaluations done in this generation. The generation evaluator may set it if organisms
+	// evaluated several times, otherwise it is the number of organisms in population which are not survivors of
+	// steady-state epoch.
+	Evaluations int
+	// The number of network activation steps done during evaluation of this generation
+	Activations int64
+	// The number of organisms which failed evaluation in this generation and got minimal fitness
+	EvaluationErrors int
+
 	// The ID of Trial this Generation was evaluated in
 	TrialId     int
 }
 
-// Collects statistics about given population
+// Collects statistics about given population assuming that fitness is maximized. If objective is minimized, the
+// statistics are collected again by experiment after evaluation of generation (see fillPopulationStatistics).
 func (epoch *Generation) FillPopulationStatistics(pop *genetics.Population) {
-	max_fitness := float64(math.MinInt64)
+	epoch.fillPopulationStatistics(pop, genetics.MaximizeObjective)
+}
+
+// Collects statistics about given population with the best organism found in given objective direction
+func (epoch *Generation) fillPopulationStatistics(pop *genetics.Population, direction genetics.ObjectiveDirection) {
+	var best *genetics.Organism
 	epoch.Diversity = len(pop.Species)
 	epoch.Age = make(Floats, epoch.Diversity)
 	epoch.Compexity = make(Floats, epoch.Diversity)
 	epoch.Fitness = make(Floats, epoch.Diversity)
 	for i, curr_species := range pop.Species {
 		epoch.Age[i] = float64(curr_species.Age)
-		epoch.Compexity[i] = float64(curr_species.Organisms[0].Phenotype.Complexity())
+		epoch.Compexity[i] = float64(curr_species.Organisms[0].Complexity())
 		epoch.Fitness[i] = curr_species.Organisms[0].Fitness
 
 		// find best organism in epoch if not solved
 		if !epoch.Solved {
-			// sort organisms from current species by fitness to have most fit first
-			sort.Sort(sort.Reverse(curr_species.Organisms))
-			if curr_species.Organisms[0].Fitness > max_fitness {
-				max_fitness = curr_species.Organisms[0].Fitness
-				epoch.Best = curr_species.Organisms[0]
+			champion := direction.BestOrganism(curr_species.Organisms)
+			if best == nil || direction.IsBetter(champion.Fitness, best.Fitness) {
+				best = champion
 			}
 		}
 	}
+	if best != nil {
+		epoch.Best = best
+	}
+}
+
+// Collects genetic diversity metrics of given population
+func (epoch *Generation) FillDiversityStatistics(pop *genetics.Population, context *neat.NeatContext) {
+	epoch.GeneticDiversity = pop.GeneticDiversity(context)
+}
+
+// Returns the number of offspring produced with each reproduction operator by all species in epoch following
+// evaluation of this generation, see genetics.ReproductionStats
+func (epoch *Generation) OperatorCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, stats := range epoch.Reproduction {
+		for op, count := range stats.Operators {
+			counts[op] += count
+		}
+	}
+	return counts
 }
 
 // Returns average fitness, age, and complexity among all organisms from population at the end of this epoch
@@ -88,6 +134,9 @@ func (epoch *Generation) Encode(enc *gob.Encoder) error {
 	err = enc.EncodeValue(reflect.ValueOf(epoch.WinnerEvals))
 	err = enc.EncodeValue(reflect.ValueOf(epoch.WinnerNodes))
 	err = enc.EncodeValue(reflect.ValueOf(epoch.WinnerGenes))
+	err = enc.EncodeValue(reflect.ValueOf(epoch.Evaluations))
+	err = enc.EncodeValue(reflect.ValueOf(epoch.Activations))
+	err = enc.EncodeValue(reflect.ValueOf(epoch.EvaluationErrors))
 
 	if err != nil {
 		return err
@@ -133,6 +182,9 @@ func (epoch *Generation) Decode(dec *gob.Decoder) error {
 	err = dec.Decode(&epoch.WinnerEvals)
 	err = dec.Decode(&epoch.WinnerNodes)
 	err = dec.Decode(&epoch.WinnerGenes)
+	err = dec.Decode(&epoch.Evaluations)
+	err = dec.Decode(&epoch.Activations)
+	err = dec.Decode(&epoch.EvaluationErrors)
 
 	if err != nil {
 		return err
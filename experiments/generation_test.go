@@ -5,6 +5,7 @@ import (
 	"encoding/gob"
 	"testing"
 	"time"
+	"math/rand"
 	"reflect"
 
 	"github.com/yaricom/goNEAT/neat"
@@ -42,6 +43,42 @@ func TestGeneration_Encode_Decode(t *testing.T) {
 	deepCompareGenerations(gen, dgen, t)
 }
 
+func TestGeneration_fillPopulationStatistics(t *testing.T) {
+	rand.Seed(42)
+	pop, err := genetics.NewPopulation(buildTestGenome(1), testExperimentContext())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	min, max := pop.Organisms[0], pop.Organisms[0]
+	for _, org := range pop.Organisms {
+		org.Fitness = rand.Float64()
+		if org.Fitness < min.Fitness {
+			min = org
+		}
+		if org.Fitness > max.Fitness {
+			max = org
+		}
+	}
+
+	epoch := Generation{}
+	epoch.FillPopulationStatistics(pop)
+	if epoch.Best != max || epoch.Diversity != len(pop.Species) {
+		t.Error("The organism with the highest fitness expected", epoch.Best.Fitness, max.Fitness)
+	}
+	epoch.fillPopulationStatistics(pop, genetics.MinimizeObjective)
+	if epoch.Best != min {
+		t.Error("The organism with the lowest fitness expected", epoch.Best.Fitness, min.Fitness)
+	}
+
+	// the winner is kept as the best
+	epoch.Solved, epoch.Best = true, max
+	epoch.fillPopulationStatistics(pop, genetics.MinimizeObjective)
+	if epoch.Best != max {
+		t.Error("The winner should be kept", epoch.Best.Fitness)
+	}
+}
+
 func deepCompareGenerations(first, second *Generation, t *testing.T) {
 	if first.Id != second.Id {
 		t.Error("first.Id != second.Id")
@@ -75,6 +112,15 @@ func deepCompareGenerations(first, second *Generation, t *testing.T) {
 	if first.WinnerGenes != second.WinnerGenes {
 		t.Error("first.WinnerGenes != second.WinnerGenes")
 	}
+	if first.Evaluations != second.Evaluations {
+		t.Error("first.Evaluations != second.Evaluations")
+	}
+	if first.Activations != second.Activations {
+		t.Error("first.Activations != second.Activations")
+	}
+	if first.EvaluationErrors != second.EvaluationErrors {
+		t.Error("first.EvaluationErrors != second.EvaluationErrors")
+	}
 
 	if first.Best.Fitness != second.Best.Fitness {
 		t.Error("first.Best.Fitness != second.Best.Fitness")
@@ -113,6 +159,9 @@ func buildTestGeneration(gen_id int, fitness float64) *Generation {
 	epoch.WinnerEvals = 12423
 	epoch.WinnerNodes = 7
 	epoch.WinnerGenes = 5
+	epoch.Evaluations = 150
+	epoch.Activations = 4500
+	epoch.EvaluationErrors = 2
 
 	genome := buildTestGenome(gen_id)
 	org := genetics.Organism{Fitness:fitness, Genotype:genome, Generation:gen_id}
@@ -143,3 +192,17 @@ func buildTestGenome(id int) *genetics.Genome {
 
 	return genetics.NewGenome(id, traits, nodes, genes)
 }
+
+func TestGeneration_OperatorCounts(t *testing.T) {
+	epoch := Generation{Reproduction:[]genetics.ReproductionStats{
+		{SpeciesId:1, Offspring:3, Operators:map[string]int{genetics.CloneReproduction:1, genetics.MutateOnlyReproduction:2}},
+		{SpeciesId:2, Offspring:1, Operators:map[string]int{genetics.MutateOnlyReproduction:1}},
+	}}
+	counts := epoch.OperatorCounts()
+	if len(counts) != 2 || counts[genetics.CloneReproduction] != 1 || counts[genetics.MutateOnlyReproduction] != 3 {
+		t.Error("Wrong operator counts", counts)
+	}
+	if counts = (&Generation{}).OperatorCounts(); len(counts) != 0 {
+		t.Error("No operators expected", counts)
+	}
+}
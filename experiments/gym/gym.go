@@ -0,0 +1,240 @@
+// The gym package provides bridge to Python Gymnasium environments, thus evolution can be run against environments
+// like CartPole or LunarLander without porting them to Go. The bridge talks with the Python side (see gym_bridge.py)
+// using small JSON protocol either through pipes of subprocess or over HTTP.
+//
+// The protocol is request/response based, each request is a JSON object with "command" field set to one of: "make",
+// "reset", "step" or "close". The "make" request has "env" field with ID of Gymnasium environment to create, the
+// "step" request has "action" field with either an integer (discrete action) or array of numbers (continuous action).
+// Each response is a JSON object with "obs", "reward", "done" and "error" fields. The non-empty "error" field signals
+// failure of request at the Python side. When talking through pipes, each request and response is written as single
+// line.
+package gym
+
+import (
+	"github.com/yaricom/goNEAT/experiments"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"bytes"
+	"net/http"
+)
+
+// The request sent to the Python side of bridge
+type request struct {
+	// The command to execute
+	Command string `json:"command"`
+	// The ID of environment to create with "make" command
+	Env     string `json:"env,omitempty"`
+	// The action to apply with "step" command
+	Action  interface{} `json:"action,omitempty"`
+}
+
+// The response received from the Python side of bridge
+type response struct {
+	// The observations of environment
+	Obs    []float64 `json:"obs"`
+	// The reward received for the step
+	Reward float64 `json:"reward"`
+	// The flag to indicate that episode is finished, i.e. terminated or truncated
+	Done   bool `json:"done"`
+	// The error message if request failed
+	Error  string `json:"error"`
+}
+
+// The transport delivering requests to the Python side of bridge
+type transport interface {
+	// Sends request and reads response
+	exchange(req *request, resp *response) error
+	// Releases resources held by transport
+	close() error
+}
+
+// The environment living at the Python side of bridge. It implements experiments.Environment and
+// experiments.EnvironmentErrorReporter, thus the errors of bridge abort episode run. The environment is not safe for
+// concurrent use, the separate environment should be created per goroutine.
+type Environment struct {
+	// The ID of Gymnasium environment, e.g. CartPole-v1
+	EnvId      string
+	// The type of action accepted by environment. If DiscreteAction, the index of maximal network output is sent as
+	// action, otherwise all outputs sent.
+	ActionType experiments.ActionType
+
+	// The transport to talk with the Python side
+	transport  transport
+	// The last error of bridge
+	err        error
+}
+
+// Creates environment with given ID at the Python side of bridge talking through provided reader and writer, e.g.
+// pipes of already running process or network connection.
+func NewPipeEnvironment(r io.Reader, w io.Writer, env_id string, action_type experiments.ActionType) (*Environment, error) {
+	return newEnvironment(&pipeTransport{encoder:json.NewEncoder(w), decoder:json.NewDecoder(r), writer:w},
+		env_id, action_type)
+}
+
+// Starts the Python side of bridge as subprocess using given command and arguments, e.g. "python3 gym_bridge.py",
+// and creates environment with given ID talking through pipes of subprocess. The stderr of subprocess is redirected
+// to stderr of this process. The subprocess is stopped by Close.
+func StartEnvironment(env_id string, action_type experiments.ActionType, command string, args ...string) (*Environment, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+	env, err := newEnvironment(&pipeTransport{encoder:json.NewEncoder(stdin), decoder:json.NewDecoder(stdout),
+		writer:stdin, cmd:cmd}, env_id, action_type)
+	if err != nil {
+		stdin.Close()
+		cmd.Wait()
+		return nil, err
+	}
+	return env, nil
+}
+
+// Creates environment with given ID at the Python side of bridge served over HTTP at given URL. Each request is
+// sent as POST with JSON body.
+func NewHTTPEnvironment(url, env_id string, action_type experiments.ActionType) (*Environment, error) {
+	return newEnvironment(&httpTransport{url:url, client:http.DefaultClient}, env_id, action_type)
+}
+
+func newEnvironment(t transport, env_id string, action_type experiments.ActionType) (*Environment, error) {
+	e := &Environment{EnvId:env_id, ActionType:action_type, transport:t}
+	if _, err := e.send(&request{Command:"make", Env:env_id}); err != nil {
+		t.close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Resets environment and returns initial observations. The error of previous episode is cleared.
+func (e *Environment) Reset() []float64 {
+	e.err = nil
+	resp, err := e.send(&request{Command:"reset"})
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	return resp.Obs
+}
+
+// Applies action to environment and returns observations, reward and flag indicating whether episode is finished.
+// The episode is reported as finished if bridge failed.
+func (e *Environment) Step(action []float64) ([]float64, float64, bool) {
+	req := request{Command:"step"}
+	if e.ActionType == experiments.DiscreteAction {
+		req.Action = argmax(action)
+	} else {
+		req.Action = action
+	}
+	resp, err := e.send(&req)
+	if err != nil {
+		e.err = err
+		return nil, 0.0, true
+	}
+	return resp.Obs, resp.Reward, resp.Done
+}
+
+// Returns the last error of bridge or nil
+func (e *Environment) Err() error {
+	return e.err
+}
+
+// Closes environment at the Python side of bridge and releases transport
+func (e *Environment) Close() error {
+	_, err := e.send(&request{Command:"close"})
+	if c_err := e.transport.close(); err == nil {
+		err = c_err
+	}
+	return err
+}
+
+func (e *Environment) send(req *request) (*response, error) {
+	resp := response{}
+	if err := e.transport.exchange(req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(fmt.Sprintf("GYM: %s failed for [%s]: %s", req.Command, e.EnvId, resp.Error))
+	}
+	return &resp, nil
+}
+
+// Returns the index of maximal value
+func argmax(values []float64) int {
+	index := 0
+	for i, v := range values {
+		if v > values[index] {
+			index = i
+		}
+	}
+	return index
+}
+
+// The transport through pipes, each message is written as single line of JSON
+type pipeTransport struct {
+	encoder *json.Encoder
+	decoder *json.Decoder
+	writer  io.Writer
+	// The subprocess if started by bridge
+	cmd     *exec.Cmd
+}
+
+func (t *pipeTransport) exchange(req *request, resp *response) error {
+	if err := t.encoder.Encode(req); err != nil {
+		return err
+	}
+	if err := t.decoder.Decode(resp); err != nil {
+		return errors.New(fmt.Sprintf("GYM: failed to read response to %s: %s", req.Command, err))
+	}
+	return nil
+}
+
+func (t *pipeTransport) close() error {
+	var err error
+	if c, ok := t.writer.(io.Closer); ok {
+		err = c.Close()
+	}
+	if t.cmd != nil {
+		if w_err := t.cmd.Wait(); err == nil {
+			err = w_err
+		}
+	}
+	return err
+}
+
+// The transport over HTTP, each request is sent as POST with JSON body
+type httpTransport struct {
+	url    string
+	client *http.Client
+}
+
+func (t *httpTransport) exchange(req *request, resp *response) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	res, err := t.client.Post(t.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.New(fmt.Sprintf("GYM: unexpected HTTP status of %s: %s", req.Command, res.Status))
+	}
+	return json.NewDecoder(res.Body).Decode(resp)
+}
+
+func (t *httpTransport) close() error {
+	return nil
+}
@@ -0,0 +1,92 @@
+#!/usr/bin/env python3
+"""The Python side of goNEAT bridge to Gymnasium environments.
+
+Usage:
+    python3 gym_bridge.py               - serve requests read line by line from stdin, responses written to stdout
+    python3 gym_bridge.py --http PORT   - serve requests posted over HTTP at given port
+
+Each process (or HTTP server) holds single environment created by the "make" request.
+"""
+import json
+import sys
+from http.server import BaseHTTPRequestHandler, HTTPServer
+
+import gymnasium as gym
+import numpy as np
+
+
+class Bridge:
+    def __init__(self):
+        self.env = None
+
+    def handle(self, req):
+        try:
+            return self.execute(req)
+        except Exception as e:
+            return {"error": str(e)}
+
+    def execute(self, req):
+        command = req.get("command")
+        if command == "make":
+            self.close()
+            self.env = gym.make(req["env"])
+            return {}
+        if command == "close":
+            self.close()
+            return {}
+        if self.env is None:
+            return {"error": "environment is not created"}
+        if command == "reset":
+            obs, _ = self.env.reset()
+            return {"obs": flatten(obs)}
+        if command == "step":
+            action = req.get("action")
+            if not isinstance(action, int):
+                action = np.asarray(action, dtype=np.float32)
+            obs, reward, terminated, truncated, _ = self.env.step(action)
+            return {"obs": flatten(obs), "reward": float(reward), "done": bool(terminated or truncated)}
+        return {"error": "unsupported command: %s" % command}
+
+    def close(self):
+        if self.env is not None:
+            self.env.close()
+            self.env = None
+
+
+def flatten(obs):
+    return np.asarray(obs, dtype=np.float64).flatten().tolist()
+
+
+def serve_pipes(bridge):
+    for line in sys.stdin:
+        if not line.strip():
+            continue
+        req = json.loads(line)
+        sys.stdout.write(json.dumps(bridge.handle(req)) + "\n")
+        sys.stdout.flush()
+        if req.get("command") == "close":
+            break
+
+
+def serve_http(bridge, port):
+    class Handler(BaseHTTPRequestHandler):
+        def do_POST(self):
+            length = int(self.headers.get("Content-Length", 0))
+            body = json.dumps(bridge.handle(json.loads(self.rfile.read(length)))).encode()
+            self.send_response(200)
+            self.send_header("Content-Type", "application/json")
+            self.send_header("Content-Length", str(len(body)))
+            self.end_headers()
+            self.wfile.write(body)
+
+        def log_message(self, format, *args):
+            pass
+
+    HTTPServer(("", port), Handler).serve_forever()
+
+
+if __name__ == "__main__":
+    if len(sys.argv) == 3 and sys.argv[1] == "--http":
+        serve_http(Bridge(), int(sys.argv[2]))
+    else:
+        serve_pipes(Bridge())
@@ -0,0 +1,220 @@
+package gym
+
+import (
+	"testing"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The fake Python side of bridge, the episode is done after three steps and reward is the value of action
+type fakeBridge struct {
+	env      string
+	steps    int
+	requests []map[string]interface{}
+}
+
+func (b *fakeBridge) handle(req map[string]interface{}) response {
+	b.requests = append(b.requests, req)
+	switch req["command"] {
+	case "make":
+		if req["env"] != "Fake-v0" {
+			return response{Error:fmt.Sprintf("unknown environment: %v", req["env"])}
+		}
+		b.env = req["env"].(string)
+		return response{}
+	case "reset":
+		b.steps = 0
+		return response{Obs:[]float64{0.0, 1.0}}
+	case "step":
+		b.steps++
+		reward := 0.0
+		switch action := req["action"].(type) {
+		case float64:
+			reward = action
+		case []interface{}:
+			reward = action[0].(float64)
+		}
+		return response{Obs:[]float64{float64(b.steps), 1.0}, Reward:reward, Done:b.steps >= 3}
+	case "close":
+		return response{}
+	}
+	return response{Error:"unsupported command"}
+}
+
+// Serves requests read line by line from reader until close request
+func (b *fakeBridge) servePipes(r io.Reader, w io.Writer) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		req := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return
+		}
+		data, _ := json.Marshal(b.handle(req))
+		fmt.Fprintln(w, string(data))
+		if req["command"] == "close" {
+			return
+		}
+	}
+}
+
+// The OS pipes are used as with real subprocess: unlike io.Pipe they are buffered, thus bridge is not blocked
+// writing the trailing newline of response which was already decoded
+func startPipeEnvironment(bridge *fakeBridge, env_id string, action_type experiments.ActionType) (*Environment, error) {
+	req_r, req_w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	resp_r, resp_w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		bridge.servePipes(req_r, resp_w)
+		resp_w.Close()
+		req_r.Close()
+	}()
+	return NewPipeEnvironment(resp_r, req_w, env_id, action_type)
+}
+
+func TestNewPipeEnvironment(t *testing.T) {
+	bridge := &fakeBridge{}
+	env, err := startPipeEnvironment(bridge, "Fake-v0", experiments.ContinuousAction)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if bridge.env != "Fake-v0" {
+		t.Error("Environment was not created", bridge.env)
+	}
+	if obs := env.Reset(); len(obs) != 2 || obs[1] != 1.0 {
+		t.Error("Wrong initial observations", obs)
+	}
+	obs, reward, done := env.Step([]float64{0.5, 0.7})
+	if env.Err() != nil {
+		t.Error(env.Err())
+	}
+	if len(obs) != 2 || obs[0] != 1.0 {
+		t.Error("Wrong observations", obs)
+	}
+	if reward != 0.5 {
+		t.Error("reward != 0.5", reward)
+	}
+	if done {
+		t.Error("Episode should not be done")
+	}
+	if err = env.Close(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err = startPipeEnvironment(&fakeBridge{}, "Unknown-v0", experiments.ContinuousAction); err == nil {
+		t.Error("Error expected for unknown environment")
+	}
+}
+
+func TestNewHTTPEnvironment(t *testing.T) {
+	bridge := &fakeBridge{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		req := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(bridge.handle(req))
+	}))
+	defer server.Close()
+
+	env, err := NewHTTPEnvironment(server.URL, "Fake-v0", experiments.DiscreteAction)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	env.Reset()
+	// the index of maximal output sent as discrete action
+	if _, reward, _ := env.Step([]float64{0.1, 0.9, 0.3}); reward != 1.0 {
+		t.Error("reward != 1.0", reward)
+	}
+	if action := bridge.requests[len(bridge.requests) - 1]["action"]; action != 1.0 {
+		t.Error("Wrong discrete action", action)
+	}
+
+	// the bridge error is reported and finishes episode
+	env.transport.(*httpTransport).url = server.URL + "/missing"
+	if _, _, done := env.Step([]float64{1.0}); !done {
+		t.Error("Episode should be done on bridge error")
+	}
+	if env.Err() == nil {
+		t.Error("Error expected for failed request")
+	}
+}
+
+func TestEnvironment_RunEpisode(t *testing.T) {
+	bridge := &fakeBridge{}
+	env, err := startPipeEnvironment(bridge, "Fake-v0", experiments.ContinuousAction)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer env.Close()
+
+	genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	net, err := genome.Genesis(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reward, steps, err := experiments.EpisodeRunner{}.RunEpisode(net, env)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if steps != 3 {
+		t.Error("steps != 3", steps)
+	}
+	if reward <= 0.0 {
+		t.Error("Positive reward expected for sigmoid outputs", reward)
+	}
+}
+
+func TestStartEnvironment(t *testing.T) {
+	// the test binary itself serves as subprocess
+	t.Setenv("GONEAT_GYM_HELPER", "1")
+	env, err := StartEnvironment("Fake-v0", experiments.ContinuousAction, os.Args[0], "-test.run=TestHelperBridgeProcess")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if obs := env.Reset(); len(obs) != 2 {
+		t.Error("Wrong initial observations", obs, env.Err())
+	}
+	if err = env.Close(); err != nil {
+		t.Error(err)
+	}
+
+	if _, err = StartEnvironment("Fake-v0", experiments.ContinuousAction, "goneat-missing-command"); err == nil {
+		t.Error("Error expected for missing command")
+	}
+}
+
+// Serves as the Python side of bridge when started as subprocess by TestStartEnvironment
+func TestHelperBridgeProcess(t *testing.T) {
+	if os.Getenv("GONEAT_GYM_HELPER") != "1" {
+		return
+	}
+	(&fakeBridge{}).servePipes(os.Stdin, os.Stdout)
+	os.Exit(0)
+}
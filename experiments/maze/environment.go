@@ -0,0 +1,301 @@
+package maze
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The point in the maze plane
+type Point struct {
+	X, Y float64
+}
+
+// Returns distance to the other point
+func (p Point) Distance(other Point) float64 {
+	return math.Hypot(p.X - other.X, p.Y - other.Y)
+}
+
+// The line segment, e.g. maze wall
+type Line struct {
+	A, B Point
+}
+
+// Returns the intersection point of this line segment with the other one and true if segments intersect
+func (l Line) Intersection(other Line) (Point, bool) {
+	d := (l.B.X - l.A.X) * (other.B.Y - other.A.Y) - (l.B.Y - l.A.Y) * (other.B.X - other.A.X)
+	if d == 0 {
+		// parallel
+		return Point{}, false
+	}
+	r := ((l.A.Y - other.A.Y) * (other.B.X - other.A.X) - (l.A.X - other.A.X) * (other.B.Y - other.A.Y)) / d
+	s := ((l.A.Y - other.A.Y) * (l.B.X - l.A.X) - (l.A.X - other.A.X) * (l.B.Y - l.A.Y)) / d
+	if r < 0 || r > 1 || s < 0 || s > 1 {
+		return Point{}, false
+	}
+	return Point{X:l.A.X + r * (l.B.X - l.A.X), Y:l.A.Y + r * (l.B.Y - l.A.Y)}, true
+}
+
+// Returns distance from given point to this line segment
+func (l Line) Distance(p Point) float64 {
+	dx, dy := l.B.X - l.A.X, l.B.Y - l.A.Y
+	length := dx * dx + dy * dy
+	if length == 0 {
+		return p.Distance(l.A)
+	}
+	t := ((p.X - l.A.X) * dx + (p.Y - l.A.Y) * dy) / length
+	t = math.Max(0, math.Min(1, t))
+	return p.Distance(Point{X:l.A.X + t * dx, Y:l.A.Y + t * dy})
+}
+
+// The maze environment definition
+type Maze struct {
+	// The walls of maze
+	Walls        []Line
+	// The start location of agent
+	Start        Point
+	// The start heading of agent in degrees
+	StartHeading float64
+	// The location of maze exit
+	Goal         Point
+}
+
+// Returns the medium maze from novelty search experiments of Lehman and Stanley
+func MediumMaze() *Maze {
+	maze, _ := ReadMaze(strings.NewReader(mediumMazeData))
+	return maze
+}
+
+// Returns the hard maze from novelty search experiments of Lehman and Stanley
+func HardMaze() *Maze {
+	maze, _ := ReadMaze(strings.NewReader(hardMazeData))
+	return maze
+}
+
+// Reads maze definition in the format of original novelty search experiments: the number of walls, the start location,
+// the start heading, the goal location and the walls each given by coordinates of its ends, one per line.
+func ReadMaze(r io.Reader) (*Maze, error) {
+	values := make([][]float64, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		line := make([]float64, len(fields))
+		for i, f := range fields {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("MAZE: failed to parse value [%s]: %s", f, err))
+			}
+			line[i] = v
+		}
+		values = append(values, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(values) < 4 || len(values[0]) != 1 || len(values[1]) != 2 || len(values[2]) != 1 || len(values[3]) != 2 {
+		return nil, errors.New("MAZE: malformed maze header")
+	}
+	walls_count := int(values[0][0])
+	if len(values) - 4 != walls_count {
+		return nil, errors.New(fmt.Sprintf("MAZE: expected %d walls, found: %d", walls_count, len(values) - 4))
+	}
+	maze := &Maze{
+		Start:Point{X:values[1][0], Y:values[1][1]},
+		StartHeading:values[2][0],
+		Goal:Point{X:values[3][0], Y:values[3][1]},
+		Walls:make([]Line, walls_count),
+	}
+	for i, w := range values[4:] {
+		if len(w) != 4 {
+			return nil, errors.New(fmt.Sprintf("MAZE: malformed wall at: %d", i))
+		}
+		maze.Walls[i] = Line{A:Point{X:w[0], Y:w[1]}, B:Point{X:w[2], Y:w[3]}}
+	}
+	return maze, nil
+}
+
+// Returns the diagonal of bounding box of maze walls, i.e. the maximal possible distance within maze
+func (m *Maze) Diagonal() float64 {
+	min_x, min_y, max_x, max_y := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, w := range m.Walls {
+		for _, p := range []Point{w.A, w.B} {
+			min_x, max_x = math.Min(min_x, p.X), math.Max(max_x, p.X)
+			min_y, max_y = math.Min(min_y, p.Y), math.Max(max_y, p.Y)
+		}
+	}
+	if len(m.Walls) == 0 {
+		return 0.0
+	}
+	return math.Hypot(max_x - min_x, max_y - min_y)
+}
+
+// The agent navigation constants from original novelty search experiments
+const (
+	// The radius of agent body
+	agentRadius = 8.0
+	// The maximal range of range finder sensors
+	rangeFinderRange = 100.0
+	// The maximal absolute value of agent speed
+	maxSpeed = 3.0
+	// The maximal absolute value of agent angular velocity
+	maxAngularVelocity = 3.0
+	// The distance to maze exit at which agent considered reached it
+	ExitRadius = 5.0
+)
+
+// The angles of range finder sensors relative to agent heading in degrees
+var rangeFinderAngles = []float64{-90.0, -45.0, 0.0, 45.0, 90.0, -180.0}
+
+// The pie slices of radar sensors detecting direction to goal relative to agent heading in degrees
+var radarSlices = [][2]float64{{315.0, 405.0}, {45.0, 135.0}, {135.0, 225.0}, {225.0, 315.0}}
+
+// The number of agent sensors, i.e. range finders and radars
+var SensorsCount = len(rangeFinderAngles) + len(radarSlices)
+
+// The simulation of wheeled agent navigating the maze. The agent has range finder sensors to detect walls and radar
+// sensors to detect direction to the maze exit. It's controlled by two outputs changing its angular velocity and speed
+// respectively. The simulation implements experiments.Environment and episode is done when agent reaches maze exit.
+type Simulation struct {
+	// The maze to navigate
+	Maze            *Maze
+	// The current location of agent
+	Location        Point
+	// The current heading of agent in degrees
+	Heading         float64
+	// The current speed of agent
+	Speed           float64
+	// The current angular velocity of agent
+	AngularVelocity float64
+
+	// The sensors values buffer
+	sensors         []float64
+}
+
+// Creates new simulation of agent navigating given maze
+func NewSimulation(maze *Maze) *Simulation {
+	s := Simulation{Maze:maze, sensors:make([]float64, SensorsCount)}
+	s.Reset()
+	return &s
+}
+
+// Puts agent into start location of maze and returns its sensors values
+func (s *Simulation) Reset() []float64 {
+	s.Location = s.Maze.Start
+	s.Heading = s.Maze.StartHeading
+	s.Speed, s.AngularVelocity = 0.0, 0.0
+	return s.updateSensors()
+}
+
+// Applies network outputs to agent controls and moves agent one time step. The first output changes angular velocity
+// and the second one changes speed of agent, the value 0.5 is neutral. Returns new sensors values and flag indicating
+// whether agent reached maze exit. The reward is always zero, the fitness is evaluated from final location of agent.
+func (s *Simulation) Step(action []float64) ([]float64, float64, bool) {
+	if len(action) >= 2 {
+		s.AngularVelocity = clamp(s.AngularVelocity + (action[0] - 0.5), maxAngularVelocity)
+		s.Speed = clamp(s.Speed + (action[1] - 0.5), maxSpeed)
+	}
+
+	rad := s.Heading * math.Pi / 180.0
+	next := Point{X:s.Location.X + math.Cos(rad) * s.Speed, Y:s.Location.Y + math.Sin(rad) * s.Speed}
+	s.Heading = math.Mod(s.Heading + s.AngularVelocity + 360.0, 360.0)
+	if !s.collides(next) {
+		s.Location = next
+	}
+	return s.updateSensors(), 0.0, s.ExitFound()
+}
+
+// Returns distance from agent to the maze exit
+func (s *Simulation) DistanceToExit() float64 {
+	return s.Location.Distance(s.Maze.Goal)
+}
+
+// Returns true if agent reached the maze exit
+func (s *Simulation) ExitFound() bool {
+	return s.DistanceToExit() < ExitRadius
+}
+
+// Returns true if agent at given location collides with any wall
+func (s *Simulation) collides(loc Point) bool {
+	for _, w := range s.Maze.Walls {
+		if w.Distance(loc) < agentRadius {
+			return true
+		}
+	}
+	return false
+}
+
+// Updates agent sensors from its current location and heading
+func (s *Simulation) updateSensors() []float64 {
+	for i, angle := range rangeFinderAngles {
+		rad := (s.Heading + angle) * math.Pi / 180.0
+		ray := Line{A:s.Location, B:Point{
+			X:s.Location.X + math.Cos(rad) * rangeFinderRange,
+			Y:s.Location.Y + math.Sin(rad) * rangeFinderRange,
+		}}
+		dist := rangeFinderRange
+		for _, w := range s.Maze.Walls {
+			if p, ok := ray.Intersection(w); ok {
+				dist = math.Min(dist, s.Location.Distance(p))
+			}
+		}
+		s.sensors[i] = dist / rangeFinderRange
+	}
+
+	angle := math.Atan2(s.Maze.Goal.Y - s.Location.Y, s.Maze.Goal.X - s.Location.X) * 180.0 / math.Pi
+	angle = math.Mod(angle - s.Heading + 720.0, 360.0)
+	offset := len(rangeFinderAngles)
+	for i, slice := range radarSlices {
+		s.sensors[offset + i] = 0.0
+		if (angle >= slice[0] && angle < slice[1]) || (angle + 360.0 >= slice[0] && angle + 360.0 < slice[1]) {
+			s.sensors[offset + i] = 1.0
+		}
+	}
+	return s.sensors
+}
+
+func clamp(v, limit float64) float64 {
+	return math.Max(-limit, math.Min(limit, v))
+}
+
+// The medium maze definition
+const mediumMazeData = `11
+30 22
+0
+270 100
+5 5 295 5
+295 5 295 135
+295 135 5 135
+5 135 5 5
+241 135 58 65
+114 5 73 42
+130 91 107 46
+196 5 139 51
+219 125 182 63
+267 5 214 63
+271 135 237 105
+`
+
+// The hard maze definition
+const hardMazeData = `11
+36 184
+0
+31 20
+41 5 3 8
+3 8 4 49
+4 49 57 53
+4 49 7 202
+7 202 195 198
+195 198 186 8
+186 8 39 5
+56 54 56 157
+57 106 158 162
+77 45 112 119
+147 11 109 183
+`
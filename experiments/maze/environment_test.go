@@ -0,0 +1,120 @@
+package maze
+
+import (
+	"testing"
+	"strings"
+	"math"
+)
+
+func TestReadMaze(t *testing.T) {
+	maze := MediumMaze()
+	if maze == nil {
+		t.Error("Failed to read medium maze")
+		return
+	}
+	if len(maze.Walls) != 11 {
+		t.Error("len(maze.Walls) != 11", len(maze.Walls))
+	}
+	if maze.Start != (Point{X:30, Y:22}) || maze.Goal != (Point{X:270, Y:100}) {
+		t.Error("Wrong start or goal", maze.Start, maze.Goal)
+	}
+	if maze.Walls[4] != (Line{A:Point{X:241, Y:135}, B:Point{X:58, Y:65}}) {
+		t.Error("Wrong wall", maze.Walls[4])
+	}
+	if HardMaze() == nil {
+		t.Error("Failed to read hard maze")
+	}
+
+	for _, data := range []string{"", "1\n0 0\n0\n1 1\n", "1\n0 0\n0\n1 1\n0 0 1\n", "1\n0 0\nx\n1 1\n0 0 1 1\n"} {
+		if _, err := ReadMaze(strings.NewReader(data)); err == nil {
+			t.Error("Error expected for malformed maze", data)
+		}
+	}
+}
+
+func TestLine_Intersection(t *testing.T) {
+	l := Line{A:Point{X:0, Y:0}, B:Point{X:10, Y:10}}
+	if p, ok := l.Intersection(Line{A:Point{X:0, Y:10}, B:Point{X:10, Y:0}}); !ok || p != (Point{X:5, Y:5}) {
+		t.Error("Wrong intersection", p, ok)
+	}
+	if _, ok := l.Intersection(Line{A:Point{X:20, Y:0}, B:Point{X:20, Y:10}}); ok {
+		t.Error("Segments should not intersect")
+	}
+	if _, ok := l.Intersection(Line{A:Point{X:1, Y:0}, B:Point{X:11, Y:10}}); ok {
+		t.Error("Parallel segments should not intersect")
+	}
+}
+
+func TestLine_Distance(t *testing.T) {
+	l := Line{A:Point{X:0, Y:0}, B:Point{X:10, Y:0}}
+	if d := l.Distance(Point{X:5, Y:3}); d != 3.0 {
+		t.Error("d != 3.0", d)
+	}
+	if d := l.Distance(Point{X:13, Y:4}); d != 5.0 {
+		t.Error("d != 5.0", d)
+	}
+}
+
+func TestSimulation_sensors(t *testing.T) {
+	maze := MediumMaze()
+	sim := NewSimulation(maze)
+	obs := sim.Reset()
+	if len(obs) != SensorsCount {
+		t.Error("Wrong sensors count", len(obs))
+		return
+	}
+	// the wall at y = 5 is 17 units to the right of agent heading along X axis, i.e. at -90 degrees
+	if math.Abs(obs[0] - 0.17) > 1e-9 {
+		t.Error("Wrong right range finder", obs[0])
+	}
+	// the wall at x = 5 is 25 units behind agent
+	if math.Abs(obs[5] - 0.25) > 1e-9 {
+		t.Error("Wrong rear range finder", obs[5])
+	}
+	// the goal is ahead of agent
+	for i, expected := range []float64{1.0, 0.0, 0.0, 0.0} {
+		if obs[len(rangeFinderAngles) + i] != expected {
+			t.Error("Wrong radar value at:", i, obs[len(rangeFinderAngles) + i])
+		}
+	}
+}
+
+func TestSimulation_Step(t *testing.T) {
+	sim := NewSimulation(MediumMaze())
+	// accelerate straight ahead
+	for i := 0; i < 3; i++ {
+		if _, reward, done := sim.Step([]float64{0.5, 1.0}); reward != 0 || done {
+			t.Error("Unexpected step result", reward, done)
+		}
+	}
+	if sim.Speed != 1.5 || sim.Heading != 0 {
+		t.Error("Wrong agent controls", sim.Speed, sim.Heading)
+	}
+	if sim.Location != (Point{X:33.0, Y:22.0}) {
+		t.Error("Wrong agent location", sim.Location)
+	}
+	// the speed is limited
+	for i := 0; i < 10; i++ {
+		sim.Step([]float64{0.5, 1.0})
+	}
+	if sim.Speed != maxSpeed {
+		t.Error("sim.Speed != maxSpeed", sim.Speed)
+	}
+
+	// the agent stops at walls
+	sim.Reset()
+	sim.Heading = 270
+	for i := 0; i < 20; i++ {
+		sim.Step([]float64{0.5, 1.0})
+	}
+	if sim.Location.Y < 5 + agentRadius {
+		t.Error("The agent should not pass through the wall", sim.Location)
+	}
+
+	// the exit is found
+	sim.Reset()
+	sim.Location = Point{X:268, Y:100}
+	if _, _, done := sim.Step([]float64{0.5, 0.5}); !done {
+		t.Error("The exit should be found")
+	}
+}
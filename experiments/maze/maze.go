@@ -0,0 +1,203 @@
+// The maze navigation experiments from the novelty search literature (Lehman and Stanley, 2011). The wheeled agent
+// controlled by evolved network should navigate from the start location to the maze exit within limited time. The
+// medium and hard mazes are deceptive, i.e. the fitness based on distance to exit leads agents into dead ends, thus
+// the experiments are the standard benchmark of novelty search. The behavior of agent is characterized by its final
+// location in the maze.
+package maze
+
+import (
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/experiments"
+	"fmt"
+	"os"
+)
+
+// The key of organism tag holding behavior characterization, i.e. final location of agent as [x, y]
+const BehaviorTag = "maze_behavior"
+
+// The key of organism tag holding objective fitness, i.e. fitness based on distance to maze exit
+const ObjectiveFitnessTag = "maze_objective_fitness"
+
+// The default number of simulation time steps per evaluation
+const defaultTimeSteps = 400
+
+// The default number of nearest neighbors and novelty threshold of novelty archive
+const (
+	defaultNoveltyNeighbors = 15
+	defaultNoveltyThreshold = 6.0
+)
+
+// The name of file in the trial output directory to store behavior characterizations of organisms per generation
+const behaviorFileName = "maze_behavior.csv"
+
+// The maze navigation experiment evaluator. If novelty search enabled, the fitness of organism is the novelty of its
+// behavior and objective fitness stored in ObjectiveFitnessTag, otherwise fitness is objective fitness in range
+// [0, 1] growing while agent gets closer to the maze exit.
+type MazeGenerationEvaluator struct {
+	// The output path to store execution results
+	OutputPath    string
+	// The maze to navigate, the medium maze is used if not set
+	Maze          *Maze
+	// The number of simulation time steps per evaluation, zero means default
+	TimeSteps     int
+	// The flag to indicate whether novelty search should be used instead of objective fitness
+	NoveltySearch bool
+	// The archive of novel behaviors, created with default parameters if not set
+	Archive       *experiments.NoveltyArchive
+}
+
+// Resets novelty archive before new trial run
+func (ex *MazeGenerationEvaluator) TrialRunStarted(trial *experiments.Trial) {
+	if ex.Archive != nil {
+		ex.Archive.Reset()
+	}
+}
+
+// This method evaluates one epoch for given population and prints results into output directory if any.
+func (ex *MazeGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiments.Generation, context *neat.NeatContext) (err error) {
+	if ex.Maze == nil {
+		ex.Maze = MediumMaze()
+	}
+	for _, org := range pop.Organisms {
+		res, err := experiments.EvaluateWithErrorPolicy(org, ex.orgEvaluate, context)
+		if err != nil {
+			return err
+		}
+		if res && (epoch.Best == nil || objectiveFitness(org) > objectiveFitness(epoch.Best)) {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(org.Genotype.Nodes)
+			epoch.WinnerGenes = org.Genotype.Extrons()
+			epoch.WinnerEvals = context.PopSize * epoch.Id + org.Genotype.Id
+			epoch.Best = org
+		}
+	}
+
+	if ex.NoveltySearch {
+		ex.evaluateNovelty(pop)
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+
+	if len(ex.OutputPath) == 0 {
+		return nil
+	}
+	out_dir := experiments.OutDirForTrial(ex.OutputPath, epoch.TrialId)
+	if err = writeBehaviors(fmt.Sprintf("%s/%s", out_dir, behaviorFileName), epoch.Id, pop); err != nil {
+		neat.ErrorLog(fmt.Sprintf("Failed to write behavior characterizations, reason: %s\n", err))
+	}
+
+	// Only print to file every print_every generations
+	if epoch.Solved || epoch.Id % context.PrintEvery == 0 {
+		pop_path := fmt.Sprintf("%s/gen_%d", out_dir, epoch.Id)
+		file, err := os.Create(pop_path)
+		if err != nil {
+			neat.ErrorLog(fmt.Sprintf("Failed to dump population, reason: %s\n", err))
+		} else {
+			pop.WriteBySpecies(file)
+			file.Close()
+		}
+	}
+
+	if epoch.Solved {
+		// print winner organism
+		org := epoch.Best
+		org_path := fmt.Sprintf("%s/%s_%d-%d", out_dir, "maze_winner", org.Genotype.Id, len(org.Genotype.Nodes))
+		file, err := os.Create(org_path)
+		if err != nil {
+			neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
+		} else {
+			org.Genotype.Write(file)
+			file.Close()
+			neat.InfoLog(fmt.Sprintf("Generation #%d winner dumped to: %s\n", epoch.Id, org_path))
+		}
+	}
+	return nil
+}
+
+// This method evaluates provided organism for maze navigation task
+func (ex *MazeGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool, error) {
+	phenotype, err := organism.Phenotype()
+	if err != nil {
+		return false, err
+	}
+	steps := ex.TimeSteps
+	if steps <= 0 {
+		steps = defaultTimeSteps
+	}
+	sim := NewSimulation(ex.Maze)
+	if _, _, err = (experiments.EpisodeRunner{MaxSteps:steps}).RunEpisode(phenotype, sim); err != nil {
+		return false, err
+	}
+
+	diagonal := ex.Maze.Diagonal()
+	organism.Fitness = (diagonal - sim.DistanceToExit()) / diagonal
+	organism.Error = 1.0 - organism.Fitness
+	organism.IsWinner = sim.ExitFound()
+	if organism.IsWinner {
+		organism.Fitness = 1.0
+		organism.Error = 0.0
+	}
+	if organism.Tags == nil {
+		organism.Tags = make(genetics.OrganismTags)
+	}
+	organism.Tags[BehaviorTag] = []float64{sim.Location.X, sim.Location.Y}
+	organism.Tags[ObjectiveFitnessTag] = organism.Fitness
+
+	if neat.LogLevel == neat.LogLevelDebug {
+		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f\tlocation: %v", organism.Genotype.Id,
+			organism.Fitness, sim.Location))
+	}
+	return organism.IsWinner, nil
+}
+
+// Sets fitness of organisms to novelty of their behaviors relative to population and novelty archive. The organisms
+// without behavior characterization, e.g. failed evaluation, keep their fitness.
+func (ex *MazeGenerationEvaluator) evaluateNovelty(pop *genetics.Population) {
+	if ex.Archive == nil {
+		ex.Archive = experiments.NewNoveltyArchive(defaultNoveltyNeighbors, defaultNoveltyThreshold)
+	}
+	orgs := make([]*genetics.Organism, 0, len(pop.Organisms))
+	behaviors := make([]experiments.BehaviorDescriptor, 0, len(pop.Organisms))
+	for _, org := range pop.Organisms {
+		if behavior, ok := org.Tags[BehaviorTag].([]float64); ok && org.EvaluationError == nil {
+			orgs = append(orgs, org)
+			behaviors = append(behaviors, experiments.VectorBehavior(behavior))
+		}
+	}
+	for i, novelty := range ex.Archive.EvaluatePopulation(behaviors) {
+		orgs[i].Fitness = novelty
+	}
+}
+
+// Returns objective fitness of organism stored in tags or its fitness if not found
+func objectiveFitness(org *genetics.Organism) float64 {
+	if f, ok := org.Tags[ObjectiveFitnessTag].(float64); ok {
+		return f
+	}
+	return org.Fitness
+}
+
+// Appends behavior characterizations of organisms evaluated in given generation to the CSV file
+func writeBehaviors(path string, generation int, pop *genetics.Population) error {
+	file, err := os.OpenFile(path, os.O_CREATE | os.O_APPEND | os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		fmt.Fprintln(file, "generation,organism,x,y,objective_fitness,fitness")
+	}
+	for _, org := range pop.Organisms {
+		behavior, ok := org.Tags[BehaviorTag].([]float64)
+		if !ok {
+			continue
+		}
+		if _, err = fmt.Fprintf(file, "%d,%d,%f,%f,%f,%f\n", generation, org.Genotype.Id, behavior[0], behavior[1],
+			objectiveFitness(org), org.Fitness); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package maze
+
+import (
+	"testing"
+	"math/rand"
+	"os"
+	"strings"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func testMazeContext() *neat.NeatContext {
+	return &neat.NeatContext{
+		PopSize:30,
+		CompatThreshold:3.0,
+		DropOffAge:15,
+		SurvivalThresh:0.2,
+		MutateOnlyProb:0.25,
+		MutateLinkWeightsProb:0.9,
+		MutateAddNodeProb:0.03,
+		MutateAddLinkProb:0.08,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		PrintEvery:2,
+		NumRuns:1,
+		NumGenerations:4,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+}
+
+func readStartGenome(t *testing.T) *genetics.Genome {
+	file, err := os.Open("../../data/mazestartgenes")
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	defer file.Close()
+	genome, err := genetics.ReadGenome(file, 1)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	return genome
+}
+
+func TestMazeGenerationEvaluator_GenerationEvaluate(t *testing.T) {
+	for _, novelty := range []bool{false, true} {
+		rand.Seed(42)
+		start_genome := readStartGenome(t)
+		if start_genome == nil {
+			return
+		}
+		out_dir := t.TempDir()
+		evaluator := &MazeGenerationEvaluator{OutputPath:out_dir, TimeSteps:100, NoveltySearch:novelty}
+		experiment := experiments.Experiment{}
+		if err := experiment.Execute(testMazeContext(), start_genome, evaluator); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(experiment.Trials[0].Generations) != 4 {
+			t.Error("Wrong number of generations", len(experiment.Trials[0].Generations))
+		}
+		if novelty && (evaluator.Archive == nil || len(evaluator.Archive.Behaviors) == 0) {
+			t.Error("Novel behaviors should be archived")
+		}
+
+		data, err := os.ReadFile(experiments.OutDirForTrial(out_dir, 0) + "/" + behaviorFileName)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if lines[0] != "generation,organism,x,y,objective_fitness,fitness" {
+			t.Error("Wrong behavior file header", lines[0])
+		}
+		if len(lines) != 4 * 30 + 1 {
+			t.Error("Wrong number of behavior records", len(lines))
+		}
+	}
+}
+
+func TestMazeGenerationEvaluator_orgEvaluate(t *testing.T) {
+	start_genome := readStartGenome(t)
+	if start_genome == nil {
+		return
+	}
+	org, err := genetics.NewOrganism(0.0, start_genome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &MazeGenerationEvaluator{Maze:MediumMaze(), TimeSteps:50}
+	if _, err = evaluator.orgEvaluate(org); err != nil {
+		t.Error(err)
+		return
+	}
+	behavior, ok := org.Tags[BehaviorTag].([]float64)
+	if !ok || len(behavior) != 2 {
+		t.Error("Behavior characterization expected", org.Tags)
+		return
+	}
+	// the zero weights of start genome produce neutral controls, thus agent stays in place
+	if behavior[0] != 30 || behavior[1] != 22 {
+		t.Error("Agent should stay at start location", behavior)
+	}
+	diagonal := evaluator.Maze.Diagonal()
+	expected := (diagonal - MediumMaze().Start.Distance(MediumMaze().Goal)) / diagonal
+	if org.Fitness != expected || objectiveFitness(org) != expected {
+		t.Error("Wrong fitness", org.Fitness, expected)
+	}
+	if org.IsWinner {
+		t.Error("Organism should not be winner")
+	}
+}
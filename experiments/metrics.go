@@ -0,0 +1,123 @@
+package experiments
+
+import (
+	"expvar"
+	"net/http"
+	"time"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The names of metrics published by MetricsExporter
+const (
+	// The gauge with ID of the last evaluated generation
+	MetricGeneration = "generation"
+	// The gauge with ID of the current trial
+	MetricTrial = "trial"
+	// The gauge with fitness of the best organism in the last evaluated generation
+	MetricBestFitness = "best_fitness"
+	// The gauge with number of species in population after last evaluated generation
+	MetricSpeciesCount = "species_count"
+	// The gauge with number of organisms evaluated per second during last generation
+	MetricEvaluationsPerSec = "evaluations_per_sec"
+	// The gauge with mean pairwise compatibility distance of organisms in population
+	MetricMeanCompatibility = "mean_compatibility"
+	// The gauge with variance of enabled link weights in population
+	MetricWeightVariance = "weight_variance"
+	// The gauge with number of unique topologies in population
+	MetricUniqueTopologies = "unique_topologies"
+	// The gauge with entropy of organisms distribution among species
+	MetricSpeciesEntropy = "species_entropy"
+	// The counter of all generations evaluated
+	MetricGenerationsTotal = "generations_total"
+	// The counter of all organisms evaluations done
+	MetricEvaluationsTotal = "evaluations_total"
+	// The counter of all network activation steps done
+	MetricActivationsTotal = "activations_total"
+	// The counter of all offspring produced by reproduction cycles
+	MetricOffspringTotal = "offspring_total"
+	// The counter of trials which was solved
+	MetricTrialsSolved = "trials_solved"
+)
+
+// The metrics exporter publishing per-generation gauges and counters of the experiment execution via expvar.
+// The published metrics can be scrapped by any monitoring system understanding expvar JSON format (including Prometheus
+// with appropriate exporter) using HTTP handler returned by Handler method.
+type MetricsExporter struct {
+	// The name under which metrics map is published
+	Name    string
+
+	// The published metrics map
+	metrics *expvar.Map
+}
+
+// Creates new metrics exporter which publishes metrics under provided name. If metrics with given name was already
+// published than existing metrics map will be reused.
+func NewMetricsExporter(name string) *MetricsExporter {
+	var metrics *expvar.Map
+	if v, ok := expvar.Get(name).(*expvar.Map); ok {
+		metrics = v
+	} else {
+		metrics = expvar.NewMap(name)
+	}
+	return &MetricsExporter{
+		Name:name,
+		metrics:metrics,
+	}
+}
+
+// Returns HTTP handler serving all published metrics in JSON format
+func (m *MetricsExporter) Handler() http.Handler {
+	return expvar.Handler()
+}
+
+// Returns current value of the metric with given name or zero if not found
+func (m *MetricsExporter) Value(name string) float64 {
+	switch v := m.metrics.Get(name).(type) {
+	case *expvar.Float:
+		return v.Value()
+	case *expvar.Int:
+		return float64(v.Value())
+	}
+	return 0
+}
+
+// Invoked to update metrics after generation evaluated. The evaluated is the number of organisms evaluated during
+// generation and duration is the time spent for evaluation.
+func (m *MetricsExporter) GenerationEvaluated(epoch *Generation, evaluated int, duration time.Duration) {
+	m.setFloat(MetricGeneration, float64(epoch.Id))
+	m.setFloat(MetricTrial, float64(epoch.TrialId))
+	m.setFloat(MetricSpeciesCount, float64(epoch.Diversity))
+	if epoch.Best != nil {
+		m.setFloat(MetricBestFitness, epoch.Best.Fitness)
+	}
+	if d := epoch.GeneticDiversity; d != nil {
+		m.setFloat(MetricMeanCompatibility, d.MeanCompatibility)
+		m.setFloat(MetricWeightVariance, d.WeightVariance)
+		m.setFloat(MetricUniqueTopologies, float64(d.UniqueTopologies))
+		m.setFloat(MetricSpeciesEntropy, d.SpeciesEntropy)
+	}
+	if duration > 0 {
+		m.setFloat(MetricEvaluationsPerSec, float64(evaluated) / duration.Seconds())
+	}
+	m.metrics.Add(MetricGenerationsTotal, 1)
+	m.metrics.Add(MetricEvaluationsTotal, int64(evaluated))
+	m.metrics.Add(MetricActivationsTotal, epoch.Activations)
+	if epoch.Solved {
+		m.metrics.Add(MetricTrialsSolved, 1)
+	}
+}
+
+// Invoked to update metrics after population epoch turnover, i.e. after reproduction cycle complete
+func (m *MetricsExporter) EpochCompleted(pop *genetics.Population) {
+	m.metrics.Add(MetricOffspringTotal, int64(len(pop.Organisms)))
+	m.setFloat(MetricSpeciesCount, float64(len(pop.Species)))
+}
+
+func (m *MetricsExporter) setFloat(name string, value float64) {
+	v, ok := m.metrics.Get(name).(*expvar.Float)
+	if !ok {
+		v = new(expvar.Float)
+		m.metrics.Set(name, v)
+	}
+	v.Set(value)
+}
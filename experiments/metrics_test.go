@@ -0,0 +1,101 @@
+package experiments
+
+import (
+	"testing"
+	"time"
+	"net/http/httptest"
+	"strings"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+func TestMetricsExporter_GenerationEvaluated(t *testing.T) {
+	m := NewMetricsExporter("test_generation_evaluated")
+
+	epoch := buildTestGeneration(2, 15.5)
+	epoch.TrialId = 3
+	epoch.Diversity = 4
+	m.GenerationEvaluated(epoch, 100, time.Second * 2)
+
+	if m.Value(MetricGeneration) != 2 {
+		t.Error("MetricGeneration", 2, m.Value(MetricGeneration))
+	}
+	if m.Value(MetricTrial) != 3 {
+		t.Error("MetricTrial", 3, m.Value(MetricTrial))
+	}
+	if m.Value(MetricSpeciesCount) != 4 {
+		t.Error("MetricSpeciesCount", 4, m.Value(MetricSpeciesCount))
+	}
+	if m.Value(MetricBestFitness) != 15.5 {
+		t.Error("MetricBestFitness", 15.5, m.Value(MetricBestFitness))
+	}
+	if m.Value(MetricEvaluationsPerSec) != 50 {
+		t.Error("MetricEvaluationsPerSec", 50, m.Value(MetricEvaluationsPerSec))
+	}
+
+	// check counters accumulated
+	m.GenerationEvaluated(epoch, 100, time.Second)
+	if m.Value(MetricGenerationsTotal) != 2 {
+		t.Error("MetricGenerationsTotal", 2, m.Value(MetricGenerationsTotal))
+	}
+	if m.Value(MetricEvaluationsTotal) != 200 {
+		t.Error("MetricEvaluationsTotal", 200, m.Value(MetricEvaluationsTotal))
+	}
+	if m.Value(MetricActivationsTotal) != 9000 {
+		t.Error("MetricActivationsTotal", 9000, m.Value(MetricActivationsTotal))
+	}
+	if m.Value(MetricTrialsSolved) != 2 {
+		t.Error("MetricTrialsSolved", 2, m.Value(MetricTrialsSolved))
+	}
+}
+
+func TestMetricsExporter_GenerationEvaluated_diversity(t *testing.T) {
+	m := NewMetricsExporter("test_generation_evaluated_diversity")
+
+	epoch := buildTestGeneration(2, 15.5)
+	m.GenerationEvaluated(epoch, 100, time.Second)
+	if m.Value(MetricUniqueTopologies) != 0 {
+		t.Error("MetricUniqueTopologies should not be set", m.Value(MetricUniqueTopologies))
+	}
+
+	epoch.GeneticDiversity = &genetics.PopulationDiversity{
+		MeanCompatibility:1.5,
+		WeightVariance:2.5,
+		UniqueTopologies:7,
+		SpeciesEntropy:0.5,
+	}
+	m.GenerationEvaluated(epoch, 100, time.Second)
+	if m.Value(MetricMeanCompatibility) != 1.5 {
+		t.Error("MetricMeanCompatibility", m.Value(MetricMeanCompatibility))
+	}
+	if m.Value(MetricWeightVariance) != 2.5 {
+		t.Error("MetricWeightVariance", m.Value(MetricWeightVariance))
+	}
+	if m.Value(MetricUniqueTopologies) != 7 {
+		t.Error("MetricUniqueTopologies", m.Value(MetricUniqueTopologies))
+	}
+	if m.Value(MetricSpeciesEntropy) != 0.5 {
+		t.Error("MetricSpeciesEntropy", m.Value(MetricSpeciesEntropy))
+	}
+}
+
+func TestMetricsExporter_Handler(t *testing.T) {
+	name := "test_metrics_handler"
+	m := NewMetricsExporter(name)
+	m.GenerationEvaluated(buildTestGeneration(1, 10.0), 10, time.Second)
+
+	// the same name should reuse published map
+	m2 := NewMetricsExporter(name)
+	if m2.Value(MetricEvaluationsTotal) != 10 {
+		t.Error("MetricEvaluationsTotal", 10, m2.Value(MetricEvaluationsTotal))
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/vars", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, name) {
+		t.Error("metrics not found in handler output", body)
+	}
+	if !strings.Contains(body, MetricBestFitness) {
+		t.Error("best fitness metric not found in handler output", body)
+	}
+}
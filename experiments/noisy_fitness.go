@@ -0,0 +1,99 @@
+package experiments
+
+import (
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The method to aggregate fitness values collected from repeated evaluations of the same organism
+type FitnessAggregationType int
+
+// The supported fitness aggregation methods
+const (
+	// The mean of collected fitness values
+	MeanFitnessAggregation FitnessAggregationType = iota
+	// The median of collected fitness values, which is robust to occasional lucky or unlucky evaluations
+	MedianFitnessAggregation
+	// The lower bound of 95% confidence interval of the mean, which penalizes organisms with unstable performance
+	ConfidenceFitnessAggregation
+)
+
+// The z-score for two-sided 95% confidence interval
+const confidenceZScore = 1.96
+
+// The interface describing evaluator of single organism which may produce stochastic fitness, e.g. when
+// environment simulation has randomness.
+type OrganismEvaluator interface {
+	// Invoked to evaluate organism once within given execution context. Returns raw fitness score and flag
+	// indicating whether organism solved the task during this evaluation.
+	OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (fitness float64, solved bool, err error)
+}
+
+// Evaluates organism context.FitnessEvalRepeats times (at least once) and sets its Fitness to the value aggregated
+// from all evaluations according to context.FitnessAggregationType. The organism considered as solver only if it was
+// able to solve the task during every evaluation. The organism is marked as evaluated (see genetics.OrganismState).
+// Returns collected raw fitness values along with solved flag.
+func EvaluateNoisyFitness(org *genetics.Organism, evaluator OrganismEvaluator, context *neat.NeatContext) (fitness Floats, solved bool, err error) {
+	repeats := context.FitnessEvalRepeats
+	if repeats < 1 {
+		repeats = 1
+	}
+	fitness = make(Floats, repeats)
+	solved = true
+	for i := 0; i < repeats; i++ {
+		f, res, err := evaluator.OrganismEvaluate(org, context)
+		if err != nil {
+			return nil, false, err
+		}
+		fitness[i] = f
+		solved = solved && res
+	}
+
+	org.Fitness, err = AggregateFitness(fitness, FitnessAggregationType(context.FitnessAggregationType))
+	if err != nil {
+		return nil, false, err
+	}
+	if err = org.MarkEvaluated(); err != nil {
+		return nil, false, err
+	}
+	return fitness, solved, nil
+}
+
+// Returns fitness value aggregated from provided fitness values according to given aggregation method
+func AggregateFitness(fitness Floats, aggregation FitnessAggregationType) (float64, error) {
+	if len(fitness) == 0 {
+		return 0.0, errors.New("No fitness values to aggregate")
+	}
+	switch aggregation {
+	case MeanFitnessAggregation:
+		return fitness.Mean(), nil
+	case MedianFitnessAggregation:
+		return fitness.Median(), nil
+	case ConfidenceFitnessAggregation:
+		std_err := fitness.Stdev() / math.Sqrt(float64(len(fitness)))
+		return fitness.Mean() - confidenceZScore * std_err, nil
+	default:
+		return 0.0, errors.New(fmt.Sprintf("Unsupported fitness aggregation type: %d", aggregation))
+	}
+}
+
+// Re-validates champion organism by evaluating it context.ChampionRevalidations more times. Returns true only if
+// champion was able to solve the task during every re-validation, i.e. it was not just lucky during regular evaluation.
+// The champion's fitness is not changed.
+func ValidateChampion(org *genetics.Organism, evaluator OrganismEvaluator, context *neat.NeatContext) (bool, error) {
+	for i := 0; i < context.ChampionRevalidations; i++ {
+		_, solved, err := evaluator.OrganismEvaluate(org, context)
+		if err != nil {
+			return false, err
+		}
+		if !solved {
+			neat.InfoLog(fmt.Sprintf("Champion organism [%d] failed re-validation at attempt: %d\n",
+				org.Genotype.Id, i + 1))
+			return false, nil
+		}
+	}
+	return true, nil
+}
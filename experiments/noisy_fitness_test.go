@@ -0,0 +1,125 @@
+package experiments
+
+import (
+	"testing"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"math"
+)
+
+// The evaluator returning predefined sequence of fitness values, organism considered solver when fitness exceeds threshold
+type sequenceEvaluator struct {
+	fitness   []float64
+	threshold float64
+	calls     int
+}
+
+func (e *sequenceEvaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (float64, bool, error) {
+	f := e.fitness[e.calls % len(e.fitness)]
+	e.calls++
+	return f, f >= e.threshold, nil
+}
+
+func TestEvaluateNoisyFitness(t *testing.T) {
+	org := &genetics.Organism{}
+	evaluator := &sequenceEvaluator{fitness:[]float64{1.0, 10.0, 2.0, 3.0}, threshold:1.0}
+
+	// mean
+	context := neat.NeatContext{FitnessEvalRepeats:4}
+	fitness, solved, err := EvaluateNoisyFitness(org, evaluator, &context)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(fitness) != 4 {
+		t.Error("len(fitness) != 4", len(fitness))
+	}
+	if evaluator.calls != 4 {
+		t.Error("evaluator.calls != 4", evaluator.calls)
+	}
+	if org.State() != genetics.OrganismEvaluated {
+		t.Error("The organism should be marked as evaluated", org.State())
+	}
+	if org.Fitness != 4.0 {
+		t.Error("org.Fitness != 4.0", org.Fitness)
+	}
+	if !solved {
+		t.Error("Organism should be solver")
+	}
+
+	// median
+	evaluator.calls = 0
+	context.FitnessAggregationType = int(MedianFitnessAggregation)
+	if _, _, err = EvaluateNoisyFitness(org, evaluator, &context); err != nil {
+		t.Error(err)
+		return
+	}
+	if org.Fitness != 2.5 {
+		t.Error("org.Fitness != 2.5", org.Fitness)
+	}
+
+	// not solved in all evaluations
+	evaluator.calls = 0
+	evaluator.threshold = 2.0
+	if _, solved, err = EvaluateNoisyFitness(org, evaluator, &context); err != nil {
+		t.Error(err)
+	} else if solved {
+		t.Error("Organism should not be solver")
+	}
+
+	// zero repeats means single evaluation
+	evaluator.calls = 0
+	context.FitnessEvalRepeats = 0
+	if _, _, err = EvaluateNoisyFitness(org, evaluator, &context); err != nil {
+		t.Error(err)
+	}
+	if evaluator.calls != 1 {
+		t.Error("evaluator.calls != 1", evaluator.calls)
+	}
+}
+
+func TestAggregateFitness(t *testing.T) {
+	fitness := Floats{2.0, 4.0, 4.0, 4.0, 5.0, 5.0, 7.0, 9.0}
+
+	if f, err := AggregateFitness(fitness, MeanFitnessAggregation); err != nil || f != 5.0 {
+		t.Error("Mean", f, err)
+	}
+	if f, err := AggregateFitness(fitness, MedianFitnessAggregation); err != nil || f != 4.5 {
+		t.Error("Median", f, err)
+	}
+	// mean - 1.96 * stdev / sqrt(n), where stdev is 2.0
+	expected := 5.0 - 1.96 * 2.0 / math.Sqrt(8.0)
+	if f, err := AggregateFitness(fitness, ConfidenceFitnessAggregation); err != nil || math.Abs(f - expected) > 1e-9 {
+		t.Error("Confidence", expected, f, err)
+	}
+
+	if _, err := AggregateFitness(Floats{}, MeanFitnessAggregation); err == nil {
+		t.Error("Error expected for empty fitness values")
+	}
+	if _, err := AggregateFitness(fitness, FitnessAggregationType(100)); err == nil {
+		t.Error("Error expected for unsupported aggregation type")
+	}
+}
+
+func TestValidateChampion(t *testing.T) {
+	gen := genetics.NewGenome(1, []*neat.Trait{}, []*network.NNode{}, []*genetics.Gene{})
+	org := &genetics.Organism{Fitness:10.0, Genotype:gen}
+	context := neat.NeatContext{ChampionRevalidations:3}
+
+	evaluator := &sequenceEvaluator{fitness:[]float64{10.0}, threshold:5.0}
+	if ok, err := ValidateChampion(org, evaluator, &context); err != nil || !ok {
+		t.Error("Champion should pass validation", ok, err)
+	}
+	if evaluator.calls != 3 {
+		t.Error("evaluator.calls != 3", evaluator.calls)
+	}
+
+	evaluator = &sequenceEvaluator{fitness:[]float64{10.0, 1.0}, threshold:5.0}
+	if ok, err := ValidateChampion(org, evaluator, &context); err != nil || ok {
+		t.Error("Champion should fail validation", ok, err)
+	}
+	if org.Fitness != 10.0 {
+		t.Error("Champion fitness should not change", org.Fitness)
+	}
+}
@@ -0,0 +1,131 @@
+package experiments
+
+import (
+	"math"
+	"sort"
+)
+
+// The default number of nearest neighbors used to estimate novelty if not set in archive
+const defaultNoveltyNeighbors = 15
+
+// The number of generations without additions to archive after which novelty threshold is lowered
+const noveltyStagnationGenerations = 10
+
+// The number of additions to archive per generation above which novelty threshold is raised
+const noveltyAdditionsLimit = 4
+
+// The archive of novel behaviors for novelty search. The novelty of behavior is estimated as the average distance to
+// its K nearest neighbors among behaviors of current population and archive measured by archive's metric. The behavior which novelty exceeds
+// threshold is added to archive. The threshold is adjusted dynamically: it is raised by 20% if too many behaviors
+// were added during generation and lowered by 5% if no behaviors were added for several generations.
+type NoveltyArchive struct {
+	// The number of nearest neighbors used to estimate novelty
+	K              int
+	// The novelty threshold for behavior to be added to archive
+	Threshold      float64
+	// The minimal value of novelty threshold
+	MinThreshold   float64
+	// The behaviors stored in archive
+	Behaviors      []BehaviorDescriptor
+	// The distance metric between behaviors, the EuclideanMetric is used if not set
+	Metric         BehaviorMetric
+
+	// The initial novelty threshold restored by Reset
+	initThreshold  float64
+	// The number of consecutive generations without additions to archive
+	stagnation     int
+}
+
+// Creates new novelty archive with given number of nearest neighbors and initial novelty threshold
+func NewNoveltyArchive(k int, threshold float64) *NoveltyArchive {
+	return &NoveltyArchive{K:k, Threshold:threshold, MinThreshold:threshold * 0.05, initThreshold:threshold}
+}
+
+// Returns the novelty of given behavior relative to other behaviors of population and behaviors in archive
+func (a *NoveltyArchive) Novelty(behavior BehaviorDescriptor, others []BehaviorDescriptor) float64 {
+	metric := a.Metric
+	if metric == nil {
+		metric = EuclideanMetric
+	}
+	distances := make([]float64, 0, len(others) + len(a.Behaviors))
+	for _, other := range others {
+		distances = append(distances, metric(behavior, other))
+	}
+	for _, other := range a.Behaviors {
+		distances = append(distances, metric(behavior, other))
+	}
+	if len(distances) == 0 {
+		return 0.0
+	}
+	sort.Float64s(distances)
+	k := a.K
+	if k <= 0 {
+		k = defaultNoveltyNeighbors
+	}
+	if k > len(distances) {
+		k = len(distances)
+	}
+	sum := 0.0
+	for _, d := range distances[:k] {
+		sum += d
+	}
+	return sum / float64(k)
+}
+
+// Evaluates novelty of each behavior of population relative to other behaviors of population and archive, adds novel
+// behaviors to archive and adjusts novelty threshold. Returns novelty of each behavior in the same order.
+func (a *NoveltyArchive) EvaluatePopulation(behaviors []BehaviorDescriptor) []float64 {
+	novelty := make([]float64, len(behaviors))
+	others := make([]BehaviorDescriptor, 0, len(behaviors))
+	for i, behavior := range behaviors {
+		others = append(others[:0], behaviors[:i]...)
+		others = append(others, behaviors[i + 1:]...)
+		novelty[i] = a.Novelty(behavior, others)
+	}
+
+	added := 0
+	for i, behavior := range behaviors {
+		if novelty[i] > a.Threshold {
+			a.Behaviors = append(a.Behaviors, copyBehavior(behavior))
+			added++
+		}
+	}
+	if added > noveltyAdditionsLimit {
+		a.Threshold *= 1.2
+	}
+	if added == 0 {
+		a.stagnation++
+	} else {
+		a.stagnation = 0
+	}
+	if a.stagnation >= noveltyStagnationGenerations {
+		a.Threshold = math.Max(a.Threshold * 0.95, a.MinThreshold)
+		a.stagnation = 0
+	}
+	return novelty
+}
+
+// Removes all behaviors from archive and restores initial novelty threshold, e.g. before new trial
+func (a *NoveltyArchive) Reset() {
+	a.Behaviors = nil
+	if a.initThreshold > 0 {
+		a.Threshold = a.initThreshold
+	}
+	a.stagnation = 0
+}
+
+// Returns the Euclidean distance between two behaviors, the missing values of shorter behavior are regarded as zeros
+func EuclideanDistance(a, b []float64) float64 {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	sum := 0.0
+	for i, v := range a {
+		d := v
+		if i < len(b) {
+			d -= b[i]
+		}
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,91 @@
+package experiments
+
+import (
+	"testing"
+	"math"
+)
+
+func TestEuclideanDistance(t *testing.T) {
+	if d := EuclideanDistance([]float64{0.0, 0.0}, []float64{3.0, 4.0}); d != 5.0 {
+		t.Error("d != 5.0", d)
+	}
+	// the missing values are zeros
+	if d := EuclideanDistance([]float64{3.0}, []float64{0.0, 4.0}); d != 5.0 {
+		t.Error("d != 5.0", d)
+	}
+}
+
+func TestNoveltyArchive_Novelty(t *testing.T) {
+	archive := NewNoveltyArchive(2, 1.0)
+	others := []BehaviorDescriptor{VectorBehavior{1.0}, VectorBehavior{3.0}, VectorBehavior{10.0}}
+	// the average distance to two nearest neighbors
+	if n := archive.Novelty(VectorBehavior{0.0}, others); n != 2.0 {
+		t.Error("n != 2.0", n)
+	}
+	// the archive behaviors are neighbors as well
+	archive.Behaviors = []BehaviorDescriptor{VectorBehavior{0.0}}
+	if n := archive.Novelty(VectorBehavior{0.0}, others); n != 0.5 {
+		t.Error("n != 0.5", n)
+	}
+	// less neighbors than K
+	archive.Behaviors = nil
+	if n := archive.Novelty(VectorBehavior{0.0}, others[:1]); n != 1.0 {
+		t.Error("n != 1.0", n)
+	}
+	if n := archive.Novelty(VectorBehavior{0.0}, nil); n != 0.0 {
+		t.Error("n != 0.0", n)
+	}
+}
+
+func TestNoveltyArchive_EvaluatePopulation(t *testing.T) {
+	archive := NewNoveltyArchive(1, 2.0)
+	vectors := []VectorBehavior{{0.0}, {1.0}, {10.0}}
+	behaviors := []BehaviorDescriptor{vectors[0], vectors[1], vectors[2]}
+	novelty := archive.EvaluatePopulation(behaviors)
+	expected := []float64{1.0, 1.0, 9.0}
+	for i, n := range novelty {
+		if n != expected[i] {
+			t.Error("Wrong novelty at:", i, n, expected[i])
+		}
+	}
+	// only novel behavior added
+	if len(archive.Behaviors) != 1 || archive.Behaviors[0].Values()[0] != 10.0 {
+		t.Error("Wrong archive behaviors", archive.Behaviors)
+	}
+	vectors[2][0] = 11.0
+	if archive.Behaviors[0].Values()[0] != 10.0 {
+		t.Error("Archive should hold copy of behavior")
+	}
+
+	// the threshold is raised when too many behaviors added
+	behaviors = make([]BehaviorDescriptor, noveltyAdditionsLimit + 1)
+	for i := range behaviors {
+		behaviors[i] = VectorBehavior{float64(i) * 100.0 + 1000.0}
+	}
+	archive.EvaluatePopulation(behaviors)
+	if archive.Threshold != 2.4 {
+		t.Error("archive.Threshold != 2.4", archive.Threshold)
+	}
+
+	// the threshold is lowered after stagnation
+	for i := 0; i < noveltyStagnationGenerations; i++ {
+		archive.EvaluatePopulation([]BehaviorDescriptor{VectorBehavior{0.0}, VectorBehavior{0.0}})
+	}
+	if math.Abs(archive.Threshold - 2.28) > 1e-9 {
+		t.Error("archive.Threshold != 2.28", archive.Threshold)
+	}
+
+	archive.Reset()
+	if len(archive.Behaviors) != 0 || archive.Threshold != 2.0 {
+		t.Error("Archive should be reset", len(archive.Behaviors), archive.Threshold)
+	}
+}
+
+func TestNoveltyArchive_Novelty_metric(t *testing.T) {
+	archive := NewNoveltyArchive(1, 1.0)
+	archive.Metric = HammingMetric
+	others := []BehaviorDescriptor{StringBehavior("abcd"), StringBehavior("xyzw")}
+	if n := archive.Novelty(StringBehavior("abzz"), others); n != 2.0 {
+		t.Error("n != 2.0", n)
+	}
+}
@@ -66,7 +66,9 @@ func (ex CartDoublePoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Pop
 
 	// Evaluate each organism on a test
 	for _, org := range pop.Organisms {
-		winner, err := ex.orgEvaluate(org, cartPole)
+		winner, err := experiments.EvaluateWithErrorPolicy(org, func(org *genetics.Organism) (bool, error) {
+			return ex.orgEvaluate(org, cartPole)
+		}, context)
 		if err != nil {
 			return err
 		}
@@ -99,7 +101,7 @@ func (ex CartDoublePoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Pop
 		// Sort the species by max organism fitness in descending order - the highest fitness first
 		sorted_species := make([]*genetics.Species, len(pop.Species))
 		copy(sorted_species, pop.Species)
-		sort.Sort(sort.Reverse(genetics.ByOrganismFitness(sorted_species)))
+		sort.Stable(sort.Reverse(genetics.ByOrganismFitness(sorted_species)))
 
 		// First update what is checked and unchecked
 		var curr_species *genetics.Species
@@ -161,7 +163,11 @@ func (ex CartDoublePoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Pop
 							// The champion needs to be flushed here because it may have
 							// leftover activation from its last test run that could affect
 							// its recurrent memory
-							champion.Phenotype.Flush()
+							if phenotype, err := champion.Phenotype(); err != nil {
+								return err
+							} else {
+								phenotype.Flush()
+							}
 
 							if generalized, err := ex.orgEvaluate(champion, cartPole); generalized {
 								generalization_score++
@@ -224,8 +230,12 @@ func (ex CartDoublePoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Pop
 		for _, org := range pop.Organisms {
 			if org.IsWinner {
 				// Prints the winner organism to file!
+				phenotype, err := org.Phenotype()
+				if err != nil {
+					return err
+				}
 				org_path := fmt.Sprintf("%s/%s_%.1f_%d-%d", experiments.OutDirForTrial(ex.OutputPath, epoch.TrialId),
-					"pole2_winner", org.Fitness, org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
+					"pole2_winner", org.Fitness, phenotype.NodeCount(), phenotype.LinkCount())
 				file, err := os.Create(org_path)
 				if err != nil {
 					neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
@@ -244,7 +254,11 @@ func (ex CartDoublePoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Pop
 // This methods evaluates provided organism for cart double pole-balancing task
 func (ex *CartDoublePoleGenerationEvaluator) orgEvaluate(organism *genetics.Organism, cartPole *CartPole) (winner bool, err error) {
 	// Try to balance a pole now
-	organism.Fitness, err = cartPole.evalNet(organism.Phenotype, ex.ActionType)
+	phenotype, err := organism.Phenotype()
+	if err != nil {
+		return false, err
+	}
+	organism.Fitness, err = cartPole.evalNet(phenotype, ex.ActionType)
 	if err != nil {
 		return false, err
 	}
@@ -28,7 +28,12 @@ type CartPoleGenerationEvaluator struct {
 func (ex CartPoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiments.Generation, context *neat.NeatContext) (err error) {
 	// Evaluate each organism on a test
 	for _, org := range pop.Organisms {
-		res := ex.orgEvaluate(org)
+		res, err := experiments.EvaluateWithErrorPolicy(org, func(org *genetics.Organism) (bool, error) {
+			return ex.orgEvaluate(org, context)
+		}, context)
+		if err != nil {
+			return err
+		}
 
 		if res && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness){
 			epoch.Solved = true
@@ -38,8 +43,12 @@ func (ex CartPoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Populatio
 			epoch.Best = org
 			if (epoch.WinnerNodes == 7) {
 				// You could dump out optimal genomes here if desired
+				phenotype, err := org.Phenotype()
+				if err != nil {
+					return err
+				}
 				opt_path := fmt.Sprintf("%s/%s_%d-%d", experiments.OutDirForTrial(ex.OutputPath, epoch.TrialId),
-					"pole1_optimal", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
+					"pole1_optimal", phenotype.NodeCount(), phenotype.LinkCount())
 				file, err := os.Create(opt_path)
 				if err != nil {
 					neat.ErrorLog(fmt.Sprintf("Failed to dump optimal genome, reason: %s\n", err))
@@ -70,8 +79,12 @@ func (ex CartPoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Populatio
 		for _, org := range pop.Organisms {
 			if org.IsWinner {
 				// Prints the winner organism to file!
+				phenotype, err := org.Phenotype()
+				if err != nil {
+					return err
+				}
 				org_path := fmt.Sprintf("%s/%s_%d-%d", experiments.OutDirForTrial(ex.OutputPath, epoch.TrialId),
-					"pole1_winner", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
+					"pole1_winner", phenotype.NodeCount(), phenotype.LinkCount())
 				file, err := os.Create(org_path)
 				if err != nil {
 					neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
@@ -87,10 +100,15 @@ func (ex CartPoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Populatio
 	return err
 }
 
-// This methods evaluates provided organism for cart pole balancing task
-func (ex *CartPoleGenerationEvaluator) orgEvaluate(organism *genetics.Organism) bool {
+// This methods evaluates provided organism for cart pole balancing task, the random start state is drawn from evaluation
+// stream of organism (see genetics.Organism.Rand)
+func (ex *CartPoleGenerationEvaluator) orgEvaluate(organism *genetics.Organism, context *neat.NeatContext) (bool, error) {
+	phenotype, err := organism.Phenotype()
+	if err != nil {
+		return false, err
+	}
 	// Try to balance a pole now
-	organism.Fitness = float64(ex.runCart(organism.Phenotype))
+	organism.Fitness = float64(ex.runCart(phenotype, organism.Rand(context)))
 
 	if neat.LogLevel == neat.LogLevelDebug {
 		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f", organism.Genotype.Id, organism.Fitness))
@@ -115,21 +133,22 @@ func (ex *CartPoleGenerationEvaluator) orgEvaluate(organism *genetics.Organism)
 		organism.Fitness = 1.0 - organism.Error
 	}
 
-	return organism.IsWinner
+	return organism.IsWinner, nil
 }
 
-// run cart emulation and return number of emulation steps pole was balanced
-func (ex *CartPoleGenerationEvaluator) runCart(net *network.Network) (steps int) {
+// run cart emulation and return number of emulation steps pole was balanced, the random start state is drawn from
+// provided generator
+func (ex *CartPoleGenerationEvaluator) runCart(net *network.Network, rng *rand.Rand) (steps int) {
 	var x float64           /* cart position, meters */
 	var x_dot float64       /* cart velocity */
 	var theta float64       /* pole angle, radians */
 	var theta_dot float64   /* pole angular velocity */
 	if ex.RandomStart {
 		/*set up random start state*/
-		x = float64(rand.Int31() % 4800) / 1000.0 - 2.4
-		x_dot = float64(rand.Int31() % 2000) / 1000.0 - 1
-		theta = float64(rand.Int31() % 400) / 1000.0 - .2
-		theta_dot = float64(rand.Int31() % 3000) / 1000.0 - 1.5
+		x = float64(rng.Int31() % 4800) / 1000.0 - 2.4
+		x_dot = float64(rng.Int31() % 2000) / 1000.0 - 1
+		theta = float64(rng.Int31() % 400) / 1000.0 - .2
+		theta_dot = float64(rng.Int31() % 3000) / 1000.0 - 1.5
 	}
 
 	in := make([]float64, 5)
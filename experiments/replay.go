@@ -0,0 +1,126 @@
+package experiments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The recorded episode of evaluation, i.e. the sequence of inputs loaded into network sensors at each step, the
+// outputs of network produced for them and rewards received from environment
+type Episode struct {
+	// The name of episode, e.g. the name of environment or test case
+	Name    string      `json:"name,omitempty"`
+	// The inputs of network at each step
+	Inputs  [][]float64 `json:"inputs"`
+	// The outputs of network at each step
+	Outputs [][]float64 `json:"outputs"`
+	// The rewards received at each step, may be empty if episode was not recorded in environment
+	Rewards []float64   `json:"rewards,omitempty"`
+}
+
+// Returns the number of steps of episode
+func (e *Episode) Len() int {
+	return len(e.Inputs)
+}
+
+// Writes provided episodes into JSON document
+func WriteEpisodes(w io.Writer, episodes []*Episode) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(episodes)
+}
+
+// Reads episodes from JSON document written by WriteEpisodes. Returns error if any episode has different number of
+// inputs and outputs.
+func ReadEpisodes(r io.Reader) ([]*Episode, error) {
+	episodes := make([]*Episode, 0)
+	if err := json.NewDecoder(r).Decode(&episodes); err != nil {
+		return nil, err
+	}
+	for i, e := range episodes {
+		if len(e.Inputs) != len(e.Outputs) {
+			return nil, errors.New(fmt.Sprintf("The episode [%d] has %d inputs and %d outputs", i, len(e.Inputs),
+				len(e.Outputs)))
+		}
+	}
+	return episodes, nil
+}
+
+// The result of episode replay
+type ReplayResult struct {
+	// The outputs of network at each replayed step
+	Outputs      [][]float64
+	// The maximal absolute deviation of outputs from recorded ones over all steps
+	MaxDeviation float64
+	// The first step where deviation of outputs exceeded tolerance, or -1 if trajectory matches recorded one
+	DivergedStep int
+}
+
+// Returns true if replayed output trajectory matches recorded one within tolerance
+func (r *ReplayResult) Matches() bool {
+	return r.DivergedStep < 0
+}
+
+// The runner of recorded episodes which feeds recorded inputs to network step by step and compares its output
+// trajectory with recorded one. The replay is open loop, i.e. environment is not involved and the inputs stay the
+// same even if network outputs diverge, thus the replay is deterministic.
+type ReplayRunner struct {
+	// The maximal number of activation attempts per step, zero means default
+	ActivationSteps int
+	// The maximal absolute deviation of each output from recorded value still considered as match
+	Tolerance       float64
+}
+
+// Replays episode with provided network, the network is flushed before replay. Returns error if network failed to
+// activate or produced different number of outputs than recorded.
+func (r ReplayRunner) Replay(net *network.Network, episode *Episode) (*ReplayResult, error) {
+	if _, err := net.Flush(); err != nil {
+		return nil, err
+	}
+	activation_steps := r.ActivationSteps
+	if activation_steps <= 0 {
+		activation_steps = defaultEpisodeActivationSteps
+	}
+	res := &ReplayResult{Outputs:make([][]float64, episode.Len()), DivergedStep:-1}
+	for step, inputs := range episode.Inputs {
+		if err := net.LoadSensors(inputs); err != nil {
+			return nil, err
+		}
+		if ok, err := net.ActivateSteps(activation_steps); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, errors.New(fmt.Sprintf("Failed to activate network at replay step: %d", step))
+		}
+		outputs := net.ReadOutputs()
+		if len(outputs) != len(episode.Outputs[step]) {
+			return nil, errors.New(fmt.Sprintf("The network has %d outputs, recorded: %d at replay step: %d",
+				len(outputs), len(episode.Outputs[step]), step))
+		}
+		for i, out := range outputs {
+			deviation := math.Abs(out - episode.Outputs[step][i])
+			if math.IsNaN(deviation) {
+				deviation = math.Inf(1)
+			}
+			res.MaxDeviation = math.Max(res.MaxDeviation, deviation)
+			if deviation > r.Tolerance && res.DivergedStep < 0 {
+				res.DivergedStep = step
+			}
+		}
+		res.Outputs[step] = outputs
+	}
+	return res, nil
+}
+
+// Replays episode with network built from provided genome, e.g. the champion genome loaded from file
+func (r ReplayRunner) ReplayGenome(genome *genetics.Genome, episode *Episode) (*ReplayResult, error) {
+	net, err := genome.Genesis(genome.Id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Replay(net, episode)
+}
@@ -0,0 +1,114 @@
+package experiments
+
+import (
+	"testing"
+	"bytes"
+	"math/rand"
+	"strings"
+)
+
+func TestEpisodeRunner_RecordEpisode(t *testing.T) {
+	rand.Seed(42)
+	org := buildEnvironmentOrganism(t)
+	if org == nil {
+		return
+	}
+	evaluator := EnvironmentEvaluator{NewEnvironment:func() Environment {
+		return &rewardEnvironment{length:4}
+	}}
+	episode, err := evaluator.RecordEpisode(org)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if episode.Len() != 4 || len(episode.Outputs) != 4 || len(episode.Rewards) != 4 {
+		t.Error("Wrong episode length", episode.Len(), len(episode.Outputs), len(episode.Rewards))
+		return
+	}
+	// the initial observations and observations after steps are recorded
+	if episode.Inputs[0][0] != 0.5 || episode.Inputs[3][0] != 3.0 {
+		t.Error("Wrong recorded inputs", episode.Inputs)
+	}
+	for i, out := range episode.Outputs {
+		if len(out) != 1 || out[0] != episode.Rewards[i] {
+			t.Error("Wrong recorded outputs at step", i, out, episode.Rewards[i])
+		}
+	}
+
+	if _, err = (&EnvironmentEvaluator{}).RecordEpisode(org); err == nil {
+		t.Error("Error expected for missing environment factory")
+	}
+}
+
+func TestReplayRunner_ReplayGenome(t *testing.T) {
+	rand.Seed(42)
+	org := buildEnvironmentOrganism(t)
+	if org == nil {
+		return
+	}
+	net, _ := org.Phenotype()
+	episode, err := EpisodeRunner{}.RecordEpisode(net, &rewardEnvironment{length:5})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the same genome reproduces recorded trajectory
+	res, err := ReplayRunner{}.ReplayGenome(org.Genotype, episode)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !res.Matches() || res.MaxDeviation != 0 || len(res.Outputs) != 5 {
+		t.Error("The replay should match recorded episode", res.DivergedStep, res.MaxDeviation)
+	}
+
+	// the changed genome diverges
+	changed, err := org.Genotype.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	changed.Genes[0].Link.Weight += 5.0
+	if res, err = (ReplayRunner{Tolerance:1e-6}).ReplayGenome(changed, episode); err != nil {
+		t.Error(err)
+		return
+	}
+	if res.Matches() || res.DivergedStep != 0 || res.MaxDeviation <= 1e-6 {
+		t.Error("The replay should diverge at the first step", res.DivergedStep, res.MaxDeviation)
+	}
+	// the large tolerance accepts any deviation
+	if res, _ = (ReplayRunner{Tolerance:1.0}).ReplayGenome(changed, episode); !res.Matches() {
+		t.Error("The replay should match within tolerance", res.MaxDeviation)
+	}
+
+	episode.Outputs[2] = []float64{0.5, 0.5}
+	if _, err = (ReplayRunner{}).ReplayGenome(org.Genotype, episode); err == nil {
+		t.Error("Error expected for wrong number of recorded outputs")
+	}
+}
+
+func TestWriteEpisodes(t *testing.T) {
+	episodes := []*Episode{
+		{Name:"first", Inputs:[][]float64{{1, 2}, {3, 4}}, Outputs:[][]float64{{0.5}, {0.25}}, Rewards:[]float64{1, 2}},
+		{Inputs:[][]float64{{0, 0}}, Outputs:[][]float64{{1}}},
+	}
+	buf := bytes.NewBufferString("")
+	if err := WriteEpisodes(buf, episodes); err != nil {
+		t.Error(err)
+		return
+	}
+	read, err := ReadEpisodes(buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(read) != 2 || read[0].Name != "first" || read[0].Len() != 2 || read[0].Inputs[1][1] != 4 ||
+		read[0].Outputs[1][0] != 0.25 || read[0].Rewards[1] != 2 || read[1].Len() != 1 || len(read[1].Rewards) != 0 {
+		t.Error("Wrong episodes read", read)
+	}
+
+	if _, err = ReadEpisodes(strings.NewReader(`[{"inputs":[[1]],"outputs":[]}]`)); err == nil {
+		t.Error("Error expected for different number of inputs and outputs")
+	}
+}
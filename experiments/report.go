@@ -0,0 +1,249 @@
+package experiments
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The size of charts in HTML report in pixels
+const (
+	reportChartWidth = 640
+	reportChartHeight = 240
+	reportChartMargin = 40
+)
+
+// The data series to be drawn as line in the chart of HTML report
+type reportSeries struct {
+	name   string
+	color  string
+	values Floats
+}
+
+// The statistics of one species at the end of trial
+type reportSpecies struct {
+	Age, Complexity, Fitness float64
+}
+
+// The configuration parameter of execution context
+type reportParam struct {
+	Name, Value string
+}
+
+// The data to fill the HTML report template
+type trialReport struct {
+	Title           string
+	Solved          bool
+	Generations     int
+	Evaluations     int
+	Duration        string
+	BestFitness     float64
+	Validated       bool
+	BestValidation  float64
+	FitnessChart    template.HTML
+	ComplexityChart template.HTML
+	SpeciesChart    template.HTML
+	Champion        template.HTML
+	ChampionGenome  string
+	Species         []reportSpecies
+	Config          []reportParam
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #252525; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #bdbdbd; padding: 2px 8px; text-align: left; }
+pre { background: #f5f5f5; padding: 1em; overflow: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><th>Solved</th><td>{{.Solved}}</td></tr>
+<tr><th>Generations</th><td>{{.Generations}}</td></tr>
+<tr><th>Evaluations</th><td>{{.Evaluations}}</td></tr>
+<tr><th>Duration</th><td>{{.Duration}}</td></tr>
+<tr><th>Best fitness</th><td>{{printf "%.4f" .BestFitness}}</td></tr>
+{{if .Validated}}<tr><th>Best validation fitness</th><td>{{printf "%.4f" .BestValidation}}</td></tr>
+{{end}}</table>
+<h2>Fitness</h2>
+{{.FitnessChart}}
+<h2>Complexity</h2>
+{{.ComplexityChart}}
+<h2>Species</h2>
+{{.SpeciesChart}}
+<h3>Species at the last generation</h3>
+<table>
+<tr><th>#</th><th>Age</th><th>Complexity</th><th>Fitness</th></tr>
+{{range $i, $s := .Species}}<tr><td>{{$i}}</td><td>{{$s.Age}}</td><td>{{$s.Complexity}}</td><td>{{printf "%.4f" $s.Fitness}}</td></tr>
+{{end}}</table>
+<h2>Champion</h2>
+{{if .Champion}}{{.Champion}}{{else}}<p>The champion network can not be rendered</p>{{end}}
+<pre>{{.ChampionGenome}}</pre>
+<h2>Configuration</h2>
+<table>
+{{range .Config}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// Writes standalone HTML report about this trial into provided writer. The report includes the charts of fitness,
+// complexity and number of species per generation, the rendering of champion network and its genome, as well as
+// configuration parameters of given context. The charts are drawn as inline SVG, thus the report can be viewed by any
+// web browser without external resources.
+func (t *Trial) WriteHTMLReport(w io.Writer, context *neat.NeatContext) error {
+	if len(t.Generations) == 0 {
+		return errors.New(fmt.Sprintf("Trial [%d] has no generations to report", t.Id))
+	}
+	avg_fitness, _, avg_complexity := t.Average()
+	report := trialReport{
+		Title:fmt.Sprintf("Trial %d", t.Id),
+		Solved:t.Solved(),
+		Generations:len(t.Generations),
+		Evaluations:t.Evaluations(),
+		Duration:t.Duration.String(),
+		ComplexityChart:svgLineChart("complexity",
+			reportSeries{name:"best", color:"#1f77b4", values:t.BestComplexity()},
+			reportSeries{name:"average", color:"#ff7f0e", values:avg_complexity}),
+		SpeciesChart:svgLineChart("species",
+			reportSeries{name:"number of species", color:"#2ca02c", values:t.Diversity()}),
+	}
+
+	fitness_series := []reportSeries{
+		{name:"best", color:"#1f77b4", values:t.BestFitness()},
+		{name:"average", color:"#ff7f0e", values:avg_fitness},
+	}
+	if epoch, ok := t.BestValidatedGeneration(); ok {
+		report.Validated, report.BestValidation = true, epoch.ValidationFitness
+		fitness_series = append(fitness_series,
+			reportSeries{name:"best validation", color:"#d62728", values:t.BestValidationFitness()})
+	}
+	report.FitnessChart = svgLineChart("fitness", fitness_series...)
+
+	last := t.Generations[len(t.Generations) - 1]
+	for i := range last.Fitness {
+		report.Species = append(report.Species, reportSpecies{
+			Age:last.Age[i], Complexity:last.Compexity[i], Fitness:last.Fitness[i]})
+	}
+
+	if champion, ok := t.BestOrganism(false); ok {
+		report.BestFitness = champion.Fitness
+		if phenotype, err := champion.Phenotype(); err == nil {
+			var buf bytes.Buffer
+			if err = phenotype.RenderSVG(&buf, nil); err == nil {
+				report.Champion = template.HTML(buf.String())
+			}
+		}
+		var buf bytes.Buffer
+		if err := champion.Genotype.Write(&buf); err != nil {
+			return err
+		}
+		report.ChampionGenome = buf.String()
+	}
+
+	if context != nil {
+		value := reflect.ValueOf(context).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.PkgPath == "" {
+				report.Config = append(report.Config, reportParam{
+					Name:field.Name, Value:fmt.Sprint(value.Field(i).Interface())})
+			}
+		}
+	}
+
+	return reportTemplate.Execute(w, report)
+}
+
+// Writes HTML report (see Trial.WriteHTMLReport) of each trial of this experiment into report.html file in the output
+// directory of trial
+func (ex *Experiment) WriteHTMLReports(out_dir string, context *neat.NeatContext) error {
+	for i := range ex.Trials {
+		trial := &ex.Trials[i]
+		path := fmt.Sprintf("%s/report.html", OutDirForTrial(out_dir, trial.Id))
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		err = trial.WriteHTMLReport(file, context)
+		if c_err := file.Close(); err == nil {
+			err = c_err
+		}
+		if err != nil {
+			return err
+		}
+		neat.InfoLog(fmt.Sprintf("Trial [%d] report written to: %s\n", trial.Id, path))
+	}
+	return nil
+}
+
+// Returns SVG line chart with given title and data series drawn against generation index
+func svgLineChart(title string, series ...reportSeries) template.HTML {
+	min_value, max_value := math.Inf(1), math.Inf(-1)
+	count := 0
+	for _, s := range series {
+		// the Min and Max of Floats sort values, thus can not be used here
+		for _, v := range s.values {
+			min_value, max_value = math.Min(min_value, v), math.Max(max_value, v)
+		}
+		if len(s.values) > count {
+			count = len(s.values)
+		}
+	}
+	if count == 0 {
+		min_value, max_value = 0, 0
+	}
+	if max_value == min_value {
+		min_value, max_value = min_value - 1, max_value + 1
+	}
+	plot_width := float64(reportChartWidth - 2 * reportChartMargin)
+	plot_height := float64(reportChartHeight - 2 * reportChartMargin)
+	x := func(i int) float64 {
+		if count < 2 {
+			return reportChartMargin + plot_width / 2
+		}
+		return reportChartMargin + plot_width * float64(i) / float64(count - 1)
+	}
+	y := func(v float64) float64 {
+		return reportChartMargin + plot_height * (max_value - v) / (max_value - min_value)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\">\n",
+		reportChartWidth, reportChartHeight)
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	// axes with value range and generations labels
+	fmt.Fprintf(&b, "<polyline points=\"%d,%d %d,%d %d,%d\" fill=\"none\" stroke=\"#252525\"/>\n",
+		reportChartMargin, reportChartMargin, reportChartMargin, reportChartHeight - reportChartMargin,
+		reportChartWidth - reportChartMargin, reportChartHeight - reportChartMargin)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"10\" text-anchor=\"end\">%.4g</text>\n",
+		reportChartMargin - 4, reportChartMargin, max_value)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"10\" text-anchor=\"end\">%.4g</text>\n",
+		reportChartMargin - 4, reportChartHeight - reportChartMargin, min_value)
+	fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"10\" text-anchor=\"end\">generation %d</text>\n",
+		reportChartWidth - reportChartMargin, reportChartHeight - reportChartMargin + 14, count - 1)
+	for i, s := range series {
+		fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"%s\" stroke-width=\"1.5\" points=\"", s.color)
+		for j, v := range s.values {
+			fmt.Fprintf(&b, "%.1f,%.1f ", x(j), y(v))
+		}
+		fmt.Fprintln(&b, "\"/>")
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"12\" fill=\"%s\">%s</text>\n",
+			reportChartMargin + i * 150, reportChartMargin - 12, s.color, html.EscapeString(s.name))
+	}
+	fmt.Fprintln(&b, "</svg>")
+
+	return template.HTML(b.String())
+}
@@ -0,0 +1,103 @@
+package experiments
+
+import (
+	"testing"
+	"bytes"
+	"math/rand"
+	"os"
+	"strings"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+func TestTrial_WriteHTMLReport(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumRuns = 1
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, &randomGenerationEvaluator{}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = experiment.Trials[0].WriteHTMLReport(&buf, context); err != nil {
+		t.Error(err)
+		return
+	}
+	report := buf.String()
+	for _, expected := range []string{"<html>", "Trial 0", "<title>fitness</title>", "<title>species</title>",
+		"<title>network ", "genomestart", "PopSize"} {
+		if !strings.Contains(report, expected) {
+			t.Error("Report should contain", expected)
+		}
+	}
+
+	if strings.Contains(report, "Best validation fitness") {
+		t.Error("Report should not contain validation fitness of not validated trial")
+	}
+	trial := experiment.Trials[0]
+	trial.Generations[0].ValidationFitness, trial.Generations[0].Validated = 0.75, true
+	buf.Reset()
+	if err = trial.WriteHTMLReport(&buf, context); err != nil {
+		t.Error(err)
+		return
+	}
+	if report = buf.String(); !strings.Contains(report, "Best validation fitness</th><td>0.7500") {
+		t.Error("Report should contain validation fitness")
+	}
+
+	empty := Trial{Id:1}
+	if err = empty.WriteHTMLReport(&buf, context); err == nil {
+		t.Error("Error expected for trial without generations")
+	}
+}
+
+func TestExperiment_WriteHTMLReports(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, &randomGenerationEvaluator{}); err != nil {
+		t.Error(err)
+		return
+	}
+
+	out_dir := t.TempDir()
+	if err = experiment.WriteHTMLReports(out_dir, context); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, trial := range experiment.Trials {
+		if _, err = os.Stat(OutDirForTrial(out_dir, trial.Id) + "/report.html"); err != nil {
+			t.Error("Report of trial not found", trial.Id, err)
+		}
+	}
+}
+
+func TestSvgLineChart(t *testing.T) {
+	values := Floats{3.0, 1.0, 2.0}
+	chart := string(svgLineChart("test <chart>", reportSeries{name:"values", color:"#000000", values:values}))
+	if values[0] != 3.0 || values[1] != 1.0 || values[2] != 2.0 {
+		t.Error("Chart data should not be modified", values)
+	}
+	if !strings.Contains(chart, "<title>test &lt;chart&gt;</title>") {
+		t.Error("Chart title should be escaped", chart)
+	}
+	if !strings.Contains(chart, "40.0,40.0 320.0,200.0 600.0,120.0") {
+		t.Error("Wrong chart points", chart)
+	}
+
+	// the chart of empty series should be rendered as well
+	if chart = string(svgLineChart("empty")); !strings.HasSuffix(chart, "</svg>\n") {
+		t.Error("Empty chart expected", chart)
+	}
+}
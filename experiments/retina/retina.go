@@ -0,0 +1,262 @@
+// The retina experiment is the standard benchmark of modularity (Kashtan and Alon, 2005; Clune et al., 2013). The
+// network sees the artificial retina of eight pixels split into the left and the right halves of four pixels each
+// (2x2). Each half may show an object, i.e. one of the eight predefined patterns of its side. The network should
+// answer whether objects are shown on both sides (L-AND-R) or at least on one side (L-OR-R). The task is decomposable
+// into two independent subproblems, thus the modular network solving each half separately is natural solution, which
+// makes the experiment suitable to evaluate modularity-promoting features.
+package retina
+
+import (
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/experiments"
+	"fmt"
+	"math"
+	"os"
+)
+
+// The logical function of objects presence at the left and the right halves of retina which network should compute
+type RetinaTask byte
+
+// The supported retina tasks
+const (
+	// The objects should be present at both halves of retina
+	AndRetinaTask RetinaTask = iota
+	// The object should be present at least at one half of retina
+	OrRetinaTask
+)
+
+// The number of pixels in each half of retina
+const halfPixels = 4
+
+// The number of all patterns of retina
+const patternsCount = 1 << (2 * halfPixels)
+
+// The key of organism tag holding separation score of its network (see SeparationScore)
+const SeparationTag = "retina_separation"
+
+// The left objects as 2x2 patterns encoded row by row, i.e. bits are top left, top right, bottom left and bottom right
+// pixels from the most significant bit. The right objects are mirror images of the left ones, thus only symmetric
+// patterns are objects at both sides.
+var leftObjects = []int{
+	0x7, // 0111
+	0x2, // 0010
+	0xd, // 1101
+	0x8, // 1000
+	0xf, // 1111
+	0x5, // 0101
+	0x3, // 0011
+	0x9, // 1001
+}
+
+// The right objects produced by horizontal mirroring of left objects
+var rightObjects = mirrorPatterns(leftObjects)
+
+// The retina experiment evaluator
+type RetinaGenerationEvaluator struct {
+	// The output path to store execution results
+	OutputPath string
+	// The task to be solved
+	Task       RetinaTask
+}
+
+// This method evaluates one epoch for given population and prints results into output directory if any.
+func (ex RetinaGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiments.Generation, context *neat.NeatContext) (err error) {
+	for _, org := range pop.Organisms {
+		res, err := experiments.EvaluateWithErrorPolicy(org, ex.orgEvaluate, context)
+		if err != nil {
+			return err
+		}
+		if res && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness) {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(org.Genotype.Nodes)
+			epoch.WinnerGenes = org.Genotype.Extrons()
+			epoch.WinnerEvals = context.PopSize * epoch.Id + org.Genotype.Id
+			epoch.Best = org
+		}
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+
+	if len(ex.OutputPath) == 0 {
+		return nil
+	}
+	out_dir := experiments.OutDirForTrial(ex.OutputPath, epoch.TrialId)
+	// Only print to file every print_every generations
+	if epoch.Solved || epoch.Id % context.PrintEvery == 0 {
+		pop_path := fmt.Sprintf("%s/gen_%d", out_dir, epoch.Id)
+		file, err := os.Create(pop_path)
+		if err != nil {
+			neat.ErrorLog(fmt.Sprintf("Failed to dump population, reason: %s\n", err))
+		} else {
+			pop.WriteBySpecies(file)
+			file.Close()
+		}
+	}
+
+	if epoch.Solved {
+		// print winner organism
+		org := epoch.Best
+		org_path := fmt.Sprintf("%s/%s_%d-%d", out_dir, "retina_winner", len(org.Genotype.Nodes), org.Genotype.Extrons())
+		file, err := os.Create(org_path)
+		if err != nil {
+			neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
+		} else {
+			org.Genotype.Write(file)
+			file.Close()
+			neat.InfoLog(fmt.Sprintf("Generation #%d winner dumped to: %s, separation: %f\n", epoch.Id, org_path,
+				org.Tags[SeparationTag]))
+		}
+	}
+	return nil
+}
+
+// This method evaluates provided organism against all patterns of retina. The fitness is 1 - mean absolute error of
+// outputs and organism is the winner if it classified all patterns correctly.
+func (ex RetinaGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool, error) {
+	phenotype, err := organism.Phenotype()
+	if err != nil {
+		return false, err
+	}
+	net_depth, err := phenotype.MaxDepth()
+	if err != nil {
+		neat.WarnLog(fmt.Sprintf("Failed to estimate maximal depth of the network with loop:\n%s\nUsing default depth: %d",
+			organism.Genotype, net_depth))
+	}
+	if net_depth == 0 {
+		net_depth = 1
+	}
+
+	error_sum, correct := 0.0, 0
+	in := make([]float64, 2 * halfPixels + 1)
+	for pattern := 0; pattern < patternsCount; pattern++ {
+		in[0] = 1.0 // Bias
+		for i := 0; i < 2 * halfPixels; i++ {
+			in[i + 1] = float64((pattern >> uint(2 * halfPixels - 1 - i)) & 1)
+		}
+		if err = phenotype.LoadSensors(in); err != nil {
+			return false, err
+		}
+		if _, err = phenotype.ForwardSteps(net_depth); err != nil {
+			return false, err
+		}
+		out := phenotype.Outputs[0].Activation
+		expected := 0.0
+		if ex.Expected(pattern) {
+			expected = 1.0
+		}
+		error_sum += math.Abs(out - expected)
+		if (out > 0.5) == (expected > 0.5) {
+			correct++
+		}
+		if _, err = phenotype.Flush(); err != nil {
+			return false, err
+		}
+	}
+
+	organism.Error = error_sum / float64(patternsCount)
+	organism.Fitness = 1.0 - organism.Error
+	organism.IsWinner = correct == patternsCount
+	if organism.Tags == nil {
+		organism.Tags = make(genetics.OrganismTags)
+	}
+	organism.Tags[SeparationTag] = SeparationScore(organism.Genotype)
+
+	if neat.LogLevel == neat.LogLevelDebug {
+		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f\tcorrect: %d", organism.Genotype.Id,
+			organism.Fitness, correct))
+	}
+	return organism.IsWinner, nil
+}
+
+// Returns true if network should respond positively to given pattern of retina. The pattern bits are the left half
+// pixels followed by the right half pixels from the most significant bit.
+func (ex RetinaGenerationEvaluator) Expected(pattern int) bool {
+	left := isObject((pattern >> halfPixels) & (1 << halfPixels - 1), leftObjects)
+	right := isObject(pattern & (1 << halfPixels - 1), rightObjects)
+	if ex.Task == OrRetinaTask {
+		return left || right
+	}
+	return left && right
+}
+
+// Returns the fraction of hidden nodes of genome which receive signals from only one half of retina among hidden nodes
+// receiving signals from any half through enabled links. The first four input nodes of genome regarded as the left
+// half and the next four as the right half. The perfectly modular network has score 1.0, the network without hidden
+// nodes has score 0.0.
+func SeparationScore(genome *genetics.Genome) float64 {
+	incoming := make(map[*network.NNode][]*network.NNode)
+	for _, gene := range genome.Genes {
+		if gene.IsEnabled {
+			incoming[gene.Link.OutNode] = append(incoming[gene.Link.OutNode], gene.Link.InNode)
+		}
+	}
+	sides := make(map[*network.NNode]int)
+	inputs := 0
+	for _, node := range genome.Nodes {
+		if node.NeuronType == network.InputNeuron {
+			if inputs < halfPixels {
+				sides[node] = 1
+			} else if inputs < 2 * halfPixels {
+				sides[node] = 2
+			}
+			inputs++
+		}
+	}
+
+	separated, connected := 0, 0
+	for _, node := range genome.Nodes {
+		if node.NeuronType != network.HiddenNeuron {
+			continue
+		}
+		side := reachingSides(node, incoming, sides, make(map[*network.NNode]bool))
+		if side != 0 {
+			connected++
+			if side != 3 {
+				separated++
+			}
+		}
+	}
+	if connected == 0 {
+		return 0.0
+	}
+	return float64(separated) / float64(connected)
+}
+
+// Returns the bit mask of retina halves which signals reach given node
+func reachingSides(node *network.NNode, incoming map[*network.NNode][]*network.NNode, sides map[*network.NNode]int,
+visited map[*network.NNode]bool) int {
+	if side, ok := sides[node]; ok {
+		return side
+	}
+	if visited[node] {
+		return 0
+	}
+	visited[node] = true
+	side := 0
+	for _, in := range incoming[node] {
+		side |= reachingSides(in, incoming, sides, visited)
+	}
+	return side
+}
+
+// Returns true if pattern is among provided objects
+func isObject(pattern int, objects []int) bool {
+	for _, o := range objects {
+		if o == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns horizontally mirrored 2x2 patterns, i.e. the left and the right columns are swapped
+func mirrorPatterns(patterns []int) []int {
+	res := make([]int, len(patterns))
+	for i, p := range patterns {
+		res[i] = (p & 0xa) >> 1 | (p & 0x5) << 1
+	}
+	return res
+}
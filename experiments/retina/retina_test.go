@@ -0,0 +1,142 @@
+package retina
+
+import (
+	"testing"
+	"math/rand"
+	"os"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func readStartGenome(t *testing.T) *genetics.Genome {
+	file, err := os.Open("../../data/retinastartgenes")
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	defer file.Close()
+	genome, err := genetics.ReadGenome(file, 1)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	return genome
+}
+
+func TestRetinaGenerationEvaluator_Expected(t *testing.T) {
+	and_count, or_count := 0, 0
+	for pattern := 0; pattern < patternsCount; pattern++ {
+		if (RetinaGenerationEvaluator{Task:AndRetinaTask}).Expected(pattern) {
+			and_count++
+		}
+		if (RetinaGenerationEvaluator{Task:OrRetinaTask}).Expected(pattern) {
+			or_count++
+		}
+	}
+	// eight objects of sixteen patterns at each side
+	if and_count != 64 {
+		t.Error("and_count != 64", and_count)
+	}
+	if or_count != 192 {
+		t.Error("or_count != 192", or_count)
+	}
+
+	// the left object 0111 with the right object 1011
+	if !(RetinaGenerationEvaluator{}).Expected(0x7b) {
+		t.Error("Objects expected at both sides")
+	}
+	// the left object 0111 is not the right object
+	if (RetinaGenerationEvaluator{}).Expected(0x77) {
+		t.Error("Object expected only at the left side")
+	}
+}
+
+func TestMirrorPatterns(t *testing.T) {
+	res := mirrorPatterns([]int{0x8, 0x1, 0xc, 0x6})
+	for i, expected := range []int{0x4, 0x2, 0xc, 0x9} {
+		if res[i] != expected {
+			t.Error("Wrong mirrored pattern at:", i, res[i], expected)
+		}
+	}
+}
+
+func TestSeparationScore(t *testing.T) {
+	genome := readStartGenome(t)
+	if genome == nil {
+		return
+	}
+	// no hidden nodes
+	if score := SeparationScore(genome); score != 0.0 {
+		t.Error("score != 0.0", score)
+	}
+
+	// the hidden node connected to the left pixels only and the hidden node connected to both sides
+	output := genome.Nodes[9]
+	left := network.NewNNode(11, network.HiddenNeuron)
+	both := network.NewNNode(12, network.HiddenNeuron)
+	genome.Nodes = append(genome.Nodes, left, both)
+	genome.Genes = append(genome.Genes,
+		genetics.NewGene(1.0, genome.Nodes[1], left, false, 100, 0),
+		genetics.NewGene(1.0, genome.Nodes[2], left, false, 101, 0),
+		genetics.NewGene(1.0, left, output, false, 102, 0),
+		genetics.NewGene(1.0, left, both, false, 103, 0),
+		genetics.NewGene(1.0, genome.Nodes[8], both, false, 104, 0),
+		genetics.NewGene(1.0, both, output, false, 105, 0))
+	if score := SeparationScore(genome); score != 0.5 {
+		t.Error("score != 0.5", score)
+	}
+
+	// the disabled link is not counted
+	genome.Genes[len(genome.Genes) - 2].IsEnabled = false
+	if score := SeparationScore(genome); score != 1.0 {
+		t.Error("score != 1.0", score)
+	}
+}
+
+func TestRetinaGenerationEvaluator_GenerationEvaluate(t *testing.T) {
+	rand.Seed(42)
+	start_genome := readStartGenome(t)
+	if start_genome == nil {
+		return
+	}
+	context := &neat.NeatContext{
+		PopSize:30,
+		CompatThreshold:3.0,
+		DropOffAge:15,
+		SurvivalThresh:0.2,
+		MutateOnlyProb:0.25,
+		MutateLinkWeightsProb:0.9,
+		MutateAddNodeProb:0.03,
+		MutateAddLinkProb:0.08,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		PrintEvery:2,
+		NumRuns:1,
+		NumGenerations:3,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	experiment := experiments.Experiment{}
+	evaluator := RetinaGenerationEvaluator{OutputPath:t.TempDir()}
+	if err := experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	generations := experiment.Trials[0].Generations
+	if len(generations) != 3 {
+		t.Error("Wrong number of generations", len(generations))
+		return
+	}
+	best := generations[len(generations) - 1].Best
+	// the fitness of best organism is adjusted by the next epoch, thus check its error
+	if best == nil || best.Error <= 0.0 || best.Error >= 1.0 {
+		t.Error("Wrong best organism", best)
+		return
+	}
+	if _, ok := best.Tags[SeparationTag].(float64); !ok {
+		t.Error("Separation score expected", best.Tags)
+	}
+}
@@ -0,0 +1,188 @@
+package experiments
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The values of one parameter of execution context explored by parameter sweep. The parameter is identified by its
+// name in plain text configuration file, e.g. "compat_threshold" or "pop_size" (see neat.NeatContext.SetParameter).
+type SweepParameter struct {
+	Name   string
+	Values []float64
+}
+
+// Returns values from min to max (inclusive) with given step to be explored by parameter sweep
+func SweepRange(min, max, step float64) []float64 {
+	if step <= 0 || max < min {
+		return []float64{min}
+	}
+	count := int(math.Floor((max - min) / step + 1e-9)) + 1
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = min + float64(i) * step
+	}
+	return values
+}
+
+// The factory of executors (see Experiment.Execute) to be used for trials of parameter sweep. The new executor is
+// created for each trial, because trials are run in parallel and executors usually hold the state of trial. The
+// provided context is the execution context of trial with swept parameters applied.
+type SweepExecutorFactory func(context *neat.NeatContext) (interface{}, error)
+
+// The parameter sweep runs given number of trials of experiment for each combination (grid) of values of execution
+// context parameters and collects statistics allowing to compare the combinations. It replaces hand-editing of
+// configuration files when tuning hyperparameters of NEAT.
+type Sweep struct {
+	// The explored parameters, each combination of their values is tried
+	Parameters  []SweepParameter
+	// The number of trials run for each combination of parameter values
+	Trials      int
+	// The maximal number of trials run in parallel, if not positive all trials are run in parallel
+	Parallelism int
+}
+
+// The results of trials run with one combination of values of swept parameters
+type SweepResult struct {
+	// The values of parameters in order of Sweep.Parameters
+	Values     []float64
+	// The experiment holding trials run with this combination
+	Experiment *Experiment
+}
+
+// Returns all combinations of values of swept parameters, the values of the last parameter change first
+func (s *Sweep) Combinations() [][]float64 {
+	combinations := [][]float64{{}}
+	for _, p := range s.Parameters {
+		next := make([][]float64, 0, len(combinations) * len(p.Values))
+		for _, c := range combinations {
+			for _, v := range p.Values {
+				next = append(next, append(append(make([]float64, 0, len(c) + 1), c...), v))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// Runs the parameter sweep, the population of each trial is spawned from provided start genome and evolved within
+// copy of given execution context with swept parameters applied. Returns results for each combination of parameter
+// values in order of Combinations.
+func (s *Sweep) Run(context *neat.NeatContext, start_genome *genetics.Genome,
+new_executor SweepExecutorFactory) ([]*SweepResult, error) {
+	return s.runCombinations(context, s.Combinations(), start_genome, new_executor)
+}
+
+// Runs trials for each of provided combinations of values of swept parameters
+func (s *Sweep) runCombinations(context *neat.NeatContext, combinations [][]float64, start_genome *genetics.Genome,
+new_executor SweepExecutorFactory) ([]*SweepResult, error) {
+	if s.Trials <= 0 {
+		return nil, errors.New(fmt.Sprintf("SWEEP: The number of trials should be positive: %d", s.Trials))
+	}
+	results := make([]*SweepResult, len(combinations))
+	contexts := make([]*neat.NeatContext, len(combinations))
+	for i, values := range combinations {
+		ctx, err := s.contextWithValues(context, values)
+		if err != nil {
+			return nil, err
+		}
+		contexts[i] = ctx
+		results[i] = &SweepResult{
+			Values:values,
+			Experiment:&Experiment{Id:i, Name:s.combinationName(values), Trials:make(Trials, s.Trials)},
+		}
+	}
+
+	parallelism := s.Parallelism
+	if parallelism <= 0 {
+		parallelism = len(combinations) * s.Trials
+	}
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var run_err error
+	for i := range combinations {
+		for run := 0; run < s.Trials; run++ {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(i, run int) {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+				if err := s.runTrial(results[i].Experiment, run, contexts[i], start_genome, new_executor); err != nil {
+					mutex.Lock()
+					if run_err == nil {
+						run_err = errors.New(fmt.Sprintf("SWEEP: Trial [%d] of [%s] failed, reason: %s",
+							run, results[i].Experiment.Name, err))
+					}
+					mutex.Unlock()
+				}
+			}(i, run)
+		}
+	}
+	wg.Wait()
+	return results, run_err
+}
+
+// Runs one trial of experiment storing it under given ID
+func (s *Sweep) runTrial(ex *Experiment, run int, context *neat.NeatContext, start_genome *genetics.Genome,
+new_executor SweepExecutorFactory) error {
+	executor, err := new_executor(context)
+	if err != nil {
+		return err
+	}
+	pop, err := genetics.NewPopulation(start_genome, context)
+	if err != nil {
+		return err
+	}
+	// each trial has its own slot in experiment, thus trials can be stored concurrently
+	ex.Trials[run], err = ex.executeTrial(run, 0, pop, context, executor)
+	return err
+}
+
+// Returns copy of given context with values of swept parameters applied
+func (s *Sweep) contextWithValues(context *neat.NeatContext, values []float64) (*neat.NeatContext, error) {
+	ctx := *context
+	for i, p := range s.Parameters {
+		if err := ctx.SetParameter(p.Name, values[i]); err != nil {
+			return nil, errors.New(fmt.Sprintf("SWEEP: %s", err))
+		}
+	}
+	return &ctx, nil
+}
+
+// Returns human readable name of combination of parameter values
+func (s *Sweep) combinationName(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%s=%g", s.Parameters[i].Name, v)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Writes table comparing provided results of this sweep: the values of parameters of each combination followed by
+// success rate, average number of evaluations, generations and duration per trial, and average best fitness.
+func (s *Sweep) WriteTable(w io.Writer, results []*SweepResult) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, p := range s.Parameters {
+		fmt.Fprintf(tw, "%s\t", p.Name)
+	}
+	fmt.Fprint(tw, "success rate\tavg evaluations\tavg generations\tavg duration\tavg best fitness\n")
+	for _, r := range results {
+		for _, v := range r.Values {
+			fmt.Fprintf(tw, "%g\t", v)
+		}
+		e := r.Experiment
+		fmt.Fprintf(tw, "%.2f\t%.1f\t%.1f\t%s\t%.4f\n", e.SuccessRate(), e.AvgEvaluationsPerTrial(),
+			e.AvgGenerationsPerTrial(), e.AvgTrialDuration(), e.BestFitness().Mean())
+	}
+	return tw.Flush()
+}
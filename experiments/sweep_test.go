@@ -0,0 +1,107 @@
+package experiments
+
+import (
+	"testing"
+	"bytes"
+	"strings"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+func TestSweepRange(t *testing.T) {
+	values := SweepRange(0.5, 1.5, 0.25)
+	expected := []float64{0.5, 0.75, 1.0, 1.25, 1.5}
+	if len(values) != len(expected) {
+		t.Error("Wrong number of values", values)
+		return
+	}
+	for i, v := range values {
+		if v != expected[i] {
+			t.Error("Wrong value at", i, v, expected[i])
+		}
+	}
+	if values = SweepRange(1.0, 0.0, 0.1); len(values) != 1 || values[0] != 1.0 {
+		t.Error("Only minimal value expected for empty range", values)
+	}
+}
+
+func TestSweep_Combinations(t *testing.T) {
+	sweep := Sweep{Parameters:[]SweepParameter{
+		{Name:"compat_threshold", Values:[]float64{1.0, 2.0}},
+		{Name:"pop_size", Values:[]float64{10, 20, 30}},
+	}}
+	combinations := sweep.Combinations()
+	if len(combinations) != 6 {
+		t.Error("Wrong number of combinations", len(combinations))
+		return
+	}
+	if combinations[0][0] != 1.0 || combinations[0][1] != 10 || combinations[5][0] != 2.0 || combinations[5][1] != 30 {
+		t.Error("Wrong combinations", combinations)
+	}
+	if combinations = (&Sweep{}).Combinations(); len(combinations) != 1 || len(combinations[0]) != 0 {
+		t.Error("The single empty combination expected", combinations)
+	}
+}
+
+func TestSweep_Run(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumGenerations = 3
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	sweep := Sweep{
+		Parameters:[]SweepParameter{
+			{Name:"pop_size", Values:[]float64{10, 15}},
+			{Name:"num_generations", Values:[]float64{2}},
+		},
+		Trials:3,
+		Parallelism:2,
+	}
+	results, err := sweep.Run(context, start_genome, func(ctx *neat.NeatContext) (interface{}, error) {
+		return &randomGenerationEvaluator{}, nil
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(results) != 2 {
+		t.Error("Wrong number of results", len(results))
+		return
+	}
+	for i, r := range results {
+		if len(r.Experiment.Trials) != 3 {
+			t.Error("Wrong number of trials", i, len(r.Experiment.Trials))
+			continue
+		}
+		// each generation evaluates all organisms of population
+		if evals := r.Experiment.AvgEvaluationsPerTrial(); evals != 2 * r.Values[0] {
+			t.Error("Swept parameters should be applied", i, evals)
+		}
+	}
+	if context.PopSize != 20 || context.NumGenerations != 3 {
+		t.Error("Original context should not change", context.PopSize, context.NumGenerations)
+	}
+
+	var buf bytes.Buffer
+	if err = sweep.WriteTable(&buf, results); err != nil {
+		t.Error(err)
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "pop_size") || !strings.HasPrefix(lines[2], "15") {
+		t.Error("Wrong table", buf.String())
+	}
+
+	// the unknown parameter and wrong number of trials
+	sweep.Parameters[0].Name = "unknown_param"
+	if _, err = sweep.Run(context, start_genome, nil); err == nil {
+		t.Error("Error expected for unknown parameter")
+	}
+	if _, err = (&Sweep{}).Run(context, start_genome, nil); err == nil {
+		t.Error("Error expected for zero trials")
+	}
+}
@@ -0,0 +1,106 @@
+package experiments
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The optional interface of organism evaluator providing the evaluation of contenders in the final tournament, e.g.
+// with more or harder test cases than regular evaluation during evolution. If not implemented, the contenders are
+// evaluated by OrganismEvaluator.
+type TournamentEvaluator interface {
+	// Invoked to evaluate contender of the final tournament once within given execution context. Returns raw fitness
+	// score and flag indicating whether organism solved the task during this evaluation.
+	TournamentEvaluate(org *genetics.Organism, context *neat.NeatContext) (fitness float64, solved bool, err error)
+}
+
+// The contender of the final tournament, i.e. the champion of one species
+type TournamentContender struct {
+	// The contending organism
+	Organism          *genetics.Organism
+	// The ID of species the organism is champion of
+	SpeciesId         int
+	// The fitness of organism found by regular evaluation during evolution
+	GenerationFitness float64
+	// The fitness values collected by tournament evaluations
+	Fitness           Floats
+	// The tournament fitness aggregated from collected values by context.FitnessAggregationType
+	TournamentFitness float64
+	// The flag to indicate whether organism solved the task during every tournament evaluation
+	Solved            bool
+}
+
+// The results of the final tournament among species champions
+type Tournament struct {
+	// The contenders sorted by tournament fitness, the best first. The solvers precede non-solvers.
+	Contenders []TournamentContender
+}
+
+// Returns the winner of tournament or nil if there were no contenders
+func (t *Tournament) Winner() *TournamentContender {
+	if len(t.Contenders) == 0 {
+		return nil
+	}
+	return &t.Contenders[0]
+}
+
+// Runs the final tournament among champions of species of provided population. Each champion is evaluated
+// context.FinalTournamentEvaluations times by TournamentEvaluator if implemented by evaluator, or by OrganismEvaluator
+// otherwise, and the contenders are ranked by aggregated tournament fitness in given objective direction. The fitness
+// of organisms themselves is not changed.
+func RunTournament(pop *genetics.Population, evaluator OrganismEvaluator, context *neat.NeatContext,
+direction genetics.ObjectiveDirection) (*Tournament, error) {
+	if context.FinalTournamentEvaluations <= 0 {
+		return nil, errors.New(fmt.Sprintf("Wrong number of tournament evaluations: %d",
+			context.FinalTournamentEvaluations))
+	}
+	evaluate := evaluator.OrganismEvaluate
+	if tournament_evaluator, ok := evaluator.(TournamentEvaluator); ok {
+		evaluate = tournament_evaluator.TournamentEvaluate
+	}
+	tournament := Tournament{Contenders:make([]TournamentContender, 0, len(pop.Species))}
+	for _, species := range pop.Species {
+		champion := direction.BestOrganism(species.Organisms)
+		if champion == nil {
+			continue
+		}
+		contender := TournamentContender{
+			Organism:champion,
+			SpeciesId:species.Id,
+			GenerationFitness:champion.Fitness,
+			Fitness:make(Floats, context.FinalTournamentEvaluations),
+			Solved:true,
+		}
+		for i := range contender.Fitness {
+			fitness, solved, err := evaluate(champion, context)
+			if err != nil {
+				return nil, err
+			}
+			contender.Fitness[i], contender.Solved = fitness, contender.Solved && solved
+		}
+		// the evaluator may update fitness of organism, thus it is restored
+		champion.Fitness = contender.GenerationFitness
+		var err error
+		if contender.TournamentFitness, err = AggregateFitness(contender.Fitness,
+			FitnessAggregationType(context.FitnessAggregationType)); err != nil {
+			return nil, err
+		}
+		tournament.Contenders = append(tournament.Contenders, contender)
+	}
+	sort.SliceStable(tournament.Contenders, func(i, j int) bool {
+		a, b := tournament.Contenders[i], tournament.Contenders[j]
+		if a.Solved != b.Solved {
+			return a.Solved
+		}
+		return direction.IsBetter(a.TournamentFitness, b.TournamentFitness)
+	})
+	if winner := tournament.Winner(); winner != nil {
+		neat.InfoLog(fmt.Sprintf("The final tournament among %d species champions won by organism [%d] of species [%d], tournament fitness: %f, generation fitness: %f\n",
+			len(tournament.Contenders), winner.Organism.Genotype.Id, winner.SpeciesId, winner.TournamentFitness,
+			winner.GenerationFitness))
+	}
+	return &tournament, nil
+}
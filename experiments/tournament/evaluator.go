@@ -0,0 +1,84 @@
+package tournament
+
+import (
+	"fmt"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The key of organism tag holding the number of matches won by organism in the last tournament
+const WinsTag = "tournament_wins"
+
+// The evaluator of generation by tournament among all organisms of population, the Elo rating of organism becomes its
+// fitness. As ratings are relative to other organisms, the survivors of steady-state epoch are rated again. It
+// implements experiments.GenerationEvaluator and assumes that fitness is maximized.
+type Evaluator struct {
+	// The tournament to run for each generation
+	Tournament   Tournament
+	// The player of matches
+	Player       MatchPlayer
+	// The minimal rating to consider organism as solver, if zero no organism is solver
+	SolvedRating float64
+
+	// The results of the last tournament
+	last         *Results
+}
+
+// Evaluates one generation of population by tournament among its organisms
+func (e *Evaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiments.Generation, context *neat.NeatContext) error {
+	results, err := e.Tournament.Run(pop.Organisms, e.Player, context)
+	if err != nil {
+		return err
+	}
+	for _, s := range results.Standings {
+		org := s.Organism
+		org.Fitness = s.Rating
+		org.IsWinner = e.SolvedRating > 0 && s.Rating >= e.SolvedRating
+		if org.Tags == nil {
+			org.Tags = make(genetics.OrganismTags)
+		}
+		org.Tags[WinsTag] = s.Wins
+		if err = org.MarkEvaluated(); err != nil {
+			return err
+		}
+		if org.IsWinner && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness) {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(org.Genotype.Nodes)
+			epoch.WinnerGenes = org.Genotype.Extrons()
+			epoch.WinnerEvals = context.PopSize * epoch.Id + org.Genotype.Id
+			epoch.Best = org
+		}
+	}
+	// each match is evaluation of two organisms
+	epoch.Evaluations = 2 * len(results.Matches)
+	e.last = results
+
+	if neat.LogLevel == neat.LogLevelDebug {
+		champion := results.Ranking()[0]
+		neat.DebugLog(fmt.Sprintf("Tournament of %d matches won by organism [%d], rating: %f, wins: %d, draws: %d, losses: %d",
+			len(results.Matches), champion.Organism.Genotype.Id, champion.Rating, champion.Wins, champion.Draws,
+			champion.Losses))
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
+
+// Returns the results of the last tournament or nil if no generation evaluated yet
+func (e *Evaluator) LastResults() *Results {
+	return e.last
+}
+
+// The adapter of match player to genetics.MatchEvaluator, which allows to play matches of competitive co-evolution
+// between populations by the same player
+type MatchEvaluator struct {
+	// The player of matches
+	Player MatchPlayer
+}
+
+// Plays the match between host and opponent and returns the score of host
+func (e MatchEvaluator) EvaluateMatch(host, opponent *genetics.Organism, context *neat.NeatContext) (float64, error) {
+	return e.Player.PlayMatch(host, opponent, context)
+}
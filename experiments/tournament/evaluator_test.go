@@ -0,0 +1,68 @@
+package tournament
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+func TestEvaluator_GenerationEvaluate(t *testing.T) {
+	rand.Seed(42)
+	conf := &neat.NeatContext{CompatThreshold:0.5, PopSize:8}
+	genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pop, err := genetics.NewPopulation(genome, conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &Evaluator{
+		Tournament:Tournament{Pairing:RandomPairing, Rounds:5, Workers:2},
+		Player:&strongerPlayer{},
+		SolvedRating:DefaultInitialRating + 1,
+	}
+	epoch := &experiments.Generation{Id:1}
+	if err = evaluator.GenerationEvaluate(pop, epoch, conf); err != nil {
+		t.Error(err)
+		return
+	}
+	results := evaluator.LastResults()
+	if results == nil || len(results.Matches) != 20 || epoch.Evaluations != 40 {
+		t.Error("Wrong tournament results", results, epoch.Evaluations)
+		return
+	}
+	for _, s := range results.Standings {
+		org := s.Organism
+		if org.Fitness != s.Rating || org.State() != genetics.OrganismEvaluated {
+			t.Error("The rating should become fitness of organism", org.Fitness, s.Rating)
+		}
+		if wins, ok := org.Tags[WinsTag].(int); !ok || wins != s.Wins {
+			t.Error("Wrong wins tag", org.Tags[WinsTag])
+		}
+	}
+	best := results.Ranking()[0]
+	if !epoch.Solved || epoch.Best != best.Organism || !best.Organism.IsWinner {
+		t.Error("The best rated organism should be solver", epoch.Solved, best.Rating)
+	}
+
+	evaluator.Player = &strongerPlayer{fail:true}
+	if err = evaluator.GenerationEvaluate(pop, &experiments.Generation{Id:2}, conf); err == nil {
+		t.Error("Error expected for failed match")
+	}
+}
+
+func TestMatchEvaluator_EvaluateMatch(t *testing.T) {
+	orgs := buildContestants(t, 2)
+	if orgs == nil {
+		return
+	}
+	var evaluator genetics.MatchEvaluator = MatchEvaluator{Player:&strongerPlayer{}}
+	if score, err := evaluator.EvaluateMatch(orgs[1], orgs[0], &neat.NeatContext{}); err != nil || score != 1 {
+		t.Error("Wrong score of host", score, err)
+	}
+}
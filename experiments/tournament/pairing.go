@@ -0,0 +1,99 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// The function pairing contestants for matches of given round of tournament based on results so far
+type pairingFunc func(round int, results *Results) []Match
+
+// Returns pairing function for given pairing type
+func pairingForType(pairing PairingType) (pairingFunc, error) {
+	switch pairing {
+	case RoundRobinPairing:
+		return roundRobinPairs, nil
+	case SwissPairing:
+		return swissPairs, nil
+	case RandomPairing:
+		return randomPairs, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("Unsupported tournament pairing type: %d", pairing))
+	}
+}
+
+// Pairs each contestant with every other, the sides are swapped at odd rounds
+func roundRobinPairs(round int, results *Results) []Match {
+	n := len(results.Standings)
+	matches := make([]Match, 0, n * (n - 1) / 2)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if round % 2 == 0 {
+				matches = append(matches, Match{Round:round, First:i, Second:j})
+			} else {
+				matches = append(matches, Match{Round:round, First:j, Second:i})
+			}
+		}
+	}
+	return matches
+}
+
+// Pairs contestants ranked by points and rating with the closest ranked contestant they have not played yet, or with
+// the closest ranked one if all were played. With odd number of contestants the one left unpaired sits out.
+func swissPairs(round int, results *Results) []Match {
+	n := len(results.Standings)
+	ranked := make([]int, n)
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := results.Standings[ranked[i]], results.Standings[ranked[j]]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		return a.Rating > b.Rating
+	})
+	played := make(map[[2]int]bool, len(results.Matches))
+	for _, m := range results.Matches {
+		played[[2]int{m.First, m.Second}], played[[2]int{m.Second, m.First}] = true, true
+	}
+
+	paired := make([]bool, n)
+	matches := make([]Match, 0, n / 2)
+	for i, first := range ranked {
+		if paired[first] {
+			continue
+		}
+		second := -1
+		for _, candidate := range ranked[i + 1:] {
+			if paired[candidate] {
+				continue
+			}
+			if second < 0 {
+				second = candidate
+			}
+			if !played[[2]int{first, candidate}] {
+				second = candidate
+				break
+			}
+		}
+		if second < 0 {
+			break
+		}
+		paired[first], paired[second] = true, true
+		matches = append(matches, Match{Round:round, First:first, Second:second})
+	}
+	return matches
+}
+
+// Pairs contestants randomly, with odd number of contestants the random one sits out
+func randomPairs(round int, results *Results) []Match {
+	order := rand.Perm(len(results.Standings))
+	matches := make([]Match, 0, len(order) / 2)
+	for i := 0; i + 1 < len(order); i += 2 {
+		matches = append(matches, Match{Round:round, First:order[i], Second:order[i + 1]})
+	}
+	return matches
+}
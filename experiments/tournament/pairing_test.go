@@ -0,0 +1,69 @@
+package tournament
+
+import (
+	"testing"
+	"math/rand"
+)
+
+func TestSwissPairs(t *testing.T) {
+	results := &Results{Standings:[]Standing{{Points:0}, {Points:2}, {Points:1}, {Points:2}, {Points:1}}}
+	matches := swissPairs(0, results)
+	if len(matches) != 2 {
+		t.Error("Wrong number of matches", len(matches))
+		return
+	}
+	// the closest ranked are paired and the lowest ranked sits out
+	if matches[0].First != 1 || matches[0].Second != 3 || matches[1].First != 2 || matches[1].Second != 4 {
+		t.Error("Wrong pairs", matches)
+	}
+
+	// the rematch is avoided
+	results.Matches = matches
+	matches = swissPairs(1, results)
+	if len(matches) != 2 || matches[0].First != 1 || matches[0].Second != 2 || matches[0].Round != 1 {
+		t.Error("Wrong pairs avoiding rematch", matches)
+	}
+}
+
+func TestRandomPairs(t *testing.T) {
+	rand.Seed(42)
+	results := &Results{Standings:make([]Standing, 5)}
+	matches := randomPairs(3, results)
+	if len(matches) != 2 {
+		t.Error("Wrong number of matches", len(matches))
+		return
+	}
+	seen := make(map[int]bool)
+	for _, m := range matches {
+		if seen[m.First] || seen[m.Second] || m.First == m.Second || m.Round != 3 {
+			t.Error("Wrong random pair", m)
+		}
+		seen[m.First], seen[m.Second] = true, true
+	}
+}
+
+func TestTournament_Run_swiss(t *testing.T) {
+	orgs := buildContestants(t, 6)
+	if orgs == nil {
+		return
+	}
+	tournament := Tournament{Pairing:SwissPairing, Rounds:3}
+	results, err := tournament.Run(orgs, &strongerPlayer{}, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(results.Matches) != 9 {
+		t.Error("Wrong number of matches", len(results.Matches))
+	}
+	played := make(map[[2]int]bool)
+	for _, m := range results.Matches {
+		if played[[2]int{m.First, m.Second}] {
+			t.Error("No rematches expected", m)
+		}
+		played[[2]int{m.First, m.Second}], played[[2]int{m.Second, m.First}] = true, true
+	}
+	if best := results.Ranking()[0]; best.Organism != orgs[5] || best.Wins != 3 {
+		t.Error("The strongest organism should win", best.Organism.Genotype.Id, best.Wins)
+	}
+}
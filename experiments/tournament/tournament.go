@@ -0,0 +1,222 @@
+// The tournament package provides evaluation of game-playing organisms by head-to-head matches, which is required
+// when fitness can not be measured against fixed environment, e.g. in competitive co-evolution. The organisms are
+// paired for matches by round robin, Swiss system or random sampling, the outcomes of matches are aggregated into
+// Elo-style ratings and the ratings become fitness of organisms. The matches of each round can be played in parallel.
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The default Elo rating of organism at the start of tournament
+const DefaultInitialRating = 1500.0
+
+// The default maximal change of Elo rating in one match
+const DefaultKFactor = 32.0
+
+// The player of head-to-head match between two organisms, e.g. game between agents controlled by their networks
+type MatchPlayer interface {
+	// Plays the match between two organisms within given execution context and returns the score of the first one in
+	// range [0, 1]: 1 for win, 0 for loss and 0.5 for draw, the intermediate values are allowed for partial wins. The
+	// score of the second organism is one minus returned score. It is invoked concurrently if tournament has more than
+	// one worker, thus it should not share state between matches.
+	PlayMatch(first, second *genetics.Organism, context *neat.NeatContext) (float64, error)
+}
+
+// The method of pairing organisms for matches in each round of tournament
+type PairingType byte
+
+const (
+	// Each organism plays every other organism once per round, the organisms swap sides at odd rounds
+	RoundRobinPairing PairingType = iota
+	// The organisms are ranked by points scored so far and paired with the closest ranked organism not played yet
+	SwissPairing
+	// The organisms are paired randomly in each round
+	RandomPairing
+)
+
+// The tournament among organisms playing head-to-head matches
+type Tournament struct {
+	// The method of pairing organisms for matches
+	Pairing       PairingType
+	// The number of rounds, if zero one round is played. With even number of organisms each organism plays one match
+	// per round with Swiss and random pairing, and all other organisms with round robin pairing.
+	Rounds        int
+	// The maximal change of rating in one match, if zero DefaultKFactor is used
+	KFactor       float64
+	// The rating of each organism at the start of tournament, if zero DefaultInitialRating is used
+	InitialRating float64
+	// The number of matches played in parallel, if less than two the matches are played sequentially
+	Workers       int
+}
+
+// The match played in tournament
+type Match struct {
+	// The round of tournament the match was played in, starting from zero
+	Round  int
+	// The indices of organisms in the list of tournament contestants
+	First  int
+	Second int
+	// The score of the first organism
+	Score  float64
+}
+
+// The standing of organism in tournament
+type Standing struct {
+	// The contestant organism
+	Organism *genetics.Organism
+	// The Elo rating of organism
+	Rating   float64
+	// The sum of scores of organism in all played matches
+	Points   float64
+	// The number of won, drawn and lost matches
+	Wins     int
+	Draws    int
+	Losses   int
+}
+
+// The results of tournament
+type Results struct {
+	// The standings of contestants in the order organisms were provided
+	Standings []Standing
+	// The matches in the order they were played
+	Matches   []Match
+}
+
+// Returns standings of contestants sorted by rating, the best first. The ties are resolved by points.
+func (r *Results) Ranking() []*Standing {
+	ranking := make([]*Standing, len(r.Standings))
+	for i := range r.Standings {
+		ranking[i] = &r.Standings[i]
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].Rating != ranking[j].Rating {
+			return ranking[i].Rating > ranking[j].Rating
+		}
+		return ranking[i].Points > ranking[j].Points
+	})
+	return ranking
+}
+
+// Runs tournament among provided organisms with matches played by given player. The ratings of organisms are updated
+// after each round from ratings at the start of round, thus results do not depend on the order of matches played in
+// parallel. The fitness of organisms is not changed. Returns error if there are less than two organisms, the pairing
+// type is not supported or any match failed.
+func (t *Tournament) Run(organisms []*genetics.Organism, player MatchPlayer, context *neat.NeatContext) (*Results, error) {
+	if len(organisms) < 2 {
+		return nil, errors.New(fmt.Sprintf("At least two organisms required for tournament, found: %d", len(organisms)))
+	}
+	pairing, err := pairingForType(t.Pairing)
+	if err != nil {
+		return nil, err
+	}
+	initial := t.InitialRating
+	if initial == 0 {
+		initial = DefaultInitialRating
+	}
+	results := &Results{Standings:make([]Standing, len(organisms))}
+	for i, org := range organisms {
+		results.Standings[i] = Standing{Organism:org, Rating:initial}
+	}
+	rounds := t.Rounds
+	if rounds <= 0 {
+		rounds = 1
+	}
+	for round := 0; round < rounds; round++ {
+		matches := pairing(round, results)
+		if err = t.play(matches, results, player, context); err != nil {
+			return nil, err
+		}
+		t.rate(matches, results)
+		results.Matches = append(results.Matches, matches...)
+	}
+	return results, nil
+}
+
+// Plays provided matches storing the scores into them
+func (t *Tournament) play(matches []Match, results *Results, player MatchPlayer, context *neat.NeatContext) error {
+	errs := make([]error, len(matches))
+	play_match := func(i int) {
+		m := &matches[i]
+		score, err := player.PlayMatch(results.Standings[m.First].Organism, results.Standings[m.Second].Organism, context)
+		if err == nil && (math.IsNaN(score) || score < 0 || score > 1) {
+			err = errors.New(fmt.Sprintf("The match score should be in [0, 1], found: %f", score))
+		}
+		m.Score, errs[i] = score, err
+	}
+
+	if t.Workers < 2 {
+		for i := range matches {
+			play_match(i)
+		}
+	} else {
+		indices := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < t.Workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indices {
+					play_match(i)
+				}
+			}()
+		}
+		for i := range matches {
+			indices <- i
+		}
+		close(indices)
+		wg.Wait()
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			m := matches[i]
+			return errors.New(fmt.Sprintf("The match between organisms [%d] and [%d] failed, reason: %s",
+				results.Standings[m.First].Organism.Genotype.Id, results.Standings[m.Second].Organism.Genotype.Id, err))
+		}
+	}
+	return nil
+}
+
+// Updates standings of contestants by scores of matches played in one round. The expected scores are computed from
+// ratings at the start of round.
+func (t *Tournament) rate(matches []Match, results *Results) {
+	k := t.KFactor
+	if k == 0 {
+		k = DefaultKFactor
+	}
+	ratings := make([]float64, len(results.Standings))
+	for i, s := range results.Standings {
+		ratings[i] = s.Rating
+	}
+	for _, m := range matches {
+		expected := ExpectedScore(ratings[m.First], ratings[m.Second])
+		first, second := &results.Standings[m.First], &results.Standings[m.Second]
+		first.Rating += k * (m.Score - expected)
+		second.Rating -= k * (m.Score - expected)
+		first.Points += m.Score
+		second.Points += 1.0 - m.Score
+		switch {
+		case m.Score > 0.5:
+			first.Wins++
+			second.Losses++
+		case m.Score < 0.5:
+			first.Losses++
+			second.Wins++
+		default:
+			first.Draws++
+			second.Draws++
+		}
+	}
+}
+
+// Returns the expected score of player with rating a in the match against player with rating b by Elo formula
+func ExpectedScore(a, b float64) float64 {
+	return 1.0 / (1.0 + math.Pow(10, (b - a) / 400.0))
+}
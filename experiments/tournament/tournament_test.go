@@ -0,0 +1,135 @@
+package tournament
+
+import (
+	"testing"
+	"errors"
+	"math"
+	"sync/atomic"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The match player where organism with greater genome ID always wins
+type strongerPlayer struct {
+	matches int32
+	fail    bool
+}
+
+func (p *strongerPlayer) PlayMatch(first, second *genetics.Organism, context *neat.NeatContext) (float64, error) {
+	if p.fail {
+		return 0, errors.New("match failed")
+	}
+	atomic.AddInt32(&p.matches, 1)
+	if first.Genotype.Id > second.Genotype.Id {
+		return 1.0, nil
+	}
+	return 0.0, nil
+}
+
+// Builds contestant organisms with genome IDs from 1 to n
+func buildContestants(t *testing.T, n int) []*genetics.Organism {
+	orgs := make([]*genetics.Organism, n)
+	for i := range orgs {
+		genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+		if err != nil {
+			t.Error(err)
+			return nil
+		}
+		genome.Id = i + 1
+		if orgs[i], err = genetics.NewOrganism(0.0, genome, 1); err != nil {
+			t.Error(err)
+			return nil
+		}
+	}
+	return orgs
+}
+
+func TestTournament_Run_roundRobin(t *testing.T) {
+	orgs := buildContestants(t, 4)
+	if orgs == nil {
+		return
+	}
+	player := &strongerPlayer{}
+	tournament := Tournament{Pairing:RoundRobinPairing, Rounds:2, Workers:3}
+	results, err := tournament.Run(orgs, player, &neat.NeatContext{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if player.matches != 12 || len(results.Matches) != 12 {
+		t.Error("Wrong number of matches", player.matches, len(results.Matches))
+	}
+	total := 0.0
+	for i, s := range results.Standings {
+		if s.Organism != orgs[i] || s.Wins != 2 * i || s.Losses != 2 * (3 - i) || s.Draws != 0 {
+			t.Error("Wrong standing of organism", i, s)
+		}
+		total += s.Rating
+		if orgs[i].Fitness != 0 {
+			t.Error("The fitness of organisms should not be changed")
+		}
+	}
+	if math.Abs(total - 4 * DefaultInitialRating) > 1e-9 {
+		t.Error("The sum of ratings should be preserved", total)
+	}
+	ranking := results.Ranking()
+	for i, s := range ranking {
+		if s.Organism != orgs[3 - i] {
+			t.Error("Wrong ranking at", i, s.Organism.Genotype.Id)
+		}
+	}
+	// the sides are swapped at odd rounds
+	if m := results.Matches[6]; m.Round != 1 || m.First != 1 || m.Second != 0 {
+		t.Error("Wrong match of second round", m)
+	}
+}
+
+func TestTournament_Run_rating(t *testing.T) {
+	orgs := buildContestants(t, 2)
+	if orgs == nil {
+		return
+	}
+	tournament := Tournament{KFactor:10, InitialRating:1000}
+	results, err := tournament.Run(orgs, &strongerPlayer{}, &neat.NeatContext{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if results.Standings[0].Rating != 995 || results.Standings[1].Rating != 1005 {
+		t.Error("Wrong ratings", results.Standings)
+	}
+	if results.Standings[1].Points != 1 || results.Standings[0].Points != 0 {
+		t.Error("Wrong points", results.Standings)
+	}
+}
+
+func TestTournament_Run_errors(t *testing.T) {
+	orgs := buildContestants(t, 3)
+	if orgs == nil {
+		return
+	}
+	tournament := Tournament{}
+	if _, err := tournament.Run(orgs[:1], &strongerPlayer{}, &neat.NeatContext{}); err == nil {
+		t.Error("Error expected for single contestant")
+	}
+	if _, err := tournament.Run(orgs, &strongerPlayer{fail:true}, &neat.NeatContext{}); err == nil {
+		t.Error("Error expected for failed match")
+	}
+	tournament.Workers = 2
+	if _, err := tournament.Run(orgs, &strongerPlayer{fail:true}, &neat.NeatContext{}); err == nil {
+		t.Error("Error expected for failed match played in parallel")
+	}
+	tournament.Pairing = 100
+	if _, err := tournament.Run(orgs, &strongerPlayer{}, &neat.NeatContext{}); err == nil {
+		t.Error("Error expected for unsupported pairing")
+	}
+}
+
+func TestExpectedScore(t *testing.T) {
+	if score := ExpectedScore(1500, 1500); score != 0.5 {
+		t.Error("Wrong expected score of equal ratings", score)
+	}
+	if score := ExpectedScore(1900, 1500); math.Abs(score - 10.0 / 11.0) > 1e-9 {
+		t.Error("Wrong expected score of stronger player", score)
+	}
+}
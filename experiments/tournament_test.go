@@ -0,0 +1,140 @@
+package experiments
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The evaluator with harder tournament evaluation, which fitness is defined per genome ID and organism is solver if
+// fitness is not less than threshold. The regular evaluation overwrites fitness of organism.
+type tournamentEvaluator struct {
+	fitness     map[int]float64
+	threshold   float64
+	tournaments int
+}
+
+func (e *tournamentEvaluator) OrganismEvaluate(org *genetics.Organism, context *neat.NeatContext) (float64, bool, error) {
+	org.Fitness = 0.5
+	return org.Fitness, false, nil
+}
+
+func (e *tournamentEvaluator) TournamentEvaluate(org *genetics.Organism, context *neat.NeatContext) (float64, bool, error) {
+	e.tournaments++
+	org.Fitness = -1.0
+	f := e.fitness[org.Genotype.Id]
+	return f, f >= e.threshold, nil
+}
+
+// Creates population with species of organisms having given generation fitness values and genome IDs in order
+func buildTournamentPopulation(species_fitness ...[]float64) *genetics.Population {
+	pop := &genetics.Population{}
+	id := 1
+	for i, fitness := range species_fitness {
+		species := genetics.NewSpecies(i + 1)
+		for _, f := range fitness {
+			species.Organisms = append(species.Organisms, &genetics.Organism{Fitness:f, Genotype:&genetics.Genome{Id:id}})
+			id++
+		}
+		pop.Species = append(pop.Species, species)
+	}
+	return pop
+}
+
+func TestRunTournament(t *testing.T) {
+	// the champions of species are organisms 2, 3 and 6
+	pop := buildTournamentPopulation([]float64{1.0, 5.0}, []float64{4.0}, []float64{2.0, 0.5, 3.0})
+	evaluator := &tournamentEvaluator{fitness:map[int]float64{1:0.2, 2:0.3, 3:0.9, 5:0.1, 6:0.7}, threshold:0.8}
+	context := &neat.NeatContext{FinalTournamentEvaluations:3}
+	tournament, err := RunTournament(pop, evaluator, context, genetics.MaximizeObjective)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if evaluator.tournaments != 9 {
+		t.Error("Wrong number of tournament evaluations", evaluator.tournaments)
+	}
+	if len(tournament.Contenders) != 3 {
+		t.Error("Wrong number of contenders", len(tournament.Contenders))
+		return
+	}
+	// the best in tournament wins rather than the best by generation fitness
+	expected := []int{3, 6, 2}
+	for i, contender := range tournament.Contenders {
+		if contender.Organism.Genotype.Id != expected[i] {
+			t.Error("Wrong contender at:", i, contender.Organism.Genotype.Id)
+		}
+		if contender.Organism.Fitness != contender.GenerationFitness || len(contender.Fitness) != 3 {
+			t.Error("The fitness of organism should be restored", contender.Organism.Fitness)
+		}
+	}
+	winner := tournament.Winner()
+	if winner.SpeciesId != 2 || !winner.Solved || winner.TournamentFitness != 0.9 || winner.GenerationFitness != 4.0 {
+		t.Error("Wrong winner", winner)
+	}
+
+	// the solvers precede non-solvers in minimized objective, the champions of species are organisms 1, 3 and 5
+	tournament, err = RunTournament(pop, evaluator, context, genetics.MinimizeObjective)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if id := tournament.Winner().Organism.Genotype.Id; id != 3 {
+		t.Error("The only solver should win", id)
+	}
+	if last := tournament.Contenders[2]; last.Organism.Genotype.Id != 1 {
+		t.Error("Wrong last contender with minimized objective", last.Organism.Genotype.Id)
+	}
+
+	if _, err = RunTournament(pop, evaluator, &neat.NeatContext{}, genetics.MaximizeObjective); err == nil {
+		t.Error("Error expected for zero tournament evaluations")
+	}
+	if (&Tournament{}).Winner() != nil {
+		t.Error("No winner expected without contenders")
+	}
+}
+
+func TestExperiment_Execute_finalTournament(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.FinalTournamentEvaluations = 2
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	evaluator := &championGenerationEvaluator{}
+	experiment := Experiment{}
+	if err = experiment.Execute(context, start_genome, evaluator); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, trial := range experiment.Trials {
+		if trial.Tournament == nil || trial.Tournament.Winner() == nil {
+			t.Error("The final tournament expected in trial", trial.Id)
+			continue
+		}
+		last := trial.Generations[len(trial.Generations) - 1]
+		if len(trial.Tournament.Contenders) != last.Diversity {
+			t.Error("Each species champion should contend", len(trial.Tournament.Contenders), last.Diversity)
+		}
+		if champion, ok := trial.Champion(); !ok || champion != trial.Tournament.Winner().Organism {
+			t.Error("The tournament winner should be declared champion of trial")
+		}
+	}
+
+	// the tournament is skipped if evaluator can not evaluate single organism
+	experiment = Experiment{}
+	if err = experiment.Execute(context, start_genome, &randomGenerationEvaluator{}); err != nil {
+		t.Error(err)
+		return
+	}
+	trial := experiment.Trials[0]
+	if trial.Tournament != nil {
+		t.Error("No tournament expected")
+	}
+	if champion, ok := trial.Champion(); !ok || champion.Fitness != trial.BestFitness().Max() {
+		t.Error("The best organism should be declared champion without tournament")
+	}
+}
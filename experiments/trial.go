@@ -3,7 +3,6 @@ package experiments
 import (
 	"time"
 	"github.com/yaricom/goNEAT/neat/genetics"
-	"sort"
 	"encoding/gob"
 )
 
@@ -18,6 +17,14 @@ type Trial struct {
 
 	// The elapsed time between trial start and finish
 	Duration         time.Duration
+
+	// The direction of fitness objective of this trial as configured by execution context. It is not encoded with
+	// trial, thus the decoded trial assumes maximized objective.
+	ObjectiveDirection genetics.ObjectiveDirection
+
+	// The results of the final tournament among species champions at the end of trial, nil if tournament was not
+	// run. It is not encoded with trial.
+	Tournament       *Tournament
 }
 
 // Calculates average duration of evaluations among all generations of organism populations in this trial
@@ -53,13 +60,39 @@ func (t *Trial) BestOrganism(onlySolvers bool) (*genetics.Organism, bool) {
 		}
 	}
 	if len(orgs) > 0 {
-		sort.Sort(sort.Reverse(orgs))
-		return orgs[0], true
+		return t.ObjectiveDirection.BestOrganism(orgs), true
 	} else {
 		return nil, false
 	}
 }
 
+// Finds the epoch of this trial which best organism has the highest fitness against validation data held out from
+// training, i.e. the champion selected by validation fitness, which is less prone to overfitting than the one selected
+// by training fitness. Returns false if no epoch was validated.
+func (t *Trial) BestValidatedGeneration() (*Generation, bool) {
+	best := -1
+	for i, e := range t.Generations {
+		if e.Validated && (best < 0 || e.ValidationFitness > t.Generations[best].ValidationFitness) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil, false
+	}
+	return &t.Generations[best], true
+}
+
+// Returns the declared winner of this trial, which is the winner of the final tournament if it was run, or the most
+// fit organism among all epochs otherwise. Returns false if trial has no organisms.
+func (t *Trial) Champion() (*genetics.Organism, bool) {
+	if t.Tournament != nil {
+		if winner := t.Tournament.Winner(); winner != nil {
+			return winner.Organism, true
+		}
+	}
+	return t.BestOrganism(false)
+}
+
 func (t *Trial) Solved() bool {
 	for _, e := range t.Generations {
 		if e.Solved {
@@ -79,6 +112,17 @@ func (t *Trial) BestFitness() Floats {
 	return x
 }
 
+// Returns the fitness of the best organisms against validation data for each validated epoch in this trial
+func (t *Trial) BestValidationFitness() Floats {
+	var x Floats = make([]float64, 0, len(t.Generations))
+	for _, e := range t.Generations {
+		if e.Validated {
+			x = append(x, e.ValidationFitness)
+		}
+	}
+	return x
+}
+
 // Age returns the age of the best species for each epoch in this trial
 func (t *Trial) BestAge() Floats {
 	var x Floats = make([]float64, len(t.Generations))
@@ -92,7 +136,7 @@ func (t *Trial) BestAge() Floats {
 func (t *Trial) BestComplexity() Floats {
 	var x Floats = make([]float64, len(t.Generations))
 	for i, e := range t.Generations {
-		x[i] = float64(e.Best.Phenotype.Complexity())
+		x[i] = float64(e.Best.Complexity())
 	}
 	return x
 }
@@ -118,6 +162,24 @@ func (t *Trial) Average() (fitness, age, complexity Floats) {
 	return fitness, age, complexity
 }
 
+// Returns the total number of organism evaluations done in this trial
+func (t *Trial) Evaluations() int {
+	total := 0
+	for _, e := range t.Generations {
+		total += e.Evaluations
+	}
+	return total
+}
+
+// Returns the total number of network activation steps done in this trial
+func (t *Trial) Activations() int64 {
+	total := int64(0)
+	for _, e := range t.Generations {
+		total += e.Activations
+	}
+	return total
+}
+
 // Returns number of nodes, genes,  organism evaluations and species diversity in the winner genome
 func (t *Trial) Winner() (nodes, genes, evals, diversity int) {
 	if t.WinnerGeneration != nil {
@@ -5,6 +5,7 @@ import (
 	"math"
 	"bytes"
 	"encoding/gob"
+	"github.com/yaricom/goNEAT/neat/genetics"
 )
 
 func TestTrial_Encode_Decode(t *testing.T) {
@@ -35,6 +36,66 @@ func TestTrial_Encode_Decode(t *testing.T) {
 	deepCompareTrials(trial, &dec_trial, t)
 }
 
+func TestTrial_Evaluations(t *testing.T) {
+	trial := buildTestTrial(1, 3)
+	if evals := trial.Evaluations(); evals != 450 {
+		t.Error("Wrong number of evaluations", evals)
+	}
+	if activations := trial.Activations(); activations != 13500 {
+		t.Error("Wrong number of activations", activations)
+	}
+}
+
+func TestTrial_BestOrganism(t *testing.T) {
+	trial := buildTestTrial(1, 3)
+	if best, ok := trial.BestOrganism(false); !ok || best.Fitness != 3.0 * math.E {
+		t.Error("The organism with the highest fitness expected", best)
+	}
+	trial.ObjectiveDirection = genetics.MinimizeObjective
+	if best, ok := trial.BestOrganism(false); !ok || best.Fitness != math.E {
+		t.Error("The organism with the lowest fitness expected", best)
+	}
+
+	ex := Experiment{Trials:Trials{*trial, *buildTestTrial(2, 1)}}
+	ex.Trials[1].ObjectiveDirection = genetics.MinimizeObjective
+	ex.Trials[1].Generations[0].Best.Fitness = 0.5
+	if best, trid, ok := ex.BestOrganism(false); !ok || trid != 1 || best.Fitness != 0.5 {
+		t.Error("The organism with the lowest fitness among trials expected", trid, best)
+	}
+}
+
+func TestTrial_BestValidatedGeneration(t *testing.T) {
+	trial := buildTestTrial(1, 4)
+	if _, ok := trial.BestValidatedGeneration(); ok {
+		t.Error("No validated generation expected")
+	}
+	if fitness := trial.BestValidationFitness(); len(fitness) != 0 {
+		t.Error("No validation fitness expected", fitness)
+	}
+	// the validation fitness peaks at second generation while training fitness keeps growing
+	for i, fitness := range []float64{0.6, 0.8, 0.7} {
+		trial.Generations[i].ValidationFitness, trial.Generations[i].Validated = fitness, true
+	}
+	epoch, ok := trial.BestValidatedGeneration()
+	if !ok || epoch != &trial.Generations[1] || epoch.Best.Fitness != 2.0 * math.E {
+		t.Error("The generation with the highest validation fitness expected", epoch)
+	}
+	if fitness := trial.BestValidationFitness(); len(fitness) != 3 || fitness[1] != 0.8 {
+		t.Error("Wrong validation fitness", fitness)
+	}
+
+	other := buildTestTrial(2, 1)
+	other.Generations[0].ValidationFitness, other.Generations[0].Validated = 0.9, true
+	other.Generations[0].TrialId = 2
+	ex := Experiment{Trials:Trials{*trial, *other}}
+	if epoch, ok := ex.BestValidatedGeneration(); !ok || epoch.TrialId != 2 || epoch.ValidationFitness != 0.9 {
+		t.Error("The generation with the highest validation fitness among trials expected", epoch)
+	}
+	if _, ok := (&Experiment{}).BestValidatedGeneration(); ok {
+		t.Error("No validated generation expected for empty experiment")
+	}
+}
+
 func deepCompareTrials(first, second *Trial, t *testing.T) {
 	if first.Id != second.Id {
 		t.Error("first.Id != second.Id")
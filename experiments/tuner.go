@@ -0,0 +1,187 @@
+package experiments
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+// The range of values of one parameter of execution context explored by tuner. The parameter is identified by its
+// name in plain text configuration file the same way as for Sweep.
+type TunerParameter struct {
+	Name     string
+	// The range of parameter values [Min, Max]
+	Min, Max float64
+	// If true the parameter takes only integer values, e.g. population size
+	Integer  bool
+}
+
+// The adaptive tuner of NEAT hyperparameters using random search with successive halving. The tuner samples random
+// candidate configurations within ranges of parameters and evaluates them in rungs: at each rung all candidates are
+// tried with given number of trials and only the best 1/Eta of them are promoted to the next rung, where they are
+// tried with Eta times more trials. Thus, the most of trials budget is allocated to the promising configurations. The
+// candidates are ranked by success rate, then by average number of evaluations per trial (less is better), and then
+// by average best fitness.
+type Tuner struct {
+	// The tuned parameters
+	Parameters  []TunerParameter
+	// The number of random candidate configurations tried at the first rung
+	Candidates  int
+	// The number of trials of each candidate at the first rung
+	Trials      int
+	// The reduction factor of successive halving, the value less than 2 means 2
+	Eta         int
+	// The maximal number of trials run in parallel, if not positive all trials of rung are run in parallel
+	Parallelism int
+}
+
+// The results of tuning
+type TunerResult struct {
+	// The names of tuned parameters in order of values of results
+	Names []string
+	// The results of candidates evaluated at each rung in order from the best to the worst
+	Rungs [][]*SweepResult
+}
+
+// Runs the tuner, the population of each trial is spawned from provided start genome and evolved within copy of given
+// execution context with values of tuned parameters applied. The executors of trials are created by provided factory.
+func (t *Tuner) Run(context *neat.NeatContext, start_genome *genetics.Genome,
+new_executor SweepExecutorFactory) (*TunerResult, error) {
+	if t.Candidates <= 0 {
+		return nil, errors.New(fmt.Sprintf("TUNER: The number of candidates should be positive: %d", t.Candidates))
+	}
+	eta := t.Eta
+	if eta < 2 {
+		eta = 2
+	}
+	sweep := Sweep{Parameters:make([]SweepParameter, len(t.Parameters)), Trials:t.Trials, Parallelism:t.Parallelism}
+	result := TunerResult{Names:make([]string, len(t.Parameters))}
+	for i, p := range t.Parameters {
+		if p.Max < p.Min {
+			return nil, errors.New(fmt.Sprintf("TUNER: Wrong range of parameter [%s]: [%g, %g]", p.Name, p.Min, p.Max))
+		}
+		sweep.Parameters[i].Name, result.Names[i] = p.Name, p.Name
+	}
+
+	candidates := make([][]float64, t.Candidates)
+	for i := range candidates {
+		candidates[i] = t.sample()
+	}
+	for rung := 0; ; rung++ {
+		neat.InfoLog(fmt.Sprintf("TUNER: Rung [%d], trying %d candidates with %d trials each\n",
+			rung, len(candidates), sweep.Trials))
+		results, err := sweep.runCombinations(context, candidates, start_genome, new_executor)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(results, func(i, j int) bool {
+			return sweepResultBetter(results[i], results[j])
+		})
+		result.Rungs = append(result.Rungs, results)
+		if len(results) == 1 {
+			break
+		}
+		promoted := len(results) / eta
+		if promoted < 1 {
+			promoted = 1
+		}
+		candidates = make([][]float64, promoted)
+		for i := range candidates {
+			candidates[i] = results[i].Values
+		}
+		sweep.Trials *= eta
+	}
+	return &result, nil
+}
+
+// Returns random values of tuned parameters within their ranges
+func (t *Tuner) sample() []float64 {
+	values := make([]float64, len(t.Parameters))
+	for i, p := range t.Parameters {
+		if p.Integer {
+			values[i] = math.Min(p.Max, math.Floor(p.Min + rand.Float64() * (p.Max - p.Min + 1.0)))
+		} else {
+			values[i] = p.Min + rand.Float64() * (p.Max - p.Min)
+		}
+	}
+	return values
+}
+
+// Returns true if result a is better than result b: it has higher success rate, or less evaluations per trial, or
+// better average best fitness in objective direction
+func sweepResultBetter(a, b *SweepResult) bool {
+	if sr_a, sr_b := a.Experiment.SuccessRate(), b.Experiment.SuccessRate(); sr_a != sr_b {
+		return sr_a > sr_b
+	}
+	if ev_a, ev_b := a.Experiment.AvgEvaluationsPerTrial(), b.Experiment.AvgEvaluationsPerTrial(); ev_a != ev_b {
+		return ev_a < ev_b
+	}
+	return a.Experiment.objectiveDirection().IsBetter(a.Experiment.BestFitness().Mean(),
+		b.Experiment.BestFitness().Mean())
+}
+
+// Returns the best found configuration, i.e. the winner of the last rung
+func (r *TunerResult) Best() *SweepResult {
+	if len(r.Rungs) == 0 {
+		return nil
+	}
+	return r.Rungs[len(r.Rungs) - 1][0]
+}
+
+// The parameter line of configuration file either in plain text (name value) or YAML (name: value) format
+var configParamLine = regexp.MustCompile(`^(\s*)([A-Za-z_][A-Za-z0-9_]*)(:?)(\s+)(\S+)(.*)$`)
+
+// Writes the best found configuration into provided writer as a ready-to-use configuration file. The configuration is
+// copied from provided base configuration, either in plain text or YAML format, with values of tuned parameters
+// replaced by the best found ones. The tuned parameters missing in base configuration are appended to it.
+func (r *TunerResult) WriteConfig(w io.Writer, base io.Reader) error {
+	best := r.Best()
+	if best == nil {
+		return errors.New("TUNER: No tuning results to write")
+	}
+	written := make(map[string]bool)
+	yaml_indent := ""
+	scanner := bufio.NewScanner(base)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := configParamLine.FindStringSubmatch(line); m != nil {
+			if m[3] != "" && m[1] != "" {
+				yaml_indent = m[1]
+			}
+			for i, name := range r.Names {
+				if name == m[2] {
+					line = m[1] + m[2] + m[3] + m[4] + fmt.Sprint(best.Values[i]) + m[6]
+					written[name] = true
+				}
+			}
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	for i, name := range r.Names {
+		if written[name] {
+			continue
+		}
+		var err error
+		if yaml_indent != "" {
+			_, err = fmt.Fprintf(w, "%s%s: %v\n", yaml_indent, name, best.Values[i])
+		} else {
+			_, err = fmt.Fprintf(w, "%s %v\n", name, best.Values[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
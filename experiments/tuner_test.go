@@ -0,0 +1,99 @@
+package experiments
+
+import (
+	"testing"
+	"bytes"
+	"strings"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+func TestTuner_Run(t *testing.T) {
+	rand.Seed(42)
+	context := testExperimentContext()
+	context.NumGenerations = 2
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	tuner := Tuner{
+		Parameters:[]TunerParameter{
+			{Name:"pop_size", Min:5, Max:30, Integer:true},
+			{Name:"compat_threshold", Min:1.0, Max:4.0},
+		},
+		Candidates:4,
+		Trials:1,
+		Parallelism:4,
+	}
+	factory := func(ctx *neat.NeatContext) (interface{}, error) {
+		return &randomGenerationEvaluator{}, nil
+	}
+	result, err := tuner.Run(context, start_genome, factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result.Rungs) != 3 || len(result.Rungs[0]) != 4 || len(result.Rungs[1]) != 2 || len(result.Rungs[2]) != 1 {
+		t.Error("Wrong rungs of successive halving", len(result.Rungs))
+		return
+	}
+	// the promoted candidates get more trials
+	if len(result.Rungs[2][0].Experiment.Trials) != 4 {
+		t.Error("Wrong number of trials at the last rung", len(result.Rungs[2][0].Experiment.Trials))
+	}
+	for _, r := range result.Rungs[0] {
+		pop_size, threshold := r.Values[0], r.Values[1]
+		if pop_size < 5 || pop_size > 30 || pop_size != float64(int(pop_size)) {
+			t.Error("Wrong integer parameter value", pop_size)
+		}
+		if threshold < 1.0 || threshold > 4.0 {
+			t.Error("Wrong parameter value", threshold)
+		}
+	}
+	// no trial is solved, thus the smallest population needs less evaluations
+	best := result.Best()
+	for _, r := range result.Rungs[0] {
+		if r.Values[0] < best.Values[0] {
+			t.Error("The smallest population should win", best.Values, r.Values)
+		}
+	}
+
+	if _, err = (&Tuner{}).Run(context, start_genome, factory); err == nil {
+		t.Error("Error expected for zero candidates")
+	}
+	tuner.Parameters[0].Min = 100
+	if _, err = tuner.Run(context, start_genome, factory); err == nil {
+		t.Error("Error expected for wrong range of parameter")
+	}
+}
+
+func TestTunerResult_WriteConfig(t *testing.T) {
+	result := TunerResult{
+		Names:[]string{"pop_size", "compat_threshold"},
+		Rungs:[][]*SweepResult{{{Values:[]float64{150, 2.5}}}},
+	}
+	var buf bytes.Buffer
+	if err := result.WriteConfig(&buf, strings.NewReader("weight_mut_power  2.5\npop_size  200\n")); err != nil {
+		t.Error(err)
+		return
+	}
+	if expected := "weight_mut_power  2.5\npop_size  150\ncompat_threshold 2.5\n"; buf.String() != expected {
+		t.Errorf("Wrong plain config\n%s\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	yaml := "# The context\nneat:\n  # The population size\n  pop_size: 200\n"
+	if err := result.WriteConfig(&buf, strings.NewReader(yaml)); err != nil {
+		t.Error(err)
+		return
+	}
+	if expected := "# The context\nneat:\n  # The population size\n  pop_size: 150\n  compat_threshold: 2.5\n"; buf.String() != expected {
+		t.Errorf("Wrong YAML config\n%s\n%s", expected, buf.String())
+	}
+
+	if err := (&TunerResult{}).WriteConfig(&buf, strings.NewReader("")); err == nil {
+		t.Error("Error expected for empty results")
+	}
+}
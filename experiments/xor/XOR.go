@@ -35,7 +35,9 @@ type XORGenerationEvaluator struct {
 func (ex XORGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiments.Generation, context *neat.NeatContext) (err error) {
 	// Evaluate each organism on a test
 	for _, org := range pop.Organisms {
-		res, err := ex.org_evaluate(org, context)
+		res, err := experiments.EvaluateWithErrorPolicy(org, func(org *genetics.Organism) (bool, error) {
+			return ex.org_evaluate(org, context)
+		}, context)
 		if err != nil {
 			return err
 		}
@@ -48,8 +50,12 @@ func (ex XORGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, ep
 			epoch.Best = org
 			if (epoch.WinnerNodes == 5) {
 				// You could dump out optimal genomes here if desired
+				phenotype, err := org.Phenotype()
+				if err != nil {
+					return err
+				}
 				opt_path := fmt.Sprintf("%s/%s_%d-%d", experiments.OutDirForTrial(ex.OutputPath, epoch.TrialId),
-					"xor_optimal", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
+					"xor_optimal", phenotype.NodeCount(), phenotype.LinkCount())
 				file, err := os.Create(opt_path)
 				if err != nil {
 					neat.ErrorLog(fmt.Sprintf("Failed to dump optimal genome, reason: %s\n", err))
@@ -80,8 +86,12 @@ func (ex XORGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, ep
 		for _, org := range pop.Organisms {
 			if org.IsWinner {
 				// Prints the winner organism to file!
+				phenotype, err := org.Phenotype()
+				if err != nil {
+					return err
+				}
 				org_path := fmt.Sprintf("%s/%s_%d-%d", experiments.OutDirForTrial(ex.OutputPath, epoch.TrialId),
-					"xor_winner", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
+					"xor_winner", phenotype.NodeCount(), phenotype.LinkCount())
 				file, err := os.Create(org_path)
 				if err != nil {
 					neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
@@ -107,7 +117,11 @@ func (ex *XORGenerationEvaluator) org_evaluate(organism *genetics.Organism, cont
 		{1.0, 1.0, 0.0},
 		{1.0, 1.0, 1.0}}
 
-	net_depth, err := organism.Phenotype.MaxDepth() // The max depth of the network to be activated
+	phenotype, err := organism.Phenotype()
+	if err != nil {
+		return false, err
+	}
+	net_depth, err := phenotype.MaxDepth() // The max depth of the network to be activated
 	if err != nil {
 		neat.WarnLog(
 			fmt.Sprintf("Failed to estimate maximal depth of the network with loop:\n%s\nUsing default dpeth: %d",
@@ -123,10 +137,10 @@ func (ex *XORGenerationEvaluator) org_evaluate(organism *genetics.Organism, cont
 
 	// Load and activate the network on each input
 	for count := 0; count < 4; count++ {
-		organism.Phenotype.LoadSensors(in[count])
+		phenotype.LoadSensors(in[count])
 
 		// Relax net and get output
-		success, err = organism.Phenotype.Activate()
+		success, err = phenotype.Activate()
 		if err != nil {
 			neat.ErrorLog("Failed to activate network")
 			return false, err
@@ -134,15 +148,15 @@ func (ex *XORGenerationEvaluator) org_evaluate(organism *genetics.Organism, cont
 
 		// use depth to ensure relaxation
 		for relax := 0; relax <= net_depth; relax++ {
-			success, err = organism.Phenotype.Activate()
+			success, err = phenotype.Activate()
 			if err != nil {
 				neat.ErrorLog("Failed to activate network")
 				return false, err
 			}
 		}
-		out[count] = organism.Phenotype.Outputs[0].Activation
+		out[count] = phenotype.Outputs[0].Activation
 
-		organism.Phenotype.Flush()
+		phenotype.Flush()
 	}
 
 	if (success) {
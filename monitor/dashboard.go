@@ -0,0 +1,144 @@
+package monitor
+
+// The single-page dashboard visualizing messages streamed by server. It plots the best and the mean fitness per
+// generation, the sizes of species in the last generation, and draws the network of champion with inputs at the left,
+// outputs at the right and hidden nodes in between. The links with positive weights are red, with negative weights are
+// blue and disabled links are dashed.
+const dashboardHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>goNEAT monitor</title>
+<style>
+body { font-family: sans-serif; margin: 20px; color: #222; }
+h1 { font-size: 20px; }
+h2 { font-size: 16px; margin: 0 0 8px 0; }
+#status { margin-bottom: 12px; }
+.panel { display: inline-block; vertical-align: top; margin: 0 20px 20px 0; }
+svg { border: 1px solid #ccc; background: #fff; }
+</style>
+</head>
+<body>
+<h1>goNEAT monitor</h1>
+<div id="status">Connecting...</div>
+<div class="panel"><h2>Fitness</h2><svg id="fitness" width="600" height="300"></svg></div>
+<div class="panel"><h2>Species</h2><svg id="species" width="400" height="300"></svg></div>
+<div class="panel"><h2>Champion</h2><svg id="champion" width="600" height="400"></svg></div>
+<script>
+var generations = [];
+var NS = "http://www.w3.org/2000/svg";
+
+function el(svg, name, attrs, text) {
+	var e = document.createElementNS(NS, name);
+	for (var k in attrs) { e.setAttribute(k, attrs[k]); }
+	if (text !== undefined) { e.textContent = text; }
+	svg.appendChild(e);
+	return e;
+}
+
+function clear(id) {
+	var svg = document.getElementById(id);
+	while (svg.firstChild) { svg.removeChild(svg.firstChild); }
+	return svg;
+}
+
+function drawFitness() {
+	var svg = clear("fitness"), w = 600, h = 300, pad = 40;
+	if (generations.length == 0) { return; }
+	var min = Infinity, max = -Infinity;
+	generations.forEach(function(g) {
+		min = Math.min(min, g.best_fitness, g.mean_fitness);
+		max = Math.max(max, g.best_fitness, g.mean_fitness);
+	});
+	if (max == min) { max = min + 1; }
+	var x = function(i) { return pad + (w - 2 * pad) * (generations.length > 1 ? i / (generations.length - 1) : 0); };
+	var y = function(v) { return h - pad - (h - 2 * pad) * (v - min) / (max - min); };
+	el(svg, "line", {x1: pad, y1: h - pad, x2: w - pad, y2: h - pad, stroke: "#888"});
+	el(svg, "line", {x1: pad, y1: pad, x2: pad, y2: h - pad, stroke: "#888"});
+	el(svg, "text", {x: 2, y: pad, "font-size": 10}, max.toPrecision(4));
+	el(svg, "text", {x: 2, y: h - pad, "font-size": 10}, min.toPrecision(4));
+	[["best_fitness", "#d62728"], ["mean_fitness", "#1f77b4"]].forEach(function(s, n) {
+		var points = generations.map(function(g, i) { return x(i) + "," + y(g[s[0]]); }).join(" ");
+		el(svg, "polyline", {points: points, fill: "none", stroke: s[1], "stroke-width": 2});
+		el(svg, "text", {x: w - pad - 100, y: 15 + 14 * n, fill: s[1], "font-size": 12}, s[0].replace("_", " "));
+	});
+}
+
+function drawSpecies(gen) {
+	var svg = clear("species"), w = 400, h = 300;
+	var species = gen.species || [];
+	if (species.length == 0) { return; }
+	var max = Math.max.apply(null, species.map(function(s) { return s.size; }));
+	var bar = Math.min(20, (h - 10) / species.length);
+	species.forEach(function(s, i) {
+		var len = (w - 120) * s.size / max;
+		el(svg, "rect", {x: 60, y: 5 + i * bar, width: len, height: bar - 2, fill: "#2ca02c"});
+		el(svg, "text", {x: 2, y: 5 + i * bar + bar - 5, "font-size": 10}, "#" + s.id);
+		el(svg, "text", {x: 64 + len, y: 5 + i * bar + bar - 5, "font-size": 10},
+			s.size + " (age " + s.age + ")");
+	});
+}
+
+function drawChampion(topology) {
+	var svg = clear("champion"), w = 600, h = 400, pad = 30;
+	if (!topology) { return; }
+	var columns = {INPT: [], BIAS: [], HIDN: [], OUTP: []};
+	topology.nodes.forEach(function(n) { (columns[n.type] || columns.HIDN).push(n); });
+	var layers = [columns.INPT.concat(columns.BIAS), columns.HIDN, columns.OUTP];
+	var pos = {};
+	layers.forEach(function(layer, l) {
+		layer.forEach(function(n, i) {
+			pos[n.id] = {x: pad + (w - 2 * pad) * l / 2, y: pad + (h - 2 * pad) * (i + 0.5) / layer.length};
+		});
+	});
+	topology.links.forEach(function(link) {
+		var a = pos[link.in], b = pos[link.out];
+		if (!a || !b) { return; }
+		el(svg, "line", {x1: a.x, y1: a.y, x2: b.x, y2: b.y,
+			stroke: link.weight >= 0 ? "#d62728" : "#1f77b4",
+			"stroke-width": Math.min(4, 0.5 + Math.abs(link.weight)),
+			"stroke-dasharray": link.enabled ? "" : "4,4", opacity: 0.7});
+	});
+	topology.nodes.forEach(function(n) {
+		var p = pos[n.id];
+		el(svg, "circle", {cx: p.x, cy: p.y, r: 10, fill: "#fff", stroke: "#222"}).appendChild(
+			document.createElementNS(NS, "title")).textContent = n.type + " " + n.activation;
+		el(svg, "text", {x: p.x, y: p.y + 4, "font-size": 9, "text-anchor": "middle"}, n.id);
+	});
+}
+
+function render(gen) {
+	document.getElementById("status").textContent = "Trial: " + gen.trial + ", generation: " + gen.generation +
+		", best fitness: " + gen.best_fitness.toPrecision(6) + ", species: " + gen.species.length +
+		(gen.solved ? ", SOLVED" : "");
+	drawFitness();
+	drawSpecies(gen);
+	drawChampion(gen.champion);
+}
+
+function connect() {
+	var ws = new WebSocket((location.protocol == "https:" ? "wss://" : "ws://") + location.host + "/ws");
+	ws.onopen = function() { document.getElementById("status").textContent = "Waiting for generations..."; };
+	ws.onmessage = function(event) {
+		var msg = JSON.parse(event.data);
+		if (msg.type == "history") {
+			generations = msg.history;
+		} else if (msg.type == "generation") {
+			if (generations.length > 0 && generations[generations.length - 1].trial != msg.generation.trial) {
+				// plot only the current trial
+				generations = [];
+			}
+			generations.push(msg.generation);
+			render(msg.generation);
+		}
+	};
+	ws.onclose = function() {
+		document.getElementById("status").textContent = "Disconnected, reconnecting...";
+		setTimeout(connect, 2000);
+	};
+}
+connect();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,396 @@
+// The monitor package provides small HTTP server streaming live statistics of experiment execution over WebSocket to
+// the bundled single-page dashboard, which plots the fitness curves, the sizes of species and the topology of champion
+// network as evolution goes.
+//
+// The server implements experiments.GenerationObserver and should be registered with experiment before execution:
+//
+//	server := monitor.NewServer()
+//	experiment.Observers = append(experiment.Observers, server)
+//	go http.ListenAndServe(":8081", server.Handler())
+//
+// The dashboard is served at "/", the stream of JSON messages at "/ws" and the history of generations statistics at
+// "/history". Each streamed message is JSON object with "type" field set either to "history", which is sent once after
+// dashboard connected and holds the statistics of previous generations in "history" field, or to "generation", which
+// holds the statistics of just evaluated generation with champion topology in "generation" field.
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The types of messages streamed to dashboard
+const (
+	// The message with statistics of previous generations sent after dashboard connected
+	HistoryMessage    = "history"
+	// The message with statistics of just evaluated generation
+	GenerationMessage = "generation"
+)
+
+// The number of messages queued per connected dashboard. The dashboard which doesn't keep up with the stream of
+// messages is disconnected.
+const clientQueueSize = 64
+
+// The message streamed to dashboard
+type Message struct {
+	// The type of message
+	Type       string `json:"type"`
+	// The statistics of generation for GenerationMessage
+	Generation *GenerationStats `json:"generation,omitempty"`
+	// The statistics of previous generations without champions topology for HistoryMessage
+	History    []*GenerationStats `json:"history,omitempty"`
+}
+
+// The statistics of evaluated generation. The values which are not finite numbers are replaced with zero to be
+// encoded as JSON.
+type GenerationStats struct {
+	// The ID of trial and generation
+	Trial       int `json:"trial"`
+	Generation  int `json:"generation"`
+	// The fitness of the best organism of generation
+	BestFitness float64 `json:"best_fitness"`
+	// The mean fitness of organisms in population
+	MeanFitness float64 `json:"mean_fitness"`
+	// The number of organisms evaluated in generation
+	Evaluations int `json:"evaluations"`
+	// The flag to indicate whether the solution found in generation
+	Solved      bool `json:"solved"`
+	// The statistics of species in population
+	Species     []SpeciesStats `json:"species"`
+	// The topology of the best organism of generation
+	Champion    *Topology `json:"champion,omitempty"`
+}
+
+// The statistics of species used to plot species charts
+type SpeciesStats struct {
+	// The ID of species
+	Id             int `json:"id"`
+	// The number of organisms in species
+	Size           int `json:"size"`
+	// The age of species
+	Age            int `json:"age"`
+	// The maximal and the average fitness of organisms in species
+	MaxFitness     float64 `json:"max_fitness"`
+	AverageFitness float64 `json:"average_fitness"`
+}
+
+// The topology of genome network to be drawn by dashboard
+type Topology struct {
+	// The ID of genome
+	GenomeId int `json:"genome_id"`
+	// The nodes of network
+	Nodes    []TopologyNode `json:"nodes"`
+	// The links between nodes
+	Links    []TopologyLink `json:"links"`
+}
+
+// The node of network topology
+type TopologyNode struct {
+	// The ID of node
+	Id         int `json:"id"`
+	// The neuron type name of node, e.g. INPT or HIDN
+	Type       string `json:"type"`
+	// The name of activation function of node
+	Activation string `json:"activation"`
+}
+
+// The link of network topology
+type TopologyLink struct {
+	// The IDs of nodes connected by link
+	In        int `json:"in"`
+	Out       int `json:"out"`
+	// The weight of link
+	Weight    float64 `json:"weight"`
+	// The flag to indicate whether link gene is enabled
+	Enabled   bool `json:"enabled"`
+	// The flag to indicate whether link is recurrent
+	Recurrent bool `json:"recurrent"`
+}
+
+// Creates statistics of generation evaluated in given population
+func NewGenerationStats(epoch *experiments.Generation, pop *genetics.Population) *GenerationStats {
+	stats := &GenerationStats{
+		Trial:epoch.TrialId,
+		Generation:epoch.Id,
+		Evaluations:epoch.Evaluations,
+		Solved:epoch.Solved,
+		Species:make([]SpeciesStats, 0, len(pop.Species)),
+	}
+	if epoch.Best != nil {
+		stats.BestFitness = finite(epoch.Best.Fitness)
+		if epoch.Best.Genotype != nil {
+			stats.Champion = NewTopology(epoch.Best.Genotype)
+		}
+	}
+	stats.MeanFitness = finite(meanFitness(pop.Organisms))
+	for _, sp := range pop.Species {
+		sp_stats := SpeciesStats{
+			Id:sp.Id,
+			Size:len(sp.Organisms),
+			Age:sp.Age,
+			AverageFitness:finite(meanFitness(sp.Organisms)),
+		}
+		for i, org := range sp.Organisms {
+			if i == 0 || org.Fitness > sp_stats.MaxFitness {
+				sp_stats.MaxFitness = org.Fitness
+			}
+		}
+		sp_stats.MaxFitness = finite(sp_stats.MaxFitness)
+		stats.Species = append(stats.Species, sp_stats)
+	}
+	return stats
+}
+
+// Creates topology of given genome network
+func NewTopology(g *genetics.Genome) *Topology {
+	topology := &Topology{
+		GenomeId:g.Id,
+		Nodes:make([]TopologyNode, 0, len(g.Nodes)),
+		Links:make([]TopologyLink, 0, len(g.Genes)),
+	}
+	for _, node := range g.Nodes {
+		activation, err := utils.NodeActivators.ActivationNameFromType(node.ActivationType)
+		if err != nil {
+			activation = ""
+		}
+		topology.Nodes = append(topology.Nodes, TopologyNode{
+			Id:node.Id,
+			Type:network.NeuronTypeName(node.NeuronType),
+			Activation:activation,
+		})
+	}
+	for _, gene := range g.Genes {
+		topology.Links = append(topology.Links, TopologyLink{
+			In:gene.Link.InNode.Id,
+			Out:gene.Link.OutNode.Id,
+			Weight:finite(gene.Link.Weight),
+			Enabled:gene.IsEnabled,
+			Recurrent:gene.Link.IsRecurrent,
+		})
+	}
+	return topology
+}
+
+// The server streaming statistics of evaluated generations to connected dashboards. It is safe for concurrent use.
+type Server struct {
+	// The maximal number of generations kept in history sent to newly connected dashboards, unlimited if zero
+	HistoryLimit int
+
+	// The mutex to guard connected clients and history
+	mutex        sync.Mutex
+	// The connected dashboards
+	clients      map[*client]bool
+	// The statistics of published generations without champions topology
+	history      []*GenerationStats
+	// The encoded message with statistics of the last published generation
+	latest       []byte
+}
+
+// Creates new server without connected dashboards
+func NewServer() *Server {
+	return &Server{clients:make(map[*client]bool)}
+}
+
+// Returns HTTP handler serving the dashboard, the stream of messages and the history of generations
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, dashboardHTML)
+	})
+	mux.HandleFunc("/ws", s.serveStream)
+	mux.HandleFunc("/history", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.History()); err != nil {
+			neat.ErrorLog(fmt.Sprintf("MONITOR: Failed to encode history, reason: %s", err))
+		}
+	})
+	return mux
+}
+
+// Publishes statistics of generation evaluated in given population to connected dashboards
+func (s *Server) OnGenerationEvaluated(epoch *experiments.Generation, pop *genetics.Population) {
+	s.Publish(NewGenerationStats(epoch, pop))
+}
+
+// Publishes given statistics of generation to connected dashboards and stores it into history
+func (s *Server) Publish(stats *GenerationStats) {
+	data, err := json.Marshal(Message{Type:GenerationMessage, Generation:stats})
+	if err != nil {
+		neat.ErrorLog(fmt.Sprintf("MONITOR: Failed to encode generation statistics, reason: %s", err))
+		return
+	}
+	entry := *stats
+	entry.Champion = nil
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.history = append(s.history, &entry)
+	if s.HistoryLimit > 0 && len(s.history) > s.HistoryLimit {
+		s.history = s.history[len(s.history) - s.HistoryLimit:]
+	}
+	s.latest = data
+	for c := range s.clients {
+		s.enqueue(c, frame{opcode:textFrame, payload:data})
+	}
+}
+
+// Returns the statistics of published generations without champions topology. The returned slice is a copy and can
+// be modified by caller.
+func (s *Server) History() []*GenerationStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*GenerationStats{}, s.history...)
+}
+
+// Disconnects all connected dashboards
+func (s *Server) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for c := range s.clients {
+		s.enqueue(c, frame{opcode:closeFrame})
+		s.unregister(c)
+	}
+}
+
+// Serves the stream of messages to dashboard connected over WebSocket
+func (s *Server) serveStream(w http.ResponseWriter, r *http.Request) {
+	conn, rw, err := upgrade(w, r)
+	if err != nil {
+		neat.WarnLog(fmt.Sprintf("MONITOR: Failed to connect dashboard, reason: %s", err))
+		return
+	}
+	c := &client{conn:conn, frames:make(chan frame, clientQueueSize)}
+	if err = s.register(c); err != nil {
+		neat.ErrorLog(fmt.Sprintf("MONITOR: Failed to encode history, reason: %s", err))
+		conn.Close()
+		return
+	}
+	go c.write(rw.Writer)
+
+	// read frames of client to reply on control frames until connection closed
+	for {
+		opcode, payload, err := readFrame(rw.Reader, maxClientFrameSize)
+		if err != nil {
+			break
+		}
+		if opcode == closeFrame {
+			s.send(c, frame{opcode:closeFrame, payload:payload})
+			break
+		}
+		if opcode == pingFrame {
+			s.send(c, frame{opcode:pongFrame, payload:payload})
+		}
+	}
+	s.mutex.Lock()
+	s.unregister(c)
+	s.mutex.Unlock()
+}
+
+// Registers connected client and queues the history of generations followed by the last generation to be sent
+func (s *Server) register(c *client) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clients[c] = true
+	if len(s.history) == 0 {
+		return nil
+	}
+	// the last generation is sent with champion topology by separate message
+	data, err := json.Marshal(Message{Type:HistoryMessage, History:s.history[:len(s.history) - 1]})
+	if err != nil {
+		s.unregister(c)
+		return err
+	}
+	s.enqueue(c, frame{opcode:textFrame, payload:data})
+	s.enqueue(c, frame{opcode:textFrame, payload:s.latest})
+	return nil
+}
+
+// Queues frame to be sent to client
+func (s *Server) send(c *client, f frame) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.enqueue(c, f)
+}
+
+// Queues frame to be sent to client if it is still registered. The client which queue is full is unregistered.
+// It should be invoked with mutex locked.
+func (s *Server) enqueue(c *client, f frame) {
+	if !s.clients[c] {
+		return
+	}
+	select {
+	case c.frames <- f:
+	default:
+		neat.WarnLog("MONITOR: Dashboard is too slow to receive messages, disconnecting")
+		s.unregister(c)
+	}
+}
+
+// Unregisters client, the queued frames will be sent before connection closed. It should be invoked with mutex locked.
+func (s *Server) unregister(c *client) {
+	if s.clients[c] {
+		delete(s.clients, c)
+		close(c.frames)
+	}
+}
+
+// The frame to be sent to client
+type frame struct {
+	opcode  byte
+	payload []byte
+}
+
+// The dashboard connected over WebSocket
+type client struct {
+	conn   net.Conn
+	// The queue of frames to be sent
+	frames chan frame
+}
+
+// Writes queued frames into connection until queue closed, after that connection closed
+func (c *client) write(w *bufio.Writer) {
+	defer c.conn.Close()
+	for f := range c.frames {
+		if err := writeFrame(w, f.opcode, f.payload); err != nil {
+			return
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// Returns the mean fitness of given organisms or zero if there are none
+func meanFitness(organisms []*genetics.Organism) float64 {
+	if len(organisms) == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	for _, org := range organisms {
+		sum += org.Fitness
+	}
+	return sum / float64(len(organisms))
+}
+
+// Returns given value if it is finite number or zero otherwise
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0.0
+	}
+	return v
+}
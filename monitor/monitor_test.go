@@ -0,0 +1,243 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"github.com/yaricom/goNEAT/experiments"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/genetics"
+)
+
+func buildTestGeneration(id int) (*experiments.Generation, *genetics.Population, error) {
+	rand.Seed(42)
+	start_genome, err := genetics.NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	pop, err := genetics.NewPopulation(start_genome, &neat.NeatContext{PopSize:10, CompatThreshold:0.5})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, org := range pop.Organisms {
+		org.Fitness = float64(i + 1)
+	}
+	epoch := &experiments.Generation{Id:id, TrialId:1, Evaluations:len(pop.Organisms)}
+	epoch.FillPopulationStatistics(pop)
+	return epoch, pop, nil
+}
+
+func TestNewGenerationStats(t *testing.T) {
+	epoch, pop, err := buildTestGeneration(3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	stats := NewGenerationStats(epoch, pop)
+	if stats.Trial != 1 || stats.Generation != 3 || stats.Evaluations != 10 {
+		t.Error("Wrong generation IDs", stats.Trial, stats.Generation, stats.Evaluations)
+	}
+	if stats.BestFitness != 10.0 || stats.MeanFitness != 5.5 {
+		t.Error("Wrong fitness", stats.BestFitness, stats.MeanFitness)
+	}
+	size := 0
+	for _, sp := range stats.Species {
+		size += sp.Size
+		if sp.MaxFitness < sp.AverageFitness {
+			t.Error("Wrong species fitness", sp)
+		}
+	}
+	if len(stats.Species) != len(pop.Species) || size != len(pop.Organisms) {
+		t.Error("Wrong species statistics", len(stats.Species), size)
+	}
+	champion := stats.Champion
+	if champion == nil || len(champion.Nodes) != 4 || len(champion.Links) != 3 {
+		t.Error("Wrong champion topology", champion)
+		return
+	}
+	if champion.Nodes[0].Type != "INPT" || champion.Nodes[3].Type != "OUTP" || champion.Links[0].Out != 4 {
+		t.Error("Wrong champion topology", champion.Nodes, champion.Links)
+	}
+
+	epoch.Best.Fitness = math.Inf(1)
+	pop.Organisms[0].Fitness = math.NaN()
+	stats = NewGenerationStats(epoch, pop)
+	if stats.BestFitness != 0.0 || stats.MeanFitness != 0.0 {
+		t.Error("The values which are not finite numbers should be replaced", stats.BestFitness, stats.MeanFitness)
+	}
+	if _, err := json.Marshal(stats); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestServer_Handler(t *testing.T) {
+	server := NewServer()
+	epoch, pop, err := buildTestGeneration(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	server.OnGenerationEvaluated(epoch, pop)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "goNEAT monitor") {
+		t.Error("The dashboard expected", string(body))
+	}
+
+	resp, err = http.Get(ts.URL + "/history")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var history []*GenerationStats
+	err = json.NewDecoder(resp.Body).Decode(&history)
+	resp.Body.Close()
+	if err != nil {
+		t.Error(err)
+	} else if len(history) != 1 || history[0].Generation != 1 || history[0].Champion != nil {
+		t.Error("Wrong history", history)
+	}
+
+	if resp, err = http.Get(ts.URL + "/ws"); err != nil {
+		t.Error(err)
+	} else if resp.StatusCode != http.StatusBadRequest {
+		t.Error("The request without WebSocket handshake should be rejected", resp.StatusCode)
+	}
+	if resp, err = http.Get(ts.URL + "/unknown"); err != nil {
+		t.Error(err)
+	} else if resp.StatusCode != http.StatusNotFound {
+		t.Error("Wrong status of unknown path", resp.StatusCode)
+	}
+}
+
+func TestServer_stream(t *testing.T) {
+	server := NewServer()
+	epoch, pop, err := buildTestGeneration(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	server.OnGenerationEvaluated(epoch, pop)
+	epoch.Id = 2
+	server.OnGenerationEvaluated(epoch, pop)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	conn, r, err := dialTestStream(ts.URL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	// the history is sent after connection followed by the last generation
+	if msg := readTestMessage(r); msg.Type != HistoryMessage || len(msg.History) != 1 || msg.History[0].Generation != 1 {
+		t.Error("Wrong history message", msg)
+	}
+	if msg := readTestMessage(r); msg.Type != GenerationMessage || msg.Generation.Generation != 2 ||
+		msg.Generation.Champion == nil {
+		t.Error("Wrong last generation message", msg)
+	}
+
+	epoch.Id = 3
+	server.OnGenerationEvaluated(epoch, pop)
+	if msg := readTestMessage(r); msg.Type != GenerationMessage || msg.Generation.Generation != 3 {
+		t.Error("Wrong generation message", msg)
+	}
+
+	if err := writeFrame(conn, pingFrame, []byte("ping")); err != nil {
+		t.Error(err)
+		return
+	}
+	if opcode, payload, err := readFrame(r, 1 << 20); err != nil || opcode != pongFrame || string(payload) != "ping" {
+		t.Error("Pong expected", opcode, string(payload), err)
+	}
+
+	server.Close()
+	if opcode, _, err := readFrame(r, 1 << 20); err != nil || opcode != closeFrame {
+		t.Error("Close frame expected", opcode, err)
+	}
+	if len(server.History()) != 3 {
+		t.Error("Wrong history size", len(server.History()))
+	}
+}
+
+func TestServer_HistoryLimit(t *testing.T) {
+	server := NewServer()
+	server.HistoryLimit = 2
+	epoch, pop, err := buildTestGeneration(0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i := 0; i < 5; i++ {
+		epoch.Id = i
+		server.OnGenerationEvaluated(epoch, pop)
+	}
+	history := server.History()
+	if len(history) != 2 || history[0].Generation != 3 || history[1].Generation != 4 {
+		t.Error("Wrong history", len(history))
+	}
+}
+
+// Connects to the stream of messages with WebSocket handshake
+func dialTestStream(url string) (net.Conn, *bufio.Reader, error) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		return nil, nil, err
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	req, err := http.NewRequest("GET", url + "/ws", nil)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err == nil {
+		if resp.StatusCode != http.StatusSwitchingProtocols ||
+			resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			err = errors.New(fmt.Sprintf("Wrong handshake response: %s", resp.Status))
+		}
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, r, nil
+}
+
+// Reads message of stream, returns empty message if failed
+func readTestMessage(r *bufio.Reader) *Message {
+	msg := &Message{}
+	if opcode, payload, err := readFrame(r, 1 << 20); err == nil && opcode == textFrame {
+		json.Unmarshal(payload, msg)
+	}
+	return msg
+}
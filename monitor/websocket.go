@@ -0,0 +1,151 @@
+package monitor
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// The GUID appended to the key of client to compute accept key of WebSocket handshake as defined by RFC 6455
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// The maximal size of payload of frame accepted from client. The dashboard sends only control frames, thus large
+// frames are not expected.
+const maxClientFrameSize = 1 << 16
+
+// The opcodes of WebSocket frames
+const (
+	textFrame  byte = 0x1
+	closeFrame byte = 0x8
+	pingFrame  byte = 0x9
+	pongFrame  byte = 0xA
+)
+
+// Returns the accept key of WebSocket handshake for given key of client
+func acceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key + websocketGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Upgrades HTTP connection to WebSocket protocol. Returns the hijacked connection with its buffered reader and writer.
+// If request is not valid WebSocket handshake the error response written and error returned.
+func upgrade(w http.ResponseWriter, r *http.Request) (net.Conn, *bufio.ReadWriter, error) {
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "WebSocket handshake expected", http.StatusBadRequest)
+		return nil, nil, errors.New("MONITOR: Not a WebSocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, nil, errors.New("MONITOR: Unsupported WebSocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if len(key) == 0 {
+		http.Error(w, "WebSocket key expected", http.StatusBadRequest)
+		return nil, nil, errors.New("MONITOR: WebSocket key missing")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, nil, errors.New("MONITOR: Connection can not be hijacked")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err = rw.Flush(); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, rw, nil
+}
+
+// Returns true if any of comma separated values of header with given name equals to value ignoring case
+func headerContains(header http.Header, name, value string) bool {
+	for _, v := range header[name] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Writes unfragmented frame with given opcode and payload. The frames sent by server are not masked.
+func writeFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch size := len(payload); {
+	case size < 126:
+		header[1] = byte(size)
+	case size <= 0xFFFF:
+		header[1] = 126
+		header = header[:4]
+		binary.BigEndian.PutUint16(header[2:], uint16(size))
+	default:
+		header[1] = 127
+		header = header[:10]
+		binary.BigEndian.PutUint64(header[2:], uint64(size))
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// Reads frame returning its opcode and unmasked payload. The frames with payload larger than limit are rejected.
+func readFrame(r io.Reader, limit int) (opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	opcode = header[0] & 0x0F
+	masked := header[1] & 0x80 != 0
+	size := uint64(header[1] & 0x7F)
+	switch size {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err = io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err = io.ReadFull(r, ext); err != nil {
+			return 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(ext)
+	}
+	if size > uint64(limit) {
+		return 0, nil, errors.New(fmt.Sprintf("MONITOR: Frame is too large: %d", size))
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i % 4]
+		}
+	}
+	return opcode, payload, nil
+}
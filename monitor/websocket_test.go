@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// the example from RFC 6455
+	if key := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); key != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Error("Wrong accept key", key)
+	}
+}
+
+func TestWriteFrame_readFrame(t *testing.T) {
+	for _, size := range []int{0, 5, 125, 126, 300, 70000} {
+		payload := bytes.Repeat([]byte{'a'}, size)
+		var buf bytes.Buffer
+		if err := writeFrame(&buf, textFrame, payload); err != nil {
+			t.Error(err)
+			continue
+		}
+		opcode, read, err := readFrame(&buf, 1 << 20)
+		if err != nil {
+			t.Error(size, err)
+			continue
+		}
+		if opcode != textFrame || !bytes.Equal(read, payload) {
+			t.Error("Wrong frame read", size, opcode, len(read))
+		}
+	}
+}
+
+func TestReadFrame_masked(t *testing.T) {
+	mask := []byte{1, 2, 3, 4}
+	payload := []byte("ping!")
+	data := []byte{0x80 | pingFrame, 0x80 | byte(len(payload))}
+	data = append(data, mask...)
+	for i, b := range payload {
+		data = append(data, b ^ mask[i % 4])
+	}
+	opcode, read, err := readFrame(bytes.NewReader(data), maxClientFrameSize)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if opcode != pingFrame || string(read) != "ping!" {
+		t.Error("Wrong masked frame read", opcode, string(read))
+	}
+}
+
+func TestReadFrame_tooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeFrame(&buf, textFrame, make([]byte, 200)); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, _, err := readFrame(&buf, 100); err == nil {
+		t.Error("Error expected for frame larger than limit")
+	}
+}
@@ -0,0 +1,224 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The migration topology type definition, i.e. the way islands of archipelago exchange organisms
+type MigrationTopology int
+
+const (
+	// The ring topology - each island sends migrants to the next one and the last island sends them to the first one
+	RingMigrationTopology MigrationTopology = iota
+	// The fully connected topology - each island sends migrants to all other islands
+	FullyConnectedMigrationTopology
+)
+
+// The archipelago of independent populations (islands) evolving in parallel. Periodically the best organisms of each
+// island migrate to other islands as defined by migration topology replacing the least fit organisms there. The
+// islands are evaluated as usual populations, after that the NextEpoch of archipelago should be invoked to perform
+// migration and to turnover all islands to the next generation.
+//
+// Note: the innovation numbers and node IDs are maintained by each island separately, thus the compatibility of migrants
+// with native organisms is only approximate.
+type Archipelago struct {
+	// The islands of archipelago
+	Islands           []*Population
+	// The number of generations between migrations. Zero disables migration.
+	MigrationInterval int
+	// The number of the most fit organisms migrating from each island
+	MigrationSize     int
+	// The migration topology
+	Topology          MigrationTopology
+
+	// The epoch executors of islands
+	executors         []PopulationEpochExecutor
+	// The objective direction defining the most fit organisms
+	direction         ObjectiveDirection
+}
+
+// Creates new archipelago with given number of islands each spawned off of genome g as configured by context. The
+// species IDs are unique among all islands. The migration is disabled by default.
+func NewArchipelago(g *Genome, islands int, context *neat.NeatContext) (*Archipelago, error) {
+	if islands <= 0 {
+		return nil, newError(ErrInvalidParameter, "ARCHIPELAGO: Wrong number of islands: %d", islands)
+	}
+	if context.PopSize <= 0 {
+		return nil, newError(ErrInvalidParameter,
+			"Wrong population size in the context: %d", context.PopSize)
+	}
+	a := &Archipelago{
+		Islands:make([]*Population, islands),
+		executors:make([]PopulationEpochExecutor, islands),
+		direction:objectiveDirection(context),
+	}
+	species_ids := &speciesIdSequence{}
+	for i := 0; i < islands; i++ {
+		pop := newPopulation()
+		pop.speciesIds = species_ids
+		if err := pop.spawn(g, context); err != nil {
+			return nil, err
+		}
+		a.Islands[i] = pop
+
+		var err error
+		if a.executors[i], err = epochExecutorForType(EpochExecutorType(context.EpochExecutorType)); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Performs migration if scheduled for given generation and turnovers all islands to the next generation in parallel.
+// It is assumed that all organisms of islands was evaluated before.
+func (a *Archipelago) NextEpoch(generation int, context *neat.NeatContext) error {
+	if a.MigrationInterval > 0 && generation % a.MigrationInterval == 0 {
+		if err := a.migrate(generation, context); err != nil {
+			return err
+		}
+	}
+
+	errs := make([]error, len(a.Islands))
+	var wg sync.WaitGroup
+	for i, island := range a.Islands {
+		wg.Add(1)
+		go func(i int, island *Population) {
+			defer wg.Done()
+			errs[i] = a.executors[i].NextEpoch(generation, island, context)
+		}(i, island)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return errors.New(fmt.Sprintf("ARCHIPELAGO: Island [%d] failed to turnover, reason: %s", i, err))
+		}
+	}
+	return nil
+}
+
+// Returns the most fit organism among all islands in the objective direction
+func (a *Archipelago) Champion() *Organism {
+	champions := make(Organisms, 0, len(a.Islands))
+	for _, island := range a.Islands {
+		if champion := a.direction.BestOrganism(island.Organisms); champion != nil {
+			champions = append(champions, champion)
+		}
+	}
+	return a.direction.BestOrganism(champions)
+}
+
+// Moves copies of the best organisms of each island to destination islands as defined by migration topology
+func (a *Archipelago) migrate(generation int, context *neat.NeatContext) error {
+	if len(a.Islands) < 2 || a.MigrationSize <= 0 {
+		return nil
+	}
+	// select emigrants before any island changed
+	emigrants := make([][]*Organism, len(a.Islands))
+	for i, island := range a.Islands {
+		emigrants[i] = island.bestOrganisms(a.MigrationSize, objectiveDirection(context))
+	}
+
+	for i := range a.Islands {
+		for _, dst := range a.migrationDestinations(i) {
+			neat.DebugLog(fmt.Sprintf("ARCHIPELAGO: Migrate %d organisms from island [%d] to island [%d]",
+				len(emigrants[i]), i, dst))
+			if err := a.Islands[dst].acceptMigrants(emigrants[i], a.Islands[i], generation, context); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns indexes of islands receiving migrants from the island with given index
+func (a *Archipelago) migrationDestinations(src int) []int {
+	switch a.Topology {
+	case FullyConnectedMigrationTopology:
+		dsts := make([]int, 0, len(a.Islands) - 1)
+		for i := range a.Islands {
+			if i != src {
+				dsts = append(dsts, i)
+			}
+		}
+		return dsts
+	default:
+		return []int{(src + 1) % len(a.Islands)}
+	}
+}
+
+// Returns up to count the most fit organisms of this population in given objective direction
+func (p *Population) bestOrganisms(count int, direction ObjectiveDirection) []*Organism {
+	sorted := make(Organisms, len(p.Organisms))
+	copy(sorted, p.Organisms)
+	sortByDirectedFitnessDesc(sorted, direction)
+	if count > len(sorted) {
+		count = len(sorted)
+	}
+	return sorted[:count]
+}
+
+// Replaces the least fit organisms of this population with copies of migrants from source population and speciates
+// them within this population.
+func (p *Population) acceptMigrants(migrants []*Organism, source *Population, generation int, context *neat.NeatContext) error {
+	// make sure that new structural innovations will not clash with migrants genes
+	if source.nextNodeId > p.nextNodeId {
+		p.nextNodeId = source.nextNodeId
+	}
+	if source.nextInnovNum > p.nextInnovNum {
+		p.nextInnovNum = source.nextInnovNum
+	}
+	return p.replaceLeastFit(migrants, generation, context)
+}
+
+// Replaces the least fit organisms of this population with copies of given organisms and speciates them within this
+// population
+func (p *Population) replaceLeastFit(migrants []*Organism, generation int, context *neat.NeatContext) error {
+	if len(migrants) > len(p.Organisms) {
+		migrants = migrants[:len(p.Organisms)]
+	}
+	sortByDirectedFitness(p.Organisms, objectiveDirection(context))
+	arrivals := make([]*Organism, len(migrants))
+	for i, migrant := range migrants {
+		// the least fit organism goes first
+		replaced := p.Organisms[i]
+		arrival, err := migrant.Clone()
+		if err != nil {
+			return err
+		}
+		arrival.Genotype.Id = replaced.Genotype.Id
+		arrival.Generation = generation
+		arrival.ExpectedOffspring = 0
+		if _, err = arrival.Phenotype(); err != nil {
+			return err
+		}
+		arrivals[i] = arrival
+
+		if replaced.Species != nil {
+			if _, err = replaced.Species.removeOrganism(replaced); err != nil {
+				return err
+			}
+		}
+		p.Organisms[i] = arrivals[i]
+	}
+	p.removeEmptySpecies(generation)
+
+	return p.speciate(arrivals, context)
+}
+
+// Returns epoch executor of given type
+func epochExecutorForType(executor_type EpochExecutorType) (PopulationEpochExecutor, error) {
+	switch executor_type {
+	case SequentialExecutorType:
+		return &SequentialPopulationEpochExecutor{}, nil
+	case ParallelExecutorType:
+		return &ParallelPopulationEpochExecutor{}, nil
+	case SteadyStateExecutorType:
+		return &SteadyStatePopulationEpochExecutor{}, nil
+	default:
+		return nil, newError(ErrUnsupportedType, "Unsupported epoch executor type requested: %d", executor_type)
+	}
+}
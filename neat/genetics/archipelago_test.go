@@ -0,0 +1,248 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func buildTestArchipelago(islands int) (*Archipelago, *neat.NeatContext, error) {
+	in, out, nmax, n := 3, 2, 15, 3
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 20,
+		BabiesStolen:5,
+		RecurOnlyProb:0.2,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, in, out, n, nmax, false, 0.8)
+	a, err := NewArchipelago(gen, islands, &conf)
+	return a, &conf, err
+}
+
+func TestNewArchipelago(t *testing.T) {
+	rand.Seed(42)
+	a, conf, err := buildTestArchipelago(3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(a.Islands) != 3 {
+		t.Error("Wrong number of islands", len(a.Islands))
+	}
+	for _, island := range a.Islands {
+		if len(island.Organisms) != conf.PopSize {
+			t.Error("Wrong island population size", len(island.Organisms))
+		}
+	}
+	if a.Islands[0] == a.Islands[1] {
+		t.Error("Islands should be independent")
+	}
+
+	if _, err = NewArchipelago(buildTestGenome(1), 0, conf); err == nil {
+		t.Error("Error expected for zero islands")
+	}
+}
+
+func TestArchipelago_migrationDestinations(t *testing.T) {
+	a := &Archipelago{Islands:make([]*Population, 3)}
+	if dsts := a.migrationDestinations(2); len(dsts) != 1 || dsts[0] != 0 {
+		t.Error("Wrong ring destinations", dsts)
+	}
+	a.Topology = FullyConnectedMigrationTopology
+	if dsts := a.migrationDestinations(1); len(dsts) != 2 || dsts[0] != 0 || dsts[1] != 2 {
+		t.Error("Wrong fully connected destinations", dsts)
+	}
+}
+
+func TestArchipelago_migrate(t *testing.T) {
+	rand.Seed(42)
+	a, conf, err := buildTestArchipelago(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	a.MigrationSize = 3
+	for i, org := range a.Islands[0].Organisms {
+		org.Fitness = float64(100 + i)
+	}
+	for _, org := range a.Islands[1].Organisms {
+		org.Fitness = 1.0
+	}
+
+	if err = a.migrate(1, conf); err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, island := range a.Islands {
+		if len(island.Organisms) != conf.PopSize {
+			t.Error("Island population size changed", len(island.Organisms))
+		}
+		count := 0
+		for _, sp := range island.Species {
+			if len(sp.Organisms) == 0 {
+				t.Error("Empty species left", sp.Id)
+			}
+			count += len(sp.Organisms)
+		}
+		if count != conf.PopSize {
+			t.Error("Organisms are not speciated", count)
+		}
+	}
+
+	// the best organisms of the first island should arrive to the second one
+	migrants := 0
+	for _, org := range a.Islands[1].Organisms {
+		if org.Fitness >= float64(100 + conf.PopSize - a.MigrationSize) {
+			migrants++
+			if org.Species == nil {
+				t.Error("Migrant has no species", org)
+			}
+		}
+	}
+	if migrants != a.MigrationSize {
+		t.Error("Wrong number of migrants", migrants)
+	}
+	// the migrants of the second island should replace the least fit organisms of the first one
+	for _, org := range a.Islands[0].Organisms {
+		if org.Fitness < 100 && org.Fitness != 1.0 {
+			t.Error("Wrong organism fitness", org.Fitness)
+		}
+	}
+}
+
+func TestArchipelago_migrate_minimize(t *testing.T) {
+	rand.Seed(42)
+	a, conf, err := buildTestArchipelago(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	conf.ObjectiveDirection = int(MinimizeObjective)
+	a.MigrationSize = 3
+	for i, org := range a.Islands[0].Organisms {
+		org.Fitness = float64(i + 1)
+	}
+	for _, org := range a.Islands[1].Organisms {
+		org.Fitness = 100.0
+	}
+
+	if err = a.migrate(1, conf); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the organisms with the lowest fitness of the first island should replace the worst ones of the second island
+	migrants := 0
+	for _, org := range a.Islands[1].Organisms {
+		if org.Fitness != 100.0 {
+			migrants++
+			if org.Fitness > float64(a.MigrationSize) {
+				t.Error("Wrong migrant fitness", org.Fitness)
+			}
+		}
+	}
+	if migrants != a.MigrationSize {
+		t.Error("Wrong number of migrants", migrants)
+	}
+	// the migrants of the second island should replace organisms with the highest fitness of the first one
+	for _, org := range a.Islands[0].Organisms {
+		if org.Fitness != 100.0 && org.Fitness > float64(conf.PopSize - a.MigrationSize) {
+			t.Error("The best organisms of island replaced by migrants", org.Fitness)
+		}
+	}
+}
+
+func TestArchipelago_Champion_minimize(t *testing.T) {
+	rand.Seed(42)
+	a, conf, err := buildTestArchipelago(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, island := range a.Islands {
+		for j, org := range island.Organisms {
+			org.Fitness = float64(10 * i + j + 1)
+		}
+	}
+	if champion := a.Champion(); champion != a.Islands[1].Organisms[conf.PopSize - 1] {
+		t.Error("Wrong champion of maximized objective", champion.Fitness)
+	}
+
+	conf.ObjectiveDirection = int(MinimizeObjective)
+	if a, err = NewArchipelago(buildTestGenome(1), 2, conf); err != nil {
+		t.Error(err)
+		return
+	}
+	for i, island := range a.Islands {
+		for j, org := range island.Organisms {
+			org.Fitness = float64(10 * i + j + 1)
+		}
+	}
+	if champion := a.Champion(); champion != a.Islands[0].Organisms[0] {
+		t.Error("Wrong champion of minimized objective", champion.Fitness)
+	}
+}
+
+func TestArchipelago_NextEpoch(t *testing.T) {
+	rand.Seed(42)
+	a, conf, err := buildTestArchipelago(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	a.MigrationInterval = 2
+	a.MigrationSize = 2
+	for generation := 1; generation <= 10; generation++ {
+		for _, island := range a.Islands {
+			for _, org := range island.Organisms {
+				org.Fitness = rand.Float64()
+			}
+		}
+		if err = a.NextEpoch(generation, conf); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	for _, island := range a.Islands {
+		if len(island.Organisms) == 0 {
+			t.Error("Island has no organisms")
+		}
+	}
+	if a.Champion() == nil {
+		t.Error("Champion not found")
+	}
+}
+
+func TestArchipelago_speciesIdsUnique(t *testing.T) {
+	rand.Seed(42)
+	a, conf, err := buildTestArchipelago(3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	a.MigrationInterval = 2
+	a.MigrationSize = 2
+	for generation := 1; generation <= 6; generation++ {
+		for _, island := range a.Islands {
+			for _, org := range island.Organisms {
+				org.Fitness = rand.Float64()
+			}
+		}
+		if err = a.NextEpoch(generation, conf); err != nil {
+			t.Error(err)
+			return
+		}
+		ids := make(map[int]bool)
+		for _, island := range a.Islands {
+			for _, sp := range island.Species {
+				if ids[sp.Id] {
+					t.Error("Species ID is not unique among islands", sp.Id)
+				}
+				ids[sp.Id] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The evaluator of matches between organisms of different populations of competitive co-evolution, e.g. games
+// between agents. It is invoked sequentially from the thread evaluating co-evolution.
+type MatchEvaluator interface {
+	// Plays the match between host organism and its opponent from another population or from hall of fame and returns
+	// the score of host in this match. The fitness of host is the average score of all matches it played in the
+	// generation.
+	EvaluateMatch(host, opponent *Organism, context *neat.NeatContext) (float64, error)
+}
+
+// The statistics of population of co-evolution collected after evaluation of generation
+type CoEvolutionStats struct {
+	// The generation of evaluation
+	Generation   int
+	// The index of population in co-evolution
+	Population   int
+	// The number of matches played by organisms of population
+	Matches      int
+	// The best and mean fitness of organisms in population
+	BestFitness  float64
+	MeanFitness  float64
+	// The ID of genome of the population champion
+	ChampionId   int
+	// The current size of hall of fame of population
+	HallOfFame   int
+}
+
+// The coordinator of competitive co-evolution of two or more populations, e.g. hosts and parasites. At each
+// generation the organisms of each population are evaluated against the shared sample of opponents drawn from every
+// other population and against the sample of champions of previous generations (hall of fame) of other populations,
+// which prevents cycling of forgotten strategies. The Evaluate should be invoked for each generation before NextEpoch,
+// which turnovers all populations together, thus populations stay synchronized by generation.
+type CoEvolution struct {
+	// The co-evolving populations
+	Populations      []*Population
+	// The number of opponents sampled from each other population for matches against organisms of population. If zero
+	// or greater than population size all organisms of other population are opponents.
+	SampleSize       int
+	// The maximal number of champions kept in hall of fame of each population, the oldest are dropped first. Zero
+	// disables the hall of fame.
+	HallOfFameSize   int
+	// The number of hall of fame opponents sampled from each other population for matches. If zero or greater than
+	// hall of fame size all champions in hall of fame are opponents.
+	HallOfFameSample int
+
+	// The hall of fame of each population
+	hallOfFame       [][]*Organism
+	// The epoch executors of populations
+	executors        []PopulationEpochExecutor
+	// The statistics of populations in evaluated generations
+	stats            []CoEvolutionStats
+	// The last evaluated generation, -1 if none
+	evaluated        int
+}
+
+// Creates new co-evolution of populations spawned off of given start genomes as configured by context, one population
+// per genome. The species IDs are unique among all populations.
+func NewCoEvolution(context *neat.NeatContext, genomes ...*Genome) (*CoEvolution, error) {
+	if len(genomes) < 2 {
+		return nil, newError(ErrInvalidParameter, "COEVOLUTION: At least two populations required, found: %d",
+			len(genomes))
+	}
+	if context.PopSize <= 0 {
+		return nil, newError(ErrInvalidParameter,
+			"Wrong population size in the context: %d", context.PopSize)
+	}
+	c := &CoEvolution{
+		Populations:make([]*Population, len(genomes)),
+		hallOfFame:make([][]*Organism, len(genomes)),
+		executors:make([]PopulationEpochExecutor, len(genomes)),
+		evaluated:-1,
+	}
+	species_ids := &speciesIdSequence{}
+	for i, g := range genomes {
+		pop := newPopulation()
+		pop.speciesIds = species_ids
+		if err := pop.spawn(g, context); err != nil {
+			return nil, err
+		}
+		c.Populations[i] = pop
+
+		var err error
+		if c.executors[i], err = epochExecutorForType(EpochExecutorType(context.EpochExecutorType)); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Evaluates organisms of all populations in given generation by matches against sampled opponents of other
+// populations and hall of fame champions. After evaluation the champion of each population enters its hall of fame
+// and statistics of populations are collected.
+func (c *CoEvolution) Evaluate(generation int, evaluator MatchEvaluator, context *neat.NeatContext) error {
+	// draw opponents before any fitness changed
+	opponents := make([][]*Organism, len(c.Populations))
+	for i, pop := range c.Populations {
+		opponents[i] = make([]*Organism, 0)
+		opponents[i] = append(opponents[i], sampleOrganisms(pop.Organisms, c.SampleSize, context.Rand())...)
+		opponents[i] = append(opponents[i], sampleOrganisms(c.hallOfFame[i], c.HallOfFameSample, context.Rand())...)
+	}
+
+	fitness := make([][]float64, len(c.Populations))
+	matches := make([]int, len(c.Populations))
+	for i, pop := range c.Populations {
+		fitness[i] = make([]float64, len(pop.Organisms))
+		for k, host := range pop.Organisms {
+			played := 0
+			for j := range c.Populations {
+				if j == i {
+					continue
+				}
+				for _, opponent := range opponents[j] {
+					score, err := evaluator.EvaluateMatch(host, opponent, context)
+					if err != nil {
+						return errors.New(fmt.Sprintf("COEVOLUTION: Match of organism [%d] from population [%d] failed, reason: %s",
+							host.Genotype.Id, i, err))
+					}
+					fitness[i][k] += score
+					played++
+				}
+			}
+			if played > 0 {
+				fitness[i][k] /= float64(played)
+			}
+			matches[i] += played
+		}
+	}
+
+	// assign fitness after all matches played, thus opponents fitness do not change during evaluation
+	direction := objectiveDirection(context)
+	for i, pop := range c.Populations {
+		total := 0.0
+		for k, org := range pop.Organisms {
+			org.Fitness = fitness[i][k]
+			if err := org.MarkEvaluated(); err != nil {
+				return err
+			}
+			total += org.Fitness
+		}
+		champion := direction.BestOrganism(pop.Organisms)
+		if champion == nil {
+			continue
+		}
+		if err := c.addToHallOfFame(i, champion); err != nil {
+			return err
+		}
+		c.stats = append(c.stats, CoEvolutionStats{
+			Generation:generation,
+			Population:i,
+			Matches:matches[i],
+			BestFitness:champion.Fitness,
+			MeanFitness:total / float64(len(pop.Organisms)),
+			ChampionId:champion.Genotype.Id,
+			HallOfFame:len(c.hallOfFame[i]),
+		})
+	}
+	c.evaluated = generation
+	return nil
+}
+
+// Turnovers all populations to the next generation in parallel. Returns error if given generation was not evaluated
+// yet, thus populations can not be turned over with stale fitness.
+func (c *CoEvolution) NextEpoch(generation int, context *neat.NeatContext) error {
+	if c.evaluated != generation {
+		return newError(ErrInvalidParameter, "COEVOLUTION: The generation [%d] is not evaluated, the last evaluated: %d",
+			generation, c.evaluated)
+	}
+	errs := make([]error, len(c.Populations))
+	var wg sync.WaitGroup
+	for i, pop := range c.Populations {
+		wg.Add(1)
+		go func(i int, pop *Population) {
+			defer wg.Done()
+			errs[i] = c.executors[i].NextEpoch(generation, pop, context)
+		}(i, pop)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return errors.New(fmt.Sprintf("COEVOLUTION: Population [%d] failed to turnover, reason: %s", i, err))
+		}
+	}
+	return nil
+}
+
+// Returns the champions of previous generations of population with given index, the oldest first. The returned slice
+// is a copy and can be modified by caller.
+func (c *CoEvolution) HallOfFame(population int) []*Organism {
+	hof := make([]*Organism, len(c.hallOfFame[population]))
+	copy(hof, c.hallOfFame[population])
+	return hof
+}
+
+// Returns the statistics of all populations in evaluated generations ordered by generation and population index. The
+// returned slice is a copy and can be modified by caller.
+func (c *CoEvolution) Stats() []CoEvolutionStats {
+	stats := make([]CoEvolutionStats, len(c.stats))
+	copy(stats, c.stats)
+	return stats
+}
+
+// Stores the copy of champion into the hall of fame of population with given index dropping the oldest champions if
+// hall of fame is full
+func (c *CoEvolution) addToHallOfFame(population int, champion *Organism) error {
+	if c.HallOfFameSize <= 0 {
+		return nil
+	}
+	clone, err := champion.Clone()
+	if err != nil {
+		return err
+	}
+	hof := append(c.hallOfFame[population], clone)
+	if len(hof) > c.HallOfFameSize {
+		hof = hof[len(hof) - c.HallOfFameSize:]
+	}
+	c.hallOfFame[population] = hof
+	return nil
+}
+
+// Returns random sample of given size from provided organisms without replacement, or all organisms if size is zero
+// or not less than the number of organisms. The sample is drawn by given random number generator.
+func sampleOrganisms(organisms []*Organism, size int, rng *rand.Rand) []*Organism {
+	if size <= 0 || size >= len(organisms) {
+		return organisms
+	}
+	sample := make([]*Organism, size)
+	for i, idx := range rng.Perm(len(organisms))[:size] {
+		sample[i] = organisms[idx]
+	}
+	return sample
+}
@@ -0,0 +1,195 @@
+package genetics
+
+import (
+	"testing"
+	"errors"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The match evaluator which scores host by the number of nodes in its genome and counts played matches
+type nodesMatchEvaluator struct {
+	matches int
+	fail    bool
+}
+
+func (e *nodesMatchEvaluator) EvaluateMatch(host, opponent *Organism, context *neat.NeatContext) (float64, error) {
+	if e.fail {
+		return 0, errors.New("match failed")
+	}
+	e.matches++
+	return float64(len(host.Genotype.Nodes)), nil
+}
+
+func buildTestCoEvolution() (*CoEvolution, *neat.NeatContext, error) {
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 10,
+		SurvivalThresh:0.5,
+		MutateAddLinkProb:0.1,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	hosts := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	parasites := newGenomeRand(2, 3, 2, 2, 5, false, 0.8)
+	c, err := NewCoEvolution(&conf, hosts, parasites)
+	return c, &conf, err
+}
+
+func TestNewCoEvolution(t *testing.T) {
+	rand.Seed(42)
+	c, conf, err := buildTestCoEvolution()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(c.Populations) != 2 {
+		t.Error("Wrong number of populations", len(c.Populations))
+		return
+	}
+	species := make(map[int]bool)
+	for _, pop := range c.Populations {
+		if len(pop.Organisms) != conf.PopSize {
+			t.Error("Wrong population size", len(pop.Organisms))
+		}
+		for _, sp := range pop.Species {
+			if species[sp.Id] {
+				t.Error("The species ID should be unique among populations", sp.Id)
+			}
+			species[sp.Id] = true
+		}
+	}
+
+	if _, err = NewCoEvolution(conf, buildTestGenome(1)); err == nil {
+		t.Error("Error expected for single population")
+	}
+	if _, err = NewCoEvolution(&neat.NeatContext{}, buildTestGenome(1), buildTestGenome(2)); err == nil {
+		t.Error("Error expected for zero population size")
+	}
+}
+
+func TestCoEvolution_Evaluate(t *testing.T) {
+	rand.Seed(42)
+	c, conf, err := buildTestCoEvolution()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	c.SampleSize = 3
+	c.HallOfFameSize = 2
+	evaluator := &nodesMatchEvaluator{}
+	if err = c.Evaluate(1, evaluator, conf); err != nil {
+		t.Error(err)
+		return
+	}
+	// each organism plays against the sample of other population, the hall of fame is empty yet
+	if evaluator.matches != 2 * conf.PopSize * 3 {
+		t.Error("Wrong number of matches", evaluator.matches)
+	}
+	for _, pop := range c.Populations {
+		for _, org := range pop.Organisms {
+			if org.Fitness != float64(len(org.Genotype.Nodes)) || org.State() != OrganismEvaluated {
+				t.Error("Wrong fitness of organism", org.Fitness, org.State())
+			}
+		}
+	}
+	stats := c.Stats()
+	if len(stats) != 2 || stats[0].Population != 0 || stats[1].Population != 1 || stats[0].Generation != 1 {
+		t.Error("Wrong statistics", stats)
+		return
+	}
+	if stats[0].BestFitness != 8 || stats[1].BestFitness != 7 || stats[0].Matches != conf.PopSize * 3 {
+		t.Error("Wrong statistics of populations", stats)
+	}
+
+	// the hall of fame champions join the opponents and the oldest are dropped
+	for gen := 2; gen <= 3; gen++ {
+		evaluator.matches = 0
+		if err = c.Evaluate(gen, evaluator, conf); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	if evaluator.matches != 2 * conf.PopSize * (3 + 2) {
+		t.Error("Wrong number of matches with hall of fame", evaluator.matches)
+	}
+	for i := range c.Populations {
+		hof := c.HallOfFame(i)
+		if len(hof) != 2 {
+			t.Error("Wrong hall of fame size", len(hof))
+		}
+		for _, champion := range hof {
+			for _, org := range c.Populations[i].Organisms {
+				if champion == org {
+					t.Error("The hall of fame should keep copies of champions")
+				}
+			}
+		}
+	}
+	if stats = c.Stats(); len(stats) != 6 || stats[5].HallOfFame != 2 || stats[5].Generation != 3 {
+		t.Error("Wrong statistics", stats)
+	}
+
+	if err = c.Evaluate(4, &nodesMatchEvaluator{fail:true}, conf); err == nil {
+		t.Error("Error expected for failed match")
+	}
+}
+
+func TestCoEvolution_NextEpoch(t *testing.T) {
+	rand.Seed(42)
+	c, conf, err := buildTestCoEvolution()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = c.NextEpoch(1, conf); err == nil {
+		t.Error("Error expected for not evaluated generation")
+	}
+	for gen := 1; gen <= 3; gen++ {
+		if err = c.Evaluate(gen, &nodesMatchEvaluator{}, conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if err = c.NextEpoch(gen, conf); err != nil {
+			t.Error(err)
+			return
+		}
+		for _, pop := range c.Populations {
+			if len(pop.Organisms) != conf.PopSize {
+				t.Error("Wrong population size after epoch", len(pop.Organisms))
+			}
+		}
+	}
+	if err = c.NextEpoch(2, conf); err == nil {
+		t.Error("Error expected for stale generation")
+	}
+}
+
+func Test_sampleOrganisms(t *testing.T) {
+	rand.Seed(42)
+	orgs := make([]*Organism, 5)
+	for i := range orgs {
+		orgs[i] = &Organism{Genotype:buildTestGenome(i + 1)}
+	}
+	if sample := sampleOrganisms(orgs, 0, rand.New(rand.NewSource(42))); len(sample) != 5 {
+		t.Error("All organisms expected for zero sample size", len(sample))
+	}
+	if sample := sampleOrganisms(orgs, 10, rand.New(rand.NewSource(42))); len(sample) != 5 {
+		t.Error("All organisms expected for oversized sample", len(sample))
+	}
+	sample := sampleOrganisms(orgs, 3, rand.New(rand.NewSource(42)))
+	if len(sample) != 3 {
+		t.Error("Wrong sample size", len(sample))
+		return
+	}
+	seen := make(map[*Organism]bool)
+	for _, org := range sample {
+		if seen[org] {
+			t.Error("The sample should be drawn without replacement")
+		}
+		seen[org] = true
+	}
+}
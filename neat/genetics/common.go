@@ -28,6 +28,32 @@ const (
 	goldGaussianMutator
 )
 
+// The method to keep link weights within bounds defined by WeightMin and WeightMax of context
+type WeightBoundType int
+// Available weight bound types
+const (
+	// The weight outside of bounds is set to the nearest bound
+	WeightBoundClamp WeightBoundType = iota
+	// The weight outside of bounds is reflected back from the bound by overshoot value
+	WeightBoundBounce
+)
+
+// The probability distribution of link weight perturbations scaled by the power of mutation
+type WeightPerturbationType int
+// Available weight perturbation types
+const (
+	// The uniform distribution within [-power, power]
+	UniformWeightPerturbation WeightPerturbationType = iota
+	// The normal distribution with standard deviation equal to power
+	GaussianWeightPerturbation
+	// The heavy-tailed Cauchy distribution with scale equal to power
+	CauchyWeightPerturbation
+	// The Laplace distribution with scale equal to power
+	LaplaceWeightPerturbation
+	// The normal distribution with standard deviation self-adapted by each gene, starting from power
+	AdaptiveWeightPerturbation
+)
+
 // Defines format of Genome data encoding
 type GenomeEncoding byte
 
@@ -36,6 +62,10 @@ const (
 	PlainGenomeEncoding GenomeEncoding = iota + 1
 	// The rich text in YAML
 	YAMLGenomeEncoding
+	// The text format produced by string representation of NEAT-Python DefaultGenome
+	NeatPythonGenomeEncoding
+	// The versioned JSON format carrying all genome features, see GenomeFormatVersion
+	JSONGenomeEncoding
 )
 
 var (
@@ -0,0 +1,201 @@
+package genetics
+
+import (
+	"math"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The connection cost type definition, i.e. the method to estimate the cost of network connections used as the
+// secondary objective of selection to encourage modular networks (Clune et al., 2013)
+type ConnectionCostType int
+
+const (
+	// The connection cost is not used
+	NoConnectionCost ConnectionCostType = iota
+	// The cost is the number of enabled links
+	LinkCountConnectionCost
+	// The cost is the summed length of enabled links with nodes placed on geometric layout (see Genome.Layout)
+	LinkLengthConnectionCost
+)
+
+// The point of geometric layout of genome's nodes
+type LayoutPoint struct {
+	X, Y float64
+}
+
+// Returns the cost of connections of this genome estimated by given method
+func (g *Genome) ConnectionCost(cost_type ConnectionCostType) (float64, error) {
+	switch cost_type {
+	case NoConnectionCost:
+		return 0.0, nil
+	case LinkCountConnectionCost:
+		count := 0
+		for _, gene := range g.Genes {
+			if gene.IsEnabled {
+				count++
+			}
+		}
+		return float64(count), nil
+	case LinkLengthConnectionCost:
+		layout := g.Layout()
+		cost := 0.0
+		for _, gene := range g.Genes {
+			if gene.IsEnabled {
+				in, out := layout[gene.Link.InNode], layout[gene.Link.OutNode]
+				cost += math.Hypot(out.X - in.X, out.Y - in.Y)
+			}
+		}
+		return cost, nil
+	default:
+		return 0.0, newError(ErrUnsupportedType, "GENOME: Unsupported connection cost type: %d", cost_type)
+	}
+}
+
+// Returns the geometric layout of genome's nodes within unit square. The nodes are placed in layers: inputs and bias
+// at the bottom (Y = 0), outputs at the top (Y = 1) and hidden nodes in between according to the length of the longest
+// path from inputs through enabled non-recurrent links. Within each layer the nodes are evenly spaced along X axis in
+// order of their appearance in genome.
+func (g *Genome) Layout() map[*network.NNode]LayoutPoint {
+	incoming := make(map[*network.NNode][]*network.NNode)
+	for _, gene := range g.Genes {
+		if gene.IsEnabled && !gene.Link.IsRecurrent {
+			incoming[gene.Link.OutNode] = append(incoming[gene.Link.OutNode], gene.Link.InNode)
+		}
+	}
+	depths := make(map[*network.NNode]int)
+	on_path := make(map[*network.NNode]bool)
+	max_depth := 0
+	for _, node := range g.Nodes {
+		if node.NeuronType == network.HiddenNeuron {
+			if d := layoutDepth(node, incoming, depths, on_path); d > max_depth {
+				max_depth = d
+			}
+		}
+	}
+
+	layers := make(map[int][]*network.NNode)
+	for _, node := range g.Nodes {
+		layer := 0
+		switch node.NeuronType {
+		case network.HiddenNeuron:
+			layer = depths[node]
+		case network.OutputNeuron:
+			layer = max_depth + 1
+		}
+		layers[layer] = append(layers[layer], node)
+	}
+	layout := make(map[*network.NNode]LayoutPoint, len(g.Nodes))
+	for layer, nodes := range layers {
+		for i, node := range nodes {
+			layout[node] = LayoutPoint{
+				X:float64(i + 1) / float64(len(nodes) + 1),
+				Y:float64(layer) / float64(max_depth + 1),
+			}
+		}
+	}
+	return layout
+}
+
+// Returns the length of the longest path from inputs to given node, the links closing loops are ignored
+func layoutDepth(node *network.NNode, incoming map[*network.NNode][]*network.NNode, depths map[*network.NNode]int,
+on_path map[*network.NNode]bool) int {
+	if node.NeuronType != network.HiddenNeuron {
+		return 0
+	}
+	if d, ok := depths[node]; ok {
+		return d
+	}
+	on_path[node] = true
+	depth := 1
+	for _, in := range incoming[node] {
+		if on_path[in] {
+			continue
+		}
+		if d := layoutDepth(in, incoming, depths, on_path) + 1; d > depth {
+			depth = d
+		}
+	}
+	on_path[node] = false
+	depths[node] = depth
+	return depth
+}
+
+// Returns fitness of given organisms replaced by the Pareto front rank of organism with respect to two objectives:
+// maximization of fitness and minimization of connection cost. The best (non-dominated) front gets the highest rank
+// equal to the number of fronts and the worst front gets rank 1. The cost objective is taken into account in each
+// pairwise comparison with probability ConnectionCostProb (stochastic Pareto dominance), thus the performance stays
+// the primary objective. Returns nil if connection cost is not configured by context.
+func connectionCostFitness(organisms Organisms, context *neat.NeatContext) (map[*Organism]float64, error) {
+	cost_type := ConnectionCostType(context.ConnectionCostType)
+	if cost_type == NoConnectionCost || len(organisms) == 0 {
+		return nil, nil
+	}
+	costs := make([]float64, len(organisms))
+	for i, org := range organisms {
+		cost, err := org.Genotype.ConnectionCost(cost_type)
+		if err != nil {
+			return nil, err
+		}
+		costs[i] = cost
+	}
+	prob := context.ConnectionCostProb
+	if prob <= 0 || prob > 1 {
+		prob = 1.0
+	}
+
+	// find dominance relations between all pairs of organisms
+	n := len(organisms)
+	dominates := make([][]int, n)
+	dominated_count := make([]int, n)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			use_cost := context.Rand().Float64() < prob
+			if paretoDominates(organisms[i].Fitness, costs[i], organisms[j].Fitness, costs[j], use_cost) {
+				dominates[i] = append(dominates[i], j)
+				dominated_count[j]++
+			} else if paretoDominates(organisms[j].Fitness, costs[j], organisms[i].Fitness, costs[i], use_cost) {
+				dominates[j] = append(dominates[j], i)
+				dominated_count[i]++
+			}
+		}
+	}
+
+	// sort organisms into fronts
+	fronts := make([][]int, 0)
+	front := make([]int, 0)
+	for i := 0; i < n; i++ {
+		if dominated_count[i] == 0 {
+			front = append(front, i)
+		}
+	}
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		next := make([]int, 0)
+		for _, i := range front {
+			for _, j := range dominates[i] {
+				if dominated_count[j]--; dominated_count[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		front = next
+	}
+
+	scaled := make(map[*Organism]float64, n)
+	for rank, front := range fronts {
+		for _, i := range front {
+			scaled[organisms[i]] = float64(len(fronts) - rank)
+		}
+	}
+	return scaled, nil
+}
+
+// Returns true if the first organism dominates the second one, i.e. it is not worse by any objective and better by
+// at least one. The cost objective is compared only if use_cost is set.
+func paretoDominates(fitness_a, cost_a, fitness_b, cost_b float64, use_cost bool) bool {
+	if !use_cost {
+		return fitness_a > fitness_b
+	}
+	return fitness_a >= fitness_b && cost_a <= cost_b && (fitness_a > fitness_b || cost_a < cost_b)
+}
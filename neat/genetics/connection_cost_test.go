@@ -0,0 +1,116 @@
+package genetics
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+func TestGenome_ConnectionCost(t *testing.T) {
+	gnome, err := NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cost, err := gnome.ConnectionCost(LinkCountConnectionCost); err != nil || cost != 3.0 {
+		t.Error("Wrong link count cost", cost, err)
+	}
+	// the inputs and bias at the bottom, the output at the top in the middle
+	expected := 2.0 * math.Hypot(0.25, 1.0) + 1.0
+	if cost, err := gnome.ConnectionCost(LinkLengthConnectionCost); err != nil || math.Abs(cost - expected) > 1e-9 {
+		t.Error("Wrong link length cost", cost, expected, err)
+	}
+	// the disabled links are not counted
+	gnome.Genes[0].IsEnabled = false
+	if cost, err := gnome.ConnectionCost(LinkCountConnectionCost); err != nil || cost != 2.0 {
+		t.Error("Wrong link count cost", cost, err)
+	}
+	if cost, err := gnome.ConnectionCost(NoConnectionCost); err != nil || cost != 0.0 {
+		t.Error("Zero cost expected", cost, err)
+	}
+	if _, err = gnome.ConnectionCost(100); err == nil {
+		t.Error("Error expected for unsupported connection cost type")
+	}
+}
+
+func TestGenome_Layout(t *testing.T) {
+	gnome, err := NewGenomeFullyConnected(2, 1, 2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	layout := gnome.Layout()
+	if len(layout) != len(gnome.Nodes) {
+		t.Error("All nodes should be placed", len(layout))
+		return
+	}
+	for _, node := range gnome.Nodes {
+		p := layout[node]
+		// the hidden nodes are in the middle layer
+		expected_y := 0.0
+		switch node.NeuronType {
+		case network.HiddenNeuron:
+			expected_y = 0.5
+		case network.OutputNeuron:
+			expected_y = 1.0
+		}
+		if p.Y != expected_y {
+			t.Error("Wrong layer of node", node.Id, p.Y, expected_y)
+		}
+		if p.X <= 0.0 || p.X >= 1.0 {
+			t.Error("Node is out of layout bounds", node.Id, p.X)
+		}
+	}
+}
+
+func TestConnectionCostFitness(t *testing.T) {
+	small, _ := NewGenomeFullyConnected(2, 1, 0)
+	big, _ := NewGenomeFullyConnected(2, 1, 2)
+	a := &Organism{Fitness:2.0, Genotype:small}
+	b := &Organism{Fitness:2.0, Genotype:big}
+	c := &Organism{Fitness:3.0, Genotype:big}
+	orgs := Organisms{a, b, c}
+
+	// the cost is always considered: the small network dominates the equally fit big one
+	conf := neat.NeatContext{ConnectionCostType:int(LinkCountConnectionCost)}
+	scaled, err := scaleFitness(orgs, 1, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, expected := range []float64{2.0, 1.0, 2.0} {
+		if scaled[orgs[i]] != expected {
+			t.Error("Wrong Pareto rank", i, scaled[orgs[i]], expected)
+		}
+	}
+	if a.Fitness != 2.0 || c.Fitness != 3.0 {
+		t.Error("Raw fitness changed")
+	}
+
+	// the cost is almost never considered: only fitness matters
+	conf.ConnectionCostProb = 1e-12
+	if scaled, err = connectionCostFitness(orgs, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	for i, expected := range []float64{1.0, 1.0, 2.0} {
+		if scaled[orgs[i]] != expected {
+			t.Error("Wrong Pareto rank", i, scaled[orgs[i]], expected)
+		}
+	}
+
+	// the connection cost can not be combined with fitness scaling
+	conf.FitnessScalingType = int(RankFitnessScaling)
+	if _, err = scaleFitness(orgs, 1, &conf); err == nil {
+		t.Error("Error expected for connection cost combined with fitness scaling")
+	}
+
+	conf = neat.NeatContext{ConnectionCostType:100}
+	if _, err = connectionCostFitness(orgs, &conf); err == nil {
+		t.Error("Error expected for unsupported connection cost type")
+	}
+	if scaled, err = connectionCostFitness(orgs, &neat.NeatContext{}); err != nil || scaled != nil {
+		t.Error("No connection cost expected", scaled, err)
+	}
+}
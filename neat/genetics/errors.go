@@ -0,0 +1,69 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The sentinel errors identifying failure modes of genetic evolution. The errors returned by this package wrap them
+// with detailed description of particular failure, thus callers can branch on failure mode with errors.Is, e.g.
+// errors.Is(err, ErrEmptySpecies).
+var (
+	// The species has no organisms, e.g. when reproduction out of empty species attempted
+	ErrEmptySpecies          = errors.New("empty species")
+	// The organism is not found, e.g. when removal of organism which doesn't belong to species attempted
+	ErrOrganismNotFound      = errors.New("organism not found")
+	// The genomes can not be mated, e.g. due to different number of traits
+	ErrIncompatibleGenomes   = errors.New("incompatible genomes")
+	// The genomes are not equal when compared
+	ErrGenomesMismatch       = errors.New("genomes mismatch")
+	// The genome has no elements needed for operation, e.g. no genes to mutate
+	ErrEmptyGenome           = errors.New("empty genome")
+	// The genome is malformed, e.g. it failed verification or can not be parsed
+	ErrMalformedGenome       = errors.New("malformed genome")
+	// The genome has values which are not finite numbers
+	ErrNonFiniteValues       = errors.New("non-finite values")
+	// The genome is recurrent while feed-forward only evolution configured
+	ErrRecurrentGenome       = errors.New("recurrent genome")
+	// The population has no organisms
+	ErrEmptyPopulation       = errors.New("empty population")
+	// The population has no species with organisms left to reproduce
+	ErrNoSpecies             = errors.New("no species")
+	// The best species of population died without offspring
+	ErrBestSpeciesDied       = errors.New("best species died")
+	// The number of offspring produced by reproduction cycle differs from expected
+	ErrProgenySizeMismatch   = errors.New("progeny size mismatch")
+	// The population has no organism eligible for operation, e.g. no organism old enough to be replaced
+	ErrNoEligibleOrganism    = errors.New("no eligible organism")
+	// The immigrant organism can not join population, e.g. due to different number of inputs or outputs
+	ErrIncompatibleImmigrant = errors.New("incompatible immigrant")
+	// The type requested by context or argument is not supported
+	ErrUnsupportedType       = errors.New("unsupported type")
+	// The value of context parameter or argument is invalid
+	ErrInvalidParameter      = errors.New("invalid parameter")
+	// The organism lifecycle state transition is not allowed, e.g. evaluation of archived organism
+	ErrIllegalOrganismState  = errors.New("illegal organism state")
+)
+
+// The error with detailed description of failure, which failure mode is identified by wrapped sentinel error
+type Error struct {
+	// The sentinel error identifying failure mode, e.g. ErrEmptySpecies
+	Code    error
+	// The detailed description of failure
+	Message string
+}
+
+// Returns the detailed description of failure
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Returns the sentinel error identifying failure mode
+func (e *Error) Unwrap() error {
+	return e.Code
+}
+
+// Creates new error with given failure mode and description formatted according to format specifier
+func newError(code error, format string, a ...interface{}) error {
+	return &Error{Code:code, Message:fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,71 @@
+package genetics
+
+import (
+	"errors"
+	"testing"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestError(t *testing.T) {
+	err := newError(ErrEmptyGenome, "Genome [%d] has no genes", 1)
+	if err.Error() != "Genome [1] has no genes" {
+		t.Error("Wrong error message", err)
+	}
+	if !errors.Is(err, ErrEmptyGenome) {
+		t.Error("!errors.Is(err, ErrEmptyGenome)")
+	}
+	if errors.Is(err, ErrMalformedGenome) {
+		t.Error("errors.Is(err, ErrMalformedGenome)")
+	}
+	var g_err *Error
+	if !errors.As(err, &g_err) || g_err.Code != ErrEmptyGenome {
+		t.Error("Failed to get error code", g_err)
+	}
+}
+
+// Tests that mating of genomes with different number of traits fails with ErrIncompatibleGenomes
+func TestGenome_mate_incompatibleGenomes(t *testing.T) {
+	gnome1 := buildTestGenome(1)
+	gnome2 := buildTestGenome(2)
+	gnome2.Traits = gnome2.Traits[:1]
+	context := &neat.NeatContext{}
+
+	if _, err := gnome1.mateMultipoint(gnome2, 3, 1.0, 2.0, context); !errors.Is(err, ErrIncompatibleGenomes) {
+		t.Error("mateMultipoint: !errors.Is(err, ErrIncompatibleGenomes)", err)
+	}
+	if _, err := gnome1.mateMultipointAvg(gnome2, 3, 1.0, 2.0, context); !errors.Is(err, ErrIncompatibleGenomes) {
+		t.Error("mateMultipointAvg: !errors.Is(err, ErrIncompatibleGenomes)", err)
+	}
+	if _, err := gnome1.mateSinglepoint(gnome2, 3, context); !errors.Is(err, ErrIncompatibleGenomes) {
+		t.Error("mateSinglepoint: !errors.Is(err, ErrIncompatibleGenomes)", err)
+	}
+}
+
+// Tests that genomes comparison fails with ErrGenomesMismatch
+func TestGenome_IsEqual_mismatch(t *testing.T) {
+	gnome1 := buildTestGenome(1)
+	gnome2 := buildTestGenome(1)
+	gnome2.Genes = gnome2.Genes[1:]
+
+	if equal, err := gnome1.IsEqual(gnome2); equal || !errors.Is(err, ErrGenomesMismatch) {
+		t.Error("!errors.Is(err, ErrGenomesMismatch)", equal, err)
+	}
+}
+
+// Tests that mutation of genome without genes fails with ErrEmptyGenome
+func TestGenome_mutate_emptyGenome(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes = nil
+
+	if _, err := gnome.mutateToggleEnable(1, utils.GlobalRand()); !errors.Is(err, ErrEmptyGenome) {
+		t.Error("!errors.Is(err, ErrEmptyGenome)", err)
+	}
+}
+
+// Tests that population can not be created with wrong size
+func TestNewPopulation_invalidParameter(t *testing.T) {
+	if _, err := NewPopulation(buildTestGenome(1), &neat.NeatContext{}); !errors.Is(err, ErrInvalidParameter) {
+		t.Error("!errors.Is(err, ErrInvalidParameter)", err)
+	}
+}
@@ -0,0 +1,194 @@
+package genetics
+
+import (
+	"math"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The fitness scaling type definition, i.e. the method to transform raw fitness of organisms before fitness sharing,
+// selection of parents and allocation of offspring
+type FitnessScalingType int
+
+const (
+	// The raw fitness used as is, unless some organisms have negative fitness (e.g. cost minimization domains). The
+	// fitness sharing and allocation of offspring need positive fitness, thus in this case the rank-based scaling is
+	// applied instead.
+	NoFitnessScaling FitnessScalingType = iota
+	// The rank-based scaling - organism's fitness replaced by its rank in population, where the least fit organism
+	// has rank 1 and the most fit has rank equal to population size. The organisms with equal fitness share the same
+	// average rank.
+	RankFitnessScaling
+	// The sigma scaling - fitness is replaced by 1 + (f - mean) / (2 * stdev) bounded from below by small positive
+	// value, thus selection pressure stays the same regardless of fitness magnitude and spread
+	SigmaFitnessScaling
+	// The Boltzmann scaling - fitness is replaced by exp(f / T) normalized by its population mean, where the
+	// temperature T decreases each generation according to cooling factor making selection pressure stronger
+	BoltzmannFitnessScaling
+)
+
+// The minimal scaled fitness value assigned to the worst organisms by sigma scaling
+const minSigmaScaledFitness = 0.1
+
+// The minimal temperature of Boltzmann scaling to avoid numerical overflow
+const minBoltzmannTemperature = 0.001
+
+// Returns scaled fitness of given organisms according to the fitness scaling type configured by context or nil if
+// fitness scaling is not configured. If connection cost is configured, the fitness is replaced by Pareto front rank
+// of organism with respect to fitness and connection cost, which can not be combined with fitness scaling. The fitness
+// of organisms is not changed. The scaled fitness is always higher for more fit organisms regardless of objective
+// direction configured by context.
+func scaleFitness(organisms Organisms, generation int, context *neat.NeatContext) (map[*Organism]float64, error) {
+	if len(organisms) == 0 {
+		return nil, nil
+	}
+	return scaleObjectiveFitness(organisms, context, func(orgs Organisms) (map[*Organism]float64, error) {
+		return scaleMaximizedFitness(orgs, generation, context)
+	})
+}
+
+// Returns fitness of given organisms scaled by provided function, which assumes that higher fitness is better. If
+// objective is minimized, the function is applied to copies of organisms with negated fitness, and the negated fitness
+// itself is returned if the function doesn't scale it (returns nil).
+func scaleObjectiveFitness(organisms Organisms, context *neat.NeatContext,
+scale func(Organisms) (map[*Organism]float64, error)) (map[*Organism]float64, error) {
+	if objectiveDirection(context) != MinimizeObjective {
+		return scale(organisms)
+	}
+	proxies := make(Organisms, len(organisms))
+	for i, org := range organisms {
+		proxy := *org
+		proxy.Fitness = -org.Fitness
+		proxies[i] = &proxy
+	}
+	scaled, err := scale(proxies)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[*Organism]float64, len(organisms))
+	for i, org := range organisms {
+		if scaled != nil {
+			result[org] = scaled[proxies[i]]
+		} else {
+			result[org] = proxies[i].Fitness
+		}
+	}
+	return result, nil
+}
+
+// Returns scaled fitness of given organisms like scaleFitness assuming that higher fitness is better
+func scaleMaximizedFitness(organisms Organisms, generation int, context *neat.NeatContext) (map[*Organism]float64, error) {
+	if ConnectionCostType(context.ConnectionCostType) != NoConnectionCost {
+		if FitnessScalingType(context.FitnessScalingType) != NoFitnessScaling {
+			return nil, newError(ErrInvalidParameter, "SELECTION: Connection cost can not be combined with fitness scaling")
+		}
+		return connectionCostFitness(organisms, context)
+	}
+	switch FitnessScalingType(context.FitnessScalingType) {
+	case NoFitnessScaling:
+		if hasNegativeFitness(organisms) {
+			return rankScaledFitness(organisms), nil
+		}
+		return nil, nil
+	case RankFitnessScaling:
+		return rankScaledFitness(organisms), nil
+	case SigmaFitnessScaling:
+		return sigmaScaledFitness(organisms), nil
+	case BoltzmannFitnessScaling:
+		return boltzmannScaledFitness(organisms, boltzmannTemperature(generation, context)), nil
+	default:
+		return nil, newError(ErrUnsupportedType,
+			"SELECTION: Unsupported fitness scaling type: %d", context.FitnessScalingType)
+	}
+}
+
+// Returns true if any of given organisms has negative fitness
+func hasNegativeFitness(organisms Organisms) bool {
+	for _, org := range organisms {
+		if org.Fitness < 0.0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the rank of each organism in ascending order of fitness
+func rankScaledFitness(organisms Organisms) map[*Organism]float64 {
+	sorted := make(Organisms, len(organisms))
+	copy(sorted, organisms)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Fitness < sorted[j].Fitness
+	})
+
+	scaled := make(map[*Organism]float64, len(sorted))
+	for start := 0; start < len(sorted); {
+		// find organisms with the same fitness and give them the average rank
+		end := start + 1
+		for end < len(sorted) && sorted[end].Fitness == sorted[start].Fitness {
+			end++
+		}
+		rank := float64(start + end + 1) / 2.0
+		for i := start; i < end; i++ {
+			scaled[sorted[i]] = rank
+		}
+		start = end
+	}
+	return scaled
+}
+
+// Returns the sigma scaled fitness of each organism
+func sigmaScaledFitness(organisms Organisms) map[*Organism]float64 {
+	mean := 0.0
+	for _, org := range organisms {
+		mean += org.Fitness
+	}
+	mean /= float64(len(organisms))
+	variance := 0.0
+	for _, org := range organisms {
+		variance += (org.Fitness - mean) * (org.Fitness - mean)
+	}
+	stdev := math.Sqrt(variance / float64(len(organisms)))
+
+	scaled := make(map[*Organism]float64, len(organisms))
+	for _, org := range organisms {
+		if stdev == 0 {
+			// all organisms are equally fit
+			scaled[org] = 1.0
+		} else {
+			scaled[org] = math.Max(minSigmaScaledFitness, 1.0 + (org.Fitness - mean) / (2.0 * stdev))
+		}
+	}
+	return scaled
+}
+
+// Returns the Boltzmann scaled fitness of each organism for given temperature
+func boltzmannScaledFitness(organisms Organisms, temperature float64) map[*Organism]float64 {
+	// subtract the maximal fitness to avoid overflow, it is canceled out by normalization
+	max_fitness := math.Inf(-1)
+	for _, org := range organisms {
+		max_fitness = math.Max(max_fitness, org.Fitness)
+	}
+	scaled := make(map[*Organism]float64, len(organisms))
+	mean := 0.0
+	for _, org := range organisms {
+		scaled[org] = math.Exp((org.Fitness - max_fitness) / temperature)
+		mean += scaled[org]
+	}
+	mean /= float64(len(organisms))
+	for org := range scaled {
+		scaled[org] /= mean
+	}
+	return scaled
+}
+
+// Returns the temperature of Boltzmann scaling at given generation as configured by context
+func boltzmannTemperature(generation int, context *neat.NeatContext) float64 {
+	temperature := context.BoltzmannTemperature
+	if temperature <= 0 {
+		temperature = 1.0
+	}
+	if cooling := context.BoltzmannCooling; cooling > 0 && cooling < 1 {
+		temperature *= math.Pow(cooling, float64(generation))
+	}
+	return math.Max(minBoltzmannTemperature, temperature)
+}
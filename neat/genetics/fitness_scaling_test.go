@@ -0,0 +1,279 @@
+package genetics
+
+import (
+	"testing"
+	"math"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func TestScaleFitness(t *testing.T) {
+	orgs := buildOrganismsWithFitness(1.0, 2.0, 3.0)
+	conf := neat.NeatContext{FitnessScalingType:int(NoFitnessScaling)}
+	if scaled, err := scaleFitness(orgs, 1, &conf); err != nil || scaled != nil {
+		t.Error("No scaling expected", scaled, err)
+	}
+
+	for _, scaling := range []FitnessScalingType{RankFitnessScaling, SigmaFitnessScaling, BoltzmannFitnessScaling} {
+		conf.FitnessScalingType = int(scaling)
+		scaled, err := scaleFitness(orgs, 1, &conf)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if len(scaled) != len(orgs) {
+			t.Error("Wrong number of scaled values", scaling, len(scaled))
+			continue
+		}
+		// the order of organisms should be preserved and fitness should not change
+		for i := 1; i < len(orgs); i++ {
+			if scaled[orgs[i]] <= scaled[orgs[i - 1]] {
+				t.Error("Scaling should preserve order of organisms", scaling, scaled[orgs[i]], scaled[orgs[i - 1]])
+			}
+			if orgs[i].Fitness != float64(i + 1) {
+				t.Error("Raw fitness changed", orgs[i].Fitness)
+			}
+		}
+	}
+
+	// the negative fitness is rank scaled even if scaling is not configured
+	conf.FitnessScalingType = int(NoFitnessScaling)
+	neg_orgs := buildOrganismsWithFitness(-3.0, 0.0, -1.0)
+	if scaled, err := scaleFitness(neg_orgs, 1, &conf); err != nil || len(scaled) != 3 {
+		t.Error("Rank scaling expected for negative fitness", scaled, err)
+	} else if scaled[neg_orgs[0]] != 1.0 || scaled[neg_orgs[1]] != 3.0 || scaled[neg_orgs[2]] != 2.0 {
+		t.Error("Wrong ranks of negative fitness", scaled)
+	}
+
+	// the lower fitness is scaled higher if objective is minimized
+	conf.ObjectiveDirection = int(MinimizeObjective)
+	for _, scaling := range []FitnessScalingType{NoFitnessScaling, RankFitnessScaling, SigmaFitnessScaling} {
+		conf.FitnessScalingType = int(scaling)
+		scaled, err := scaleFitness(orgs, 1, &conf)
+		if err != nil || len(scaled) != len(orgs) {
+			t.Error("Scaled fitness expected for minimized objective", scaling, scaled, err)
+			continue
+		}
+		for i := 1; i < len(orgs); i++ {
+			if scaled[orgs[i]] >= scaled[orgs[i - 1]] {
+				t.Error("Scaling should reverse order of organisms", scaling, scaled[orgs[i]], scaled[orgs[i - 1]])
+			}
+		}
+	}
+	conf.FitnessScalingType = int(NoFitnessScaling)
+	if scaled, _ := scaleFitness(neg_orgs, 1, &conf); scaled[neg_orgs[0]] != 3.0 {
+		t.Error("The lowest negative fitness should be the best", scaled)
+	}
+	conf.ObjectiveDirection = int(MaximizeObjective)
+
+	conf.FitnessScalingType = 100
+	if _, err := scaleFitness(orgs, 1, &conf); err == nil {
+		t.Error("Error expected for unsupported fitness scaling type")
+	}
+}
+
+func TestRankScaledFitness(t *testing.T) {
+	orgs := buildOrganismsWithFitness(1000.0, 0.001, 5.0, 5.0)
+	scaled := rankScaledFitness(orgs)
+	expected := []float64{4.0, 1.0, 2.5, 2.5}
+	for i, org := range orgs {
+		if scaled[org] != expected[i] {
+			t.Error("Wrong rank", i, scaled[org], expected[i])
+		}
+	}
+}
+
+func TestSigmaScaledFitness(t *testing.T) {
+	orgs := buildOrganismsWithFitness(1.0, 3.0, 100.0, 102.0)
+	scaled := sigmaScaledFitness(orgs)
+	// the mean fitness 51.5, stdev 49.505...
+	for _, org := range orgs {
+		if scaled[org] < minSigmaScaledFitness {
+			t.Error("Scaled fitness below minimum", scaled[org])
+		}
+	}
+	if math.Abs(scaled[orgs[3]] + scaled[orgs[0]] - 2.0) > 1e-9 {
+		t.Error("Scaled fitness should be symmetric around 1.0", scaled[orgs[0]], scaled[orgs[3]])
+	}
+
+	// the magnitude of fitness doesn't matter
+	big_orgs := buildOrganismsWithFitness(1.0e6, 3.0e6, 100.0e6, 102.0e6)
+	big_scaled := sigmaScaledFitness(big_orgs)
+	for i := range orgs {
+		if math.Abs(scaled[orgs[i]] - big_scaled[big_orgs[i]]) > 1e-9 {
+			t.Error("Sigma scaling should not depend on fitness magnitude", scaled[orgs[i]], big_scaled[big_orgs[i]])
+		}
+	}
+
+	// equally fit organisms
+	orgs = buildOrganismsWithFitness(2.0, 2.0)
+	scaled = sigmaScaledFitness(orgs)
+	if scaled[orgs[0]] != 1.0 || scaled[orgs[1]] != 1.0 {
+		t.Error("Equal scaled fitness expected", scaled[orgs[0]], scaled[orgs[1]])
+	}
+}
+
+func TestBoltzmannScaledFitness(t *testing.T) {
+	orgs := buildOrganismsWithFitness(1.0, 2.0, 1.0e5)
+	scaled := boltzmannScaledFitness(orgs, 1.0)
+	mean := 0.0
+	for _, org := range orgs {
+		if math.IsNaN(scaled[org]) || math.IsInf(scaled[org], 0) {
+			t.Error("Scaled fitness overflow", scaled[org])
+		}
+		mean += scaled[org]
+	}
+	if math.Abs(mean / 3.0 - 1.0) > 1e-9 {
+		t.Error("Scaled fitness should be normalized by mean", mean / 3.0)
+	}
+
+	// the higher temperature the lower selection pressure
+	orgs = buildOrganismsWithFitness(1.0, 2.0)
+	hot, cold := boltzmannScaledFitness(orgs, 10.0), boltzmannScaledFitness(orgs, 0.5)
+	if hot[orgs[1]] / hot[orgs[0]] >= cold[orgs[1]] / cold[orgs[0]] {
+		t.Error("Selection pressure should increase with cooling", hot, cold)
+	}
+}
+
+func TestBoltzmannTemperature(t *testing.T) {
+	conf := neat.NeatContext{BoltzmannTemperature:2.0, BoltzmannCooling:0.5}
+	if temp := boltzmannTemperature(0, &conf); temp != 2.0 {
+		t.Error("Wrong initial temperature", temp)
+	}
+	if temp := boltzmannTemperature(2, &conf); temp != 0.5 {
+		t.Error("Wrong temperature after cooling", temp)
+	}
+	if temp := boltzmannTemperature(1000, &conf); temp != minBoltzmannTemperature {
+		t.Error("Temperature should be bounded", temp)
+	}
+
+	// defaults
+	conf = neat.NeatContext{}
+	if temp := boltzmannTemperature(10, &conf); temp != 1.0 {
+		t.Error("Wrong default temperature", temp)
+	}
+}
+
+func TestSequentialPopulationEpochExecutor_NextEpoch_fitnessScaling(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 30,
+		BabiesStolen:10,
+		RecurOnlyProb:0.2,
+		FitnessScalingType:int(RankFitnessScaling),
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	executor := SequentialPopulationEpochExecutor{}
+	for generation := 1; generation <= 5; generation++ {
+		// the raw fitness differs by orders of magnitude between generations
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64() * math.Pow(1000.0, float64(generation))
+		}
+		if err = executor.NextEpoch(generation, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(pop.Organisms) != conf.PopSize {
+			t.Error("Wrong population size", len(pop.Organisms))
+		}
+	}
+	// the population record should be set from raw fitness
+	if pop.HighestFitness < math.Pow(1000.0, 4.0) * 0.1 {
+		t.Error("The highest fitness should be raw fitness", pop.HighestFitness)
+	}
+}
+
+// Tests evolution with minimized objective, i.e. the lower fitness is better
+func TestSequentialPopulationEpochExecutor_NextEpoch_minimizeObjective(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:15,
+		PopSize: 30,
+		SurvivalThresh:0.2,
+		RecurOnlyProb:0.2,
+		ObjectiveDirection:int(MinimizeObjective),
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	executor := SequentialPopulationEpochExecutor{}
+	best := math.Inf(1)
+	for generation := 1; generation <= 5; generation++ {
+		var champion *Organism
+		for _, org := range pop.Organisms {
+			// the error decreases with generations
+			org.Fitness = rand.Float64() / float64(generation)
+			if champion == nil || org.Fitness < champion.Fitness {
+				champion = org
+			}
+		}
+		best = math.Min(best, champion.Fitness)
+		if err = executor.NextEpoch(generation, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if pop.Champion() != champion {
+			t.Error("The organism with the lowest fitness should be champion", generation, pop.Champion().Fitness)
+		}
+		if pop.EpochsHighestLastChanged != 0 && pop.HighestFitness <= champion.Fitness {
+			t.Error("The lower fitness should be recorded", pop.HighestFitness, champion.Fitness)
+		}
+	}
+	if pop.HighestFitness != best {
+		t.Error("The lowest fitness expected as record", best, pop.HighestFitness)
+	}
+}
+
+// Tests evolution in domain with negative fitness, e.g. cost minimization
+func TestSequentialPopulationEpochExecutor_NextEpoch_negativeFitness(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:15,
+		PopSize: 30,
+		SurvivalThresh:0.2,
+		RecurOnlyProb:0.2,
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	executor := SequentialPopulationEpochExecutor{}
+	best := math.Inf(-1)
+	for generation := 1; generation <= 5; generation++ {
+		for _, org := range pop.Organisms {
+			org.Fitness = -100.0 * rand.Float64()
+			best = math.Max(best, org.Fitness)
+		}
+		if err = executor.NextEpoch(generation, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(pop.Organisms) != conf.PopSize {
+			t.Error("Wrong population size", len(pop.Organisms))
+		}
+	}
+	// the negative fitness should be tracked as record
+	if pop.HighestFitness != best {
+		t.Error("The highest negative fitness expected", best, pop.HighestFitness)
+	}
+	for _, sp := range pop.Species {
+		if sp.MaxFitnessEver > 0 {
+			t.Error("The max fitness of species should be negative", sp.MaxFitnessEver)
+		}
+	}
+}
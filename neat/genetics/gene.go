@@ -24,6 +24,12 @@ type Gene struct {
 	MutationNum   float64
 	// If true the gene is enabled
 	IsEnabled     bool
+	// If true the gene is protected from mutations, i.e. its weight, trait, enabled status, delay and gate are never
+	// changed and the gene is never removed or split by evolution. The flag is inherited by offspring.
+	IsFrozen      bool
+
+	// The self-adapted standard deviation of link weight perturbations, zero if not yet adapted
+	mutationSigma float64
 }
 
 // Creates new Gene
@@ -39,8 +45,13 @@ recurrent bool, inov_num int64, mut_num float64) *Gene {
 
 // Construct a gene off of another gene as a duplicate
 func NewGeneCopy(g *Gene, trait *neat.Trait, in_node, out_node *network.NNode) *Gene {
-	return newGene(network.NewLinkWithTrait(trait, g.Link.Weight, in_node, out_node, g.Link.IsRecurrent),
-		g.InnovationNum, g.MutationNum, true)
+	gene := newGene(network.NewLinkWithTrait(trait, g.Link.Weight, in_node, out_node, g.Link.IsRecurrent),
+		g.InnovationNum, g.MutationNum, g.IsEnabled)
+	gene.mutationSigma = g.mutationSigma
+	gene.IsFrozen = g.IsFrozen
+	// the gating node should be resolved among nodes of the new genome
+	gene.Link.Delay, gene.Link.GateNode = g.Link.Delay, g.Link.GateNode
+	return gene
 }
 
 func newGene(link *network.Link, inov_num int64, mut_num float64, enabled bool) *Gene {
@@ -65,6 +76,15 @@ func (g *Gene) String() string {
 	if g.Link.Trait != nil {
 		trait_str = fmt.Sprintf(" Link's trait_id: %d", g.Link.Trait.Id)
 	}
+	if g.Link.Delay > 0 {
+		recurr_str += fmt.Sprintf(" -DELAY %d-", g.Link.Delay)
+	}
+	if g.Link.GateNode != nil {
+		recurr_str += fmt.Sprintf(" -GATE %d-", g.Link.GateNode.Id)
+	}
+	if g.IsFrozen {
+		enabl_str += " -FROZEN-"
+	}
 	return fmt.Sprintf("[Link (%4d ->%4d) INNOV (%4d, % .3f) Weight: % .3f %s%s%s : %s->%s]",
 		g.Link.InNode.Id, g.Link.OutNode.Id, g.InnovationNum, g.MutationNum, g.Link.Weight,
 		trait_str, enabl_str, recurr_str, g.Link.InNode, g.Link.OutNode)
@@ -41,3 +41,33 @@ func TestNewGeneCopy(t *testing.T) {
 		t.Error("g.IsEnabled != g1.IsEnabled", g.IsEnabled, g1.IsEnabled)
 	}
 }
+
+// Tests that disabled gene stays disabled after copy
+func TestNewGeneCopy_disabled(t *testing.T) {
+	nodes := []*network.NNode{
+		{Id:1, NeuronType: network.InputNeuron, ActivationType: utils.NullActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+		{Id:2, NeuronType: network.OutputNeuron, ActivationType: utils.SigmoidSteepenedActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+	}
+	g1 := NewGene(3.2, nodes[0], nodes[1], false, 42, 5.2)
+	g1.IsEnabled = false
+
+	g := NewGeneCopy(g1, nil, nodes[0], nodes[1])
+	if g.IsEnabled {
+		t.Error("Copy of disabled gene should be disabled")
+	}
+}
+
+// Tests that self-adapted mutation sigma preserved after copy
+func TestNewGeneCopy_mutationSigma(t *testing.T) {
+	nodes := []*network.NNode{
+		{Id:1, NeuronType: network.InputNeuron, ActivationType: utils.NullActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+		{Id:2, NeuronType: network.OutputNeuron, ActivationType: utils.SigmoidSteepenedActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+	}
+	g1 := NewGene(3.2, nodes[0], nodes[1], false, 42, 5.2)
+	g1.mutationSigma = 0.7
+
+	g := NewGeneCopy(g1, nil, nodes[0], nodes[1])
+	if g.mutationSigma != g1.mutationSigma {
+		t.Error("g.mutationSigma != g1.mutationSigma", g.mutationSigma, g1.mutationSigma)
+	}
+}
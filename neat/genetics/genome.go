@@ -7,9 +7,10 @@ import (
 	"math/rand"
 	"io"
 	"fmt"
-	"errors"
 	"math"
 	"reflect"
+	"bytes"
+	"text/tabwriter"
 )
 
 // A Genome is the primary source of genotype information used to create  a phenotype.
@@ -38,13 +39,19 @@ type Genome struct {
 	Genes        []*Gene
 	// List of MIMO control genes
 	ControlGenes []*MIMOControlGene
+	// The optional free-form metadata (e.g. experiment name or origin), persisted by JSONGenomeEncoding and kept by
+	// Clone, but not inherited by offspring
+	Metadata     map[string]string
 
-	// Allows Genome to be matched with its Network
+	// Allows Genome to be matched with its Network. It is the cache of network built by Genesis which is discarded
+	// when genome mutated.
 	Phenotype    *network.Network
 }
 
-// Constructor which takes full genome specs and puts them into the new one
+// Constructor which takes full genome specs and puts them into the new one. The gating nodes of genes are resolved by
+// ID among provided nodes, thus genes copied from other genome refer nodes of this one.
 func NewGenome(id int, t []*neat.Trait, n []*network.NNode, g []*Gene) *Genome {
+	resolveGateNodes(g, n)
 	return &Genome{
 		Id:id,
 		Traits:t,
@@ -53,8 +60,9 @@ func NewGenome(id int, t []*neat.Trait, n []*network.NNode, g []*Gene) *Genome {
 	}
 }
 
-// Constructs new modular genome
+// Constructs new modular genome. The gating nodes of genes are resolved the same way as by NewGenome.
 func NewModularGenome(id int, t []*neat.Trait, n []*network.NNode, g []*Gene, mimoG []*MIMOControlGene) *Genome {
+	resolveGateNodes(g, n)
 	return &Genome{
 		Id:id,
 		Traits:t,
@@ -68,6 +76,11 @@ func NewModularGenome(id int, t []*neat.Trait, n []*network.NNode, g []*Gene, mi
 // connectivity.  If rec is true then recurrent connections will be included. The last input is a bias
 // link_prob is the probability of a link. The created genome is not modular.
 func newGenomeRand(new_id, in, out, n, nmax int, recurrent bool, link_prob float64) *Genome {
+	return newGenomeRandWith(utils.GlobalRand(), new_id, in, out, n, nmax, recurrent, link_prob)
+}
+
+// The same as newGenomeRand but connectivity and weights are randomized by provided random number generator
+func newGenomeRandWith(rng *rand.Rand, new_id, in, out, n, nmax int, recurrent bool, link_prob float64) *Genome {
 	total_nodes := in + out + nmax
 	matrix_dim := total_nodes * total_nodes
 	// The connection matrix which will be randomized
@@ -97,7 +110,7 @@ func newGenomeRand(new_id, in, out, n, nmax int, recurrent bool, link_prob float
 
 	// Step through the connection matrix, randomly assigning bits
 	for count := 0; count < matrix_dim; count++ {
-		cm[count] = (rand.Float64() < link_prob)
+		cm[count] = (rng.Float64() < link_prob)
 	}
 
 	// Build the input nodes
@@ -173,7 +186,7 @@ func newGenomeRand(new_id, in, out, n, nmax int, recurrent bool, link_prob float
 					}
 
 					// Create the gene
-					new_weight := float64(utils.RandSign()) * rand.Float64()
+					new_weight := float64(utils.RandSignWith(rng)) * rng.Float64()
 					new_gene = NewGeneWithTrait(new_trait, new_weight, in_node, out_node, flag_recurrent, int64(count), new_weight)
 
 					//Add the gene to the genome
@@ -190,6 +203,145 @@ func newGenomeRand(new_id, in, out, n, nmax int, recurrent bool, link_prob float
 	return &gnome
 }
 
+// Creates new genome with given number of inputs and outputs and a single layer of hidden nodes. All inputs and bias
+// are connected to every hidden node and every hidden node is connected to all outputs. If there are no hidden nodes,
+// the inputs and bias connected directly to the outputs. The initial weights of links are random in range [-1, 1].
+// The nodes IDs assigned in the following order: inputs, bias (in + 1), outputs, hidden.
+func NewGenomeFullyConnected(in, out, hidden int) (*Genome, error) {
+	return NewGenomeFullyConnectedWith(utils.GlobalRand(), in, out, hidden)
+}
+
+// The same as NewGenomeFullyConnected but the initial weights are drawn from provided random number generator
+func NewGenomeFullyConnectedWith(rng *rand.Rand, in, out, hidden int) (*Genome, error) {
+	if hidden < 0 {
+		return nil, newError(ErrInvalidParameter, "GENOME: Wrong number of hidden nodes: %d", hidden)
+	}
+	if hidden == 0 {
+		return NewGenomeLayeredWith(rng, []int{in, out})
+	}
+	return NewGenomeLayeredWith(rng, []int{in, hidden, out})
+}
+
+// Creates new genome with given number of inputs and outputs without hidden nodes, where each input and bias is
+// connected to each output with given density probability. Each output will have at least one incoming link.
+// The initial weights of links are random in range [-1, 1]. The nodes IDs assigned in the following order: inputs,
+// bias (in + 1), outputs.
+func NewGenomeSparse(in, out int, density float64) (*Genome, error) {
+	return NewGenomeSparseWith(utils.GlobalRand(), in, out, density)
+}
+
+// The same as NewGenomeSparse but the links and initial weights are drawn from provided random number generator
+func NewGenomeSparseWith(rng *rand.Rand, in, out int, density float64) (*Genome, error) {
+	if density <= 0 || density > 1 {
+		return nil, newError(ErrInvalidParameter, "GENOME: Links density should be in range (0, 1], but found: %f", density)
+	}
+	gnome, layers, err := newGenomeWithLayers([]int{in, out})
+	if err != nil {
+		return nil, err
+	}
+	sensors, outputs := layers[0], layers[1]
+	for _, out_node := range outputs {
+		connected := false
+		for _, in_node := range sensors {
+			if rng.Float64() < density {
+				gnome.addRandomWeightGene(in_node, out_node, rng)
+				connected = true
+			}
+		}
+		if !connected {
+			// keep output connected
+			gnome.addRandomWeightGene(sensors[rng.Intn(len(sensors))], out_node, rng)
+		}
+	}
+	return gnome, nil
+}
+
+// Creates new genome with layers of nodes, where the first layer is the inputs, the last layer is the outputs and
+// layers in between are hidden. The bias node added to the inputs layer. Each node of the layer is connected to all
+// nodes of the next layer. The initial weights of links are random in range [-1, 1]. The nodes IDs assigned in the
+// following order: inputs, bias (number of inputs + 1), outputs, hidden layers in order.
+func NewGenomeLayered(layers []int) (*Genome, error) {
+	return NewGenomeLayeredWith(utils.GlobalRand(), layers)
+}
+
+// The same as NewGenomeLayered but the initial weights are drawn from provided random number generator
+func NewGenomeLayeredWith(rng *rand.Rand, layers []int) (*Genome, error) {
+	gnome, node_layers, err := newGenomeWithLayers(layers)
+	if err != nil {
+		return nil, err
+	}
+	for l := 1; l < len(node_layers); l++ {
+		for _, out_node := range node_layers[l] {
+			for _, in_node := range node_layers[l - 1] {
+				gnome.addRandomWeightGene(in_node, out_node, rng)
+			}
+		}
+	}
+	return gnome, nil
+}
+
+// Creates genome without genes having nodes organized in given layers. Returns created genome and its nodes
+// grouped by layers, where the first layer includes bias node.
+func newGenomeWithLayers(layers []int) (*Genome, [][]*network.NNode, error) {
+	if len(layers) < 2 {
+		return nil, nil, newError(ErrInvalidParameter,
+			"GENOME: At least inputs and outputs layers expected, but found: %d", len(layers))
+	}
+	for i, size := range layers {
+		if size <= 0 {
+			return nil, nil, newError(ErrInvalidParameter, "GENOME: Wrong size of layer [%d]: %d", i, size)
+		}
+	}
+
+	// Create a dummy trait (this is for future expansion of the system)
+	trait := neat.NewTrait()
+	trait.Id = 1
+	trait.Params = make([]float64, neat.Num_trait_params)
+
+	gnome := &Genome{
+		Id:1,
+		Traits:[]*neat.Trait{trait},
+		Nodes:make([]*network.NNode, 0),
+		Genes:make([]*Gene, 0),
+	}
+	node_layers := make([][]*network.NNode, len(layers))
+	add_node := func(layer int, neuron_type network.NodeNeuronType) {
+		node := network.NewNNode(len(gnome.Nodes) + 1, neuron_type)
+		node.Trait = trait
+		if neuron_type == network.InputNeuron || neuron_type == network.BiasNeuron {
+			node.ActivationType = utils.NullActivation
+		}
+		gnome.Nodes = append(gnome.Nodes, node)
+		node_layers[layer] = append(node_layers[layer], node)
+	}
+
+	// inputs with bias
+	for i := 0; i < layers[0]; i++ {
+		add_node(0, network.InputNeuron)
+	}
+	add_node(0, network.BiasNeuron)
+	// outputs
+	last := len(layers) - 1
+	for i := 0; i < layers[last]; i++ {
+		add_node(last, network.OutputNeuron)
+	}
+	// hidden
+	for l := 1; l < last; l++ {
+		for i := 0; i < layers[l]; i++ {
+			add_node(l, network.HiddenNeuron)
+		}
+	}
+	return gnome, node_layers, nil
+}
+
+// Adds gene linking given nodes with weight drawn from given random number generator in range [-1, 1] and the next
+// innovation number
+func (g *Genome) addRandomWeightGene(in_node, out_node *network.NNode, rng *rand.Rand) {
+	weight := float64(utils.RandSignWith(rng)) * rng.Float64()
+	gene := NewGeneWithTrait(g.Traits[0], weight, in_node, out_node, false, int64(len(g.Genes) + 1), weight)
+	g.Genes = append(g.Genes, gene)
+}
+
 // Reads Genome from reader
 func ReadGenome(ir io.Reader, id int) (*Genome, error) {
 	// stub for backward compatibility
@@ -214,33 +366,42 @@ func (g *Genome) Write(w io.Writer) error {
 	return err
 }
 
-// Stringer
+// Stringer which prints summary of this genome followed by tables of its nodes, genes and traits
 func (g *Genome) String() string {
-	str := "GENOME START\nNodes:\n"
+	b := bytes.NewBufferString("GENOME START\n")
+	fmt.Fprintf(b, "%s\n", g.Summary())
+
+	w := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+	fmt.Fprint(w, "Nodes:\n\tID\tTYPE\tNEURON\tACTIVATION\tTRAIT\n")
 	for _, n := range g.Nodes {
-		n_type := ""
-		switch n.NeuronType {
-		case network.InputNeuron:
-			n_type = "I"
-		case network.OutputNeuron:
-			n_type = "O"
-		case network.BiasNeuron:
-			n_type = "B"
-		case network.HiddenNeuron:
-			n_type = "H"
-		}
-		str += fmt.Sprintf("\t%s%s \n", n_type, n)
-	}
-	str += "Genes:\n"
+		activation, _ := utils.NodeActivators.ActivationNameFromType(n.ActivationType)
+		trait_id := "-"
+		if n.Trait != nil {
+			trait_id = fmt.Sprintf("%d", n.Trait.Id)
+		}
+		fmt.Fprintf(w, "\t%d\t%s\t%s\t%s\t%s\n", n.Id, network.NodeTypeName(n.NodeType()),
+			network.NeuronTypeName(n.NeuronType), activation, trait_id)
+	}
+	w.Flush()
+
+	w = tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+	fmt.Fprint(w, "Genes:\n\tINNOV\tLINK\tWEIGHT\tENABLED\tRECURRENT\tMUT NUM\tTRAIT\n")
 	for _, gn := range g.Genes {
-		str += fmt.Sprintf("\t%s\n", gn)
+		trait_id := "-"
+		if gn.Link.Trait != nil {
+			trait_id = fmt.Sprintf("%d", gn.Link.Trait.Id)
+		}
+		fmt.Fprintf(w, "\t%d\t%d -> %d\t% .3f\t%t\t%t\t%.3f\t%s\n", gn.InnovationNum, gn.Link.InNode.Id,
+			gn.Link.OutNode.Id, gn.Link.Weight, gn.IsEnabled, gn.Link.IsRecurrent, gn.MutationNum, trait_id)
 	}
-	str += "Traits:\n"
+	w.Flush()
+
+	fmt.Fprint(b, "Traits:\n")
 	for _, t := range g.Traits {
-		str += fmt.Sprintf("\t%s\n", t)
+		fmt.Fprintf(b, "  %s\n", t)
 	}
-	str += "GENOME END"
-	return str
+	fmt.Fprint(b, "GENOME END")
+	return b.String()
 }
 
 // Return # of non-disabled genes
@@ -258,46 +419,46 @@ func (g *Genome) Extrons() int {
 // If mismatch detected the error will be returned with mismatch details.
 func (g *Genome) IsEqual(og *Genome) (bool, error) {
 	if len(g.Traits) != len(og.Traits) {
-		return false, errors.New(fmt.Sprintf("traits count mismatch: %d != %d",
-			len(g.Traits), len(og.Traits)))
+		return false, newError(ErrGenomesMismatch, "traits count mismatch: %d != %d",
+			len(g.Traits), len(og.Traits))
 	}
 	for i, tr := range og.Traits {
 		if !reflect.DeepEqual(tr, g.Traits[i]) {
-			return false, errors.New(
-				fmt.Sprintf("traits mismatch, expected: %s, but found: %s", tr, g.Traits[i]))
+			return false, newError(ErrGenomesMismatch,
+				"traits mismatch, expected: %s, but found: %s", tr, g.Traits[i])
 		}
 	}
 
 	if len(g.Nodes) != len(og.Nodes) {
-		return false, errors.New(fmt.Sprintf("nodes count mismatch: %d != %d",
-			len(g.Nodes), len(og.Nodes)))
+		return false, newError(ErrGenomesMismatch, "nodes count mismatch: %d != %d",
+			len(g.Nodes), len(og.Nodes))
 	}
 	for i, nd := range og.Nodes {
 		if !reflect.DeepEqual(nd, g.Nodes[i]) {
-			return false, errors.New(
-				fmt.Sprintf("node mismatch, expected: %s\nfound: %s", nd, g.Nodes[i]))
+			return false, newError(ErrGenomesMismatch,
+				"node mismatch, expected: %s\nfound: %s", nd, g.Nodes[i])
 		}
 	}
 
 	if len(g.Genes) != len(og.Genes) {
-		return false, errors.New(fmt.Sprintf("genes count mismatch: %d != %d",
-			len(g.Genes), len(og.Genes)))
+		return false, newError(ErrGenomesMismatch, "genes count mismatch: %d != %d",
+			len(g.Genes), len(og.Genes))
 	}
 	for i, gen := range og.Genes {
 		if !reflect.DeepEqual(gen, g.Genes[i]) {
-			return false, errors.New(
-				fmt.Sprintf("gene mismatch, expected: %s\nfound: %s", gen, g.Genes[i]))
+			return false, newError(ErrGenomesMismatch,
+				"gene mismatch, expected: %s\nfound: %s", gen, g.Genes[i])
 		}
 	}
 
 	if len(g.ControlGenes) != len(og.ControlGenes) {
-		return false, errors.New(fmt.Sprintf("control genes count mismatch: %d != %d",
-			len(g.ControlGenes), len(og.ControlGenes)))
+		return false, newError(ErrGenomesMismatch, "control genes count mismatch: %d != %d",
+			len(g.ControlGenes), len(og.ControlGenes))
 	}
 	for i, cg := range og.ControlGenes {
 		if !reflect.DeepEqual(cg, g.ControlGenes[i]) {
-			return false, errors.New(
-				fmt.Sprintf("control gene mismatch, expected: %s\nfound: %s", cg, g.ControlGenes[i]))
+			return false, newError(ErrGenomesMismatch,
+				"control gene mismatch, expected: %s\nfound: %s", cg, g.ControlGenes[i])
 		}
 	}
 
@@ -307,7 +468,7 @@ func (g *Genome) IsEqual(og *Genome) (bool, error) {
 // Return id of final NNode in Genome
 func (g *Genome) getLastNodeId() (int, error) {
 	if len(g.Nodes) == 0 {
-		return -1, errors.New("Genome has no nodes")
+		return -1, newError(ErrEmptyGenome, "Genome has no nodes")
 	}
 	id := g.Nodes[len(g.Nodes) - 1].Id
 	// check control genes
@@ -326,7 +487,7 @@ func (g *Genome) getNextGeneInnovNum() (int64, error) {
 	if len(g.Genes) > 0 {
 		inn_num = g.Genes[len(g.Genes) - 1].InnovationNum
 	} else {
-		return -1, errors.New("Genome has no Genes")
+		return -1, newError(ErrEmptyGenome, "Genome has no Genes")
 	}
 	// check control genes if any
 	if len(g.ControlGenes) > 0 {
@@ -371,9 +532,9 @@ func (g *Genome) Genesis(net_id int) (*network.Network, error) {
 	// Inputs and outputs will be collected here for the network.
 	// All nodes are collected in an all_list -
 	// this is useful for network traversing routines
-	in_list := make([]*network.NNode, 0)
-	out_list := make([]*network.NNode, 0)
-	all_list := make([]*network.NNode, 0)
+	in_list := make([]*network.NNode, 0, len(g.Nodes))
+	out_list := make([]*network.NNode, 0, len(g.Nodes))
+	all_list := make([]*network.NNode, 0, len(g.Nodes))
 
 	var new_node *network.NNode
 	// Create the network nodes
@@ -395,11 +556,30 @@ func (g *Genome) Genesis(net_id int) (*network.Network, error) {
 	}
 
 	if len(g.Genes) == 0 {
-		return nil, errors.New("The network built whitout GENES; the result can be unpredictable")
+		return nil, newError(ErrEmptyGenome, "The network built whitout GENES; the result can be unpredictable")
 	}
 
 	if len(out_list) == 0 {
-		return nil, errors.New(fmt.Sprintf("The network whitout OUTPUTS; the result can be unpredictable. Genome: %s", g))
+		return nil, newError(ErrEmptyGenome, "The network whitout OUTPUTS; the result can be unpredictable. Genome: %s", g)
+	}
+
+	// Preallocate links lists of nodes from the shared buffers to avoid lists growth during links creation
+	in_degree, out_degree := make(map[*network.NNode]int, len(all_list)), make(map[*network.NNode]int, len(all_list))
+	links_count := 0
+	for _, gn := range g.Genes {
+		if gn.IsEnabled {
+			in_degree[gn.Link.OutNode.PhenotypeAnalogue]++
+			out_degree[gn.Link.InNode.PhenotypeAnalogue]++
+			links_count++
+		}
+	}
+	incoming, outgoing := make([]*network.Link, links_count), make([]*network.Link, links_count)
+	in_offset, out_offset := 0, 0
+	for _, n := range all_list {
+		in_end, out_end := in_offset + in_degree[n], out_offset + out_degree[n]
+		n.Incoming = incoming[in_offset:in_offset:in_end]
+		n.Outgoing = outgoing[out_offset:out_offset:out_end]
+		in_offset, out_offset = in_end, out_end
 	}
 
 	var in_node, out_node *network.NNode
@@ -415,6 +595,12 @@ func (g *Genome) Genesis(net_id int) (*network.Network, error) {
 			// NOTE: This line could be run through a recurrency check if desired
 			// (no need to in the current implementation of NEAT)
 			new_link = network.NewLinkWithTrait(cur_link.Trait, cur_link.Weight, in_node, out_node, cur_link.IsRecurrent)
+			new_link.Delay = cur_link.Delay
+			if cur_link.GateNode != nil {
+				if new_link.GateNode = cur_link.GateNode.PhenotypeAnalogue; new_link.GateNode == nil {
+					return nil, newError(ErrMalformedGenome, "The gating node of gene is not in genome: %s", gn)
+				}
+			}
 
 			// Add link to the connected nodes
 			out_node.Incoming = append(out_node.Incoming, new_link)
@@ -466,18 +652,31 @@ func (g *Genome) Genesis(net_id int) (*network.Network, error) {
 	return new_net, nil
 }
 
-// Duplicate this Genome to create a new one with the specified id
-func (g *Genome) duplicate(new_id int) (*Genome, error) {
+// Discards the cached phenotype of this genome after mutation, the new one will be built on demand
+func (g *Genome) invalidatePhenotype() {
+	g.Phenotype = nil
+}
+
+// Returns deep copy of this Genome with the same ID. All traits, nodes, genes and MIMO control genes are copied, and
+// the copied nodes and genes refer only to the copied traits and nodes, thus changes of the copy never affect this
+// genome and vice versa. The learning parameters of nodes and links are copied as well. The phenotype is not copied,
+// it will be built for the copy on demand.
+func (g *Genome) Clone() (*Genome, error) {
+	return g.cloneWithId(g.Id)
+}
+
+// Returns deep copy of this Genome with the specified id, see Clone
+func (g *Genome) cloneWithId(new_id int) (*Genome, error) {
 
 	// Duplicate the traits
-	traits_dup := make([]*neat.Trait, 0)
+	traits_dup := make([]*neat.Trait, 0, len(g.Traits))
 	for _, tr := range g.Traits {
 		new_trait := neat.NewTraitCopy(tr)
 		traits_dup = append(traits_dup, new_trait)
 	}
 
 	// Duplicate NNodes
-	nodes_dup := make([]*network.NNode, 0)
+	nodes_dup := make([]*network.NNode, 0, len(g.Nodes))
 	for _, nd := range g.Nodes {
 		// First, find the duplicate of the trait that this node points to
 		assoc_trait := nd.Trait
@@ -490,20 +689,20 @@ func (g *Genome) duplicate(new_id int) (*Genome, error) {
 	}
 
 	// Duplicate Genes
-	genes_dup := make([]*Gene, 0)
+	genes_dup := make([]*Gene, 0, len(g.Genes))
 	for _, gn := range g.Genes {
 		// First find the nodes connected by the gene's link
 		in_node := nodeWithId(gn.Link.InNode.Id, nodes_dup)
 		if in_node == nil {
-			return nil, errors.New(
-				fmt.Sprintf("incoming node: %d not found for gene %s",
-					gn.Link.InNode.Id, gn.String()))
+			return nil, newError(ErrMalformedGenome,
+				"incoming node: %d not found for gene %s",
+					gn.Link.InNode.Id, gn.String())
 		}
 		out_node := nodeWithId(gn.Link.OutNode.Id, nodes_dup)
 		if out_node == nil {
-			return nil, errors.New(
-				fmt.Sprintf("outgoing node: %d not found for gene %s",
-					gn.Link.OutNode.Id, gn.String()))
+			return nil, newError(ErrMalformedGenome,
+				"outgoing node: %d not found for gene %s",
+					gn.Link.OutNode.Id, gn.String())
 		}
 
 		// Find the duplicate of trait associated with this gene
@@ -518,10 +717,12 @@ func (g *Genome) duplicate(new_id int) (*Genome, error) {
 
 	if len(g.ControlGenes) == 0 {
 		// If no MIMO control genes return plain genome
-		return NewGenome(new_id, traits_dup, nodes_dup, genes_dup), nil
+		genome := NewGenome(new_id, traits_dup, nodes_dup, genes_dup)
+		genome.Metadata = copyMetadata(g.Metadata)
+		return genome, nil
 	} else {
 		// Duplicate MIMO Control Genes and build modular genome
-		control_genes_dup := make([]*MIMOControlGene, 0)
+		control_genes_dup := make([]*MIMOControlGene, 0, len(g.ControlGenes))
 		for _, cg := range g.ControlGenes {
 			// duplicate control node
 			c_node := cg.ControlNode
@@ -535,11 +736,14 @@ func (g *Genome) duplicate(new_id int) (*Genome, error) {
 			for _, l := range c_node.Incoming {
 				in_node := nodeWithId(l.InNode.Id, nodes_dup)
 				if in_node == nil {
-					return nil, errors.New(
-						fmt.Sprintf("incoming node: %d not found for control node: %d",
-							l.InNode.Id, c_node.Id))
+					return nil, newError(ErrMalformedGenome,
+						"incoming node: %d not found for control node: %d",
+							l.InNode.Id, c_node.Id)
 				}
 				new_in_link := network.NewLinkCopy(l, in_node, new_c_node)
+				if l.Trait != nil {
+					new_in_link.Trait = traitWithId(l.Trait.Id, traits_dup)
+				}
 				new_c_node.Incoming = append(new_c_node.Incoming, new_in_link)
 			}
 
@@ -547,11 +751,14 @@ func (g *Genome) duplicate(new_id int) (*Genome, error) {
 			for _, l := range c_node.Outgoing {
 				out_node := nodeWithId(l.OutNode.Id, nodes_dup)
 				if out_node == nil {
-					return nil, errors.New(
-						fmt.Sprintf("outgoing node: %d not found for control node: %d",
-							l.InNode.Id, c_node.Id))
+					return nil, newError(ErrMalformedGenome,
+						"outgoing node: %d not found for control node: %d",
+							l.InNode.Id, c_node.Id)
 				}
 				new_out_link := network.NewLinkCopy(l, new_c_node, out_node)
+				if l.Trait != nil {
+					new_out_link.Trait = traitWithId(l.Trait.Id, traits_dup)
+				}
 				new_c_node.Outgoing = append(new_c_node.Outgoing, new_out_link)
 			}
 
@@ -560,21 +767,35 @@ func (g *Genome) duplicate(new_id int) (*Genome, error) {
 			control_genes_dup = append(control_genes_dup, new_cg)
 		}
 
-		return NewModularGenome(new_id, traits_dup, nodes_dup, genes_dup, control_genes_dup), nil
+		genome := NewModularGenome(new_id, traits_dup, nodes_dup, genes_dup, control_genes_dup)
+		genome.Metadata = copyMetadata(g.Metadata)
+		return genome, nil
 	}
 }
 
+// Returns copy of provided metadata or nil if it is empty
+func copyMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	res := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		res[k] = v
+	}
+	return res
+}
+
 // For debugging: A number of tests can be run on a genome to check its integrity.
 // Note: Some of these tests do not indicate a bug, but rather are meant to be used to detect specific system states.
 func (g *Genome) verify() (bool, error) {
 	if len(g.Genes) == 0 {
-		return false, errors.New("Genome has no Genes")
+		return false, newError(ErrEmptyGenome, "Genome has no Genes")
 	}
 	if len(g.Nodes) == 0 {
-		return false, errors.New("Genome has no Nodes")
+		return false, newError(ErrEmptyGenome, "Genome has no Nodes")
 	}
 	if len(g.Traits) == 0 {
-		return false, errors.New("Genome has no Traits")
+		return false, newError(ErrEmptyGenome, "Genome has no Traits")
 	}
 
 
@@ -594,10 +815,13 @@ func (g *Genome) verify() (bool, error) {
 
 		// check results
 		if !i_found {
-			return false, errors.New("Missing input node of gene in the genome nodes")
+			return false, newError(ErrMalformedGenome, "Missing input node of gene in the genome nodes")
 		}
 		if !o_found {
-			return false, errors.New("Missing output node of gene in the genome nodes")
+			return false, newError(ErrMalformedGenome, "Missing output node of gene in the genome nodes")
+		}
+		if gate := gn.Link.GateNode; gate != nil && nodeWithId(gate.Id, g.Nodes) != gate {
+			return false, newError(ErrMalformedGenome, "Missing gating node of gene in the genome nodes")
 		}
 	}
 
@@ -605,7 +829,7 @@ func (g *Genome) verify() (bool, error) {
 	last_id := 0
 	for _, n := range g.Nodes {
 		if n.Id < last_id {
-			return false, errors.New("Nodes out of order in genome")
+			return false, newError(ErrMalformedGenome, "Nodes out of order in genome")
 		}
 		last_id = n.Id
 	}
@@ -614,7 +838,7 @@ func (g *Genome) verify() (bool, error) {
 	for _, gn := range g.Genes {
 		for _, gn2 := range g.Genes {
 			if gn != gn2 && gn.Link.IsEqualGenetically(gn2.Link) {
-				return false, errors.New(fmt.Sprintf("Duplicate genes found. %s == %s", gn, gn2))
+				return false, newError(ErrMalformedGenome, "Duplicate genes found. %s == %s", gn, gn2)
 			}
 		}
 	}
@@ -624,7 +848,7 @@ func (g *Genome) verify() (bool, error) {
 		disab := false
 		for _, gn := range g.Genes {
 			if gn.IsEnabled == false && disab {
-				return false, errors.New("Two gene disables in a row")
+				return false, newError(ErrMalformedGenome, "Two gene disables in a row")
 			}
 			disab = !gn.IsEnabled
 		}
@@ -708,7 +932,7 @@ func geneInsert(genes[]*Gene, g *Gene) []*Gene {
 func (g *Genome) mutateConnectSensors(pop *Population, context *neat.NeatContext) (bool, error) {
 
 	if len(g.Genes) == 0 {
-		return false, errors.New("Genome has no genes")
+		return false, newError(ErrEmptyGenome, "Genome has no genes")
 	}
 
 	// Find all the sensors and outputs
@@ -747,7 +971,7 @@ func (g *Genome) mutateConnectSensors(pop *Population, context *neat.NeatContext
 	}
 
 	// pick randomly from disconnected sensors
-	sensor := disconnected_sensors[rand.Intn(len(disconnected_sensors))]
+	sensor := disconnected_sensors[context.Rand().Intn(len(disconnected_sensors))]
 	// add new links to chosen sensor, avoiding redundancy
 	link_added := false
 	for _, output := range outputs {
@@ -761,41 +985,33 @@ func (g *Genome) mutateConnectSensors(pop *Population, context *neat.NeatContext
 
 		if !found {
 			var new_gene *Gene
-			// Check to see if this innovation already occurred in the population
-			innovation_found := false
-			for _, inn := range pop.Innovations {
-				if inn.innovationType == newLinkInnType &&
+			// Check to see if this innovation already occurred in the population or register the new one
+			inn, innovation_found := pop.findOrAddInnovationSynced(func(inn *Innovation) bool {
+				return inn.innovationType == newLinkInnType &&
 					inn.InNodeId == sensor.Id &&
 					inn.OutNodeId == output.Id &&
-					inn.IsRecurrent == false {
-
-					new_gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight,
-						sensor, output, false, inn.InnovationNum, 0)
-
-					innovation_found = true
-					break
-				}
-			}
-
-			// The innovation is totally novel
-			if !innovation_found {
+					inn.IsRecurrent == false
+			}, func() *Innovation {
 				// Choose a random trait
-				trait_num := rand.Intn(len(g.Traits))
+				trait_num := context.Rand().Intn(len(g.Traits))
 				// Choose the new weight
-				new_weight := float64(utils.RandSign()) * rand.Float64() * 10.0
+				new_weight := float64(utils.RandSignWith(context.Rand())) * context.Rand().Float64() * 10.0
 				// read next innovation id
 				next_innov_id := pop.getNextInnovationNumberAndIncrement()
 
+				// The innovation for created link
+				return NewInnovationForLink(sensor.Id, output.Id, next_innov_id, new_weight, trait_num)
+			})
 
-				// Create the new gene
-				new_gene = NewGeneWithTrait(g.Traits[trait_num], new_weight, sensor, output,
-					false, next_innov_id, new_weight)
-
-				// Add the innovation for created link
-				new_innov := NewInnovationForLink(sensor.Id, output.Id, next_innov_id,
-					new_weight, trait_num)
-				pop.addInnovationSynced(new_innov)
-			} else if g.hasGene(new_gene) {
+			if innovation_found {
+				new_gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight,
+					sensor, output, false, inn.InnovationNum, 0)
+			} else {
+				// The innovation is totally novel - create the new gene
+				new_gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight, sensor, output,
+					false, inn.InnovationNum, inn.NewWeight)
+			}
+			if innovation_found && g.hasGene(new_gene) {
 				// The gene for already occurred innovation already in this genome.
 				// This may happen as result of parent genome mutation in current epoch which is
 				// repeated in the child after parent's genome transferred to child during mating
@@ -807,6 +1023,7 @@ func (g *Genome) mutateConnectSensors(pop *Population, context *neat.NeatContext
 
 			// Now add the new Gene to the Genome
 			g.Genes = geneInsert(g.Genes, new_gene)
+			g.invalidatePhenotype()
 			link_added = true
 		}
 	}
@@ -816,19 +1033,21 @@ func (g *Genome) mutateConnectSensors(pop *Population, context *neat.NeatContext
 // Mutate the genome by adding a new link between two random NNodes,
 // if NNodes are already connected, keep trying conf.NewLinkTries times
 func (g *Genome) mutateAddLink(pop *Population, context *neat.NeatContext) (bool, error) {
-	// If the phenotype does not exist, exit on false, print error
-	// Note: This should never happen - if it does there is a bug
+	if len(g.Nodes) == 0 {
+		return false, newError(ErrEmptyGenome, "Genome has no nodes to be connected by new link")
+	}
+	// The phenotype is used to check whether new link is recurrent, build it if not yet done
 	if g.Phenotype == nil {
-		return false, errors.New("Attempt to add link to genome with no phenotype")
-	} else if len(g.Nodes) == 0 {
-		return false, errors.New("Genome has no nodes to be connected by new link")
+		if _, err := g.Genesis(g.Id); err != nil {
+			return false, err
+		}
 	}
 
 	nodes_len := len(g.Nodes)
 
 	// Decide whether to make link recurrent
 	do_recur := false
-	if rand.Float64() < context.RecurOnlyProb {
+	if !context.FeedForwardOnly && context.Rand().Float64() < context.RecurOnlyProb {
 		do_recur = true
 	}
 
@@ -854,22 +1073,22 @@ func (g *Genome) mutateAddLink(pop *Population, context *neat.NeatContext) (bool
 			// 50% of prob to decide create a recurrent link (node X to node X)
 			// 50% of a normal link (node X to node Y)
 			loop_recur := false
-			if rand.Float64() > 0.5 {
+			if context.Rand().Float64() > 0.5 {
 				loop_recur = true
 			}
 			if loop_recur {
-				node_num_1 = first_non_sensor + rand.Intn(nodes_len - first_non_sensor) // only NON SENSOR
+				node_num_1 = first_non_sensor + context.Rand().Intn(nodes_len - first_non_sensor) // only NON SENSOR
 				node_num_2 = node_num_1
 			} else {
 				for node_num_1 == node_num_2 {
-					node_num_1 = rand.Intn(nodes_len)
-					node_num_2 = first_non_sensor + rand.Intn(nodes_len - first_non_sensor) // only NON SENSOR
+					node_num_1 = context.Rand().Intn(nodes_len)
+					node_num_2 = first_non_sensor + context.Rand().Intn(nodes_len - first_non_sensor) // only NON SENSOR
 				}
 			}
 		} else {
 			for node_num_1 == node_num_2 {
-				node_num_1 = rand.Intn(nodes_len)
-				node_num_2 = first_non_sensor + rand.Intn(nodes_len - first_non_sensor) // only NON SENSOR
+				node_num_1 = context.Rand().Intn(nodes_len)
+				node_num_2 = first_non_sensor + context.Rand().Intn(nodes_len - first_non_sensor) // only NON SENSOR
 			}
 		}
 
@@ -896,19 +1115,10 @@ func (g *Genome) mutateAddLink(pop *Population, context *neat.NeatContext) (bool
 		}
 
 		if !link_exists {
-			// These are used to avoid getting stuck in an infinite loop checking for recursion
+			// The link must be recurrent if signal already flows from node_2 to node_1.
 			// Note that we check for recursion to control the frequency of adding recurrent links rather
 			// than to prevent any particular kind of error
-			thresh := nodes_len * nodes_len
-			count := 0
-			recur_flag := g.Phenotype.IsRecurrent(node_1.PhenotypeAnalogue, node_2.PhenotypeAnalogue, &count, thresh)
-
-			// NOTE: A loop doesn't really matter - just debug output it
-			if count > thresh {
-				neat.DebugLog(
-					fmt.Sprintf("GENOME: LOOP DETECTED DURING A RECURRENCY CHECK -> " +
-						"node in: %s <-> node out: %s", node_1.PhenotypeAnalogue, node_2.PhenotypeAnalogue))
-			}
+			recur_flag := g.Phenotype.PathExists(node_2.PhenotypeAnalogue, node_1.PhenotypeAnalogue)
 
 			// Make sure it finds the right kind of link (recurrent or not)
 			if (!recur_flag && do_recur) || (recur_flag && !do_recur) {
@@ -926,40 +1136,35 @@ func (g *Genome) mutateAddLink(pop *Population, context *neat.NeatContext) (bool
 	// Continue only if an open link was found
 	if found {
 		var new_gene *Gene
-		// Check to see if this innovation already occurred in the population
-		innovation_found := false
-		for _, inn := range pop.Innovations {
+		// Check to see if this innovation already occurred in the population or register the new one
+		inn, innovation_found := pop.findOrAddInnovationSynced(func(inn *Innovation) bool {
 			// match the innovation in the innovations list
-			if inn.innovationType == newLinkInnType &&
+			return inn.innovationType == newLinkInnType &&
 				inn.InNodeId == node_1.Id &&
 				inn.OutNodeId == node_2.Id &&
-				inn.IsRecurrent == do_recur {
-
-				// Create new gene
-				new_gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight, node_1, node_2, do_recur, inn.InnovationNum, 0)
-
-				innovation_found = true
-				break
-			}
-		}
-		// The innovation is totally novel
-		if !innovation_found {
+				inn.IsRecurrent == do_recur
+		}, func() *Innovation {
 			// Choose a random trait
-			trait_num := rand.Intn(len(g.Traits))
+			trait_num := context.Rand().Intn(len(g.Traits))
 			// Choose the new weight
-			new_weight := float64(utils.RandSign()) * rand.Float64() * 10.0
+			new_weight := float64(utils.RandSignWith(context.Rand())) * context.Rand().Float64() * 10.0
 			// read next innovation id
 			next_innov_id := pop.getNextInnovationNumberAndIncrement()
 
-			// Create the new gene
-			new_gene = NewGeneWithTrait(g.Traits[trait_num], new_weight, node_1, node_2,
-				do_recur, next_innov_id, new_weight)
-
-			// Add the innovation
-			new_innov := NewInnovationForRecurrentLink(node_1.Id, node_2.Id, next_innov_id,
+			// The innovation for created link
+			return NewInnovationForRecurrentLink(node_1.Id, node_2.Id, next_innov_id,
 				new_weight, trait_num, do_recur)
-			pop.addInnovationSynced(new_innov)
-		} else if g.hasGene(new_gene) {
+		})
+
+		if innovation_found {
+			// Create new gene
+			new_gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight, node_1, node_2, do_recur, inn.InnovationNum, 0)
+		} else {
+			// The innovation is totally novel - create the new gene
+			new_gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight, node_1, node_2,
+				do_recur, inn.InnovationNum, inn.NewWeight)
+		}
+		if innovation_found && g.hasGene(new_gene) {
 			// The gene for already occurred innovation already in this genome.
 			// This may happen as result of parent genome mutation in current epoch which is
 			// repeated in the child after parent's genome transferred to child during mating
@@ -972,11 +1177,12 @@ func (g *Genome) mutateAddLink(pop *Population, context *neat.NeatContext) (bool
 		// sanity check
 		if new_gene.Link.InNode.Id == new_gene.Link.OutNode.Id && !do_recur {
 			neat.DebugLog(fmt.Sprintf("Recurent link created when recurency is not enabled: %s", new_gene))
-			return false, errors.New(fmt.Sprintf("GENOME: Wrong gene created!\n%s", g))
+			return false, newError(ErrMalformedGenome, "GENOME: Wrong gene created!\n%s", g)
 		}
 
 		// Now add the new Gene to the Genome
 		g.Genes = geneInsert(g.Genes, new_gene)
+		g.invalidatePhenotype()
 	}
 
 	return found, nil
@@ -1001,7 +1207,7 @@ func (g *Genome) mutateAddNode(pop *Population, context *neat.NeatContext) (bool
 	if len(g.Genes) < 15 {
 		for _, gn := range g.Genes {
 			// Now randomize which gene is chosen.
-			if gn.IsEnabled && gn.Link.InNode.NeuronType != network.BiasNeuron && rand.Float32() >= 0.3 {
+			if gn.IsEnabled && !gn.IsFrozen && gn.Link.InNode.NeuronType != network.BiasNeuron && context.Rand().Float32() >= 0.3 {
 				gene = gn
 				found = true
 				break
@@ -1011,9 +1217,9 @@ func (g *Genome) mutateAddNode(pop *Population, context *neat.NeatContext) (bool
 		try_count := 0
 		// Alternative uniform random choice of genes. When the genome is not tiny, it is safe to choose randomly.
 		for try_count < 20 && !found {
-			gene_num := rand.Intn(len(g.Genes))
+			gene_num := context.Rand().Intn(len(g.Genes))
 			gene = g.Genes[gene_num]
-			if gene.IsEnabled && gene.Link.InNode.NeuronType != network.BiasNeuron {
+			if gene.IsEnabled && !gene.IsFrozen && gene.Link.InNode.NeuronType != network.BiasNeuron {
 				found = true
 			}
 			try_count++
@@ -1036,71 +1242,54 @@ func (g *Genome) mutateAddNode(pop *Population, context *neat.NeatContext) (bool
 	// Extract the nodes
 	in_node, out_node := link.InNode, link.OutNode
 	if in_node == nil || out_node == nil {
-		return false, errors.New(
-			fmt.Sprintf("Genome:mutateAddNode: Anomalous link found with either IN or OUT node not set. %s", link))
+		return false, newError(ErrMalformedGenome,
+			"Genome:mutateAddNode: Anomalous link found with either IN or OUT node not set. %s", link)
 	}
 
 	var new_gene_1, new_gene_2 *Gene
 	var new_node *network.NNode
 
-	// Check to see if this innovation already occurred in the population
-	innovation_found := false
-	for _, inn := range pop.Innovations {
-		/* We check to see if an innovation already occurred that was:
-		 	-A new node
-		 	-Stuck between the same nodes as were chosen for this mutation
-		 	-Splitting the same gene as chosen for this mutation
-		 If so, we know this mutation is not a novel innovation in this generation
-		 so we make it match the original, identical mutation which occurred
-		 elsewhere in the population by coincidence */
-		if inn.innovationType == newNodeInnType &&
+	/* We check to see if an innovation already occurred that was:
+	 	-A new node
+	 	-Stuck between the same nodes as were chosen for this mutation
+	 	-Splitting the same gene as chosen for this mutation
+	 If so, we know this mutation is not a novel innovation in this generation
+	 so we make it match the original, identical mutation which occurred
+	 elsewhere in the population by coincidence. Otherwise the novel innovation registered with population. */
+	inn, innovation_found := pop.findOrAddInnovationSynced(func(inn *Innovation) bool {
+		return inn.innovationType == newNodeInnType &&
 			inn.InNodeId == in_node.Id &&
 			inn.OutNodeId == out_node.Id &&
-			inn.OldInnovNum == gene.InnovationNum {
+			inn.OldInnovNum == gene.InnovationNum
+	}, func() *Innovation {
+		// Get the current node id with post increment
+		new_node_id := int(pop.getNextNodeIdAndIncrement())
+		// get the next innovation id for gene 1
+		gene_innov_1 := pop.getNextInnovationNumberAndIncrement()
+		// get the next innovation id for gene 2
+		gene_innov_2 := pop.getNextInnovationNumberAndIncrement()
 
-			// Create the new NNode
-			new_node = network.NewNNode(inn.NewNodeId, network.HiddenNeuron)
-			// By convention, it will point to the first trait
-			// Note: In future may want to change this
-			new_node.Trait = g.Traits[0]
+		return NewInnovationForNode(in_node.Id, out_node.Id, gene_innov_1, gene_innov_2, new_node_id, gene.InnovationNum)
+	})
 
-			// Create the new Genes
-			new_gene_1 = NewGeneWithTrait(trait, 1.0, in_node, new_node, link.IsRecurrent, inn.InnovationNum, 0)
-			new_gene_2 = NewGeneWithTrait(trait, old_weight, new_node, out_node, false, inn.InnovationNum2, 0)
+	// Create the new NNode
+	new_node = network.NewNNode(inn.NewNodeId, network.HiddenNeuron)
+	// By convention, it will point to the first trait
+	// Note: In future may want to change this
+	new_node.Trait = g.Traits[0]
+
+	// Create the new Genes
+	new_gene_1 = NewGeneWithTrait(trait, 1.0, in_node, new_node, link.IsRecurrent, inn.InnovationNum, 0)
+	new_gene_2 = NewGeneWithTrait(trait, old_weight, new_node, out_node, false, inn.InnovationNum2, 0)
 
-			innovation_found = true
-			break
-		}
-	}
 	// The innovation is totally novel
 	if !innovation_found {
-		// Get the current node id with post increment
-		new_node_id := int(pop.getNextNodeIdAndIncrement())
-
-		// Create the new NNode
-		new_node = network.NewNNode(new_node_id, network.HiddenNeuron)
-		// By convention, it will point to the first trait
-		new_node.Trait = g.Traits[0]
 		// Set node activation function as random from a list of types registered with context
 		if act_type, err := context.RandomNodeActivationType(); err != nil {
 			return false, err
 		} else {
 			new_node.ActivationType = act_type
 		}
-
-		// get the next innovation id for gene 1
-		gene_innov_1 := pop.getNextInnovationNumberAndIncrement()
-		// create gene with the current gene innovation
-		new_gene_1 = NewGeneWithTrait(trait, 1.0, in_node, new_node, link.IsRecurrent, gene_innov_1, 0);
-
-		// get the next innovation id for gene 2
-		gene_innov_2 := pop.getNextInnovationNumberAndIncrement()
-		// create the second gene with this innovation incremented
-		new_gene_2 = NewGeneWithTrait(trait, old_weight, new_node, out_node, false, gene_innov_2, 0);
-
-		// Store innovation
-		innov := NewInnovationForNode(in_node.Id, out_node.Id, gene_innov_1, gene_innov_2, new_node.Id, gene.InnovationNum)
-		pop.addInnovationSynced(innov)
 	} else if g.hasNode(new_node) {
 		// The same add node innovation occurred in the same genome (parent) - just skip.
 		// This may happen when parent of this organism experienced the same mutation in current epoch earlier
@@ -1119,20 +1308,32 @@ func (g *Genome) mutateAddNode(pop *Population, context *neat.NeatContext) (bool
 	g.Genes = geneInsert(g.Genes, new_gene_1)
 	g.Genes = geneInsert(g.Genes, new_gene_2)
 	g.Nodes = nodeInsert(g.Nodes, new_node)
+	g.invalidatePhenotype()
 
 	return true, nil
 }
 
 // Adds Gaussian noise to link weights either GAUSSIAN or COLD_GAUSSIAN (from zero).
 // The COLD_GAUSSIAN means ALL connection weights will be given completely new values
-func (g *Genome) mutateLinkWeights(power, rate float64, mutation_type mutatorType) (bool, error) {
+// The random noise is drawn from distribution of given perturbation type scaled by power.
+func (g *Genome) mutateLinkWeights(power, rate float64, mutation_type mutatorType, perturbation WeightPerturbationType, rng *rand.Rand) (bool, error) {
+	return g.mutateLinkWeightsScaled(power, rate, mutation_type, perturbation, nil, rng)
+}
+
+// The same as mutateLinkWeights but the random noise of each gene additionally multiplied by the scale factor stored
+// at the index of gene, the replaced weights are moved towards new values by the scale part of the distance. If scales
+// is nil the noise is not scaled.
+func (g *Genome) mutateLinkWeightsScaled(power, rate float64, mutation_type mutatorType, perturbation WeightPerturbationType, scales []float64, rng *rand.Rand) (bool, error) {
 	if len(g.Genes) == 0 {
-		return false, errors.New("Genome has no genes")
+		return false, newError(ErrEmptyGenome, "Genome has no genes")
+	}
+	if perturbation < UniformWeightPerturbation || perturbation > AdaptiveWeightPerturbation {
+		return false, newError(ErrUnsupportedType, "GENOME: Unsupported weight perturbation type: %d", perturbation)
 	}
 
 	// Once in a while really shake things up
 	severe := false
-	if rand.Float64() > 0.5 {
+	if rng.Float64() > 0.5 {
 		severe = true
 	}
 
@@ -1141,7 +1342,12 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutation_type mutatorTyp
 	end_part := gene_total * 0.8
 	var gauss_point, cold_gauss_point float64
 
-	for _, gene := range g.Genes {
+	for i, gene := range g.Genes {
+		if gene.IsFrozen {
+			// the frozen genes keep their weights
+			num += 1.0
+			continue
+		}
 		// The following if determines the probabilities of doing cold gaussian
 		// mutation, meaning the probability of replacing a link weight with
 		// another, entirely random weight. It is meant to bias such mutations
@@ -1156,7 +1362,7 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutation_type mutatorTyp
 			cold_gauss_point = 0.3 // Mutate the rest by replacement % of the time
 		} else {
 			// Half the time don't do any cold mutations
-			if rand.Float64() > 0.5 {
+			if rng.Float64() > 0.5 {
 				gauss_point = 1.0 - rate
 				cold_gauss_point = gauss_point - 0.1
 			} else {
@@ -1165,16 +1371,20 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutation_type mutatorTyp
 			}
 		}
 
-		rand_val := float64(utils.RandSign()) * rand.Float64() * power
+		rand_val := weightPerturbation(gene, power, perturbation, rng)
+		scale := 1.0
+		if scales != nil {
+			scale = scales[i]
+		}
 		if mutation_type == gaussianMutator {
-			rand_choice := rand.Float64()
+			rand_choice := rng.Float64()
 			if rand_choice > gauss_point {
-				gene.Link.Weight += rand_val
+				gene.Link.Weight += rand_val * scale
 			} else if rand_choice > cold_gauss_point {
-				gene.Link.Weight = rand_val
+				gene.Link.Weight = scaledWeightReplacement(gene.Link.Weight, rand_val, scale)
 			}
 		} else if mutation_type == goldGaussianMutator {
-			gene.Link.Weight = rand_val
+			gene.Link.Weight = scaledWeightReplacement(gene.Link.Weight, rand_val, scale)
 		}
 
 		// Record the innovation
@@ -1182,148 +1392,405 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutation_type mutatorTyp
 
 		num += 1.0
 	}
+	g.invalidatePhenotype()
 
 	return true, nil
 }
 
+// The learning rate of self-adaptive standard deviation of gene's weight perturbations
+const adaptiveSigmaLearningRate = 0.2
+
+// Returns random link weight perturbation for given gene drawn from distribution of specified type scaled by power
+func weightPerturbation(gene *Gene, power float64, perturbation WeightPerturbationType, rng *rand.Rand) float64 {
+	switch perturbation {
+	case GaussianWeightPerturbation:
+		return rng.NormFloat64() * power
+	case CauchyWeightPerturbation:
+		return math.Tan(math.Pi * (rng.Float64() - 0.5)) * power
+	case LaplaceWeightPerturbation:
+		return float64(utils.RandSignWith(rng)) * -math.Log(1.0 - rng.Float64()) * power
+	case AdaptiveWeightPerturbation:
+		if gene.mutationSigma <= 0 {
+			gene.mutationSigma = power
+		}
+		// log-normal self-adaptation of sigma before it is used
+		gene.mutationSigma *= math.Exp(adaptiveSigmaLearningRate * rng.NormFloat64())
+		return rng.NormFloat64() * gene.mutationSigma
+	default:
+		return float64(utils.RandSignWith(rng)) * rng.Float64() * power
+	}
+}
+
+// Keeps link weights of this genome within bounds defined by context. The bounds ignored if WeightMin is not less than
+// WeightMax, which is the case when they are not configured. Returns true if any weight was changed.
+func (g *Genome) boundLinkWeights(context *neat.NeatContext) (bool, error) {
+	if context.WeightMin >= context.WeightMax {
+		return false, nil
+	}
+	changed := false
+	for _, gene := range g.Genes {
+		w := gene.Link.Weight
+		if gene.IsFrozen || w >= context.WeightMin && w <= context.WeightMax {
+			continue
+		}
+		switch WeightBoundType(context.WeightBoundType) {
+		case WeightBoundClamp:
+			w = math.Max(context.WeightMin, math.Min(w, context.WeightMax))
+		case WeightBoundBounce:
+			if w > context.WeightMax {
+				w = context.WeightMax - (w - context.WeightMax)
+			} else {
+				w = context.WeightMin + (context.WeightMin - w)
+			}
+			// clamp if overshoot is larger than the range of bounds
+			w = math.Max(context.WeightMin, math.Min(w, context.WeightMax))
+		default:
+			return false, newError(ErrUnsupportedType,
+				"GENOME: Unsupported weight bound type: %d", context.WeightBoundType)
+		}
+		gene.Link.Weight = w
+		gene.MutationNum = w
+		changed = true
+	}
+	if changed {
+		g.invalidatePhenotype()
+	}
+	return changed, nil
+}
+
+// Checks whether this genome encodes feed-forward network, i.e. its enabled genes have neither recurrent links nor
+// loops. The disabled genes are ignored as they are not expressed in phenotype.
+func (g *Genome) IsFeedForward() bool {
+	return len(g.recurrentGenes()) == 0
+}
+
+// Disables genes which introduce recurrent links or loops into this genome making it encode feed-forward network.
+// Such genes may appear after crossover or re-enabling mutations, the genes are checked in order and the first gene
+// closing a loop gets disabled. Returns the number of disabled genes.
+func (g *Genome) disableRecurrentGenes() int {
+	genes := g.recurrentGenes()
+	for _, gene := range genes {
+		gene.IsEnabled = false
+	}
+	if len(genes) > 0 {
+		g.invalidatePhenotype()
+	}
+	return len(genes)
+}
+
+// Returns enabled genes which make this genome recurrent
+func (g *Genome) recurrentGenes() []*Gene {
+	recurrent := make([]*Gene, 0)
+	// the forward links between nodes accepted so far (in node ID -> out nodes IDs)
+	links := make(map[int][]int)
+	for _, gene := range g.Genes {
+		if !gene.IsEnabled {
+			continue
+		}
+		in_id, out_id := gene.Link.InNode.Id, gene.Link.OutNode.Id
+		if gene.Link.IsRecurrent || pathExists(links, out_id, in_id) {
+			recurrent = append(recurrent, gene)
+		} else {
+			links[in_id] = append(links[in_id], out_id)
+		}
+	}
+	return recurrent
+}
+
+// Checks whether path exists between nodes with given IDs following provided links
+func pathExists(links map[int][]int, from, to int) bool {
+	if from == to {
+		return true
+	}
+	visited := map[int]bool{from:true}
+	stack := []int{from}
+	for len(stack) > 0 {
+		id := stack[len(stack) - 1]
+		stack = stack[:len(stack) - 1]
+		for _, next := range links[id] {
+			if next == to {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
+
 // Perturb params in one trait
 func (g *Genome) mutateRandomTrait(context *neat.NeatContext) (bool, error) {
 	if len(g.Traits) == 0 {
-		return false, errors.New("Genome has no traits")
+		return false, newError(ErrEmptyGenome, "Genome has no traits")
 	}
 	// Choose a random trait number
-	trait_num := rand.Intn(len(g.Traits))
+	trait_num := context.Rand().Intn(len(g.Traits))
 
 	// Retrieve the trait and mutate it
-	g.Traits[trait_num].Mutate(context.TraitMutationPower, context.TraitParamMutProb)
+	g.Traits[trait_num].MutateWith(context.Rand(), context.TraitMutationPower, context.TraitParamMutProb)
+	g.invalidatePhenotype()
 
 	return true, nil
 }
 
 // This chooses a random gene, extracts the link from it and re-points the link to a random trait
-func (g *Genome) mutateLinkTrait(times int) (bool, error) {
+func (g *Genome) mutateLinkTrait(times int, rng *rand.Rand) (bool, error) {
 	if len(g.Traits) == 0 || len(g.Genes) == 0 {
-		return false, errors.New("Genome has either no traits od genes")
+		return false, newError(ErrEmptyGenome, "Genome has either no traits od genes")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random trait number
-		trait_num := rand.Intn(len(g.Traits))
+		trait_num := rng.Intn(len(g.Traits))
 
 		// Choose a random link number
-		gene_num := rand.Intn(len(g.Genes))
+		gene_num := rng.Intn(len(g.Genes))
 
-		// set the link to point to the new trait
-		g.Genes[gene_num].Link.Trait = g.Traits[trait_num]
+		// set the link to point to the new trait unless gene is frozen
+		if !g.Genes[gene_num].IsFrozen {
+			g.Genes[gene_num].Link.Trait = g.Traits[trait_num]
+		}
 
 	}
+	g.invalidatePhenotype()
 	return true, nil
 }
 
 // This chooses a random node and re-points the node to a random trait specified number of times
-func (g *Genome) mutateNodeTrait(times int) (bool, error) {
+func (g *Genome) mutateNodeTrait(times int, rng *rand.Rand) (bool, error) {
 	if len(g.Traits) == 0 || len(g.Nodes) == 0 {
-		return false, errors.New("Genome has either no traits or nodes")
+		return false, newError(ErrEmptyGenome, "Genome has either no traits or nodes")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random trait number
-		trait_num := rand.Intn(len(g.Traits))
+		trait_num := rng.Intn(len(g.Traits))
 
 		// Choose a random node number
-		node_num := rand.Intn(len(g.Nodes))
+		node_num := rng.Intn(len(g.Nodes))
 
 		// set the node to point to the new trait
 		g.Nodes[node_num].Trait = g.Traits[trait_num]
 	}
+	g.invalidatePhenotype()
 	return true, nil
 }
 
-// Toggle genes from enable on to enable off or vice versa.  Do it specified number of times.
-func (g *Genome) mutateToggleEnable(times int) (bool, error) {
+// Toggle genes from enable on to enable off or vice versa.  Do it specified number of times. The gene will not be
+// disabled if it is the only enabled gene going out of its in-node to avoid isolation of network sections. Returns
+// true if at least one gene was toggled.
+func (g *Genome) mutateToggleEnable(times int, rng *rand.Rand) (bool, error) {
 	if len(g.Genes) == 0 {
-		return false, errors.New("Genome has no genes to toggle")
+		return false, newError(ErrEmptyGenome, "Genome has no genes to toggle")
 	}
+	toggled := false
 	for loop := 0; loop < times; loop++ {
 		// Choose a random gene number
-		gene_num := rand.Intn(len(g.Genes))
+		gene_num := rng.Intn(len(g.Genes))
 
 		gene := g.Genes[gene_num]
-		if gene.IsEnabled {
+		if gene.IsFrozen {
+			continue
+		} else if gene.IsEnabled {
 			// We need to make sure that another gene connects out of the in-node.
 			// Because if not a section of network will break off and become isolated.
 			for _, check_gene := range g.Genes {
 				if check_gene.Link.InNode.Id == gene.Link.InNode.Id &&
 					check_gene.IsEnabled && check_gene.InnovationNum != gene.InnovationNum {
 					gene.IsEnabled = false
+					toggled = true
 					break
 				}
 			}
 		} else {
 			gene.IsEnabled = true
+			toggled = true
 		}
 
 	}
-	return true, nil
+	if toggled {
+		g.invalidatePhenotype()
+	}
+	return toggled, nil
+}
+// Perturbs initial activations of hidden and output neurons the same way as link weights, i.e. each neuron gets
+// uniform random noise scaled by power with probability 0.5. Returns false if genome has no neurons.
+func (g *Genome) mutateInitialActivations(power float64, rng *rand.Rand) (bool, error) {
+	mutated := false
+	for _, node := range g.Nodes {
+		if node.IsNeuron() && rng.Float64() < 0.5 {
+			node.InitialActivation += float64(utils.RandSignWith(rng)) * rng.Float64() * power
+			mutated = true
+		}
+	}
+	if mutated {
+		g.invalidatePhenotype()
+	}
+	return mutated, nil
 }
-// Finds first disabled gene and enable it
+
+// Finds first disabled gene which is not frozen and enable it. Returns false if there is no such genes found.
 func (g *Genome) mutateGeneReenable() (bool, error) {
 	if len(g.Genes) == 0 {
-		return false, errors.New("Genome has no genes to re-enable")
+		return false, newError(ErrEmptyGenome, "Genome has no genes to re-enable")
 	}
 	for _, gene := range g.Genes {
-		if !gene.IsEnabled {
+		if !gene.IsEnabled && !gene.IsFrozen {
 			gene.IsEnabled = true
-			break
+			g.invalidatePhenotype()
+			return true, nil
 		}
 	}
-	return true, nil
+	return false, nil
 }
 
-// Applies all non-structural mutations to this genome
-func (g *Genome) mutateAllNonstructural(context *neat.NeatContext) (bool, error) {
-	res := false
-	var err error
-	if rand.Float64() < context.MutateRandomTraitProb {
-		// mutate random trait
-		res, err = g.mutateRandomTrait(context)
+// Removes random gene from this genome. The gene will not be removed if it is the only enabled gene going into its
+// out-node to avoid disconnection of network sections. The hidden nodes left without any genes are removed as well.
+// Returns true if gene was removed.
+func (g *Genome) mutateDeleteLink(rng *rand.Rand) (bool, error) {
+	if len(g.Genes) == 0 {
+		return false, newError(ErrEmptyGenome, "Genome has no genes to delete")
 	}
-
-	if err == nil && rand.Float64() < context.MutateLinkTraitProb {
-		// mutate link trait
-		res, err = g.mutateLinkTrait(1)
+	if len(g.Genes) == 1 {
+		// keep the last gene
+		return false, nil
 	}
-
-	if err == nil && rand.Float64() < context.MutateNodeTraitProb {
-		// mutate node trait
-		res, err = g.mutateNodeTrait(1)
+	gene_num := rng.Intn(len(g.Genes))
+	gene := g.Genes[gene_num]
+	if gene.IsFrozen {
+		return false, nil
+	} else if gene.IsEnabled {
+		found := false
+		for _, check_gene := range g.Genes {
+			if check_gene.Link.OutNode.Id == gene.Link.OutNode.Id &&
+				check_gene.IsEnabled && check_gene.InnovationNum != gene.InnovationNum {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
 	}
+	g.Genes = append(g.Genes[:gene_num], g.Genes[gene_num + 1:]...)
 
-	if err == nil && rand.Float64() < context.MutateLinkWeightsProb {
-		// mutate link weight
-		res, err = g.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator)
+	// remove dangling hidden nodes
+	for _, node := range []*network.NNode{gene.Link.InNode, gene.Link.OutNode} {
+		if node.NeuronType == network.HiddenNeuron && !g.hasGenesOf(node) {
+			for i, n := range g.Nodes {
+				if n.Id == node.Id {
+					g.Nodes = append(g.Nodes[:i], g.Nodes[i + 1:]...)
+					break
+				}
+			}
+		}
 	}
+	g.invalidatePhenotype()
+	return true, nil
+}
 
-	if err == nil && rand.Float64() < context.MutateToggleEnableProb {
-		// mutate toggle enable
-		res, err = g.mutateToggleEnable(1)
+// Returns true if there is at least one gene or control gene connected to the given node
+func (g *Genome) hasGenesOf(node *network.NNode) bool {
+	for _, gn := range g.Genes {
+		if gn.Link.InNode.Id == node.Id || gn.Link.OutNode.Id == node.Id {
+			return true
+		}
 	}
-
-	if err == nil && rand.Float64() < context.MutateGeneReenableProb {
-		// mutate gene reenable
-		res, err = g.mutateGeneReenable();
+	for _, cg := range g.ControlGenes {
+		for _, l := range cg.ControlNode.Incoming {
+			if l.InNode.Id == node.Id {
+				return true
+			}
+		}
+		for _, l := range cg.ControlNode.Outgoing {
+			if l.OutNode.Id == node.Id {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// Applies all non-structural mutations to this genome with probabilities configured by context. The name of each
+// mutation which actually changed genome is reported to applied function if provided.
+func (g *Genome) mutateAllNonstructural(context *neat.NeatContext, applied func(name string)) (bool, error) {
+	res := false
+	var err error
+	// applies mutation with given probability and reports it if genome was changed
+	mutate := func(name string, prob float64, mutation func() (bool, error)) {
+		if err != nil || context.Rand().Float64() >= prob {
+			return
+		}
+		if res, err = mutation(); err == nil && res && applied != nil {
+			applied(name)
+		}
+	}
+	// mutate random trait
+	mutate("mutateRandomTrait", context.MutateRandomTraitProb, func() (bool, error) {
+		return g.mutateRandomTrait(context)
+	})
+	// mutate link trait
+	mutate("mutateLinkTrait", context.MutateLinkTraitProb, func() (bool, error) {
+		return g.mutateLinkTrait(1, context.Rand())
+	})
+	// mutate node trait
+	mutate("mutateNodeTrait", context.MutateNodeTraitProb, func() (bool, error) {
+		return g.mutateNodeTrait(1, context.Rand())
+	})
+	// mutate link weight
+	mutate(LinkWeightsMutation.Name(), context.MutateLinkWeightsProb, func() (bool, error) {
+		return g.mutateLinkWeightsForContext(context)
+	})
+	// mutate toggle enable
+	mutate(ToggleEnableMutation.Name(), context.MutateToggleEnableProb, func() (bool, error) {
+		return g.mutateToggleEnable(1, context.Rand())
+	})
+	// mutate gene reenable
+	mutate(GeneReenableMutation.Name(), context.MutateGeneReenableProb, g.mutateGeneReenable)
+	// mutate initial activations of neurons
+	mutate(InitialActivationMutation.Name(), context.MutateInitActivationProb, func() (bool, error) {
+		return g.mutateInitialActivations(context.WeightMutPower, context.Rand())
+	})
+	// mutate delay of link
+	mutate(LinkDelayMutation.Name(), context.MutateLinkDelayProb, func() (bool, error) {
+		return g.mutateLinkDelay(context.MaxLinkDelay, context.Rand())
+	})
+	// add or remove gate of link
+	mutate(LinkGateMutation.Name(), context.MutateLinkGateProb, func() (bool, error) {
+		return g.mutateLinkGate(context)
+	})
+	// turn hidden neuron into memory cell or back
+	mutate(MemoryNodeMutation.Name(), context.MutateMemoryNodeProb, func() (bool, error) {
+		return g.mutateMemoryNode(context.Rand())
+	})
+	// perturb forget gates of memory cells
+	mutate(MemoryRetentionMutation.Name(), context.MutateMemoryRetentionProb, func() (bool, error) {
+		return g.mutateMemoryRetention(context.WeightMutPower, context.Rand())
+	})
 	return res, err
 }
 
 /* ****** MATING METHODS ***** */
 
+// Returns true if gene disabled in either parent should be kept disabled in offspring. Such gene is re-enabled in
+// offspring with probability 1 - MateKeepDisabledProb. If MateKeepDisabledProb is zero than 0.75 is used as in original
+// NEAT, negative value means that such genes always re-enabled.
+func keepGeneDisabled(context *neat.NeatContext) bool {
+	prob := context.MateKeepDisabledProb
+	if prob == 0 {
+		prob = 0.75
+	}
+	return context.Rand().Float64() < prob
+}
+
 // This method mates this Genome with another Genome g. For every point in each Genome, where each Genome shares
 // the innovation number, the Gene is chosen randomly from either parent.  If one parent has an innovation absent in
 // the other, the baby may inherit the innovation if it is from the more fit parent.
 // The new Genome is given the id in the genomeid argument.
-func (gen *Genome) mateMultipoint(og *Genome, genomeid int, fitness1, fitness2 float64) (*Genome, error) {
+func (gen *Genome) mateMultipoint(og *Genome, genomeid int, fitness1, fitness2 float64, context *neat.NeatContext) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(gen.Traits) != len(og.Traits) {
-		return nil, errors.New(fmt.Sprintf("Genomes has different traits count, %d != %d", len(gen.Traits), len(og.Traits)))
+		return nil, newError(ErrIncompatibleGenomes, "Genomes has different traits count, %d != %d", len(gen.Traits), len(og.Traits))
 	}
 
 	// First, average the Traits from the 2 parents to form the baby's Traits. It is assumed that trait vectors are
@@ -1368,7 +1835,7 @@ func (gen *Genome) mateMultipoint(og *Genome, genomeid int, fitness1, fitness2 f
 	i1, i2, size1, size2 := 0, 0, len(gen.Genes), len(og.Genes)
 	var chosen_gene *Gene
 	for i1 < size1 || i2 < size2 {
-		skip, disable := false, false
+		skip, disable, reenable := false, false, false
 
 		// choose best gene
 		if i1 >= size1 {
@@ -1392,15 +1859,16 @@ func (gen *Genome) mateMultipoint(og *Genome, genomeid int, fitness1, fitness2 f
 			p2innov := p2gene.InnovationNum
 
 			if p1innov == p2innov {
-				if rand.Float64() < 0.5 {
+				if context.Rand().Float64() < 0.5 {
 					chosen_gene = p1gene
 				} else {
 					chosen_gene = p2gene
 				}
 
 				// If one is disabled, the corresponding gene in the offspring will likely be disabled
-				if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
-					disable = true
+				if !p1gene.IsEnabled || !p2gene.IsEnabled {
+					disable = keepGeneDisabled(context)
+					reenable = !disable
 				}
 				i1++
 				i2++
@@ -1484,8 +1952,12 @@ func (gen *Genome) mateMultipoint(og *Genome, genomeid int, fitness1, fitness2 f
 				gene_trait_num = chosen_gene.Link.Trait.Id - gen.Traits[0].Id
 			}
 			newgene := NewGeneCopy(chosen_gene, new_traits[gene_trait_num], new_in_node, new_out_node)
-			if disable {
+			if newgene.IsFrozen {
+				// the frozen gene keeps its enabled status
+			} else if disable {
 				newgene.IsEnabled = false
+			} else if reenable {
+				newgene.IsEnabled = true
 			}
 			new_genes = append(new_genes, newgene)
 		} // end SKIP
@@ -1509,10 +1981,10 @@ func (gen *Genome) mateMultipoint(og *Genome, genomeid int, fitness1, fitness2 f
 
 // This method mates like multipoint but instead of selecting one or the other when the innovation numbers match,
 // it averages their weights.
-func (gen *Genome) mateMultipointAvg(og *Genome, genomeid int, fitness1, fitness2 float64) (*Genome, error) {
+func (gen *Genome) mateMultipointAvg(og *Genome, genomeid int, fitness1, fitness2 float64, context *neat.NeatContext) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(gen.Traits) != len(og.Traits) {
-		return nil, errors.New(fmt.Sprintf("Genomes has different traits count, %d != %d", len(gen.Traits), len(og.Traits)))
+		return nil, newError(ErrIncompatibleGenomes, "Genomes has different traits count, %d != %d", len(gen.Traits), len(og.Traits))
 	}
 
 	// First, average the Traits from the 2 parents to form the baby's Traits. It is assumed that trait vectors are
@@ -1587,24 +2059,24 @@ func (gen *Genome) mateMultipointAvg(og *Genome, genomeid int, fitness1, fitness
 
 			if p1innov == p2innov {
 				// Average them into the avg_gene
-				if rand.Float64() > 0.5 {
+				if context.Rand().Float64() > 0.5 {
 					avg_gene.Link.Trait = p1gene.Link.Trait
 				} else {
 					avg_gene.Link.Trait = p2gene.Link.Trait
 				}
 				avg_gene.Link.Weight = (p1gene.Link.Weight + p2gene.Link.Weight) / 2.0 // WEIGHTS AVERAGED HERE
 
-				if rand.Float64() > 0.5 {
+				if context.Rand().Float64() > 0.5 {
 					avg_gene.Link.InNode = p1gene.Link.InNode
 				} else {
 					avg_gene.Link.InNode = p2gene.Link.InNode
 				}
-				if rand.Float64() > 0.5 {
+				if context.Rand().Float64() > 0.5 {
 					avg_gene.Link.OutNode = p1gene.Link.OutNode
 				} else {
 					avg_gene.Link.OutNode = p2gene.Link.OutNode
 				}
-				if rand.Float64() > 0.5 {
+				if context.Rand().Float64() > 0.5 {
 					avg_gene.Link.IsRecurrent = p1gene.Link.IsRecurrent
 				} else {
 					avg_gene.Link.IsRecurrent = p2gene.Link.IsRecurrent
@@ -1612,9 +2084,11 @@ func (gen *Genome) mateMultipointAvg(og *Genome, genomeid int, fitness1, fitness
 
 				avg_gene.InnovationNum = p1innov
 				avg_gene.MutationNum = (p1gene.MutationNum + p2gene.MutationNum) / 2.0
-				if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
-					avg_gene.IsEnabled = false
+				if !p1gene.IsEnabled || !p2gene.IsEnabled {
+					// If one is disabled, the corresponding gene in the offspring will likely be disabled
+					avg_gene.IsEnabled = !keepGeneDisabled(context)
 				}
+				averageFrozenGene(avg_gene, p1gene, p2gene)
 
 				chosen_gene = avg_gene
 				i1++
@@ -1722,10 +2196,10 @@ func (gen *Genome) mateMultipointAvg(og *Genome, genomeid int, fitness1, fitness
 // This method is similar to a standard single point CROSSOVER operator. Traits are averaged as in the previous two
 // mating methods. A Gene is chosen in the smaller Genome for splitting. When the Gene is reached, it is averaged with
 // the matching Gene from the larger Genome, if one exists. Then every other Gene is taken from the larger Genome.
-func (gen *Genome) mateSinglepoint(og *Genome, genomeid int) (*Genome, error) {
+func (gen *Genome) mateSinglepoint(og *Genome, genomeid int, context *neat.NeatContext) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(gen.Traits) != len(og.Traits) {
-		return nil, errors.New(fmt.Sprintf("Genomes has different traits count, %d != %d", len(gen.Traits), len(og.Traits)))
+		return nil, newError(ErrIncompatibleGenomes, "Genomes has different traits count, %d != %d", len(gen.Traits), len(og.Traits))
 	}
 
 	// First, average the Traits from the 2 parents to form the baby's Traits. It is assumed that trait vectors are
@@ -1765,14 +2239,14 @@ func (gen *Genome) mateSinglepoint(og *Genome, genomeid int) (*Genome, error) {
 	var p1genes, p2genes []*Gene
 	size1, size2 := len(gen.Genes), len(og.Genes)
 	if size1 < size2 {
-		crosspoint = rand.Intn(size1)
+		crosspoint = context.Rand().Intn(size1)
 		p1stop = size1
 		p2stop = size2
 		stopper = size2
 		p1genes = gen.Genes
 		p2genes = og.Genes
 	} else {
-		crosspoint = rand.Intn(size2)
+		crosspoint = context.Rand().Intn(size2)
 		p1stop = size2
 		p2stop = size1
 		stopper = size1
@@ -1808,24 +2282,24 @@ func (gen *Genome) mateSinglepoint(og *Genome, genomeid int) (*Genome, error) {
 					chosen_gene = p2gene
 				} else {
 					// We are at the crosspoint here - average genes into the avgene
-					if rand.Float64() > 0.5 {
+					if context.Rand().Float64() > 0.5 {
 						avg_gene.Link.Trait = p1gene.Link.Trait
 					} else {
 						avg_gene.Link.Trait = p2gene.Link.Trait
 					}
 					avg_gene.Link.Weight = (p1gene.Link.Weight + p2gene.Link.Weight) / 2.0 // WEIGHTS AVERAGED HERE
 
-					if rand.Float64() > 0.5 {
+					if context.Rand().Float64() > 0.5 {
 						avg_gene.Link.InNode = p1gene.Link.InNode
 					} else {
 						avg_gene.Link.InNode = p2gene.Link.InNode
 					}
-					if rand.Float64() > 0.5 {
+					if context.Rand().Float64() > 0.5 {
 						avg_gene.Link.OutNode = p1gene.Link.OutNode
 					} else {
 						avg_gene.Link.OutNode = p2gene.Link.OutNode
 					}
-					if rand.Float64() > 0.5 {
+					if context.Rand().Float64() > 0.5 {
 						avg_gene.Link.IsRecurrent = p1gene.Link.IsRecurrent
 					} else {
 						avg_gene.Link.IsRecurrent = p2gene.Link.IsRecurrent
@@ -1833,9 +2307,11 @@ func (gen *Genome) mateSinglepoint(og *Genome, genomeid int) (*Genome, error) {
 
 					avg_gene.InnovationNum = p1innov
 					avg_gene.MutationNum = (p1gene.MutationNum + p2gene.MutationNum) / 2.0
-					if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
-						avg_gene.IsEnabled = false
+					if !p1gene.IsEnabled || !p2gene.IsEnabled {
+						// If one is disabled, the corresponding gene in the offspring will likely be disabled
+						avg_gene.IsEnabled = !keepGeneDisabled(context)
 					}
+					averageFrozenGene(avg_gene, p1gene, p2gene)
 
 					chosen_gene = avg_gene
 				}
@@ -2017,7 +2493,22 @@ func (g *Genome) compatibility(og *Genome, context *neat.NeatContext) float64 {
 // The compatibility formula remains the same: disjoint_coeff * pdg + excess_coeff * peg + mutdiff_coeff * mdmg
 // where: pdg - PERCENT DISJOINT GENES, peg - PERCENT EXCESS GENES, and mdmg - MUTATIONAL DIFFERENCE WITHIN MATCHING GENES
 func (g *Genome) compatLinear(og *Genome, context *neat.NeatContext) float64 {
-	num_disjoint, num_excess, mut_diff_total, num_matching := 0.0, 0.0, 0.0, 0.0
+	num_disjoint, num_excess, mut_diff_avg := g.compatTerms(og)
+
+	// Return the compatibility number using compatibility formula
+	// Note that mut_diff_avg gives the AVERAGE difference between mutation_nums for any two matching
+	// Genes in the Genome. Look at disjointedness and excess in the absolute (ignoring size)
+	comp := context.DisjointCoeff * num_disjoint + context.ExcessCoeff * num_excess +
+		context.MutdiffCoeff * mut_diff_avg
+
+	return comp
+}
+
+// Returns the three characterizing variables of compatibility between two Genomes, i.e. the number of disjoint genes,
+// the number of excess genes and the average mutational difference within matching genes. The genes are compared in
+// linear order of innovation numbers.
+func (g *Genome) compatTerms(og *Genome) (num_disjoint, num_excess, mut_diff_avg float64) {
+	mut_diff_total, num_matching := 0.0, 0.0
 	size1, size2 := len(g.Genes), len(og.Genes)
 	max_genome_size := size2
 	if size1 > size2 {
@@ -2055,13 +2546,8 @@ func (g *Genome) compatLinear(og *Genome, context *neat.NeatContext) float64 {
 
 	//fmt.Printf("num_disjoint: %.f num_excess: %.f mut_diff_total: %.f num_matching: %.f\n", num_disjoint, num_excess, mut_diff_total, num_matching)
 
-	// Return the compatibility number using compatibility formula
-	// Note that mut_diff_total/num_matching gives the AVERAGE difference between mutation_nums for any two matching
-	// Genes in the Genome. Look at disjointedness and excess in the absolute (ignoring size)
-	comp := context.DisjointCoeff * num_disjoint + context.ExcessCoeff * num_excess +
-		context.MutdiffCoeff * (mut_diff_total / num_matching)
-
-	return comp
+	mut_diff_avg = mut_diff_total / num_matching
+	return num_disjoint, num_excess, mut_diff_avg
 }
 
 
@@ -0,0 +1,80 @@
+package genetics
+
+import (
+	"io"
+	"fmt"
+	"bufio"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Writes this genome as directed graph in the DOT language of Graphviz. The sensor nodes are placed at the top rank
+// and output nodes at the bottom one. The link weights are used as edge labels, the negative weights drawn in red and
+// the positive ones in blue, the disabled links are dashed and the recurrent ones drawn with hollow arrow.
+func (g *Genome) WriteDOT(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "digraph genome_%d {\n", g.Id)
+	fmt.Fprintln(b, "\trankdir=TB;")
+	fmt.Fprintln(b, "\tnode [fontsize=10];")
+	fmt.Fprintln(b, "\tedge [fontsize=8];")
+
+	sensors, outputs := make([]int, 0), make([]int, 0)
+	for _, n := range g.Nodes {
+		activation, _ := utils.NodeActivators.ActivationNameFromType(n.ActivationType)
+		shape := "circle"
+		switch n.NeuronType {
+		case network.InputNeuron, network.BiasNeuron:
+			shape = "box"
+			sensors = append(sensors, n.Id)
+		case network.OutputNeuron:
+			shape = "doublecircle"
+			outputs = append(outputs, n.Id)
+		}
+		fmt.Fprintf(b, "\tn%d [label=\"%d\\n%s\\n%s\", shape=%s];\n", n.Id, n.Id,
+			network.NeuronTypeName(n.NeuronType), activation, shape)
+	}
+	writeDOTRank(b, "source", sensors)
+	writeDOTRank(b, "sink", outputs)
+
+	for _, gn := range g.Genes {
+		l := gn.Link
+		color := "blue"
+		if l.Weight < 0 {
+			color = "red"
+		}
+		style := "solid"
+		if !gn.IsEnabled {
+			style = "dashed"
+		}
+		arrow := "normal"
+		if l.IsRecurrent {
+			arrow = "empty"
+		}
+		fmt.Fprintf(b, "\tn%d -> n%d [label=\"%.3f\", color=%s, style=%s, arrowhead=%s];\n",
+			l.InNode.Id, l.OutNode.Id, l.Weight, color, style, arrow)
+	}
+
+	for _, cg := range g.ControlGenes {
+		fmt.Fprintf(b, "\tn%d [label=\"%d\\nCONTROL\", shape=hexagon];\n", cg.ControlNode.Id, cg.ControlNode.Id)
+		for _, l := range cg.ControlNode.Incoming {
+			fmt.Fprintf(b, "\tn%d -> n%d [style=dotted];\n", l.InNode.Id, cg.ControlNode.Id)
+		}
+		for _, l := range cg.ControlNode.Outgoing {
+			fmt.Fprintf(b, "\tn%d -> n%d [style=dotted];\n", cg.ControlNode.Id, l.OutNode.Id)
+		}
+	}
+	fmt.Fprintln(b, "}")
+
+	return b.Flush()
+}
+
+func writeDOTRank(b *bufio.Writer, rank string, ids []int) {
+	if len(ids) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\t{ rank=%s;", rank)
+	for _, id := range ids {
+		fmt.Fprintf(b, " n%d;", id)
+	}
+	fmt.Fprintln(b, " }")
+}
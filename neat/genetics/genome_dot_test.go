@@ -0,0 +1,51 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"strings"
+)
+
+func TestGenome_WriteDOT(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes[1].IsEnabled = false
+	gnome.Genes[2].Link.Weight = -3.5
+
+	var buf bytes.Buffer
+	if err := gnome.WriteDOT(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	dot := buf.String()
+
+	expected := []string{
+		"digraph genome_1 {",
+		"n1 [label=\"1\\nINPT\\nNullActivation\", shape=box];",
+		"n4 [label=\"4\\nOUTP\\nSigmoidSteepenedActivation\", shape=doublecircle];",
+		"{ rank=source; n1; n2; n3; }",
+		"{ rank=sink; n4; }",
+		"n1 -> n4 [label=\"1.500\", color=blue, style=solid, arrowhead=normal];",
+		"n2 -> n4 [label=\"2.500\", color=blue, style=dashed, arrowhead=normal];",
+		"n3 -> n4 [label=\"-3.500\", color=red, style=solid, arrowhead=normal];",
+	}
+	for _, e := range expected {
+		if !strings.Contains(dot, e) {
+			t.Error("Expected line not found", e)
+		}
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Error("Graph is not closed")
+	}
+}
+
+func TestGenome_WriteDOT_modular(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	var buf bytes.Buffer
+	if err := gnome.WriteDOT(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(buf.String(), "CONTROL") {
+		t.Error("Control node not found")
+	}
+}
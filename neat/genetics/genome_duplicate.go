@@ -0,0 +1,168 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The maximal number of hidden nodes in the module copied by module duplication mutation
+const maxDuplicatedModuleSize = 5
+
+// The standard deviation of perturbation of weights copied by node duplication mutation relative to WeightMutPower
+const duplicatedNodeWeightNoise = 0.1
+
+// This mutator duplicates sub-network (module) of this genome enabling evolution of repeated structures. The module is
+// grown from random hidden node along enabled genes connecting hidden nodes up to maxDuplicatedModuleSize nodes. The
+// copied nodes get new IDs and the copied genes get fresh innovation numbers. The copy is wired to the same nodes
+// outside the module as the original with the same weights, i.e. it receives the same inputs and contributes to the
+// same outputs. The hidden nodes connected to functional modules (MIMO control genes) are not duplicated. Returns
+// true if module was duplicated.
+func (g *Genome) mutateDuplicateModule(pop *Population, context *neat.NeatContext) (bool, error) {
+	// find hidden nodes which can be duplicated
+	eligible := make(map[int]bool)
+	candidates := make([]*network.NNode, 0)
+	for _, n := range g.Nodes {
+		if n.NeuronType == network.HiddenNeuron && !g.hasControlGenesOf(n) {
+			eligible[n.Id] = true
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+
+	// grow module from random seed node
+	seed := candidates[context.Rand().Intn(len(candidates))]
+	in_module := map[int]bool{seed.Id:true}
+	module := []*network.NNode{seed}
+	for i := 0; i < len(module) && len(module) < maxDuplicatedModuleSize; i++ {
+		for _, gn := range g.Genes {
+			if !gn.IsEnabled || len(module) >= maxDuplicatedModuleSize {
+				continue
+			}
+			var next *network.NNode
+			if gn.Link.InNode.Id == module[i].Id {
+				next = gn.Link.OutNode
+			} else if gn.Link.OutNode.Id == module[i].Id {
+				next = gn.Link.InNode
+			}
+			if next != nil && eligible[next.Id] && !in_module[next.Id] {
+				in_module[next.Id] = true
+				module = append(module, next)
+			}
+		}
+	}
+
+	// copy module nodes
+	copies := make(map[int]*network.NNode, len(module))
+	for _, n := range module {
+		node := network.NewNNodeCopy(n, n.Trait)
+		node.Id = int(pop.getNextNodeIdAndIncrement())
+		copies[n.Id] = node
+	}
+
+	// copy enabled genes connected to module nodes
+	new_genes := make([]*Gene, 0)
+	for _, gn := range g.Genes {
+		in_copy, out_copy := copies[gn.Link.InNode.Id], copies[gn.Link.OutNode.Id]
+		if !gn.IsEnabled || in_copy == nil && out_copy == nil {
+			continue
+		}
+		if in_copy == nil {
+			in_copy = gn.Link.InNode
+		}
+		if out_copy == nil {
+			out_copy = gn.Link.OutNode
+		}
+		new_gene := NewGeneWithTrait(gn.Link.Trait, gn.Link.Weight, in_copy, out_copy,
+			gn.Link.IsRecurrent, pop.getNextInnovationNumberAndIncrement(), gn.MutationNum)
+		new_gene.Link.Delay, new_gene.Link.GateNode = gn.Link.Delay, gn.Link.GateNode
+		if gate := gn.Link.GateNode; gate != nil && copies[gate.Id] != nil {
+			new_gene.Link.GateNode = copies[gate.Id]
+		}
+		new_genes = append(new_genes, new_gene)
+	}
+
+	for _, n := range module {
+		g.Nodes = nodeInsert(g.Nodes, copies[n.Id])
+	}
+	for _, gn := range new_genes {
+		g.Genes = geneInsert(g.Genes, gn)
+	}
+	g.invalidatePhenotype()
+
+	neat.DebugLog(fmt.Sprintf("GENOME: Module of %d nodes with %d genes duplicated in genome [%d]",
+		len(module), len(new_genes), g.Id))
+
+	return true, nil
+}
+
+// This mutator splits random hidden node into two by duplicating it with all its connections. The copy gets new ID and
+// the copied genes get fresh innovation numbers, the weights of copied genes are slightly perturbed by Gaussian noise
+// with standard deviation of duplicatedNodeWeightNoise * context.WeightMutPower to break the symmetry between copies.
+// The self-loop of node is copied as self-loop of the copy. The hidden nodes connected to functional modules (MIMO
+// control genes) are not duplicated. Returns true if node was duplicated.
+func (g *Genome) mutateDuplicateNode(pop *Population, context *neat.NeatContext) (bool, error) {
+	candidates := make([]*network.NNode, 0)
+	for _, n := range g.Nodes {
+		if n.NeuronType == network.HiddenNeuron && !g.hasControlGenesOf(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+	node := candidates[context.Rand().Intn(len(candidates))]
+	node_copy := network.NewNNodeCopy(node, node.Trait)
+	node_copy.Id = int(pop.getNextNodeIdAndIncrement())
+
+	// copy genes connected to the node keeping their enabled status
+	new_genes := make([]*Gene, 0)
+	for _, gn := range g.Genes {
+		in_node, out_node := gn.Link.InNode, gn.Link.OutNode
+		if in_node.Id != node.Id && out_node.Id != node.Id {
+			continue
+		}
+		if in_node.Id == node.Id {
+			in_node = node_copy
+		}
+		if out_node.Id == node.Id {
+			out_node = node_copy
+		}
+		weight := gn.Link.Weight + context.Rand().NormFloat64() * duplicatedNodeWeightNoise * context.WeightMutPower
+		new_gene := NewGeneWithTrait(gn.Link.Trait, weight, in_node, out_node, gn.Link.IsRecurrent,
+			pop.getNextInnovationNumberAndIncrement(), gn.MutationNum)
+		new_gene.IsEnabled = gn.IsEnabled
+		new_gene.Link.Delay, new_gene.Link.GateNode = gn.Link.Delay, gn.Link.GateNode
+		new_genes = append(new_genes, new_gene)
+	}
+
+	g.Nodes = nodeInsert(g.Nodes, node_copy)
+	for _, gn := range new_genes {
+		g.Genes = geneInsert(g.Genes, gn)
+	}
+	g.invalidatePhenotype()
+
+	neat.DebugLog(fmt.Sprintf("GENOME: Node [%d] duplicated as [%d] with %d genes in genome [%d]",
+		node.Id, node_copy.Id, len(new_genes), g.Id))
+
+	return true, nil
+}
+
+// Returns true if given node is connected to any MIMO control gene of this genome
+func (g *Genome) hasControlGenesOf(node *network.NNode) bool {
+	for _, cg := range g.ControlGenes {
+		for _, l := range cg.ControlNode.Incoming {
+			if l.InNode.Id == node.Id {
+				return true
+			}
+		}
+		for _, l := range cg.ControlNode.Outgoing {
+			if l.OutNode.Id == node.Id {
+				return true
+			}
+		}
+	}
+	return false
+}
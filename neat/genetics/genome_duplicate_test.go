@@ -0,0 +1,196 @@
+package genetics
+
+import (
+	"testing"
+	"math"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Builds genome with two inputs connected to output through chain of two hidden nodes and bias connected directly
+func buildTestGenomeWithHidden(id int) *Genome {
+	traits := []*neat.Trait{
+		{Id:1, Params:[]float64{0.1, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	nodes := []*network.NNode{
+		{Id:1, NeuronType: network.InputNeuron, ActivationType: utils.NullActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+		{Id:2, NeuronType: network.InputNeuron, ActivationType: utils.NullActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+		{Id:3, NeuronType: network.BiasNeuron, ActivationType: utils.SigmoidSteepenedActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+		{Id:4, NeuronType: network.OutputNeuron, ActivationType: utils.SigmoidSteepenedActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+		{Id:5, NeuronType: network.HiddenNeuron, ActivationType: utils.SigmoidSteepenedActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+		{Id:6, NeuronType: network.HiddenNeuron, ActivationType: utils.GaussianBipolarActivation, Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)},
+	}
+	genes := []*Gene{
+		newGene(network.NewLinkWithTrait(traits[0], 1.5, nodes[0], nodes[4], false), 1, 1.5, true),
+		newGene(network.NewLinkWithTrait(traits[0], 2.5, nodes[1], nodes[4], false), 2, 2.5, true),
+		newGene(network.NewLinkWithTrait(traits[0], 3.5, nodes[4], nodes[5], false), 3, 3.5, true),
+		newGene(network.NewLinkWithTrait(traits[0], 4.5, nodes[5], nodes[3], false), 4, 4.5, true),
+		newGene(network.NewLinkWithTrait(traits[0], 5.5, nodes[2], nodes[3], false), 5, 5.5, true),
+	}
+	return NewGenome(id, traits, nodes, genes)
+}
+
+func TestGenome_mutateDuplicateModule(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenomeWithHidden(1)
+	pop := newPopulation()
+	pop.nextNodeId, pop.nextInnovNum = 6, 5
+	context := neat.NewNeatContext()
+
+	res, err := gnome.mutateDuplicateModule(pop, context)
+	if !res || err != nil {
+		t.Error("Failed to duplicate module", err)
+		return
+	}
+	// both hidden nodes are connected, thus module includes both of them
+	if len(gnome.Nodes) != 8 {
+		t.Error("Two nodes should be added", len(gnome.Nodes))
+	}
+	if len(gnome.Genes) != 9 {
+		t.Error("Four genes should be added", len(gnome.Genes))
+	}
+	if pop.nextNodeId != 8 || pop.nextInnovNum != 9 {
+		t.Error("Fresh IDs and innovation numbers should be used", pop.nextNodeId, pop.nextInnovNum)
+	}
+
+	// check that copies connected like originals, the copies are recognized by activation type
+	copy_of := make(map[int]int)
+	for _, node := range gnome.Nodes[6:] {
+		switch node.ActivationType {
+		case utils.SigmoidSteepenedActivation:
+			copy_of[5] = node.Id
+		case utils.GaussianBipolarActivation:
+			copy_of[6] = node.Id
+		}
+	}
+	if len(copy_of) != 2 {
+		t.Error("Copied nodes should keep activation type", copy_of)
+		return
+	}
+	mapped := func(id int) int {
+		if c, ok := copy_of[id]; ok {
+			return c
+		}
+		return id
+	}
+	for i, orig := range gnome.Genes[:4] {
+		dup := gnome.Genes[5 + i]
+		if dup.Link.InNode.Id != mapped(orig.Link.InNode.Id) || dup.Link.OutNode.Id != mapped(orig.Link.OutNode.Id) {
+			t.Error("Wrong copy of gene", orig, dup)
+		}
+		if dup.Link.Weight != orig.Link.Weight || dup.InnovationNum != int64(6 + i) {
+			t.Error("Wrong weight or innovation of copied gene", dup)
+		}
+	}
+	if gnome.Genes[4].InnovationNum != 5 {
+		t.Error("Bias gene should not be copied", gnome.Genes[4])
+	}
+
+	if ok, err := gnome.verify(); !ok {
+		t.Error("Genome should be valid after module duplication", err)
+	}
+	if !gnome.IsFeedForward() {
+		t.Error("Genome should stay feed-forward")
+	}
+	net, err := gnome.Genesis(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = net.LoadSensors([]float64{0.5, 1.0, 1.0}); err != nil {
+		t.Error(err)
+	} else if _, err = net.Activate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenome_mutateDuplicateModule_noHidden(t *testing.T) {
+	gnome := buildTestGenome(1)
+	pop := newPopulation()
+	if res, err := gnome.mutateDuplicateModule(pop, neat.NewNeatContext()); res || err != nil {
+		t.Error("Genome without hidden nodes should not be mutated", res, err)
+	}
+
+	// the hidden nodes of functional modules are not duplicated
+	gnome = buildTestModularGenome(1)
+	if res, err := gnome.mutateDuplicateModule(pop, neat.NewNeatContext()); res || err != nil {
+		t.Error("Hidden nodes of functional modules should not be duplicated", res, err)
+	}
+}
+
+func TestGenome_mutateDuplicateNode(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenomeWithHidden(1)
+	gnome.Genes[2].IsEnabled = false
+	pop := newPopulation()
+	pop.nextNodeId, pop.nextInnovNum = 6, 5
+	context := neat.NewNeatContext()
+	context.WeightMutPower = 2.5
+
+	res, err := gnome.mutateDuplicateNode(pop, context)
+	if !res || err != nil {
+		t.Error("Failed to duplicate node", err)
+		return
+	}
+	if len(gnome.Nodes) != 7 || gnome.Nodes[6].Id != 7 {
+		t.Error("The copy of node should be added with fresh ID", len(gnome.Nodes))
+		return
+	}
+	node_copy := gnome.Nodes[6]
+	var orig *network.NNode
+	for _, n := range gnome.Nodes[4:6] {
+		if n.ActivationType == node_copy.ActivationType {
+			orig = n
+		}
+	}
+	if orig == nil {
+		t.Error("The copy should keep activation type of original", node_copy.ActivationType)
+		return
+	}
+	// each hidden node has one incoming and one outgoing gene, except node 5 with two incoming ones
+	copied := gnome.Genes[5:]
+	expected := 2
+	if orig.Id == 5 {
+		expected = 3
+	}
+	if len(copied) != expected || pop.nextInnovNum != int64(5 + expected) {
+		t.Error("Wrong number of copied genes", len(copied), pop.nextInnovNum)
+		return
+	}
+	for i, dup := range copied {
+		if dup.Link.InNode != node_copy && dup.Link.OutNode != node_copy {
+			t.Error("The copied gene should be connected to the copy of node at:", i)
+		}
+		var original *Gene
+		for _, gn := range gnome.Genes[:5] {
+			if gn.Link.InNode.Id == orig.Id && gn.Link.OutNode == dup.Link.OutNode ||
+				gn.Link.OutNode.Id == orig.Id && gn.Link.InNode == dup.Link.InNode {
+				original = gn
+			}
+		}
+		if original == nil {
+			t.Error("No original of copied gene at:", i)
+			continue
+		}
+		if dup.Link.Weight == original.Link.Weight || math.Abs(dup.Link.Weight - original.Link.Weight) > 1.5 {
+			t.Error("The weight should be slightly perturbed", dup.Link.Weight, original.Link.Weight)
+		}
+		if dup.IsEnabled != original.IsEnabled || dup.InnovationNum != int64(6 + i) {
+			t.Error("Wrong enabled status or innovation of copied gene", dup)
+		}
+	}
+
+	if ok, err := gnome.verify(); !ok {
+		t.Error("Genome should be valid after node duplication", err)
+	}
+	if _, err = gnome.Genesis(1); err != nil {
+		t.Error(err)
+	}
+
+	// the genome without eligible hidden nodes is not mutated
+	if res, err := buildTestModularGenome(1).mutateDuplicateNode(pop, context); res || err != nil {
+		t.Error("Hidden nodes of functional modules should not be duplicated", res, err)
+	}
+}
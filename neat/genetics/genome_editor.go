@@ -0,0 +1,188 @@
+package genetics
+
+import (
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The editor of genome for manual design of networks, e.g. by human-in-the-loop tools. The structural edits are
+// registered as innovations in the same way as mutations do, thus the edited genome stays consistent with other
+// genomes of population: the same edit of different genomes gets the same innovation numbers and node IDs. The frozen
+// genes are never changed by editor.
+type GenomeEditor struct {
+	// The edited genome
+	Genome      *Genome
+	// The store of innovations, i.e. the population of genome or private store if genome is edited out of population
+	innovations *Population
+}
+
+// Creates new editor of given genome. The innovations of edits are registered in provided population, or in private
+// innovation store continuing the innovation numbers and node IDs of genome if population is nil.
+func NewGenomeEditor(g *Genome, pop *Population) (*GenomeEditor, error) {
+	if pop == nil {
+		var err error
+		if pop, err = g.innovationStore(); err != nil {
+			return nil, err
+		}
+	}
+	return &GenomeEditor{Genome:g, innovations:pop}, nil
+}
+
+// Splits the enabled gene with given innovation number by new hidden node: the gene is disabled and replaced by link
+// from its input node to the new node with weight 1.0 and link from the new node to its output node with weight of the
+// split gene. Returns the new node.
+func (e *GenomeEditor) AddNodeBetween(innovation int64) (*network.NNode, error) {
+	g := e.Genome
+	gene, err := e.editableGene(innovation)
+	if err != nil {
+		return nil, err
+	}
+	if !gene.IsEnabled {
+		return nil, newError(ErrInvalidParameter, "GENOME EDITOR: The gene [%d] is disabled", innovation)
+	}
+	link := gene.Link
+	in_node, out_node := link.InNode, link.OutNode
+	inn, _ := e.innovations.findOrAddInnovationSynced(func(inn *Innovation) bool {
+		return inn.innovationType == newNodeInnType &&
+			inn.InNodeId == in_node.Id &&
+			inn.OutNodeId == out_node.Id &&
+			inn.OldInnovNum == gene.InnovationNum
+	}, func() *Innovation {
+		new_node_id := int(e.innovations.getNextNodeIdAndIncrement())
+		gene_innov_1 := e.innovations.getNextInnovationNumberAndIncrement()
+		gene_innov_2 := e.innovations.getNextInnovationNumberAndIncrement()
+		return NewInnovationForNode(in_node.Id, out_node.Id, gene_innov_1, gene_innov_2, new_node_id, gene.InnovationNum)
+	})
+
+	new_node := network.NewNNode(inn.NewNodeId, network.HiddenNeuron)
+	if g.hasNode(new_node) {
+		return nil, newError(ErrInvalidParameter, "GENOME EDITOR: The gene [%d] was already split by node [%d]",
+			innovation, new_node.Id)
+	}
+	if len(g.Traits) > 0 {
+		new_node.Trait = g.Traits[0]
+	}
+	gene.IsEnabled = false
+	g.Genes = geneInsert(g.Genes, NewGeneWithTrait(link.Trait, 1.0, in_node, new_node, link.IsRecurrent,
+		inn.InnovationNum, 0))
+	g.Genes = geneInsert(g.Genes, NewGeneWithTrait(link.Trait, link.Weight, new_node, out_node, false,
+		inn.InnovationNum2, 0))
+	g.Nodes = nodeInsert(g.Nodes, new_node)
+	g.invalidatePhenotype()
+	return new_node, nil
+}
+
+// Adds new link with given weight between nodes with provided IDs. The link is recurrent if it is loop or signal
+// already flows from the output node to the input node through enabled genes. Returns the new gene, or error if any
+// node not found, the output node is sensor or genome already has link between the nodes.
+func (e *GenomeEditor) AddLink(from, to int, weight float64) (*Gene, error) {
+	g := e.Genome
+	in_node, out_node := e.node(from), e.node(to)
+	if in_node == nil || out_node == nil {
+		return nil, newError(ErrInvalidParameter, "GENOME EDITOR: The node of link %d -> %d not found", from, to)
+	}
+	if out_node.IsSensor() {
+		return nil, newError(ErrInvalidParameter, "GENOME EDITOR: The sensor [%d] can not have incoming link", to)
+	}
+	for _, gene := range g.Genes {
+		if gene.Link.InNode.Id == from && gene.Link.OutNode.Id == to {
+			return nil, newError(ErrInvalidParameter, "GENOME EDITOR: The link %d -> %d already exists, gene [%d]",
+				from, to, gene.InnovationNum)
+		}
+	}
+	recurrent := from == to || e.pathExists(to, from)
+
+	inn, _ := e.innovations.findOrAddInnovationSynced(func(inn *Innovation) bool {
+		return inn.innovationType == newLinkInnType &&
+			inn.InNodeId == from &&
+			inn.OutNodeId == to &&
+			inn.IsRecurrent == recurrent
+	}, func() *Innovation {
+		next_innov_id := e.innovations.getNextInnovationNumberAndIncrement()
+		return NewInnovationForRecurrentLink(from, to, next_innov_id, weight, 0, recurrent)
+	})
+
+	var trait *neat.Trait
+	if inn.NewTraitNum < len(g.Traits) {
+		trait = g.Traits[inn.NewTraitNum]
+	}
+	gene := NewGeneWithTrait(trait, weight, in_node, out_node, recurrent, inn.InnovationNum, 0)
+	g.Genes = geneInsert(g.Genes, gene)
+	g.invalidatePhenotype()
+	return gene, nil
+}
+
+// Disables the gene with given innovation number
+func (e *GenomeEditor) DisableGene(innovation int64) error {
+	return e.setEnabled(innovation, false)
+}
+
+// Enables the gene with given innovation number
+func (e *GenomeEditor) EnableGene(innovation int64) error {
+	return e.setEnabled(innovation, true)
+}
+
+// Sets the weight of gene with given innovation number
+func (e *GenomeEditor) SetWeight(innovation int64, weight float64) error {
+	gene, err := e.editableGene(innovation)
+	if err != nil {
+		return err
+	}
+	gene.Link.Weight = weight
+	e.Genome.invalidatePhenotype()
+	return nil
+}
+
+// Sets the enabled status of gene with given innovation number
+func (e *GenomeEditor) setEnabled(innovation int64, enabled bool) error {
+	gene, err := e.editableGene(innovation)
+	if err != nil {
+		return err
+	}
+	gene.IsEnabled = enabled
+	e.Genome.invalidatePhenotype()
+	return nil
+}
+
+// Returns the gene with given innovation number, or error if it is not found or frozen
+func (e *GenomeEditor) editableGene(innovation int64) (*Gene, error) {
+	for _, gene := range e.Genome.Genes {
+		if gene.InnovationNum == innovation {
+			if gene.IsFrozen {
+				return nil, newError(ErrInvalidParameter, "GENOME EDITOR: The gene [%d] is frozen", innovation)
+			}
+			return gene, nil
+		}
+	}
+	return nil, newError(ErrInvalidParameter, "GENOME EDITOR: The gene [%d] not found", innovation)
+}
+
+// Returns the node of genome with given ID or nil
+func (e *GenomeEditor) node(id int) *network.NNode {
+	for _, node := range e.Genome.Nodes {
+		if node.Id == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// Checks whether signal flows from the node with given ID to the other through enabled genes
+func (e *GenomeEditor) pathExists(from, to int) bool {
+	visited := map[int]bool{from:true}
+	queue := []int{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			return true
+		}
+		for _, gene := range e.Genome.Genes {
+			if out := gene.Link.OutNode.Id; gene.IsEnabled && gene.Link.InNode.Id == id && !visited[out] {
+				visited[out] = true
+				queue = append(queue, out)
+			}
+		}
+	}
+	return false
+}
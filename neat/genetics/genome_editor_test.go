@@ -0,0 +1,139 @@
+package genetics
+
+import (
+	"testing"
+	"errors"
+)
+
+func TestGenomeEditor_AddNodeBetween(t *testing.T) {
+	gnome := buildTestGenome(1)
+	editor, err := NewGenomeEditor(gnome, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	node, err := editor.AddNodeBetween(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(gnome.Nodes) != 5 || len(gnome.Genes) != 5 || gnome.Genes[0].IsEnabled {
+		t.Error("The gene should be split by new node", len(gnome.Nodes), len(gnome.Genes))
+	}
+	if node.Id != 6 || gnome.Genes[3].Link.OutNode != node || gnome.Genes[4].Link.InNode != node {
+		t.Error("Wrong new node", node.Id)
+	}
+	if gnome.Genes[3].Link.Weight != 1.0 || gnome.Genes[4].Link.Weight != 1.5 {
+		t.Error("Wrong weights of new genes", gnome.Genes[3].Link.Weight, gnome.Genes[4].Link.Weight)
+	}
+	if _, err = gnome.Genesis(1); err != nil {
+		t.Error(err)
+	}
+
+	// the disabled gene can not be split, the same split is not repeated
+	if _, err = editor.AddNodeBetween(1); !errors.Is(err, ErrInvalidParameter) {
+		t.Error("Error expected for disabled gene", err)
+	}
+	if err = editor.EnableGene(1); err != nil {
+		t.Error(err)
+	}
+	if _, err = editor.AddNodeBetween(1); err == nil {
+		t.Error("Error expected for repeated split")
+	}
+	if _, err = editor.AddNodeBetween(100); err == nil {
+		t.Error("Error expected for unknown gene")
+	}
+}
+
+func TestGenomeEditor_innovations(t *testing.T) {
+	pop := newPopulation()
+	pop.nextNodeId, pop.nextInnovNum = 5, 4
+	gnome1, gnome2 := buildTestGenome(1), buildTestGenome(2)
+	editor1, _ := NewGenomeEditor(gnome1, pop)
+	editor2, _ := NewGenomeEditor(gnome2, pop)
+
+	node1, err := editor1.AddNodeBetween(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	node2, err := editor2.AddNodeBetween(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	gene1, _ := editor1.AddLink(1, node1.Id, 0.5)
+	gene2, _ := editor2.AddLink(1, node2.Id, -0.5)
+	// the same edits get the same innovations
+	if node1.Id != node2.Id || gene1.InnovationNum != gene2.InnovationNum || len(pop.Innovations) != 2 {
+		t.Error("The same edits should share innovations", node1.Id, node2.Id, gene1.InnovationNum,
+			gene2.InnovationNum, len(pop.Innovations))
+	}
+	if gene1.Link.Weight != 0.5 || gene2.Link.Weight != -0.5 {
+		t.Error("The weights of links should be as requested", gene1.Link.Weight, gene2.Link.Weight)
+	}
+}
+
+func TestGenomeEditor_AddLink(t *testing.T) {
+	gnome := buildTestGenome(1)
+	editor, _ := NewGenomeEditor(gnome, nil)
+	node, err := editor.AddNodeBetween(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	gene, err := editor.AddLink(2, node.Id, 0.7)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gene.Link.IsRecurrent || gene.Link.InNode.Id != 2 || gene.Link.OutNode != node || gene.InnovationNum != 7 {
+		t.Error("Wrong link added", gene)
+	}
+	// the signal already flows from node to output
+	if gene, err = editor.AddLink(4, node.Id, 0.1); err != nil || !gene.Link.IsRecurrent {
+		t.Error("The recurrent link expected", gene, err)
+	}
+	if gene, err = editor.AddLink(node.Id, node.Id, 0.1); err != nil || !gene.Link.IsRecurrent {
+		t.Error("The recurrent loop expected", gene, err)
+	}
+	if _, err = gnome.Genesis(1); err != nil {
+		t.Error(err)
+	}
+
+	if _, err = editor.AddLink(2, node.Id, 0.7); err == nil {
+		t.Error("Error expected for existing link")
+	}
+	if _, err = editor.AddLink(4, 1, 0.7); err == nil {
+		t.Error("Error expected for link into sensor")
+	}
+	if _, err = editor.AddLink(100, 4, 0.7); err == nil {
+		t.Error("Error expected for unknown node")
+	}
+}
+
+func TestGenomeEditor_SetWeight(t *testing.T) {
+	gnome := buildTestGenome(1)
+	editor, _ := NewGenomeEditor(gnome, nil)
+	if err := editor.SetWeight(2, -1.0); err != nil || gnome.Genes[1].Link.Weight != -1.0 {
+		t.Error("The weight should be set", err)
+	}
+	if err := editor.DisableGene(3); err != nil || gnome.Genes[2].IsEnabled {
+		t.Error("The gene should be disabled", err)
+	}
+
+	// the frozen genes can not be edited
+	gnome.Genes[0].IsFrozen = true
+	if err := editor.SetWeight(1, 0.0); err == nil || gnome.Genes[0].Link.Weight != 1.5 {
+		t.Error("Error expected for frozen gene", err)
+	}
+	if err := editor.DisableGene(1); err == nil || !gnome.Genes[0].IsEnabled {
+		t.Error("Error expected for frozen gene", err)
+	}
+	if _, err := editor.AddNodeBetween(1); err == nil {
+		t.Error("Error expected for frozen gene")
+	}
+	if err := editor.SetWeight(100, 0.0); err == nil {
+		t.Error("Error expected for unknown gene")
+	}
+}
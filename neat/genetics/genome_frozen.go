@@ -0,0 +1,97 @@
+package genetics
+
+import (
+	"io"
+	"sync"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The read-only snapshot of genome which can be safely shared among concurrent readers (e.g. evaluator goroutines)
+// while the live genome gets mutated by reproduction. The snapshot holds its own deep copy of genome which is never
+// exposed, thus it stays unchanged during its lifetime.
+type FrozenGenome struct {
+	// The private copy of genome
+	genome *Genome
+	// The mutex to serialize phenotype building, which records phenotype analogues in the genome nodes
+	mutex  sync.Mutex
+}
+
+// Returns immutable snapshot of this genome. The later changes of this genome are not reflected in the snapshot.
+func (g *Genome) Freeze() (*FrozenGenome, error) {
+	genome, err := g.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &FrozenGenome{genome:genome}, nil
+}
+
+// Returns ID of the genome
+func (f *FrozenGenome) Id() int {
+	return f.genome.Id
+}
+
+// Returns the number of nodes in the genome
+func (f *FrozenGenome) NodeCount() int {
+	return len(f.genome.Nodes)
+}
+
+// Returns the number of genes in the genome
+func (f *FrozenGenome) GeneCount() int {
+	return len(f.genome.Genes)
+}
+
+// Returns the number of enabled genes in the genome
+func (f *FrozenGenome) Extrons() int {
+	return f.genome.Extrons()
+}
+
+// Checks whether the genome encodes feed-forward network
+func (f *FrozenGenome) IsFeedForward() bool {
+	return f.genome.IsFeedForward()
+}
+
+// Returns structured statistics about the genome's composition
+func (f *FrozenGenome) Summary() *GenomeSummary {
+	return f.genome.Summary()
+}
+
+// Returns canonical hash of the genome
+func (f *FrozenGenome) Hash() uint64 {
+	return f.genome.Hash()
+}
+
+// Returns canonical hash of the genome topology
+func (f *FrozenGenome) TopologyHash() uint64 {
+	return f.genome.TopologyHash()
+}
+
+// Builds the new network phenotype of the genome with specified id. Each invocation returns separate network, thus
+// concurrent callers can activate their networks independently.
+func (f *FrozenGenome) Genesis(net_id int) (*network.Network, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	net, err := f.genome.Genesis(net_id)
+	// the network is owned by caller and should not be cached
+	f.genome.Phenotype = nil
+	return net, err
+}
+
+// Returns the new mutable genome with the same genetic information as the snapshot
+func (f *FrozenGenome) Thaw() (*Genome, error) {
+	return f.genome.Clone()
+}
+
+// Writes the genome into provided writer in plain encoding
+func (f *FrozenGenome) Write(w io.Writer) error {
+	return f.genome.Write(w)
+}
+
+// Writes the genome into provided writer in Graphviz DOT format
+func (f *FrozenGenome) WriteDOT(w io.Writer) error {
+	return f.genome.WriteDOT(w)
+}
+
+func (f *FrozenGenome) String() string {
+	return f.genome.String()
+}
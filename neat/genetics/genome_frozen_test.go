@@ -0,0 +1,109 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"sync"
+)
+
+func TestGenome_Freeze(t *testing.T) {
+	gnome := buildTestGenome(1)
+	frozen, err := gnome.Freeze()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	hash := gnome.Hash()
+	if frozen.Id() != gnome.Id || frozen.Hash() != hash || frozen.TopologyHash() != gnome.TopologyHash() {
+		t.Error("Snapshot should have the same genetic information")
+	}
+	if frozen.NodeCount() != len(gnome.Nodes) || frozen.GeneCount() != len(gnome.Genes) {
+		t.Error("Wrong snapshot size", frozen.NodeCount(), frozen.GeneCount())
+	}
+	if frozen.Extrons() != gnome.Extrons() || frozen.IsFeedForward() != gnome.IsFeedForward() {
+		t.Error("Wrong snapshot structure")
+	}
+
+	// mutate live genome
+	gnome.Genes[0].Link.Weight += 10.0
+	gnome.Genes[1].IsEnabled = false
+	if frozen.Hash() != hash {
+		t.Error("Snapshot changed after genome mutation")
+	}
+	if frozen.Extrons() != 3 {
+		t.Error("Snapshot genes changed", frozen.Extrons())
+	}
+
+	// write snapshot
+	buf := bytes.NewBufferString("")
+	if err = frozen.Write(buf); err != nil {
+		t.Error(err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Snapshot not written")
+	}
+}
+
+func TestFrozenGenome_Thaw(t *testing.T) {
+	frozen, err := buildTestGenome(1).Freeze()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	gnome, err := frozen.Thaw()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gnome.Hash() != frozen.Hash() {
+		t.Error("Thawed genome has different genetic information")
+	}
+	// mutation of thawed genome doesn't affect snapshot
+	gnome.Genes[0].Link.Weight += 10.0
+	if gnome.Hash() == frozen.Hash() {
+		t.Error("Snapshot changed after thawed genome mutation")
+	}
+}
+
+func TestFrozenGenome_Genesis(t *testing.T) {
+	gnome := buildTestGenome(1)
+	frozen, err := gnome.Freeze()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// build phenotypes concurrently while live genome mutated
+	workers := 4
+	nets := make([]int, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			net, err := frozen.Genesis(i)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if _, err = net.Activate(); err != nil {
+				errs[i] = err
+				return
+			}
+			nets[i] = net.LinkCount()
+		}(i)
+	}
+	for _, gn := range gnome.Genes {
+		gn.IsEnabled = false
+	}
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		if errs[i] != nil {
+			t.Error(errs[i])
+		} else if nets[i] != 3 {
+			t.Error("Wrong number of links in phenotype", nets[i])
+		}
+	}
+}
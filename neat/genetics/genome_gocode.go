@@ -0,0 +1,25 @@
+package genetics
+
+import (
+	"io"
+)
+
+// Writes Go source code of package with given name implementing standalone function Predict(inputs []float64) []float64
+// which computes outputs of this genome phenotype, see network.Network.WriteGoCode. The phenotype is activated given
+// number of steps per prediction, if steps is not positive the maximal depth of phenotype is used, which is not
+// supported for modular networks.
+func (g *Genome) WriteGoCode(w io.Writer, package_name string, steps int) error {
+	net, err := g.Genesis(g.Id)
+	if err != nil {
+		return err
+	}
+	if steps <= 0 {
+		if steps, err = net.MaxDepth(); err != nil {
+			return err
+		}
+		if steps <= 0 {
+			steps = 1
+		}
+	}
+	return net.WriteGoCode(w, package_name, steps)
+}
@@ -0,0 +1,48 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+func TestGenome_WriteGoCode(t *testing.T) {
+	gnome := buildTestGenome(1)
+	net, err := gnome.Genesis(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	depth, err := net.MaxDepth()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = gnome.WriteGoCode(&buf, "champion", 0); err != nil {
+		t.Error(err)
+		return
+	}
+	code := buf.String()
+	if !strings.Contains(code, "package champion") {
+		t.Error("Wrong package of generated code")
+	}
+	if !strings.Contains(code, "func Predict(inputs []float64) []float64 {") {
+		t.Error("Predict function not found")
+	}
+	if depth > 0 && !strings.Contains(code, fmt.Sprintf("const activationSteps = %d", depth)) {
+		t.Error("Network depth should be used as number of activation steps", depth)
+	}
+	if strings.Contains(code, "goNEAT/") {
+		t.Error("Generated code should not depend on goNEAT")
+	}
+
+	buf.Reset()
+	if err = gnome.WriteGoCode(&buf, "champion", 5); err != nil {
+		t.Error(err)
+	} else if !strings.Contains(buf.String(), "const activationSteps = 5") {
+		t.Error("Explicit number of activation steps should be used")
+	}
+}
@@ -0,0 +1,94 @@
+package genetics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The quantum used to round link weights before hashing, i.e. weights which differ less than this value considered equal
+const GenomeHashWeightQuantum = 0.001
+
+// The maximal number of extra mutations applied to duplicate offspring in attempt to make it unique
+const dedupMutationTries = 10
+
+// Returns canonical hash of this genome built from its topology and quantized weights of enabled links. The genomes
+// producing identical phenotypes will have the same hash regardless of genes order, genome ID or innovation numbers.
+func (g *Genome) Hash() uint64 {
+	return g.hash(true)
+}
+
+// Returns canonical hash of this genome topology, i.e. the same as Hash but ignoring link weights
+func (g *Genome) TopologyHash() uint64 {
+	return g.hash(false)
+}
+
+func (g *Genome) hash(with_weights bool) uint64 {
+	parts := make([]string, 0, len(g.Nodes) + len(g.Genes) + len(g.ControlGenes))
+	for _, n := range g.Nodes {
+		part := fmt.Sprintf("n%d:%d:%d", n.Id, n.NeuronType, n.ActivationType)
+		if with_weights && n.InitialActivation != 0 {
+			part += fmt.Sprintf(":%d", int64(math.Floor(n.InitialActivation / GenomeHashWeightQuantum + 0.5)))
+		}
+		if n.IsMemory {
+			retention := int64(0)
+			if with_weights {
+				retention = int64(math.Floor(n.MemoryRetention / GenomeHashWeightQuantum + 0.5))
+			}
+			part += fmt.Sprintf(":m%d", retention)
+		}
+		parts = append(parts, part)
+	}
+	for _, gn := range g.Genes {
+		if !gn.IsEnabled {
+			continue
+		}
+		l := gn.Link
+		weight := int64(0)
+		if with_weights {
+			weight = int64(math.Floor(l.Weight / GenomeHashWeightQuantum + 0.5))
+		}
+		part := fmt.Sprintf("g%d>%d:%t:%d", l.InNode.Id, l.OutNode.Id, l.IsRecurrent, weight)
+		if l.Delay > 0 {
+			part += fmt.Sprintf(":d%d", l.Delay)
+		}
+		if l.GateNode != nil {
+			part += fmt.Sprintf(":x%d", l.GateNode.Id)
+		}
+		parts = append(parts, part)
+	}
+	for _, cg := range g.ControlGenes {
+		if !cg.IsEnabled {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("c%d:%d", cg.ControlNode.Id, cg.ControlNode.ActivationType))
+	}
+	sort.Strings(parts)
+
+	h := fnv.New64a()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{';'})
+	}
+	return h.Sum64()
+}
+
+// Makes sure that baby organism is not exact duplicate of organisms with given hashes by mutating its link weights.
+// Returns true if baby is unique after deduplication.
+func deduplicateOrganism(baby *Organism, hashes map[uint64]bool, context *neat.NeatContext) (bool, error) {
+	for tries := 0; hashes[baby.Genotype.Hash()]; tries++ {
+		if tries >= dedupMutationTries || len(baby.Genotype.Genes) == 0 {
+			return false, nil
+		}
+		if _, err := baby.Genotype.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator,
+			WeightPerturbationType(context.WeightPerturbationType), context.Rand()); err != nil {
+			return false, err
+		}
+		if _, err := baby.Genotype.boundLinkWeights(context); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
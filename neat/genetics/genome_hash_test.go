@@ -0,0 +1,201 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func TestGenome_Hash(t *testing.T) {
+	gnome := buildTestGenome(1)
+	hash := gnome.Hash()
+
+	// the same topology and weights with other ID and innovations
+	other := buildTestGenome(2)
+	for _, g := range other.Genes {
+		g.InnovationNum += 100
+	}
+	if other.Hash() != hash {
+		t.Error("Hash should not depend on genome ID and innovation numbers")
+	}
+
+	// genes order doesn't matter
+	other.Genes[0], other.Genes[2] = other.Genes[2], other.Genes[0]
+	if other.Hash() != hash {
+		t.Error("Hash should not depend on genes order")
+	}
+
+	// the weight difference below quantum is ignored
+	other.Genes[0].Link.Weight += GenomeHashWeightQuantum / 10.0
+	if other.Hash() != hash {
+		t.Error("Hash should not depend on weight difference below quantum")
+	}
+
+	// the weight difference above quantum is detected
+	other.Genes[0].Link.Weight += GenomeHashWeightQuantum * 10.0
+	if other.Hash() == hash {
+		t.Error("Hash should depend on weights")
+	}
+
+	// the disabled gene changes topology
+	other = buildTestGenome(1)
+	other.Genes[1].IsEnabled = false
+	if other.Hash() == hash {
+		t.Error("Hash should depend on enabled genes")
+	}
+
+	// the activation type changes hash
+	other = buildTestGenome(1)
+	other.Nodes[3].ActivationType = other.Nodes[0].ActivationType
+	if other.Hash() == hash {
+		t.Error("Hash should depend on nodes activation")
+	}
+
+	// the initial activation changes hash but not topology hash
+	other = buildTestGenome(1)
+	other.Nodes[3].InitialActivation = 0.5
+	if other.Hash() == hash {
+		t.Error("Hash should depend on nodes initial activation")
+	}
+	if other.TopologyHash() != gnome.TopologyHash() {
+		t.Error("Topology hash should not depend on nodes initial activation")
+	}
+
+	// the memory cell changes topology, its forget gate changes hash only
+	other = buildTestGenome(1)
+	other.Nodes[3].IsMemory, other.Nodes[3].MemoryRetention = true, 0.5
+	if other.TopologyHash() == gnome.TopologyHash() {
+		t.Error("Topology hash should depend on memory cells")
+	}
+	memory := buildTestGenome(1)
+	memory.Nodes[3].IsMemory, memory.Nodes[3].MemoryRetention = true, 0.25
+	if other.Hash() == memory.Hash() || other.TopologyHash() != memory.TopologyHash() {
+		t.Error("Only hash should depend on forget gate of memory cell")
+	}
+
+	// the delay and gate of link change topology
+	if buildTestTemporalGenome(1).TopologyHash() == gnome.TopologyHash() {
+		t.Error("Topology hash should depend on delay and gate of links")
+	}
+	other = buildTestTemporalGenome(1)
+	other.Genes[0].Link.Delay = 1
+	if other.Hash() == buildTestTemporalGenome(1).Hash() {
+		t.Error("Hash should depend on delay of link")
+	}
+
+	// the modular genome
+	modular := buildTestModularGenome(1)
+	if modular.Hash() == hash {
+		t.Error("Hash should depend on control genes")
+	}
+	if modular.Hash() != buildTestModularGenome(3).Hash() {
+		t.Error("Hash of modular genome should be stable")
+	}
+}
+
+func TestDeduplicateOrganism(t *testing.T) {
+	rand.Seed(42)
+	org, err := NewOrganism(0.0, buildTestGenome(1), 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	hashes := map[uint64]bool{org.Genotype.Hash():true}
+
+	baby, err := NewOrganism(0.0, buildTestGenome(2), 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	unique, err := deduplicateOrganism(baby, hashes, &neat.NeatContext{WeightMutPower:2.5})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !unique {
+		t.Error("Baby should be unique after deduplication")
+	}
+	if hashes[baby.Genotype.Hash()] {
+		t.Error("Baby still duplicates existing organism")
+	}
+	if phenotype, err := baby.Phenotype(); err != nil {
+		t.Error(err)
+	} else if phenotype.LinkCount() != len(baby.Genotype.Genes) ||
+		phenotype.Outputs[0].Incoming[0].Weight != baby.Genotype.Genes[0].Link.Weight {
+		t.Error("Baby phenotype was not rebuilt")
+	}
+
+	// the genome without genes can not be deduplicated
+	empty := NewGenome(3, nil, buildTestGenome(3).Nodes, []*Gene{})
+	empty_org := &Organism{Genotype:empty}
+	hashes[empty.Hash()] = true
+	if unique, err = deduplicateOrganism(empty_org, hashes, &neat.NeatContext{WeightMutPower:2.5}); err != nil || unique {
+		t.Error("Empty genome can not be deduplicated", unique, err)
+	}
+}
+
+func TestSpecies_reproduce_deduplicate(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext {
+		DropOffAge:5,
+		SurvivalThresh:0.5,
+		AgeSignificance:0.5,
+		PopSize:30,
+		CompatThreshold:0.6,
+		MutateOnlyProb:1.0,
+		WeightMutPower:2.5,
+		DeduplicateOffspring:true,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	sorted_species := make([]*Species, len(pop.Species))
+	copy(sorted_species, pop.Species)
+	sort.Sort(byOrganismOrigFitness{sorted_species, MaximizeObjective})
+
+	sp := pop.Species[0]
+	sp.ExpectedOffspring = 11
+	babies, err := sp.reproduce(1, pop, sorted_species, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	hashes := make(map[uint64]bool)
+	for _, org := range pop.Organisms {
+		hashes[org.Genotype.Hash()] = true
+	}
+	duplicates := 0
+	for _, baby := range babies {
+		if hashes[baby.Genotype.Hash()] {
+			duplicates++
+		}
+		hashes[baby.Genotype.Hash()] = true
+	}
+	// only the champion clone allowed to be duplicate
+	if duplicates > 1 {
+		t.Error("Too many duplicate offspring", duplicates)
+	}
+}
+
+func TestGenome_TopologyHash(t *testing.T) {
+	gnome := buildTestGenome(1)
+	other := buildTestGenome(2)
+	other.Genes[0].Link.Weight += 10.0
+	if gnome.Hash() == other.Hash() {
+		t.Error("Hash should depend on weights")
+	}
+	if gnome.TopologyHash() != other.TopologyHash() {
+		t.Error("Topology hash should not depend on weights")
+	}
+	other.Genes[1].IsEnabled = false
+	if gnome.TopologyHash() == other.TopologyHash() {
+		t.Error("Topology hash should depend on enabled genes")
+	}
+}
@@ -0,0 +1,378 @@
+package genetics
+
+import (
+	"io"
+	"bufio"
+	"unicode"
+	"encoding/json"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The name of format stored in the header of JSON encoded genome
+const GenomeFormatName = "goNEAT-genome"
+
+// The version of JSON genome format written by JSONGenomeEncoding writer. The version 1 denotes the legacy plain text
+// format of original NEAT by Stanley (see PlainGenomeEncoding), which genomes are migrated by JSON genome reader.
+const GenomeFormatVersion = 2
+
+// The JSON encoded genome document with format header
+type jsonGenomeDocument struct {
+	// The name of format, must be GenomeFormatName
+	Format        string            `json:"format"`
+	// The version of format
+	FormatVersion int               `json:"format_version"`
+	// The optional metadata of genome
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	// The genome data
+	Genome        *jsonGenome       `json:"genome"`
+}
+
+// The JSON encoded genome
+type jsonGenome struct {
+	Id      int               `json:"id"`
+	Traits  []jsonGenomeTrait `json:"traits"`
+	Nodes   []jsonGenomeNode  `json:"nodes"`
+	Genes   []jsonGenomeGene  `json:"genes"`
+	Modules []jsonGenomeGene  `json:"modules,omitempty"`
+}
+
+// The JSON encoded trait
+type jsonGenomeTrait struct {
+	Id     int       `json:"id"`
+	Params []float64 `json:"params"`
+}
+
+// The JSON encoded network node
+type jsonGenomeNode struct {
+	Id              int       `json:"id"`
+	TraitId         int       `json:"trait_id"`
+	Type            string    `json:"type"`
+	Activation      string    `json:"activation"`
+	InitActivation  float64   `json:"init_activation,omitempty"`
+	Memory          bool      `json:"memory,omitempty"`
+	MemoryRetention float64   `json:"memory_retention,omitempty"`
+	Params          []float64 `json:"params,omitempty"`
+}
+
+// The JSON encoded connection gene or MIMO control gene. The control gene has ID of control node, its activation and
+// IDs of input and output nodes instead of link data.
+type jsonGenomeGene struct {
+	Id            int       `json:"id,omitempty"`
+	TraitId       int       `json:"trait_id"`
+	SrcId         int       `json:"src_id,omitempty"`
+	TgtId         int       `json:"tgt_id,omitempty"`
+	Weight        float64   `json:"weight"`
+	Recurrent     bool      `json:"recurrent,omitempty"`
+	InnovNum      int64     `json:"innov_num"`
+	MutNum        float64   `json:"mut_num"`
+	Enabled       bool      `json:"enabled"`
+	Frozen        bool      `json:"frozen,omitempty"`
+	Delay         int       `json:"delay,omitempty"`
+	GateId        int       `json:"gate_id,omitempty"`
+	MutationSigma float64   `json:"mutation_sigma,omitempty"`
+	Params        []float64 `json:"params,omitempty"`
+	Activation    string    `json:"activation,omitempty"`
+	Inputs        []int     `json:"inputs,omitempty"`
+	Outputs       []int     `json:"outputs,omitempty"`
+}
+
+// The writer of genomes in versioned JSON format. In addition to data stored by other encodings it persists the
+// plasticity coefficients of nodes and links, the self-adapted mutation power of genes and genome metadata.
+type jsonGenomeWriter struct {
+	w *bufio.Writer
+}
+
+func (wr *jsonGenomeWriter) WriteGenome(g *Genome) (err error) {
+	jg := &jsonGenome{
+		Id:g.Id,
+		Traits:make([]jsonGenomeTrait, len(g.Traits)),
+		Nodes:make([]jsonGenomeNode, len(g.Nodes)),
+		Genes:make([]jsonGenomeGene, len(g.Genes)),
+	}
+	for i, t := range g.Traits {
+		jg.Traits[i] = jsonGenomeTrait{Id:t.Id, Params:t.Params}
+	}
+	for i, n := range g.Nodes {
+		if jg.Nodes[i], err = encodeJSONNode(n); err != nil {
+			return err
+		}
+	}
+	for i, gene := range g.Genes {
+		jg.Genes[i] = encodeJSONGene(gene)
+	}
+	for _, cg := range g.ControlGenes {
+		module, err := encodeJSONControlGene(cg)
+		if err != nil {
+			return err
+		}
+		jg.Modules = append(jg.Modules, module)
+	}
+
+	doc := jsonGenomeDocument{
+		Format:GenomeFormatName,
+		FormatVersion:GenomeFormatVersion,
+		Metadata:g.Metadata,
+		Genome:jg,
+	}
+	enc := json.NewEncoder(wr.w)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(doc); err == nil {
+		err = wr.w.Flush()
+	}
+	return err
+}
+
+func encodeJSONNode(n *network.NNode) (jsonGenomeNode, error) {
+	activation, err := utils.NodeActivators.ActivationNameFromType(n.ActivationType)
+	if err != nil {
+		return jsonGenomeNode{}, err
+	}
+	return jsonGenomeNode{
+		Id:n.Id,
+		TraitId:traitId(n.Trait),
+		Type:network.NeuronTypeName(n.NeuronType),
+		Activation:activation,
+		InitActivation:n.InitialActivation,
+		Memory:n.IsMemory,
+		MemoryRetention:n.MemoryRetention,
+		Params:n.Params,
+	}, nil
+}
+
+func encodeJSONGene(gene *Gene) jsonGenomeGene {
+	jg := jsonGenomeGene{
+		TraitId:traitId(gene.Link.Trait),
+		SrcId:gene.Link.InNode.Id,
+		TgtId:gene.Link.OutNode.Id,
+		Weight:gene.Link.Weight,
+		Recurrent:gene.Link.IsRecurrent,
+		InnovNum:gene.InnovationNum,
+		MutNum:gene.MutationNum,
+		Enabled:gene.IsEnabled,
+		Frozen:gene.IsFrozen,
+		Delay:gene.Link.Delay,
+		MutationSigma:gene.mutationSigma,
+		Params:gene.Link.Params,
+	}
+	if gene.Link.GateNode != nil {
+		jg.GateId = gene.Link.GateNode.Id
+	}
+	return jg
+}
+
+func encodeJSONControlGene(gene *MIMOControlGene) (jsonGenomeGene, error) {
+	activation, err := utils.NodeActivators.ActivationNameFromType(gene.ControlNode.ActivationType)
+	if err != nil {
+		return jsonGenomeGene{}, err
+	}
+	jg := jsonGenomeGene{
+		Id:gene.ControlNode.Id,
+		TraitId:traitId(gene.ControlNode.Trait),
+		InnovNum:gene.InnovationNum,
+		MutNum:gene.MutationNum,
+		Enabled:gene.IsEnabled,
+		Activation:activation,
+		Inputs:make([]int, len(gene.ControlNode.Incoming)),
+		Outputs:make([]int, len(gene.ControlNode.Outgoing)),
+	}
+	for i, in := range gene.ControlNode.Incoming {
+		jg.Inputs[i] = in.InNode.Id
+	}
+	for i, out := range gene.ControlNode.Outgoing {
+		jg.Outputs[i] = out.OutNode.Id
+	}
+	return jg, nil
+}
+
+// Returns ID of trait or zero if trait is nil
+func traitId(trait *neat.Trait) int {
+	if trait == nil {
+		return 0
+	}
+	return trait.Id
+}
+
+// The reader of genomes in versioned JSON format. The genomes in legacy plain text format (format version 1) are
+// migrated transparently, i.e. read by plain text reader and get the default values of features not supported by it.
+type jsonGenomeReader struct {
+	r *bufio.Reader
+}
+
+func (jr *jsonGenomeReader) Read() (*Genome, error) {
+	// the JSON document starts with object, anything else is considered as legacy plain text genome
+	if legacy, err := jr.isLegacy(); err != nil {
+		return nil, err
+	} else if legacy {
+		neat.InfoLog("Migrating genome from legacy plain text format")
+		return (&plainGenomeReader{r:jr.r}).Read()
+	}
+
+	doc := jsonGenomeDocument{}
+	if err := json.NewDecoder(jr.r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.Format != GenomeFormatName {
+		return nil, newError(ErrMalformedGenome, "Unknown genome format: [%s]", doc.Format)
+	}
+	if doc.FormatVersion != GenomeFormatVersion {
+		return nil, newError(ErrUnsupportedGenomeEncoding, "Unsupported version of genome format: %d, expected: %d",
+			doc.FormatVersion, GenomeFormatVersion)
+	}
+	if doc.Genome == nil {
+		return nil, newError(ErrMalformedGenome, "No genome data found")
+	}
+	gnome, err := decodeJSONGenome(doc.Genome)
+	if err != nil {
+		return nil, err
+	}
+	gnome.Metadata = copyMetadata(doc.Metadata)
+	return gnome, nil
+}
+
+// Checks whether the first meaningful character of data is not the start of JSON object
+func (jr *jsonGenomeReader) isLegacy() (bool, error) {
+	for {
+		r, _, err := jr.r.ReadRune()
+		if err == io.EOF {
+			return false, newError(ErrMalformedGenome, "No genome data found")
+		} else if err != nil {
+			return false, err
+		}
+		if !unicode.IsSpace(r) {
+			return r != '{', jr.r.UnreadRune()
+		}
+	}
+}
+
+func decodeJSONGenome(jg *jsonGenome) (*Genome, error) {
+	gnome := &Genome{
+		Id:jg.Id,
+		Traits:make([]*neat.Trait, 0, len(jg.Traits)),
+		Nodes:make([]*network.NNode, 0, len(jg.Nodes)),
+		Genes:make([]*Gene, 0, len(jg.Genes)),
+		ControlGenes:make([]*MIMOControlGene, 0, len(jg.Modules)),
+	}
+	for _, jt := range jg.Traits {
+		if traitWithId(jt.Id, gnome.Traits) != nil {
+			return nil, newError(ErrMalformedGenome, "Trait ID: %d is not unique", jt.Id)
+		}
+		if len(jt.Params) > neat.Num_trait_params {
+			return nil, newError(ErrMalformedGenome, "Too many parameters of trait: %d, expected: %d",
+				len(jt.Params), neat.Num_trait_params)
+		}
+		trait := neat.NewTrait()
+		trait.Id = jt.Id
+		copy(trait.Params, jt.Params)
+		gnome.Traits = append(gnome.Traits, trait)
+	}
+	for _, jn := range jg.Nodes {
+		if nodeWithId(jn.Id, gnome.Nodes) != nil {
+			return nil, newError(ErrMalformedGenome, "Node ID: %d is not unique", jn.Id)
+		}
+		node, err := decodeJSONNode(jn, gnome.Traits)
+		if err != nil {
+			return nil, err
+		}
+		gnome.Nodes = append(gnome.Nodes, node)
+	}
+	for _, jgene := range jg.Genes {
+		gene, err := decodeJSONGene(jgene, gnome.Traits, gnome.Nodes)
+		if err != nil {
+			return nil, err
+		}
+		gnome.Genes = append(gnome.Genes, gene)
+	}
+	for _, jm := range jg.Modules {
+		if nodeWithId(jm.Id, gnome.Nodes) != nil {
+			return nil, newError(ErrMalformedGenome, "Control node ID: %d is not unique", jm.Id)
+		}
+		gene, err := decodeJSONControlGene(jm, gnome.Traits, gnome.Nodes)
+		if err != nil {
+			return nil, err
+		}
+		gnome.ControlGenes = append(gnome.ControlGenes, gene)
+	}
+	return gnome, nil
+}
+
+func decodeJSONNode(jn jsonGenomeNode, traits []*neat.Trait) (node *network.NNode, err error) {
+	node = network.NewNetworkNode()
+	node.Id = jn.Id
+	node.Trait = traitWithId(jn.TraitId, traits)
+	if node.NeuronType, err = network.NeuronTypeByName(jn.Type); err != nil {
+		return nil, err
+	}
+	if node.ActivationType, err = utils.NodeActivators.ActivationTypeFromName(jn.Activation); err != nil {
+		return nil, err
+	}
+	node.InitialActivation = jn.InitActivation
+	node.IsMemory, node.MemoryRetention = jn.Memory, jn.MemoryRetention
+	node.Params = jn.Params
+	return node, nil
+}
+
+func decodeJSONGene(jg jsonGenomeGene, traits []*neat.Trait, nodes []*network.NNode) (*Gene, error) {
+	in_node, out_node := nodeWithId(jg.SrcId, nodes), nodeWithId(jg.TgtId, nodes)
+	if in_node == nil || out_node == nil {
+		return nil, newError(ErrMalformedGenome, "Nodes of gene with innovation: %d not found, source: %d, target: %d",
+			jg.InnovNum, jg.SrcId, jg.TgtId)
+	}
+	trait := traitWithId(jg.TraitId, traits)
+	gene := newGene(network.NewLinkWithTrait(trait, jg.Weight, in_node, out_node, jg.Recurrent), jg.InnovNum, jg.MutNum,
+		jg.Enabled)
+	gene.mutationSigma, gene.IsFrozen = jg.MutationSigma, jg.Frozen
+	if jg.Params != nil {
+		// the plasticity coefficients may have been changed after derived from trait
+		gene.Link.Params = jg.Params
+	}
+	return gene, setGeneDelayAndGate(gene, jg.Delay, jg.GateId, nodes)
+}
+
+func decodeJSONControlGene(jg jsonGenomeGene, traits []*neat.Trait, nodes []*network.NNode) (gene *MIMOControlGene, err error) {
+	control_node := network.NewNetworkNode()
+	control_node.Id = jg.Id
+	control_node.NeuronType = network.HiddenNeuron
+	control_node.Trait = traitWithId(jg.TraitId, traits)
+	if control_node.ActivationType, err = utils.NodeActivators.ActivationTypeFromName(jg.Activation); err != nil {
+		return nil, err
+	}
+	control_node.Incoming = make([]*network.Link, len(jg.Inputs))
+	for i, id := range jg.Inputs {
+		node := nodeWithId(id, nodes)
+		if node == nil {
+			return nil, newError(ErrMalformedGenome, "no MIMO input node with id: %d can be found for module: %d",
+				id, control_node.Id)
+		}
+		control_node.Incoming[i] = network.NewLink(1.0, node, control_node, false)
+	}
+	control_node.Outgoing = make([]*network.Link, len(jg.Outputs))
+	for i, id := range jg.Outputs {
+		node := nodeWithId(id, nodes)
+		if node == nil {
+			return nil, newError(ErrMalformedGenome, "no MIMO output node with id: %d can be found for module: %d",
+				id, control_node.Id)
+		}
+		control_node.Outgoing[i] = network.NewLink(1.0, control_node, node, false)
+	}
+	return NewMIMOGene(control_node, jg.InnovNum, jg.MutNum, jg.Enabled), nil
+}
+
+// Migrates genome read from provided reader in legacy plain text format into the current version of JSON format
+// written into provided writer. The genomes already stored in JSON format are validated and rewritten.
+func MigrateGenome(r io.Reader, w io.Writer) error {
+	reader, err := NewGenomeReader(r, JSONGenomeEncoding)
+	if err != nil {
+		return err
+	}
+	gnome, err := reader.Read()
+	if err != nil {
+		return err
+	}
+	writer, err := NewGenomeWriter(w, JSONGenomeEncoding)
+	if err != nil {
+		return err
+	}
+	return writer.WriteGenome(gnome)
+}
@@ -0,0 +1,189 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"encoding/json"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestJSONGenomeWriter_WriteGenome(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	gnome.Metadata = map[string]string{"experiment":"xor", "trial":"2"}
+	gnome.Nodes[len(gnome.Nodes) - 1].InitialActivation = 0.75
+	gnome.Nodes[len(gnome.Nodes) - 1].ActivationType = utils.TanhActivation
+	gnome.Nodes[4].IsMemory, gnome.Nodes[4].MemoryRetention = true, 0.25
+	gnome.Nodes[4].Params = []float64{0.1, 0.2}
+	gnome.Genes[0].Link.Delay, gnome.Genes[0].Link.GateNode = 1, gnome.Nodes[1]
+	gnome.Genes[1].mutationSigma = 0.3
+	gnome.Genes[2].Link.Params = []float64{0.5, -0.5, 0.25}
+
+	out_buf := bytes.NewBufferString("")
+	wr, err := NewGenomeWriter(out_buf, JSONGenomeEncoding)
+	if err == nil {
+		err = wr.WriteGenome(gnome)
+	}
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// check the format header
+	header := make(map[string]interface{})
+	if err = json.Unmarshal(out_buf.Bytes(), &header); err != nil {
+		t.Error(err)
+		return
+	}
+	if header["format"] != GenomeFormatName || header["format_version"] != float64(GenomeFormatVersion) {
+		t.Error("Wrong format header", header["format"], header["format_version"])
+	}
+
+	reader, err := NewGenomeReader(bytes.NewReader(out_buf.Bytes()), JSONGenomeEncoding)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	gnome_enc, err := reader.Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gnome_enc.Id != gnome.Id || !reflect.DeepEqual(gnome_enc.Metadata, gnome.Metadata) {
+		t.Error("Wrong genome ID or metadata", gnome_enc.Id, gnome_enc.Metadata)
+	}
+	if len(gnome_enc.Traits) != len(gnome.Traits) {
+		t.Error("Wrong number of traits", len(gnome_enc.Traits))
+		return
+	}
+	for i, tr := range gnome.Traits {
+		if etr := gnome_enc.Traits[i]; tr.Id != etr.Id || !reflect.DeepEqual(tr.Params, etr.Params) {
+			t.Error("Wrong trait at:", i, etr)
+		}
+	}
+	if len(gnome_enc.Nodes) != len(gnome.Nodes) {
+		t.Error("Wrong number of nodes", len(gnome_enc.Nodes))
+		return
+	}
+	for i, n := range gnome.Nodes {
+		nd := gnome_enc.Nodes[i]
+		if n.Id != nd.Id || n.NeuronType != nd.NeuronType || n.ActivationType != nd.ActivationType {
+			t.Error("Wrong node at:", i, nd)
+		}
+		if n.InitialActivation != nd.InitialActivation || n.IsMemory != nd.IsMemory ||
+			n.MemoryRetention != nd.MemoryRetention {
+			t.Error("Wrong node state at:", i, nd)
+		}
+		if len(n.Params) > 0 && !reflect.DeepEqual(n.Params, nd.Params) {
+			t.Error("Wrong plasticity coefficients of node at:", i, nd.Params)
+		}
+		if traitId(n.Trait) != traitId(nd.Trait) {
+			t.Error("Wrong trait of node at:", i)
+		}
+	}
+	if len(gnome_enc.Genes) != len(gnome.Genes) {
+		t.Error("Wrong number of genes", len(gnome_enc.Genes))
+		return
+	}
+	for i, g := range gnome.Genes {
+		og := gnome_enc.Genes[i]
+		if !g.Link.IsEqualGenetically(og.Link) || g.IsEnabled != og.IsEnabled ||
+			g.MutationNum != og.MutationNum || g.InnovationNum != og.InnovationNum {
+			t.Error("Wrong gene at:", i, og)
+		}
+		if g.Link.Delay != og.Link.Delay || traitId(g.Link.Trait) != traitId(og.Link.Trait) {
+			t.Error("Wrong link at:", i)
+		}
+		if (g.Link.GateNode == nil) != (og.Link.GateNode == nil) ||
+			g.Link.GateNode != nil && og.Link.GateNode != gnome_enc.Nodes[1] {
+			t.Error("Wrong gating node at:", i)
+		}
+		if g.mutationSigma != og.mutationSigma || !reflect.DeepEqual(g.Link.Params, og.Link.Params) {
+			t.Error("Wrong mutation power or plasticity coefficients at:", i, og.mutationSigma, og.Link.Params)
+		}
+	}
+	if len(gnome_enc.ControlGenes) != len(gnome.ControlGenes) {
+		t.Error("Wrong number of control genes", len(gnome_enc.ControlGenes))
+		return
+	}
+	for i, cg := range gnome.ControlGenes {
+		ocg := gnome_enc.ControlGenes[i]
+		if cg.ControlNode.Id != ocg.ControlNode.Id || cg.InnovationNum != ocg.InnovationNum ||
+			cg.ControlNode.ActivationType != ocg.ControlNode.ActivationType || cg.IsEnabled != ocg.IsEnabled {
+			t.Error("Wrong control gene at:", i)
+		}
+		checkLinks(cg.ControlNode.Incoming, ocg.ControlNode.Incoming, t)
+		checkLinks(cg.ControlNode.Outgoing, ocg.ControlNode.Outgoing, t)
+	}
+}
+
+func TestJSONGenomeReader_Read_legacy(t *testing.T) {
+	file, err := os.Open("../../data/xorstartgenes")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer file.Close()
+
+	out_buf := bytes.NewBufferString("")
+	if err = MigrateGenome(file, out_buf); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(out_buf.String(), `"format_version": 2`) {
+		t.Error("The migrated genome should have format version header", out_buf.String())
+	}
+	reader, _ := NewGenomeReader(out_buf, JSONGenomeEncoding)
+	gnome, err := reader.Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gnome.Id != 1 || len(gnome.Traits) != 3 || len(gnome.Nodes) != 4 || len(gnome.Genes) != 3 {
+		t.Error("Wrong migrated genome", gnome.Id, len(gnome.Traits), len(gnome.Nodes), len(gnome.Genes))
+	}
+	if gnome.Genes[2].Link.Trait == nil || gnome.Genes[2].Link.Trait.Params[0] != 0.3 {
+		t.Error("The trait of gene should be kept")
+	}
+	if gnome.Nodes[3].ActivationType != utils.SigmoidSteepenedActivation {
+		t.Error("The default activation expected", gnome.Nodes[3].ActivationType)
+	}
+}
+
+func TestJSONGenomeReader_Read_errors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"  \n",
+		`{"format":"unknown","format_version":2,"genome":{}}`,
+		`{"format":"goNEAT-genome","format_version":3,"genome":{}}`,
+		`{"format":"goNEAT-genome","genome":{}}`,
+		`{"format":"goNEAT-genome","format_version":2}`,
+		`{"format":"goNEAT-genome","format_version":2,"genome":{"nodes":[{"id":1,"type":"INPT","activation":"Unknown"}]}}`,
+		`{"format":"goNEAT-genome","format_version":2,"genome":{"genes":[{"src_id":1,"tgt_id":2}]}}`,
+		`{"format":"goNEAT-genome","format_version":2,"genome":{"traits":[{"id":1},{"id":1}]}}`,
+	} {
+		reader, _ := NewGenomeReader(strings.NewReader(data), JSONGenomeEncoding)
+		if _, err := reader.Read(); err == nil {
+			t.Error("Error expected for:", data)
+		}
+	}
+}
+
+func TestGenome_Clone_metadata(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Metadata = map[string]string{"origin":"test"}
+	clone, err := gnome.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(clone.Metadata, gnome.Metadata) {
+		t.Error("The metadata should be copied", clone.Metadata)
+	}
+	clone.Metadata["origin"] = "clone"
+	if gnome.Metadata["origin"] != "test" {
+		t.Error("The metadata of clone should be independent")
+	}
+}
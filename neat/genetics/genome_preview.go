@@ -0,0 +1,194 @@
+package genetics
+
+import (
+	"fmt"
+	"sync"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The change of gene present in genome both before and after mutation
+type GeneChange struct {
+	// The gene before and after mutation
+	Before *Gene
+	After  *Gene
+}
+
+// The changes which mutation would make to genome, reported by Genome.PreviewMutation. The nodes and traits are
+// identified by their IDs and genes by innovation numbers.
+type GenomeDelta struct {
+	// The name of mutation operator
+	Operator      string
+	// True if operator reported that genome was mutated
+	Mutated       bool
+	// The nodes added and removed by mutation
+	AddedNodes    []*network.NNode
+	RemovedNodes  []*network.NNode
+	// The genes added and removed by mutation
+	AddedGenes    []*Gene
+	RemovedGenes  []*Gene
+	// The genes which weight, enabled status or trait changed by mutation
+	ChangedGenes  []GeneChange
+	// The IDs of traits which parameters changed by mutation
+	ChangedTraits []int
+	// The proposed genome, i.e. the mutated copy of genome. It refers to innovations which are not registered in
+	// population until preview is applied by Population.ApplyPreview.
+	Proposed      *Genome
+
+	// The innovations created by mutation
+	innovations   []*Innovation
+	// The innovation and node ID counters of population before and after mutation
+	innovNumFrom, innovNumTo int64
+	nodeIdFrom, nodeIdTo     int32
+}
+
+// Returns true if mutation would not change genome
+func (d *GenomeDelta) IsEmpty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.AddedGenes) == 0 &&
+		len(d.RemovedGenes) == 0 && len(d.ChangedGenes) == 0 && len(d.ChangedTraits) == 0
+}
+
+// Returns true if mutation would change topology of genome
+func (d *GenomeDelta) IsStructural() bool {
+	return len(d.AddedNodes) > 0 || len(d.RemovedNodes) > 0 || len(d.AddedGenes) > 0 || len(d.RemovedGenes) > 0
+}
+
+// Returns the short summary of changes
+func (d *GenomeDelta) String() string {
+	str := fmt.Sprintf("%s: nodes +%d/-%d, genes +%d/-%d, changed genes: %d, changed traits: %d", d.Operator,
+		len(d.AddedNodes), len(d.RemovedNodes), len(d.AddedGenes), len(d.RemovedGenes), len(d.ChangedGenes),
+		len(d.ChangedTraits))
+	for _, gene := range d.AddedGenes {
+		str += fmt.Sprintf("\n\t+ gene [%d] %d -> %d, weight: %.3f", gene.InnovationNum, gene.Link.InNode.Id,
+			gene.Link.OutNode.Id, gene.Link.Weight)
+	}
+	for _, gene := range d.RemovedGenes {
+		str += fmt.Sprintf("\n\t- gene [%d] %d -> %d", gene.InnovationNum, gene.Link.InNode.Id, gene.Link.OutNode.Id)
+	}
+	return str
+}
+
+// Reports what provided mutation operator would change in this genome without committing it, i.e. neither this
+// genome nor population are changed. The mutation is applied to the copy of genome with innovations tracked by the
+// copy of population innovations if population is provided, or by innovation counters of this genome otherwise. As
+// mutations are random, the delta is one possible outcome, which can be committed by Population.ApplyPreview.
+func (g *Genome) PreviewMutation(op MutationOperator, pop *Population, context *neat.NeatContext) (*GenomeDelta, error) {
+	proposed, err := g.Clone()
+	if err != nil {
+		return nil, err
+	}
+	var shadow *Population
+	if pop != nil {
+		shadow = &Population{mutex:&sync.Mutex{}}
+		pop.mutex.Lock()
+		shadow.Innovations = append(make([]*Innovation, 0, len(pop.Innovations)), pop.Innovations...)
+		shadow.nextInnovNum, shadow.nextNodeId = pop.nextInnovNum, pop.nextNodeId
+		pop.mutex.Unlock()
+	} else if shadow, err = g.innovationStore(); err != nil {
+		return nil, err
+	}
+	known := len(shadow.Innovations)
+	delta := &GenomeDelta{
+		Operator:op.Name(),
+		Proposed:proposed,
+		innovNumFrom:shadow.nextInnovNum,
+		nodeIdFrom:shadow.nextNodeId,
+	}
+	if delta.Mutated, err = op.Mutate(proposed, shadow, context); err != nil {
+		return nil, err
+	}
+	delta.innovations = shadow.Innovations[known:]
+	delta.innovNumTo, delta.nodeIdTo = shadow.nextInnovNum, shadow.nextNodeId
+	delta.diff(g, proposed)
+	return delta, nil
+}
+
+// Registers innovations created by previewed mutation in this population and returns the proposed genome. Returns
+// error if innovations of population changed since preview, e.g. by other mutations, thus the proposed genome might
+// use the same innovation numbers or node IDs for different innovations.
+func (p *Population) ApplyPreview(delta *GenomeDelta) (*Genome, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.nextInnovNum != delta.innovNumFrom || p.nextNodeId != delta.nodeIdFrom {
+		return nil, newError(ErrInvalidParameter,
+			"POPULATION: The innovations of population changed since preview of mutation: %s", delta.Operator)
+	}
+	p.Innovations = append(p.Innovations, delta.innovations...)
+	p.nextInnovNum, p.nextNodeId = delta.innovNumTo, delta.nodeIdTo
+	return delta.Proposed, nil
+}
+
+// Collects the differences between genome before and after mutation
+func (d *GenomeDelta) diff(before, after *Genome) {
+	before_nodes, after_nodes := make(map[int]bool, len(before.Nodes)), make(map[int]bool, len(after.Nodes))
+	for _, node := range before.Nodes {
+		before_nodes[node.Id] = true
+	}
+	for _, node := range after.Nodes {
+		after_nodes[node.Id] = true
+		if !before_nodes[node.Id] {
+			d.AddedNodes = append(d.AddedNodes, node)
+		}
+	}
+	for _, node := range before.Nodes {
+		if !after_nodes[node.Id] {
+			d.RemovedNodes = append(d.RemovedNodes, node)
+		}
+	}
+
+	before_genes, after_genes := make(map[int64]*Gene, len(before.Genes)), make(map[int64]*Gene, len(after.Genes))
+	for _, gene := range before.Genes {
+		before_genes[gene.InnovationNum] = gene
+	}
+	for _, gene := range after.Genes {
+		after_genes[gene.InnovationNum] = gene
+		if prev, ok := before_genes[gene.InnovationNum]; !ok {
+			d.AddedGenes = append(d.AddedGenes, gene)
+		} else if prev.Link.Weight != gene.Link.Weight || prev.IsEnabled != gene.IsEnabled ||
+			traitId(prev.Link.Trait) != traitId(gene.Link.Trait) {
+			d.ChangedGenes = append(d.ChangedGenes, GeneChange{Before:prev, After:gene})
+		}
+	}
+	for _, gene := range before.Genes {
+		if _, ok := after_genes[gene.InnovationNum]; !ok {
+			d.RemovedGenes = append(d.RemovedGenes, gene)
+		}
+	}
+
+	before_traits := make(map[int]*neat.Trait, len(before.Traits))
+	for _, tr := range before.Traits {
+		before_traits[tr.Id] = tr
+	}
+	for _, tr := range after.Traits {
+		if prev, ok := before_traits[tr.Id]; ok && !equalParams(prev.Params, tr.Params) {
+			d.ChangedTraits = append(d.ChangedTraits, tr.Id)
+		}
+	}
+}
+
+// Returns true if trait parameters are equal
+func equalParams(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns new innovation store, i.e. the population holding only innovations, which innovation number and node ID
+// counters continue the ones of this genome. It allows to track innovations of genome edited out of population.
+func (g *Genome) innovationStore() (*Population, error) {
+	last_node_id, err := g.getLastNodeId()
+	if err != nil {
+		return nil, err
+	}
+	store := &Population{Innovations:make([]*Innovation, 0), nextNodeId:int32(last_node_id + 1), mutex:&sync.Mutex{}}
+	if store.nextInnovNum, err = g.getNextGeneInnovNum(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,88 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func TestGenome_PreviewMutation(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	pop := newPopulation()
+	pop.nextNodeId, pop.nextInnovNum = 5, 5
+	context := neat.NewNeatContext()
+
+	delta, err := gnome.PreviewMutation(AddNodeMutation, pop, context)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// neither genome nor population are changed
+	if len(gnome.Nodes) != 4 || len(gnome.Genes) != 3 || !gnome.Genes[0].IsEnabled {
+		t.Error("The genome should not be changed by preview", len(gnome.Nodes), len(gnome.Genes))
+	}
+	if len(pop.Innovations) != 0 || pop.nextNodeId != 5 || pop.nextInnovNum != 5 {
+		t.Error("The population should not be changed by preview", len(pop.Innovations), pop.nextNodeId)
+	}
+
+	if !delta.Mutated || !delta.IsStructural() || delta.IsEmpty() || delta.Operator != AddNodeMutation.Name() {
+		t.Error("Wrong delta", delta)
+	}
+	if len(delta.AddedNodes) != 1 || len(delta.AddedGenes) != 2 || len(delta.RemovedNodes) != 0 ||
+		len(delta.RemovedGenes) != 0 {
+		t.Error("Wrong structural changes", delta)
+		return
+	}
+	if len(delta.ChangedGenes) != 1 || !delta.ChangedGenes[0].Before.IsEnabled || delta.ChangedGenes[0].After.IsEnabled {
+		t.Error("The split gene should be disabled", delta.ChangedGenes)
+	}
+	if delta.AddedNodes[0].Id != 6 || len(delta.Proposed.Nodes) != 5 {
+		t.Error("Wrong proposed genome", delta.AddedNodes[0].Id, len(delta.Proposed.Nodes))
+	}
+
+	// the preview is committed
+	proposed, err := pop.ApplyPreview(delta)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if proposed != delta.Proposed || len(pop.Innovations) != 1 || pop.nextNodeId != 6 || pop.nextInnovNum != 7 {
+		t.Error("The innovations of preview should be registered", len(pop.Innovations), pop.nextNodeId,
+			pop.nextInnovNum)
+	}
+	if _, err = pop.ApplyPreview(delta); err == nil {
+		t.Error("Error expected for stale preview")
+	}
+}
+
+func TestGenome_PreviewMutation_noPopulation(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	weights := make([]float64, len(gnome.Genes))
+	for i, gene := range gnome.Genes {
+		weights[i] = gene.Link.Weight
+	}
+	conf := neat.NeatContext{WeightMutPower:0.5}
+	delta, err := gnome.PreviewMutation(LinkWeightsMutation, nil, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if delta.IsStructural() || len(delta.ChangedGenes) == 0 {
+		t.Error("Only gene weights should be changed", delta)
+	}
+	for i, gene := range gnome.Genes {
+		if gene.Link.Weight != weights[i] {
+			t.Error("The genome should not be changed by preview")
+		}
+	}
+	for _, change := range delta.ChangedGenes {
+		if change.Before.Link.Weight == change.After.Link.Weight {
+			t.Error("The weight of changed gene should differ", change.Before.InnovationNum)
+		}
+	}
+	if str := delta.String(); len(str) == 0 {
+		t.Error("Empty delta summary")
+	}
+}
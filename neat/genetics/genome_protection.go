@@ -0,0 +1,67 @@
+package genetics
+
+// Marks genes of this genome with given innovation numbers as frozen, i.e. protected from mutations (see
+// Gene.IsFrozen). It allows to preserve domain knowledge encoded in the seed genome, e.g. the known-good reflex circuit,
+// while evolution augments network around it. Returns error if there is no gene with any of given innovation numbers,
+// the genes are not changed in such case.
+func (g *Genome) FreezeGenes(innovations ...int64) error {
+	return g.setGenesFrozen(true, innovations)
+}
+
+// Removes protection from mutations of genes of this genome with given innovation numbers. Returns error if there is
+// no gene with any of given innovation numbers, the genes are not changed in such case.
+func (g *Genome) UnfreezeGenes(innovations ...int64) error {
+	return g.setGenesFrozen(false, innovations)
+}
+
+// Marks all genes of this genome as frozen, e.g. to protect all connections of the seed genome
+func (g *Genome) FreezeAllGenes() {
+	for _, gn := range g.Genes {
+		gn.IsFrozen = true
+	}
+}
+
+// Returns the frozen genes of this genome
+func (g *Genome) FrozenGenes() []*Gene {
+	frozen := make([]*Gene, 0)
+	for _, gn := range g.Genes {
+		if gn.IsFrozen {
+			frozen = append(frozen, gn)
+		}
+	}
+	return frozen
+}
+
+func (g *Genome) setGenesFrozen(frozen bool, innovations []int64) error {
+	genes := make([]*Gene, len(innovations))
+	for i, innov := range innovations {
+		for _, gn := range g.Genes {
+			if gn.InnovationNum == innov {
+				genes[i] = gn
+				break
+			}
+		}
+		if genes[i] == nil {
+			return newError(ErrInvalidParameter, "GENOME: No gene with innovation number: %d found in genome [%d]",
+				innov, g.Id)
+		}
+	}
+	for _, gn := range genes {
+		gn.IsFrozen = frozen
+	}
+	return nil
+}
+
+// Makes the gene averaged by mating from two parent genes with the same innovation number keep the weight, trait and
+// enabled status of the frozen parent gene if any, the averaged gene is frozen as well in such case
+func averageFrozenGene(avg_gene, p1gene, p2gene *Gene) {
+	avg_gene.IsFrozen = false
+	for _, gn := range []*Gene{p1gene, p2gene} {
+		if gn.IsFrozen {
+			avg_gene.Link.Weight, avg_gene.Link.Trait = gn.Link.Weight, gn.Link.Trait
+			avg_gene.IsEnabled, avg_gene.MutationNum = gn.IsEnabled, gn.MutationNum
+			avg_gene.IsFrozen = true
+			return
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestGenome_FreezeGenes(t *testing.T) {
+	gnome := buildTestGenome(1)
+	if err := gnome.FreezeGenes(1, 3); err != nil {
+		t.Error(err)
+		return
+	}
+	frozen := gnome.FrozenGenes()
+	if len(frozen) != 2 || frozen[0].InnovationNum != 1 || frozen[1].InnovationNum != 3 {
+		t.Error("Wrong frozen genes", frozen)
+	}
+	if err := gnome.UnfreezeGenes(1); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(gnome.FrozenGenes()) != 1 || !gnome.Genes[2].IsFrozen {
+		t.Error("Only the third gene should be frozen")
+	}
+
+	// no genes changed if any innovation number is missing
+	if err := gnome.FreezeGenes(2, 10); err == nil {
+		t.Error("Error expected for missing innovation number")
+	}
+	if gnome.Genes[1].IsFrozen {
+		t.Error("The gene should not be frozen after error")
+	}
+	if err := gnome.UnfreezeGenes(10); err == nil {
+		t.Error("Error expected for missing innovation number")
+	}
+
+	gnome.FreezeAllGenes()
+	if len(gnome.FrozenGenes()) != len(gnome.Genes) {
+		t.Error("All genes should be frozen")
+	}
+
+	clone, err := gnome.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(clone.FrozenGenes()) != len(gnome.Genes) {
+		t.Error("The frozen flags should be copied")
+	}
+}
+
+func TestGenome_frozenGenesMutations(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	gnome.FreezeAllGenes()
+	context := &neat.NeatContext{WeightMin:-1.0, WeightMax:1.0}
+
+	if res, err := gnome.mutateLinkWeights(10.0, 1.0, gaussianMutator, GaussianWeightPerturbation, utils.GlobalRand()); !res || err != nil {
+		t.Error("Failed to mutate link weights", res, err)
+	}
+	gnome.boundLinkWeights(context)
+	if _, err := gnome.mutateLinkTrait(10, utils.GlobalRand()); err != nil {
+		t.Error(err)
+	}
+	if res, _ := gnome.mutateToggleEnable(10, utils.GlobalRand()); res {
+		t.Error("The frozen genes should not be toggled")
+	}
+	if res, _ := gnome.mutateDeleteLink(utils.GlobalRand()); res {
+		t.Error("The frozen genes should not be deleted")
+	}
+	if res, _ := gnome.mutateLinkDelay(5, utils.GlobalRand()); res {
+		t.Error("The delay of frozen genes should not be changed")
+	}
+	if res, _ := gnome.mutateLinkGate(context); res {
+		t.Error("The frozen genes should not be gated")
+	}
+	if res, _ := gnome.mutateAddNode(newPopulation(), context); res {
+		t.Error("The frozen genes should not be split")
+	}
+
+	expected := buildTestGenome(1)
+	if len(gnome.Genes) != len(expected.Genes) {
+		t.Error("Wrong number of genes", len(gnome.Genes))
+		return
+	}
+	for i, gn := range gnome.Genes {
+		eg := expected.Genes[i]
+		if gn.Link.Weight != eg.Link.Weight || traitId(gn.Link.Trait) != traitId(eg.Link.Trait) || !gn.IsEnabled ||
+			gn.Link.Delay != 0 || gn.Link.GateNode != nil {
+			t.Error("The frozen gene was mutated", gn)
+		}
+	}
+
+	// the disabled frozen gene should not be re-enabled
+	gnome.Genes[1].IsEnabled = false
+	if res, _ := gnome.mutateGeneReenable(); res || gnome.Genes[1].IsEnabled {
+		t.Error("The frozen gene should not be re-enabled")
+	}
+}
+
+func TestGenome_mate_frozenGenes(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenome(1)
+	gnome2 := buildTestGenome(2)
+	for _, gn := range gnome2.Genes {
+		gn.Link.Weight = -gn.Link.Weight
+		gn.IsEnabled = false
+	}
+	if err := gnome1.FreezeGenes(2); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the averaged gene keeps data of the frozen parent gene
+	context := &neat.NeatContext{}
+	for i := 0; i < 10; i++ {
+		for _, child := range []*Genome{
+			mustMate(gnome1.mateMultipointAvg(gnome2, 3, 1.0, 1.0, context)),
+			mustMate(gnome2.mateMultipointAvg(gnome1, 3, 1.0, 1.0, context)),
+		} {
+			if child == nil {
+				t.Error("Failed to create child genome")
+				return
+			}
+			for _, gn := range child.Genes {
+				if gn.InnovationNum == 2 && (!gn.IsFrozen || gn.Link.Weight != 2.5 || !gn.IsEnabled) {
+					t.Error("The frozen gene should be inherited unchanged", gn)
+				} else if gn.InnovationNum != 2 && gn.IsFrozen {
+					t.Error("Only gene 2 should be frozen", gn)
+				}
+			}
+		}
+	}
+}
+
+func mustMate(child *Genome, err error) *Genome {
+	if err != nil {
+		return nil
+	}
+	return child
+}
+
+func TestGenome_frozenGenesPersistence(t *testing.T) {
+	gnome := buildTestGenome(1)
+	if err := gnome.FreezeGenes(1, 3); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, encoding := range []GenomeEncoding{PlainGenomeEncoding, YAMLGenomeEncoding, JSONGenomeEncoding} {
+		out_buf := bytes.NewBufferString("")
+		wr, err := NewGenomeWriter(out_buf, encoding)
+		if err == nil {
+			err = wr.WriteGenome(gnome)
+		}
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reader, err := NewGenomeReader(bytes.NewReader(out_buf.Bytes()), encoding)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		gnome_enc, err := reader.Read()
+		if err != nil {
+			t.Error(encoding, err)
+			continue
+		}
+		for i, gn := range gnome_enc.Genes {
+			if gn.IsFrozen != gnome.Genes[i].IsFrozen || gn.Link.Weight != gnome.Genes[i].Link.Weight {
+				t.Error("Wrong frozen flag of gene at:", i, encoding)
+			}
+		}
+	}
+}
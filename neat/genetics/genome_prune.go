@@ -0,0 +1,104 @@
+package genetics
+
+import (
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// Returns minimized copy of this genome with non-functional structure removed, e.g. for deployment of evolved champion.
+// The disabled genes, the hidden nodes which are not on any path from inputs to outputs (including dead-end ones) and
+// the genes connected to them are not copied. The input and output nodes are always kept to preserve the network
+// interface as well as all IO nodes of functional modules to preserve their arity. This genome stays intact.
+func (g *Genome) Prune() (*Genome, error) {
+	pruned, err := g.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	// build the adjacency lists of enabled connections, the control nodes of modules included as ordinary nodes
+	forward, backward := make(map[int][]int), make(map[int][]int)
+	connect := func(from, to int) {
+		forward[from] = append(forward[from], to)
+		backward[to] = append(backward[to], from)
+	}
+	for _, gn := range pruned.Genes {
+		if gn.IsEnabled {
+			connect(gn.Link.InNode.Id, gn.Link.OutNode.Id)
+		}
+	}
+	for _, cg := range pruned.ControlGenes {
+		if cg.IsEnabled {
+			for _, l := range cg.ControlNode.Incoming {
+				connect(l.InNode.Id, cg.ControlNode.Id)
+			}
+			for _, l := range cg.ControlNode.Outgoing {
+				connect(cg.ControlNode.Id, l.OutNode.Id)
+			}
+		}
+	}
+
+	// find nodes reachable from inputs and nodes from which outputs are reachable
+	sources, sinks := make([]int, 0), make([]int, 0)
+	for _, n := range pruned.Nodes {
+		if n.IsSensor() {
+			sources = append(sources, n.Id)
+		} else if n.NeuronType == network.OutputNeuron {
+			sinks = append(sinks, n.Id)
+		}
+	}
+	from_inputs, to_outputs := reachableNodes(sources, forward), reachableNodes(sinks, backward)
+	keep := make(map[int]bool, len(pruned.Nodes))
+	for _, n := range pruned.Nodes {
+		if n.IsSensor() || n.NeuronType == network.OutputNeuron || from_inputs[n.Id] && to_outputs[n.Id] {
+			keep[n.Id] = true
+		}
+	}
+
+	// the modules arity should be preserved, thus all IO nodes of functional modules kept
+	control_genes := make([]*MIMOControlGene, 0, len(pruned.ControlGenes))
+	for _, cg := range pruned.ControlGenes {
+		if cg.IsEnabled && from_inputs[cg.ControlNode.Id] && to_outputs[cg.ControlNode.Id] {
+			control_genes = append(control_genes, cg)
+			for _, l := range cg.ControlNode.Incoming {
+				keep[l.InNode.Id] = true
+			}
+			for _, l := range cg.ControlNode.Outgoing {
+				keep[l.OutNode.Id] = true
+			}
+		}
+	}
+
+	nodes := make([]*network.NNode, 0, len(pruned.Nodes))
+	for _, n := range pruned.Nodes {
+		if keep[n.Id] {
+			nodes = append(nodes, n)
+		}
+	}
+	genes := make([]*Gene, 0, len(pruned.Genes))
+	for _, gn := range pruned.Genes {
+		if gn.IsEnabled && keep[gn.Link.InNode.Id] && keep[gn.Link.OutNode.Id] {
+			genes = append(genes, gn)
+		}
+	}
+	if len(genes) == 0 {
+		return nil, newError(ErrEmptyGenome, "GENOME: No functional genes left after pruning of genome [%d]", g.Id)
+	}
+	pruned.Nodes, pruned.Genes, pruned.ControlGenes = nodes, genes, control_genes
+
+	return pruned, nil
+}
+
+// Returns IDs of nodes reachable from the start nodes following given adjacency lists
+func reachableNodes(start []int, adjacency map[int][]int) map[int]bool {
+	visited := make(map[int]bool, len(adjacency))
+	stack := append([]int(nil), start...)
+	for len(stack) > 0 {
+		id := stack[len(stack) - 1]
+		stack = stack[:len(stack) - 1]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, adjacency[id]...)
+	}
+	return visited
+}
@@ -0,0 +1,100 @@
+package genetics
+
+import (
+	"testing"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Activates networks built from both genomes with the same inputs and checks that outputs are equal
+func checkSameOutputs(t *testing.T, g1, g2 *Genome) {
+	net1, err := g1.Genesis(g1.Id)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	net2, err := g2.Genesis(g2.Id)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	in := []float64{0.5, 1.5}
+	for _, net := range []*network.Network{net1, net2} {
+		net.LoadSensors(in)
+		for i := 0; i < 5; i++ {
+			if _, err = net.Activate(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}
+	out1, out2 := net1.ReadOutputs(), net2.ReadOutputs()
+	for i := range out1 {
+		if out1[i] != out2[i] {
+			t.Error("Outputs mismatch", out1, out2)
+		}
+	}
+}
+
+func TestGenome_Prune(t *testing.T) {
+	gnome := buildTestGenome(1)
+	dead_end := &network.NNode{Id:5, NeuronType: network.HiddenNeuron, ActivationType: utils.SigmoidSteepenedActivation,
+		Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)}
+	no_inputs := &network.NNode{Id:6, NeuronType: network.HiddenNeuron, ActivationType: utils.SigmoidSteepenedActivation,
+		Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)}
+	gnome.Nodes = append(gnome.Nodes, dead_end, no_inputs)
+	gnome.Genes = append(gnome.Genes,
+		newGene(network.NewLinkWithTrait(gnome.Traits[0], 1.0, gnome.Nodes[0], dead_end, false), 4, 0, true),
+		newGene(network.NewLinkWithTrait(gnome.Traits[0], 1.0, no_inputs, gnome.Nodes[3], false), 5, 0, true))
+	gnome.Genes[1].IsEnabled = false
+
+	pruned, err := gnome.Prune()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pruned.Nodes) != 4 {
+		t.Error("Wrong number of nodes", len(pruned.Nodes))
+	}
+	for _, n := range pruned.Nodes {
+		if n.Id == dead_end.Id || n.Id == no_inputs.Id {
+			t.Error("Non functional node was not pruned", n)
+		}
+	}
+	if len(pruned.Genes) != 2 || pruned.Genes[0].InnovationNum != 1 || pruned.Genes[1].InnovationNum != 3 {
+		t.Error("Wrong genes left", pruned.Genes)
+	}
+	if len(gnome.Nodes) != 6 || len(gnome.Genes) != 5 {
+		t.Error("The original genome should stay intact")
+	}
+	if ok, err := pruned.verify(); !ok {
+		t.Error(err)
+	}
+	checkSameOutputs(t, gnome, pruned)
+}
+
+func TestGenome_Prune_modular(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	pruned, err := gnome.Prune()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pruned.ControlGenes) != 1 {
+		t.Error("Functional module was pruned")
+	}
+	if len(pruned.Nodes) != len(gnome.Nodes) || len(pruned.Genes) != len(gnome.Genes) {
+		t.Error("Functional structure was pruned", pruned)
+	}
+	checkSameOutputs(t, gnome, pruned)
+}
+
+func TestGenome_Prune_noFunctionalGenes(t *testing.T) {
+	gnome := buildTestGenome(1)
+	for _, gn := range gnome.Genes {
+		gn.IsEnabled = false
+	}
+	if _, err := gnome.Prune(); err == nil {
+		t.Error("Error expected")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"io"
 	"fmt"
 	"bufio"
-	"errors"
 	"strings"
 	"strconv"
 	"gopkg.in/yaml.v2"
@@ -28,6 +27,11 @@ func NewGenomeReader(r io.Reader, encoding GenomeEncoding) (GenomeReader, error)
 		return &plainGenomeReader{r: bufio.NewReader(r)}, nil
 	case YAMLGenomeEncoding:
 		return &yamlGenomeReader{r: bufio.NewReader(r)}, nil
+	case JSONGenomeEncoding:
+		return &jsonGenomeReader{r: bufio.NewReader(r)}, nil
+	case NeatPythonGenomeEncoding:
+		return nil, newError(ErrUnsupportedGenomeEncoding, "NEAT-Python genome reader requires number of inputs and outputs, " +
+			"use NewNeatPythonGenomeReader instead")
 	default:
 		return nil, ErrUnsupportedGenomeEncoding
 	}
@@ -53,7 +57,7 @@ func (pgr *plainGenomeReader) Read() (*Genome, error) {
 		line := scanner.Text()
 		parts := strings.SplitN(line, " ", 2)
 		if len(parts) < 2 {
-			return nil, errors.New(fmt.Sprintf("Line: [%s] can not be split when reading Genome", line))
+			return nil, newError(ErrMalformedGenome, "Line: [%s] can not be split when reading Genome", line)
 		}
 		lr := strings.NewReader(parts[1])
 
@@ -66,8 +70,8 @@ func (pgr *plainGenomeReader) Read() (*Genome, error) {
 			}
 			// check that trait ID is unique
 			if prev_trait := traitWithId(new_trait.Id, gnome.Traits); prev_trait != nil {
-				return nil, errors.New(
-					fmt.Sprintf("Trait ID: %d is not unique", new_trait.Id))
+				return nil, newError(ErrMalformedGenome,
+					"Trait ID: %d is not unique", new_trait.Id)
 			}
 			gnome.Traits = append(gnome.Traits, new_trait)
 
@@ -79,8 +83,8 @@ func (pgr *plainGenomeReader) Read() (*Genome, error) {
 			}
 			// check that node ID is unique
 			if prev_node := nodeWithId(new_node.Id, gnome.Nodes); prev_node != nil {
-				return nil, errors.New(
-					fmt.Sprintf("Node ID: %d is not unique", new_node.Id))
+				return nil, newError(ErrMalformedGenome,
+					"Node ID: %d is not unique", new_node.Id)
 			}
 			gnome.Nodes = append(gnome.Nodes, new_node)
 
@@ -135,7 +139,7 @@ func readPlainNetworkNode(r io.Reader, traits []*neat.Trait) (*network.NNode, er
 	}
 	parts := strings.Split(string(line), " ")
 	if len(parts) < 4 {
-		return nil, errors.New(fmt.Sprintf("node line is too short: %d (%s)", len(parts), parts))
+		return nil, newError(ErrMalformedGenome, "node line is too short: %d (%s)", len(parts), parts)
 	}
 	if n_Id, err := strconv.ParseInt(parts[0], 10, 32); err != nil {
 		return nil, err
@@ -154,8 +158,21 @@ func readPlainNetworkNode(r io.Reader, traits []*neat.Trait) (*network.NNode, er
 		n.NeuronType = network.NodeNeuronType(n_NeuronType)
 	}
 
-	if len(parts) == 5 {
-		n.ActivationType, err = utils.NodeActivators.ActivationTypeFromName(parts[4])
+	if len(parts) >= 5 {
+		if n.ActivationType, err = utils.NodeActivators.ActivationTypeFromName(parts[4]); err != nil {
+			return nil, err
+		}
+	}
+	if len(parts) >= 6 {
+		if n.InitialActivation, err = strconv.ParseFloat(parts[5], 64); err != nil {
+			return nil, err
+		}
+	}
+	// the memory cell is marked by node type and has forget gate value after initial activation
+	if n.IsMemory = parts[2] == strconv.Itoa(int(network.MemoryNode)); n.IsMemory && len(parts) >= 7 {
+		if n.MemoryRetention, err = strconv.ParseFloat(parts[6], 64); err != nil {
+			return nil, err
+		}
 	}
 
 	return n, err
@@ -173,6 +190,22 @@ func readPlainConnectionGene(r io.Reader, traits []*neat.Trait, nodes []*network
 		return nil, err
 	}
 
+	// the optional delay and gating node ID followed by optional frozen flag and self-adapted mutation step size
+	var delay, gateId int
+	var frozen bool
+	var sigma float64
+	if _, err = fmt.Fscanf(r, "%d %d", &delay, &gateId); err == nil {
+		if _, err = fmt.Fscanf(r, " %t", &frozen); err == nil {
+			if _, err = fmt.Fscanf(r, " %g", &sigma); err != nil && err != io.EOF {
+				return nil, err
+			}
+		} else if err != io.EOF {
+			return nil, err
+		}
+	} else if err != io.EOF {
+		return nil, err
+	}
+
 	trait := traitWithId(traitId, traits)
 	var inNode, outNode *network.NNode
 	for _, np := range nodes {
@@ -183,11 +216,28 @@ func readPlainConnectionGene(r io.Reader, traits []*neat.Trait, nodes []*network
 			outNode = np
 		}
 	}
+	var gene *Gene
 	if trait != nil {
-		return newGene(network.NewLinkWithTrait(trait, weight, inNode, outNode, recurrent), inov_num, mut_num, enabled), nil
+		gene = newGene(network.NewLinkWithTrait(trait, weight, inNode, outNode, recurrent), inov_num, mut_num, enabled)
 	} else {
-		return newGene(network.NewLink(weight, inNode, outNode, recurrent), inov_num, mut_num, enabled), nil
+		gene = newGene(network.NewLink(weight, inNode, outNode, recurrent), inov_num, mut_num, enabled)
 	}
+	gene.IsFrozen, gene.mutationSigma = frozen, sigma
+	return gene, setGeneDelayAndGate(gene, delay, gateId, nodes)
+}
+
+// Sets delay and gating node with given ID to the link of gene, the zero gate ID means that link is not gated
+func setGeneDelayAndGate(gene *Gene, delay, gate_id int, nodes []*network.NNode) error {
+	if delay < 0 {
+		return newError(ErrMalformedGenome, "Negative delay of link: %d", delay)
+	}
+	gene.Link.Delay = delay
+	if gate_id != 0 {
+		if gene.Link.GateNode = nodeWithId(gate_id, nodes); gene.Link.GateNode == nil {
+			return newError(ErrMalformedGenome, "Gating node: %d not found", gate_id)
+		}
+	}
+	return nil
 }
 
 // A YAMLGenomeReader reads genome data from YAML encoded text file
@@ -205,7 +255,7 @@ func (ygr *yamlGenomeReader) Read() (*Genome, error) {
 
 	gm, ok := m["genome"].(map[interface{}]interface{})
 	if ok == false {
-		return nil, errors.New("failed to parse YAML configuration")
+		return nil, newError(ErrMalformedGenome, "failed to parse YAML configuration")
 	}
 
 	// read Genome
@@ -230,8 +280,8 @@ func (ygr *yamlGenomeReader) Read() (*Genome, error) {
 		}
 		// check that trait ID is unique
 		if prev_trait := traitWithId(trait.Id, gnome.Traits); prev_trait != nil {
-			return nil, errors.New(
-				fmt.Sprintf("Trait ID: %d is not unique", trait.Id))
+			return nil, newError(ErrMalformedGenome,
+				"Trait ID: %d is not unique", trait.Id)
 		}
 		gnome.Traits = append(gnome.Traits, trait)
 	}
@@ -245,8 +295,8 @@ func (ygr *yamlGenomeReader) Read() (*Genome, error) {
 		}
 		// check that node ID is unique
 		if prev_node := nodeWithId(node.Id, gnome.Nodes); prev_node != nil {
-			return nil, errors.New(
-				fmt.Sprintf("Node ID: %d is not unique", node.Id))
+			return nil, newError(ErrMalformedGenome,
+				"Node ID: %d is not unique", node.Id)
 		}
 		gnome.Nodes = append(gnome.Nodes, node)
 	}
@@ -271,8 +321,8 @@ func (ygr *yamlGenomeReader) Read() (*Genome, error) {
 			}
 			// check that control node ID is unique
 			if prev_node := nodeWithId(mGene.ControlNode.Id, gnome.Nodes); prev_node != nil {
-				return nil, errors.New(
-					fmt.Sprintf("Control node ID: %d is not unique", mGene.ControlNode.Id))
+				return nil, newError(ErrMalformedGenome,
+					"Control node ID: %d is not unique", mGene.ControlNode.Id)
 			}
 			gnome.ControlGenes = append(gnome.ControlGenes, mGene)
 		}
@@ -307,6 +357,26 @@ func readGene(conf map[interface{}]interface{}, traits []*neat.Trait, nodes []*n
 		return nil, err
 	}
 
+	// the optional delay and gating node ID
+	delay, err := cast.ToIntE(conf["delay"])
+	if err != nil && conf["delay"] != nil {
+		return nil, err
+	}
+	gateId, err := cast.ToIntE(conf["gate_id"])
+	if err != nil && conf["gate_id"] != nil {
+		return nil, err
+	}
+	// the optional frozen flag
+	frozen, err := cast.ToBoolE(conf["frozen"])
+	if err != nil && conf["frozen"] != nil {
+		return nil, err
+	}
+	// the optional self-adapted mutation step size
+	sigma, err := cast.ToFloat64E(conf["mutation_sigma"])
+	if err != nil && conf["mutation_sigma"] != nil {
+		return nil, err
+	}
+
 	trait := traitWithId(traitId, traits)
 	var inNode, outNode *network.NNode
 	for _, np := range nodes {
@@ -317,11 +387,14 @@ func readGene(conf map[interface{}]interface{}, traits []*neat.Trait, nodes []*n
 			outNode = np
 		}
 	}
+	var gene *Gene
 	if trait != nil {
-		return newGene(network.NewLinkWithTrait(trait, weight, inNode, outNode, recurrent), inov_num, mut_num, enabled), nil
+		gene = newGene(network.NewLinkWithTrait(trait, weight, inNode, outNode, recurrent), inov_num, mut_num, enabled)
 	} else {
-		return newGene(network.NewLink(weight, inNode, outNode, recurrent), inov_num, mut_num, enabled), nil
+		gene = newGene(network.NewLink(weight, inNode, outNode, recurrent), inov_num, mut_num, enabled)
 	}
+	gene.IsFrozen, gene.mutationSigma = frozen, sigma
+	return gene, setGeneDelayAndGate(gene, delay, gateId, nodes)
 }
 
 // Reads MIMOControlGene configuration
@@ -371,8 +444,8 @@ func readMIMOControlGene(conf map[interface{}]interface{}, traits []*neat.Trait,
 		if node != nil {
 			control_node.Incoming[i] = network.NewLink(1.0, node, control_node, false)
 		} else {
-			return nil, errors.New(fmt.Sprintf("no MIMO input node with id: %d can be found for module: %d",
-				node_id, control_node.Id))
+			return nil, newError(ErrMalformedGenome, "no MIMO input node with id: %d can be found for module: %d",
+				node_id, control_node.Id)
 		}
 	}
 
@@ -392,8 +465,8 @@ func readMIMOControlGene(conf map[interface{}]interface{}, traits []*neat.Trait,
 		if node != nil {
 			control_node.Outgoing[i] = network.NewLink(1.0, control_node, node, false)
 		} else {
-			return nil, errors.New(fmt.Sprintf("no MIMO output node with id: %d can be found for module: %d",
-				node_id, control_node.Id))
+			return nil, newError(ErrMalformedGenome, "no MIMO output node with id: %d can be found for module: %d",
+				node_id, control_node.Id)
 		}
 	}
 
@@ -416,7 +489,18 @@ func readNNode(conf map[interface{}]interface{}, traits []*neat.Trait) (*network
 		return nil, err
 	}
 	activation := conf["activation"].(string)
-	nd.ActivationType, err = utils.NodeActivators.ActivationTypeFromName(activation)
+	if nd.ActivationType, err = utils.NodeActivators.ActivationTypeFromName(activation); err != nil {
+		return nil, err
+	}
+	if init_activation, ok := conf["init_activation"]; ok {
+		if nd.InitialActivation, err = cast.ToFloat64E(init_activation); err != nil {
+			return nil, err
+		}
+	}
+	if retention, ok := conf["memory_retention"]; ok {
+		nd.IsMemory = true
+		nd.MemoryRetention, err = cast.ToFloat64E(retention)
+	}
 	return nd, err
 }
 
@@ -435,3 +519,247 @@ func readTrait(conf map[interface{}]interface{}) (*neat.Trait, error) {
 	return nt, nil
 }
 
+
+// Creates reader for genomes encoded in text format produced by string representation of NEAT-Python DefaultGenome.
+// The num_inputs and num_outputs is the number of genome inputs and outputs as defined by NEAT-Python configuration.
+func NewNeatPythonGenomeReader(r io.Reader, num_inputs, num_outputs int) GenomeReader {
+	return &neatPythonGenomeReader{r: bufio.NewReader(r), numInputs:num_inputs, numOutputs:num_outputs}
+}
+
+// The reader of genomes encoded in text format produced by string representation of NEAT-Python DefaultGenome, e.g.:
+//
+//	Key: 1
+//	Fitness: 3.9
+//	Nodes:
+//		0 DefaultNodeGene(key=0, bias=0.1, response=1.0, activation=sigmoid, aggregation=sum)
+//	Connections:
+//		DefaultConnectionGene(key=(-1, 0), weight=0.5, enabled=True)
+//
+// NEAT-Python input nodes have negative keys (-1, -2, ...) and the node with key -k gets ID k. The inputs followed by
+// bias node with ID (num_inputs + 1), which holds biases of all nodes as weights of links from it, and should be loaded
+// with 1.0 when network activated. The output and hidden nodes get ID (key + num_inputs + 2). The node response is
+// applied to the weights of its incoming links. The connection is marked as recurrent if it closes a cycle with
+// connections listed before it. Only sum aggregation is supported.
+type neatPythonGenomeReader struct {
+	r          *bufio.Reader
+	numInputs  int
+	numOutputs int
+}
+
+func (npr *neatPythonGenomeReader) Read() (*Genome, error) {
+	gen_id, node_lines, conn_lines, err := npr.readSections()
+	if err != nil {
+		return nil, err
+	}
+
+	// the default trait for all nodes and genes
+	trait := neat.NewTrait()
+	trait.Id = 1
+	gnome := Genome{
+		Id:gen_id,
+		Traits:[]*neat.Trait{trait},
+		Nodes:make([]*network.NNode, 0),
+		Genes:make([]*Gene, 0),
+	}
+
+	// create input and bias nodes
+	for i := 1; i <= npr.numInputs; i++ {
+		node := network.NewNNode(i, network.InputNeuron)
+		node.ActivationType = utils.NullActivation
+		node.Trait = trait
+		gnome.Nodes = append(gnome.Nodes, node)
+	}
+	bias_node := network.NewNNode(npr.numInputs + 1, network.BiasNeuron)
+	bias_node.ActivationType = utils.NullActivation
+	bias_node.Trait = trait
+	gnome.Nodes = append(gnome.Nodes, bias_node)
+
+	// read output and hidden nodes
+	biases := make([]float64, len(node_lines))
+	responses := make(map[int]float64)
+	for i, line := range node_lines {
+		attrs, err := parseNeatPythonGene(line)
+		if err != nil {
+			return nil, err
+		}
+		key, err := strconv.Atoi(attrs["key"])
+		if err != nil {
+			return nil, err
+		}
+		if key < 0 {
+			return nil, newError(ErrMalformedGenome, "NEAT-Python node gene with negative key: %s", line)
+		}
+		if aggr, ok := attrs["aggregation"]; ok && aggr != "sum" {
+			return nil, newError(ErrUnsupportedType, "Unsupported NEAT-Python aggregation: %s", aggr)
+		}
+		neuron_type := network.HiddenNeuron
+		if key < npr.numOutputs {
+			neuron_type = network.OutputNeuron
+		}
+		node := network.NewNNode(npr.nodeId(key), neuron_type)
+		node.Trait = trait
+		if node.ActivationType, err = utils.ActivationTypeFromNeatPython(attrs["activation"]); err != nil {
+			return nil, err
+		}
+		if biases[i], err = neatPythonFloatAttr(attrs, "bias", 0.0); err != nil {
+			return nil, err
+		}
+		if responses[node.Id], err = neatPythonFloatAttr(attrs, "response", 1.0); err != nil {
+			return nil, err
+		}
+		gnome.Nodes = append(gnome.Nodes, node)
+	}
+
+	// read connection genes, the connection is recurrent if it closes a cycle with feed-forward connections read before
+	innov_num := int64(1)
+	links := make(map[int][]int)
+	for _, line := range conn_lines {
+		attrs, err := parseNeatPythonGene(line)
+		if err != nil {
+			return nil, err
+		}
+		keys := strings.Split(strings.Trim(attrs["key"], "()"), ",")
+		if len(keys) != 2 {
+			return nil, newError(ErrMalformedGenome, "Malformed NEAT-Python connection key: %s", attrs["key"])
+		}
+		in_key, err := strconv.Atoi(strings.TrimSpace(keys[0]))
+		if err != nil {
+			return nil, err
+		}
+		out_key, err := strconv.Atoi(strings.TrimSpace(keys[1]))
+		if err != nil {
+			return nil, err
+		}
+		in_node, out_node := nodeWithId(npr.nodeId(in_key), gnome.Nodes), nodeWithId(npr.nodeId(out_key), gnome.Nodes)
+		if in_node == nil || out_node == nil {
+			return nil, newError(ErrMalformedGenome, "Connection refers unknown node: %s", line)
+		}
+		weight, err := strconv.ParseFloat(attrs["weight"], 64)
+		if err != nil {
+			return nil, err
+		}
+		recurrent := pathExists(links, out_node.Id, in_node.Id)
+		if !recurrent {
+			links[in_node.Id] = append(links[in_node.Id], out_node.Id)
+		}
+		gene := NewGeneWithTrait(trait, weight * responses[out_node.Id], in_node, out_node,
+			recurrent, innov_num, 0)
+		gene.IsEnabled = attrs["enabled"] == "True"
+		gnome.Genes = append(gnome.Genes, gene)
+		innov_num++
+	}
+
+	// store biases as links from bias node
+	for i, bias := range biases {
+		if bias == 0 {
+			continue
+		}
+		node := gnome.Nodes[npr.numInputs + 1 + i]
+		gene := NewGeneWithTrait(trait, bias, bias_node, node, false, innov_num, 0)
+		gnome.Genes = append(gnome.Genes, gene)
+		innov_num++
+	}
+	return &gnome, nil
+}
+
+// Returns node ID corresponding to the given NEAT-Python node key
+func (npr *neatPythonGenomeReader) nodeId(key int) int {
+	if key < 0 {
+		return -key
+	}
+	return key + npr.numInputs + 2
+}
+
+// Reads key of genome and lines with node and connection genes. Stops reading when the next genome record found.
+func (npr *neatPythonGenomeReader) readSections() (gen_id int, nodes, conns []string, err error) {
+	section := ""
+	for {
+		if section != "" {
+			// check if next genome record starts
+			if next, _ := npr.r.Peek(4); string(next) == "Key:" {
+				break
+			}
+		}
+		line, read_err := npr.r.ReadString('\n')
+		if read_err != nil && read_err != io.EOF {
+			return 0, nil, nil, read_err
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Key:"):
+			if gen_id, err = strconv.Atoi(strings.TrimSpace(trimmed[4:])); err != nil {
+				return 0, nil, nil, err
+			}
+			section = "key"
+		case section == "":
+			if len(trimmed) > 0 {
+				return 0, nil, nil, newError(ErrMalformedGenome, "NEAT-Python genome should start with Key: %s", trimmed)
+			}
+		case strings.HasPrefix(trimmed, "Fitness:"):
+			// fitness is not a part of genome
+		case trimmed == "Nodes:":
+			section = "nodes"
+		case trimmed == "Connections:":
+			section = "connections"
+		case len(trimmed) == 0:
+			// skip empty lines
+		case section == "nodes":
+			// the node line starts with node key followed by gene
+			parts := strings.SplitN(trimmed, " ", 2)
+			if len(parts) != 2 {
+				return 0, nil, nil, newError(ErrMalformedGenome, "Malformed NEAT-Python node gene: %s", trimmed)
+			}
+			nodes = append(nodes, parts[1])
+		case section == "connections":
+			conns = append(conns, trimmed)
+		default:
+			return 0, nil, nil, newError(ErrMalformedGenome, "Unexpected line in NEAT-Python genome: %s", trimmed)
+		}
+		if read_err == io.EOF {
+			break
+		}
+	}
+	if section == "" {
+		return 0, nil, nil, io.EOF
+	}
+	return gen_id, nodes, conns, nil
+}
+
+// Returns float value of NEAT-Python gene attribute or default value if attribute is absent
+func neatPythonFloatAttr(attrs map[string]string, name string, def float64) (float64, error) {
+	if value, ok := attrs[name]; ok {
+		return strconv.ParseFloat(value, 64)
+	}
+	return def, nil
+}
+
+// Parses attributes of NEAT-Python gene string representation, e.g.:
+// DefaultConnectionGene(key=(-1, 0), weight=0.5, enabled=True)
+func parseNeatPythonGene(line string) (map[string]string, error) {
+	start, end := strings.Index(line, "("), strings.LastIndex(line, ")")
+	if start < 0 || end < start {
+		return nil, newError(ErrMalformedGenome, "Malformed NEAT-Python gene: %s", line)
+	}
+	attrs := make(map[string]string)
+	depth, attr_start := 0, start + 1
+	body := line[:end] + ","
+	for i := start + 1; i < len(body); i++ {
+		switch body[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth > 0 {
+				continue
+			}
+			parts := strings.SplitN(body[attr_start:i], "=", 2)
+			if len(parts) != 2 {
+				return nil, newError(ErrMalformedGenome, "Malformed NEAT-Python gene attribute: %s", body[attr_start:i])
+			}
+			attrs[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			attr_start = i + 1
+		}
+	}
+	return attrs, nil
+}
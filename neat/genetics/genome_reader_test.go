@@ -8,6 +8,7 @@ import (
 	"os"
 	"github.com/yaricom/goNEAT/neat"
 	"github.com/yaricom/goNEAT/neat/utils"
+	"io"
 )
 
 func TestPlainGenomeReader_Read(t *testing.T) {
@@ -148,6 +149,30 @@ func TestReadGene_ReadPlainNNode(t *testing.T) {
 	if node.NeuronType != gen_node_label {
 		t.Errorf("The wrong node placement label found, %d != %d", gen_node_label, node.NeuronType)
 	}
+
+	// with activation type and initial activation
+	node_str = fmt.Sprintf("%d %d %d %d TanhActivation 0.5", node_id, trait_id, network.NeuronNode, network.HiddenNeuron)
+	if node, err = readPlainNetworkNode(strings.NewReader(node_str), traits); err != nil {
+		t.Error(err)
+		return
+	}
+	if node.ActivationType != utils.TanhActivation || node.InitialActivation != 0.5 {
+		t.Error("Wrong activation of node", node.ActivationType, node.InitialActivation)
+	}
+	node_str = fmt.Sprintf("%d %d %d %d TanhActivation x", node_id, trait_id, network.NeuronNode, network.HiddenNeuron)
+	if _, err = readPlainNetworkNode(strings.NewReader(node_str), traits); err == nil {
+		t.Error("Error expected for malformed initial activation")
+	}
+
+	// the memory cell
+	node_str = fmt.Sprintf("%d %d %d %d TanhActivation 0 0.75", node_id, trait_id, network.MemoryNode, network.HiddenNeuron)
+	if node, err = readPlainNetworkNode(strings.NewReader(node_str), traits); err != nil {
+		t.Error(err)
+		return
+	}
+	if !node.IsMemory || node.MemoryRetention != 0.75 {
+		t.Error("Wrong memory cell", node.IsMemory, node.MemoryRetention)
+	}
 }
 
 // Tests Gene ReadGene
@@ -197,6 +222,32 @@ func TestReadGene_ReadPlainGene(t *testing.T) {
 	if link.IsRecurrent != recurrent {
 		t.Error("link.IsRecurrent", recurrent, link.IsRecurrent)
 	}
+	if link.Delay != 0 || link.GateNode != nil {
+		t.Error("Link should not be delayed or gated", link.Delay, link.GateNode)
+	}
+
+	// with delay and gating node
+	if gene, err = readPlainConnectionGene(strings.NewReader(gene_str + " 2 1"), []*neat.Trait{trait}, nodes); err != nil {
+		t.Error(err)
+		return
+	}
+	if gene.Link.Delay != 2 || gene.Link.GateNode != nodes[0] {
+		t.Error("Wrong delay or gate of link", gene.Link.Delay, gene.Link.GateNode)
+	}
+	// with frozen flag and self-adapted mutation step size
+	if gene, err = readPlainConnectionGene(strings.NewReader(gene_str + " 0 0 false 0.5"), []*neat.Trait{trait}, nodes); err != nil {
+		t.Error(err)
+		return
+	}
+	if gene.IsFrozen || gene.mutationSigma != 0.5 {
+		t.Error("Wrong frozen flag or mutation sigma of gene", gene.IsFrozen, gene.mutationSigma)
+	}
+	if _, err = readPlainConnectionGene(strings.NewReader(gene_str + " 0 5"), []*neat.Trait{trait}, nodes); err == nil {
+		t.Error("Error expected for missing gating node")
+	}
+	if _, err = readPlainConnectionGene(strings.NewReader(gene_str + " -1 0"), []*neat.Trait{trait}, nodes); err == nil {
+		t.Error("Error expected for negative delay")
+	}
 }
 
 func TestPlainGenomeReader_ReadFile(t *testing.T) {
@@ -436,3 +487,114 @@ func TestYAMLGenomeReader_Read(t *testing.T) {
 		id_count++
 	}
 }
+
+const neatPythonGenomesStr = `Key: 42
+Fitness: 3.95
+Nodes:
+	0 DefaultNodeGene(key=0, bias=-0.5, response=2.0, activation=sigmoid, aggregation=sum)
+	3 DefaultNodeGene(key=3, bias=0.0, response=1.0, activation=tanh, aggregation=sum)
+Connections:
+	DefaultConnectionGene(key=(-2, 3), weight=1.5, enabled=True)
+	DefaultConnectionGene(key=(-1, 0), weight=0.25, enabled=False)
+	DefaultConnectionGene(key=(3, 0), weight=-1.0, enabled=True)
+Key: 43
+Fitness: None
+Nodes:
+	0 DefaultNodeGene(key=0, bias=0.1, response=1.0, activation=identity, aggregation=sum)
+Connections:
+	DefaultConnectionGene(key=(-1, 0), weight=0.5, enabled=True)
+`
+
+func TestNeatPythonGenomeReader_Read(t *testing.T) {
+	r := NewNeatPythonGenomeReader(strings.NewReader(neatPythonGenomesStr), 2, 1)
+	gnome, err := r.Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gnome.Id != 42 {
+		t.Error("gnome.Id", 42, gnome.Id)
+	}
+	// two inputs, bias, output and hidden
+	if len(gnome.Nodes) != 5 {
+		t.Error("len(gnome.Nodes)", 5, len(gnome.Nodes))
+		return
+	}
+	expected_types := []network.NodeNeuronType{network.InputNeuron, network.InputNeuron, network.BiasNeuron,
+		network.OutputNeuron, network.HiddenNeuron}
+	expected_ids := []int{1, 2, 3, 4, 7}
+	for i, n := range gnome.Nodes {
+		if n.NeuronType != expected_types[i] {
+			t.Error("Wrong neuron type at:", i, n.NeuronType)
+		}
+		if n.Id != expected_ids[i] {
+			t.Error("Wrong node ID at:", i, n.Id)
+		}
+	}
+	if gnome.Nodes[4].ActivationType != utils.TanhActivation {
+		t.Error("Wrong activation of hidden node", gnome.Nodes[4].ActivationType)
+	}
+
+	// three connections and one bias link
+	if len(gnome.Genes) != 4 {
+		t.Error("len(gnome.Genes)", 4, len(gnome.Genes))
+		return
+	}
+	// response applied to weights of output node
+	if gnome.Genes[1].Link.Weight != 0.5 || gnome.Genes[1].IsEnabled {
+		t.Error("Wrong gene", gnome.Genes[1])
+	}
+	if gnome.Genes[2].Link.Weight != -2.0 || gnome.Genes[2].Link.InNode.Id != 7 {
+		t.Error("Wrong gene", gnome.Genes[2])
+	}
+	bias := gnome.Genes[3]
+	if bias.Link.InNode.NeuronType != network.BiasNeuron || bias.Link.OutNode.Id != 4 || bias.Link.Weight != -0.5 {
+		t.Error("Wrong bias gene", bias)
+	}
+	if res, err := gnome.verify(); !res || err != nil {
+		t.Error("Genome verification failed", err)
+	}
+
+	// read the second genome from the same stream
+	gnome, err = r.Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if gnome.Id != 43 || len(gnome.Genes) != 2 {
+		t.Error("Wrong second genome", gnome)
+	}
+
+	if _, err = r.Read(); err != io.EOF {
+		t.Error("io.EOF expected", err)
+	}
+}
+
+func TestNeatPythonGenomeReader_Read_recurrent(t *testing.T) {
+	genome_str := `Key: 1
+Nodes:
+	0 DefaultNodeGene(key=0, bias=0.0, response=1.0, activation=sigmoid, aggregation=sum)
+	1 DefaultNodeGene(key=1, bias=0.0, response=1.0, activation=sigmoid, aggregation=sum)
+Connections:
+	DefaultConnectionGene(key=(-1, 1), weight=1.0, enabled=True)
+	DefaultConnectionGene(key=(0, 1), weight=1.0, enabled=True)
+	DefaultConnectionGene(key=(1, 0), weight=1.0, enabled=True)
+	DefaultConnectionGene(key=(1, 1), weight=1.0, enabled=True)
+`
+	gnome, err := NewNeatPythonGenomeReader(strings.NewReader(genome_str), 1, 1).Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(gnome.Genes) != 4 {
+		t.Error("len(gnome.Genes)", 4, len(gnome.Genes))
+		return
+	}
+	// the link closing the cycle between output and hidden nodes and the self-loop are recurrent
+	expected := []bool{false, false, true, true}
+	for i, gene := range gnome.Genes {
+		if gene.Link.IsRecurrent != expected[i] {
+			t.Error("Wrong recurrent flag at:", i, gene.Link.IsRecurrent)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The default number of random input samples to estimate sensitivity of network outputs to the link weights
+const defaultSafeMutationSamples = 10
+
+// The change of link weight used to estimate sensitivity of network outputs by finite difference
+const sensitivityWeightDelta = 0.01
+
+// Perturbs link weights of this genome with power and perturbation type configured by context. If safe mutations
+// enabled the perturbation of each link weight is scaled inversely to the sensitivity of network outputs to it, thus
+// the weights having strong effect on the network behavior get smaller perturbations.
+func (g *Genome) mutateLinkWeightsForContext(context *neat.NeatContext) (bool, error) {
+	var scales []float64
+	if context.SafeMutation {
+		samples := context.SafeMutationSamples
+		if samples <= 0 {
+			samples = defaultSafeMutationSamples
+		}
+		var err error
+		if scales, err = g.safeMutationScales(samples, context.Rand()); err != nil {
+			// it happens when network outputs are not reachable from inputs - fallback to ordinary perturbations
+			neat.DebugLog(fmt.Sprintf("GENOME: Failed to estimate weights sensitivity of genome [%d], reason: %s",
+				g.Id, err))
+		}
+	}
+	return g.mutateLinkWeightsScaled(context.WeightMutPower, 1.0, gaussianMutator,
+		WeightPerturbationType(context.WeightPerturbationType), scales, context.Rand())
+}
+
+// Returns the scale factors of link weight perturbations for each gene of this genome. The perturbation is scaled down
+// only for the weights which change network outputs more than the weight itself changes, i.e. the sensitivity of
+// outputs to such weights is greater than one.
+func (g *Genome) safeMutationScales(samples int, rng *rand.Rand) ([]float64, error) {
+	sensitivities, err := g.weightSensitivities(samples, rng)
+	if err != nil {
+		return nil, err
+	}
+	scales := make([]float64, len(sensitivities))
+	for i, s := range sensitivities {
+		scales[i] = 1.0 / math.Max(1.0, s)
+	}
+	return scales, nil
+}
+
+// Estimates sensitivity of network outputs to the link weight of each gene of this genome as the absolute change of
+// all outputs per unit change of link weight averaged over given number of random input samples uniformly distributed
+// in [0, 1) and drawn from provided generator. The returned list has the sensitivity of each gene at its index, the
+// sensitivity of disabled genes is zero.
+func (g *Genome) weightSensitivities(samples int, rng *rand.Rand) ([]float64, error) {
+	net, err := g.Genesis(g.Id)
+	if err != nil {
+		return nil, err
+	}
+	// find phenotype link of each enabled gene, the links are added to the out-node in order of genes
+	links := make([]*network.Link, len(g.Genes))
+	next_link := make(map[*network.NNode]int)
+	for i, gn := range g.Genes {
+		if gn.IsEnabled {
+			out_node := gn.Link.OutNode.PhenotypeAnalogue
+			links[i] = out_node.Incoming[next_link[out_node]]
+			next_link[out_node]++
+		}
+	}
+
+	in_count := 0
+	for _, n := range g.Nodes {
+		if n.NeuronType == network.InputNeuron {
+			in_count++
+		}
+	}
+	inputs := make([][]float64, samples)
+	for i := range inputs {
+		inputs[i] = make([]float64, in_count)
+		for j := range inputs[i] {
+			inputs[i][j] = rng.Float64()
+		}
+	}
+	// the depth of network to relax activation, it is approximate for networks with loops
+	depth, _ := net.MaxDepth()
+
+	// the base outputs of network
+	out_count := len(net.Outputs)
+	base := make([]float64, samples * out_count)
+	for i, in := range inputs {
+		if err = activateForSensitivity(net, in, depth, base[i * out_count:(i + 1) * out_count]); err != nil {
+			return nil, err
+		}
+	}
+
+	sensitivities := make([]float64, len(g.Genes))
+	outs := make([]float64, out_count)
+	for i, link := range links {
+		if link == nil {
+			continue
+		}
+		weight := link.Weight
+		link.Weight += sensitivityWeightDelta
+		for s, in := range inputs {
+			if err = activateForSensitivity(net, in, depth, outs); err != nil {
+				link.Weight = weight
+				return nil, err
+			}
+			for o, out := range outs {
+				sensitivities[i] += math.Abs(out - base[s * out_count + o])
+			}
+		}
+		link.Weight = weight
+		sensitivities[i] /= sensitivityWeightDelta * float64(samples)
+	}
+	return sensitivities, nil
+}
+
+// Returns link weight replaced by the new value. The scale less than one moves the weight only part of the way towards
+// the new value.
+func scaledWeightReplacement(weight, value, scale float64) float64 {
+	if scale == 1.0 {
+		return value
+	}
+	return weight + (value - weight) * scale
+}
+
+// Activates network with given inputs and relaxes it specified number of steps. The outputs stored into provided list.
+func activateForSensitivity(net *network.Network, inputs []float64, depth int, outs []float64) error {
+	if _, err := net.Flush(); err != nil {
+		return err
+	}
+	if err := net.LoadSensors(inputs); err != nil {
+		return err
+	}
+	for relax := 0; relax <= depth; relax++ {
+		if _, err := net.Activate(); err != nil {
+			return err
+		}
+	}
+	net.ReadOutputsInto(outs)
+	return nil
+}
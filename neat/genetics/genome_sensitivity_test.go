@@ -0,0 +1,103 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestGenome_weightSensitivities(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	gnome.Genes[1].IsEnabled = false
+
+	sensitivities, err := gnome.weightSensitivities(10, utils.GlobalRand())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(sensitivities) != len(gnome.Genes) {
+		t.Error("Wrong number of sensitivities", len(sensitivities))
+		return
+	}
+	if sensitivities[0] <= 0 || sensitivities[2] <= 0 {
+		t.Error("Enabled genes should affect outputs", sensitivities)
+	}
+	if sensitivities[1] != 0 {
+		t.Error("Disabled gene should not affect outputs", sensitivities[1])
+	}
+	// the weights should be restored
+	if gnome.Phenotype.Outputs[0].Incoming[0].Weight != gnome.Genes[0].Link.Weight {
+		t.Error("The phenotype link weight was not restored")
+	}
+}
+
+func TestGenome_safeMutationScales(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	// with zero weights the output doesn't depend on inputs and its sensitivity to the bias link weight is maximal
+	for _, gn := range gnome.Genes {
+		gn.Link.Weight = 0
+	}
+	scales, err := gnome.safeMutationScales(10, utils.GlobalRand())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, s := range scales {
+		if s <= 0 || s > 1.0 {
+			t.Error("Scale out of range", i, s)
+		}
+	}
+	// the bias link is the most sensitive one
+	if scales[2] >= 1.0 || scales[2] > scales[0] || scales[2] > scales[1] {
+		t.Error("The bias link perturbation should be scaled down", scales)
+	}
+}
+
+func TestGenome_mutateLinkWeightsForContext_safeMutation(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{WeightMutPower:2.5, SafeMutation:true}
+	gnome := buildTestGenome(1)
+	if res, err := gnome.mutateLinkWeightsForContext(&conf); !res || err != nil {
+		t.Error("Failed to mutate link weights", err)
+	}
+
+	// the outputs are not reachable - fallback to unscaled perturbations
+	for _, gn := range gnome.Genes {
+		gn.IsEnabled = false
+	}
+	if res, err := gnome.mutateLinkWeightsForContext(&conf); !res || err != nil {
+		t.Error("Failed to mutate link weights", err)
+	}
+}
+
+func TestGenome_mutateLinkWeightsScaled(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	weights := make([]float64, len(gnome.Genes))
+	for i, gn := range gnome.Genes {
+		weights[i] = gn.Link.Weight
+	}
+	// the zero scales should keep weights intact
+	scales := make([]float64, len(gnome.Genes))
+	if _, err := gnome.mutateLinkWeightsScaled(2.5, 1.0, gaussianMutator, UniformWeightPerturbation, scales, utils.GlobalRand()); err != nil {
+		t.Error(err)
+		return
+	}
+	for i, gn := range gnome.Genes {
+		if gn.Link.Weight != weights[i] {
+			t.Error("Weight should not change", weights[i], gn.Link.Weight)
+		}
+	}
+}
+
+func TestScaledWeightReplacement(t *testing.T) {
+	if w := scaledWeightReplacement(1.0, 3.0, 1.0); w != 3.0 {
+		t.Error("Weight should be replaced", w)
+	}
+	if w := scaledWeightReplacement(1.0, 3.0, 0.5); w != 2.0 {
+		t.Error("Weight should be moved half of the way", w)
+	}
+}
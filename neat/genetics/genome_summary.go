@@ -0,0 +1,96 @@
+package genetics
+
+import (
+	"github.com/yaricom/goNEAT/neat/network"
+	"fmt"
+	"math"
+)
+
+// The structured statistics about genome's composition
+type GenomeSummary struct {
+	// The genome ID
+	Id             int
+	// The number of input (sensor) nodes
+	Inputs         int
+	// The number of bias nodes
+	Biases         int
+	// The number of output nodes
+	Outputs        int
+	// The number of hidden nodes
+	Hidden         int
+	// The number of enabled link genes
+	EnabledLinks   int
+	// The number of disabled link genes
+	DisabledLinks  int
+	// The number of recurrent link genes (both enabled and disabled)
+	RecurrentLinks int
+	// The number of MIMO control genes
+	ControlGenes   int
+	// The number of traits
+	Traits         int
+
+	// The weights distribution statistics collected over enabled link genes
+	MinWeight      float64
+	MaxWeight      float64
+	MeanWeight     float64
+	WeightStdev    float64
+}
+
+// Returns structured statistics about this genome's composition
+func (g *Genome) Summary() *GenomeSummary {
+	s := GenomeSummary{
+		Id:g.Id,
+		ControlGenes:len(g.ControlGenes),
+		Traits:len(g.Traits),
+	}
+	for _, n := range g.Nodes {
+		switch n.NeuronType {
+		case network.InputNeuron:
+			s.Inputs++
+		case network.BiasNeuron:
+			s.Biases++
+		case network.OutputNeuron:
+			s.Outputs++
+		case network.HiddenNeuron:
+			s.Hidden++
+		}
+	}
+
+	sum, sum_sq := 0.0, 0.0
+	for _, gn := range g.Genes {
+		if gn.Link.IsRecurrent {
+			s.RecurrentLinks++
+		}
+		if !gn.IsEnabled {
+			s.DisabledLinks++
+			continue
+		}
+		w := gn.Link.Weight
+		if s.EnabledLinks == 0 || w < s.MinWeight {
+			s.MinWeight = w
+		}
+		if s.EnabledLinks == 0 || w > s.MaxWeight {
+			s.MaxWeight = w
+		}
+		s.EnabledLinks++
+		sum += w
+		sum_sq += w * w
+	}
+	if s.EnabledLinks > 0 {
+		n := float64(s.EnabledLinks)
+		s.MeanWeight = sum / n
+		s.WeightStdev = math.Sqrt(math.Max(sum_sq / n - s.MeanWeight * s.MeanWeight, 0.0))
+	}
+	return &s
+}
+
+// Stringer
+func (s *GenomeSummary) String() string {
+	str := fmt.Sprintf("Genome #%d: inputs: %d, bias: %d, outputs: %d, hidden: %d\n",
+		s.Id, s.Inputs, s.Biases, s.Outputs, s.Hidden)
+	str += fmt.Sprintf("Links: enabled: %d, disabled: %d, recurrent: %d, control genes: %d, traits: %d\n",
+		s.EnabledLinks, s.DisabledLinks, s.RecurrentLinks, s.ControlGenes, s.Traits)
+	str += fmt.Sprintf("Weights: min: %.3f, max: %.3f, mean: %.3f, stdev: %.3f",
+		s.MinWeight, s.MaxWeight, s.MeanWeight, s.WeightStdev)
+	return str
+}
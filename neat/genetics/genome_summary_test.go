@@ -0,0 +1,85 @@
+package genetics
+
+import (
+	"testing"
+	"math"
+	"strings"
+)
+
+func TestGenome_Summary(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes[1].IsEnabled = false
+	gnome.Genes[2].Link.IsRecurrent = true
+
+	s := gnome.Summary()
+	if s.Id != 1 {
+		t.Error("s.Id", 1, s.Id)
+	}
+	if s.Inputs != 2 {
+		t.Error("s.Inputs", 2, s.Inputs)
+	}
+	if s.Biases != 1 {
+		t.Error("s.Biases", 1, s.Biases)
+	}
+	if s.Outputs != 1 {
+		t.Error("s.Outputs", 1, s.Outputs)
+	}
+	if s.Hidden != 0 {
+		t.Error("s.Hidden", 0, s.Hidden)
+	}
+	if s.EnabledLinks != 2 {
+		t.Error("s.EnabledLinks", 2, s.EnabledLinks)
+	}
+	if s.DisabledLinks != 1 {
+		t.Error("s.DisabledLinks", 1, s.DisabledLinks)
+	}
+	if s.RecurrentLinks != 1 {
+		t.Error("s.RecurrentLinks", 1, s.RecurrentLinks)
+	}
+	if s.Traits != 3 {
+		t.Error("s.Traits", 3, s.Traits)
+	}
+	if s.MinWeight != 1.5 {
+		t.Error("s.MinWeight", 1.5, s.MinWeight)
+	}
+	if s.MaxWeight != 3.5 {
+		t.Error("s.MaxWeight", 3.5, s.MaxWeight)
+	}
+	if s.MeanWeight != 2.5 {
+		t.Error("s.MeanWeight", 2.5, s.MeanWeight)
+	}
+	if math.Abs(s.WeightStdev - 1.0) > 1e-9 {
+		t.Error("s.WeightStdev", 1.0, s.WeightStdev)
+	}
+}
+
+func TestGenome_Summary_modular(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+
+	s := gnome.Summary()
+	if s.Hidden != 3 {
+		t.Error("s.Hidden", 3, s.Hidden)
+	}
+	if s.EnabledLinks != 6 {
+		t.Error("s.EnabledLinks", 6, s.EnabledLinks)
+	}
+	if s.ControlGenes != len(gnome.ControlGenes) {
+		t.Error("s.ControlGenes", len(gnome.ControlGenes), s.ControlGenes)
+	}
+}
+
+func TestGenome_String(t *testing.T) {
+	gnome := buildTestGenome(1)
+
+	str := gnome.String()
+	if !strings.HasPrefix(str, "GENOME START") || !strings.HasSuffix(str, "GENOME END") {
+		t.Error("Wrong genome string boundaries", str)
+	}
+	if !strings.Contains(str, "Genome #1: inputs: 2, bias: 1, outputs: 1, hidden: 0") {
+		t.Error("Genome summary not found", str)
+	}
+	// check that each gene printed in its own row
+	if !strings.Contains(str, "2 -> 4") || strings.Count(str, " -> ") != len(gnome.Genes) {
+		t.Error("Genes table is wrong", str)
+	}
+}
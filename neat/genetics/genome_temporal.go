@@ -0,0 +1,121 @@
+package genetics
+
+import (
+	"math"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Makes gating nodes of given genes refer the nodes with the same IDs among provided ones. The gate is removed if
+// there is no such node, e.g. the gating node was not inherited by offspring.
+func resolveGateNodes(genes []*Gene, nodes []*network.NNode) {
+	for _, gn := range genes {
+		if gate := gn.Link.GateNode; gate != nil {
+			gn.Link.GateNode = nodeWithId(gate.Id, nodes)
+		}
+	}
+}
+
+// Changes the delay of random enabled link by one activation step keeping it within [0, max_delay] range. Returns
+// false if links can not be delayed, i.e. max_delay is not positive, or delay was not changed.
+func (g *Genome) mutateLinkDelay(max_delay int, rng *rand.Rand) (bool, error) {
+	gene := g.randomMutableGene(rng)
+	if max_delay <= 0 || gene == nil {
+		return false, nil
+	}
+	delay := gene.Link.Delay + 1
+	if rng.Float64() < 0.5 {
+		delay = gene.Link.Delay - 1
+	}
+	if delay < 0 || delay > max_delay {
+		return false, nil
+	}
+	gene.Link.Delay = delay
+	g.invalidatePhenotype()
+	return true, nil
+}
+
+// Removes the gate of random enabled link if it's gated, otherwise makes random non-bias node of genome the gating
+// node of the link. The new gates are not added when feed-forward only evolution requested, because gating node can
+// be activated after the gated link. Returns true if genome was mutated.
+func (g *Genome) mutateLinkGate(context *neat.NeatContext) (bool, error) {
+	gene := g.randomMutableGene(context.Rand())
+	if gene == nil {
+		return false, nil
+	}
+	if gene.Link.GateNode != nil {
+		gene.Link.GateNode = nil
+		g.invalidatePhenotype()
+		return true, nil
+	}
+	if context.FeedForwardOnly {
+		return false, nil
+	}
+	candidates := make([]*network.NNode, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if node.NeuronType != network.BiasNeuron {
+			candidates = append(candidates, node)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+	gene.Link.GateNode = candidates[context.Rand().Intn(len(candidates))]
+	g.invalidatePhenotype()
+	return true, nil
+}
+
+// Turns random hidden neuron of genome into memory cell with random forget gate or memory cell back into ordinary
+// neuron. Returns false if genome has no hidden neurons.
+func (g *Genome) mutateMemoryNode(rng *rand.Rand) (bool, error) {
+	hidden := make([]*network.NNode, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if node.NeuronType == network.HiddenNeuron {
+			hidden = append(hidden, node)
+		}
+	}
+	if len(hidden) == 0 {
+		return false, nil
+	}
+	node := hidden[rng.Intn(len(hidden))]
+	if node.IsMemory {
+		node.IsMemory, node.MemoryRetention = false, 0.0
+	} else {
+		node.IsMemory, node.MemoryRetention = true, rng.Float64()
+	}
+	g.invalidatePhenotype()
+	return true, nil
+}
+
+// Perturbs forget gates of memory cells keeping them within [0, 1] range, each gate is changed with probability 0.5.
+// Returns false if genome has no memory cells or nothing was changed.
+func (g *Genome) mutateMemoryRetention(power float64, rng *rand.Rand) (bool, error) {
+	mutated := false
+	for _, node := range g.Nodes {
+		if node.IsMemory && rng.Float64() < 0.5 {
+			retention := node.MemoryRetention + float64(utils.RandSignWith(rng)) * rng.Float64() * power
+			node.MemoryRetention = math.Max(0.0, math.Min(1.0, retention))
+			mutated = true
+		}
+	}
+	if mutated {
+		g.invalidatePhenotype()
+	}
+	return mutated, nil
+}
+
+// Returns random enabled gene of this genome which is not frozen or nil if there are no such genes
+func (g *Genome) randomMutableGene(rng *rand.Rand) *Gene {
+	enabled := make([]*Gene, 0, len(g.Genes))
+	for _, gn := range g.Genes {
+		if gn.IsEnabled && !gn.IsFrozen {
+			enabled = append(enabled, gn)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+	return enabled[rng.Intn(len(enabled))]
+}
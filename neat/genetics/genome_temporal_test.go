@@ -0,0 +1,210 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Creates test genome with the first link delayed and gated by the second input
+func buildTestTemporalGenome(id int) *Genome {
+	gnome := buildTestGenome(id)
+	gnome.Genes[0].Link.Delay = 2
+	gnome.Genes[0].Link.GateNode = gnome.Nodes[1]
+	return gnome
+}
+
+func TestNewGenome_resolveGateNodes(t *testing.T) {
+	gnome := buildTestTemporalGenome(1)
+	dup, err := gnome.cloneWithId(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	link := dup.Genes[0].Link
+	if link.Delay != 2 {
+		t.Error("Delay should be copied", link.Delay)
+	}
+	if link.GateNode != dup.Nodes[1] {
+		t.Error("Gating node should refer node of clone", link.GateNode)
+	}
+
+	// the gate is removed if gating node is missing
+	genes := []*Gene{NewGeneCopy(gnome.Genes[0], gnome.Traits[0], gnome.Nodes[0], gnome.Nodes[3])}
+	other := NewGenome(3, gnome.Traits, []*network.NNode{gnome.Nodes[0], gnome.Nodes[3]}, genes)
+	if other.Genes[0].Link.GateNode != nil {
+		t.Error("Gate expected to be removed", other.Genes[0].Link.GateNode)
+	}
+	if _, err = other.verify(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenome_Genesis_temporal(t *testing.T) {
+	gnome := buildTestTemporalGenome(1)
+	net, err := gnome.Genesis(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	link := net.Outputs[0].Incoming[0]
+	if link.Delay != 2 {
+		t.Error("Delay should be inherited by phenotype", link.Delay)
+	}
+	if link.GateNode != net.AllNodes()[1] {
+		t.Error("Gating node should be node of phenotype", link.GateNode)
+	}
+
+	// the gating node which is not in genome
+	gnome.Genes[0].Link.GateNode = network.NewNNode(10, network.HiddenNeuron)
+	if _, err = gnome.verify(); err == nil {
+		t.Error("Error expected for missing gating node")
+	}
+	if _, err = gnome.Genesis(2); err == nil {
+		t.Error("Error expected for missing gating node")
+	}
+}
+
+func TestGenome_mutateLinkDelay(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	if res, err := gnome.mutateLinkDelay(0, utils.GlobalRand()); res || err != nil {
+		t.Error("Links can not be delayed", res, err)
+	}
+	mutated := false
+	for i := 0; i < 50; i++ {
+		res, err := gnome.mutateLinkDelay(2, utils.GlobalRand())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mutated = mutated || res
+	}
+	if !mutated {
+		t.Error("Link delay should be mutated")
+	}
+	for _, gn := range gnome.Genes {
+		if gn.Link.Delay < 0 || gn.Link.Delay > 2 {
+			t.Error("Delay is out of range", gn.Link.Delay)
+		}
+	}
+
+	// the disabled links are not mutated
+	for _, gn := range gnome.Genes {
+		gn.IsEnabled = false
+	}
+	if res, err := gnome.mutateLinkDelay(2, utils.GlobalRand()); res || err != nil {
+		t.Error("No enabled links to mutate", res, err)
+	}
+}
+
+func TestGenome_mutateLinkGate(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	context := neat.NeatContext{}
+	res, err := gnome.mutateLinkGate(&context)
+	if !res || err != nil {
+		t.Error("Link gate should be added", res, err)
+		return
+	}
+	gated := 0
+	for _, gn := range gnome.Genes {
+		if gate := gn.Link.GateNode; gate != nil {
+			gated++
+			if gate.NeuronType == network.BiasNeuron {
+				t.Error("Bias should not gate link")
+			}
+			gn.Link.GateNode = nil
+		}
+	}
+	if gated != 1 {
+		t.Error("One gated link expected", gated)
+	}
+
+	// the gates are only removed in feed-forward networks
+	context.FeedForwardOnly = true
+	if res, err = gnome.mutateLinkGate(&context); res || err != nil {
+		t.Error("Link gate should not be added", res, err)
+	}
+	for _, gn := range gnome.Genes {
+		gn.Link.GateNode = gnome.Nodes[0]
+	}
+	if res, err = gnome.mutateLinkGate(&context); !res || err != nil {
+		t.Error("Link gate should be removed", res, err)
+	}
+}
+
+func TestGenome_mutateMemoryNode(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	if res, err := gnome.mutateMemoryNode(utils.GlobalRand()); res || err != nil {
+		t.Error("Genome has no hidden neurons", res, err)
+	}
+	gnome = buildTestModularGenome(1)
+	res, err := gnome.mutateMemoryNode(utils.GlobalRand())
+	if !res || err != nil {
+		t.Error("Memory cell should be added", res, err)
+		return
+	}
+	var memory *network.NNode
+	for _, node := range gnome.Nodes {
+		if node.IsMemory {
+			if memory != nil || node.NeuronType != network.HiddenNeuron {
+				t.Error("Only one hidden memory cell expected", node)
+			}
+			memory = node
+		}
+	}
+	if memory == nil || memory.MemoryRetention < 0.0 || memory.MemoryRetention > 1.0 {
+		t.Error("Wrong memory cell", memory)
+		return
+	}
+
+	// the memory cell turns back into neuron
+	for i := 0; i < 50 && memory.IsMemory; i++ {
+		if _, err = gnome.mutateMemoryNode(utils.GlobalRand()); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	if memory.IsMemory || memory.MemoryRetention != 0.0 {
+		t.Error("Memory cell should be turned into neuron", memory.MemoryRetention)
+	}
+}
+
+func TestGenome_mutateMemoryRetention(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestModularGenome(1)
+	if res, err := gnome.mutateMemoryRetention(1.0, utils.GlobalRand()); res || err != nil {
+		t.Error("Genome has no memory cells", res, err)
+	}
+	memory := gnome.Nodes[4]
+	memory.IsMemory, memory.MemoryRetention = true, 0.5
+	mutated := false
+	for i := 0; i < 20; i++ {
+		res, err := gnome.mutateMemoryRetention(2.0, utils.GlobalRand())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mutated = mutated || res
+		if memory.MemoryRetention < 0.0 || memory.MemoryRetention > 1.0 {
+			t.Error("Forget gate is out of range", memory.MemoryRetention)
+		}
+	}
+	if !mutated {
+		t.Error("Forget gate should be mutated")
+	}
+
+	// the memory cell is inherited by phenotype
+	net, err := gnome.Genesis(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if node := net.AllNodes()[4]; !node.IsMemory || node.MemoryRetention != memory.MemoryRetention {
+		t.Error("Memory cell should be inherited by phenotype", node)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/yaricom/goNEAT/neat/network"
 	"github.com/yaricom/goNEAT/neat"
 	"math/rand"
+	"math"
 	"github.com/yaricom/goNEAT/neat/utils"
 )
 
@@ -103,6 +104,147 @@ func TestGenome_NewGenomeRand(t *testing.T) {
 	//}
 }
 
+func TestGenome_NewGenomeFullyConnected(t *testing.T) {
+	rand.Seed(42)
+	in, out, hidden := 3, 2, 4
+	gnome, err := NewGenomeFullyConnected(in, out, hidden)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(gnome.Nodes) != in + 1 + out + hidden {
+		t.Error("len(gnome.Nodes)", in + 1 + out + hidden, len(gnome.Nodes))
+	}
+	if len(gnome.Genes) != (in + 1) * hidden + hidden * out {
+		t.Error("len(gnome.Genes)", (in + 1) * hidden + hidden * out, len(gnome.Genes))
+	}
+	if gnome.Nodes[in].NeuronType != network.BiasNeuron {
+		t.Error("Bias node expected after inputs", gnome.Nodes[in])
+	}
+	if res, err := gnome.verify(); !res || err != nil {
+		t.Error("Genome verification failed", err)
+	}
+	if _, err = gnome.Genesis(gnome.Id); err != nil {
+		t.Error(err)
+	}
+
+	// without hidden
+	gnome, err = NewGenomeFullyConnected(in, out, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(gnome.Genes) != (in + 1) * out {
+		t.Error("len(gnome.Genes)", (in + 1) * out, len(gnome.Genes))
+	}
+
+	if _, err = NewGenomeFullyConnected(in, out, -1); err == nil {
+		t.Error("Error expected for negative number of hidden nodes")
+	}
+}
+
+func TestGenome_NewGenomeSparse(t *testing.T) {
+	rand.Seed(42)
+	in, out := 100, 5
+	gnome, err := NewGenomeSparse(in, out, 0.1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(gnome.Nodes) != in + 1 + out {
+		t.Error("len(gnome.Nodes)", in + 1 + out, len(gnome.Nodes))
+	}
+	if len(gnome.Genes) == 0 || len(gnome.Genes) >= (in + 1) * out / 2 {
+		t.Error("Wrong number of sparse genes", len(gnome.Genes))
+	}
+	if res, err := gnome.verify(); !res || err != nil {
+		t.Error("Genome verification failed", err)
+	}
+
+	// each output should be connected even with low density
+	gnome, err = NewGenomeSparse(2, out, 0.0001)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, n := range gnome.Nodes {
+		if n.NeuronType == network.OutputNeuron {
+			connected := false
+			for _, g := range gnome.Genes {
+				if g.Link.OutNode == n {
+					connected = true
+				}
+			}
+			if !connected {
+				t.Error("Output is not connected", n)
+			}
+		}
+	}
+
+	if _, err = NewGenomeSparse(in, out, 0); err == nil {
+		t.Error("Error expected for zero density")
+	}
+}
+
+func TestGenome_NewGenomeSparseWith(t *testing.T) {
+	gnome, err := NewGenomeSparseWith(rand.New(rand.NewSource(42)), 10, 3, 0.3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	other, err := NewGenomeSparseWith(rand.New(rand.NewSource(42)), 10, 3, 0.3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the same stream should produce the same links and weights
+	if equal, err := gnome.IsEqual(other); !equal {
+		t.Error("Genomes created with the same random stream are not equal", err)
+	}
+}
+
+func TestGenome_NewGenomeLayered(t *testing.T) {
+	rand.Seed(42)
+	gnome, err := NewGenomeLayered([]int{4, 3, 2, 1})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(gnome.Nodes) != 5 + 3 + 2 + 1 {
+		t.Error("len(gnome.Nodes)", 11, len(gnome.Nodes))
+	}
+	if len(gnome.Genes) != 5 * 3 + 3 * 2 + 2 * 1 {
+		t.Error("len(gnome.Genes)", 5 * 3 + 3 * 2 + 2 * 1, len(gnome.Genes))
+	}
+	// the output follows inputs and bias
+	if gnome.Nodes[5].NeuronType != network.OutputNeuron {
+		t.Error("Output node expected after bias", gnome.Nodes[5])
+	}
+	for i, g := range gnome.Genes {
+		if g.InnovationNum != int64(i + 1) {
+			t.Error("Wrong innovation number", i + 1, g.InnovationNum)
+		}
+	}
+	if res, err := gnome.verify(); !res || err != nil {
+		t.Error("Genome verification failed", err)
+	}
+	net, err := gnome.Genesis(gnome.Id)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if depth, err := net.MaxDepth(); err != nil || depth != 3 {
+		t.Error("Wrong network depth", depth, err)
+	}
+
+	if _, err = NewGenomeLayered([]int{4}); err == nil {
+		t.Error("Error expected for single layer")
+	}
+	if _, err = NewGenomeLayered([]int{4, 0, 1}); err == nil {
+		t.Error("Error expected for empty layer")
+	}
+}
+
 // Test genesis
 func TestGenome_Genesis(t *testing.T) {
 	gnome := buildTestGenome(1)
@@ -168,7 +310,7 @@ func TestGenome_GenesisModular(t *testing.T) {
 func TestGenome_Duplicate(t *testing.T) {
 	gnome := buildTestGenome(1)
 
-	new_gnome, err := gnome.duplicate(2)
+	new_gnome, err := gnome.cloneWithId(2)
 	if err != nil {
 		t.Error(err)
 		return
@@ -196,7 +338,7 @@ func TestGenome_Duplicate(t *testing.T) {
 func TestGenome_DuplicateModular(t *testing.T) {
 	gnome := buildTestModularGenome(1)
 
-	new_gnome, err := gnome.duplicate(2)
+	new_gnome, err := gnome.cloneWithId(2)
 	if err != nil {
 		t.Error(err)
 		return
@@ -367,10 +509,117 @@ func TestGenome_Compatibility_Fast(t *testing.T) {
 	}
 }
 
+// Collects pointers to all mutable parts of genome
+func genomePointers(g *Genome) map[interface{}]bool {
+	ptrs := make(map[interface{}]bool)
+	add_params := func(params []float64) {
+		if len(params) > 0 {
+			ptrs[&params[0]] = true
+		}
+	}
+	for _, tr := range g.Traits {
+		ptrs[tr] = true
+		add_params(tr.Params)
+	}
+	for _, n := range g.Nodes {
+		ptrs[n] = true
+		add_params(n.Params)
+	}
+	for _, gn := range g.Genes {
+		ptrs[gn] = true
+		ptrs[gn.Link] = true
+		add_params(gn.Link.Params)
+	}
+	for _, cg := range g.ControlGenes {
+		ptrs[cg] = true
+		ptrs[cg.ControlNode] = true
+		for _, l := range append(append([]*network.Link{}, cg.ControlNode.Incoming...), cg.ControlNode.Outgoing...) {
+			ptrs[l] = true
+			add_params(l.Params)
+		}
+	}
+	return ptrs
+}
+
+func TestGenome_Clone(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	gnome.Nodes[3].Params = []float64{0.5, 1.5}
+	c_link := gnome.ControlGenes[0].ControlNode.Incoming[0]
+	c_link.Trait, c_link.Params = gnome.Traits[1], append([]float64(nil), gnome.Traits[1].Params...)
+
+	clone, err := gnome.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if clone.Id != gnome.Id {
+		t.Error("The clone should have the same ID", clone.Id)
+	}
+	if equal, err := gnome.IsEqual(clone); !equal {
+		t.Error(err)
+	}
+	if clone.Phenotype != nil {
+		t.Error("The phenotype should not be copied")
+	}
+
+	// the clone should not share any mutable part with original
+	original := genomePointers(gnome)
+	for ptr := range genomePointers(clone) {
+		if original[ptr] {
+			t.Errorf("The clone shares %T with original genome", ptr)
+		}
+	}
+
+	// the clone refers only its own traits and nodes
+	traits := make(map[*neat.Trait]bool)
+	for _, tr := range clone.Traits {
+		traits[tr] = true
+	}
+	nodes := make(map[*network.NNode]bool)
+	for _, n := range clone.Nodes {
+		nodes[n] = true
+		if n.Trait != nil && !traits[n.Trait] {
+			t.Error("The node of clone refers to foreign trait", n.Id)
+		}
+	}
+	for _, gn := range clone.Genes {
+		if !nodes[gn.Link.InNode] || !nodes[gn.Link.OutNode] {
+			t.Error("The gene of clone refers to foreign node", gn)
+		}
+		if gn.Link.Trait != nil && !traits[gn.Link.Trait] {
+			t.Error("The gene of clone refers to foreign trait", gn)
+		}
+	}
+	for _, l := range clone.ControlGenes[0].ControlNode.Incoming {
+		if !nodes[l.InNode] || l.Trait != nil && !traits[l.Trait] {
+			t.Error("The control link of clone refers to foreign node or trait", l)
+		}
+	}
+	if params := clone.Nodes[3].Params; len(params) != 2 || params[1] != 1.5 {
+		t.Error("The node parameters should be copied", params)
+	}
+
+	// the changes of clone should not affect original
+	clone.Traits[0].Params[0] = 100.0
+	clone.Nodes[3].Params[0] = 100.0
+	clone.Genes[0].Link.Weight = 100.0
+	clone.Genes[0].Link.Params[0] = 100.0
+	clone.ControlGenes[0].ControlNode.Incoming[0].Weight = 100.0
+	clone.Genes = append(clone.Genes[:1], clone.Genes[2:]...)
+	if gnome.Traits[0].Params[0] == 100.0 || gnome.Nodes[3].Params[0] == 100.0 ||
+		gnome.Genes[0].Link.Weight == 100.0 || gnome.Genes[0].Link.Params[0] == 100.0 ||
+		gnome.ControlGenes[0].ControlNode.Incoming[0].Weight == 100.0 {
+		t.Error("The changes of clone affected original genome")
+	}
+	if len(gnome.Genes) != 6 || gnome.Genes[1].InnovationNum != 2 {
+		t.Error("The genes of original genome changed", len(gnome.Genes))
+	}
+}
+
 func TestGenome_Compatibility_Duplicate(t *testing.T) {
 	rand.Seed(42)
 	gnome1 := buildTestGenome(1)
-	gnome2, err := gnome1.duplicate(2)
+	gnome2, err := gnome1.cloneWithId(2)
 	if err != nil {
 		t.Error(err)
 		return
@@ -547,7 +796,7 @@ func TestGenome_mutateLinkWeights(t *testing.T) {
 		WeightMutPower:0.5,
 	}
 
-	res, err := gnome1.mutateLinkWeights(conf.WeightMutPower, 1.0, gaussianMutator)
+	res, err := gnome1.mutateLinkWeights(conf.WeightMutPower, 1.0, gaussianMutator, UniformWeightPerturbation, utils.GlobalRand())
 	if !res || err != nil {
 		t.Error("Failed to mutate link weights")
 	}
@@ -558,6 +807,223 @@ func TestGenome_mutateLinkWeights(t *testing.T) {
 	}
 }
 
+func TestGenome_mutateLinkWeights_perturbations(t *testing.T) {
+	rand.Seed(42)
+	for _, perturbation := range []WeightPerturbationType{UniformWeightPerturbation, GaussianWeightPerturbation,
+		CauchyWeightPerturbation, LaplaceWeightPerturbation, AdaptiveWeightPerturbation} {
+		gnome := buildTestGenome(1)
+		// cold gaussian mutator replaces all weights
+		res, err := gnome.mutateLinkWeights(0.5, 1.0, goldGaussianMutator, perturbation, utils.GlobalRand())
+		if !res || err != nil {
+			t.Error("Failed to mutate link weights", perturbation, err)
+			continue
+		}
+		for i, gn := range gnome.Genes {
+			if gn.Link.Weight == float64(i) + 1.5 || math.IsNaN(gn.Link.Weight) || math.IsInf(gn.Link.Weight, 0) {
+				t.Error("Wrong mutated weight", perturbation, gn)
+			}
+			if gn.MutationNum != gn.Link.Weight {
+				t.Error("Mutation number should follow weight", perturbation, gn)
+			}
+			if (perturbation == AdaptiveWeightPerturbation) != (gn.mutationSigma > 0) {
+				t.Error("Wrong self-adapted sigma", perturbation, gn.mutationSigma)
+			}
+		}
+	}
+
+	gnome := buildTestGenome(1)
+	if _, err := gnome.mutateLinkWeights(0.5, 1.0, gaussianMutator, WeightPerturbationType(10), utils.GlobalRand()); err == nil {
+		t.Error("Error expected for unsupported perturbation type")
+	}
+}
+
+func TestWeightPerturbation_distributions(t *testing.T) {
+	rand.Seed(42)
+	samples := 20000
+	// the fraction of large perturbations should be ordered by tail heaviness
+	tail := func(perturbation WeightPerturbationType) float64 {
+		count := 0
+		gene := &Gene{}
+		for i := 0; i < samples; i++ {
+			if math.Abs(weightPerturbation(gene, 1.0, perturbation, utils.GlobalRand())) > 3.0 {
+				count++
+			}
+		}
+		return float64(count) / float64(samples)
+	}
+	uniform, gaussian := tail(UniformWeightPerturbation), tail(GaussianWeightPerturbation)
+	laplace, cauchy := tail(LaplaceWeightPerturbation), tail(CauchyWeightPerturbation)
+	if uniform != 0 {
+		t.Error("Uniform perturbation out of power range", uniform)
+	}
+	if !(gaussian < laplace && laplace < cauchy) {
+		t.Error("Wrong tails order", gaussian, laplace, cauchy)
+	}
+}
+
+func TestGenome_boundLinkWeights(t *testing.T) {
+	// no bounds configured
+	gnome := buildTestGenome(1)
+	conf := neat.NeatContext{}
+	if changed, err := gnome.boundLinkWeights(&conf); changed || err != nil {
+		t.Error("Weights should not be bounded", changed, err)
+	}
+
+	// clamp weights: 1.5, 2.5, 3.5
+	conf = neat.NeatContext{WeightMin:-2.0, WeightMax:2.0, WeightBoundType:int(WeightBoundClamp)}
+	if changed, err := gnome.boundLinkWeights(&conf); !changed || err != nil {
+		t.Error("Weights should be clamped", changed, err)
+	}
+	for i, expected := range []float64{1.5, 2.0, 2.0} {
+		if gnome.Genes[i].Link.Weight != expected {
+			t.Error("Wrong clamped weight at", i, gnome.Genes[i].Link.Weight)
+		}
+	}
+
+	// bounce back weights: 1.5, 2.5, 3.5, -2.5, 7.0
+	gnome = buildTestGenome(1)
+	gnome.Genes = append(gnome.Genes,
+		newGene(network.NewLink(-2.5, gnome.Nodes[0], gnome.Nodes[3], true), 4, 0, true),
+		newGene(network.NewLink(7.0, gnome.Nodes[1], gnome.Nodes[3], true), 5, 0, true))
+	conf.WeightBoundType = int(WeightBoundBounce)
+	if changed, err := gnome.boundLinkWeights(&conf); !changed || err != nil {
+		t.Error("Weights should be bounced", changed, err)
+	}
+	for i, expected := range []float64{1.5, 1.5, 0.5, -1.5, -2.0} {
+		if gnome.Genes[i].Link.Weight != expected {
+			t.Error("Wrong bounced weight at", i, gnome.Genes[i].Link.Weight)
+		}
+		if i > 0 && gnome.Genes[i].MutationNum != expected {
+			// mutation number of bounced genes should follow weight
+			t.Error("Wrong mutation number at", i, gnome.Genes[i].MutationNum)
+		}
+	}
+
+	// unsupported bound type
+	gnome = buildTestGenome(1)
+	conf.WeightBoundType = 10
+	if _, err := gnome.boundLinkWeights(&conf); err == nil {
+		t.Error("Error expected for unsupported bound type")
+	}
+}
+
+func TestPopulation_spawn_boundLinkWeights(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{PopSize:10, CompatThreshold:0.5, WeightMin:-0.5, WeightMax:0.5}
+	pop, err := NewPopulation(buildTestGenome(1), &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, org := range pop.Organisms {
+		for _, g := range org.Genotype.Genes {
+			if g.Link.Weight < conf.WeightMin || g.Link.Weight > conf.WeightMax {
+				t.Error("Weight out of bounds", g.Link.Weight)
+			}
+		}
+	}
+}
+
+func TestGenome_IsFeedForward(t *testing.T) {
+	gnome := buildTestGenome(1)
+	if !gnome.IsFeedForward() {
+		t.Error("Genome is feed-forward")
+	}
+
+	// add hidden node with loop: 1 -> 5 -> 4 -> 5
+	node := &network.NNode{Id:5, NeuronType: network.HiddenNeuron, ActivationType: utils.SigmoidSteepenedActivation}
+	gnome.Nodes = append(gnome.Nodes, node)
+	gnome.Genes = append(gnome.Genes,
+		newGene(network.NewLink(1.0, gnome.Nodes[0], node, false), 4, 0, true),
+		newGene(network.NewLink(1.0, node, gnome.Nodes[3], false), 5, 0, true))
+	if !gnome.IsFeedForward() {
+		t.Error("Genome is still feed-forward")
+	}
+	loop := newGene(network.NewLink(1.0, gnome.Nodes[3], node, false), 6, 0, true)
+	gnome.Genes = append(gnome.Genes, loop)
+	if gnome.IsFeedForward() {
+		t.Error("Genome has loop")
+	}
+
+	// disabled genes are ignored
+	loop.IsEnabled = false
+	if !gnome.IsFeedForward() {
+		t.Error("Genome loop is disabled")
+	}
+
+	// recurrent link
+	gnome = buildTestGenome(1)
+	gnome.Genes = append(gnome.Genes, newGene(network.NewLink(1.0, gnome.Nodes[3], gnome.Nodes[3], true), 4, 0, true))
+	if gnome.IsFeedForward() {
+		t.Error("Genome has recurrent link")
+	}
+}
+
+func TestGenome_disableRecurrentGenes(t *testing.T) {
+	gnome := buildTestGenome(1)
+	node := &network.NNode{Id:5, NeuronType: network.HiddenNeuron, ActivationType: utils.SigmoidSteepenedActivation}
+	gnome.Nodes = append(gnome.Nodes, node)
+	gnome.Genes = append(gnome.Genes,
+		newGene(network.NewLink(1.0, gnome.Nodes[0], node, false), 4, 0, true),
+		newGene(network.NewLink(1.0, node, gnome.Nodes[3], false), 5, 0, true),
+		newGene(network.NewLink(1.0, gnome.Nodes[3], node, false), 6, 0, true),
+		newGene(network.NewLink(1.0, node, node, true), 7, 0, true))
+
+	if disabled := gnome.disableRecurrentGenes(); disabled != 2 {
+		t.Error("disabled != 2", disabled)
+	}
+	for i, gene := range gnome.Genes {
+		if gene.IsEnabled != (i < 5) {
+			t.Error("Wrong gene enabled state at", i, gene.IsEnabled)
+		}
+	}
+	if !gnome.IsFeedForward() {
+		t.Error("Genome must be feed-forward")
+	}
+	phenotype, err := gnome.Genesis(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if phenotype.IsRecurrent() {
+		t.Error("Phenotype must be feed-forward")
+	}
+}
+
+func TestGenome_mutateAddLink_feedForwardOnly(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	conf := neat.NeatContext{
+		RecurOnlyProb:1.0,
+		NewLinkTries:10,
+		FeedForwardOnly:true,
+	}
+	pop := newPopulation()
+	pop.nextInnovNum = int64(4)
+	gnome.Genesis(1)
+
+	// the only NEURON node already connected to all sensors and self loop is recurrent
+	res, err := gnome.mutateAddLink(pop, &conf)
+	if err != nil {
+		t.Error(err)
+	}
+	if res {
+		t.Error("Recurrent link added", gnome.Genes[len(gnome.Genes) - 1])
+	}
+}
+
+func TestPopulation_spawn_feedForwardOnly(t *testing.T) {
+	conf := neat.NeatContext{PopSize:10, CompatThreshold:0.5, FeedForwardOnly:true}
+	gnome := buildTestGenome(1)
+	gnome.Genes = append(gnome.Genes, newGene(network.NewLink(1.0, gnome.Nodes[3], gnome.Nodes[3], true), 4, 0, true))
+	if _, err := NewPopulation(gnome, &conf); err == nil {
+		t.Error("Error expected for recurrent start genome")
+	}
+	if _, err := NewPopulationRandom(3, 2, 5, true, 0.5, &conf); err == nil {
+		t.Error("Error expected for random recurrent genomes")
+	}
+}
+
 func TestGenome_mutateRandomTrait(t *testing.T) {
 	rand.Seed(42)
 	gnome1 := buildTestGenome(1)
@@ -594,7 +1060,7 @@ func TestGenome_mutateLinkTrait(t *testing.T) {
 	rand.Seed(42)
 	gnome1 := buildTestGenome(1)
 
-	res, err := gnome1.mutateLinkTrait(10)
+	res, err := gnome1.mutateLinkTrait(10, utils.GlobalRand())
 	if !res || err != nil {
 		t.Error("Failed to mutate link trait")
 	}
@@ -622,7 +1088,7 @@ func TestGenome_mutateNodeTrait(t *testing.T) {
 	}
 	gnome1.Nodes[3].Trait = &neat.Trait{Id:4, Params: []float64{0.4, 0, 0, 0, 0, 0, 0, 0}}
 
-	res, err := gnome1.mutateNodeTrait(2)
+	res, err := gnome1.mutateNodeTrait(2, utils.GlobalRand())
 	if !res || err != nil {
 		t.Error("Failed to mutate node trait")
 	}
@@ -644,7 +1110,7 @@ func TestGenome_mutateToggleEnable(t *testing.T) {
 	gene := newGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2], gnome1.Nodes[3], false), 4, 0, true)
 	gnome1.Genes = append(gnome1.Genes, gene)
 
-	res, err := gnome1.mutateToggleEnable(5)
+	res, err := gnome1.mutateToggleEnable(5, utils.GlobalRand())
 	if !res || err != nil {
 		t.Error("Failed to mutate toggle genes")
 	}
@@ -678,6 +1144,186 @@ func TestGenome_mutateGeneReenable(t *testing.T) {
 	}
 }
 
+func TestGenome_mutateInitialActivations(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenome(1)
+	mutated := false
+	for i := 0; i < 10; i++ {
+		res, err := gnome1.mutateInitialActivations(1.0, utils.GlobalRand())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mutated = mutated || res
+	}
+	if !mutated {
+		t.Error("Initial activations should be mutated")
+	}
+	changed := 0
+	for _, node := range gnome1.Nodes {
+		if !node.IsNeuron() && node.InitialActivation != 0 {
+			t.Error("Initial activation of sensor should not be mutated", node.Id)
+		}
+		if node.IsNeuron() && node.InitialActivation != 0 {
+			changed++
+		}
+	}
+	if changed == 0 {
+		t.Error("Initial activation of neurons expected")
+	}
+
+	// the initial activations are inherited by phenotype
+	net, err := gnome1.Genesis(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, node := range net.AllNodes() {
+		if node.InitialActivation != gnome1.Nodes[i].InitialActivation {
+			t.Error("Wrong initial activation of network node", node.Id)
+		}
+	}
+}
+
+func TestGenome_mutateGeneReenable_noDisabled(t *testing.T) {
+	gnome1 := buildTestGenome(1)
+	res, err := gnome1.mutateGeneReenable()
+	if res || err != nil {
+		t.Error("Nothing to re-enable", res, err)
+	}
+}
+
+func TestGenome_mutateToggleEnable_isolated(t *testing.T) {
+	rand.Seed(42)
+	// each gene is the only one going out of its in-node, thus can not be disabled
+	gnome1 := buildTestGenome(1)
+	res, err := gnome1.mutateToggleEnable(5, utils.GlobalRand())
+	if res || err != nil {
+		t.Error("No genes should be toggled", res, err)
+	}
+	for _, gn := range gnome1.Genes {
+		if !gn.IsEnabled {
+			t.Error("Gene should stay enabled", gn)
+		}
+	}
+}
+
+func TestGenome_mutateDeleteLink(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenome(1)
+	hidden := &network.NNode{Id:5, NeuronType: network.HiddenNeuron, ActivationType: utils.SigmoidSteepenedActivation,
+		Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)}
+	gnome1.Nodes = append(gnome1.Nodes, hidden)
+	gene := newGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, hidden, gnome1.Nodes[3], false), 4, 0, true)
+	gnome1.Genes = append(gnome1.Genes, gene)
+
+	for deleted := 0; len(gnome1.Genes) > 1; {
+		res, err := gnome1.mutateDeleteLink(utils.GlobalRand())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !res {
+			t.Error("Failed to delete link")
+			return
+		}
+		deleted++
+		if len(gnome1.Genes) != 4 - deleted {
+			t.Error("Wrong number of genes", len(gnome1.Genes))
+		}
+		// the hidden node should be removed with its last gene
+		has_node := false
+		for _, n := range gnome1.Nodes {
+			if n.Id == hidden.Id {
+				has_node = true
+			}
+		}
+		if has_node != gnome1.hasGenesOf(hidden) {
+			t.Error("Dangling hidden node was not removed", gnome1)
+		}
+	}
+
+	// the last gene should be kept
+	if res, err := gnome1.mutateDeleteLink(utils.GlobalRand()); res || err != nil || len(gnome1.Genes) != 1 {
+		t.Error("The last gene should not be deleted", res, err)
+	}
+}
+
+func TestGenome_mutateDeleteLink_isolated(t *testing.T) {
+	rand.Seed(42)
+	// the single enabled gene going into output can not be deleted
+	gnome1 := buildTestGenome(1)
+	gnome1.Genes[0].IsEnabled = false
+	gnome1.Genes[1].IsEnabled = false
+	for i := 0; i < 10; i++ {
+		if _, err := gnome1.mutateDeleteLink(utils.GlobalRand()); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	if len(gnome1.Genes) != 1 || !gnome1.Genes[0].IsEnabled {
+		t.Error("The only enabled gene should be kept", gnome1)
+	}
+}
+
+func TestKeepGeneDisabled(t *testing.T) {
+	rand.Seed(42)
+	count := func(prob float64) int {
+		conf := neat.NeatContext{MateKeepDisabledProb:prob}
+		kept := 0
+		for i := 0; i < 1000; i++ {
+			if keepGeneDisabled(&conf) {
+				kept++
+			}
+		}
+		return kept
+	}
+	if kept := count(0); kept < 700 || kept > 800 {
+		t.Error("Default probability 0.75 expected", kept)
+	}
+	if kept := count(1.0); kept != 1000 {
+		t.Error("Genes should be always kept disabled", kept)
+	}
+	if kept := count(-1.0); kept != 0 {
+		t.Error("Genes should be always re-enabled", kept)
+	}
+}
+
+func TestGenome_mate_disabledGenes(t *testing.T) {
+	rand.Seed(42)
+	mates := map[string]func(g1, g2 *Genome, conf *neat.NeatContext) (*Genome, error) {
+		"multipoint":func(g1, g2 *Genome, conf *neat.NeatContext) (*Genome, error) {
+			return g1.mateMultipoint(g2, 3, 1.0, 2.3, conf)
+		},
+		"multipoint_avg":func(g1, g2 *Genome, conf *neat.NeatContext) (*Genome, error) {
+			return g1.mateMultipointAvg(g2, 3, 1.0, 2.3, conf)
+		},
+	}
+	for name, mate := range mates {
+		for _, prob := range []float64{1.0, -1.0} {
+			gnome1 := buildTestGenome(1)
+			gnome2 := buildTestGenome(2)
+			gnome1.Genes[1].IsEnabled = false
+
+			conf := neat.NeatContext{MateKeepDisabledProb:prob}
+			child, err := mate(gnome1, gnome2, &conf)
+			if err != nil {
+				t.Error(name, err)
+				continue
+			}
+			for _, gn := range child.Genes {
+				expected := true
+				if gn.InnovationNum == gnome1.Genes[1].InnovationNum && prob > 0 {
+					expected = false
+				}
+				if gn.IsEnabled != expected {
+					t.Error(name, prob, "Wrong enabled state of gene", gn.InnovationNum, gn.IsEnabled)
+				}
+			}
+		}
+	}
+}
+
 func TestGenome_mateMultipoint(t *testing.T) {
 	rand.Seed(42)
 	gnome1 := buildTestGenome(1)
@@ -686,7 +1332,7 @@ func TestGenome_mateMultipoint(t *testing.T) {
 	genomeid := 3
 	fitness1, fitness2 := 1.0, 2.3
 
-	gnome_child, err := gnome1.mateMultipoint(gnome2, genomeid, fitness1, fitness2)
+	gnome_child, err := gnome1.mateMultipoint(gnome2, genomeid, fitness1, fitness2, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -708,7 +1354,7 @@ func TestGenome_mateMultipoint(t *testing.T) {
 	gene := newGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2], gnome1.Nodes[3], false), 4, 0, true)
 	gnome1.Genes = append(gnome1.Genes, gene)
 	fitness1, fitness2 = 15.0, 2.3
-	gnome_child, err = gnome1.mateMultipoint(gnome2, genomeid, fitness1, fitness2)
+	gnome_child, err = gnome1.mateMultipoint(gnome2, genomeid, fitness1, fitness2, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -734,7 +1380,7 @@ func TestGenome_mateMultipointModular(t *testing.T) {
 	genomeid := 3
 	fitness1, fitness2 := 1.0, 2.3
 
-	gnome_child, err := gnome1.mateMultipoint(gnome2, genomeid, fitness1, fitness2)
+	gnome_child, err := gnome1.mateMultipoint(gnome2, genomeid, fitness1, fitness2, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -763,7 +1409,7 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 
 	genomeid := 3
 	fitness1, fitness2 := 1.0, 2.3
-	gnome_child, err := gnome1.mateMultipointAvg(gnome2, genomeid, fitness1, fitness2)
+	gnome_child, err := gnome1.mateMultipointAvg(gnome2, genomeid, fitness1, fitness2, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -787,7 +1433,7 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 	gnome2.Genes = append(gnome2.Genes, gene2)
 
 	fitness1, fitness2 = 15.0, 2.3
-	gnome_child, err = gnome1.mateMultipointAvg(gnome2, genomeid, fitness1, fitness2)
+	gnome_child, err = gnome1.mateMultipointAvg(gnome2, genomeid, fitness1, fitness2, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -813,7 +1459,7 @@ func TestGenome_mateMultipointAvgModular(t *testing.T) {
 	genomeid := 3
 	fitness1, fitness2 := 1.0, 2.3
 
-	gnome_child, err := gnome1.mateMultipointAvg(gnome2, genomeid, fitness1, fitness2)
+	gnome_child, err := gnome1.mateMultipointAvg(gnome2, genomeid, fitness1, fitness2, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -841,7 +1487,7 @@ func TestGenome_mateSinglepoint(t *testing.T) {
 	gnome2 := buildTestGenome(2)
 
 	genomeid := 3
-	gnome_child, err := gnome1.mateSinglepoint(gnome2, genomeid)
+	gnome_child, err := gnome1.mateSinglepoint(gnome2, genomeid, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -861,7 +1507,7 @@ func TestGenome_mateSinglepoint(t *testing.T) {
 	// check not size equal gene pools
 	gene := newGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2], gnome1.Nodes[3], false), 4, 0, false)
 	gnome1.Genes = append(gnome1.Genes, gene)
-	gnome_child, err = gnome1.mateSinglepoint(gnome2, genomeid)
+	gnome_child, err = gnome1.mateSinglepoint(gnome2, genomeid, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -883,7 +1529,7 @@ func TestGenome_mateSinglepoint(t *testing.T) {
 	// append additional gene
 	gnome2.Genes = append(gnome2.Genes, newGene(network.NewLinkWithTrait(gnome2.Traits[2], 5.5, gnome2.Nodes[1], gnome2.Nodes[3], true), 4, 0, false))
 
-	gnome_child, err = gnome1.mateSinglepoint(gnome2, genomeid)
+	gnome_child, err = gnome1.mateSinglepoint(gnome2, genomeid, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -908,7 +1554,7 @@ func TestGenome_mateSinglepointModular(t *testing.T) {
 
 	genomeid := 3
 
-	gnome_child, err := gnome1.mateSinglepoint(gnome2, genomeid)
+	gnome_child, err := gnome1.mateSinglepoint(gnome2, genomeid, &neat.NeatContext{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -944,4 +1590,52 @@ func TestGenome_geneInsert(t *testing.T) {
 			t.Error("(g.InnovationNum != i + 1)", g.InnovationNum, i + 1)
 		}
 	}
-}
\ No newline at end of file
+}
+// Creates pair of random genomes of moderate size sharing the same innovation numbers of initial links
+func buildBenchmarkGenomes() (*Genome, *Genome) {
+	rand.Seed(42)
+	in, out, nmax, n := 10, 5, 20, 10
+	return newGenomeRand(1, in, out, n, nmax, false, 0.5), newGenomeRand(2, in, out, n, nmax, false, 0.5)
+}
+
+func BenchmarkGenome_compatLinear(b *testing.B) {
+	gnome1, gnome2 := buildBenchmarkGenomes()
+	conf := neat.NeatContext{DisjointCoeff:1.0, ExcessCoeff:1.0, MutdiffCoeff:0.4, GenCompatMethod:0}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gnome1.compatibility(gnome2, &conf)
+	}
+}
+
+func BenchmarkGenome_compatFast(b *testing.B) {
+	gnome1, gnome2 := buildBenchmarkGenomes()
+	conf := neat.NeatContext{DisjointCoeff:1.0, ExcessCoeff:1.0, MutdiffCoeff:0.4, GenCompatMethod:1}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gnome1.compatibility(gnome2, &conf)
+	}
+}
+
+func BenchmarkGenome_Genesis(b *testing.B) {
+	gnome, _ := buildBenchmarkGenomes()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gnome.Genesis(1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenome_Clone(b *testing.B) {
+	gnome, _ := buildBenchmarkGenomes()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gnome.Clone(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
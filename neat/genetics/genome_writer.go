@@ -4,6 +4,9 @@ import (
 	"io"
 	"bufio"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"gopkg.in/yaml.v2"
 	"github.com/spf13/cast"
 	"github.com/yaricom/goNEAT/neat"
@@ -24,6 +27,10 @@ func NewGenomeWriter(w io.Writer, encoding GenomeEncoding) (GenomeWriter, error)
 		return &plainGenomeWriter{w:bufio.NewWriter(w)}, nil
 	case YAMLGenomeEncoding:
 		return &yamlGenomeWriter{w:bufio.NewWriter(w)}, nil
+	case NeatPythonGenomeEncoding:
+		return &neatPythonGenomeWriter{w:bufio.NewWriter(w)}, nil
+	case JSONGenomeEncoding:
+		return &jsonGenomeWriter{w:bufio.NewWriter(w)}, nil
 	default:
 		return nil, ErrUnsupportedGenomeEncoding
 	}
@@ -104,6 +111,13 @@ func (wr *plainGenomeWriter) writeNetworkNode(n *network.NNode) error {
 		_, err = fmt.Fprintf(wr.w, "%d %d %d %d %s", n.Id, trait_id, n.NodeType(),
 			n.NeuronType, act_str)
 	}
+	if err == nil && n.IsMemory {
+		// the memory cell is marked by node type and always followed by initial activation and forget gate
+		_, err = fmt.Fprintf(wr.w, " %g %g", n.InitialActivation, n.MemoryRetention)
+	} else if err == nil && n.InitialActivation != 0 {
+		// the initial activation is optional to keep format compatible with genomes without it
+		_, err = fmt.Fprintf(wr.w, " %g", n.InitialActivation)
+	}
 	return err
 }
 // Dump connection gene in plain text format
@@ -123,6 +137,22 @@ func (wr *plainGenomeWriter) writeConnectionGene(g *Gene) error {
 
 	_, err := fmt.Fprintf(wr.w, "%d %d %d %g %t %d %g %t",
 		traitId, inNodeId, outNodeId, weight, recurrent, innov_num, mut_num, enabled)
+	if err == nil && (link.Delay > 0 || link.GateNode != nil || g.IsFrozen || g.mutationSigma > 0) {
+		// the delay and gating node ID are optional to keep format compatible with genes without them
+		gate_id := 0
+		if link.GateNode != nil {
+			gate_id = link.GateNode.Id
+		}
+		_, err = fmt.Fprintf(wr.w, " %d %d", link.Delay, gate_id)
+	}
+	if err == nil && (g.IsFrozen || g.mutationSigma > 0) {
+		// the frozen flag is optional and follows delay and gating node ID
+		_, err = fmt.Fprintf(wr.w, " %t", g.IsFrozen)
+	}
+	if err == nil && g.mutationSigma > 0 {
+		// the self-adapted mutation step size is optional and follows frozen flag
+		_, err = fmt.Fprintf(wr.w, " %g", g.mutationSigma)
+	}
 	return err
 }
 
@@ -240,6 +270,18 @@ func (wr *yamlGenomeWriter) encodeConnectionGene(gene *Gene) map[string]interfac
 	g_map["mut_num"] = gene.MutationNum
 	g_map["recurrent"] = cast.ToString(gene.Link.IsRecurrent)
 	g_map["enabled"] = cast.ToString(gene.IsEnabled)
+	if gene.Link.Delay > 0 {
+		g_map["delay"] = gene.Link.Delay
+	}
+	if gene.Link.GateNode != nil {
+		g_map["gate_id"] = gene.Link.GateNode.Id
+	}
+	if gene.IsFrozen {
+		g_map["frozen"] = cast.ToString(gene.IsFrozen)
+	}
+	if gene.mutationSigma > 0 {
+		g_map["mutation_sigma"] = gene.mutationSigma
+	}
 	return g_map
 }
 
@@ -253,6 +295,12 @@ func (wr *yamlGenomeWriter) encodeNetworkNode(node *network.NNode) (n_map map[st
 	}
 	n_map["type"] = network.NeuronTypeName(node.NeuronType)
 	n_map["activation"], err = utils.NodeActivators.ActivationNameFromType(node.ActivationType)
+	if node.InitialActivation != 0 {
+		n_map["init_activation"] = node.InitialActivation
+	}
+	if node.IsMemory {
+		n_map["memory_retention"] = node.MemoryRetention
+	}
 	return n_map, err
 }
 
@@ -261,4 +309,114 @@ func (wr *yamlGenomeWriter) encodeGenomeTrait(trait *neat.Trait) map[string]inte
 	tr_map["id"] = trait.Id
 	tr_map["params"] = trait.Params
 	return tr_map
-}
\ No newline at end of file
+}
+// The writer of genomes in text format produced by string representation of NEAT-Python DefaultGenome. The input nodes
+// get NEAT-Python keys -1, -2, ... in order of appearance, the output nodes get keys 0, 1, ..., and the hidden nodes
+// keys following after outputs. The weights of enabled links from bias nodes are stored as biases of connected nodes,
+// and the disabled links from bias nodes are skipped as they contribute no bias.
+// See neatPythonGenomeReader for format details.
+type neatPythonGenomeWriter struct {
+	w *bufio.Writer
+}
+
+func (wr *neatPythonGenomeWriter) WriteGenome(g *Genome) error {
+	if len(g.ControlGenes) > 0 {
+		return newError(ErrUnsupportedGenomeEncoding, "NEAT-Python genome can not have MIMO control genes")
+	}
+	for _, gene := range g.Genes {
+		if gene.Link.Delay > 0 || gene.Link.GateNode != nil {
+			return newError(ErrUnsupportedGenomeEncoding, "NEAT-Python genome can not have delayed or gated links: %s", gene)
+		}
+	}
+	for _, node := range g.Nodes {
+		if node.IsMemory {
+			return newError(ErrUnsupportedGenomeEncoding, "NEAT-Python genome can not have memory nodes: %s", node)
+		}
+	}
+
+	// assign NEAT-Python keys
+	keys := make(map[int]int)
+	inputs, outputs := 0, 0
+	for _, n := range g.Nodes {
+		switch n.NeuronType {
+		case network.InputNeuron:
+			inputs++
+			keys[n.Id] = -inputs
+		case network.OutputNeuron:
+			keys[n.Id] = outputs
+			outputs++
+		}
+	}
+	hidden := make([]*network.NNode, 0)
+	for _, n := range g.Nodes {
+		if n.NeuronType == network.HiddenNeuron {
+			keys[n.Id] = outputs + len(hidden)
+			hidden = append(hidden, n)
+		}
+	}
+
+	// collect biases and connections
+	biases := make(map[int]float64)
+	conns := make([]*Gene, 0)
+	for _, gene := range g.Genes {
+		if gene.Link.InNode.NeuronType == network.BiasNeuron {
+			if gene.IsEnabled {
+				biases[gene.Link.OutNode.Id] += gene.Link.Weight
+			}
+		} else {
+			conns = append(conns, gene)
+		}
+	}
+	sort.SliceStable(conns, func(i, j int) bool {
+		in_i, in_j := keys[conns[i].Link.InNode.Id], keys[conns[j].Link.InNode.Id]
+		if in_i != in_j {
+			return in_i < in_j
+		}
+		return keys[conns[i].Link.OutNode.Id] < keys[conns[j].Link.OutNode.Id]
+	})
+
+	_, err := fmt.Fprintf(wr.w, "Key: %d\nFitness: None\nNodes:", g.Id)
+	if err != nil {
+		return err
+	}
+	for _, n := range g.Nodes {
+		if n.NeuronType != network.OutputNeuron && n.NeuronType != network.HiddenNeuron {
+			continue
+		}
+		activation, err := utils.ActivationTypeToNeatPython(n.ActivationType)
+		if err != nil {
+			return err
+		}
+		key := keys[n.Id]
+		if _, err = fmt.Fprintf(wr.w, "\n\t%d DefaultNodeGene(key=%d, bias=%s, response=1.0, activation=%s, aggregation=sum)",
+			key, key, pythonFloat(biases[n.Id]), activation); err != nil {
+			return err
+		}
+	}
+	if _, err = fmt.Fprint(wr.w, "\nConnections:"); err != nil {
+		return err
+	}
+	for _, gene := range conns {
+		enabled := "False"
+		if gene.IsEnabled {
+			enabled = "True"
+		}
+		if _, err = fmt.Fprintf(wr.w, "\n\tDefaultConnectionGene(key=(%d, %d), weight=%s, enabled=%s)",
+			keys[gene.Link.InNode.Id], keys[gene.Link.OutNode.Id], pythonFloat(gene.Link.Weight), enabled); err != nil {
+			return err
+		}
+	}
+	if _, err = fmt.Fprintln(wr.w); err != nil {
+		return err
+	}
+	return wr.w.Flush()
+}
+
+// Formats float value the same way as Python does, i.e. with at least one decimal digit
+func pythonFloat(value float64) string {
+	str := strconv.FormatFloat(value, 'g', -1, 64)
+	if !strings.ContainsAny(str, ".eEn") {
+		str += ".0"
+	}
+	return str
+}
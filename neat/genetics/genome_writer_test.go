@@ -62,6 +62,32 @@ func TestPlainGenomeWriter_WriteNetworkNode(t *testing.T) {
 	if out_str != node_str {
 		t.Errorf("Node serialization failed. Expected: %s, but found %s", node_str, out_str)
 	}
+
+	// the initial activation is written if set
+	node.InitialActivation = -0.25
+	out_buffer.Reset()
+	if err = wr.writeNetworkNode(node); err != nil {
+		t.Error(err)
+		return
+	}
+	wr.w.Flush()
+	if out_str = out_buffer.String(); out_str != node_str + " -0.25" {
+		t.Errorf("Node serialization failed. Expected: %s -0.25, but found %s", node_str, out_str)
+	}
+
+	// the memory cell is marked by node type
+	node = network.NewNNode(node_id, network.HiddenNeuron)
+	node.IsMemory, node.MemoryRetention = true, 0.5
+	out_buffer.Reset()
+	if err = wr.writeNetworkNode(node); err != nil {
+		t.Error(err)
+		return
+	}
+	wr.w.Flush()
+	node_str = fmt.Sprintf("%d 0 %d %d SigmoidSteepenedActivation 0 0.5", node_id, network.MemoryNode, network.HiddenNeuron)
+	if out_str = out_buffer.String(); out_str != node_str {
+		t.Errorf("Node serialization failed. Expected: %s, but found %s", node_str, out_str)
+	}
 }
 
 func TestPlainGenomeWriter_WriteConnectionGene(t *testing.T) {
@@ -92,6 +118,18 @@ func TestPlainGenomeWriter_WriteConnectionGene(t *testing.T) {
 	if gene_str != out_str {
 		t.Errorf("Wrong Gene serialization\n[%s]\n[%s]", gene_str, out_str)
 	}
+
+	// the delay and gating node ID are written if set
+	gene.Link.Delay, gene.Link.GateNode = 3, gene.Link.InNode
+	out_buf.Reset()
+	if err = wr.writeConnectionGene(gene); err != nil {
+		t.Error(err)
+		return
+	}
+	wr.w.Flush()
+	if out_str = out_buf.String(); out_str != gene_str + " 3 1" {
+		t.Errorf("Wrong Gene serialization\n[%s 3 1]\n[%s]", gene_str, out_str)
+	}
 }
 
 func TestPlainGenomeWriter_WriteGenome(t *testing.T) {
@@ -124,8 +162,44 @@ func TestPlainGenomeWriter_WriteGenome(t *testing.T) {
 	}
 }
 
+func TestGenome_mutationSigmaPersistence(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes[0].mutationSigma = 0.25
+	gnome.Genes[1].mutationSigma, gnome.Genes[1].IsFrozen = 1.5, true
+	for _, encoding := range []GenomeEncoding{PlainGenomeEncoding, YAMLGenomeEncoding, JSONGenomeEncoding} {
+		out_buf := bytes.NewBufferString("")
+		wr, err := NewGenomeWriter(out_buf, encoding)
+		if err == nil {
+			err = wr.WriteGenome(gnome)
+		}
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reader, err := NewGenomeReader(bytes.NewReader(out_buf.Bytes()), encoding)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		gnome_enc, err := reader.Read()
+		if err != nil {
+			t.Error(encoding, err)
+			continue
+		}
+		// the self-adapted mutation step size should survive dump and reload
+		for i, gn := range gnome_enc.Genes {
+			if gn.mutationSigma != gnome.Genes[i].mutationSigma || gn.IsFrozen != gnome.Genes[i].IsFrozen {
+				t.Error("Wrong mutation sigma of gene at:", i, encoding, gn.mutationSigma)
+			}
+		}
+	}
+}
+
 func TestYamlGenomeWriter_WriteGenome(t *testing.T) {
 	gnome := buildTestModularGenome(1)
+	gnome.Nodes[len(gnome.Nodes) - 1].InitialActivation = 0.75
+	gnome.Nodes[4].IsMemory, gnome.Nodes[4].MemoryRetention = true, 0.25
+	gnome.Genes[0].Link.Delay, gnome.Genes[0].Link.GateNode = 1, gnome.Nodes[1]
 
 	// encode genome
 	out_buf := bytes.NewBufferString("")
@@ -167,6 +241,13 @@ func TestYamlGenomeWriter_WriteGenome(t *testing.T) {
 		if g.InnovationNum != og.InnovationNum {
 			t.Error("g.InnovationNum != og.InnovationNum at:", i)
 		}
+		if g.Link.Delay != og.Link.Delay {
+			t.Error("g.Link.Delay != og.Link.Delay at:", i)
+		}
+		if (g.Link.GateNode == nil) != (og.Link.GateNode == nil) ||
+			g.Link.GateNode != nil && g.Link.GateNode.Id != og.Link.GateNode.Id {
+			t.Error("Wrong gating node at:", i)
+		}
 	}
 
 	if len(gnome.Nodes) != len(gnome_enc.Nodes) {
@@ -183,6 +264,12 @@ func TestYamlGenomeWriter_WriteGenome(t *testing.T) {
 		if n.NeuronType != nd.NeuronType {
 			t.Error("n.NeuronType != nd.NeuronType at:", i)
 		}
+		if n.InitialActivation != nd.InitialActivation {
+			t.Error("n.InitialActivation != nd.InitialActivation at:", i)
+		}
+		if n.IsMemory != nd.IsMemory || n.MemoryRetention != nd.MemoryRetention {
+			t.Error("Wrong memory cell at:", i)
+		}
 	}
 
 	if len(gnome.Traits) != len(gnome_enc.Traits) {
@@ -237,4 +324,96 @@ func checkLinks(left, right []*network.Link, t *testing.T) {
 			t.Error("l.Weight != r.Weight", l.Weight, r.Weight)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestNeatPythonGenomeWriter_WriteGenome(t *testing.T) {
+	gnome, err := NewNeatPythonGenomeReader(strings.NewReader(neatPythonGenomesStr), 2, 1).Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	out_buf := bytes.NewBufferString("")
+	wr, err := NewGenomeWriter(out_buf, NeatPythonGenomeEncoding)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = wr.WriteGenome(gnome); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the response is already applied to the weights
+	expected := "Key: 42\nFitness: None\nNodes:\n" +
+		"\t0 DefaultNodeGene(key=0, bias=-0.5, response=1.0, activation=sigmoid, aggregation=sum)\n" +
+		"\t1 DefaultNodeGene(key=1, bias=0.0, response=1.0, activation=tanh, aggregation=sum)\n" +
+		"Connections:\n" +
+		"\tDefaultConnectionGene(key=(-2, 1), weight=1.5, enabled=True)\n" +
+		"\tDefaultConnectionGene(key=(-1, 0), weight=0.5, enabled=False)\n" +
+		"\tDefaultConnectionGene(key=(1, 0), weight=-2.0, enabled=True)\n"
+	if out_buf.String() != expected {
+		t.Errorf("Wrong NEAT-Python genome serialization\n%s\n%s", expected, out_buf.String())
+	}
+
+	// read it back
+	r_gnome, err := NewNeatPythonGenomeReader(out_buf, 2, 1).Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(r_gnome.Nodes) != len(gnome.Nodes) || len(r_gnome.Genes) != len(gnome.Genes) {
+		t.Error("Genome read back has wrong structure", r_gnome)
+	}
+}
+
+func TestNeatPythonGenomeWriter_WriteGenome_disabledBias(t *testing.T) {
+	gnome, err := NewNeatPythonGenomeReader(strings.NewReader(neatPythonGenomesStr), 2, 1).Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the last gene is a link from bias node toggled off by mutation
+	gnome.Genes[3].IsEnabled = false
+	out_buf := bytes.NewBufferString("")
+	wr, _ := NewGenomeWriter(out_buf, NeatPythonGenomeEncoding)
+	if err = wr.WriteGenome(gnome); err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(out_buf.String(), "0 DefaultNodeGene(key=0, bias=0.0,") {
+		t.Error("The disabled bias link should not contribute bias", out_buf.String())
+	}
+
+	// the disabled bias link is skipped, thus genome read back has no bias genes
+	r_gnome, err := NewNeatPythonGenomeReader(out_buf, 2, 1).Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(r_gnome.Genes) != len(gnome.Genes) - 1 {
+		t.Error("Wrong number of genes read back", len(r_gnome.Genes))
+	}
+}
+
+func TestNeatPythonGenomeWriter_WriteGenome_temporal(t *testing.T) {
+	wr, _ := NewGenomeWriter(bytes.NewBufferString(""), NeatPythonGenomeEncoding)
+	if err := wr.WriteGenome(buildTestTemporalGenome(1)); err == nil {
+		t.Error("Error expected for delayed and gated links")
+	}
+}
+
+func TestNeatPythonGenomeWriter_WriteGenome_memory(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Nodes[3].IsMemory = true
+	wr, _ := NewGenomeWriter(bytes.NewBufferString(""), NeatPythonGenomeEncoding)
+	if err := wr.WriteGenome(gnome); err == nil {
+		t.Error("Error expected for memory node")
+	}
+}
+
+func TestNeatPythonGenomeWriter_WriteGenome_modular(t *testing.T) {
+	wr, _ := NewGenomeWriter(bytes.NewBufferString(""), NeatPythonGenomeEncoding)
+	if err := wr.WriteGenome(buildTestModularGenome(1)); err == nil {
+		t.Error("Error expected for modular genome")
+	}
+}
@@ -0,0 +1,94 @@
+package genetics
+
+import (
+	"sort"
+)
+
+// The key of link gene within innovations database: the link is identified by its end nodes and recurrence flag
+type linkInnovationKey struct {
+	inNodeId, outNodeId int
+	isRecurrent         bool
+}
+
+// Maps innovation numbers of genes of given foreign genome, e.g. immigrant from other population or genome imported
+// from NEAT-Python, onto the innovation database of this population. The gene gets innovation number of the link
+// already known to this population, i.e. registered as structural innovation of current generation or present in
+// genome of any organism, which connects the same nodes and has the same recurrence. The genes created by add node
+// mutation are matched by links to and from the new node. The genes unknown to this population get new innovation
+// numbers registered as link innovations, thus the same foreign link reconciled again will get the same number. The
+// nodes of foreign genome are matched by IDs and the node ID counter is moved beyond the IDs used by genome to avoid
+// clashes with nodes created by future mutations. After reconciliation the genes of genome are sorted by innovation
+// number, thus it can be used for crossover with organisms of this population.
+func (p *Population) ReconcileGenome(g *Genome) {
+	known := p.knownLinkInnovations()
+	for _, gene := range g.Genes {
+		key := linkInnovationKey{
+			inNodeId:gene.Link.InNode.Id,
+			outNodeId:gene.Link.OutNode.Id,
+			isRecurrent:gene.Link.IsRecurrent,
+		}
+		if innov_num, ok := known[key]; ok {
+			gene.InnovationNum = innov_num
+			continue
+		}
+		weight := gene.Link.Weight
+		inn, _ := p.findOrAddInnovationSynced(func(inn *Innovation) bool {
+			return inn.innovationType == newLinkInnType &&
+				inn.InNodeId == key.inNodeId &&
+				inn.OutNodeId == key.outNodeId &&
+				inn.IsRecurrent == key.isRecurrent
+		}, func() *Innovation {
+			return NewInnovationForRecurrentLink(key.inNodeId, key.outNodeId,
+				p.getNextInnovationNumberAndIncrement(), weight, 0, key.isRecurrent)
+		})
+		gene.InnovationNum = inn.InnovationNum
+		known[key] = inn.InnovationNum
+	}
+	sort.SliceStable(g.Genes, func(i, j int) bool {
+		return g.Genes[i].InnovationNum < g.Genes[j].InnovationNum
+	})
+
+	for _, node := range g.Nodes {
+		if int32(node.Id) > p.nextNodeId {
+			p.nextNodeId = int32(node.Id)
+		}
+	}
+	// the control genes of modular genome are not matched, just make sure they will not clash with future innovations
+	for _, cg := range g.ControlGenes {
+		if cg.InnovationNum > p.nextInnovNum {
+			p.nextInnovNum = cg.InnovationNum
+		}
+	}
+}
+
+// Returns innovation numbers of links known to this population. The innovations of current generation take
+// precedence over genes of organisms. If the same link has different innovation numbers in genomes of organisms, i.e.
+// it was independently discovered in different generations, the smallest number is used.
+func (p *Population) knownLinkInnovations() map[linkInnovationKey]int64 {
+	known := make(map[linkInnovationKey]int64)
+	for _, org := range p.Organisms {
+		for _, gene := range org.Genotype.Genes {
+			key := linkInnovationKey{
+				inNodeId:gene.Link.InNode.Id,
+				outNodeId:gene.Link.OutNode.Id,
+				isRecurrent:gene.Link.IsRecurrent,
+			}
+			if innov_num, ok := known[key]; !ok || gene.InnovationNum < innov_num {
+				known[key] = gene.InnovationNum
+			}
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, inn := range p.Innovations {
+		switch inn.innovationType {
+		case newLinkInnType:
+			known[linkInnovationKey{inNodeId:inn.InNodeId, outNodeId:inn.OutNodeId, isRecurrent:inn.IsRecurrent}] = inn.InnovationNum
+		case newNodeInnType:
+			known[linkInnovationKey{inNodeId:inn.InNodeId, outNodeId:inn.NewNodeId}] = inn.InnovationNum
+			known[linkInnovationKey{inNodeId:inn.NewNodeId, outNodeId:inn.OutNodeId}] = inn.InnovationNum2
+		}
+	}
+	return known
+}
@@ -0,0 +1,82 @@
+package genetics
+
+import (
+	"testing"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+func TestPopulation_ReconcileGenome(t *testing.T) {
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		PopSize: 2,
+	}
+	start, err := NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pop, err := NewPopulation(start, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	last_innov := pop.nextInnovNum
+	// the add node innovation of current generation: the link 1 -> 4 split by node 5
+	pop.Innovations = append(pop.Innovations,
+		NewInnovationForNode(1, 4, last_innov + 1, last_innov + 2, 5, start.Genes[0].InnovationNum))
+	pop.nextInnovNum, pop.nextNodeId = last_innov + 2, 5
+
+	// the foreign genome with the same structure but other innovation numbers and new recurrent link
+	foreign, err := NewGenomeFullyConnected(2, 1, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	hidden := network.NewNNode(5, network.HiddenNeuron)
+	recurrent := network.NewNNode(7, network.HiddenNeuron)
+	foreign.Nodes = append(foreign.Nodes, hidden, recurrent)
+	foreign.Genes = append(foreign.Genes,
+		NewGene(1.0, foreign.Nodes[0], hidden, false, 1, 0),
+		NewGene(1.0, hidden, foreign.Nodes[3], false, 2, 0),
+		NewGene(1.0, recurrent, recurrent, true, 3, 0))
+	for i, gene := range foreign.Genes[:3] {
+		gene.InnovationNum = int64(100 - i)
+	}
+
+	pop.ReconcileGenome(foreign)
+
+	expected := map[linkInnovationKey]int64{
+		{inNodeId:1, outNodeId:4}:start.Genes[0].InnovationNum,
+		{inNodeId:2, outNodeId:4}:start.Genes[1].InnovationNum,
+		{inNodeId:3, outNodeId:4}:start.Genes[2].InnovationNum,
+		{inNodeId:1, outNodeId:5}:last_innov + 1,
+		{inNodeId:5, outNodeId:4}:last_innov + 2,
+		{inNodeId:7, outNodeId:7, isRecurrent:true}:last_innov + 3,
+	}
+	for i, gene := range foreign.Genes {
+		key := linkInnovationKey{gene.Link.InNode.Id, gene.Link.OutNode.Id, gene.Link.IsRecurrent}
+		if gene.InnovationNum != expected[key] {
+			t.Error("Wrong innovation number", key, gene.InnovationNum, expected[key])
+		}
+		if i > 0 && foreign.Genes[i - 1].InnovationNum > gene.InnovationNum {
+			t.Error("Genes should be sorted by innovation number")
+		}
+	}
+	if pop.nextInnovNum != last_innov + 3 || pop.nextNodeId != 7 {
+		t.Error("Wrong counters", pop.nextInnovNum, pop.nextNodeId)
+	}
+
+	// the same unknown link reconciled again gets the same innovation number
+	other, _ := NewGenomeFullyConnected(2, 1, 0)
+	node := network.NewNNode(7, network.HiddenNeuron)
+	other.Nodes = append(other.Nodes, node)
+	other.Genes = append(other.Genes, NewGene(0.5, node, node, true, 1, 0))
+	pop.ReconcileGenome(other)
+	if gene := other.Genes[len(other.Genes) - 1]; gene.InnovationNum != last_innov + 3 {
+		t.Error("The same innovation number expected", gene.InnovationNum)
+	}
+	if pop.nextInnovNum != last_innov + 3 {
+		t.Error("No new innovations expected", pop.nextInnovNum)
+	}
+}
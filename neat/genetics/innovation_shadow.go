@@ -0,0 +1,123 @@
+package genetics
+
+import (
+	"sort"
+	"sync"
+)
+
+// The private store of innovations used by species reproducing in parallel with other species. The innovations and
+// new nodes get provisional numbers continuing the innovation numbers and node IDs of population, and the final ones
+// are assigned by merging shadows in order of species, thus the IDs do not depend on the order in which concurrent
+// workers reached innovation database.
+type innovationShadow struct {
+	// The population to pass to reproduction of species instead of the original one
+	pop        *Population
+	// The number of innovations of population known when shadow was created
+	known      int
+	// The innovation number and node ID of population when shadow was created, the greater ones are provisional
+	innovNum   int64
+	nodeId     int32
+}
+
+// Returns new shadow of this population to reproduce species in parallel with other species. The shadow shares species,
+// organisms, mutation pipeline, compatibility parameters and species ID sequence with this population, but registers
+// innovations and reproduction statistics in private store.
+func (p *Population) newInnovationShadow() *innovationShadow {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	shadow := &Population{
+		Species:p.Species,
+		Organisms:p.Organisms,
+		LastSpecies:p.LastSpecies,
+		CompatThreshold:p.CompatThreshold,
+		CompatCoefficients:p.CompatCoefficients,
+		MutationPipeline:p.MutationPipeline,
+		speciesIds:p.speciesIds,
+		Innovations:append(make([]*Innovation, 0, len(p.Innovations)), p.Innovations...),
+		nextInnovNum:p.nextInnovNum,
+		nextNodeId:p.nextNodeId,
+		mutex:&sync.Mutex{},
+	}
+	return &innovationShadow{pop:shadow, known:len(p.Innovations), innovNum:p.nextInnovNum, nodeId:p.nextNodeId}
+}
+
+// Registers innovations of given shadow in this population and replaces provisional innovation numbers and node IDs
+// in genomes of offspring produced with shadow. The innovations already occurred in this population keep their
+// numbers, the novel ones get the next numbers in order of their creation. The provisional IDs not related to
+// innovations, e.g. of duplicated nodes, get the next IDs in order of offspring. The reproduction statistics of shadow
+// recorded in this population as well. The shadows should be merged in order of species to get the same IDs in each
+// run.
+func (p *Population) mergeInnovationShadow(shadow *innovationShadow, babies []*Organism) {
+	innov_nums, node_ids := make(map[int64]int64), make(map[int]int)
+	map_innov := func(num int64) int64 {
+		if num <= shadow.innovNum {
+			return num
+		}
+		if mapped, ok := innov_nums[num]; ok {
+			return mapped
+		}
+		innov_nums[num] = p.getNextInnovationNumberAndIncrement()
+		return innov_nums[num]
+	}
+	map_node := func(id int) int {
+		if int32(id) <= shadow.nodeId {
+			return id
+		}
+		if mapped, ok := node_ids[id]; ok {
+			return mapped
+		}
+		node_ids[id] = int(p.getNextNodeIdAndIncrement())
+		return node_ids[id]
+	}
+
+	for _, inn := range shadow.pop.Innovations[shadow.known:] {
+		local := *inn
+		local.InNodeId, local.OutNodeId = map_node(inn.InNodeId), map_node(inn.OutNodeId)
+		if inn.innovationType == newNodeInnType {
+			local.OldInnovNum = map_innov(inn.OldInnovNum)
+		}
+		registered, _ := p.findOrAddInnovationSynced(func(inn *Innovation) bool {
+			return inn.innovationType == local.innovationType &&
+				inn.InNodeId == local.InNodeId &&
+				inn.OutNodeId == local.OutNodeId &&
+				inn.IsRecurrent == local.IsRecurrent &&
+				inn.OldInnovNum == local.OldInnovNum
+		}, func() *Innovation {
+			created := local
+			if created.innovationType == newNodeInnType {
+				created.NewNodeId = int(p.getNextNodeIdAndIncrement())
+				created.InnovationNum = p.getNextInnovationNumberAndIncrement()
+				created.InnovationNum2 = p.getNextInnovationNumberAndIncrement()
+			} else {
+				created.InnovationNum = p.getNextInnovationNumberAndIncrement()
+			}
+			return &created
+		})
+		innov_nums[inn.InnovationNum] = registered.InnovationNum
+		if inn.innovationType == newNodeInnType {
+			innov_nums[inn.InnovationNum2] = registered.InnovationNum2
+			node_ids[inn.NewNodeId] = registered.NewNodeId
+		}
+	}
+
+	for _, baby := range babies {
+		g := baby.Genotype
+		for _, node := range g.Nodes {
+			node.Id = map_node(node.Id)
+		}
+		for _, gene := range g.Genes {
+			gene.InnovationNum = map_innov(gene.InnovationNum)
+		}
+		sort.SliceStable(g.Nodes, func(i, j int) bool {
+			return g.Nodes[i].Id < g.Nodes[j].Id
+		})
+		sort.SliceStable(g.Genes, func(i, j int) bool {
+			return g.Genes[i].InnovationNum < g.Genes[j].InnovationNum
+		})
+		g.invalidatePhenotype()
+	}
+
+	for i := range shadow.pop.reproductionStats {
+		p.recordReproductionStats(&shadow.pop.reproductionStats[i])
+	}
+}
@@ -0,0 +1,223 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The last lineage ID assigned to organism
+var lastLineageId int64
+
+// Returns the next lineage ID unique within the process
+func nextLineageId() int64 {
+	return atomic.AddInt64(&lastLineageId, 1)
+}
+
+// Sets lineage IDs of given parents as the parents of this organism
+func (o *Organism) inheritLineage(parents []*Organism) {
+	o.ParentLineageIds = make([]int64, len(parents))
+	for i, parent := range parents {
+		o.ParentLineageIds[i] = parent.LineageId
+	}
+}
+
+// Encodes lineage IDs into single line string, the empty list encoded as "-"
+func encodeLineageIds(ids []int64) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(strs, ",")
+}
+
+// Decodes lineage IDs from string produced by encodeLineageIds
+func decodeLineageIds(str string) ([]int64, error) {
+	if str == "-" {
+		return nil, nil
+	}
+	strs := strings.Split(str, ",")
+	ids := make([]int64, len(strs))
+	for i, s := range strs {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// The membership of organism in particular species at given generation
+type SpeciesMembership struct {
+	// The generation number
+	Generation int
+	// The ID of species
+	SpeciesId  int
+}
+
+func (m SpeciesMembership) String() string {
+	return fmt.Sprintf("%d:%d", m.Generation, m.SpeciesId)
+}
+
+// The epoch observer which records species membership of organisms per generation and their parentage, thus it allows
+// to study how lineages of organisms move between species (species hopping) and which organisms founded each species.
+// The organisms are identified by Organism.LineageId. The survivors of steady-state epochs keep their lineage IDs,
+// thus their membership is recorded in each generation they live. The history is kept for the whole evolutionary
+// run, thus the tracker should be used for analysis rather than for long production runs.
+type LineageTracker struct {
+	BaseEpochObserver
+
+	// The species membership history per lineage ID
+	memberships map[int64][]SpeciesMembership
+	// The lineage IDs of parents per lineage ID
+	parents     map[int64][]int64
+	// The lineage IDs of founding members per species ID, i.e. members at the first recorded generation of species
+	founders    map[int][]int64
+	// The first recorded generation per species ID
+	speciesGen  map[int]int
+
+	// The mutex to guard records against concurrent queries
+	mutex       sync.RWMutex
+}
+
+// Creates new lineage tracker, it should be registered as observer of population to record lineage data
+func NewLineageTracker() *LineageTracker {
+	return &LineageTracker{
+		memberships:make(map[int64][]SpeciesMembership),
+		parents:make(map[int64][]int64),
+		founders:make(map[int][]int64),
+		speciesGen:make(map[int]int),
+	}
+}
+
+// Records lineage data of organisms produced by epoch of given generation
+func (t *LineageTracker) OnReproductionDone(generation int, pop *Population) {
+	t.Record(generation, pop)
+}
+
+// Records species membership of all organisms of population at given generation. It can be used to record the
+// initial population as generation zero before the first epoch. The repeated records of the same organism per
+// generation are ignored.
+func (t *LineageTracker) Record(generation int, pop *Population) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, org := range pop.Organisms {
+		if org.Species == nil {
+			continue
+		}
+		id := org.LineageId
+		history := t.memberships[id]
+		if len(history) > 0 && history[len(history) - 1].Generation == generation {
+			continue
+		}
+		t.memberships[id] = append(history, SpeciesMembership{Generation:generation, SpeciesId:org.Species.Id})
+		if _, ok := t.parents[id]; !ok {
+			t.parents[id] = append([]int64(nil), org.ParentLineageIds...)
+		}
+
+		first_gen, ok := t.speciesGen[org.Species.Id]
+		if !ok {
+			first_gen = generation
+			t.speciesGen[org.Species.Id] = generation
+		}
+		if first_gen == generation {
+			t.founders[org.Species.Id] = append(t.founders[org.Species.Id], id)
+		}
+	}
+}
+
+// Returns species membership of organism with given lineage ID in generations it was recorded ordered by generation
+func (t *LineageTracker) SpeciesHistory(lineage_id int64) []SpeciesMembership {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return append([]SpeciesMembership(nil), t.memberships[lineage_id]...)
+}
+
+// Returns the lineage IDs of parents of organism with given lineage ID, the first is the primary parent (mom). Returns
+// false if organism was not recorded.
+func (t *LineageTracker) Parents(lineage_id int64) ([]int64, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	parents, ok := t.parents[lineage_id]
+	return append([]int64(nil), parents...), ok
+}
+
+// Returns species membership along the line of primary parents (moms) of organism with given lineage ID ordered by
+// generation, i.e. the history of species which ancestors of organism belonged to, ending with organism itself.
+func (t *LineageTracker) LineageSpeciesHistory(lineage_id int64) []SpeciesMembership {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	// collect history backwards, the ancestor's membership is taken only before its descendant was recorded, because
+	// ancestors may live longer if they survived steady-state epochs
+	res := make([]SpeciesMembership, 0)
+	limit := math.MaxInt32
+	visited := make(map[int64]bool)
+	for id := lineage_id; !visited[id]; {
+		visited[id] = true
+		history := t.memberships[id]
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].Generation < limit {
+				res = append(res, history[i])
+			}
+		}
+		if len(history) > 0 && history[0].Generation < limit {
+			limit = history[0].Generation
+		}
+		parents := t.parents[id]
+		if len(parents) == 0 {
+			break
+		}
+		id = parents[0]
+	}
+	for i, j := 0, len(res) - 1; i < j; i, j = i + 1, j - 1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+// Returns the number of times the lineage of organism with given lineage ID changed species along the line of
+// primary parents (see LineageSpeciesHistory)
+func (t *LineageTracker) SpeciesHops(lineage_id int64) int {
+	hops := 0
+	history := t.LineageSpeciesHistory(lineage_id)
+	for i := 1; i < len(history); i++ {
+		if history[i].SpeciesId != history[i - 1].SpeciesId {
+			hops++
+		}
+	}
+	return hops
+}
+
+// Returns the lineage IDs of organisms which founded species with given ID, i.e. its members at the first generation
+// the species was recorded
+func (t *LineageTracker) Founders(species_id int) []int64 {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return append([]int64(nil), t.founders[species_id]...)
+}
+
+// Returns the IDs of all recorded species in ascending order
+func (t *LineageTracker) SpeciesIds() []int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	ids := make([]int, 0, len(t.speciesGen))
+	for id := range t.speciesGen {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
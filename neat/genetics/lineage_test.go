@@ -0,0 +1,194 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"encoding/gob"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Builds population of organisms with given lineage IDs and species IDs
+func buildPopulationForLineage(members map[int64]int, parents map[int64][]int64) *Population {
+	pop := newPopulation()
+	species := make(map[int]*Species)
+	for id, sp_id := range members {
+		sp, ok := species[sp_id]
+		if !ok {
+			sp = NewSpecies(sp_id)
+			species[sp_id] = sp
+			pop.Species = append(pop.Species, sp)
+		}
+		org := &Organism{LineageId:id, ParentLineageIds:parents[id], Species:sp}
+		sp.addOrganism(org)
+		pop.Organisms = append(pop.Organisms, org)
+	}
+	return pop
+}
+
+func TestLineageTracker_Record(t *testing.T) {
+	tracker := NewLineageTracker()
+	tracker.Record(0, buildPopulationForLineage(map[int64]int{1:1, 2:1, 3:2}, nil))
+	// organism 4 is the child of 1 hopped to the species 2, organism 5 is the child of 3 and 2 founded species 3
+	// and organism 1 survived
+	gen1 := buildPopulationForLineage(map[int64]int{1:1, 4:2, 5:3}, map[int64][]int64{4:{1}, 5:{3, 2}})
+	tracker.Record(1, gen1)
+	// the repeated record is ignored
+	tracker.OnReproductionDone(1, gen1)
+	// organism 6 is the child of 4 hopped back to the species 1
+	tracker.OnReproductionDone(2, buildPopulationForLineage(map[int64]int{6:1, 5:3}, map[int64][]int64{6:{4}}))
+
+	if history := tracker.SpeciesHistory(1); len(history) != 2 || history[1] != (SpeciesMembership{1, 1}) {
+		t.Error("Wrong species history of survivor", history)
+	}
+	if history := tracker.SpeciesHistory(100); len(history) != 0 {
+		t.Error("Empty history expected for unknown organism", history)
+	}
+	if parents, ok := tracker.Parents(5); !ok || len(parents) != 2 || parents[0] != 3 || parents[1] != 2 {
+		t.Error("Wrong parents", parents, ok)
+	}
+	if parents, ok := tracker.Parents(1); !ok || len(parents) != 0 {
+		t.Error("No parents expected for initial organism", parents, ok)
+	}
+	if _, ok := tracker.Parents(100); ok {
+		t.Error("Unknown organism should not be found")
+	}
+
+	// the lineage of organism 6 is 1 -> 4 -> 6, where organism 1 lived longer than it was recorded in lineage
+	expected := []SpeciesMembership{{0, 1}, {1, 2}, {2, 1}}
+	history := tracker.LineageSpeciesHistory(6)
+	if len(history) != len(expected) {
+		t.Error("Wrong lineage species history", history)
+	} else {
+		for i, m := range history {
+			if m != expected[i] {
+				t.Error("Wrong lineage species membership at", i, m, expected[i])
+			}
+		}
+	}
+	if hops := tracker.SpeciesHops(6); hops != 2 {
+		t.Error("Wrong number of species hops", hops)
+	}
+	if hops := tracker.SpeciesHops(1); hops != 0 {
+		t.Error("No species hops expected", hops)
+	}
+
+	if founders := tracker.Founders(3); len(founders) != 1 || founders[0] != 5 {
+		t.Error("Wrong founders of species", founders)
+	}
+	if founders := tracker.Founders(1); len(founders) != 2 {
+		t.Error("Wrong founders of initial species", founders)
+	}
+	if ids := tracker.SpeciesIds(); len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Error("Wrong species IDs", ids)
+	}
+}
+
+func TestLineageTracker_epoch(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DisjointCoeff:1.0,
+		ExcessCoeff:1.0,
+		MutdiffCoeff:0.4,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.2,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		MateMultipointProb:0.5,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	tracker := NewLineageTracker()
+	tracker.Record(0, pop)
+	pop.AddObserver(tracker)
+
+	ex := SequentialPopulationEpochExecutor{}
+	for i := 0; i < 5; i++ {
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64()
+		}
+		if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	ids := make(map[int64]bool)
+	for _, org := range pop.Organisms {
+		if ids[org.LineageId] {
+			t.Error("Lineage ID is not unique", org.LineageId)
+		}
+		ids[org.LineageId] = true
+		if len(org.ParentLineageIds) == 0 {
+			t.Error("Offspring should have parents", org.LineageId)
+		}
+		history := tracker.SpeciesHistory(org.LineageId)
+		if len(history) != 1 || history[0].Generation != 5 || history[0].SpeciesId != org.Species.Id {
+			t.Error("Wrong species history", org.LineageId, history)
+		}
+		// the lineage goes back to the initial population
+		if lineage := tracker.LineageSpeciesHistory(org.LineageId); len(lineage) != 6 || lineage[0].Generation != 0 {
+			t.Error("Wrong lineage species history", org.LineageId, lineage)
+		}
+	}
+}
+
+func TestOrganism_MarshalBinary_lineage(t *testing.T) {
+	org, err := NewOrganism(rand.Float64(), buildTestGenome(1), 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	org.ParentLineageIds = []int64{10, 20}
+
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(org); err != nil {
+		t.Error(err)
+		return
+	}
+	dec_org := Organism{}
+	if err = gob.NewDecoder(&buf).Decode(&dec_org); err != nil {
+		t.Error(err)
+		return
+	}
+	if dec_org.LineageId != org.LineageId || dec_org.LineageId == 0 {
+		t.Error("Lineage ID was not decoded", dec_org.LineageId, org.LineageId)
+	}
+	if len(dec_org.ParentLineageIds) != 2 || dec_org.ParentLineageIds[1] != 20 {
+		t.Error("Parents lineage IDs was not decoded", dec_org.ParentLineageIds)
+	}
+}
+
+func TestEncodeLineageIds(t *testing.T) {
+	for _, ids := range [][]int64{nil, {1}, {3, 1234567890123}} {
+		str := encodeLineageIds(ids)
+		dec_ids, err := decodeLineageIds(str)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if len(dec_ids) != len(ids) {
+			t.Error("Wrong decoded IDs", str, dec_ids)
+			continue
+		}
+		for i := range ids {
+			if dec_ids[i] != ids[i] {
+				t.Error("Wrong decoded ID", str, i, dec_ids[i])
+			}
+		}
+	}
+	if _, err := decodeLineageIds("1,a"); err == nil {
+		t.Error("Error expected for wrong encoding")
+	}
+}
@@ -0,0 +1,243 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The mutation operator which can be applied to genome as a stage of mutation pipeline
+type MutationOperator interface {
+	// Returns name of this operator
+	Name() string
+	// Returns true if this operator changes topology of genome
+	IsStructural() bool
+	// Applies mutation to the genome. Returns true if genome was actually mutated.
+	Mutate(g *Genome, pop *Population, context *neat.NeatContext) (bool, error)
+}
+
+// The rule to decide whether mutation stage should be considered with regard to other stages of pipeline
+type MutationStageRule int
+
+const (
+	// The stage is considered independently of other stages
+	IndependentMutationStage MutationStageRule = iota
+	// The stage is considered only if none of the preceding exclusive stages was applied, i.e. the exclusive stages
+	// of pipeline form "either A or B or C" chain
+	ExclusiveMutationStage
+	// The stage is considered only if none of the preceding exclusive stages was applied, but it doesn't prevent
+	// the following exclusive stages, i.e. it is "else" branch of the preceding exclusive stages
+	FallbackMutationStage
+)
+
+// The stage of mutation pipeline
+type MutationStage struct {
+	// The mutation operator to apply
+	Operator         MutationOperator
+	// The probability to apply mutation operator when stage is considered
+	Probability      float64
+	// The rule to consider this stage
+	Rule             MutationStageRule
+	// The flag to indicate that exclusive stage is counted as applied once selected by probability even if its
+	// operator failed to mutate genome, i.e. it prevents the following exclusive and fallback stages as in the
+	// original NEAT, where failed add node or add link mutation still skips non-structural mutations
+	ConsumeOnFailure bool
+}
+
+// The ordered list of mutation stages applied to the offspring genome during reproduction
+type MutationPipeline struct {
+	Stages []MutationStage
+}
+
+// Creates mutation pipeline with the standard NEAT mutation scheme as configured by given context: add node or add link
+// or connect sensors, and all non-structural mutations if no structural one was applied. The module and node duplications
+// are included as the last structural alternatives if their probabilities are set.
+func DefaultMutationPipeline(context *neat.NeatContext) *MutationPipeline {
+	stages := []MutationStage{
+		{Operator:AddNodeMutation, Probability:context.MutateAddNodeProb, Rule:ExclusiveMutationStage,
+			ConsumeOnFailure:true},
+		{Operator:AddLinkMutation, Probability:context.MutateAddLinkProb, Rule:ExclusiveMutationStage,
+			ConsumeOnFailure:true},
+		{Operator:ConnectSensorsMutation, Probability:context.MutateConnectSensors, Rule:ExclusiveMutationStage},
+	}
+	if context.MutateDupModuleProb > 0 {
+		stages = append(stages,
+			MutationStage{Operator:DuplicateModuleMutation, Probability:context.MutateDupModuleProb,
+				Rule:ExclusiveMutationStage})
+	}
+	if context.MutateDupNodeProb > 0 {
+		stages = append(stages,
+			MutationStage{Operator:DuplicateNodeMutation, Probability:context.MutateDupNodeProb,
+				Rule:ExclusiveMutationStage})
+	}
+	stages = append(stages, MutationStage{Operator:NonstructuralMutation, Probability:1.0, Rule:FallbackMutationStage})
+	return &MutationPipeline{Stages:stages}
+}
+
+// Applies stages of this pipeline to the genome in order. Returns true if at least one structural mutation was applied,
+// including the selected structural stages consuming exclusivity on failure.
+func (mp *MutationPipeline) Apply(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+	return mp.apply(g, pop, context, nil)
+}
+
+// Applies stages of this pipeline to the genome in order and reports the name of each mutation which actually changed
+// genome to applied function if provided. The composite operators report their constituent mutations instead.
+func (mp *MutationPipeline) apply(g *Genome, pop *Population, context *neat.NeatContext, applied func(name string)) (bool, error) {
+	structural, exclusive_applied := false, false
+	for _, stage := range mp.Stages {
+		if stage.Rule != IndependentMutationStage && exclusive_applied {
+			continue
+		}
+		if context.Rand().Float64() >= stage.Probability {
+			continue
+		}
+		neat.DebugLog(fmt.Sprintf("MUTATION PIPELINE: ---> %s", stage.Operator.Name()))
+
+		var mutated bool
+		var err error
+		if composite, ok := stage.Operator.(compositeMutationOperator); ok {
+			mutated, err = composite.mutateReporting(g, pop, context, applied)
+		} else if mutated, err = stage.Operator.Mutate(g, pop, context); err == nil && mutated && applied != nil {
+			applied(stage.Operator.Name())
+		}
+		if err != nil {
+			return false, err
+		}
+		if mutated || (stage.ConsumeOnFailure && stage.Rule == ExclusiveMutationStage) {
+			if stage.Rule == ExclusiveMutationStage {
+				exclusive_applied = true
+			}
+			if stage.Operator.IsStructural() {
+				structural = true
+			}
+		}
+	}
+	return structural, nil
+}
+
+// The mutation operator composed of several mutations, which is able to report each of them actually applied
+type compositeMutationOperator interface {
+	MutationOperator
+	// Applies mutation to the genome like Mutate and reports the name of each constituent mutation which changed
+	// genome to applied function if provided
+	mutateReporting(g *Genome, pop *Population, context *neat.NeatContext, applied func(name string)) (bool, error)
+}
+
+// The mutation operator applying all non-structural mutations with probabilities configured by context
+type nonstructuralMutationOperator struct{}
+
+func (nonstructuralMutationOperator) Name() string {
+	return "mutateAllNonstructural"
+}
+
+func (nonstructuralMutationOperator) IsStructural() bool {
+	return false
+}
+
+func (nonstructuralMutationOperator) Mutate(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+	return g.mutateAllNonstructural(context, nil)
+}
+
+func (nonstructuralMutationOperator) mutateReporting(g *Genome, pop *Population, context *neat.NeatContext, applied func(name string)) (bool, error) {
+	return g.mutateAllNonstructural(context, applied)
+}
+
+// The mutation operator backed by function
+type mutationOperator struct {
+	name       string
+	structural bool
+	mutate     func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error)
+}
+
+// Creates new mutation operator with given name which applies provided mutation function
+func NewMutationOperator(name string, structural bool,
+	mutate func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error)) MutationOperator {
+	return &mutationOperator{name:name, structural:structural, mutate:mutate}
+}
+
+func (mo *mutationOperator) Name() string {
+	return mo.name
+}
+
+func (mo *mutationOperator) IsStructural() bool {
+	return mo.structural
+}
+
+func (mo *mutationOperator) Mutate(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+	return mo.mutate(g, pop, context)
+}
+
+// The standard mutation operators
+var (
+	// Adds new node by splitting random link
+	AddNodeMutation = NewMutationOperator("mutateAddNode", true,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateAddNode(pop, context)
+		})
+	// Adds new link between existing nodes
+	AddLinkMutation = NewMutationOperator("mutateAddLink", true,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateAddLink(pop, context)
+		})
+	// Connects unconnected sensors to the outputs
+	ConnectSensorsMutation = NewMutationOperator("mutateConnectSensors", true,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateConnectSensors(pop, context)
+		})
+	// Duplicates sub-network (module) of genome
+	DuplicateModuleMutation = NewMutationOperator("mutateDuplicateModule", true,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateDuplicateModule(pop, context)
+		})
+	// Duplicates hidden node with all its connections
+	DuplicateNodeMutation = NewMutationOperator("mutateDuplicateNode", true,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateDuplicateNode(pop, context)
+		})
+	// Removes random link
+	DeleteLinkMutation = NewMutationOperator("mutateDeleteLink", true,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateDeleteLink(context.Rand())
+		})
+	// Perturbs weights of all links
+	LinkWeightsMutation = NewMutationOperator("mutateLinkWeights", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateLinkWeightsForContext(context)
+		})
+	// Toggles enabled status of random link
+	ToggleEnableMutation = NewMutationOperator("mutateToggleEnable", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateToggleEnable(1, context.Rand())
+		})
+	// Re-enables first disabled link
+	GeneReenableMutation = NewMutationOperator("mutateGeneReenable", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateGeneReenable()
+		})
+	// Perturbs initial activations of neurons
+	InitialActivationMutation = NewMutationOperator("mutateInitialActivations", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateInitialActivations(context.WeightMutPower, context.Rand())
+		})
+	// Changes delay of random link by one activation step
+	LinkDelayMutation = NewMutationOperator("mutateLinkDelay", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateLinkDelay(context.MaxLinkDelay, context.Rand())
+		})
+	// Adds or removes gating node of random link
+	LinkGateMutation = NewMutationOperator("mutateLinkGate", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateLinkGate(context)
+		})
+	// Turns random hidden neuron into memory cell or memory cell back into ordinary neuron
+	MemoryNodeMutation = NewMutationOperator("mutateMemoryNode", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateMemoryNode(context.Rand())
+		})
+	// Perturbs forget gates of memory cells
+	MemoryRetentionMutation = NewMutationOperator("mutateMemoryRetention", false,
+		func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+			return g.mutateMemoryRetention(context.WeightMutPower, context.Rand())
+		})
+	// Applies all non-structural mutations with probabilities configured by context
+	NonstructuralMutation MutationOperator = nonstructuralMutationOperator{}
+)
@@ -0,0 +1,254 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// Creates mutation operator which records its invocations into provided list
+func recordingOperator(name string, structural, result bool, calls *[]string) MutationOperator {
+	return NewMutationOperator(name, structural, func(g *Genome, pop *Population, context *neat.NeatContext) (bool, error) {
+		*calls = append(*calls, name)
+		return result, nil
+	})
+}
+
+func TestMutationPipeline_Apply(t *testing.T) {
+	rand.Seed(42)
+	calls := make([]string, 0)
+	// add node OR add link OR delete link, else weights
+	pipeline := &MutationPipeline{
+		Stages:[]MutationStage{
+			{Operator:recordingOperator("node", true, false, &calls), Probability:1.0, Rule:ExclusiveMutationStage},
+			{Operator:recordingOperator("link", true, true, &calls), Probability:1.0, Rule:ExclusiveMutationStage},
+			{Operator:recordingOperator("delete", true, true, &calls), Probability:1.0, Rule:ExclusiveMutationStage},
+			{Operator:recordingOperator("weights", false, true, &calls), Probability:1.0, Rule:FallbackMutationStage},
+			{Operator:recordingOperator("never", false, true, &calls), Probability:0.0, Rule:IndependentMutationStage},
+			{Operator:recordingOperator("trait", false, true, &calls), Probability:1.0, Rule:IndependentMutationStage},
+		},
+	}
+	structural, err := pipeline.Apply(buildTestGenome(1), nil, &neat.NeatContext{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !structural {
+		t.Error("Structural mutation expected")
+	}
+	// the failed node mutation should not prevent the link mutation
+	expected := []string{"node", "link", "trait"}
+	if len(calls) != len(expected) {
+		t.Error("Wrong stages applied", calls)
+		return
+	}
+	for i, c := range expected {
+		if calls[i] != c {
+			t.Error("Wrong stages applied", calls)
+		}
+	}
+
+	// the fallback applied when none of exclusive stages was applied
+	calls = calls[:0]
+	pipeline.Stages[1].Probability = 0.0
+	pipeline.Stages[2].Probability = 0.0
+	if structural, err = pipeline.Apply(buildTestGenome(1), nil, &neat.NeatContext{}); err != nil {
+		t.Error(err)
+		return
+	}
+	if structural {
+		t.Error("No structural mutation expected")
+	}
+	if len(calls) != 3 || calls[1] != "weights" {
+		t.Error("The fallback stage should be applied", calls)
+	}
+}
+
+func TestDefaultMutationPipeline(t *testing.T) {
+	conf := neat.NeatContext{MutateAddNodeProb:0.03, MutateAddLinkProb:0.08, MutateConnectSensors:0.5}
+	pipeline := DefaultMutationPipeline(&conf)
+	expected := []MutationStage{
+		{Operator:AddNodeMutation, Probability:0.03, Rule:ExclusiveMutationStage, ConsumeOnFailure:true},
+		{Operator:AddLinkMutation, Probability:0.08, Rule:ExclusiveMutationStage, ConsumeOnFailure:true},
+		{Operator:ConnectSensorsMutation, Probability:0.5, Rule:ExclusiveMutationStage},
+		{Operator:NonstructuralMutation, Probability:1.0, Rule:FallbackMutationStage},
+	}
+	if len(pipeline.Stages) != len(expected) {
+		t.Error("Wrong number of stages", len(pipeline.Stages))
+		return
+	}
+	for i, st := range expected {
+		if pipeline.Stages[i] != st {
+			t.Error("Wrong stage", i, pipeline.Stages[i].Operator.Name())
+		}
+	}
+}
+
+func TestMutationPipeline_Apply_consumeOnFailure(t *testing.T) {
+	calls := make([]string, 0)
+	pipeline := &MutationPipeline{
+		Stages:[]MutationStage{
+			{Operator:recordingOperator("node", true, false, &calls), Probability:1.0, Rule:ExclusiveMutationStage,
+				ConsumeOnFailure:true},
+			{Operator:recordingOperator("link", true, true, &calls), Probability:1.0, Rule:ExclusiveMutationStage},
+			{Operator:recordingOperator("weights", false, true, &calls), Probability:1.0, Rule:FallbackMutationStage},
+			{Operator:recordingOperator("trait", false, true, &calls), Probability:1.0, Rule:IndependentMutationStage},
+		},
+	}
+	structural, err := pipeline.Apply(buildTestGenome(1), nil, &neat.NeatContext{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the failed node mutation should be counted as structural and prevent link and fallback mutations
+	if !structural {
+		t.Error("Structural mutation expected")
+	}
+	if len(calls) != 2 || calls[0] != "node" || calls[1] != "trait" {
+		t.Error("Wrong stages applied", calls)
+	}
+}
+
+func TestDefaultMutationPipeline_failedStructural(t *testing.T) {
+	// the selected structural mutation which failed to change genome should still make baby structural and skip the
+	// following mutations as in the original NEAT
+	for i, conf := range []neat.NeatContext{
+		{MutateAddNodeProb:1.0, MutateAddLinkProb:1.0, MutateLinkWeightsProb:1.0, WeightMutPower:2.5},
+		{MutateAddLinkProb:1.0, MutateConnectSensors:1.0, MutateLinkWeightsProb:1.0, WeightMutPower:2.5},
+	} {
+		calls := make([]string, 0)
+		gnome := buildTestGenome(1)
+		if i == 0 {
+			// no enabled link to split with new node
+			for _, gene := range gnome.Genes {
+				gene.IsEnabled = false
+			}
+		}
+		// no tries to find new link (NewLinkTries is zero)
+		structural, err := DefaultMutationPipeline(&conf).apply(gnome, nil, &conf, func(name string) {
+			calls = append(calls, name)
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !structural {
+			t.Error("The failed structural mutation should mark baby as structural", i)
+		}
+		if len(calls) != 0 {
+			t.Error("No mutations should be applied after failed structural mutation", i, calls)
+		}
+	}
+}
+
+func TestDefaultMutationPipeline_duplicateModule(t *testing.T) {
+	conf := neat.NeatContext{MutateAddNodeProb:0.03, MutateAddLinkProb:0.08, MutateDupModuleProb:0.01}
+	pipeline := DefaultMutationPipeline(&conf)
+	if len(pipeline.Stages) != 5 {
+		t.Error("Wrong number of stages", len(pipeline.Stages))
+		return
+	}
+	expected := MutationStage{Operator:DuplicateModuleMutation, Probability:0.01, Rule:ExclusiveMutationStage}
+	if pipeline.Stages[3] != expected {
+		t.Error("Wrong module duplication stage", pipeline.Stages[3].Operator.Name())
+	}
+	if !DuplicateModuleMutation.IsStructural() {
+		t.Error("Module duplication should be structural")
+	}
+}
+
+func TestDefaultMutationPipeline_duplicateNode(t *testing.T) {
+	conf := neat.NeatContext{MutateAddNodeProb:0.03, MutateAddLinkProb:0.08, MutateDupModuleProb:0.01,
+		MutateDupNodeProb:0.02}
+	pipeline := DefaultMutationPipeline(&conf)
+	if len(pipeline.Stages) != 6 {
+		t.Error("Wrong number of stages", len(pipeline.Stages))
+		return
+	}
+	expected := MutationStage{Operator:DuplicateNodeMutation, Probability:0.02, Rule:ExclusiveMutationStage}
+	if pipeline.Stages[4] != expected {
+		t.Error("Wrong node duplication stage", pipeline.Stages[4].Operator.Name())
+	}
+	if !DuplicateNodeMutation.IsStructural() || DuplicateNodeMutation.Name() != "mutateDuplicateNode" {
+		t.Error("Wrong node duplication operator")
+	}
+}
+
+func TestSpecies_reproduce_mutationPipeline(t *testing.T) {
+	rand.Seed(42)
+	in, out, nmax, n := 3, 2, 15, 3
+
+	// Configuration
+	conf := neat.NeatContext {
+		DropOffAge:5,
+		SurvivalThresh:0.5,
+		AgeSignificance:0.5,
+		PopSize:30,
+		CompatThreshold:0.6,
+		MutateOnlyProb:1.0,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+
+	gen := newGenomeRand(1, in, out, n, nmax, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pop.MutationPipeline = &MutationPipeline{
+		Stages:[]MutationStage{
+			{Operator:DeleteLinkMutation, Probability:1.0, Rule:ExclusiveMutationStage},
+		},
+	}
+
+	sorted_species := make([]*Species, len(pop.Species))
+	copy(sorted_species, pop.Species)
+	sort.Sort(byOrganismOrigFitness{sorted_species, MaximizeObjective})
+
+	sp := pop.Species[0]
+	sp.ExpectedOffspring = 5
+	babies, err := sp.reproduce(1, pop, sorted_species, &conf)
+	if err != nil {
+		t.Error("err != nil", err)
+		return
+	}
+	parent_genes := len(sp.Organisms[0].Genotype.Genes)
+	mutated := 0
+	for _, baby := range babies {
+		if baby.mutationStructBaby {
+			mutated++
+			if len(baby.Genotype.Genes) >= parent_genes {
+				t.Error("Baby should have link deleted", baby.Genotype)
+			}
+		}
+	}
+	if mutated == 0 {
+		t.Error("The custom mutation pipeline was not applied")
+	}
+}
+
+func TestMutationPipeline_apply_reporting(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	calls := make([]string, 0)
+	pipeline := &MutationPipeline{
+		Stages:[]MutationStage{
+			{Operator:recordingOperator("custom", false, true, &calls), Probability:1.0, Rule:IndependentMutationStage},
+			{Operator:recordingOperator("unchanged", false, false, &calls), Probability:1.0, Rule:IndependentMutationStage},
+			{Operator:NonstructuralMutation, Probability:1.0, Rule:IndependentMutationStage},
+		},
+	}
+	conf := neat.NeatContext{MutateLinkWeightsProb:1.0, WeightMutPower:0.5}
+	applied := make([]string, 0)
+	if _, err := pipeline.apply(gnome, nil, &conf, func(name string) {
+		applied = append(applied, name)
+	}); err != nil {
+		t.Error(err)
+		return
+	}
+	// only actually applied mutations reported and the composite operator reports its constituent mutations
+	if len(applied) != 2 || applied[0] != "custom" || applied[1] != LinkWeightsMutation.Name() {
+		t.Error("Wrong applied mutations reported", applied)
+	}
+}
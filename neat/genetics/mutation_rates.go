@@ -0,0 +1,74 @@
+package genetics
+
+import (
+	"math"
+	"math/rand"
+	"fmt"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The mutation rates carried by species when adaptive mutation is enabled. These rates replace the global mutation
+// probabilities and power of the context during reproduction of species and themselves mutated each generation.
+type MutationRates struct {
+	// The probability of adding new node
+	AddNodeProb      float64
+	// The probability of adding new link between nodes
+	AddLinkProb      float64
+	// The probability of link weight value mutation
+	LinkWeightsProb  float64
+	// The probability of enabling/disabling of specific link/gene
+	ToggleEnableProb float64
+	// The probability of finding the first disabled gene and re-enabling it
+	GeneReenableProb float64
+	// The power of link's weight mutation
+	WeightMutPower   float64
+}
+
+// Creates new mutation rates initialized with global mutation probabilities of given context
+func NewMutationRates(context *neat.NeatContext) *MutationRates {
+	return &MutationRates{
+		AddNodeProb:context.MutateAddNodeProb,
+		AddLinkProb:context.MutateAddLinkProb,
+		LinkWeightsProb:context.MutateLinkWeightsProb,
+		ToggleEnableProb:context.MutateToggleEnableProb,
+		GeneReenableProb:context.MutateGeneReenableProb,
+		WeightMutPower:context.WeightMutPower,
+	}
+}
+
+// Returns deep copy of this mutation rates
+func (r *MutationRates) copy() *MutationRates {
+	c := *r
+	return &c
+}
+
+// Mutates rates by multiplying each of them with log-normally distributed random factor with given power. The
+// probabilities are kept within [0, 1] range.
+func (r *MutationRates) mutate(power float64, rng *rand.Rand) {
+	perturb := func(value float64) float64 {
+		return value * math.Exp(power * rng.NormFloat64())
+	}
+	r.AddNodeProb = math.Min(perturb(r.AddNodeProb), 1.0)
+	r.AddLinkProb = math.Min(perturb(r.AddLinkProb), 1.0)
+	r.LinkWeightsProb = math.Min(perturb(r.LinkWeightsProb), 1.0)
+	r.ToggleEnableProb = math.Min(perturb(r.ToggleEnableProb), 1.0)
+	r.GeneReenableProb = math.Min(perturb(r.GeneReenableProb), 1.0)
+	r.WeightMutPower = perturb(r.WeightMutPower)
+}
+
+// Returns copy of given context with global mutation probabilities replaced by this rates
+func (r *MutationRates) applyTo(context *neat.NeatContext) *neat.NeatContext {
+	c := *context
+	c.MutateAddNodeProb = r.AddNodeProb
+	c.MutateAddLinkProb = r.AddLinkProb
+	c.MutateLinkWeightsProb = r.LinkWeightsProb
+	c.MutateToggleEnableProb = r.ToggleEnableProb
+	c.MutateGeneReenableProb = r.GeneReenableProb
+	c.WeightMutPower = r.WeightMutPower
+	return &c
+}
+
+func (r *MutationRates) String() string {
+	return fmt.Sprintf("add_node: %.3f, add_link: %.3f, link_weights: %.3f, toggle_enable: %.3f, gene_reenable: %.3f, weight_power: %.3f",
+		r.AddNodeProb, r.AddLinkProb, r.LinkWeightsProb, r.ToggleEnableProb, r.GeneReenableProb, r.WeightMutPower)
+}
@@ -0,0 +1,77 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestNewMutationRates(t *testing.T) {
+	context := neat.NeatContext{
+		MutateAddNodeProb:0.03,
+		MutateAddLinkProb:0.08,
+		MutateLinkWeightsProb:0.9,
+		MutateToggleEnableProb:0.1,
+		MutateGeneReenableProb:0.05,
+		WeightMutPower:2.5,
+	}
+	rates := NewMutationRates(&context)
+	if rates.AddNodeProb != 0.03 || rates.AddLinkProb != 0.08 || rates.LinkWeightsProb != 0.9 ||
+		rates.ToggleEnableProb != 0.1 || rates.GeneReenableProb != 0.05 || rates.WeightMutPower != 2.5 {
+		t.Error("Wrong rates initialized from context", rates)
+	}
+}
+
+func TestMutationRates_mutate(t *testing.T) {
+	rand.Seed(42)
+	rates := &MutationRates{
+		AddNodeProb:0.5,
+		AddLinkProb:0.9,
+		LinkWeightsProb:1.0,
+		ToggleEnableProb:0.0,
+		GeneReenableProb:0.2,
+		WeightMutPower:2.5,
+	}
+	orig := rates.copy()
+	for i := 0; i < 100; i++ {
+		rates.mutate(0.5, utils.GlobalRand())
+		for _, p := range []float64{rates.AddNodeProb, rates.AddLinkProb, rates.LinkWeightsProb,
+			rates.ToggleEnableProb, rates.GeneReenableProb} {
+			if p < 0 || p > 1 {
+				t.Error("Probability out of range", p)
+			}
+		}
+		if rates.WeightMutPower <= 0 {
+			t.Error("Wrong weight mutation power", rates.WeightMutPower)
+		}
+	}
+	if rates.ToggleEnableProb != 0 {
+		t.Error("Zero probability should stay zero", rates.ToggleEnableProb)
+	}
+	if *rates == *orig {
+		t.Error("Rates was not mutated")
+	}
+	if orig.AddNodeProb != 0.5 {
+		t.Error("Copy of rates was changed", orig.AddNodeProb)
+	}
+}
+
+func TestMutationRates_applyTo(t *testing.T) {
+	context := neat.NeatContext{
+		MutateAddNodeProb:0.03,
+		WeightMutPower:2.5,
+		PopSize:150,
+	}
+	rates := &MutationRates{AddNodeProb:0.5, WeightMutPower:1.0}
+	applied := rates.applyTo(&context)
+	if applied.MutateAddNodeProb != 0.5 || applied.WeightMutPower != 1.0 {
+		t.Error("Rates was not applied", applied.MutateAddNodeProb, applied.WeightMutPower)
+	}
+	if applied.PopSize != 150 {
+		t.Error("Other parameters should be preserved", applied.PopSize)
+	}
+	if context.MutateAddNodeProb != 0.03 || context.WeightMutPower != 2.5 {
+		t.Error("Original context was changed")
+	}
+}
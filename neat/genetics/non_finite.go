@@ -0,0 +1,132 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// Invokes provided function for each numeric value of genetic information which affects phenotype or its further
+// mutations, i.e. link weights, mutation numbers, self-adapted mutation sigmas and parameters of traits and nodes.
+// The bounds to clamp link weights are taken from context if configured.
+func (g *Genome) visitNumericValues(context *neat.NeatContext, visit func(v *float64, min, max float64)) {
+	w_min, w_max := -network.MaxFiniteValue, network.MaxFiniteValue
+	if context.WeightMin < context.WeightMax {
+		w_min, w_max = context.WeightMin, context.WeightMax
+	}
+	for _, gene := range g.Genes {
+		visit(&gene.Link.Weight, w_min, w_max)
+		visit(&gene.MutationNum, w_min, w_max)
+		// the zero sigma will be re-initialized by the next adaptive perturbation
+		visit(&gene.mutationSigma, 0.0, 0.0)
+	}
+	for _, tr := range g.Traits {
+		for i := range tr.Params {
+			visit(&tr.Params[i], -network.MaxFiniteValue, network.MaxFiniteValue)
+		}
+	}
+	for _, n := range g.Nodes {
+		for i := range n.Params {
+			visit(&n.Params[i], -network.MaxFiniteValue, network.MaxFiniteValue)
+		}
+	}
+}
+
+// Returns the number of numeric values of this genome which are not finite numbers, i.e. NaN or infinity
+func (g *Genome) nonFiniteCount(context *neat.NeatContext) int {
+	count := 0
+	g.visitNumericValues(context, func(v *float64, min, max float64) {
+		if !network.IsFinite(*v) {
+			count++
+		}
+	})
+	return count
+}
+
+// Checks that numeric values of this genome are finite numbers and handles the non-finite ones according to the policy
+// configured by context. The ignore policy keeps genome as is, the clamp policy replaces NaN by zero and infinite values
+// by weight bounds (if configured). Returns true if genome should be rejected according to reject policy or error if
+// non-finite values are not allowed.
+func (g *Genome) guardNonFinite(context *neat.NeatContext) (bool, error) {
+	policy := network.NonFinitePolicy(context.NonFinitePolicy)
+	if policy == network.IgnoreNonFinite {
+		return false, nil
+	}
+	count := g.nonFiniteCount(context)
+	if count == 0 {
+		return false, nil
+	}
+	switch policy {
+	case network.ClampNonFinite:
+		g.visitNumericValues(context, func(v *float64, min, max float64) {
+			*v = network.ClampToFinite(*v, min, max)
+		})
+		g.invalidatePhenotype()
+		return false, nil
+	case network.RejectNonFinite:
+		return true, nil
+	case network.ErrorOnNonFinite:
+		return false, newError(ErrNonFiniteValues,
+			"GENOME: Genome [%d] has %d values which are not finite numbers", g.Id, count)
+	default:
+		return false, newError(ErrUnsupportedType,
+			"GENOME: Unsupported non-finite values policy: %d", context.NonFinitePolicy)
+	}
+}
+
+// Guards provided offspring genome against numeric values which are not finite numbers. If offspring genome rejected,
+// it is replaced by duplicate of parent genome with the same ID. Returns the genome to be used by offspring.
+func guardOffspringGenome(offspring, parent *Genome, context *neat.NeatContext) (*Genome, error) {
+	rejected, err := offspring.guardNonFinite(context)
+	if err != nil {
+		return nil, err
+	} else if !rejected {
+		return offspring, nil
+	}
+	neat.WarnLog(fmt.Sprintf("GENOME: Offspring genome [%d] with values which are not finite numbers rejected, " +
+		"replaced by duplicate of parent genome [%d]\n", offspring.Id, parent.Id))
+	replacement, err := parent.cloneWithId(offspring.Id)
+	if err != nil {
+		return nil, err
+	}
+	if rejected, err = replacement.guardNonFinite(context); err != nil {
+		return nil, err
+	} else if rejected {
+		return nil, newError(ErrNonFiniteValues,
+			"GENOME: Parent genome [%d] has values which are not finite numbers", parent.Id)
+	}
+	return replacement, nil
+}
+
+// Checks that fitness of given organisms is finite number and handles the non-finite fitness according to the policy
+// configured by context. The ignore policy keeps fitness as is, the clamp policy replaces NaN by zero and infinite
+// values by the maximal finite value of the same sign. The reject policy assigns the rejected fitness of configured
+// objective direction (see ObjectiveDirection.RejectedFitness) making organism to be eliminated by selection.
+func guardFitness(organisms Organisms, context *neat.NeatContext) error {
+	policy := network.NonFinitePolicy(context.NonFinitePolicy)
+	if policy == network.IgnoreNonFinite {
+		return nil
+	}
+	for _, org := range organisms {
+		if network.IsFinite(org.Fitness) {
+			continue
+		}
+		switch policy {
+		case network.ClampNonFinite:
+			org.Fitness = network.ClampToFinite(org.Fitness, -network.MaxFiniteValue, network.MaxFiniteValue)
+		case network.RejectNonFinite:
+			neat.WarnLog(fmt.Sprintf("POPULATION: Organism [%d] with fitness %f rejected\n",
+				org.Genotype.Id, org.Fitness))
+			org.Fitness = objectiveDirection(context).RejectedFitness()
+			org.IsWinner = false
+		case network.ErrorOnNonFinite:
+			return newError(ErrNonFiniteValues, "POPULATION: Organism [%d] has fitness which is not finite number: %f",
+				org.Genotype.Id, org.Fitness)
+		default:
+			return newError(ErrUnsupportedType,
+				"POPULATION: Unsupported non-finite values policy: %d", context.NonFinitePolicy)
+		}
+	}
+	return nil
+}
+
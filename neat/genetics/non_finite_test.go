@@ -0,0 +1,209 @@
+package genetics
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Builds test genome with infinite and NaN link weights
+func buildNonFiniteGenome(id int) *Genome {
+	gnome := buildTestGenome(id)
+	gnome.Genes[0].Link.Weight = math.Inf(1)
+	gnome.Genes[1].Link.Weight = math.NaN()
+	gnome.Traits[0].Params[1] = math.Inf(-1)
+	return gnome
+}
+
+func TestGenome_nonFiniteCount(t *testing.T) {
+	conf := neat.NeatContext{}
+	if count := buildTestGenome(1).nonFiniteCount(&conf); count != 0 {
+		t.Error("No non-finite values expected", count)
+	}
+	if count := buildNonFiniteGenome(1).nonFiniteCount(&conf); count != 3 {
+		t.Error("Wrong number of non-finite values", count)
+	}
+}
+
+func TestGenome_guardNonFinite_ignore(t *testing.T) {
+	// the genome is kept as is by default
+	gnome := buildNonFiniteGenome(1)
+	conf := neat.NeatContext{}
+	if rejected, err := gnome.guardNonFinite(&conf); err != nil || rejected {
+		t.Error("Genome should be kept", rejected, err)
+	}
+	if count := gnome.nonFiniteCount(&conf); count != 3 {
+		t.Error("The non-finite values should be kept", count)
+	}
+}
+
+func TestGenome_guardNonFinite_clamp(t *testing.T) {
+	gnome := buildNonFiniteGenome(1)
+	conf := neat.NeatContext{NonFinitePolicy:int(network.ClampNonFinite), WeightMin:-8.0, WeightMax:8.0}
+	if rejected, err := gnome.guardNonFinite(&conf); err != nil || rejected {
+		t.Error("Genome should be clamped", rejected, err)
+	}
+	if w := gnome.Genes[0].Link.Weight; w != conf.WeightMax {
+		t.Error("Infinite weight should be clamped to upper bound", w)
+	}
+	if w := gnome.Genes[1].Link.Weight; w != 0 {
+		t.Error("NaN weight should be replaced by zero", w)
+	}
+	if w := gnome.Genes[2].Link.Weight; w != 3.5 {
+		t.Error("Finite weight should not be changed", w)
+	}
+	if p := gnome.Traits[0].Params[1]; p != -network.MaxFiniteValue {
+		t.Error("Infinite trait parameter should be clamped", p)
+	}
+	if count := gnome.nonFiniteCount(&conf); count != 0 {
+		t.Error("All values should be finite after clamping", count)
+	}
+}
+
+func TestGenome_guardNonFinite_rejectAndError(t *testing.T) {
+	conf := neat.NeatContext{NonFinitePolicy:int(network.RejectNonFinite)}
+	if rejected, err := buildNonFiniteGenome(1).guardNonFinite(&conf); err != nil || !rejected {
+		t.Error("Genome should be rejected", rejected, err)
+	}
+	if rejected, err := buildTestGenome(1).guardNonFinite(&conf); err != nil || rejected {
+		t.Error("Finite genome should not be rejected", rejected, err)
+	}
+
+	conf.NonFinitePolicy = int(network.ErrorOnNonFinite)
+	if _, err := buildNonFiniteGenome(1).guardNonFinite(&conf); err == nil {
+		t.Error("Error expected")
+	}
+
+	conf.NonFinitePolicy = 100
+	if _, err := buildNonFiniteGenome(1).guardNonFinite(&conf); err == nil {
+		t.Error("Error expected for unsupported policy")
+	}
+}
+
+func TestGuardOffspringGenome(t *testing.T) {
+	parent := buildTestGenome(1)
+	conf := neat.NeatContext{NonFinitePolicy:int(network.RejectNonFinite)}
+	offspring, err := guardOffspringGenome(buildNonFiniteGenome(2), parent, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if offspring.Id != 2 {
+		t.Error("Replacement should keep offspring ID", offspring.Id)
+	}
+	for i, gene := range offspring.Genes {
+		if gene.Link.Weight != parent.Genes[i].Link.Weight {
+			t.Error("Replacement should be duplicate of parent", i, gene.Link.Weight)
+		}
+	}
+
+	if _, err = guardOffspringGenome(buildNonFiniteGenome(2), buildNonFiniteGenome(1), &conf); err == nil {
+		t.Error("Error expected when both offspring and parent rejected")
+	}
+}
+
+// Tests that infinite weights of parents produce NaN in averaged offspring which then caught by guard
+func TestGenome_mateMultipointAvg_nonFinite(t *testing.T) {
+	gnome1, gnome2 := buildTestGenome(1), buildTestGenome(2)
+	gnome1.Genes[0].Link.Weight = math.Inf(1)
+	gnome2.Genes[0].Link.Weight = math.Inf(-1)
+	conf := neat.NeatContext{NonFinitePolicy:int(network.ClampNonFinite)}
+
+	child, err := gnome1.mateMultipointAvg(gnome2, 3, 1.0, 1.0, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if child.nonFiniteCount(&conf) == 0 {
+		t.Error("Non-finite weights expected in offspring of parents with infinite weights")
+	}
+	if child, err = guardOffspringGenome(child, gnome1, &conf); err != nil {
+		t.Error(err)
+	} else if count := child.nonFiniteCount(&conf); count != 0 {
+		t.Error("Offspring should have only finite values after guard", count)
+	}
+}
+
+// Tests that NaN weight is not fixed by mutation and bounding, thus should be caught by guard
+func TestGenome_mutateLinkWeights_nonFinite(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes[0].Link.Weight = math.NaN()
+	conf := neat.NeatContext{NonFinitePolicy:int(network.ErrorOnNonFinite), WeightMin:-8.0, WeightMax:8.0}
+
+	if _, err := gnome.mutateLinkWeights(0.5, 1.0, gaussianMutator, GaussianWeightPerturbation, utils.GlobalRand()); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := gnome.boundLinkWeights(&conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if !math.IsNaN(gnome.Genes[0].Link.Weight) {
+		t.Error("NaN weight expected to survive mutation", gnome.Genes[0].Link.Weight)
+	}
+	if _, err := guardOffspringGenome(gnome, buildTestGenome(2), &conf); err == nil {
+		t.Error("Error expected")
+	}
+}
+
+func TestGuardFitness(t *testing.T) {
+	build := func() Organisms {
+		orgs := buildOrganismsWithFitness(1.0, math.NaN(), math.Inf(1), math.Inf(-1))
+		for i, org := range orgs {
+			org.Genotype = buildTestGenome(i)
+		}
+		return orgs
+	}
+
+	// the fitness is kept as is by default
+	orgs := build()
+	conf := neat.NeatContext{}
+	if err := guardFitness(orgs, &conf); err != nil {
+		t.Error(err)
+	}
+	if !math.IsNaN(orgs[1].Fitness) || !math.IsInf(orgs[2].Fitness, 1) {
+		t.Error("The non-finite fitness should be kept", orgs[1].Fitness, orgs[2].Fitness)
+	}
+
+	orgs = build()
+	conf.NonFinitePolicy = int(network.ClampNonFinite)
+	if err := guardFitness(orgs, &conf); err != nil {
+		t.Error(err)
+	}
+	expected := []float64{1.0, 0.0, network.MaxFiniteValue, -network.MaxFiniteValue}
+	for i, org := range orgs {
+		if org.Fitness != expected[i] {
+			t.Error("Wrong clamped fitness at:", i, org.Fitness)
+		}
+	}
+
+	orgs = build()
+	conf.NonFinitePolicy = int(network.RejectNonFinite)
+	if err := guardFitness(orgs, &conf); err != nil {
+		t.Error(err)
+	}
+	expected = []float64{1.0, 0.0, 0.0, 0.0}
+	for i, org := range orgs {
+		if org.Fitness != expected[i] {
+			t.Error("Wrong fitness of rejected organism at:", i, org.Fitness)
+		}
+	}
+	orgs = build()
+	conf.ObjectiveDirection = int(MinimizeObjective)
+	if err := guardFitness(orgs, &conf); err != nil {
+		t.Error(err)
+	}
+	if orgs[0].Fitness != 1.0 || orgs[1].Fitness != network.MaxFiniteValue {
+		t.Error("The worst fitness of rejected organism expected for minimized objective", orgs[1].Fitness)
+	}
+
+	conf.NonFinitePolicy = int(network.ErrorOnNonFinite)
+	if err := guardFitness(build(), &conf); err == nil {
+		t.Error("Error expected")
+	}
+	if err := guardFitness(buildOrganismsWithFitness(1.0, 2.0), &conf); err != nil {
+		t.Error("No error expected for finite fitness", err)
+	}
+}
@@ -0,0 +1,66 @@
+package genetics
+
+import (
+	"math"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The objective direction type definition, i.e. whether higher or lower fitness of organism is better
+type ObjectiveDirection int
+
+const (
+	// The higher fitness is better as in the original NEAT
+	MaximizeObjective ObjectiveDirection = iota
+	// The lower fitness is better, e.g. the error or cost is minimized. The fitness is set by evaluators as is, i.e.
+	// without inversion, and the sorting, selection, champion tracking and statistics take the direction into account.
+	MinimizeObjective
+)
+
+// Returns the objective direction configured by context
+func objectiveDirection(context *neat.NeatContext) ObjectiveDirection {
+	return ObjectiveDirection(context.ObjectiveDirection)
+}
+
+// Returns true if fitness a is better than fitness b in this objective direction
+func (d ObjectiveDirection) IsBetter(a, b float64) bool {
+	if d == MinimizeObjective {
+		return a < b
+	}
+	return a > b
+}
+
+// Returns true if fitness beats the record fitness in this objective direction. The record fitness is negative
+// infinity if not set yet (see Population.HighestFitness and Species.MaxFitnessEver), thus any fitness beats it.
+func (d ObjectiveDirection) IsRecord(fitness, record float64) bool {
+	return math.IsInf(record, -1) || d.IsBetter(fitness, record)
+}
+
+// Returns the most fit organism among provided ones in this objective direction with ties broken like in ByFitness,
+// or nil if organisms list is empty
+func (d ObjectiveDirection) BestOrganism(organisms Organisms) *Organism {
+	var best *Organism
+	for _, org := range organisms {
+		if best == nil || lessByFitness(d.maximized(best.Fitness), d.maximized(org.Fitness), best, org) {
+			best = org
+		}
+	}
+	return best
+}
+
+// Returns the fitness assigned to organisms rejected due to evaluation errors or non-finite fitness: zero for
+// maximized objective as in the original NEAT and the maximal finite value for minimized objective
+func (d ObjectiveDirection) RejectedFitness() float64 {
+	if d == MinimizeObjective {
+		return network.MaxFiniteValue
+	}
+	return 0.0
+}
+
+// Returns fitness oriented so that higher value is better, i.e. the negated fitness for minimized objective
+func (d ObjectiveDirection) maximized(fitness float64) float64 {
+	if d == MinimizeObjective {
+		return -fitness
+	}
+	return fitness
+}
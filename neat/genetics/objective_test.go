@@ -0,0 +1,74 @@
+package genetics
+
+import (
+	"testing"
+	"math"
+	"sort"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+func TestObjectiveDirection_IsBetter(t *testing.T) {
+	if !MaximizeObjective.IsBetter(2.0, 1.0) || MaximizeObjective.IsBetter(1.0, 2.0) {
+		t.Error("The higher fitness is better for maximized objective")
+	}
+	if !MinimizeObjective.IsBetter(1.0, 2.0) || MinimizeObjective.IsBetter(2.0, 1.0) {
+		t.Error("The lower fitness is better for minimized objective")
+	}
+	if MaximizeObjective.IsBetter(1.0, 1.0) || MinimizeObjective.IsBetter(1.0, 1.0) {
+		t.Error("The equal fitness is not better")
+	}
+}
+
+func TestObjectiveDirection_IsRecord(t *testing.T) {
+	for _, direction := range []ObjectiveDirection{MaximizeObjective, MinimizeObjective} {
+		if !direction.IsRecord(-100.0, math.Inf(-1)) || !direction.IsRecord(100.0, math.Inf(-1)) {
+			t.Error("Any fitness beats record which is not set", direction)
+		}
+	}
+	if !MinimizeObjective.IsRecord(0.5, 1.0) || MinimizeObjective.IsRecord(1.5, 1.0) {
+		t.Error("The lower fitness is record for minimized objective")
+	}
+	if !MaximizeObjective.IsRecord(1.5, 1.0) || MaximizeObjective.IsRecord(0.5, 1.0) {
+		t.Error("The higher fitness is record for maximized objective")
+	}
+}
+
+func TestObjectiveDirection_BestOrganism(t *testing.T) {
+	orgs := buildOrganismsForSorting(0.5, 0.1, 0.9, 0.1)
+	if best := MaximizeObjective.BestOrganism(orgs); best != orgs[2] {
+		t.Error("The organism with the highest fitness expected", best.Fitness)
+	}
+	// the tie is broken in favor of the most recent organism with equal complexity
+	if best := MinimizeObjective.BestOrganism(orgs); best != orgs[3] {
+		t.Error("The organism with the lowest fitness expected", best.Genotype.Id)
+	}
+	if best := MinimizeObjective.BestOrganism(nil); best != nil {
+		t.Error("No organism expected", best)
+	}
+}
+
+func TestObjectiveDirection_RejectedFitness(t *testing.T) {
+	if f := MaximizeObjective.RejectedFitness(); f != 0.0 {
+		t.Error("Zero fitness expected", f)
+	}
+	if f := MinimizeObjective.RejectedFitness(); f != network.MaxFiniteValue {
+		t.Error("Maximal finite fitness expected", f)
+	}
+}
+
+func TestByOrganismOrigFitness_minimize(t *testing.T) {
+	species := make([]*Species, 3)
+	for i, f := range []float64{0.5, 0.1, 0.9} {
+		species[i] = NewSpecies(i + 1)
+		org := buildOrganismsForSorting(f)[0]
+		org.Genotype.Id = i + 1
+		species[i].addOrganism(org)
+	}
+	sort.Stable(sort.Reverse(byOrganismOrigFitness{species, MinimizeObjective}))
+	if species[0].Id != 2 || species[1].Id != 1 || species[2].Id != 3 {
+		t.Error("The species with the lowest fitness should go first", species[0].Id, species[1].Id, species[2].Id)
+	}
+	if best := bestSpeciesIndex(species, MaximizeObjective); species[best].Id != 3 {
+		t.Error("The species with the highest fitness expected", species[best].Id)
+	}
+}
@@ -0,0 +1,215 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The offspring allocation type definition, i.e. the method to divide offspring of the next generation among species
+type OffspringAllocationType int
+
+const (
+	// The original NEAT allocation - expected offspring of each organism is its fitness divided by average fitness of
+	// population and the fractional parts are skimmed within species until they add up to whole offspring. The lost
+	// offspring due to rounding are given to the species expecting the most.
+	SkimOffspringAllocation OffspringAllocationType = iota
+	// The offspring are allocated in proportion to the total fitness of organisms within species
+	FitnessOffspringAllocation
+	// The offspring are allocated in proportion to the rank of species, where the species with the least total
+	// fitness has rank 1 and the best species has rank equal to the number of species
+	RankOffspringAllocation
+	// All species get equal number of offspring, and the best species (holding the population champion) gets twice as
+	// much as others
+	EqualOffspringAllocation
+)
+
+// Allocates given number of offspring among species of this population according to the offspring allocation type
+// configured by context, i.e. sets ExpectedOffspring of each species. The fitness of organisms should be adjusted
+// within species before. All schemes except skim apportion offspring by largest remainder method, thus exactly given
+// number of offspring allocated.
+func (p *Population) allocateOffspring(offspring int, context *neat.NeatContext) error {
+	if len(p.Species) == 0 {
+		return nil
+	}
+	var shares []float64
+	switch OffspringAllocationType(context.OffspringAllocationType) {
+	case SkimOffspringAllocation:
+		p.allocateOffspringBySkim(offspring)
+		return nil
+	case FitnessOffspringAllocation:
+		shares = speciesFitnessShares(p.Species)
+	case RankOffspringAllocation:
+		shares = speciesRankShares(p.Species)
+	case EqualOffspringAllocation:
+		shares = make([]float64, len(p.Species))
+		best := p.Species[bestSpeciesIndex(p.Species, objectiveDirection(context))]
+		for i, sp := range p.Species {
+			shares[i] = 1.0
+			if sp == best {
+				shares[i] = 2.0
+			}
+		}
+	default:
+		return newError(ErrUnsupportedType,
+			"POPULATION: Unsupported offspring allocation type: %d", context.OffspringAllocationType)
+	}
+
+	for i, count := range apportionOffspring(shares, offspring, bestSpeciesIndex(p.Species, objectiveDirection(context))) {
+		p.Species[i].ExpectedOffspring = count
+	}
+	return nil
+}
+
+// Allocates given number of offspring among species by skimming fractional parts of organisms' expected offspring
+func (p *Population) allocateOffspringBySkim(offspring int) {
+	// Go through the organisms and add up their fitnesses to compute the overall average
+	total := 0.0
+	for _, o := range p.Organisms {
+		total += o.Fitness
+	}
+	// The average modified fitness per one offspring
+	overall_average := total / float64(offspring)
+
+	// Now compute expected number of offspring for each individual organism. If all organisms have zero fitness,
+	// none of them expects offspring and all offspring given to the best species below.
+	for _, o := range p.Organisms {
+		if overall_average != 0 {
+			o.ExpectedOffspring = o.Fitness / overall_average
+		} else {
+			o.ExpectedOffspring = 0
+		}
+	}
+
+	//The fractional parts of expected offspring that can be used only when they accumulate above 1 for the purposes
+	// of counting Offspring
+	skim := 0.0
+	// precision checking
+	total_expected := 0
+
+	// Now add those offspring up within each Species to get the number of offspring per Species
+	for _, sp := range p.Species {
+		sp.ExpectedOffspring, skim = sp.countOffspring(skim)
+		total_expected += sp.ExpectedOffspring
+	}
+	neat.DebugLog(fmt.Sprintf("POPULATION: Total expected offspring count: %d", total_expected))
+
+	// Need to make up for lost floating point precision in offspring assignment.
+	// If we lost precision, give an extra baby to the best Species
+	if total_expected < offspring {
+		// Find the Species expecting the most
+		var best_species *Species
+		max_expected := 0
+		final_expected := 0
+		for _, sp := range p.Species {
+			if sp.ExpectedOffspring >= max_expected {
+				max_expected = sp.ExpectedOffspring
+				best_species = sp
+			}
+			final_expected += sp.ExpectedOffspring
+		}
+		// Give the extra offspring to the best species
+		best_species.ExpectedOffspring += 1
+		final_expected++
+
+		// If we still aren't at total, there is a problem. Note that this can happen if a stagnant Species
+		// dominates the population and then gets killed off by its age. Then the whole population plummets in
+		// fitness. If the average fitness is allowed to hit 0, then we no longer have an average we can use to
+		// assign offspring.
+		if final_expected < offspring {
+			neat.DebugLog(
+				fmt.Sprintf("POPULATION: Population died !!! (expected/total) %d/%d",
+					final_expected, offspring))
+			for _, sp := range p.Species {
+				sp.ExpectedOffspring = 0
+			}
+			best_species.ExpectedOffspring = offspring
+		}
+	}
+}
+
+// Returns the total fitness of organisms per each species
+func speciesFitnessShares(species []*Species) []float64 {
+	shares := make([]float64, len(species))
+	for i, sp := range species {
+		for _, org := range sp.Organisms {
+			shares[i] += org.Fitness
+		}
+	}
+	return shares
+}
+
+// Returns the rank of each species in ascending order of total fitness of its organisms. The species with equal
+// fitness share the same average rank.
+func speciesRankShares(species []*Species) []float64 {
+	fitness := speciesFitnessShares(species)
+	order := make([]int, len(species))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return fitness[order[i]] < fitness[order[j]]
+	})
+
+	shares := make([]float64, len(species))
+	for start := 0; start < len(order); {
+		end := start + 1
+		for end < len(order) && fitness[order[end]] == fitness[order[start]] {
+			end++
+		}
+		rank := float64(start + end + 1) / 2.0
+		for i := start; i < end; i++ {
+			shares[order[i]] = rank
+		}
+		start = end
+	}
+	return shares
+}
+
+// Returns the index of the best species, i.e. the species with the most fit first organism in given objective direction
+// (see byOrganismOrigFitness)
+func bestSpeciesIndex(species []*Species, direction ObjectiveDirection) int {
+	best := 0
+	for i := 1; i < len(species); i++ {
+		if (byOrganismOrigFitness{species, direction}).Less(best, i) {
+			best = i
+		}
+	}
+	return best
+}
+
+// Divides given number of offspring in proportion to the shares by largest remainder method, the ties of remainders
+// are resolved in order of shares. If all shares are zero, all offspring given to the best one.
+func apportionOffspring(shares []float64, offspring, best int) []int {
+	counts := make([]int, len(shares))
+	total := 0.0
+	for _, s := range shares {
+		total += s
+	}
+	if total <= 0 || math.IsInf(total, 0) || math.IsNaN(total) {
+		counts[best] = offspring
+		return counts
+	}
+
+	allocated := 0
+	remainders := make([]float64, len(shares))
+	for i, s := range shares {
+		quota := s / total * float64(offspring)
+		counts[i] = int(math.Floor(quota))
+		remainders[i] = quota - float64(counts[i])
+		allocated += counts[i]
+	}
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for i := 0; allocated < offspring; i = (i + 1) % len(order) {
+		counts[order[i]]++
+		allocated++
+	}
+	return counts
+}
@@ -0,0 +1,160 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Builds population with three species with total fitness 0.8, 0.1 and 0.1, where the first species is the best
+func buildPopulationForAllocation() *Population {
+	orgs := buildOrganismsForSorting(0.5, 0.3, 0.1, 0.05, 0.05)
+	pop := &Population{Organisms:orgs}
+	for i, members := range [][]*Organism{orgs[0:2], orgs[2:3], orgs[3:5]} {
+		sp := NewSpecies(i + 1)
+		for _, org := range members {
+			sp.addOrganism(org)
+			org.Species = sp
+		}
+		pop.Species = append(pop.Species, sp)
+	}
+	return pop
+}
+
+func TestPopulation_allocateOffspring(t *testing.T) {
+	testCases := []struct {
+		allocation OffspringAllocationType
+		offspring  int
+		expected   []int
+	}{
+		{FitnessOffspringAllocation, 10, []int{8, 1, 1}},
+		{FitnessOffspringAllocation, 2, []int{2, 0, 0}},
+		{RankOffspringAllocation, 10, []int{5, 3, 2}},
+		{EqualOffspringAllocation, 10, []int{5, 3, 2}},
+		{EqualOffspringAllocation, 3, []int{1, 1, 1}},
+	}
+	for _, tc := range testCases {
+		pop := buildPopulationForAllocation()
+		context := neat.NeatContext{OffspringAllocationType:int(tc.allocation)}
+		if err := pop.allocateOffspring(tc.offspring, &context); err != nil {
+			t.Error(err)
+			continue
+		}
+		for i, sp := range pop.Species {
+			if sp.ExpectedOffspring != tc.expected[i] {
+				t.Error("Wrong expected offspring", tc.allocation, tc.offspring, i, sp.ExpectedOffspring, tc.expected[i])
+			}
+		}
+	}
+}
+
+func TestPopulation_allocateOffspring_skim(t *testing.T) {
+	pop := buildPopulationForAllocation()
+	context := neat.NeatContext{OffspringAllocationType:int(SkimOffspringAllocation)}
+	if err := pop.allocateOffspring(10, &context); err != nil {
+		t.Error(err)
+		return
+	}
+	total := 0
+	for _, sp := range pop.Species {
+		total += sp.ExpectedOffspring
+	}
+	if total != 10 {
+		t.Error("Wrong total expected offspring", total)
+	}
+	if pop.Species[0].ExpectedOffspring < 7 {
+		t.Error("The best species should get most of offspring", pop.Species[0].ExpectedOffspring)
+	}
+
+	// the zero fitness of all organisms gives all offspring to one species ignoring previous expectations
+	for _, org := range pop.Organisms {
+		org.Fitness = 0.0
+	}
+	if err := pop.allocateOffspring(10, &context); err != nil {
+		t.Error(err)
+		return
+	}
+	total = 0
+	for _, sp := range pop.Species {
+		total += sp.ExpectedOffspring
+		if sp.ExpectedOffspring != 0 && sp.ExpectedOffspring != 10 {
+			t.Error("All offspring should be given to one species", sp.ExpectedOffspring)
+		}
+	}
+	if total != 10 {
+		t.Error("Wrong total expected offspring", total)
+	}
+	for _, org := range pop.Organisms {
+		if org.ExpectedOffspring != 0 {
+			t.Error("Organism with zero fitness should not expect offspring", org.ExpectedOffspring)
+		}
+	}
+
+	context.OffspringAllocationType = 100
+	if err := pop.allocateOffspring(10, &context); err == nil {
+		t.Error("Error expected for unsupported offspring allocation type")
+	}
+}
+
+func TestSpeciesRankShares(t *testing.T) {
+	pop := buildPopulationForAllocation()
+	shares := speciesRankShares(pop.Species)
+	expected := []float64{3.0, 1.5, 1.5}
+	for i, s := range shares {
+		if s != expected[i] {
+			t.Error("Wrong rank", i, s, expected[i])
+		}
+	}
+}
+
+func TestApportionOffspring(t *testing.T) {
+	counts := apportionOffspring([]float64{1.0, 1.0, 1.0}, 4, 2)
+	if counts[0] != 2 || counts[1] != 1 || counts[2] != 1 {
+		t.Error("The ties of remainders should be resolved in order of shares", counts)
+	}
+	counts = apportionOffspring([]float64{0.0, 0.0, 0.0}, 4, 2)
+	if counts[0] != 0 || counts[1] != 0 || counts[2] != 4 {
+		t.Error("All offspring should be given to the best if all shares are zero", counts)
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_offspringAllocation(t *testing.T) {
+	for _, allocation := range []OffspringAllocationType{
+		FitnessOffspringAllocation, RankOffspringAllocation, EqualOffspringAllocation} {
+		rand.Seed(42)
+		conf := neat.NeatContext{
+			CompatThreshold:0.5,
+			DropOffAge:15,
+			PopSize:30,
+			SurvivalThresh:0.2,
+			MutateAddLinkProb:0.1,
+			MutateAddNodeProb:0.05,
+			MutateLinkWeightsProb:0.9,
+			WeightMutPower:2.5,
+			NewLinkTries:20,
+			NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+			NodeActivatorsProb:[]float64{1.0},
+			OffspringAllocationType:int(allocation),
+		}
+		gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+		pop, err := NewPopulation(gen, &conf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		ex := SequentialPopulationEpochExecutor{}
+		for i := 0; i < 5; i++ {
+			for _, org := range pop.Organisms {
+				org.Fitness = rand.Float64()
+			}
+			if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+				t.Error(allocation, err)
+				return
+			}
+			if len(pop.Organisms) != conf.PopSize {
+				t.Error("Population size should be kept", allocation, len(pop.Organisms))
+			}
+		}
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"github.com/yaricom/goNEAT/neat/network"
 	"fmt"
 	"bytes"
+	"io"
+	"math/rand"
+	"sync/atomic"
 )
 
 // The object to associate implementation specific data with particular organism for various algorithm implementations
@@ -22,8 +25,6 @@ type Organism struct {
 	// Win marker (if needed for a particular task)
 	IsWinner                  bool
 
-	// The Organism's phenotype
-	Phenotype                 *network.Network
 	// The Organism's genotype
 	Genotype                  *Genome
 	// The Species of the Organism
@@ -33,18 +34,28 @@ type Organism struct {
 	ExpectedOffspring         float64
 	// Tells which generation this Organism is from
 	Generation                int
+	// The number of evaluation time steps this Organism is alive in real-time mode
+	TimeAlive                 int
 
 	// The utility data transfer object to be used by different GA implementations to hold additional data.
 	// Implemented as ANY to allow implementation specific objects.
 	Data                      *OrganismData
+	// The ID of this organism unique within the process, it is used to track lineage of organisms
+	LineageId                 int64
+	// The lineage IDs of parents of this organism, the first one is the primary parent (mom). Empty for organisms
+	// of initial population.
+	ParentLineageIds          []int64
+	// The user defined annotations of this organism which can be inherited by offspring and transmitted with organism
+	Tags                      OrganismTags
+	// The error of the last evaluation of this organism if it failed and the failure was tolerated by evaluation error
+	// policy, nil otherwise
+	EvaluationError           error
 
 	// A fitness measure that won't change during fitness adjustments of population's epoch evaluation
 	originalFitness           float64
 
-	// Marker for destruction of inferior Organisms
-	toEliminate               bool
-	// Marks the species champion
-	isChampion                bool
+	// The lifecycle state of organism, see OrganismState
+	state                     OrganismState
 
 	// Number of reserved offspring for a population leader
 	superChampOffspring       int
@@ -52,6 +63,8 @@ type Organism struct {
 	isPopulationChampion      bool
 	// Marks the duplicate child of a champion (for tracking purposes)
 	isPopulationChampionChild bool
+	// Marks the organism survived epoch of steady-state executor unchanged
+	isSurvivor                bool
 
 	// DEBUG variable - highest fitness of champ
 	highestFitness            float64
@@ -60,35 +73,183 @@ type Organism struct {
 	mutationStructBaby        bool
 	mateBaby                  bool
 
+	// The mutation rates inherited from parent species when adaptive mutation is enabled
+	mutationRates             *MutationRates
+	// The random number generator of evaluation, see Rand
+	rng                       *rand.Rand
+	// The index of this organism among the clones sharing its genome ID and generation, mixed into evaluation seed
+	cloneIndex                int
+	// The counter of clones shared by original organism and all its clones
+	clones                    *int64
+
 	// The flag to be used as utility value
 	Flag                      int
 }
 
-// Creates new organism with specified genome, fitness and given generation number
+// Creates new organism with specified genome, fitness and given generation number. The genome is validated by building
+// its phenotype if it was not built yet.
 func NewOrganism(fit float64, g *Genome, generation int) (org *Organism, err error) {
-	phenotype := g.Phenotype
-	if phenotype == nil {
-		phenotype, err = g.Genesis(g.Id)
-		if err != nil {
+	if g.Phenotype == nil {
+		if _, err = g.Genesis(g.Id); err != nil {
 			return nil, err
 		}
 	}
 	org = &Organism{
 		Fitness:fit,
 		Genotype:g,
-		Phenotype:phenotype,
 		Generation:generation,
+		LineageId:nextLineageId(),
 	}
 	return org, nil
 }
 
+// Returns deep copy of this organism with the clone of its genome (see Genome.Clone). The fitness, evaluation results,
+// generation, tags and mutation rates are copied. The clone is a new organism in terms of lineage, i.e. it gets new
+// lineage ID with this organism as its parent. The clone gets the next index among clones of the original organism
+// to draw evaluation stream distinct from this organism (see Rand). The clone does not belong to any species and the
+// epoch bookkeeping flags (champion, elimination, survivor marks) are reset, i.e. the clone is evaluated if this
+// organism was ever evaluated, and created otherwise. The tags map is copied, but the values of tags and the utility
+// Data are opaque, thus they are shared with this organism.
+func (o *Organism) Clone() (*Organism, error) {
+	gnome, err := o.Genotype.Clone()
+	if err != nil {
+		return nil, err
+	}
+	clone := &Organism{
+		Fitness:o.Fitness,
+		Error:o.Error,
+		IsWinner:o.IsWinner,
+		Genotype:gnome,
+		ExpectedOffspring:o.ExpectedOffspring,
+		Generation:o.Generation,
+		TimeAlive:o.TimeAlive,
+		EvaluationError:o.EvaluationError,
+		LineageId:nextLineageId(),
+		ParentLineageIds:[]int64{o.LineageId},
+		originalFitness:o.originalFitness,
+		highestFitness:o.highestFitness,
+		mutationStructBaby:o.mutationStructBaby,
+		mateBaby:o.mateBaby,
+		Flag:o.Flag,
+	}
+	if o.clones == nil {
+		o.clones = new(int64)
+	}
+	clone.clones = o.clones
+	clone.cloneIndex = int(atomic.AddInt64(o.clones, 1))
+	if o.state != OrganismCreated {
+		clone.state = OrganismEvaluated
+	}
+	if o.Data != nil {
+		clone.Data = &OrganismData{Value:o.Data.Value}
+	}
+	if o.Tags != nil {
+		clone.Tags = make(OrganismTags, len(o.Tags))
+		for k, v := range o.Tags {
+			clone.Tags[k] = v
+		}
+	}
+	if o.mutationRates != nil {
+		clone.mutationRates = o.mutationRates.copy()
+	}
+	return clone, nil
+}
+
+// Returns the Organism's phenotype. The phenotype is built from genotype on first use and cached until genotype is
+// mutated, thus it is cheap to call this method repeatedly.
+func (o *Organism) Phenotype() (*network.Network, error) {
+	if o.Genotype.Phenotype != nil {
+		return o.Genotype.Phenotype, nil
+	}
+	return o.Genotype.Genesis(o.Genotype.Id)
+}
+
+// Writes snapshot of this organism's phenotype state, i.e. current weights of links and states of nodes, in plain text
+// format. It allows to save the controller adapted during episode exactly as it ended, separately from the genome.
+func (o *Organism) WritePhenotypeSnapshot(w io.Writer) error {
+	phenotype, err := o.Phenotype()
+	if err != nil {
+		return err
+	}
+	return phenotype.Snapshot().Write(w)
+}
+
+// Reads snapshot of phenotype state written by WritePhenotypeSnapshot and restores it into the phenotype of this
+// organism, which is built from genotype if needed. The genotype should be the same as one of organism which
+// snapshot was written.
+func (o *Organism) ReadPhenotypeSnapshot(r io.Reader) error {
+	snapshot, err := network.ReadSnapshot(r)
+	if err != nil {
+		return err
+	}
+	phenotype, err := o.Phenotype()
+	if err != nil {
+		return err
+	}
+	return phenotype.Restore(snapshot)
+}
+
+// Copies weights of links of this organism's phenotype into the genes of its genotype, e.g. after the weights were
+// refined by gradient descent (see network.GradientNetwork), thus the refined weights are saved with genome and
+// inherited by offspring. The genes are matched with links by IDs of connected nodes and recurrent flag. Does nothing
+// if phenotype was not built yet.
+func (o *Organism) SavePhenotypeWeights() error {
+	phenotype := o.Genotype.Phenotype
+	if phenotype == nil {
+		return nil
+	}
+	type link_key struct {
+		in, out   int
+		recurrent bool
+	}
+	weights := make(map[link_key]float64)
+	for _, node := range phenotype.AllNodes() {
+		for _, link := range node.Incoming {
+			weights[link_key{in:link.InNode.Id, out:link.OutNode.Id, recurrent:link.IsRecurrent}] = link.Weight
+		}
+	}
+	for _, gene := range o.Genotype.Genes {
+		if !gene.IsEnabled {
+			continue
+		}
+		weight, ok := weights[link_key{in:gene.Link.InNode.Id, out:gene.Link.OutNode.Id, recurrent:gene.Link.IsRecurrent}]
+		if !ok {
+			return newError(ErrGenomesMismatch, "The link of gene is not found in phenotype: %s", gene)
+		}
+		gene.Link.Weight = weight
+	}
+	return nil
+}
+
+// Returns true if this organism is the champion of population, i.e. has the highest fitness in its generation. The flag
+// is maintained by population epoch executor.
+func (o *Organism) IsPopulationChampion() bool {
+	return o.isPopulationChampion
+}
+
+// Returns true if this organism survived the last epoch unchanged, i.e. it was not replaced by steady-state epoch
+// executor. The survivor keeps its fitness from previous evaluation, thus its evaluation can be skipped if fitness
+// is deterministic.
+func (o *Organism) IsSurvivor() bool {
+	return o.isSurvivor
+}
+
+// Returns complexity of the Organism's phenotype, i.e. the sum of its nodes and links count. Returns zero if phenotype
+// can not be built.
+func (o *Organism) Complexity() int {
+	if phenotype, err := o.Phenotype(); err == nil {
+		return phenotype.Complexity()
+	}
+	return 0
+}
+
 // Regenerate the network based on a change in the genotype
 func (o *Organism) UpdatePhenotype() (err error) {
 	// First, delete the old phenotype (net)
-	o.Phenotype = nil
+	o.Genotype.invalidatePhenotype()
 
 	// Now, recreate the phenotype off the new genotype
-	o.Phenotype, err = o.Genotype.Genesis(o.Genotype.Id)
+	_, err = o.Phenotype()
 	return err
 }
 
@@ -104,6 +265,23 @@ func (o *Organism) CheckChampionChildDamaged() bool {
 func (o *Organism) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
 	_, err := fmt.Fprintln(&buf, o.Fitness, o.Generation, o.highestFitness, o.isPopulationChampionChild, o.Genotype.Id)
+	if err == nil {
+		rates := o.mutationRates
+		if rates == nil {
+			rates = &MutationRates{}
+		}
+		_, err = fmt.Fprintln(&buf, o.mutationRates != nil, rates.AddNodeProb, rates.AddLinkProb,
+			rates.LinkWeightsProb, rates.ToggleEnableProb, rates.GeneReenableProb, rates.WeightMutPower)
+	}
+	if err == nil {
+		_, err = fmt.Fprintln(&buf, o.LineageId, encodeLineageIds(o.ParentLineageIds))
+	}
+	if err == nil {
+		var tags string
+		if tags, err = encodeOrganismTags(o.Tags); err == nil {
+			_, err = fmt.Fprintln(&buf, tags)
+		}
+	}
 	o.Genotype.Write(&buf)
 	if err != nil {
 		return nil, err
@@ -117,21 +295,45 @@ func (o *Organism) UnmarshalBinary(data []byte) error {
 	b := bytes.NewBuffer(data)
 	var genotype_id int
 	_, err := fmt.Fscanln(b, &o.Fitness, &o.Generation, &o.highestFitness, &o.isPopulationChampionChild, &genotype_id)
-	o.Genotype, err = ReadGenome(b, genotype_id)
-	if err == nil {
-		o.Phenotype, err = o.Genotype.Genesis(genotype_id)
+	if err != nil {
+		return err
+	}
+	has_rates, rates := false, MutationRates{}
+	_, err = fmt.Fscanln(b, &has_rates, &rates.AddNodeProb, &rates.AddLinkProb, &rates.LinkWeightsProb,
+		&rates.ToggleEnableProb, &rates.GeneReenableProb, &rates.WeightMutPower)
+	if err != nil {
+		return err
+	}
+	if has_rates {
+		o.mutationRates = &rates
 	}
+	var parents string
+	if _, err = fmt.Fscanln(b, &o.LineageId, &parents); err != nil {
+		return err
+	}
+	if o.ParentLineageIds, err = decodeLineageIds(parents); err != nil {
+		return err
+	}
+	var tags string
+	if _, err = fmt.Fscanln(b, &tags); err != nil {
+		return err
+	}
+	if o.Tags, err = decodeOrganismTags(tags); err != nil {
+		return err
+	}
+	// the phenotype will be built on demand
+	o.Genotype, err = ReadGenome(b, genotype_id)
 
 	return err
 }
 
 func (o *Organism) String() string {
 	champStr := ""
-	if o.isChampion {
+	if o.IsChampion() {
 		champStr = " - CHAMPION - "
 	}
 	eliminStr := ""
-	if o.toEliminate {
+	if o.IsMarkedForDeath() {
 		eliminStr = " - TO BE ELIMINATED - "
 	}
 	return fmt.Sprintf("[Organism generation: %d, fitness: %.3f, original fitness: %.3f%s%s]",
@@ -144,15 +346,17 @@ func (o *Organism) Dump() string {
 	fmt.Fprintln(b, "Fitness: ", o.Fitness)
 	fmt.Fprintln(b, "Error: ", o.Error)
 	fmt.Fprintln(b, "IsWinner: ", o.IsWinner)
-	fmt.Fprintln(b, "Phenotype: ", o.Phenotype)
+	fmt.Fprintln(b, "Phenotype: ", o.Genotype.Phenotype)
 	fmt.Fprintln(b, "Genotype: ", o.Genotype)
 	fmt.Fprintln(b, "Species: ", o.Species)
 	fmt.Fprintln(b, "ExpectedOffspring: ", o.ExpectedOffspring)
 	fmt.Fprintln(b, "Data: ", o.Data)
-	fmt.Fprintln(b, "Phenotype: ", o.Phenotype)
+	fmt.Fprintln(b, "LineageId: ", o.LineageId)
+	fmt.Fprintln(b, "ParentLineageIds: ", o.ParentLineageIds)
+	fmt.Fprintln(b, "Tags: ", o.Tags)
 	fmt.Fprintln(b, "originalFitness: ", o.originalFitness)
-	fmt.Fprintln(b, "toEliminate: ", o.toEliminate)
-	fmt.Fprintln(b, "isChampion: ", o.isChampion)
+	fmt.Fprintln(b, "state: ", o.state)
+	fmt.Fprintln(b, "isChampion: ", o.IsChampion())
 	fmt.Fprintln(b, "superChampOffspring: ", o.superChampOffspring)
 	fmt.Fprintln(b, "isPopulationChampion: ", o.isPopulationChampion)
 	fmt.Fprintln(b, "isPopulationChampionChild: ", o.isPopulationChampionChild)
@@ -164,7 +368,7 @@ func (o *Organism) Dump() string {
 	return b.String()
 }
 
-// Organisms is sortable list of organisms by fitness
+// Organisms is sortable list of organisms by fitness in the same order as ByFitness
 type Organisms []*Organism
 
 func (f Organisms) Len() int {
@@ -174,18 +378,5 @@ func (f Organisms) Swap(i, j int) {
 	f[i], f[j] = f[j], f[i]
 }
 func (f Organisms) Less(i, j int) bool {
-	if f[i].Fitness < f[j].Fitness {
-		// try to promote most fit organisms
-		return true  // lower fitness is less
-	} else if f[i].Fitness == f[j].Fitness {
-		// try to promote less complex organisms
-		ci := f[i].Phenotype.Complexity()
-		cj := f[j].Phenotype.Complexity()
-		if ci > cj {
-			return true // higher complexity is less
-		} else if ci == cj {
-			return f[i].Genotype.Id < f[j].Genotype.Id // least recent (older) is less
-		}
-	}
-	return false
+	return lessByFitness(f[i].Fitness, f[j].Fitness, f[i], f[j])
 }
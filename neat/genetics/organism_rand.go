@@ -0,0 +1,57 @@
+package genetics
+
+import (
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// Returns the seed of random number stream for evaluation of organism with given genome ID in given generation, which
+// is derived from the run seed by SplitMix64 mixing. The optional keys, e.g. the index of organism among its clones,
+// are mixed in as well. The distinct organisms get statistically independent streams.
+func EvaluationSeed(run_seed int64, generation, genome_id int, keys ...int) int64 {
+	z := uint64(run_seed)
+	for _, v := range append([]int{generation, genome_id}, keys...) {
+		z += 0x9E3779B97F4A7C15 + uint64(v)
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		z ^= z >> 31
+	}
+	return int64(z)
+}
+
+// The salt mixed into the run seed to derive reproduction streams distinct from evaluation streams
+const reproductionSeedSalt = 0x5DEECE66D
+
+// Returns the random number generator for reproduction of species at given index in given generation. The stream is
+// seeded by EvaluationSeed from context.RandomSeed mixed with reproductionSeedSalt, thus the offspring of species do
+// not depend on the order in which species reproduced concurrently. If context.RandomSeed is zero, the stream is seeded
+// from the generator of context.
+func reproductionRand(context *neat.NeatContext, generation, species_idx int) *rand.Rand {
+	var seed int64
+	if context.RandomSeed != 0 {
+		seed = EvaluationSeed(context.RandomSeed ^ reproductionSeedSalt, generation, species_idx)
+	} else {
+		seed = context.Rand().Int63()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// Returns the random number generator to be used by evaluation of this organism instead of global one. The stream of
+// generator is seeded by EvaluationSeed from context.RandomSeed, generation, genome ID and clone index of organism,
+// thus results of evaluation do not depend on evaluation order or scheduling of concurrent evaluation workers, and the
+// clones sharing genome ID get distinct streams. If context.RandomSeed is
+// zero, the stream is seeded from the generator of context. The generator is created at first call and kept by organism, thus
+// repeated evaluations continue the same stream. The generator is not safe for concurrent use and should be used only
+// by the worker evaluating this organism.
+func (o *Organism) Rand(context *neat.NeatContext) *rand.Rand {
+	if o.rng == nil {
+		var seed int64
+		if context.RandomSeed != 0 {
+			seed = EvaluationSeed(context.RandomSeed, o.Generation, o.Genotype.Id, o.cloneIndex)
+		} else {
+			seed = context.Rand().Int63()
+		}
+		o.rng = rand.New(rand.NewSource(seed))
+	}
+	return o.rng
+}
@@ -0,0 +1,110 @@
+package genetics
+
+import (
+	"testing"
+	"sync"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// Creates organisms with distinct genome IDs in given generation
+func buildOrganismsForRand(count, generation int) []*Organism {
+	orgs := make([]*Organism, count)
+	for i := range orgs {
+		orgs[i] = &Organism{Genotype:&Genome{Id:i + 1}, Generation:generation}
+	}
+	return orgs
+}
+
+// The noisy evaluation drawing several values from evaluation stream of organism
+func evaluateWithRand(org *Organism, context *neat.NeatContext) float64 {
+	rng := org.Rand(context)
+	sum := 0.0
+	for i := 0; i < 10; i++ {
+		sum += rng.Float64()
+	}
+	return sum
+}
+
+func TestOrganism_Rand(t *testing.T) {
+	context := &neat.NeatContext{RandomSeed:42}
+	sequential := buildOrganismsForRand(20, 3)
+	expected := make([]float64, len(sequential))
+	for i, org := range sequential {
+		expected[i] = evaluateWithRand(org, context)
+	}
+
+	// the concurrent evaluation in reverse order gives the same results
+	concurrent := buildOrganismsForRand(20, 3)
+	results := make([]float64, len(concurrent))
+	var wg sync.WaitGroup
+	for i := len(concurrent) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = evaluateWithRand(concurrent[i], context)
+		}(i)
+	}
+	wg.Wait()
+	for i, res := range results {
+		if res != expected[i] {
+			t.Error("Wrong result of concurrent evaluation at:", i, res, expected[i])
+		}
+	}
+
+	// the repeated evaluation continues the stream
+	if org := sequential[0]; evaluateWithRand(org, context) == expected[0] {
+		t.Error("The repeated evaluation should continue the stream")
+	}
+	// the streams of distinct organisms, generations and runs differ
+	if expected[0] == expected[1] {
+		t.Error("The streams of distinct organisms should differ")
+	}
+	if res := evaluateWithRand(buildOrganismsForRand(1, 4)[0], context); res == expected[0] {
+		t.Error("The streams of distinct generations should differ")
+	}
+	if res := evaluateWithRand(buildOrganismsForRand(1, 3)[0], &neat.NeatContext{RandomSeed:43}); res == expected[0] {
+		t.Error("The streams of distinct runs should differ")
+	}
+}
+
+func TestOrganism_Rand_clones(t *testing.T) {
+	org, err := NewOrganism(0.0, buildTestGenome(1), 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	clone, err := org.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	clone_of_clone, err := clone.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the clones share genome ID and generation, but should get distinct streams
+	context := &neat.NeatContext{RandomSeed:42}
+	results := map[float64]bool{}
+	for _, o := range []*Organism{org, clone, clone_of_clone} {
+		results[evaluateWithRand(o, context)] = true
+	}
+	if len(results) != 3 {
+		t.Error("The streams of clones should differ", results)
+	}
+}
+
+func TestEvaluationSeed(t *testing.T) {
+	if EvaluationSeed(1, 2, 3) != EvaluationSeed(1, 2, 3) {
+		t.Error("The seed should be deterministic")
+	}
+	seeds := make(map[int64]bool)
+	for generation := 0; generation < 10; generation++ {
+		for id := 0; id < 10; id++ {
+			seeds[EvaluationSeed(42, generation, id)] = true
+		}
+	}
+	if len(seeds) != 100 {
+		t.Error("The distinct seeds expected", len(seeds))
+	}
+}
@@ -0,0 +1,105 @@
+package genetics
+
+import "sort"
+
+// The sort orders of organisms below are total, i.e. the ties are broken by genome ID, thus sorting gives the same
+// result regardless of the initial order of organisms (given that genome IDs are unique). They sort in ascending
+// order, use sort.Reverse to get the best organisms first. The stable sorting is used by this package to keep the
+// order of organisms with the same genome ID.
+
+// ByFitness sorts organisms by fitness. The less complex organism is greater among organisms with equal fitness,
+// and the least recent (lower genome ID) organism is less among equally complex ones.
+type ByFitness []*Organism
+
+func (f ByFitness) Len() int {
+	return len(f)
+}
+func (f ByFitness) Swap(i, j int) {
+	f[i], f[j] = f[j], f[i]
+}
+func (f ByFitness) Less(i, j int) bool {
+	return lessByFitness(f[i].Fitness, f[j].Fitness, f[i], f[j])
+}
+
+// ByOriginalFitness sorts organisms by original fitness, i.e. the fitness before adjustment by fitness sharing
+// within species. The ties are broken the same way as in ByFitness.
+type ByOriginalFitness []*Organism
+
+func (f ByOriginalFitness) Len() int {
+	return len(f)
+}
+func (f ByOriginalFitness) Swap(i, j int) {
+	f[i], f[j] = f[j], f[i]
+}
+func (f ByOriginalFitness) Less(i, j int) bool {
+	return lessByFitness(f[i].originalFitness, f[j].originalFitness, f[i], f[j])
+}
+
+// ByComplexity sorts organisms by complexity of their phenotypes, the ties are broken by genome ID.
+type ByComplexity []*Organism
+
+func (f ByComplexity) Len() int {
+	return len(f)
+}
+func (f ByComplexity) Swap(i, j int) {
+	f[i], f[j] = f[j], f[i]
+}
+func (f ByComplexity) Less(i, j int) bool {
+	if ci, cj := f[i].Complexity(), f[j].Complexity(); ci != cj {
+		return ci < cj
+	}
+	return f[i].Genotype.Id < f[j].Genotype.Id
+}
+
+// ByAge sorts organisms from the youngest to the oldest, i.e. in descending order of generation when organism was
+// born, the ties are broken by genome ID.
+type ByAge []*Organism
+
+func (f ByAge) Len() int {
+	return len(f)
+}
+func (f ByAge) Swap(i, j int) {
+	f[i], f[j] = f[j], f[i]
+}
+func (f ByAge) Less(i, j int) bool {
+	if f[i].Generation != f[j].Generation {
+		return f[i].Generation > f[j].Generation
+	}
+	return f[i].Genotype.Id < f[j].Genotype.Id
+}
+
+// Checks whether organism a with fitness fa is less than organism b with fitness fb
+func lessByFitness(fa, fb float64, a, b *Organism) bool {
+	if fa != fb {
+		// try to promote most fit organisms
+		return fa < fb
+	}
+	// try to promote less complex organisms
+	if ca, cb := a.Complexity(), b.Complexity(); ca != cb {
+		return ca > cb // higher complexity is less
+	}
+	return a.Genotype.Id < b.Genotype.Id // least recent (older) is less
+}
+
+// Sorts given organisms by fitness in descending order, i.e. the most fit organism goes first
+func sortByFitnessDesc(organisms []*Organism) {
+	sort.Stable(sort.Reverse(ByFitness(organisms)))
+}
+
+// Sorts given organisms by fitness oriented by objective direction in ascending order, i.e. the least fit organism
+// goes first
+func sortByDirectedFitness(organisms []*Organism, direction ObjectiveDirection) {
+	sort.SliceStable(organisms, func(i, j int) bool {
+		return lessByFitness(direction.maximized(organisms[i].Fitness), direction.maximized(organisms[j].Fitness),
+			organisms[i], organisms[j])
+	})
+}
+
+// Sorts given organisms by fitness oriented by objective direction in descending order, i.e. the most fit organism
+// goes first
+func sortByDirectedFitnessDesc(organisms []*Organism, direction ObjectiveDirection) {
+	sort.SliceStable(organisms, func(i, j int) bool {
+		return lessByFitness(direction.maximized(organisms[j].Fitness), direction.maximized(organisms[i].Fitness),
+			organisms[j], organisms[i])
+	})
+}
@@ -0,0 +1,105 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"sort"
+)
+
+// Builds organisms with given fitness values and genome IDs in order of values
+func buildOrganismsForSorting(fitness ...float64) []*Organism {
+	orgs := make([]*Organism, len(fitness))
+	for i, f := range fitness {
+		orgs[i] = &Organism{Fitness:f, originalFitness:f, Genotype:buildTestGenome(i + 1)}
+	}
+	return orgs
+}
+
+// Returns genome IDs of organisms in their order
+func organismIds(orgs []*Organism) []int {
+	ids := make([]int, len(orgs))
+	for i, org := range orgs {
+		ids[i] = org.Genotype.Id
+	}
+	return ids
+}
+
+// Checks that sorting of shuffled organisms always gives expected order of genome IDs
+func checkSortOrder(t *testing.T, name string, orgs []*Organism, sorter func([]*Organism) sort.Interface, expected []int) {
+	for seed := int64(0); seed < 10; seed++ {
+		rand.Seed(seed)
+		rand.Shuffle(len(orgs), func(i, j int) {
+			orgs[i], orgs[j] = orgs[j], orgs[i]
+		})
+		sort.Stable(sorter(orgs))
+		ids := organismIds(orgs)
+		for i := range expected {
+			if ids[i] != expected[i] {
+				t.Error(name, "wrong sort order", ids, expected)
+				return
+			}
+		}
+	}
+}
+
+func TestByFitness(t *testing.T) {
+	orgs := buildOrganismsForSorting(2.0, 1.0, 2.0, 3.0, 1.0)
+	// the more complex organism with equal fitness is less
+	orgs[2].Genotype = buildTestModularGenome(3)
+
+	checkSortOrder(t, "ByFitness", orgs, func(o []*Organism) sort.Interface {
+		return ByFitness(o)
+	}, []int{2, 5, 3, 1, 4})
+	checkSortOrder(t, "Organisms", orgs, func(o []*Organism) sort.Interface {
+		return Organisms(o)
+	}, []int{2, 5, 3, 1, 4})
+	checkSortOrder(t, "Reverse ByFitness", orgs, func(o []*Organism) sort.Interface {
+		return sort.Reverse(ByFitness(o))
+	}, []int{4, 1, 3, 5, 2})
+}
+
+func TestByOriginalFitness(t *testing.T) {
+	orgs := buildOrganismsForSorting(2.0, 1.0, 2.0, 3.0)
+	// the adjusted fitness should be ignored
+	for i, org := range orgs {
+		org.Fitness = float64(-i)
+	}
+	checkSortOrder(t, "ByOriginalFitness", orgs, func(o []*Organism) sort.Interface {
+		return ByOriginalFitness(o)
+	}, []int{2, 1, 3, 4})
+}
+
+func TestByComplexity(t *testing.T) {
+	orgs := buildOrganismsForSorting(1.0, 2.0, 3.0)
+	orgs[0].Genotype = buildTestModularGenome(1)
+	checkSortOrder(t, "ByComplexity", orgs, func(o []*Organism) sort.Interface {
+		return ByComplexity(o)
+	}, []int{2, 3, 1})
+}
+
+func TestByAge(t *testing.T) {
+	orgs := buildOrganismsForSorting(1.0, 1.0, 1.0, 1.0)
+	for i, gen := range []int{3, 1, 3, 2} {
+		orgs[i].Generation = gen
+	}
+	checkSortOrder(t, "ByAge", orgs, func(o []*Organism) sort.Interface {
+		return ByAge(o)
+	}, []int{1, 3, 4, 2})
+}
+
+func TestSpecies_findChampion_ties(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		orgs := buildOrganismsForSorting(1.0, 3.0, 3.0, 2.0, 3.0)
+		rand.Seed(seed)
+		rand.Shuffle(len(orgs), func(i, j int) {
+			orgs[i], orgs[j] = orgs[j], orgs[i]
+		})
+		sp := NewSpecies(1)
+		for _, org := range orgs {
+			sp.addOrganism(org)
+		}
+		if champ := sp.findChampion(); champ.Genotype.Id != 5 {
+			t.Error("Champion should not depend on order of organisms", seed, champ.Genotype.Id)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package genetics
+
+// The lifecycle state of organism. The organism is created unevaluated, becomes evaluated when its fitness is
+// assigned, may be ranked as champion or marked for death by selection within its species and finally gets archived
+// when removed from population. Only transitions defined by organismStateTransitions are allowed.
+type OrganismState byte
+
+// The available organism lifecycle states
+const (
+	// The organism is created, but its fitness is not evaluated yet
+	OrganismCreated OrganismState = iota
+	// The organism fitness is evaluated
+	OrganismEvaluated
+	// The organism is ranked the best within its species during epoch, it can not be marked for death
+	OrganismChampion
+	// The organism is ranked too low to be a parent and will be removed from population during epoch
+	OrganismMarkedForDeath
+	// The organism is removed from population, it can still be inspected (e.g. as champion of generation), but it
+	// takes no part in evolution anymore
+	OrganismArchived
+)
+
+// The allowed transitions between organism lifecycle states. The champion or organism marked for death can be
+// evaluated again if it is kept in population, e.g. as survivor of steady-state epoch, or ranked anew. The archived
+// state is terminal.
+var organismStateTransitions = map[OrganismState][]OrganismState{
+	OrganismCreated:{OrganismEvaluated, OrganismArchived},
+	OrganismEvaluated:{OrganismEvaluated, OrganismChampion, OrganismMarkedForDeath, OrganismArchived},
+	OrganismChampion:{OrganismEvaluated, OrganismArchived},
+	OrganismMarkedForDeath:{OrganismEvaluated, OrganismArchived},
+}
+
+// Returns name of organism state
+func (s OrganismState) String() string {
+	switch s {
+	case OrganismCreated:
+		return "created"
+	case OrganismEvaluated:
+		return "evaluated"
+	case OrganismChampion:
+		return "champion"
+	case OrganismMarkedForDeath:
+		return "marked for death"
+	case OrganismArchived:
+		return "archived"
+	default:
+		return "unknown"
+	}
+}
+
+// Returns the current lifecycle state of organism
+func (o *Organism) State() OrganismState {
+	return o.state
+}
+
+// Returns true if organism is marked for death by selection within its species
+func (o *Organism) IsMarkedForDeath() bool {
+	return o.state == OrganismMarkedForDeath
+}
+
+// Returns true if organism is the champion of its species in the current epoch
+func (o *Organism) IsChampion() bool {
+	return o.state == OrganismChampion
+}
+
+// Marks organism as evaluated, it should be invoked by evaluators after fitness of organism assigned. Returns error if
+// organism is already archived.
+func (o *Organism) MarkEvaluated() error {
+	return o.setState(OrganismEvaluated)
+}
+
+// Marks organism for death, the champion of species can not be marked
+func (o *Organism) markForDeath() error {
+	if o.state == OrganismChampion {
+		return newError(ErrIllegalOrganismState, "The champion of species can not be marked for death, organism [%d]",
+			o.Genotype.Id)
+	}
+	return o.setState(OrganismMarkedForDeath)
+}
+
+// Changes lifecycle state of organism, returns error if transition from the current state is not allowed
+func (o *Organism) setState(state OrganismState) error {
+	for _, next := range organismStateTransitions[o.state] {
+		if next == state {
+			o.state = state
+			return nil
+		}
+	}
+	id := 0
+	if o.Genotype != nil {
+		id = o.Genotype.Id
+	}
+	return newError(ErrIllegalOrganismState, "Illegal transition of organism [%d] from state: %s to: %s", id, o.state,
+		state)
+}
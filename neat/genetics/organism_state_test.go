@@ -0,0 +1,79 @@
+package genetics
+
+import (
+	"testing"
+	"errors"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func TestOrganism_setState(t *testing.T) {
+	org := &Organism{Genotype:buildTestGenome(1)}
+	if org.State() != OrganismCreated {
+		t.Error("The new organism should be created", org.State())
+	}
+	if err := org.markForDeath(); !errors.Is(err, ErrIllegalOrganismState) {
+		t.Error("The unevaluated organism can not be marked for death", err)
+	}
+	for _, state := range []OrganismState{OrganismEvaluated, OrganismEvaluated, OrganismChampion, OrganismEvaluated,
+		OrganismMarkedForDeath, OrganismEvaluated, OrganismMarkedForDeath, OrganismArchived} {
+		if err := org.setState(state); err != nil {
+			t.Error(err)
+			return
+		}
+		if org.State() != state {
+			t.Error("Wrong state", org.State(), state)
+		}
+	}
+	// the archived state is terminal
+	if err := org.MarkEvaluated(); !errors.Is(err, ErrIllegalOrganismState) {
+		t.Error("The archived organism can not be evaluated", err)
+	}
+	if org.State() != OrganismArchived {
+		t.Error("The state should not change after illegal transition", org.State())
+	}
+
+	champion := &Organism{Genotype:buildTestGenome(2), state:OrganismChampion}
+	if err := champion.markForDeath(); !errors.Is(err, ErrIllegalOrganismState) || champion.IsMarkedForDeath() {
+		t.Error("The champion can not be marked for death", err)
+	}
+	if !champion.IsChampion() {
+		t.Error("The champion expected")
+	}
+}
+
+func TestOrganismState_String(t *testing.T) {
+	names := map[OrganismState]string{OrganismCreated:"created", OrganismEvaluated:"evaluated",
+		OrganismChampion:"champion", OrganismMarkedForDeath:"marked for death", OrganismArchived:"archived",
+		OrganismState(100):"unknown"}
+	for state, name := range names {
+		if state.String() != name {
+			t.Error("Wrong name of state", state.String(), name)
+		}
+	}
+}
+
+func TestSpecies_removeOrganism_archives(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = sp.adjustFitness(&neat.NeatContext{DropOffAge:5, SurvivalThresh:0.5, AgeSignificance:1.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	removed := sp.Organisms[2]
+	if _, err = sp.removeOrganism(removed); err != nil {
+		t.Error(err)
+		return
+	}
+	if removed.State() != OrganismArchived {
+		t.Error("The removed organism should be archived", removed.State())
+	}
+
+	// the archived organism can not be ranked in species
+	sp.addOrganism(removed)
+	if err = sp.adjustFitness(&neat.NeatContext{DropOffAge:5, SurvivalThresh:0.5}); !errors.Is(err, ErrIllegalOrganismState) {
+		t.Error("Error expected for archived organism in species", err)
+	}
+}
@@ -0,0 +1,57 @@
+package genetics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The user defined annotations of organism, e.g. behavior descriptors assigned by evaluator. The tags are inherited
+// by offspring if InheritOrganismTags enabled in context and transmitted with organism in parallel reproduction. The
+// values of custom types should be registered with gob.Register to be transmitted.
+type OrganismTags map[string]interface{}
+
+// Sets tags of this organism inherited from given parents if configured by context. The tags of the first parent
+// (mom) take precedence over the tags of other parents.
+func (o *Organism) inheritTags(parents []*Organism, context *neat.NeatContext) {
+	if !context.InheritOrganismTags {
+		return
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		for k, v := range parents[i].Tags {
+			if o.Tags == nil {
+				o.Tags = make(OrganismTags)
+			}
+			o.Tags[k] = v
+		}
+	}
+}
+
+// Encodes tags into single line string, the empty tags encoded as "-"
+func encodeOrganismTags(tags OrganismTags) (string, error) {
+	if len(tags) == 0 {
+		return "-", nil
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(tags); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Decodes tags from string produced by encodeOrganismTags
+func decodeOrganismTags(str string) (OrganismTags, error) {
+	if str == "-" {
+		return nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(OrganismTags)
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
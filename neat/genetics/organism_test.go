@@ -7,6 +7,8 @@ import (
 	"math"
 	"bytes"
 	"encoding/gob"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
 )
 
 // tests organisms sorting
@@ -86,10 +88,279 @@ func TestOrganism_MarshalBinary(t *testing.T) {
 	if gnome.Id != dec_gnome.Id {
 		t.Error("gnome.Id != dec_gnome.Id")
 	}
-
+	if dec_gnome.Phenotype != nil {
+		t.Error("The phenotype of decoded organism should be built on demand")
+	}
+	if _, err = dec_org.Phenotype(); err != nil {
+		t.Error(err)
+	}
 
 	equals, err := gnome.IsEqual(dec_gnome)
 	if !equals {
 		t.Error(err)
 	}
+	if dec_org.mutationRates != nil {
+		t.Error("dec_org.mutationRates != nil")
+	}
+}
+
+func TestOrganism_MarshalBinary_mutationRates(t *testing.T) {
+	gnome := buildTestGenome(1)
+	org, err := NewOrganism(rand.Float64(), gnome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	org.mutationRates = &MutationRates{AddNodeProb:0.03, AddLinkProb:0.08, LinkWeightsProb:0.9,
+		ToggleEnableProb:0.1, GeneReenableProb:0.05, WeightMutPower:2.5}
+
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(org); err != nil {
+		t.Error(err)
+		return
+	}
+	dec_org := Organism{}
+	if err = gob.NewDecoder(&buf).Decode(&dec_org); err != nil {
+		t.Error(err)
+		return
+	}
+	if dec_org.mutationRates == nil || *dec_org.mutationRates != *org.mutationRates {
+		t.Error("Mutation rates was not decoded", dec_org.mutationRates)
+	}
+}
+
+func TestOrganism_MarshalBinary_tags(t *testing.T) {
+	gnome := buildTestGenome(1)
+	org, err := NewOrganism(rand.Float64(), gnome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	org.Tags = OrganismTags{"behavior":[]float64{0.5, 1.5}, "name":"test", "steps":10}
+
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(org); err != nil {
+		t.Error(err)
+		return
+	}
+	dec_org := Organism{}
+	if err = gob.NewDecoder(&buf).Decode(&dec_org); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(dec_org.Tags) != 3 {
+		t.Error("Wrong number of decoded tags", dec_org.Tags)
+		return
+	}
+	if behavior, ok := dec_org.Tags["behavior"].([]float64); !ok || len(behavior) != 2 || behavior[1] != 1.5 {
+		t.Error("Wrong behavior tag", dec_org.Tags["behavior"])
+	}
+	if dec_org.Tags["name"] != "test" || dec_org.Tags["steps"] != 10 {
+		t.Error("Wrong decoded tags", dec_org.Tags)
+	}
+}
+
+func TestOrganism_Clone(t *testing.T) {
+	org, err := NewOrganism(0.5, buildTestGenome(1), 3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	org.Error, org.IsWinner, org.TimeAlive = 0.1, true, 10
+	org.Tags = OrganismTags{"name":"test"}
+	org.mutationRates = &MutationRates{AddNodeProb:0.1}
+	org.Species = NewSpecies(1)
+	org.state = OrganismChampion
+
+	clone, err := org.Clone()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if clone.Fitness != org.Fitness || clone.Error != org.Error || !clone.IsWinner || clone.Generation != 3 ||
+		clone.TimeAlive != 10 {
+		t.Error("The evaluation results was not copied", clone)
+	}
+	if clone.Genotype == org.Genotype || clone.Genotype.Id != org.Genotype.Id {
+		t.Error("The genome should be cloned", clone.Genotype.Id)
+	}
+	if clone.Species != nil || clone.IsChampion() || clone.State() != OrganismEvaluated {
+		t.Error("The clone should not belong to species and should have no epoch marks")
+	}
+	if clone.LineageId == org.LineageId || len(clone.ParentLineageIds) != 1 ||
+		clone.ParentLineageIds[0] != org.LineageId {
+		t.Error("The clone should be a child of original in lineage", clone.LineageId, clone.ParentLineageIds)
+	}
+
+	// the changes of clone should not affect original
+	clone.Tags["name"] = "clone"
+	clone.mutationRates.AddNodeProb = 0.5
+	clone.Genotype.Genes[0].Link.Weight = 100.0
+	if org.Tags["name"] != "test" || org.mutationRates.AddNodeProb != 0.1 ||
+		org.Genotype.Genes[0].Link.Weight == 100.0 {
+		t.Error("The changes of clone affected original organism")
+	}
+}
+
+func TestOrganism_inheritTags(t *testing.T) {
+	mom := &Organism{Tags:OrganismTags{"a":1, "b":2}}
+	dad := &Organism{Tags:OrganismTags{"b":3, "c":4}}
+
+	baby := &Organism{}
+	baby.inheritTags([]*Organism{mom, dad}, &neat.NeatContext{})
+	if baby.Tags != nil {
+		t.Error("Tags should not be inherited", baby.Tags)
+	}
+
+	baby.inheritTags([]*Organism{mom, dad}, &neat.NeatContext{InheritOrganismTags:true})
+	if len(baby.Tags) != 3 || baby.Tags["a"] != 1 || baby.Tags["b"] != 2 || baby.Tags["c"] != 4 {
+		t.Error("Wrong inherited tags", baby.Tags)
+	}
+	// the tags of baby are independent from parent
+	baby.Tags["a"] = 10
+	if mom.Tags["a"] != 1 {
+		t.Error("Parent tags changed", mom.Tags)
+	}
+}
+
+func TestOrganism_Phenotype(t *testing.T) {
+	gnome := buildTestGenome(1)
+	org, err := NewOrganism(rand.Float64(), gnome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	net, err := org.Phenotype()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cached, _ := org.Phenotype(); cached != net {
+		t.Error("The phenotype should be cached")
+	}
+	if org.Complexity() != net.Complexity() {
+		t.Error("Wrong complexity", org.Complexity())
+	}
+
+	// the mutation should invalidate cached phenotype
+	if _, err = gnome.mutateLinkWeights(2.5, 1.0, gaussianMutator, UniformWeightPerturbation, utils.GlobalRand()); err != nil {
+		t.Error(err)
+		return
+	}
+	if gnome.Phenotype != nil {
+		t.Error("The phenotype should be invalidated by mutation")
+	}
+	rebuilt, err := org.Phenotype()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if rebuilt == net {
+		t.Error("The phenotype should be rebuilt after mutation")
+	}
+	if rebuilt.Outputs[0].Incoming[0].Weight != gnome.Genes[0].Link.Weight {
+		t.Error("The rebuilt phenotype has wrong link weight")
+	}
+}
+
+func TestOrganism_PhenotypeSnapshot(t *testing.T) {
+	gnome := buildTestGenome(1)
+	org, err := NewOrganism(rand.Float64(), gnome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	net, err := org.Phenotype()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	net.LoadSensors([]float64{0.5, 1.0})
+	if _, err = net.ForwardSteps(3); err != nil {
+		t.Error(err)
+		return
+	}
+	// the weight adapted during episode differs from genome
+	net.Outputs[0].Incoming[0].Weight = 42.0
+
+	var buf bytes.Buffer
+	if err = org.WritePhenotypeSnapshot(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	restored, err := NewOrganism(0.0, buildTestGenome(1), 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = restored.ReadPhenotypeSnapshot(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	restored_net, _ := restored.Phenotype()
+	if restored_net.Outputs[0].Incoming[0].Weight != 42.0 {
+		t.Error("Adapted weight should be restored", restored_net.Outputs[0].Incoming[0].Weight)
+	}
+	if restored_net.Outputs[0].Activation != net.Outputs[0].Activation {
+		t.Error("Output activation should be restored", restored_net.Outputs[0].Activation)
+	}
+	if restored.Genotype.Genes[0].Link.Weight == 42.0 {
+		t.Error("The genome should not be changed")
+	}
+}
+
+func TestOrganism_SavePhenotypeWeights(t *testing.T) {
+	gnome := buildTestGenome(1)
+	org, err := NewOrganism(rand.Float64(), gnome, 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	net, err := org.Phenotype()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, node := range net.AllNodes() {
+		for j, link := range node.Incoming {
+			link.Weight = float64(i * 10 + j)
+		}
+	}
+	if err = org.SavePhenotypeWeights(); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, gene := range gnome.Genes {
+		if !gene.IsEnabled {
+			continue
+		}
+		found := false
+		for _, node := range net.AllNodes() {
+			for _, link := range node.Incoming {
+				if link.IsEqualGenetically(gene.Link) {
+					found = link.Weight == gene.Link.Weight
+				}
+			}
+		}
+		if !found {
+			t.Error("The weight of phenotype link should be saved in gene", gene)
+		}
+	}
+	// the phenotype rebuilt from genome has refined weights
+	if err = org.UpdatePhenotype(); err != nil {
+		t.Error(err)
+		return
+	}
+	rebuilt, _ := org.Phenotype()
+	if rebuilt.Outputs[0].Incoming[0].Weight != net.Outputs[0].Incoming[0].Weight {
+		t.Error("The rebuilt phenotype should have saved weights")
+	}
+
+	// the phenotype without link of gene
+	net.Outputs[0].Incoming = nil
+	gnome.Phenotype = net
+	if err = org.SavePhenotypeWeights(); err == nil {
+		t.Error("Error expected for phenotype missing links of genes")
+	}
 }
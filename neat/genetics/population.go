@@ -1,8 +1,6 @@
 package genetics
 
 import (
-	"math/rand"
-	"errors"
 	"github.com/yaricom/goNEAT/neat"
 
 	"io"
@@ -22,7 +20,8 @@ type Population struct {
 	Species                  []*Species
 	// The organisms in the Population
 	Organisms                []*Organism
-	// The highest species number
+	// The highest species number. The species IDs are unique per evolutionary run and never reused, even after
+	// species extinction.
 	LastSpecies              int
 	// For holding the genetic innovations of the newest generation
 	Innovations              []*Innovation
@@ -42,13 +41,41 @@ type Population struct {
 	Variance                 float64
 	StandardDev              float64
 
+	// The compatibility threshold of threshold speciation for this population. If not set the one configured by context
+	// will be used. It can be set to override context per population, e.g. for islands of archipelago, and it is
+	// adjusted dynamically between generations if context.CompatAdjustStep is set.
+	CompatThreshold          float64
+	// The coefficients of compatibility distance for this population tuned from its genomes each generation if
+	// context.CompatAutoTune is set. If not set the ones configured by context will be used.
+	CompatCoefficients       *CompatCoefficients
+
+	// The mutation pipeline applied to offspring during reproduction. If not set the default pipeline configured by
+	// context will be used.
+	MutationPipeline         *MutationPipeline
+
 	// The next innovation number for population
 	nextInnovNum             int64
 	// The next ID for new node in population
 	nextNodeId               int32
+	// The generator of species IDs, it may be shared with other populations, e.g. islands of archipelago
+	speciesIds               *speciesIdSequence
 
 	// The mutex to guard against concurrent modifications
 	mutex                    *sync.Mutex
+	// The registered observers of epoch lifecycle events
+	observers                []EpochObserver
+	// The history of population champions per generation
+	champions                []ChampionRecord
+	// The copy of the last population record holder preserved for champion regression detection
+	preservedChampion        *Organism
+	// The number of generations in a row when the best organism was worse than preserved champion
+	championRegressions      int
+	// The reproduction statistics of species during the last reproduction cycle
+	reproductionStats        []ReproductionStats
+	// The history of extinction events
+	extinctionEvents         []ExtinctionEvent
+	// The generation of the last extinction event
+	lastExtinction           int
 }
 
 // The auxiliary data type to hold results of parallel reproduction sent over the wires
@@ -62,8 +89,8 @@ type reproductionResult struct {
 // Construct off of a single spawning Genome
 func NewPopulation(g *Genome, context *neat.NeatContext) (*Population, error) {
 	if context.PopSize <= 0 {
-		return nil, errors.New(
-			fmt.Sprintf("Wrong population size in the context: %d", context.PopSize))
+		return nil, newError(ErrInvalidParameter,
+			"Wrong population size in the context: %d", context.PopSize)
 	}
 
 	pop := newPopulation()
@@ -79,13 +106,16 @@ func NewPopulation(g *Genome, context *neat.NeatContext) (*Population, error) {
 // See the Genome constructor above for the argument specifications
 func NewPopulationRandom(in, out, nmax int, recurrent bool, link_prob float64, context *neat.NeatContext) (*Population, error) {
 	if context.PopSize <= 0 {
-		return nil, errors.New(
-			fmt.Sprintf("Wrong population size in the context: %d", context.PopSize))
+		return nil, newError(ErrInvalidParameter,
+			"Wrong population size in the context: %d", context.PopSize)
+	}
+	if recurrent && context.FeedForwardOnly {
+		return nil, newError(ErrRecurrentGenome, "POPULATION: Recurrent genomes requested while feed-forward only evolution configured")
 	}
 
 	pop := newPopulation()
 	for count := 0; count < context.PopSize; count++ {
-		gen := newGenomeRand(count, in, out, rand.Intn(nmax), nmax, recurrent, link_prob)
+		gen := newGenomeRandWith(context.Rand(), count, in, out, context.Rand().Intn(nmax), nmax, recurrent, link_prob)
 		org, err := NewOrganism(0.0, gen, 1)
 		if err != nil {
 			return nil, err
@@ -103,7 +133,8 @@ func NewPopulationRandom(in, out, nmax int, recurrent bool, link_prob float64, c
 	return pop, nil
 }
 
-// Reads population from provided reader
+// Reads population from provided reader. If population was written by species, the species and their IDs are restored,
+// otherwise organisms are speciated anew.
 func ReadPopulation(ir io.Reader, context *neat.NeatContext) (pop *Population, err error) {
 	pop = newPopulation()
 
@@ -112,11 +143,14 @@ func ReadPopulation(ir io.Reader, context *neat.NeatContext) (pop *Population, e
 	scanner.Split(bufio.ScanLines)
 	var out_buff *bytes.Buffer
 	var id_check int
+	// The species of organisms being read and organisms which are not belonging to any species
+	var curr_species *Species
+	unspeciated := make([]*Organism, 0)
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.SplitN(line, " ", 2)
 		if len(parts) < 2 {
-			return nil, errors.New(fmt.Sprintf("Line: [%s] can not be split when reading Population", line))
+			return nil, newError(ErrMalformedGenome, "Line: [%s] can not be split when reading Population", line)
 		}
 		switch parts[0] {
 		case "genomestart":
@@ -137,6 +171,12 @@ func ReadPopulation(ir io.Reader, context *neat.NeatContext) (pop *Population, e
 				return nil, err
 			}
 			pop.Organisms = append(pop.Organisms, new_organism)
+			if curr_species != nil {
+				curr_species.addOrganism(new_organism)
+				new_organism.Species = curr_species
+			} else {
+				unspeciated = append(unspeciated, new_organism)
+			}
 
 			if last_node_id, err := new_genome.getLastNodeId(); err == nil {
 				if pop.nextNodeId < int32(last_node_id) {
@@ -158,7 +198,23 @@ func ReadPopulation(ir io.Reader, context *neat.NeatContext) (pop *Population, e
 			id_check = -1
 
 		case "/*":
-			// read all comments and print it
+			// read species info and print all comments
+			var id, size, age int
+			var avg_fitness float64
+			if n, _ := fmt.Sscanf(line, "/* Species #%d : (Size %d) (AF %f) (Age %d)", &id, &size, &avg_fitness, &age); n == 4 {
+				curr_species = newSpecies(id)
+				curr_species.Age = age
+				curr_species.AgeOfLastImprovement = age
+				if context.AdaptiveMutation {
+					curr_species.MutationRates = NewMutationRates(context)
+				}
+				pop.Species = append(pop.Species, curr_species)
+				if id > pop.LastSpecies {
+					pop.LastSpecies = id
+				}
+			} else if n, _ = fmt.Sscanf(line, "/* Last species #%d", &id); n == 1 && id > pop.LastSpecies {
+				pop.LastSpecies = id
+			}
 			neat.InfoLog(line)
 		default:
 			// write line to buffer
@@ -166,12 +222,24 @@ func ReadPopulation(ir io.Reader, context *neat.NeatContext) (pop *Population, e
 		}
 
 	}
-	err = pop.speciate(pop.Organisms, context)
-	if err != nil {
-		return nil, err
-	} else {
-		return pop, nil
+	if len(pop.Organisms) == 0 {
+		return nil, newError(ErrEmptyPopulation, "POPULATION: No organisms found")
+	}
+	// remove species without organisms
+	species := make([]*Species, 0, len(pop.Species))
+	for _, sp := range pop.Species {
+		if len(sp.Organisms) > 0 {
+			species = append(species, sp)
+		}
 	}
+	pop.Species = species
+
+	if len(unspeciated) > 0 {
+		if err = pop.speciate(unspeciated, context); err != nil {
+			return nil, err
+		}
+	}
+	return pop, nil
 }
 
 // Writes given population to a writer
@@ -182,8 +250,9 @@ func (p *Population) Write(w io.Writer) {
 	}
 }
 
-// Writes given population by species
+// Writes given population by species. The species IDs are stored to be restored by ReadPopulation.
 func (p *Population) WriteBySpecies(w io.Writer) {
+	fmt.Fprintf(w, "/* Last species #%d */\n", p.LastSpecies)
 	// Step through the Species and write them
 	for _, sp := range p.Species {
 		sp.Write(w)
@@ -207,15 +276,72 @@ func (p *Population) Verify() (bool, error) {
 func newPopulation() *Population {
 	return &Population{
 		WinnerGen:0,
-		HighestFitness:0.0,
+		HighestFitness:math.Inf(-1),
 		EpochsHighestLastChanged:0,
 		Species:make([]*Species, 0),
 		Organisms:make([]*Organism, 0),
 		Innovations:make([]*Innovation, 0),
 		mutex:&sync.Mutex{},
+		speciesIds:&speciesIdSequence{},
 	}
 }
 
+// Applies context.InitialMutationRounds rounds of warm-up mutations to the genome of organism of initial population to
+// decorrelate clones of the start genome. Each round mutates link weights, or applies the mutation pipeline of this
+// population including structural mutations if context.InitialStructuralMutation is set.
+func (p *Population) warmUpGenome(g *Genome, context *neat.NeatContext) error {
+	if context.InitialMutationRounds <= 0 {
+		return nil
+	}
+	pipeline := p.MutationPipeline
+	if pipeline == nil {
+		pipeline = DefaultMutationPipeline(context)
+	}
+	for round := 0; round < context.InitialMutationRounds; round++ {
+		var err error
+		if context.InitialStructuralMutation {
+			_, err = pipeline.Apply(g, p, context)
+		} else {
+			_, err = LinkWeightsMutation.Mutate(g, p, context)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The generator of species IDs which are never reused. It is safe for concurrent use, thus can be shared among
+// populations evolving in parallel to get species IDs unique among all of them.
+type speciesIdSequence struct {
+	last int64
+}
+
+// Returns the next species ID
+func (s *speciesIdSequence) next() int {
+	return int(atomic.AddInt64(&s.last, 1))
+}
+
+// Makes sure that generated IDs will be greater than given one
+func (s *speciesIdSequence) skip(id int) {
+	for {
+		last := atomic.LoadInt64(&s.last)
+		if last >= int64(id) || atomic.CompareAndSwapInt64(&s.last, last, int64(id)) {
+			return
+		}
+	}
+}
+
+// Returns the new unique species ID and records it as the highest species number of this population
+func (p *Population) nextSpeciesId() int {
+	if p.speciesIds == nil {
+		p.speciesIds = &speciesIdSequence{}
+	}
+	p.speciesIds.skip(p.LastSpecies)
+	p.LastSpecies = p.speciesIds.next()
+	return p.LastSpecies
+}
+
 // Returns current innovation number and increment innovations number counter after that
 func (p *Population) getNextInnovationNumberAndIncrement() int64 {
 	return atomic.AddInt64(&p.nextInnovNum, 1)
@@ -225,24 +351,58 @@ func (p *Population) getNextNodeIdAndIncrement() int32 {
 	return atomic.AddInt32(&p.nextNodeId, 1)
 }
 
-// Appends given innovation to the list of known innovations in thread safe manner
-func (p *Population) addInnovationSynced(i *Innovation) {
+// Looks for already occurred innovation accepted by match function and if not found registers the new innovation
+// returned by create function. The look up and registration done atomically, thus the same structural innovation
+// occurred simultaneously in concurrent reproduction workers will get the same innovation numbers. Returns the found
+// or registered innovation with flag indicating whether it was found.
+func (p *Population) findOrAddInnovationSynced(match func(*Innovation) bool, create func() *Innovation) (*Innovation, bool) {
 	p.mutex.Lock()
-	p.Innovations = append(p.Innovations, i)
-	p.mutex.Unlock()
+	defer p.mutex.Unlock()
+
+	for _, inn := range p.Innovations {
+		if match(inn) {
+			return inn, true
+		}
+	}
+	inn := create()
+	p.Innovations = append(p.Innovations, inn)
+	return inn, false
 }
 
 // Create a population of size size off of Genome g. The new Population will have the same topology as g
 // with link weights slightly perturbed from g's
 func (p *Population) spawn(g *Genome, context *neat.NeatContext) (err error) {
+	if context.FeedForwardOnly && !g.IsFeedForward() {
+		return newError(ErrRecurrentGenome, "POPULATION: The start genome is recurrent while feed-forward only evolution requested")
+	}
+	// Keep a record of the innovation and node number we are on, the warm-up mutations may add new ones
+	if nextNodeId, err := g.getLastNodeId(); err != nil {
+		return err
+	} else {
+		p.nextNodeId = int32(nextNodeId + 1)
+	}
+	if p.nextInnovNum, err = g.getNextGeneInnovNum(); err != nil {
+		return err
+	}
 	for count := 0; count < context.PopSize; count++ {
 		// make genome duplicate for new organism
-		new_genome, err := g.duplicate(count)
+		new_genome, err := g.cloneWithId(count)
 		if err != nil {
 			return err
 		}
-		// introduce initial mutations
-		if _, err = new_genome.mutateLinkWeights(1.0, 1.0, gaussianMutator); err != nil {
+		// introduce initial mutations, the uniform perturbation keeps initial weights bounded regardless of configured
+		// perturbation of evolution, which may be heavy-tailed
+		if _, err = new_genome.mutateLinkWeights(1.0, 1.0, gaussianMutator, UniformWeightPerturbation,
+			context.Rand()); err != nil {
+			return err
+		}
+		if err = p.warmUpGenome(new_genome, context); err != nil {
+			return err
+		}
+		if new_genome, err = guardOffspringGenome(new_genome, g, context); err != nil {
+			return err
+		}
+		if _, err = new_genome.boundLinkWeights(context); err != nil {
 			return err
 		}
 		// create organism for new genome
@@ -252,16 +412,6 @@ func (p *Population) spawn(g *Genome, context *neat.NeatContext) (err error) {
 			p.Organisms = append(p.Organisms, new_organism)
 		}
 	}
-	// Keep a record of the innovation and node number we are on
-	if nextNodeId, err := g.getLastNodeId(); err != nil {
-		return err
-	} else {
-		p.nextNodeId = int32(nextNodeId + 1)
-	}
-	if p.nextInnovNum, err = g.getNextGeneInnovNum(); err != nil {
-		return err
-	}
-
 	// Separate the new Population into species
 	err = p.speciate(p.Organisms, context)
 
@@ -284,7 +434,7 @@ func (p *Population) checkBestSpeciesAlive(best_species_id int, best_species_rep
 		}
 	}
 	if !best_ok && !best_species_reproduced {
-		return errors.New("POPULATION: The best species died without offspring!")
+		return newError(ErrBestSpeciesDied, "POPULATION: The best species died without offspring!")
 	} else {
 		neat.DebugLog(fmt.Sprintf("POPULATION: The best survived species Id: %d, max fitness ever: %f",
 			best_species_id, best_sp_max_fitness))
@@ -294,19 +444,16 @@ func (p *Population) checkBestSpeciesAlive(best_species_id int, best_species_rep
 
 // Speciate separates given organisms into species of this population by checking compatibilities against a threshold.
 // Any organism that does is not compatible with the first organism in any existing species becomes a new species.
-func (p *Population) speciate(organisms []*Organism, context *neat.NeatContext) error {
-	if len(organisms) == 0 {
-		return errors.New("There is no organisms to speciate from")
-	}
-
+func (p *Population) speciateByThreshold(organisms []*Organism, context *neat.NeatContext) error {
 	// Step through all given organisms and speciate them within the population
 	for _, curr_org := range organisms {
 		if len(p.Species) == 0 {
 			// Create the first species
-			createFirstSpecies(p, curr_org)
+			createFirstSpecies(p, curr_org, context)
 		} else {
-			if context.CompatThreshold == 0 {
-				return errors.New("POPULATION: compatibility thershold is set to ZERO. " +
+			compat_threshold := p.compatThreshold(context)
+			if compat_threshold == 0 {
+				return newError(ErrInvalidParameter, "POPULATION: compatibility thershold is set to ZERO. " +
 					"Will not find any compatible species.")
 			}
 			// For each organism, search for a species it is compatible to
@@ -318,7 +465,7 @@ func (p *Population) speciate(organisms []*Organism, context *neat.NeatContext)
 				// compare current organism with first organism in current specie
 				if comp_org != nil {
 					curr_compat := curr_org.Genotype.compatibility(comp_org.Genotype, context)
-					if curr_compat < context.CompatThreshold && curr_compat < best_compat_value {
+					if curr_compat < compat_threshold && curr_compat < best_compat_value {
 						best_compatible = curr_species
 						best_compat_value = curr_compat
 						done = true
@@ -334,7 +481,7 @@ func (p *Population) speciate(organisms []*Organism, context *neat.NeatContext)
 				curr_org.Species = best_compatible
 			} else {
 				// If we didn't find a match, create a new species
-				createFirstSpecies(p, curr_org)
+				createFirstSpecies(p, curr_org, context)
 			}
 		}
 	}
@@ -343,8 +490,9 @@ func (p *Population) speciate(organisms []*Organism, context *neat.NeatContext)
 }
 
 // Removes zero offspring species from this population, i.e. species which will not have any offspring organism belonging to it
-// after reproduction cycle due to its fitness stagnation
-func (p *Population) purgeZeroOffspringSpecies(generation int) {
+// after reproduction cycle due to its fitness stagnation. The offspring are allocated among species before according
+// to the offspring allocation type configured by context.
+func (p *Population) purgeZeroOffspringSpecies(generation int, context *neat.NeatContext) error {
 	// Used to compute average fitness over all Organisms
 	total := 0.0
 	total_organisms := len(p.Organisms)
@@ -359,57 +507,10 @@ func (p *Population) purgeZeroOffspringSpecies(generation int) {
 		"POPULATION: Generation %d: overall average fitness = %.3f, # of organisms: %d, # of species: %d\n",
 		generation, overall_average, len(p.Organisms), len(p.Species)))
 
-	// Now compute expected number of offspring for each individual organism
-	if overall_average != 0 {
-		for _, o := range p.Organisms {
-			o.ExpectedOffspring = o.Fitness / overall_average
-		}
-	}
-
-	//The fractional parts of expected offspring that can be used only when they accumulate above 1 for the purposes
-	// of counting Offspring
-	skim := 0.0
-	// precision checking
-	total_expected := 0
-
-	// Now add those offspring up within each Species to get the number of offspring per Species
-	for _, sp := range p.Species {
-		sp.ExpectedOffspring, skim = sp.countOffspring(skim)
-		total_expected += sp.ExpectedOffspring
-	}
-	neat.DebugLog(fmt.Sprintf("POPULATION: Total expected offspring count: %d", total_expected))
-
-	// Need to make up for lost floating point precision in offspring assignment.
-	// If we lost precision, give an extra baby to the best Species
-	if total_expected < total_organisms {
-		// Find the Species expecting the most
-		var best_species *Species
-		max_expected := 0
-		final_expected := 0
-		for _, sp := range p.Species {
-			if sp.ExpectedOffspring >= max_expected {
-				max_expected = sp.ExpectedOffspring
-				best_species = sp
-			}
-			final_expected += sp.ExpectedOffspring
-		}
-		// Give the extra offspring to the best species
-		best_species.ExpectedOffspring += 1
-		final_expected++
-
-		// If we still aren't at total, there is a problem. Note that this can happen if a stagnant Species
-		// dominates the population and then gets killed off by its age. Then the whole population plummets in
-		// fitness. If the average fitness is allowed to hit 0, then we no longer have an average we can use to
-		// assign offspring.
-		if final_expected < total_organisms {
-			neat.DebugLog(
-				fmt.Sprintf("POPULATION: Population died !!! (expected/total) %d/%d",
-					final_expected, total_organisms))
-			for _, sp := range p.Species {
-				sp.ExpectedOffspring = 0
-			}
-			best_species.ExpectedOffspring = total_organisms
-		}
+	// Now compute the number of offspring per Species, the population may shrink before reproduction, e.g. after
+	// extinction event, thus offspring allocated to fill the population size
+	if err := p.allocateOffspring(context.PopSize, context); err != nil {
+		return err
 	}
 
 	// Remove stagnated species which can not produce any offspring any more
@@ -417,9 +518,12 @@ func (p *Population) purgeZeroOffspringSpecies(generation int) {
 	for _, sp := range p.Species {
 		if sp.ExpectedOffspring > 0 {
 			species_to_keep = append(species_to_keep, sp)
+		} else {
+			p.notifySpeciesExtinct(generation, sp)
 		}
 	}
 	p.Species = species_to_keep
+	return nil
 }
 
 // When population stagnation detected the delta coding will be performed in attempt to fix this
@@ -458,14 +562,16 @@ func (p *Population) deltaCoding(sorted_species []*Species, context *neat.NeatCo
 }
 
 // The system can take expected offspring away from worse species and give them
-// to superior species depending on the system parameter BabiesStolen (when BabiesStolen > 0)
+// to superior species depending on the system parameter BabiesStolen (when BabiesStolen > 0).
+// The stolen offspring assigned to the champions of the receiving species as super champion offspring, which will be
+// produced by cloning or mutant cloning of the champion during reproduction. The total number of expected offspring
+// in population is not changed.
 func (p *Population) giveBabiesToTheBest(sorted_species []*Species, context *neat.NeatContext) {
 	stolen_babies := 0 // Babies taken from the bad species and given to the champs
 
-	curr_species := sorted_species[0] // the best species
-	// Take away a constant number of expected offspring from the worst few species
-	for i := len(sorted_species) - 1; i >= 0 && stolen_babies < context.BabiesStolen; i-- {
-		curr_species = sorted_species[i]
+	// Take away a constant number of expected offspring from the worst few species, the best species is never robbed
+	for i := len(sorted_species) - 1; i > 0 && stolen_babies < context.BabiesStolen; i-- {
+		curr_species := sorted_species[i]
 		if curr_species.Age > 5 && curr_species.ExpectedOffspring > 2 {
 			if curr_species.ExpectedOffspring - 1 >= context.BabiesStolen - stolen_babies {
 				// This species has enough to finish off the stolen pool
@@ -487,51 +593,65 @@ func (p *Population) giveBabiesToTheBest(sorted_species []*Species, context *nea
 	// They will get, in order, 1/5 1/5 and 1/10 of the stolen babies
 	stolen_blocks := []int{context.BabiesStolen / 5, context.BabiesStolen / 5, context.BabiesStolen / 10}
 	block_index := 0
-	for _, curr_species = range sorted_species {
+	for _, curr_species := range sorted_species {
+		if stolen_babies <= 0 {
+			break
+		}
 		if curr_species.lastImproved() > context.DropOffAge {
 			// Don't give a chance to dying species even if they are champs
 			continue
 		}
 
-		if block_index < 3 && stolen_babies >= stolen_blocks[block_index] {
+		give_babies := 0
+		if block_index < len(stolen_blocks) {
 			// Give stolen babies to the top three in 1/5 1/5 and 1/10 ratios
-			curr_species.Organisms[0].superChampOffspring = stolen_blocks[block_index]
-			curr_species.ExpectedOffspring += stolen_blocks[block_index]
-			stolen_babies -= stolen_blocks[block_index]
-		} else if block_index >= 3 {
-			// Give stolen to the rest in random ratios
-			if rand.Float64() > 0.1 {
-				// Randomize a little which species get boosted by a super champ
-				if stolen_babies > 3 {
-					curr_species.Organisms[0].superChampOffspring = 3
-					curr_species.ExpectedOffspring += 3
-					stolen_babies -= 3
-				} else {
-					curr_species.Organisms[0].superChampOffspring = stolen_babies
-					curr_species.ExpectedOffspring += stolen_babies
-					stolen_babies = 0
-				}
+			if stolen_babies >= stolen_blocks[block_index] {
+				give_babies = stolen_blocks[block_index]
+			}
+			block_index++
+		} else if context.Rand().Float64() > 0.1 {
+			// Give stolen to the rest in random ratios. Randomize a little which species get boosted by a super champ
+			give_babies = 3
+			if stolen_babies < give_babies {
+				give_babies = stolen_babies
 			}
 		}
-
-		if stolen_babies <= 0 {
-			break
-		}
-		block_index++
+		curr_species.Organisms[0].superChampOffspring += give_babies
+		curr_species.ExpectedOffspring += give_babies
+		stolen_babies -= give_babies
 	}
 	// If any stolen babies aren't taken, give them to species #1's champ
 	if stolen_babies > 0 {
-		curr_species = sorted_species[0]
+		curr_species := sorted_species[0]
 		curr_species.Organisms[0].superChampOffspring += stolen_babies
 		curr_species.ExpectedOffspring += stolen_babies
 	}
 }
 
+// Removes species which has no organisms left, e.g. after their organisms were replaced by immigrants or removed
+// from population. The species may become empty in the middle of the epoch cycle, thus this method should be invoked
+// before any processing which assumes that species has at least one organism, e.g. fitness adjustment, offspring
+// counting, or reproduction. Returns the number of removed species.
+func (p *Population) removeEmptySpecies(generation int) int {
+	species := make([]*Species, 0, len(p.Species))
+	for _, sp := range p.Species {
+		if len(sp.Organisms) > 0 {
+			species = append(species, sp)
+		} else {
+			neat.DebugLog(fmt.Sprintf("POPULATION: >> Empty species [%d] removed", sp.Id))
+			p.notifySpeciesExtinct(generation, sp)
+		}
+	}
+	removed := len(p.Species) - len(species)
+	p.Species = species
+	return removed
+}
+
 // Purge from population all organisms marked to be eliminated
 func (p *Population) purgeOrganisms() error {
 	org_to_keep := make([]*Organism, 0)
 	for _, curr_org := range p.Organisms {
-		if curr_org.toEliminate {
+		if curr_org.IsMarkedForDeath() {
 			// Remove the organism from its Species
 			_, err := curr_org.Species.removeOrganism(curr_org)
 			if err != nil {
@@ -569,7 +689,7 @@ func (p *Population) purgeOldGeneration(best_species_id int) error {
 
 // Removes all empty Species and age ones that survive.
 // As this happens, create master organism list for the new generation.
-func (p *Population) purgeOrAgeSpecies() {
+func (p *Population) purgeOrAgeSpecies(generation int) {
 	org_count := 0
 	species_to_keep := make([]*Species, 0)
 	for _, curr_species := range p.Species {
@@ -588,9 +708,10 @@ func (p *Population) purgeOrAgeSpecies() {
 			}
 			// keep this species
 			species_to_keep = append(species_to_keep, curr_species)
-		} else if neat.LogLevel == neat.LogLevelDebug {
+		} else {
 			neat.DebugLog(fmt.Sprintf("POPULATION: >> Species [%d] have not survived reproduction!",
 				curr_species.Id))
+			p.notifySpeciesExtinct(generation, curr_species)
 		}
 	}
 	// Keep only survived species
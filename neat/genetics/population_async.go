@@ -0,0 +1,154 @@
+package genetics
+
+import (
+	"context"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The type of epoch progress event
+type EpochProgressType int
+const (
+	// The epoch started
+	EpochStartedProgress EpochProgressType = iota
+	// The species produced its offspring
+	SpeciesReproducedProgress
+	// The produced offspring speciated within population
+	SpeciationProgress
+	// The epoch finished either successfully or with error
+	EpochDoneProgress
+)
+
+// The event about progress of epoch executed asynchronously, see Population.EpochAsync
+type EpochProgress struct {
+	// The type of event
+	Type              EpochProgressType
+	// The generation of epoch
+	Generation        int
+	// The species which produced offspring for SpeciesReproducedProgress event, nil otherwise
+	Species           *Species
+	// The number of species which already produced offspring and the total number of species to reproduce
+	SpeciesReproduced int
+	SpeciesTotal      int
+	// The number of offspring already produced and the total number of offspring expected in this epoch
+	Offspring         int
+	OffspringTotal    int
+	// The percent of epoch completed in range [0, 100] estimated by the share of expected offspring produced
+	Percent           float64
+	// The error of epoch for EpochDoneProgress event, nil if epoch finished successfully
+	Err               error
+}
+
+// Executes the next epoch of this population with given executor in separate GO routine and returns the channel of
+// progress events, allowing to show the live progress of slow generations. The channel is closed after EpochDoneProgress
+// event delivered. The epoch is never blocked by slow consumer: if channel buffer is full, the oldest pending event is
+// dropped in favor of the new one, thus the consumer always gets the latest progress and the EpochDoneProgress event.
+// The population should not be accessed until channel closed. If ctx canceled before epoch started, the epoch is not
+// executed and EpochDoneProgress event holds ctx error, otherwise the epoch runs to completion to keep population
+// consistent and cancellation only stops delivery of the following events, except EpochDoneProgress event which is
+// delivered if channel buffer has free space.
+func (p *Population) EpochAsync(ctx context.Context, executor PopulationEpochExecutor, generation int, conf *neat.NeatContext) <-chan EpochProgress {
+	observer := &asyncEpochObserver{
+		ctx:ctx,
+		pop:p,
+		events:make(chan EpochProgress, len(p.Species) + 3),
+	}
+	p.AddObserver(observer)
+	go func() {
+		defer close(observer.events)
+		err := ctx.Err()
+		if err == nil {
+			err = executor.NextEpoch(generation, p, conf)
+		}
+		p.RemoveObserver(observer)
+
+		done := observer.progress(EpochDoneProgress, generation)
+		if err == nil {
+			done.Percent = 100.0
+		}
+		done.Err = err
+		if ctx.Err() == nil {
+			observer.send(done)
+		} else {
+			// deliver without blocking, the events may be not consumed anymore
+			select {
+			case observer.events <- done:
+			default:
+			}
+		}
+	}()
+	return observer.events
+}
+
+// The epoch observer which streams progress events into channel
+type asyncEpochObserver struct {
+	BaseEpochObserver
+	ctx               context.Context
+	pop               *Population
+	events            chan EpochProgress
+	// The number of species which produced offspring and the number of produced offspring
+	speciesReproduced int
+	offspring         int
+	// The total number of species to reproduce and offspring expected, known after offspring allocated
+	speciesTotal      int
+	offspringTotal    int
+}
+
+func (o *asyncEpochObserver) OnGenerationStart(generation int, pop *Population) {
+	o.send(o.progress(EpochStartedProgress, generation))
+}
+
+func (o *asyncEpochObserver) OnSpeciesReproduced(generation int, species *Species, offspring int) {
+	if o.speciesReproduced == 0 {
+		o.speciesTotal = len(o.pop.Species)
+		for _, sp := range o.pop.Species {
+			o.offspringTotal += sp.ExpectedOffspring
+		}
+	}
+	o.speciesReproduced++
+	o.offspring += offspring
+	event := o.progress(SpeciesReproducedProgress, generation)
+	event.Species = species
+	o.send(event)
+}
+
+func (o *asyncEpochObserver) OnSpeciation(generation int, pop *Population) {
+	event := o.progress(SpeciationProgress, generation)
+	event.Percent = 100.0
+	o.send(event)
+}
+
+// Creates progress event of given type with the current state of reproduction cycle
+func (o *asyncEpochObserver) progress(event_type EpochProgressType, generation int) EpochProgress {
+	event := EpochProgress{
+		Type:event_type,
+		Generation:generation,
+		SpeciesReproduced:o.speciesReproduced,
+		SpeciesTotal:o.speciesTotal,
+		Offspring:o.offspring,
+		OffspringTotal:o.offspringTotal,
+	}
+	if o.offspringTotal > 0 {
+		event.Percent = 100.0 * float64(o.offspring) / float64(o.offspringTotal)
+	}
+	return event
+}
+
+// Sends event into channel without blocking unless context canceled. If channel buffer is full, the oldest pending
+// event is dropped to make room for the given one.
+func (o *asyncEpochObserver) send(event EpochProgress) {
+	if o.ctx.Err() != nil {
+		return
+	}
+	for {
+		select {
+		case o.events <- event:
+			return
+		default:
+		}
+		// coalesce with pending events, the consumer may have received the oldest one meanwhile
+		select {
+		case <-o.events:
+		default:
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package genetics
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestPopulation_EpochAsync(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.2,
+		BabiesStolen:10,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+		ReplacementFraction:0.2,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	executors := []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{}, &ParallelPopulationEpochExecutor{},
+		&SteadyStatePopulationEpochExecutor{}}
+	for i, ex := range executors {
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64()
+		}
+		species := len(pop.Species)
+		events := make([]EpochProgress, 0)
+		for event := range pop.EpochAsync(context.Background(), ex, i + 1, &conf) {
+			events = append(events, event)
+		}
+		// started, species reproduced, speciation and done events
+		if len(events) != species + 3 {
+			t.Error("Wrong number of events", i, len(events), species)
+			continue
+		}
+		if events[0].Type != EpochStartedProgress || events[0].Percent != 0 {
+			t.Error("Wrong start event", i, events[0])
+		}
+		percent := 0.0
+		for j, event := range events[1:species + 1] {
+			if event.Type != SpeciesReproducedProgress || event.Species == nil {
+				t.Error("Wrong species reproduced event", i, event)
+			}
+			if event.SpeciesReproduced != j + 1 || event.SpeciesTotal != species {
+				t.Error("Wrong species progress", i, event.SpeciesReproduced, event.SpeciesTotal)
+			}
+			if event.Percent < percent || event.Percent > 100.0 {
+				t.Error("Wrong percent of progress", i, event.Percent, percent)
+			}
+			percent = event.Percent
+		}
+		last := events[species]
+		if last.Offspring != last.OffspringTotal || last.Percent != 100.0 {
+			t.Error("All expected offspring should be produced", i, last.Offspring, last.OffspringTotal)
+		}
+		if events[species + 1].Type != SpeciationProgress {
+			t.Error("Wrong speciation event", i, events[species + 1])
+		}
+		if done := events[species + 2]; done.Type != EpochDoneProgress || done.Err != nil || done.Percent != 100.0 {
+			t.Error("Wrong done event", i, done)
+		}
+		if len(pop.observers) != 0 {
+			t.Error("The progress observer should be removed", i, len(pop.observers))
+		}
+	}
+}
+
+func TestPopulation_EpochAsync_canceled(t *testing.T) {
+	pop, err := NewPopulation(buildTestGenome(1), &neat.NeatContext{PopSize:10, CompatThreshold:0.5})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	organisms := append([]*Organism(nil), pop.Organisms...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	events := make([]EpochProgress, 0)
+	for event := range pop.EpochAsync(ctx, &SequentialPopulationEpochExecutor{}, 1, &neat.NeatContext{}) {
+		events = append(events, event)
+	}
+	if len(events) != 1 || events[0].Type != EpochDoneProgress || events[0].Err != context.Canceled {
+		t.Error("Only done event with cancellation error expected", events)
+	}
+	for i, org := range organisms {
+		if pop.Organisms[i] != org {
+			t.Error("The epoch should not be executed after cancellation")
+			break
+		}
+	}
+}
+
+func TestAsyncEpochObserver_send(t *testing.T) {
+	observer := &asyncEpochObserver{ctx:context.Background(), events:make(chan EpochProgress, 2)}
+	// the events are not consumed, thus sending should not block but drop the oldest ones
+	for generation := 1; generation <= 5; generation++ {
+		observer.send(EpochProgress{Type:SpeciationProgress, Generation:generation})
+	}
+	if len(observer.events) != 2 {
+		t.Error("Wrong number of pending events", len(observer.events))
+		return
+	}
+	for _, generation := range []int{4, 5} {
+		if event := <-observer.events; event.Generation != generation {
+			t.Error("The latest events expected", generation, event.Generation)
+		}
+	}
+
+	// nothing sent after cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	observer.ctx = ctx
+	observer.send(EpochProgress{Type:EpochDoneProgress})
+	if len(observer.events) != 0 {
+		t.Error("No events expected after cancellation", len(observer.events))
+	}
+}
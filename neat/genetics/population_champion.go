@@ -0,0 +1,131 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The default number of champion clones reinjected into population after regression detected
+const defaultChampionRegressionClones = 1
+
+// The record about champion of population in particular generation
+type ChampionRecord struct {
+	// The generation when champion was found
+	Generation int
+	// The champion organism, i.e. the organism with the best original fitness in population. It is retained by
+	// the record even after removal from population.
+	Organism   *Organism
+	// The original (raw) fitness of the champion
+	Fitness    float64
+	// Indicates whether champion achieved new population record fitness
+	IsRecord   bool
+}
+
+// Returns the champion of the last generation processed by epoch executor or nil if there was no epoch yet
+func (p *Population) Champion() *Organism {
+	if len(p.champions) == 0 {
+		return nil
+	}
+	return p.champions[len(p.champions) - 1].Organism
+}
+
+// Returns the records about population champions per each generation processed by epoch executor in order of
+// generations. The returned slice is a copy and can be modified by caller.
+func (p *Population) ChampionHistory() []ChampionRecord {
+	history := make([]ChampionRecord, len(p.champions))
+	copy(history, p.champions)
+	return history
+}
+
+// Finds the champion among organisms of this population, i.e. the organism with the best original fitness in given
+// objective direction with ties broken like in ByOriginalFitness, marks it as population champion and appends it into
+// champions history. It should be invoked after adjustment of fitness in all species. This method doesn't update
+// HighestFitness of population. Returns the champion record.
+func (p *Population) updateChampion(generation int, direction ObjectiveDirection) ChampionRecord {
+	var champion *Organism
+	for _, org := range p.Organisms {
+		org.isPopulationChampion = false
+		if champion == nil || lessByFitness(direction.maximized(champion.originalFitness),
+			direction.maximized(org.originalFitness), champion, org) {
+			champion = org
+		}
+	}
+	if champion == nil {
+		return ChampionRecord{Generation:generation}
+	}
+	champion.isPopulationChampion = true
+
+	record := ChampionRecord{
+		Generation:generation,
+		Organism:champion,
+		Fitness:champion.originalFitness,
+		IsRecord:direction.IsRecord(champion.originalFitness, p.HighestFitness),
+	}
+	p.champions = append(p.champions, record)
+	return record
+}
+
+// Returns the preserved champion, i.e. the copy of the last population record holder with its raw fitness, or nil if
+// champion regression detection is not configured by context or there was no record yet. The fitness of preserved
+// champion may be updated by re-evaluation each generation, e.g. if fitness is noisy, before the next epoch.
+func (p *Population) PreservedChampion() *Organism {
+	return p.preservedChampion
+}
+
+// Preserves copy of given champion if it achieved population record fitness and champion regression detection is
+// configured by context
+func (p *Population) preserveChampion(champion ChampionRecord, context *neat.NeatContext) error {
+	if context.ChampionRegressionGenerations <= 0 || !champion.IsRecord {
+		return nil
+	}
+	preserved, err := champion.Organism.Clone()
+	if err != nil {
+		return err
+	}
+	// the fitness of champion is already adjusted within species
+	preserved.Fitness = champion.Fitness
+	p.preservedChampion = preserved
+	p.championRegressions = 0
+	return nil
+}
+
+// Checks whether the best organism of population is worse than preserved champion by more than tolerance configured
+// by context. If it happens context.ChampionRegressionGenerations generations in a row, the clones of preserved
+// champion replace the least fit organisms of population and speciated, thus the champion is reinjected into its
+// species if it still exists. It should be invoked after evaluation of organisms and before fitness adjustment.
+func (p *Population) checkChampionRegression(generation int, context *neat.NeatContext) error {
+	if context.ChampionRegressionGenerations <= 0 || p.preservedChampion == nil {
+		return nil
+	}
+	direction := objectiveDirection(context)
+	best := direction.BestOrganism(p.Organisms)
+	if best == nil {
+		return nil
+	}
+	regression := direction.maximized(p.preservedChampion.Fitness) - direction.maximized(best.Fitness)
+	if regression > context.ChampionRegressionTolerance {
+		p.championRegressions++
+	} else {
+		p.championRegressions = 0
+	}
+	if p.championRegressions < context.ChampionRegressionGenerations {
+		return nil
+	}
+
+	clones := context.ChampionRegressionClones
+	if clones <= 0 {
+		clones = defaultChampionRegressionClones
+	}
+	if clones > len(p.Organisms) - 1 {
+		// keep the best organism of population
+		clones = len(p.Organisms) - 1
+	}
+	neat.InfoLog(fmt.Sprintf("POPULATION: Champion regression for %d generations, the best fitness: %f, champion fitness: %f, reinject %d clones\n",
+		p.championRegressions, best.Fitness, p.preservedChampion.Fitness, clones))
+	p.championRegressions = 0
+	migrants := make([]*Organism, clones)
+	for i := range migrants {
+		migrants[i] = p.preservedChampion
+	}
+	return p.replaceLeastFit(migrants, generation, context)
+}
@@ -0,0 +1,255 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestPopulation_updateChampion(t *testing.T) {
+	pop := newPopulation()
+	if pop.Champion() != nil || len(pop.ChampionHistory()) != 0 {
+		t.Error("No champion expected before the first epoch")
+	}
+	if record := pop.updateChampion(1, MaximizeObjective); record.Organism != nil {
+		t.Error("No champion expected in empty population", record)
+	}
+
+	pop.Organisms = buildOrganismsForSorting(1.0, 3.0, 2.0, 3.0)
+	for _, org := range pop.Organisms {
+		// the adjusted fitness should be ignored
+		org.Fitness = 10.0 - org.originalFitness
+	}
+	pop.Organisms[0].isPopulationChampion = true
+
+	record := pop.updateChampion(1, MaximizeObjective)
+	if record.Organism != pop.Organisms[3] || record.Fitness != 3.0 || !record.IsRecord || record.Generation != 1 {
+		t.Error("Wrong champion record", record)
+	}
+	for i, org := range pop.Organisms {
+		if org.IsPopulationChampion() != (i == 3) {
+			t.Error("Only champion should be flagged", i)
+		}
+	}
+	if pop.Champion() != pop.Organisms[3] {
+		t.Error("Wrong champion", pop.Champion())
+	}
+
+	// the champion without record fitness
+	pop.HighestFitness = 3.0
+	pop.Organisms[3].originalFitness = 0.5
+	if record = pop.updateChampion(2, MaximizeObjective); record.Organism != pop.Organisms[1] || record.IsRecord {
+		t.Error("Wrong champion record", record)
+	}
+	history := pop.ChampionHistory()
+	if len(history) != 2 || history[0].Generation != 1 || history[1].Generation != 2 {
+		t.Error("Wrong champions history", history)
+	}
+	history[0].Fitness = -1
+	if pop.ChampionHistory()[0].Fitness != 3.0 {
+		t.Error("Champions history should be copied")
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_champions(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.2,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	observer := &testEpochObserver{}
+	pop.AddObserver(observer)
+
+	ex := SequentialPopulationEpochExecutor{}
+	generations := 10
+	for i := 0; i < generations; i++ {
+		max_fitness := 0.0
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64()
+			if org.Fitness > max_fitness {
+				max_fitness = org.Fitness
+			}
+		}
+		if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if champ := pop.Champion(); champ == nil || !champ.IsPopulationChampion() || champ.originalFitness != max_fitness {
+			t.Error("Wrong champion at generation", i + 1, champ)
+		}
+	}
+
+	history := pop.ChampionHistory()
+	if len(history) != generations {
+		t.Error("Wrong length of champions history", len(history))
+		return
+	}
+	highest, records := 0.0, 0
+	for i, record := range history {
+		if record.Generation != i + 1 {
+			t.Error("Wrong generation of champion record", record.Generation)
+		}
+		if record.IsRecord != (record.Fitness > highest) {
+			t.Error("Wrong record flag at generation", record.Generation, record.Fitness, highest)
+		}
+		if record.IsRecord {
+			highest = record.Fitness
+			records++
+		}
+	}
+	if pop.HighestFitness != highest {
+		t.Error("Wrong highest fitness", pop.HighestFitness, highest)
+	}
+	if len(observer.champions) != records {
+		t.Error("Observer should be notified about each record", len(observer.champions), records)
+	}
+}
+
+func TestPopulation_checkChampionRegression(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		PopSize:10,
+		ChampionRegressionGenerations:2,
+		ChampionRegressionTolerance:0.1,
+		ChampionRegressionClones:3,
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, org := range pop.Organisms {
+		org.Fitness = float64(i)
+		org.originalFitness = org.Fitness
+	}
+	record := pop.updateChampion(1, MaximizeObjective)
+	if err = pop.preserveChampion(record, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	preserved := pop.PreservedChampion()
+	if preserved == nil || preserved == record.Organism || preserved.Fitness != 9.0 {
+		t.Error("The copy of record holder should be preserved", preserved)
+		return
+	}
+
+	// the regression within tolerance resets counter
+	setFitness := func(fitness float64) {
+		for _, org := range pop.Organisms {
+			org.Fitness = fitness
+		}
+	}
+	for _, fitness := range []float64{1.0, 8.95, 1.0} {
+		setFitness(fitness)
+		if err = pop.checkChampionRegression(2, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if countClones(pop, preserved) != 0 {
+			t.Error("No clones expected before regression detected", fitness)
+		}
+	}
+	// the second generation of regression in a row
+	setFitness(1.0)
+	if err = pop.checkChampionRegression(3, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Organisms) != conf.PopSize {
+		t.Error("The population size should be kept", len(pop.Organisms))
+	}
+	if clones := countClones(pop, preserved); clones != conf.ChampionRegressionClones {
+		t.Error("Wrong number of reinjected clones", clones)
+	}
+	total := 0
+	for _, sp := range pop.Species {
+		total += len(sp.Organisms)
+	}
+	if total != conf.PopSize {
+		t.Error("All organisms should be speciated", total)
+	}
+	if pop.championRegressions != 0 {
+		t.Error("Regressions counter should be reset", pop.championRegressions)
+	}
+}
+
+func TestPopulation_checkChampionRegression_minimize(t *testing.T) {
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		PopSize:3,
+		ObjectiveDirection:int(MinimizeObjective),
+		ChampionRegressionGenerations:1,
+		ChampionRegressionClones:5,
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, org := range pop.Organisms {
+		org.Fitness = 0.5
+	}
+	if pop.preservedChampion, err = pop.Organisms[0].Clone(); err != nil {
+		t.Error(err)
+		return
+	}
+	pop.preservedChampion.Fitness = 1.0
+	if err = pop.checkChampionRegression(1, &conf); err != nil || countClones(pop, pop.preservedChampion) != 0 {
+		t.Error("No regression expected when error is lower than champion's", err)
+	}
+
+	for _, org := range pop.Organisms {
+		org.Fitness = 2.0
+	}
+	if err = pop.checkChampionRegression(2, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	// the best organism of population is kept
+	if clones := countClones(pop, pop.preservedChampion); clones != len(pop.Organisms) - 1 {
+		t.Error("Wrong number of reinjected clones", clones)
+	}
+}
+
+func TestPopulation_preserveChampion_disabled(t *testing.T) {
+	pop := newPopulation()
+	pop.Organisms = buildOrganismsForSorting(1.0, 3.0)
+	record := pop.updateChampion(1, MaximizeObjective)
+	if err := pop.preserveChampion(record, &neat.NeatContext{}); err != nil || pop.PreservedChampion() != nil {
+		t.Error("No champion should be preserved when regression detection is not configured", err)
+	}
+	record.IsRecord = false
+	if err := pop.preserveChampion(record, &neat.NeatContext{ChampionRegressionGenerations:1}); err != nil || pop.PreservedChampion() != nil {
+		t.Error("No champion should be preserved without record fitness", err)
+	}
+}
+
+// Returns the number of organisms in population cloned from given champion
+func countClones(pop *Population, champion *Organism) int {
+	count := 0
+	for _, org := range pop.Organisms {
+		if len(org.ParentLineageIds) == 1 && org.ParentLineageIds[0] == champion.LineageId {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,110 @@
+package genetics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// The summary of changes between two snapshots of population, e.g. between the checkpoints of consecutive
+// generations. The organisms are identified by Organism.LineageId and species by Species.Id, thus the snapshots should
+// be taken from the same evolutionary run.
+type PopulationDiff struct {
+	// The organisms of the later snapshot which are absent in the earlier one ordered by lineage ID
+	Births           []*Organism
+	// The organisms of the earlier snapshot which are absent in the later one ordered by lineage ID
+	Deaths           []*Organism
+	// The species of the later snapshot which are absent in the earlier one ordered by ID
+	SpeciesCreated   []*Species
+	// The species of the earlier snapshot which are absent in the later one ordered by ID
+	SpeciesExtinct   []*Species
+	// The champions of the earlier and the later snapshots, nil if not known, see Population.Champion
+	PreviousChampion *Organism
+	Champion         *Organism
+}
+
+// Takes snapshot of this population, i.e. its shallow copy which retains the current organisms, species and
+// champions history unchanged by the following epochs. The snapshot is intended to be compared with DiffPopulations
+// and should not be evolved further.
+func (p *Population) Snapshot() *Population {
+	snapshot := *p
+	snapshot.Organisms = append([]*Organism(nil), p.Organisms...)
+	snapshot.Species = append([]*Species(nil), p.Species...)
+	snapshot.champions = append([]ChampionRecord(nil), p.champions...)
+	return &snapshot
+}
+
+// Returns the summary of changes from population snapshot a to snapshot b, i.e. the organisms born and died, the
+// species created and extinct, and the change of champion.
+func DiffPopulations(a, b *Population) *PopulationDiff {
+	return &PopulationDiff{
+		Births:diffOrganisms(b.Organisms, a.Organisms),
+		Deaths:diffOrganisms(a.Organisms, b.Organisms),
+		SpeciesCreated:diffSpecies(b.Species, a.Species),
+		SpeciesExtinct:diffSpecies(a.Species, b.Species),
+		PreviousChampion:a.Champion(),
+		Champion:b.Champion(),
+	}
+}
+
+// Returns true if champion of population changed between snapshots, i.e. the champions are different organisms
+func (d *PopulationDiff) ChampionChanged() bool {
+	if d.PreviousChampion == nil || d.Champion == nil {
+		return d.PreviousChampion != d.Champion
+	}
+	return d.PreviousChampion.LineageId != d.Champion.LineageId
+}
+
+// Returns the short summary of changes
+func (d *PopulationDiff) String() string {
+	str := fmt.Sprintf("Births: %d, deaths: %d, species created: %d, species extinct: %d",
+		len(d.Births), len(d.Deaths), len(d.SpeciesCreated), len(d.SpeciesExtinct))
+	if d.ChampionChanged() {
+		str += fmt.Sprintf(", champion changed: %s -> %s", championLineage(d.PreviousChampion),
+			championLineage(d.Champion))
+	}
+	return str
+}
+
+// Returns the organisms from given list which lineage IDs are absent in other list ordered by lineage ID
+func diffOrganisms(organisms, other []*Organism) []*Organism {
+	ids := make(map[int64]bool, len(other))
+	for _, org := range other {
+		ids[org.LineageId] = true
+	}
+	diff := make([]*Organism, 0)
+	for _, org := range organisms {
+		if !ids[org.LineageId] {
+			diff = append(diff, org)
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].LineageId < diff[j].LineageId
+	})
+	return diff
+}
+
+// Returns the species from given list which IDs are absent in other list ordered by ID
+func diffSpecies(species, other []*Species) []*Species {
+	ids := make(map[int]bool, len(other))
+	for _, sp := range other {
+		ids[sp.Id] = true
+	}
+	diff := make([]*Species, 0)
+	for _, sp := range species {
+		if !ids[sp.Id] {
+			diff = append(diff, sp)
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Id < diff[j].Id
+	})
+	return diff
+}
+
+// Returns the lineage ID of champion as string or "none" if champion is not known
+func championLineage(champion *Organism) string {
+	if champion == nil {
+		return "none"
+	}
+	return fmt.Sprintf("#%d", champion.LineageId)
+}
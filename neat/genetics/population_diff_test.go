@@ -0,0 +1,121 @@
+package genetics
+
+import (
+	"math/rand"
+	"testing"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func buildDiffOrganisms(ids ...int64) []*Organism {
+	organisms := make([]*Organism, len(ids))
+	for i, id := range ids {
+		organisms[i] = &Organism{LineageId:id}
+	}
+	return organisms
+}
+
+func TestDiffPopulations(t *testing.T) {
+	a, b := newPopulation(), newPopulation()
+	a.Organisms = buildDiffOrganisms(3, 1, 2)
+	b.Organisms = buildDiffOrganisms(5, 2, 4)
+	a.Species = []*Species{newSpecies(1), newSpecies(2)}
+	b.Species = []*Species{newSpecies(3), newSpecies(2)}
+	a.champions = []ChampionRecord{{Generation:1, Organism:a.Organisms[2]}}
+	b.champions = []ChampionRecord{{Generation:2, Organism:b.Organisms[1]}}
+
+	diff := DiffPopulations(a, b)
+	checkDiffOrganisms(t, "Births", diff.Births, 4, 5)
+	checkDiffOrganisms(t, "Deaths", diff.Deaths, 1, 3)
+	if len(diff.SpeciesCreated) != 1 || diff.SpeciesCreated[0].Id != 3 {
+		t.Error("Wrong created species", diff.SpeciesCreated)
+	}
+	if len(diff.SpeciesExtinct) != 1 || diff.SpeciesExtinct[0].Id != 1 {
+		t.Error("Wrong extinct species", diff.SpeciesExtinct)
+	}
+	// the same lineage is champion in both snapshots
+	if diff.ChampionChanged() {
+		t.Error("diff.ChampionChanged()")
+	}
+	if str := diff.String(); str != "Births: 2, deaths: 2, species created: 1, species extinct: 1" {
+		t.Error("Wrong summary", str)
+	}
+
+	b.champions = append(b.champions, ChampionRecord{Generation:3, Organism:b.Organisms[0]})
+	diff = DiffPopulations(a, b)
+	if !diff.ChampionChanged() {
+		t.Error("!diff.ChampionChanged()")
+	}
+	if str := diff.String(); str != "Births: 2, deaths: 2, species created: 1, species extinct: 1, champion changed: #2 -> #5" {
+		t.Error("Wrong summary", str)
+	}
+
+	diff = DiffPopulations(newPopulation(), a)
+	if !diff.ChampionChanged() || diff.PreviousChampion != nil {
+		t.Error("The champion should change from unknown one", diff.PreviousChampion)
+	}
+	checkDiffOrganisms(t, "Births", diff.Births, 1, 2, 3)
+	if len(diff.Deaths) != 0 || len(diff.SpeciesExtinct) != 0 {
+		t.Error("Nothing should die in empty population", diff.Deaths, diff.SpeciesExtinct)
+	}
+}
+
+func TestPopulation_Snapshot(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 30,
+		BabiesStolen:10,
+		RecurOnlyProb:0.2,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, org := range pop.Organisms {
+		org.Fitness = float64(i + 1)
+	}
+	snapshot := pop.Snapshot()
+	organisms := append([]*Organism(nil), pop.Organisms...)
+
+	executor := SequentialPopulationEpochExecutor{}
+	if err = executor.NextEpoch(1, pop, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(snapshot.Organisms) != len(organisms) {
+		t.Error("The snapshot organisms changed by epoch", len(snapshot.Organisms))
+		return
+	}
+	for i, org := range organisms {
+		if snapshot.Organisms[i] != org {
+			t.Error("The snapshot organism changed by epoch at", i)
+		}
+	}
+	if snapshot.Champion() != nil {
+		t.Error("The snapshot taken before epoch should have no champion", snapshot.Champion())
+	}
+
+	diff := DiffPopulations(snapshot, pop)
+	if len(diff.Births) != len(pop.Organisms) || len(diff.Deaths) != len(organisms) {
+		t.Error("All organisms should be replaced by generational epoch", len(diff.Births), len(diff.Deaths))
+	}
+	if !diff.ChampionChanged() || diff.Champion != pop.Champion() {
+		t.Error("The champion should be found by epoch", diff.Champion)
+	}
+}
+
+func checkDiffOrganisms(t *testing.T, name string, organisms []*Organism, ids ...int64) {
+	if len(organisms) != len(ids) {
+		t.Error("Wrong number of", name, len(organisms))
+		return
+	}
+	for i, id := range ids {
+		if organisms[i].LineageId != id {
+			t.Error("Wrong", name, "at", i, organisms[i].LineageId, id)
+		}
+	}
+}
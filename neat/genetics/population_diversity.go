@@ -0,0 +1,76 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The genetic diversity metrics of population allowing to detect premature convergence
+type PopulationDiversity struct {
+	// The mean compatibility distance between all pairs of organisms in population
+	MeanCompatibility float64
+	// The variance of weights of all enabled links in population
+	WeightVariance    float64
+	// The number of unique topologies (genomes structures ignoring link weights) in population
+	UniqueTopologies  int
+	// The Shannon entropy (in nats) of organisms distribution among species. It is zero when all organisms belong to
+	// the single species and maximal when all species have equal size.
+	SpeciesEntropy    float64
+}
+
+// Computes genetic diversity metrics of this population. The compatibility distance is calculated as configured by
+// provided context.
+func (p *Population) GeneticDiversity(context *neat.NeatContext) *PopulationDiversity {
+	d := &PopulationDiversity{}
+
+	// mean pairwise compatibility
+	pairs := 0
+	for i := 0; i < len(p.Organisms); i++ {
+		for j := i + 1; j < len(p.Organisms); j++ {
+			d.MeanCompatibility += p.Organisms[i].Genotype.compatibility(p.Organisms[j].Genotype, context)
+			pairs++
+		}
+	}
+	if pairs > 0 {
+		d.MeanCompatibility /= float64(pairs)
+	}
+
+	// weights variance and unique topologies
+	sum, sum_sq, count := 0.0, 0.0, 0
+	topologies := make(map[uint64]bool)
+	for _, org := range p.Organisms {
+		for _, gn := range org.Genotype.Genes {
+			if gn.IsEnabled {
+				sum += gn.Link.Weight
+				sum_sq += gn.Link.Weight * gn.Link.Weight
+				count++
+			}
+		}
+		topologies[org.Genotype.TopologyHash()] = true
+	}
+	if count > 0 {
+		mean := sum / float64(count)
+		d.WeightVariance = math.Max(sum_sq / float64(count) - mean * mean, 0)
+	}
+	d.UniqueTopologies = len(topologies)
+
+	// species entropy
+	total := 0
+	for _, sp := range p.Species {
+		total += len(sp.Organisms)
+	}
+	for _, sp := range p.Species {
+		if size := len(sp.Organisms); size > 0 {
+			prob := float64(size) / float64(total)
+			d.SpeciesEntropy -= prob * math.Log(prob)
+		}
+	}
+
+	return d
+}
+
+func (d *PopulationDiversity) String() string {
+	return fmt.Sprintf("mean compatibility: %.3f, weight variance: %.3f, unique topologies: %d, species entropy: %.3f",
+		d.MeanCompatibility, d.WeightVariance, d.UniqueTopologies, d.SpeciesEntropy)
+}
@@ -0,0 +1,72 @@
+package genetics
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func TestPopulation_GeneticDiversity(t *testing.T) {
+	conf := neat.NeatContext{DisjointCoeff:1.0, ExcessCoeff:1.0, MutdiffCoeff:0.4}
+
+	// the single species with identical organisms
+	pop := newPopulation()
+	sp, err := buildSpeciesWithOrganisms(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pop.Species = []*Species{sp}
+	pop.Organisms = append(pop.Organisms, sp.Organisms...)
+
+	d := pop.GeneticDiversity(&conf)
+	if d.MeanCompatibility != 0 {
+		t.Error("MeanCompatibility", d.MeanCompatibility)
+	}
+	// weights: 1.5, 2.5, 3.5
+	if math.Abs(d.WeightVariance - 2.0 / 3.0) > 1e-9 {
+		t.Error("WeightVariance", d.WeightVariance)
+	}
+	if d.UniqueTopologies != 1 {
+		t.Error("UniqueTopologies", d.UniqueTopologies)
+	}
+	if d.SpeciesEntropy != 0 {
+		t.Error("SpeciesEntropy", d.SpeciesEntropy)
+	}
+
+	// add other species of the same size with different topology
+	other := NewSpecies(2)
+	for i := 0; i < 3; i++ {
+		gnome := buildTestGenome(i + 10)
+		gnome.Genes = gnome.Genes[:2]
+		org, err := NewOrganism(0.0, gnome, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		other.addOrganism(org)
+		pop.Organisms = append(pop.Organisms, org)
+	}
+	pop.Species = append(pop.Species, other)
+
+	d = pop.GeneticDiversity(&conf)
+	if d.MeanCompatibility <= 0 {
+		t.Error("MeanCompatibility", d.MeanCompatibility)
+	}
+	if d.UniqueTopologies != 2 {
+		t.Error("UniqueTopologies", d.UniqueTopologies)
+	}
+	if math.Abs(d.SpeciesEntropy - math.Log(2)) > 1e-9 {
+		t.Error("SpeciesEntropy", d.SpeciesEntropy)
+	}
+	if len(d.String()) == 0 {
+		t.Error("Empty string representation")
+	}
+}
+
+func TestPopulation_GeneticDiversity_empty(t *testing.T) {
+	d := newPopulation().GeneticDiversity(&neat.NeatContext{})
+	if d.MeanCompatibility != 0 || d.WeightVariance != 0 || d.UniqueTopologies != 0 || d.SpeciesEntropy != 0 {
+		t.Error("Zero diversity expected for empty population", d)
+	}
+}
@@ -17,6 +17,8 @@ const (
 	SequentialExecutorType EpochExecutorType = 0
 	// The parallel executor to perform reproduction cycle in parallel threads
 	ParallelExecutorType = 1
+	// The steady-state executor replacing only the worst fraction of population per epoch
+	SteadyStateExecutorType = 2
 )
 
 // Executes epoch's turnover for population of organisms
@@ -42,6 +44,9 @@ func (ex *SequentialPopulationEpochExecutor) NextEpoch(generation int, populatio
 		return err
 	}
 	err = ex.finalize(generation, population, context)
+	if err == nil {
+		population.notifyReproductionDone(generation)
+	}
 
 	neat.DebugLog(fmt.Sprintf("POPULATION: >>>>> Epoch %d complete\n", generation))
 
@@ -52,23 +57,54 @@ func (ex *SequentialPopulationEpochExecutor) prepare(generation int, p *Populati
 	// clear executor state from previous run
 	ex.sorted_species = nil
 
+	p.notifyGenerationStart(generation)
+
+	// The species may become empty after evaluation, e.g. when organisms replaced by immigrants
+	p.removeEmptySpecies(generation)
+	if len(p.Species) == 0 {
+		return newError(ErrNoSpecies, "POPULATION: No species with organisms left to reproduce")
+	}
+
 	// Use Species' ages to modify the objective fitness of organisms in other words, make it more fair for younger
 	// species so they have a chance to take hold and also penalize stagnant species. Then adjust the fitness using
 	// the species size to "share" fitness within a species. Then, within each Species, mark for death those below
-	// survival_thresh * average
+	// survival_thresh * average. The raw fitness may be scaled before to control selection pressure.
+	if err := guardFitness(p.Organisms, context); err != nil {
+		return err
+	}
+	// The extinction event kills part of population to maintain diversity if it is due
+	if _, err := p.applyExtinction(generation, context); err != nil {
+		return err
+	}
+	// The clones of preserved champion replace the least fit organisms if population regressed
+	if err := p.checkChampionRegression(generation, context); err != nil {
+		return err
+	}
+	scaled, err := scaleFitness(p.Organisms, generation, context)
+	if err != nil {
+		return err
+	}
 	for _, sp := range p.Species {
-		sp.adjustFitness(context)
+		if err = sp.adjustScaledFitness(context, scaled); err != nil {
+			return err
+		}
 	}
 
 	// find and remove species unable to produce offspring due to fitness stagnation
-	p.purgeZeroOffspringSpecies(generation)
+	if err = p.purgeZeroOffspringSpecies(generation, context); err != nil {
+		return err
+	}
+
+	if len(p.Species) == 0 {
+		return newError(ErrNoSpecies, "POPULATION: All species purged, no offspring allocated")
+	}
 
 	// Stick the Species pointers into a new Species list for sorting
 	ex.sorted_species = make([]*Species, len(p.Species))
 	copy(ex.sorted_species, p.Species)
 
 	// Sort the Species by max original fitness of its first organism
-	sort.Sort(sort.Reverse(byOrganismOrigFitness(ex.sorted_species)))
+	sort.Stable(sort.Reverse(byOrganismOrigFitness{ex.sorted_species, objectiveDirection(context)}))
 
 	// Used in debugging to see why (if) best species dies
 	ex.best_species_id = ex.sorted_species[0].Id
@@ -86,20 +122,13 @@ func (ex *SequentialPopulationEpochExecutor) prepare(generation int, p *Populati
 	}
 
 	// Check for Population-level stagnation
-	curr_species := ex.sorted_species[0]
-	curr_species.Organisms[0].isPopulationChampion = true // DEBUG marker of the best of pop
-	if curr_species.Organisms[0].originalFitness > p.HighestFitness {
-		p.HighestFitness = curr_species.Organisms[0].originalFitness
-		p.EpochsHighestLastChanged = 0
-		neat.DebugLog(fmt.Sprintf("POPULATION: NEW POPULATION RECORD FITNESS: %f of SPECIES with ID: %d\n", p.HighestFitness, ex.best_species_id))
-
-	} else {
-		p.EpochsHighestLastChanged += 1
-		neat.DebugLog(fmt.Sprintf(" generations since last population fitness record: %f\n", p.HighestFitness))
+	if err = p.trackChampion(generation, context); err != nil {
+		return err
 	}
 
 	// Check for stagnation - if there is stagnation, perform delta-coding
-	if p.EpochsHighestLastChanged >= context.DropOffAge + 5 {
+	if stagnation_limit := populationStagnationLimit(context);
+		stagnation_limit > 0 && p.EpochsHighestLastChanged >= stagnation_limit {
 		// Population stagnated - trying to fix it by delta coding
 		p.deltaCoding(ex.sorted_species, context)
 	} else if context.BabiesStolen > 0 {
@@ -109,10 +138,37 @@ func (ex *SequentialPopulationEpochExecutor) prepare(generation int, p *Populati
 	}
 
 	// Kill off all Organisms marked for death. The remainder will be allowed to reproduce.
-	err := p.purgeOrganisms()
+	err = p.purgeOrganisms()
 	return err
 }
 
+// Finds the champion of population and updates population's record fitness if champion beats it, otherwise counts
+// generation without record for stagnation detection. The record holder is preserved for champion regression detection.
+func (p *Population) trackChampion(generation int, context *neat.NeatContext) error {
+	champion := p.updateChampion(generation, objectiveDirection(context))
+	if champion.IsRecord {
+		p.HighestFitness = champion.Fitness
+		p.EpochsHighestLastChanged = 0
+		neat.DebugLog(fmt.Sprintf("POPULATION: NEW POPULATION RECORD FITNESS: %f of SPECIES with ID: %d\n", p.HighestFitness, champion.Organism.Species.Id))
+		p.notifyNewChampion(generation, champion.Organism)
+
+	} else {
+		p.EpochsHighestLastChanged += 1
+		neat.DebugLog(fmt.Sprintf(" generations since last population fitness record: %f\n", p.HighestFitness))
+	}
+	return p.preserveChampion(champion, context)
+}
+
+// Returns the number of generations without population's fitness record after which population considered stagnated.
+// Non positive value returned means that stagnation should be ignored.
+func populationStagnationLimit(context *neat.NeatContext) int {
+	if context.PopulationStagnationLimit == 0 {
+		// default value from original NEAT
+		return context.DropOffAge + 5
+	}
+	return context.PopulationStagnationLimit
+}
+
 // Do sequential reproduction cycle
 func (ex *SequentialPopulationEpochExecutor) reproduce(generation int, p *Population, context *neat.NeatContext) error {
 	neat.DebugLog("POPULATION: Start Sequential Reproduction Cycle >>>>>")
@@ -125,6 +181,7 @@ func (ex *SequentialPopulationEpochExecutor) reproduce(generation int, p *Popula
 		if err != nil {
 			return err
 		}
+		p.notifySpeciesReproduced(generation, sp, len(rep_babies))
 		if sp.Id == ex.best_species_id {
 			// store flag if best species reproduced - it will be used to determine if best species
 			// produced offspring before died
@@ -137,14 +194,20 @@ func (ex *SequentialPopulationEpochExecutor) reproduce(generation int, p *Popula
 
 	// sanity check - make sure that population size keep the same
 	if len(babies) != context.PopSize {
-		return errors.New(
-			fmt.Sprintf("POPULATION: Progeny size after reproduction cycle dimished.\nExpected: [%d], but got: [%d]",
-				context.PopSize, len(babies)))
+		return newError(ErrProgenySizeMismatch,
+			"POPULATION: Progeny size after reproduction cycle dimished.\nExpected: [%d], but got: [%d]",
+				context.PopSize, len(babies))
 	}
 
 
 	// speciate fresh progeny
 	err := p.speciate(babies, context)
+	if err == nil {
+		err = p.adjustCompatThreshold(context)
+	}
+	if err == nil {
+		p.notifySpeciation(generation)
+	}
 
 	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
 
@@ -160,7 +223,7 @@ func (ex *SequentialPopulationEpochExecutor) finalize(generation int, p *Populat
 
 	// Removes all empty Species and age ones that survive.
 	// As this happens, create master organism list for the new generation.
-	p.purgeOrAgeSpecies()
+	p.purgeOrAgeSpecies(generation)
 
 	// Remove the innovations of the current generation
 	p.Innovations = make([]*Innovation, 0)
@@ -182,7 +245,12 @@ func (ex *SequentialPopulationEpochExecutor) finalize(generation int, p *Populat
 	return err
 }
 
-// The population epoch executor with parallel reproduction cycle
+// The population epoch executor with parallel reproduction cycle. Each species produces its offspring in separate
+// GO routine, after that the produced offspring merged in the order species appear in population, i.e. the order of
+// progeny speciation doesn't depend on the order in which GO routines was completed. Each species draws random numbers
+// from its own stream derived from context.RandomSeed and registers innovations in private store, the final innovation
+// numbers and node IDs are assigned in the order of species after all completed, thus with the seed set the offspring
+// are the same in each run.
 type ParallelPopulationEpochExecutor struct {
 	sequential *SequentialPopulationEpochExecutor
 }
@@ -201,6 +269,9 @@ func (ex *ParallelPopulationEpochExecutor) NextEpoch(generation int, population
 	}
 
 	err = ex.sequential.finalize(generation, population, context)
+	if err == nil {
+		population.notifyReproductionDone(generation)
+	}
 
 	neat.DebugLog(fmt.Sprintf("POPULATION: >>>>> Epoch %d complete\n", generation))
 
@@ -213,18 +284,26 @@ func (ex *ParallelPopulationEpochExecutor) reproduce(generation int, p *Populati
 
 	// Perform reproduction. Reproduction is done on a per-Species basis
 	sp_num := len(p.Species)
-	res_chan := make(chan reproductionResult, sp_num)
+	// The results stored at the index of species to merge them in deterministic order
+	results := make([]reproductionResult, sp_num)
 	// The wait group to wait for all GO routines
 	var wg sync.WaitGroup
+	// The indexes of species which completed reproduction in order of completion
+	done := make(chan int, sp_num)
 
-	for _, curr_species := range p.Species {
+	// The private innovation stores of species, the innovations are merged in order of species when all completed
+	shadows := make([]*innovationShadow, sp_num)
+
+	for i, curr_species := range p.Species {
+		shadows[i] = p.newInnovationShadow()
+		// each species draws random numbers from its own stream
+		sp_context := context.WithRand(reproductionRand(context, generation, i))
 		wg.Add(1)
 		// run in separate GO thread
-		go func(sp *Species, generation int, p *Population, sorted_species []*Species,
-		context *neat.NeatContext, res_chan chan <- reproductionResult, wg *sync.WaitGroup) {
+		go func(sp *Species, generation int, shadow *Population, sorted_species []*Species,
+		context *neat.NeatContext, res *reproductionResult, wg *sync.WaitGroup, idx int) {
 
-			babies, err := sp.reproduce(generation, p, sorted_species, context)
-			res := reproductionResult{}
+			babies, err := sp.reproduce(generation, shadow, sorted_species, context)
 			if err == nil {
 				res.species_id = sp.Id
 
@@ -244,25 +323,31 @@ func (ex *ParallelPopulationEpochExecutor) reproduce(generation int, p *Populati
 			}
 			res.err = err
 
-			// write result to channel and signal to wait group
-			res_chan <- res
+			// signal to wait group that result is ready
 			wg.Done()
+			done <- idx
 
-		}(curr_species, generation, p, ex.sequential.sorted_species, context, res_chan, &wg)
+		}(curr_species, generation, shadows[i].pop, ex.sequential.sorted_species, sp_context, &results[i], &wg, i)
 	}
 
-	// wait for reproduction results
+	// notify about species reproduced as results become ready and wait for all of them
+	for range p.Species {
+		if i := <-done; results[i].err == nil {
+			p.notifySpeciesReproduced(generation, p.Species[i], results[i].babies_stored)
+		}
+	}
 	wg.Wait()
-	close(res_chan)
 
-	// read reproduction results, instantiate progeny and speciate over population
+	// read reproduction results in order of species, instantiate progeny, assign innovation numbers and node IDs
+	// in order of species and speciate over population
 	babies := make([]*Organism, 0)
-	for result := range res_chan {
+	for idx, result := range results {
 		if result.err != nil {
 			return result.err
 		}
 		// read baby genome
 		dec := gob.NewDecoder(bytes.NewBuffer(result.babies))
+		sp_babies := make([]*Organism, 0, result.babies_stored)
 		for i := 0; i < result.babies_stored; i++ {
 			org := Organism{}
 			err := dec.Decode(&org)
@@ -270,25 +355,33 @@ func (ex *ParallelPopulationEpochExecutor) reproduce(generation int, p *Populati
 				return errors.New(
 					fmt.Sprintf("POPULATION: Failed to decode baby organism, reason: %s", err))
 			}
-			babies = append(babies, &org)
+			sp_babies = append(sp_babies, &org)
 		}
+		p.mergeInnovationShadow(shadows[idx], sp_babies)
+		babies = append(babies, sp_babies...)
 		if result.species_id == ex.sequential.best_species_id {
 			// store flag if best species reproduced - it will be used to determine if best species
 			// produced offspring before died
-			ex.sequential.best_species_reproduced = (babies != nil)
+			ex.sequential.best_species_reproduced = result.babies_stored > 0
 		}
 	}
 
 	// sanity check - make sure that population size keep the same
 	if len(babies) != context.PopSize {
-		return errors.New(
-			fmt.Sprintf("POPULATION: Progeny size after reproduction cycle dimished.\nExpected: [%d], but got: [%d]",
-				context.PopSize, len(babies)))
+		return newError(ErrProgenySizeMismatch,
+			"POPULATION: Progeny size after reproduction cycle dimished.\nExpected: [%d], but got: [%d]",
+				context.PopSize, len(babies))
 	}
 
 
 	// speciate fresh progeny
 	err := p.speciate(babies, context)
+	if err == nil {
+		err = p.adjustCompatThreshold(context)
+	}
+	if err == nil {
+		p.notifySpeciation(generation)
+	}
 
 	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"github.com/yaricom/goNEAT/neat"
 	"math/rand"
+	"bytes"
 )
 
 func runSequentialPopulationEpochExecutor_NextEpoch(pop *Population, conf *neat.NeatContext) error {
@@ -65,3 +66,171 @@ func TestPopulationEpochExecutor_NextEpoch(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestPopulationStagnationLimit(t *testing.T) {
+	conf := neat.NeatContext{DropOffAge:15}
+	if limit := populationStagnationLimit(&conf); limit != 20 {
+		t.Error("Default limit expected", 20, limit)
+	}
+	conf.PopulationStagnationLimit = 7
+	if limit := populationStagnationLimit(&conf); limit != 7 {
+		t.Error("Configured limit expected", 7, limit)
+	}
+	conf.PopulationStagnationLimit = -1
+	if limit := populationStagnationLimit(&conf); limit > 0 {
+		t.Error("Delta coding should be disabled", limit)
+	}
+}
+
+func TestParallelPopulationEpochExecutor_reproduce(t *testing.T) {
+	rand.Seed(42)
+	in, out, nmax, n := 3, 2, 15, 3
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 30,
+		RecurOnlyProb:0.2,
+	}
+	gen := newGenomeRand(1, in, out, n, nmax, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := ParallelPopulationEpochExecutor{}
+	ex.sequential = &SequentialPopulationEpochExecutor{}
+	if err = ex.sequential.prepare(1, pop, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if err = ex.reproduce(1, pop, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if !ex.sequential.best_species_reproduced {
+		t.Error("The best species should reproduce")
+	}
+}
+
+// Tests that parallel reproduction with the seed set produces the same offspring in each run
+func TestParallelPopulationEpochExecutor_reproduce_deterministic(t *testing.T) {
+	in, out, nmax, n := 3, 2, 15, 3
+	gen := newGenomeRand(1, in, out, n, nmax, false, 0.8)
+	conf := neat.NewNeatContext()
+	conf.CompatThreshold, conf.DropOffAge, conf.PopSize, conf.RecurOnlyProb = 1.0, 15, 30, 0.2
+	conf.MutateAddNodeProb, conf.MutateAddLinkProb, conf.MutateLinkWeightsProb = 0.5, 0.5, 0.9
+	conf.MateMultipointProb, conf.InterspeciesMateRate, conf.WeightMutPower = 0.5, 0.1, 2.5
+	conf.DisjointCoeff, conf.ExcessCoeff, conf.MutdiffCoeff = 1.0, 1.0, 0.4
+	conf.RandomSeed = 42
+	run := func() ([]string, error) {
+		// the initial population is perturbed by generator of context
+		pop, err := NewPopulation(gen, conf.WithRand(rand.New(rand.NewSource(42))))
+		if err != nil {
+			return nil, err
+		}
+		ex := ParallelPopulationEpochExecutor{}
+		for generation := 1; generation <= 5; generation++ {
+			for _, org := range pop.Organisms {
+				org.Fitness = float64(len(org.Genotype.Genes))
+			}
+			if err = ex.NextEpoch(generation, pop, conf); err != nil {
+				return nil, err
+			}
+		}
+		if len(pop.Species) < 2 {
+			t.Error("Multiple species expected", len(pop.Species))
+		}
+		genomes := make([]string, 0)
+		for _, org := range pop.Organisms {
+			var buf bytes.Buffer
+			org.Genotype.Write(&buf)
+			genomes = append(genomes, buf.String())
+		}
+		return genomes, nil
+	}
+
+	first, err := run()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(first) != conf.PopSize {
+		t.Error("Wrong number of offspring", len(first))
+	}
+	for i := 0; i < 5; i++ {
+		next, err := run()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(next) != len(first) {
+			t.Error("Wrong number of offspring", len(next))
+			return
+		}
+		for j := range first {
+			if next[j] != first[j] {
+				t.Errorf("The offspring [%d] differs between runs:\n%s\n%s", j, first[j], next[j])
+				return
+			}
+		}
+	}
+}
+
+// Tests that species emptied in the middle of epoch cycle are removed before reproduction by all executors
+func TestPopulationEpochExecutor_NextEpoch_emptySpecies(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 30,
+		RecurOnlyProb:0.2,
+		ReplacementFraction:0.2,
+	}
+	executors := map[string]PopulationEpochExecutor{
+		"sequential":&SequentialPopulationEpochExecutor{},
+		"parallel":&ParallelPopulationEpochExecutor{},
+		"steady state":&SteadyStatePopulationEpochExecutor{},
+	}
+	for name, ex := range executors {
+		gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+		pop, err := NewPopulation(gen, &conf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		empty := NewSpecies(pop.LastSpecies + 1)
+		pop.LastSpecies++
+		pop.Species = append([]*Species{empty}, pop.Species...)
+
+		for i := 0; i < 5; i++ {
+			if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+				t.Error(name, err)
+				break
+			}
+			for _, sp := range pop.Species {
+				if len(sp.Organisms) == 0 {
+					t.Error(name, "The empty species should be removed", sp.Id)
+				}
+			}
+		}
+	}
+}
+
+func TestPopulation_removeEmptySpecies(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pop := &Population{Species:[]*Species{NewSpecies(2), sp, NewSpecies(3)}}
+	if removed := pop.removeEmptySpecies(1); removed != 2 {
+		t.Error("Two empty species should be removed", removed)
+	}
+	if len(pop.Species) != 1 || pop.Species[0] != sp {
+		t.Error("Only species with organisms should be kept", len(pop.Species))
+	}
+	if removed := pop.removeEmptySpecies(2); removed != 0 {
+		t.Error("No species should be removed", removed)
+	}
+}
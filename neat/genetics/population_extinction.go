@@ -0,0 +1,167 @@
+package genetics
+
+import (
+	"fmt"
+	"sort"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The extinction type definition, i.e. the method to kill part of population during extinction event
+type ExtinctionType int
+
+const (
+	// The extinction events are disabled
+	NoExtinction ExtinctionType = iota
+	// The worst species ranked by the fitness of their champions are killed entirely, the fraction of killed species is
+	// defined by context.ExtinctionRate. The best species always survives.
+	SpeciesExtinction
+	// The random catastrophe kills each organism with probability defined by context.ExtinctionRate, the champions of
+	// species are spared, thus all species survive.
+	CatastropheExtinction
+)
+
+// The record of extinction event happened in population
+type ExtinctionEvent struct {
+	// The generation when event happened
+	Generation      int
+	// The type of extinction
+	Type            ExtinctionType
+	// True if event was triggered by population stagnation rather than by period
+	Triggered       bool
+	// The IDs of killed species
+	SpeciesKilled   []int
+	// The total number of killed organisms
+	OrganismsKilled int
+}
+
+// The optional extension of EpochObserver interested to be notified about extinction events. If registered observer
+// implements it, it will be notified after each extinction event. The killed species are also reported by
+// EpochObserver.OnSpeciesExtinct.
+type ExtinctionObserver interface {
+	// Invoked after extinction event happened in population
+	OnExtinctionEvent(generation int, event *ExtinctionEvent)
+}
+
+// Returns the history of extinction events happened in this population. The returned slice is a copy and can be
+// modified by caller.
+func (p *Population) ExtinctionEvents() []ExtinctionEvent {
+	events := make([]ExtinctionEvent, len(p.extinctionEvents))
+	copy(events, p.extinctionEvents)
+	return events
+}
+
+// Applies extinction event to this population if it is due in given generation, i.e. if generation is multiple of
+// context.ExtinctionPeriod or population has no fitness record for context.ExtinctionStagnation generations since the
+// start or the last extinction event. It should be invoked at the start of epoch after evaluation of organisms and
+// before fitness adjustment, the killed organisms are archived and removed from population, thus offspring to fill
+// population is allocated among survivors. Returns the event happened or nil.
+func (p *Population) applyExtinction(generation int, context *neat.NeatContext) (*ExtinctionEvent, error) {
+	extinction := ExtinctionType(context.ExtinctionType)
+	if extinction == NoExtinction {
+		return nil, nil
+	}
+	if context.ExtinctionRate < 0 || context.ExtinctionRate > 1 {
+		return nil, newError(ErrInvalidParameter, "POPULATION: The extinction rate should be in [0, 1], found: %f",
+			context.ExtinctionRate)
+	}
+	periodic := context.ExtinctionPeriod > 0 && generation > 0 && generation % context.ExtinctionPeriod == 0
+	triggered := context.ExtinctionStagnation > 0 && p.EpochsHighestLastChanged >= context.ExtinctionStagnation &&
+		generation - p.lastExtinction >= context.ExtinctionStagnation
+	if !periodic && !triggered {
+		return nil, nil
+	}
+
+	event := &ExtinctionEvent{Generation:generation, Type:extinction, Triggered:triggered && !periodic}
+	var err error
+	switch extinction {
+	case SpeciesExtinction:
+		err = p.killWorstSpecies(event, context)
+	case CatastropheExtinction:
+		err = p.killByCatastrophe(event, context)
+	default:
+		return nil, newError(ErrUnsupportedType, "POPULATION: Unsupported extinction type: %d", context.ExtinctionType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// rebuild the master organisms list from survived species
+	organisms := make([]*Organism, 0, len(p.Organisms) - event.OrganismsKilled)
+	for _, sp := range p.Species {
+		organisms = append(organisms, sp.Organisms...)
+	}
+	p.Organisms = organisms
+	p.lastExtinction = generation
+	p.extinctionEvents = append(p.extinctionEvents, *event)
+
+	neat.InfoLog(fmt.Sprintf("POPULATION: Extinction event in generation [%d] killed %d organisms and %d species\n",
+		generation, event.OrganismsKilled, len(event.SpeciesKilled)))
+	p.notifyExtinctionEvent(generation, event)
+	return event, nil
+}
+
+// Kills the worst fraction of species ranked by fitness of their champions, the best species is spared
+func (p *Population) killWorstSpecies(event *ExtinctionEvent, context *neat.NeatContext) error {
+	direction := objectiveDirection(context)
+	ranked := make([]*Species, 0, len(p.Species))
+	champions := make(map[*Species]*Organism, len(p.Species))
+	for _, sp := range p.Species {
+		if champion := direction.BestOrganism(sp.Organisms); champion != nil {
+			ranked = append(ranked, sp)
+			champions[sp] = champion
+		}
+	}
+	// the best species go first
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return direction.IsBetter(champions[ranked[i]].Fitness, champions[ranked[j]].Fitness)
+	})
+	kill := int(float64(len(ranked)) * context.ExtinctionRate)
+	if kill > len(ranked) - 1 {
+		kill = len(ranked) - 1
+	}
+
+	killed := make(map[*Species]bool, kill)
+	for _, sp := range ranked[len(ranked) - kill:] {
+		for len(sp.Organisms) > 0 {
+			if _, err := sp.removeOrganism(sp.Organisms[0]); err != nil {
+				return err
+			}
+			event.OrganismsKilled++
+		}
+		killed[sp] = true
+		event.SpeciesKilled = append(event.SpeciesKilled, sp.Id)
+	}
+	if len(killed) > 0 {
+		species := make([]*Species, 0, len(p.Species) - len(killed))
+		for _, sp := range p.Species {
+			if killed[sp] {
+				p.notifySpeciesExtinct(event.Generation, sp)
+			} else {
+				species = append(species, sp)
+			}
+		}
+		p.Species = species
+	}
+	return nil
+}
+
+// Kills each organism of population with probability context.ExtinctionRate except the champions of species
+func (p *Population) killByCatastrophe(event *ExtinctionEvent, context *neat.NeatContext) error {
+	direction := objectiveDirection(context)
+	for _, sp := range p.Species {
+		champion := direction.BestOrganism(sp.Organisms)
+		victims := make([]*Organism, 0)
+		for _, org := range sp.Organisms {
+			if org != champion && context.Rand().Float64() < context.ExtinctionRate {
+				victims = append(victims, org)
+			}
+		}
+		for _, org := range victims {
+			if _, err := sp.removeOrganism(org); err != nil {
+				return err
+			}
+		}
+		event.OrganismsKilled += len(victims)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The observer of extinction events
+type extinctionObserver struct {
+	BaseEpochObserver
+	events  []*ExtinctionEvent
+	extinct []int
+}
+
+func (o *extinctionObserver) OnExtinctionEvent(generation int, event *ExtinctionEvent) {
+	o.events = append(o.events, event)
+}
+
+func (o *extinctionObserver) OnSpeciesExtinct(generation int, species *Species) {
+	o.extinct = append(o.extinct, species.Id)
+}
+
+// Builds population with species of organisms built by buildClusteredOrganisms and fitness equal to the group index
+func buildExtinctionPopulation(per_group, groups int) *Population {
+	centers := make([]float64, groups)
+	for i := range centers {
+		centers[i] = float64(i) * 10.0
+	}
+	pop := newPopulation()
+	pop.Organisms = buildClusteredOrganisms(per_group, centers...)
+	for _, org := range pop.Organisms {
+		org.Fitness = float64(clusterGroup(org, per_group)) + float64(org.Genotype.Id) * 0.01
+	}
+	pop.speciate(pop.Organisms, &neat.NeatContext{CompatThreshold:3.0, MutdiffCoeff:1.0})
+	return pop
+}
+
+func TestPopulation_applyExtinction_species(t *testing.T) {
+	pop := buildExtinctionPopulation(3, 4)
+	if len(pop.Species) != 4 {
+		t.Error("Wrong number of species", len(pop.Species))
+		return
+	}
+	observer := &extinctionObserver{}
+	pop.AddObserver(observer)
+	conf := neat.NeatContext{ExtinctionType:int(SpeciesExtinction), ExtinctionRate:0.5, ExtinctionPeriod:5}
+
+	// not due yet
+	if event, err := pop.applyExtinction(4, &conf); event != nil || err != nil {
+		t.Error("No extinction event expected", event, err)
+	}
+
+	// the two worst species killed
+	event, err := pop.applyExtinction(5, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if event == nil || event.Triggered || event.OrganismsKilled != 6 || len(event.SpeciesKilled) != 2 {
+		t.Error("Wrong extinction event", event)
+		return
+	}
+	if event.SpeciesKilled[0] != 2 || event.SpeciesKilled[1] != 1 {
+		t.Error("The worst species should be killed", event.SpeciesKilled)
+	}
+	if len(pop.Species) != 2 || len(pop.Organisms) != 6 {
+		t.Error("Wrong population after extinction", len(pop.Species), len(pop.Organisms))
+	}
+	for _, org := range pop.Organisms {
+		if clusterGroup(org, 3) < 2 || org.State() == OrganismArchived {
+			t.Error("Wrong survived organism", org.Genotype.Id)
+		}
+	}
+	if len(observer.events) != 1 || len(observer.extinct) != 2 {
+		t.Error("Wrong notifications", len(observer.events), observer.extinct)
+	}
+	if events := pop.ExtinctionEvents(); len(events) != 1 || events[0].Generation != 5 {
+		t.Error("Wrong extinction events history", events)
+	}
+
+	// the best species always survives
+	conf.ExtinctionRate = 1.0
+	if event, err = pop.applyExtinction(10, &conf); err != nil || len(event.SpeciesKilled) != 1 {
+		t.Error("Only one species should be killed", event, err)
+	}
+	if len(pop.Species) != 1 || pop.Species[0].Id != 4 {
+		t.Error("The best species should survive")
+	}
+}
+
+func TestPopulation_applyExtinction_catastrophe(t *testing.T) {
+	rand.Seed(42)
+	pop := buildExtinctionPopulation(5, 3)
+	conf := neat.NeatContext{ExtinctionType:int(CatastropheExtinction), ExtinctionRate:1.0, ExtinctionPeriod:1}
+	event, err := pop.applyExtinction(1, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if event.OrganismsKilled != 12 || len(event.SpeciesKilled) != 0 {
+		t.Error("Wrong extinction event", event)
+	}
+	if len(pop.Species) != 3 || len(pop.Organisms) != 3 {
+		t.Error("The champions of all species should survive", len(pop.Species), len(pop.Organisms))
+		return
+	}
+	for i, sp := range pop.Species {
+		if len(sp.Organisms) != 1 || sp.Organisms[0].Genotype.Id != (i + 1) * 5 {
+			t.Error("Wrong survived champion of species", sp.Id)
+		}
+	}
+}
+
+func TestPopulation_applyExtinction_triggered(t *testing.T) {
+	pop := buildExtinctionPopulation(2, 3)
+	conf := neat.NeatContext{ExtinctionType:int(SpeciesExtinction), ExtinctionRate:0.4, ExtinctionStagnation:3}
+	pop.EpochsHighestLastChanged = 2
+	if event, _ := pop.applyExtinction(7, &conf); event != nil {
+		t.Error("No extinction event expected before stagnation")
+	}
+	pop.EpochsHighestLastChanged = 3
+	event, err := pop.applyExtinction(7, &conf)
+	if err != nil || event == nil || !event.Triggered || len(event.SpeciesKilled) != 1 {
+		t.Error("Triggered extinction event expected", event, err)
+	}
+	// the next triggered event is not allowed before stagnation period passed
+	pop.EpochsHighestLastChanged = 5
+	if event, _ = pop.applyExtinction(9, &conf); event != nil {
+		t.Error("No extinction event expected right after previous one")
+	}
+}
+
+func TestPopulation_applyExtinction_errors(t *testing.T) {
+	pop := buildExtinctionPopulation(2, 2)
+	if event, err := pop.applyExtinction(1, &neat.NeatContext{ExtinctionPeriod:1}); event != nil || err != nil {
+		t.Error("The extinction events should be disabled", event, err)
+	}
+	conf := neat.NeatContext{ExtinctionType:int(SpeciesExtinction), ExtinctionRate:1.5, ExtinctionPeriod:1}
+	if _, err := pop.applyExtinction(1, &conf); err == nil {
+		t.Error("Error expected for wrong extinction rate")
+	}
+	conf = neat.NeatContext{ExtinctionType:100, ExtinctionPeriod:1}
+	if _, err := pop.applyExtinction(1, &conf); err == nil {
+		t.Error("Error expected for unsupported extinction type")
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_extinction(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DisjointCoeff:1.0,
+		ExcessCoeff:1.0,
+		MutdiffCoeff:0.4,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.2,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+		ExtinctionType:int(CatastropheExtinction),
+		ExtinctionRate:0.5,
+		ExtinctionPeriod:2,
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ex := SequentialPopulationEpochExecutor{}
+	for i := 0; i < 4; i++ {
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64()
+		}
+		if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(pop.Organisms) != conf.PopSize {
+			t.Error("The population size should be restored after extinction", len(pop.Organisms))
+		}
+	}
+	if events := pop.ExtinctionEvents(); len(events) != 2 || events[0].OrganismsKilled == 0 {
+		t.Error("Wrong extinction events", events)
+	}
+}
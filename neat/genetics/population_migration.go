@@ -0,0 +1,138 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"encoding/gob"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// The criteria to select organisms emigrating from population
+type EmigrantSelectionType int
+
+const (
+	// The most fit organisms of population emigrate
+	BestEmigrants EmigrantSelectionType = iota
+	// The emigrants selected among all organisms of population with uniform probability
+	RandomEmigrants
+	// The champions of species emigrate, the champions of the most fit species go first
+	SpeciesChampionEmigrants
+)
+
+// Returns copies of up to n organisms of this population selected according to given criteria. The most fit organisms
+// are defined by the objective direction of context. The emigrants do not belong to any species and can be transmitted
+// to other population, e.g. running in separate process, with WriteOrganisms. The organisms of this population are not
+// changed.
+func (p *Population) ExportEmigrants(n int, criteria EmigrantSelectionType, context *neat.NeatContext) ([]*Organism, error) {
+	direction := objectiveDirection(context)
+	var selected []*Organism
+	switch criteria {
+	case BestEmigrants:
+		selected = p.bestOrganisms(n, direction)
+	case RandomEmigrants:
+		if n > len(p.Organisms) {
+			n = len(p.Organisms)
+		}
+		selected = make([]*Organism, n)
+		for i, idx := range context.Rand().Perm(len(p.Organisms))[:n] {
+			selected[i] = p.Organisms[idx]
+		}
+	case SpeciesChampionEmigrants:
+		selected = make([]*Organism, 0, len(p.Species))
+		for _, sp := range p.Species {
+			if len(sp.Organisms) > 0 {
+				selected = append(selected, direction.BestOrganism(sp.Organisms))
+			}
+		}
+		sortByDirectedFitnessDesc(selected, direction)
+		if n < len(selected) {
+			selected = selected[:n]
+		}
+	default:
+		return nil, newError(ErrUnsupportedType, "POPULATION: Unsupported emigrants selection type: %d", criteria)
+	}
+
+	emigrants := make([]*Organism, len(selected))
+	for i, org := range selected {
+		emigrant, err := org.Clone()
+		if err != nil {
+			return nil, err
+		}
+		emigrants[i] = emigrant
+	}
+	return emigrants, nil
+}
+
+// Imports copies of immigrant organisms which emigrated from other population, e.g. running in separate process, into
+// this population. The immigrants replace the least fit organisms of population and get speciated within it. The
+// innovation numbers of immigrant genomes are mapped onto the innovation database of this population (see
+// ReconcileGenome), thus the immigrants can be mated with local organisms and future structural innovations will not
+// clash with immigrants' genes. The immigrants should have the same number of inputs and outputs
+// as organisms of this population.
+func (p *Population) ImportImmigrants(immigrants []*Organism, generation int, context *neat.NeatContext) error {
+	if len(immigrants) == 0 {
+		return nil
+	}
+	if len(p.Organisms) == 0 {
+		return newError(ErrEmptyPopulation, "POPULATION: Can not import immigrants into empty population")
+	}
+	in, out := genomeSensorsOutputs(p.Organisms[0].Genotype)
+	for _, org := range immigrants {
+		if org.Genotype == nil {
+			return newError(ErrIncompatibleImmigrant, "POPULATION: Immigrant organism without genome")
+		}
+		if org_in, org_out := genomeSensorsOutputs(org.Genotype); org_in != in || org_out != out {
+			return newError(ErrIncompatibleImmigrant,
+				"POPULATION: Immigrant genome [%d] has %d inputs and %d outputs, expected: %d inputs and %d outputs",
+				org.Genotype.Id, org_in, org_out, in, out)
+		}
+		p.ReconcileGenome(org.Genotype)
+	}
+	return p.replaceLeastFit(immigrants, generation, context)
+}
+
+// Returns the number of sensors (including bias) and outputs of given genome
+func genomeSensorsOutputs(g *Genome) (in, out int) {
+	for _, node := range g.Nodes {
+		if node.IsSensor() {
+			in++
+		} else if node.NeuronType == network.OutputNeuron {
+			out++
+		}
+	}
+	return in, out
+}
+
+// Writes given organisms, e.g. emigrants, into provided writer to be transmitted to other process
+func WriteOrganisms(w io.Writer, organisms []*Organism) error {
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(len(organisms)); err != nil {
+		return err
+	}
+	for _, org := range organisms {
+		if err := enc.Encode(org); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads organisms written by WriteOrganisms from provided reader
+func ReadOrganisms(r io.Reader) ([]*Organism, error) {
+	dec := gob.NewDecoder(r)
+	var count int
+	if err := dec.Decode(&count); err != nil {
+		return nil, err
+	}
+	organisms := make([]*Organism, count)
+	for i := range organisms {
+		org := Organism{}
+		if err := dec.Decode(&org); err != nil {
+			return nil, errors.New(fmt.Sprintf("POPULATION: Failed to decode organism, reason: %s", err))
+		}
+		organisms[i] = &org
+	}
+	return organisms, nil
+}
@@ -0,0 +1,193 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func buildTestMigrationPopulation(t *testing.T) (*Population, *neat.NeatContext) {
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 20,
+		BabiesStolen:5,
+		RecurOnlyProb:0.2,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	pop, err := NewPopulation(newGenomeRand(1, 3, 2, 3, 15, false, 0.8), &conf)
+	if err != nil {
+		t.Error(err)
+		return nil, nil
+	}
+	for i, org := range pop.Organisms {
+		org.Fitness = float64(i + 1)
+	}
+	return pop, &conf
+}
+
+func TestPopulation_ExportEmigrants(t *testing.T) {
+	rand.Seed(42)
+	pop, conf := buildTestMigrationPopulation(t)
+	if pop == nil {
+		return
+	}
+
+	emigrants, err := pop.ExportEmigrants(3, BestEmigrants, conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(emigrants) != 3 {
+		t.Error("len(emigrants) != 3", len(emigrants))
+		return
+	}
+	for i, emigrant := range emigrants {
+		if emigrant.Fitness != float64(20 - i) {
+			t.Error("Wrong emigrant fitness", i, emigrant.Fitness)
+		}
+		if emigrant.Species != nil {
+			t.Error("Emigrant should not belong to species")
+		}
+		for _, org := range pop.Organisms {
+			if org == emigrant || org.Genotype == emigrant.Genotype {
+				t.Error("Emigrant should be a copy")
+			}
+		}
+	}
+
+	if emigrants, err = pop.ExportEmigrants(100, RandomEmigrants, conf); err != nil {
+		t.Error(err)
+	} else if len(emigrants) != len(pop.Organisms) {
+		t.Error("All organisms should emigrate", len(emigrants))
+	}
+
+	if emigrants, err = pop.ExportEmigrants(100, SpeciesChampionEmigrants, conf); err != nil {
+		t.Error(err)
+	} else if len(emigrants) != len(pop.Species) || emigrants[0].Fitness != 20.0 {
+		t.Error("Wrong species champions emigrated", len(emigrants), len(pop.Species))
+	}
+
+	if _, err = pop.ExportEmigrants(1, 100, conf); err == nil {
+		t.Error("Error expected for unsupported emigrants selection")
+	}
+}
+
+func TestPopulation_ExportEmigrants_minimize(t *testing.T) {
+	rand.Seed(42)
+	pop, conf := buildTestMigrationPopulation(t)
+	if pop == nil {
+		return
+	}
+	conf.ObjectiveDirection = int(MinimizeObjective)
+
+	emigrants, err := pop.ExportEmigrants(3, BestEmigrants, conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the organisms with the lowest fitness emigrate
+	for i, emigrant := range emigrants {
+		if emigrant.Fitness != float64(i + 1) {
+			t.Error("Wrong emigrant fitness", i, emigrant.Fitness)
+		}
+	}
+
+	if emigrants, err = pop.ExportEmigrants(100, SpeciesChampionEmigrants, conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(emigrants) != len(pop.Species) || emigrants[0].Fitness != 1.0 {
+		t.Error("Wrong species champions emigrated", len(emigrants), emigrants[0].Fitness)
+	}
+	for _, sp := range pop.Species {
+		champion := MinimizeObjective.BestOrganism(sp.Organisms)
+		found := false
+		for _, emigrant := range emigrants {
+			found = found || emigrant.Fitness == champion.Fitness
+		}
+		if !found {
+			t.Error("Species champion not emigrated", sp.Id, champion.Fitness)
+		}
+	}
+}
+
+func TestPopulation_ImportImmigrants(t *testing.T) {
+	rand.Seed(42)
+	src, _ := buildTestMigrationPopulation(t)
+	dst, conf := buildTestMigrationPopulation(t)
+	if src == nil || dst == nil {
+		return
+	}
+	for _, org := range dst.Organisms {
+		org.Fitness = 0.5
+	}
+	emigrants, err := src.ExportEmigrants(3, BestEmigrants, conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// transmit emigrants over the wire
+	var buf bytes.Buffer
+	if err = WriteOrganisms(&buf, emigrants); err != nil {
+		t.Error(err)
+		return
+	}
+	immigrants, err := ReadOrganisms(&buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(immigrants) != 3 || immigrants[0].Fitness != 20.0 {
+		t.Error("Wrong organisms read", len(immigrants))
+		return
+	}
+
+	// the innovations of immigrants are beyond ones of destination population
+	immigrants[0].Genotype.Genes[0].InnovationNum = dst.nextInnovNum + 100
+	immigrants[0].Genotype.Nodes[len(immigrants[0].Genotype.Nodes) - 1].Id = int(dst.nextNodeId) + 100
+	last_innov, expected_node := dst.nextInnovNum, dst.nextNodeId + 100
+
+	if err = dst.ImportImmigrants(immigrants, 1, conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(dst.Organisms) != conf.PopSize {
+		t.Error("Population size should not change", len(dst.Organisms))
+	}
+	arrived := 0
+	for _, org := range dst.Organisms {
+		if org.Fitness == 0.5 {
+			continue
+		}
+		arrived++
+		if org.Species == nil {
+			t.Error("Immigrant should be speciated")
+		}
+	}
+	if arrived != 3 {
+		t.Error("arrived != 3", arrived)
+	}
+	if dst.nextNodeId != expected_node {
+		t.Error("Node ID counter should be reconciled", dst.nextNodeId)
+	}
+	for _, gene := range immigrants[0].Genotype.Genes {
+		if gene.InnovationNum > dst.nextInnovNum || gene.InnovationNum == last_innov + 100 {
+			t.Error("Innovation number should be reconciled", gene.InnovationNum, dst.nextInnovNum)
+		}
+	}
+	if _, err = dst.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	// the immigrants with wrong number of inputs
+	wrong, err := NewGenomeFullyConnected(1, 2, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = dst.ImportImmigrants([]*Organism{{Genotype:wrong}}, 1, conf); err == nil {
+		t.Error("Error expected for incompatible immigrant")
+	}
+}
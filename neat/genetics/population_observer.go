@@ -0,0 +1,95 @@
+package genetics
+
+// The observer interested to receive notifications about lifecycle events of population's epoch. All notifications are
+// delivered synchronously from the thread executing the epoch, i.e. even with parallel epoch executor.
+type EpochObserver interface {
+	// Invoked at the beginning of new epoch before any fitness adjustments done
+	OnGenerationStart(generation int, pop *Population)
+	// Invoked after fresh progeny was speciated within population
+	OnSpeciation(generation int, pop *Population)
+	// Invoked after reproduction cycle complete and population is ready for the next generation evaluation
+	OnReproductionDone(generation int, pop *Population)
+	// Invoked when species was removed from population either due to stagnation or because it has no survived organisms
+	OnSpeciesExtinct(generation int, species *Species)
+	// Invoked when new population record fitness achieved by the champion organism
+	OnNewChampion(generation int, champion *Organism)
+}
+
+// The optional extension of EpochObserver interested to track progress of reproduction cycle. If registered observer
+// implements it, it will be notified after each species of population produced its offspring.
+type SpeciesReproductionObserver interface {
+	// Invoked after species produced given number of offspring, which may be zero
+	OnSpeciesReproduced(generation int, species *Species, offspring int)
+}
+
+// The no-op implementation of EpochObserver which can be embedded to implement only events of interest
+type BaseEpochObserver struct {}
+
+func (BaseEpochObserver) OnGenerationStart(generation int, pop *Population) {}
+func (BaseEpochObserver) OnSpeciation(generation int, pop *Population) {}
+func (BaseEpochObserver) OnReproductionDone(generation int, pop *Population) {}
+func (BaseEpochObserver) OnSpeciesExtinct(generation int, species *Species) {}
+func (BaseEpochObserver) OnNewChampion(generation int, champion *Organism) {}
+
+// Registers observer to receive epoch lifecycle notifications of this population
+func (p *Population) AddObserver(observer EpochObserver) {
+	p.observers = append(p.observers, observer)
+}
+
+// Unregisters previously registered observer. Returns false if observer was not registered. Observers are compared
+// by equality, thus it is better to register them as pointers.
+func (p *Population) RemoveObserver(observer EpochObserver) bool {
+	for i, o := range p.observers {
+		if o == observer {
+			p.observers = append(p.observers[:i], p.observers[i + 1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Population) notifyGenerationStart(generation int) {
+	for _, o := range p.observers {
+		o.OnGenerationStart(generation, p)
+	}
+}
+
+func (p *Population) notifySpeciation(generation int) {
+	for _, o := range p.observers {
+		o.OnSpeciation(generation, p)
+	}
+}
+
+func (p *Population) notifyReproductionDone(generation int) {
+	for _, o := range p.observers {
+		o.OnReproductionDone(generation, p)
+	}
+}
+
+func (p *Population) notifySpeciesExtinct(generation int, species *Species) {
+	for _, o := range p.observers {
+		o.OnSpeciesExtinct(generation, species)
+	}
+}
+
+func (p *Population) notifyNewChampion(generation int, champion *Organism) {
+	for _, o := range p.observers {
+		o.OnNewChampion(generation, champion)
+	}
+}
+
+func (p *Population) notifySpeciesReproduced(generation int, species *Species, offspring int) {
+	for _, o := range p.observers {
+		if ro, ok := o.(SpeciesReproductionObserver); ok {
+			ro.OnSpeciesReproduced(generation, species, offspring)
+		}
+	}
+}
+
+func (p *Population) notifyExtinctionEvent(generation int, event *ExtinctionEvent) {
+	for _, o := range p.observers {
+		if eo, ok := o.(ExtinctionObserver); ok {
+			eo.OnExtinctionEvent(generation, event)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The observer recording received events
+type testEpochObserver struct {
+	BaseEpochObserver
+	events    []string
+	extinct   int
+	champions []*Organism
+}
+
+func (o *testEpochObserver) OnGenerationStart(generation int, pop *Population) {
+	o.events = append(o.events, "start")
+}
+func (o *testEpochObserver) OnSpeciation(generation int, pop *Population) {
+	o.events = append(o.events, "speciation")
+}
+func (o *testEpochObserver) OnReproductionDone(generation int, pop *Population) {
+	o.events = append(o.events, "done")
+}
+func (o *testEpochObserver) OnSpeciesExtinct(generation int, species *Species) {
+	o.extinct++
+}
+func (o *testEpochObserver) OnNewChampion(generation int, champion *Organism) {
+	o.champions = append(o.champions, champion)
+}
+
+func TestPopulation_Observers(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 30,
+		BabiesStolen:10,
+		RecurOnlyProb:0.2,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	observer := &testEpochObserver{}
+	pop.AddObserver(observer)
+	// the other observer with no-op implementation
+	pop.AddObserver(&BaseEpochObserver{})
+
+	executors := []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{}, &ParallelPopulationEpochExecutor{}}
+	generations := 10
+	for _, ex := range executors {
+		observer.events = nil
+		for i := 0; i < generations; i++ {
+			for _, org := range pop.Organisms {
+				org.Fitness = rand.Float64() * float64(i + 1)
+			}
+			if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if len(observer.events) != generations * 3 {
+			t.Error("Wrong number of events", len(observer.events))
+			continue
+		}
+		for i := 0; i < generations; i++ {
+			if observer.events[i * 3] != "start" || observer.events[i * 3 + 1] != "speciation" ||
+				observer.events[i * 3 + 2] != "done" {
+				t.Error("Wrong order of events", observer.events[i * 3:i * 3 + 3])
+			}
+		}
+	}
+	if len(observer.champions) == 0 {
+		t.Error("No new champion notifications")
+	}
+	for i := 1; i < len(observer.champions); i++ {
+		if observer.champions[i].originalFitness <= observer.champions[i - 1].originalFitness {
+			t.Error("Champion fitness should increase")
+		}
+	}
+	if !pop.RemoveObserver(observer) {
+		t.Error("Observer should be removed")
+	}
+	if pop.RemoveObserver(observer) {
+		t.Error("Observer already removed")
+	}
+	if len(pop.observers) != 1 {
+		t.Error("Wrong number of observers left", len(pop.observers))
+	}
+}
+
+func TestPopulation_Observers_speciesExtinct(t *testing.T) {
+	pop := newPopulation()
+	observer := &testEpochObserver{}
+	pop.AddObserver(observer)
+
+	alive, err := buildSpeciesWithOrganisms(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	empty := NewSpecies(2)
+	pop.Species = []*Species{alive, empty}
+
+	pop.purgeOrAgeSpecies(1)
+	if observer.extinct != 1 {
+		t.Error("Wrong number of species extinction notifications", observer.extinct)
+	}
+	if len(pop.Species) != 1 || pop.Species[0] != alive {
+		t.Error("Only alive species should survive", len(pop.Species))
+	}
+}
+
+// The observer recording offspring produced by species
+type testReproductionObserver struct {
+	BaseEpochObserver
+	species   []*Species
+	offspring int
+}
+
+func (o *testReproductionObserver) OnSpeciesReproduced(generation int, species *Species, offspring int) {
+	o.species = append(o.species, species)
+	o.offspring += offspring
+}
+
+func TestPopulation_Observers_speciesReproduced(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:1,
+		PopSize: 30,
+		BabiesStolen:10,
+		RecurOnlyProb:0.2,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	observer := &testReproductionObserver{}
+	pop.AddObserver(observer)
+
+	executors := []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{}, &ParallelPopulationEpochExecutor{}}
+	for i, ex := range executors {
+		observer.species, observer.offspring = nil, 0
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64()
+		}
+		species := len(pop.Species)
+		if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(observer.species) != species {
+			t.Error("Each species should be notified", len(observer.species), species)
+		}
+		if observer.offspring != conf.PopSize {
+			t.Error("Wrong number of offspring notified", observer.offspring)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package genetics
+
+import (
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// Increments the time alive of all organisms in population by given number of evaluation time steps. Should be
+// invoked by real-time evaluation loop after each evaluation time slice.
+func (p *Population) AgeOrganisms(steps int) {
+	for _, org := range p.Organisms {
+		org.TimeAlive += steps
+	}
+}
+
+// Returns the worst organism among ones which are alive at least context.TimeAliveMinimum evaluation time steps,
+// i.e. which was evaluated long enough to be eligible for removal in real-time mode. The organisms compared by their
+// fitness shared within species, i.e. divided by the size of species. Returns nil if there is no eligible organisms.
+func (p *Population) WorstEligibleOrganism(context *neat.NeatContext) *Organism {
+	var worst *Organism
+	worst_fitness := 0.0
+	for _, org := range p.Organisms {
+		if org.TimeAlive < context.TimeAliveMinimum {
+			continue
+		}
+		adjusted_fitness := org.Fitness
+		if org.Species != nil && len(org.Species.Organisms) > 0 {
+			adjusted_fitness /= float64(len(org.Species.Organisms))
+		}
+		if worst == nil || adjusted_fitness < worst_fitness {
+			worst = org
+			worst_fitness = adjusted_fitness
+		}
+	}
+	return worst
+}
+
+// Replaces the worst eligible for removal organism (see WorstEligibleOrganism) with provided baby organism. The baby
+// will be speciated within population and the species left empty after removal of the worst organism will be removed
+// from population. Returns the removed organism or error if there is no organisms eligible for removal.
+func (p *Population) ReplaceWorstOrganism(baby *Organism, context *neat.NeatContext) (*Organism, error) {
+	worst := p.WorstEligibleOrganism(context)
+	if worst == nil {
+		return nil, newError(ErrNoEligibleOrganism,
+			"POPULATION: No organisms alive at least %d time steps to be replaced", context.TimeAliveMinimum)
+	}
+
+	// remove worst from population
+	orgs := make([]*Organism, 0, len(p.Organisms))
+	for _, org := range p.Organisms {
+		if org != worst {
+			orgs = append(orgs, org)
+		}
+	}
+	p.Organisms = orgs
+
+	// remove worst from its species and the species itself if it becomes empty
+	if sp := worst.Species; sp != nil {
+		if _, err := sp.removeOrganism(worst); err != nil {
+			return nil, err
+		}
+		p.removeEmptySpecies(baby.Generation)
+	}
+
+	// add baby to the population
+	baby.TimeAlive = 0
+	p.Organisms = append(p.Organisms, baby)
+	err := p.speciate([]*Organism{baby}, context)
+	return worst, err
+}
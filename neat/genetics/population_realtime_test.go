@@ -0,0 +1,111 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+func buildRealTimePopulation(t *testing.T, conf *neat.NeatContext) *Population {
+	rand.Seed(42)
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, conf)
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	for i, org := range pop.Organisms {
+		org.Fitness = float64(i + 1)
+	}
+	return pop
+}
+
+func TestPopulation_AgeOrganisms(t *testing.T) {
+	conf := neat.NeatContext{PopSize:10, CompatThreshold:0.5}
+	pop := buildRealTimePopulation(t, &conf)
+	if pop == nil {
+		return
+	}
+	pop.AgeOrganisms(5)
+	pop.AgeOrganisms(2)
+	for _, org := range pop.Organisms {
+		if org.TimeAlive != 7 {
+			t.Error("org.TimeAlive", 7, org.TimeAlive)
+		}
+	}
+}
+
+func TestPopulation_WorstEligibleOrganism(t *testing.T) {
+	conf := neat.NeatContext{PopSize:10, CompatThreshold:0.5, TimeAliveMinimum:10}
+	pop := buildRealTimePopulation(t, &conf)
+	if pop == nil {
+		return
+	}
+	if worst := pop.WorstEligibleOrganism(&conf); worst != nil {
+		t.Error("No organisms should be eligible for removal", worst)
+	}
+
+	// make eligible all except the first, least fit one
+	for _, org := range pop.Organisms[1:] {
+		org.TimeAlive = 10
+	}
+	worst := pop.WorstEligibleOrganism(&conf)
+	if worst == nil {
+		t.Error("worst == nil")
+		return
+	}
+	if worst == pop.Organisms[0] {
+		t.Error("Too young organism selected")
+	}
+	worst_fitness := worst.Fitness / float64(len(worst.Species.Organisms))
+	for _, org := range pop.Organisms[1:] {
+		if org.Fitness / float64(len(org.Species.Organisms)) < worst_fitness {
+			t.Error("Organism with lower adjusted fitness found", org)
+		}
+	}
+}
+
+func TestPopulation_ReplaceWorstOrganism(t *testing.T) {
+	conf := neat.NeatContext{PopSize:10, CompatThreshold:0.5, TimeAliveMinimum:10}
+	pop := buildRealTimePopulation(t, &conf)
+	if pop == nil {
+		return
+	}
+	baby, err := NewOrganism(0.0, buildTestGenome(100), 1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = pop.ReplaceWorstOrganism(baby, &conf); err == nil {
+		t.Error("Error expected when no eligible organisms")
+	}
+
+	pop.AgeOrganisms(10)
+	baby.TimeAlive = 100
+	removed, err := pop.ReplaceWorstOrganism(baby, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Organisms) != conf.PopSize {
+		t.Error("Population size should not change", len(pop.Organisms))
+	}
+	for _, org := range pop.Organisms {
+		if org == removed {
+			t.Error("Removed organism found in population")
+		}
+	}
+	for _, sp := range pop.Species {
+		for _, org := range sp.Organisms {
+			if org == removed {
+				t.Error("Removed organism found in species", sp.Id)
+			}
+		}
+	}
+	if baby.Species == nil {
+		t.Error("Baby should be speciated")
+	}
+	if baby.TimeAlive != 0 {
+		t.Error("Baby time alive should be reset", baby.TimeAlive)
+	}
+}
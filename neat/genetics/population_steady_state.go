@@ -0,0 +1,179 @@
+package genetics
+
+import (
+	"github.com/yaricom/goNEAT/neat"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The default fraction of population replaced per epoch by steady-state executor if not set in context
+const defaultReplacementFraction = 0.25
+
+// The population epoch executor which replaces only the worst fraction of population (see context.ReplacementFraction)
+// by offspring of the best organisms per epoch, the rest of organisms survive unchanged with their fitness, thus only
+// offspring need to be evaluated in the next generation. The organisms compared by fitness shared within species
+// and the population champion always survives. The offspring allocated among species the same way as in generational
+// replacement (see context.OffspringAllocationType).
+type SteadyStatePopulationEpochExecutor struct {
+}
+
+func (ex *SteadyStatePopulationEpochExecutor) NextEpoch(generation int, p *Population, context *neat.NeatContext) error {
+	replace_count, err := replacementCount(len(p.Organisms), context)
+	if err != nil {
+		return err
+	}
+
+	p.notifyGenerationStart(generation)
+
+	// The species may become empty after evaluation, e.g. when organisms replaced by immigrants
+	p.removeEmptySpecies(generation)
+	if len(p.Species) == 0 {
+		return newError(ErrNoSpecies, "POPULATION: No species with organisms left to reproduce")
+	}
+
+	// Adjust fitness of organisms the same way as generational executor do, see SequentialPopulationEpochExecutor
+	if err := guardFitness(p.Organisms, context); err != nil {
+		return err
+	}
+	if err := p.checkChampionRegression(generation, context); err != nil {
+		return err
+	}
+	scaled, err := scaleFitness(p.Organisms, generation, context)
+	if err != nil {
+		return err
+	}
+	for _, sp := range p.Species {
+		if err = sp.adjustScaledFitness(context, scaled); err != nil {
+			return err
+		}
+	}
+	if err = p.trackChampion(generation, context); err != nil {
+		return err
+	}
+
+	// Find the organisms to be replaced and allocate offspring among species
+	replaced := p.worstOrganisms(replace_count)
+	sorted_species := make([]*Species, len(p.Species))
+	copy(sorted_species, p.Species)
+	sort.Stable(sort.Reverse(byOrganismOrigFitness{sorted_species, objectiveDirection(context)}))
+	if err = p.allocateOffspring(len(replaced), context); err != nil {
+		return err
+	}
+
+	// Perform reproduction within species, the organisms marked for death are not allowed to be parents
+	babies := make([]*Organism, 0, len(replaced))
+	for _, sp := range p.Species {
+		if sp.ExpectedOffspring == 0 {
+			p.notifySpeciesReproduced(generation, sp, 0)
+			continue
+		}
+		parents := *sp
+		parents.Organisms = make([]*Organism, 0, len(sp.Organisms))
+		for _, org := range sp.Organisms {
+			if !org.IsMarkedForDeath() {
+				parents.Organisms = append(parents.Organisms, org)
+			}
+		}
+		if len(parents.Organisms) == 0 {
+			// all organisms of species are replaced, but it still should produce allocated offspring
+			parents.Organisms = append(parents.Organisms, sp.Organisms...)
+		}
+		rep_babies, err := parents.reproduce(generation, p, sorted_species, context)
+		if err != nil {
+			return err
+		}
+		p.notifySpeciesReproduced(generation, sp, len(rep_babies))
+		babies = append(babies, rep_babies...)
+	}
+	// sanity check - make sure that population size keep the same
+	if len(babies) != len(replaced) {
+		return newError(ErrProgenySizeMismatch,
+			"POPULATION: Progeny size after steady-state reproduction differs from replaced.\nExpected: [%d], but got: [%d]",
+				len(replaced), len(babies))
+	}
+
+	// Remove replaced organisms and restore survivors to the state they had after evaluation
+	is_replaced := make(map[*Organism]bool, len(replaced))
+	for _, org := range replaced {
+		is_replaced[org] = true
+		if _, err = org.Species.removeOrganism(org); err != nil {
+			return err
+		}
+	}
+	for _, org := range p.Organisms {
+		if !is_replaced[org] {
+			org.Fitness = org.originalFitness
+			org.ExpectedOffspring = 0
+			if err = org.setState(OrganismEvaluated); err != nil {
+				return err
+			}
+			org.isSurvivor = true
+		}
+	}
+
+	// speciate fresh progeny among survived species
+	if err = p.speciate(babies, context); err != nil {
+		return err
+	}
+	if err = p.adjustCompatThreshold(context); err != nil {
+		return err
+	}
+	p.notifySpeciation(generation)
+
+	// Remove empty species, age survived ones and rebuild the master organism list for the new generation
+	p.Organisms = make([]*Organism, 0)
+	p.purgeOrAgeSpecies(generation)
+
+	// Remove the innovations of the current generation
+	p.Innovations = make([]*Innovation, 0)
+
+	p.notifyReproductionDone(generation)
+	neat.DebugLog(fmt.Sprintf("POPULATION: >>>>> Steady-state epoch %d complete, replaced %d organisms\n",
+		generation, len(replaced)))
+
+	return nil
+}
+
+// Returns the number of organisms to be replaced per epoch in population of given size as configured by context. At
+// least one organism replaced and at least one (the champion) survives.
+func replacementCount(pop_size int, context *neat.NeatContext) (int, error) {
+	fraction := context.ReplacementFraction
+	if fraction == 0 {
+		fraction = defaultReplacementFraction
+	}
+	if fraction < 0 || fraction > 1 {
+		return 0, newError(ErrInvalidParameter,
+			"POPULATION: Replacement fraction should be in range (0, 1], found: %f", fraction)
+	}
+	if pop_size < 2 {
+		return 0, newError(ErrInvalidParameter,
+			"POPULATION: Too small population for steady-state replacement: %d", pop_size)
+	}
+	count := int(math.Floor(fraction * float64(pop_size) + 0.5))
+	if count < 1 {
+		count = 1
+	} else if count > pop_size - 1 {
+		count = pop_size - 1
+	}
+	return count, nil
+}
+
+// Returns up to count organisms with the lowest fitness in population, the population champion is never included.
+// The fitness of organisms should be adjusted within species before.
+func (p *Population) worstOrganisms(count int) []*Organism {
+	sorted := make([]*Organism, len(p.Organisms))
+	copy(sorted, p.Organisms)
+	sort.Stable(ByFitness(sorted))
+
+	worst := make([]*Organism, 0, count)
+	for _, org := range sorted {
+		if len(worst) == count {
+			break
+		}
+		if !org.isPopulationChampion {
+			worst = append(worst, org)
+		}
+	}
+	return worst
+}
@@ -0,0 +1,117 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestReplacementCount(t *testing.T) {
+	testCases := []struct {
+		fraction float64
+		pop_size int
+		count    int
+	}{
+		{0.0, 100, 25},
+		{0.1, 100, 10},
+		{0.5, 15, 8},
+		{0.001, 100, 1},
+		{1.0, 100, 99},
+	}
+	for _, tc := range testCases {
+		count, err := replacementCount(tc.pop_size, &neat.NeatContext{ReplacementFraction:tc.fraction})
+		if err != nil {
+			t.Error(err)
+		} else if count != tc.count {
+			t.Error("Wrong replacement count", tc.fraction, tc.pop_size, count, tc.count)
+		}
+	}
+
+	if _, err := replacementCount(100, &neat.NeatContext{ReplacementFraction:1.5}); err == nil {
+		t.Error("Error expected for replacement fraction above one")
+	}
+	if _, err := replacementCount(1, &neat.NeatContext{ReplacementFraction:0.5}); err == nil {
+		t.Error("Error expected for population of one organism")
+	}
+}
+
+func TestPopulation_worstOrganisms(t *testing.T) {
+	pop := &Population{Organisms:buildOrganismsForSorting(0.5, 0.1, 0.9, 0.3, 0.2)}
+	// the worst organism is marked as champion to check that it is never replaced
+	pop.Organisms[1].isPopulationChampion = true
+
+	replaced := pop.worstOrganisms(2)
+	if ids := organismIds(replaced); len(ids) != 2 || ids[0] != 5 || ids[1] != 4 {
+		t.Error("The worst organisms expected in ascending order of fitness", ids)
+	}
+	if replaced = pop.worstOrganisms(10); len(replaced) != 4 {
+		t.Error("The population champion should never be replaced", organismIds(replaced))
+	}
+}
+
+func TestSteadyStatePopulationEpochExecutor_NextEpoch(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.2,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+		ReplacementFraction:0.2,
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := SteadyStatePopulationEpochExecutor{}
+	for i := 0; i < 10; i++ {
+		fitness := make(map[*Organism]float64, len(pop.Organisms))
+		for _, org := range pop.Organisms {
+			if !org.IsSurvivor() {
+				org.Fitness = rand.Float64()
+			}
+			fitness[org] = org.Fitness
+		}
+		generation := i + 1
+		if err = ex.NextEpoch(generation, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(pop.Organisms) != conf.PopSize {
+			t.Error("Population size should be kept", len(pop.Organisms))
+		}
+
+		survivors := 0
+		for _, org := range pop.Organisms {
+			if org.IsSurvivor() {
+				survivors++
+				if f, ok := fitness[org]; !ok || f != org.Fitness {
+					t.Error("Survivor should keep its fitness", org.Fitness, f)
+				}
+			} else if org.Generation != generation {
+				t.Error("Offspring should be born in current generation", org.Generation)
+			} else if _, ok := fitness[org]; ok {
+				t.Error("Offspring should not be found in previous generation")
+			}
+			if org.Species == nil {
+				t.Error("Organism should be speciated")
+			}
+		}
+		if survivors != conf.PopSize - 6 {
+			t.Error("Wrong number of survivors", survivors)
+		}
+		if champ := pop.Champion(); !champ.IsSurvivor() {
+			t.Error("Population champion should survive")
+		}
+	}
+}
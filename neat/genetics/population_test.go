@@ -4,9 +4,13 @@ import (
 	"testing"
 	"github.com/yaricom/goNEAT/neat"
 	"math/rand"
+	"math"
 	"strings"
 	"bytes"
 	"bufio"
+	"sync"
+	"sync/atomic"
+	"github.com/yaricom/goNEAT/neat/utils"
 )
 
 func TestNewPopulationRandom(t *testing.T) {
@@ -55,6 +59,56 @@ func TestNewPopulationRandom(t *testing.T) {
 
 }
 
+func TestNewPopulationRandom_withRand(t *testing.T) {
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		PopSize:10,
+	}
+	pop, err := NewPopulationRandom(3, 2, 5, false, 0.5, conf.WithRand(rand.New(rand.NewSource(42))))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	other, err := NewPopulationRandom(3, 2, 5, false, 0.5, conf.WithRand(rand.New(rand.NewSource(42))))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the topologies and weights should be drawn from the generator of context
+	for i, org := range pop.Organisms {
+		if equal, err := org.Genotype.IsEqual(other.Organisms[i].Genotype); !equal {
+			t.Error("Genomes created with the same random stream are not equal", i, err)
+			return
+		}
+	}
+}
+
+func TestPopulation_newInnovationShadow(t *testing.T) {
+	pop := newPopulation()
+	pop.LastSpecies = 5
+	pop.CompatThreshold = 2.5
+	pop.CompatCoefficients = &CompatCoefficients{Disjoint:1.5, Excess:1.0, Mutdiff:0.5}
+	pop.speciesIds.skip(pop.LastSpecies)
+
+	shadow := pop.newInnovationShadow()
+	if shadow.pop.LastSpecies != pop.LastSpecies {
+		t.Error("shadow.pop.LastSpecies", pop.LastSpecies, shadow.pop.LastSpecies)
+	}
+	if shadow.pop.CompatThreshold != pop.CompatThreshold {
+		t.Error("shadow.pop.CompatThreshold", pop.CompatThreshold, shadow.pop.CompatThreshold)
+	}
+	if shadow.pop.CompatCoefficients != pop.CompatCoefficients {
+		t.Error("shadow.pop.CompatCoefficients", pop.CompatCoefficients, shadow.pop.CompatCoefficients)
+	}
+	// the species IDs should be shared to be unique among shadows and population
+	if id := shadow.pop.nextSpeciesId(); id != 6 {
+		t.Error("shadow.pop.nextSpeciesId()", 6, id)
+	}
+	if id := pop.nextSpeciesId(); id != 7 {
+		t.Error("pop.nextSpeciesId()", 7, id)
+	}
+}
+
 func TestNewPopulation(t *testing.T) {
 	rand.Seed(42)
 	in, out, nmax, n := 3, 2, 5, 3
@@ -101,6 +155,77 @@ func TestNewPopulation(t *testing.T) {
 	}
 }
 
+func TestNewPopulation_heavyTailedPerturbation(t *testing.T) {
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		PopSize:50,
+		WeightPerturbationType:int(CauchyWeightPerturbation),
+	}
+	gen := buildTestGenome(1)
+	pop, err := NewPopulation(gen, conf.WithRand(rand.New(rand.NewSource(42))))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the initial weights are perturbed or replaced uniformly within unit range regardless of configured perturbation
+	for _, org := range pop.Organisms {
+		for i, gene := range org.Genotype.Genes {
+			weight := gene.Link.Weight
+			if math.Abs(weight - gen.Genes[i].Link.Weight) > 1.0 && math.Abs(weight) > 1.0 {
+				t.Error("The initial weight is out of range", i, weight)
+				return
+			}
+		}
+	}
+}
+
+func TestNewPopulation_warmUp(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		PopSize:10,
+		WeightMutPower:2.5,
+		MutateLinkWeightsProb:1.0,
+		MutateAddNodeProb:0.5,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+		InitialMutationRounds:3,
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.5)
+	last_node_id, _ := gen.getLastNodeId()
+
+	// only link weights are mutated
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, org := range pop.Organisms {
+		if len(org.Genotype.Nodes) != len(gen.Nodes) || len(org.Genotype.Genes) != len(gen.Genes) {
+			t.Error("The topology of organism should not be changed", org.Genotype.Id)
+		}
+	}
+	if pop.Organisms[0].Genotype.Genes[0].Link.Weight == pop.Organisms[1].Genotype.Genes[0].Link.Weight {
+		t.Error("The weights of clones should be decorrelated")
+	}
+
+	// the structural mutations are applied and registered as innovations
+	conf.InitialStructuralMutation = true
+	if pop, err = NewPopulation(gen, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	grown := 0
+	for _, org := range pop.Organisms {
+		if len(org.Genotype.Nodes) > len(gen.Nodes) {
+			grown++
+		}
+	}
+	if grown == 0 || len(pop.Innovations) == 0 || pop.nextNodeId <= int32(last_node_id + 1) {
+		t.Error("The structural mutations should be applied", grown, len(pop.Innovations), pop.nextNodeId)
+	}
+}
+
 func TestReadPopulation(t *testing.T) {
 	pop_str := "genomestart 1\n" +
 		"trait 1 0.1 0 0 0 0 0 0 0\n" +
@@ -248,3 +373,362 @@ func TestPopulation_Write(t *testing.T) {
 		}
 	}
 }
+
+func TestPopulation_deltaCoding(t *testing.T) {
+	sorted_species := make([]*Species, 3)
+	for i := range sorted_species {
+		sp, err := buildSpeciesWithOrganisms(3 - i)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sp.Age = 10
+		sp.ExpectedOffspring = 5
+		sorted_species[i] = sp
+	}
+	conf := neat.NeatContext{
+		PopSize:15,
+	}
+	pop := &Population{EpochsHighestLastChanged:20}
+	pop.deltaCoding(sorted_species, &conf)
+
+	if pop.EpochsHighestLastChanged != 0 {
+		t.Error("pop.EpochsHighestLastChanged", 0, pop.EpochsHighestLastChanged)
+	}
+	// the first two species champions should get all population
+	if sorted_species[0].ExpectedOffspring != 7 || sorted_species[0].Organisms[0].superChampOffspring != 7 {
+		t.Error("Wrong offspring of the first species", sorted_species[0].ExpectedOffspring)
+	}
+	if sorted_species[1].ExpectedOffspring != 8 || sorted_species[1].Organisms[0].superChampOffspring != 8 {
+		t.Error("Wrong offspring of the second species", sorted_species[1].ExpectedOffspring)
+	}
+	if sorted_species[2].ExpectedOffspring != 0 {
+		t.Error("The third species should have no offspring", sorted_species[2].ExpectedOffspring)
+	}
+	for i := 0; i < 2; i++ {
+		if sorted_species[i].AgeOfLastImprovement != sorted_species[i].Age {
+			t.Error("Species stagnation counter should be reset", i)
+		}
+	}
+}
+
+func TestPopulation_giveBabiesToTheBest(t *testing.T) {
+	rand.Seed(42)
+	sorted_species := make([]*Species, 6)
+	for i := range sorted_species {
+		sp, err := buildSpeciesWithOrganisms(len(sorted_species) - i)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sp.Age = 10
+		sp.AgeOfLastImprovement = 10
+		sp.ExpectedOffspring = 10
+		sorted_species[i] = sp
+	}
+	// the third species is stagnated and should not receive stolen babies
+	sorted_species[2].AgeOfLastImprovement = 0
+
+	conf := neat.NeatContext{
+		PopSize:60,
+		BabiesStolen:20,
+		DropOffAge:5,
+	}
+	pop := &Population{}
+	pop.giveBabiesToTheBest(sorted_species, &conf)
+
+	total, super_champ := 0, 0
+	for _, sp := range sorted_species {
+		total += sp.ExpectedOffspring
+		super_champ += sp.Organisms[0].superChampOffspring
+	}
+	if total != conf.PopSize {
+		t.Error("The total number of offspring should not change", conf.PopSize, total)
+	}
+	if super_champ != conf.BabiesStolen {
+		t.Error("All stolen babies should be given to champions", conf.BabiesStolen, super_champ)
+	}
+	// the worst species robbed first, but keep at least one offspring
+	if sorted_species[5].ExpectedOffspring - sorted_species[5].Organisms[0].superChampOffspring != 1 {
+		t.Error("Wrong number of offspring left in the worst species", sorted_species[5].ExpectedOffspring)
+	}
+	if sorted_species[4].ExpectedOffspring - sorted_species[4].Organisms[0].superChampOffspring != 1 {
+		t.Error("Wrong number of offspring left in the second worst species", sorted_species[4].ExpectedOffspring)
+	}
+	// the top species get 1/5 1/5 of stolen, the stagnated one skipped and next gets 1/10
+	if sorted_species[0].Organisms[0].superChampOffspring < 4 {
+		t.Error("The best species champion should get at least 1/5 of stolen", sorted_species[0].Organisms[0].superChampOffspring)
+	}
+	if sorted_species[1].Organisms[0].superChampOffspring != 4 {
+		t.Error("The second species champion should get 1/5 of stolen", sorted_species[1].Organisms[0].superChampOffspring)
+	}
+	if sorted_species[2].Organisms[0].superChampOffspring != 0 {
+		t.Error("The stagnated species should not get stolen babies", sorted_species[2].Organisms[0].superChampOffspring)
+	}
+	if sorted_species[3].Organisms[0].superChampOffspring != 2 {
+		t.Error("The third not stagnated species champion should get 1/10 of stolen", sorted_species[3].Organisms[0].superChampOffspring)
+	}
+}
+
+func TestPopulation_findOrAddInnovationSynced(t *testing.T) {
+	pop := newPopulation()
+
+	var wg sync.WaitGroup
+	found := int32(0)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok := pop.findOrAddInnovationSynced(func(inn *Innovation) bool {
+				return inn.InNodeId == 1 && inn.OutNodeId == 2
+			}, func() *Innovation {
+				return NewInnovationForLink(1, 2, pop.getNextInnovationNumberAndIncrement(), 1.0, 0)
+			})
+			if ok {
+				atomic.AddInt32(&found, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(pop.Innovations) != 1 {
+		t.Error("Only one innovation should be registered", len(pop.Innovations))
+	}
+	if found != 49 {
+		t.Error("The registered innovation should be found by others", found)
+	}
+	if pop.nextInnovNum != 1 {
+		t.Error("Innovation number should be incremented only once", pop.nextInnovNum)
+	}
+}
+
+func TestPopulation_concurrentMutateAddNode(t *testing.T) {
+	rand.Seed(42)
+	pop := newPopulation()
+	pop.nextInnovNum = 3
+	pop.nextNodeId = 4
+	context := neat.NewNeatContext()
+
+	genomes := make([]*Genome, 50)
+	for i := range genomes {
+		genomes[i] = buildTestGenome(i + 1)
+		genomes[i].Genesis(i + 1)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(genomes))
+	for _, g := range genomes {
+		wg.Add(1)
+		go func(g *Genome) {
+			defer wg.Done()
+			if _, err := g.mutateAddNode(pop, context); err != nil {
+				errs <- err
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// each split gene should produce exactly one innovation with unique numbers
+	if len(pop.Innovations) > 3 {
+		t.Error("Too many innovations registered", len(pop.Innovations))
+	}
+	innov_nums := make(map[int64]bool)
+	node_ids := make(map[int]bool)
+	for _, inn := range pop.Innovations {
+		if innov_nums[inn.InnovationNum] || innov_nums[inn.InnovationNum2] || node_ids[inn.NewNodeId] {
+			t.Error("Duplicate innovation numbers found", inn)
+		}
+		innov_nums[inn.InnovationNum], innov_nums[inn.InnovationNum2] = true, true
+		node_ids[inn.NewNodeId] = true
+	}
+	// the same structural innovation should have the same numbers in all genomes
+	for _, g := range genomes {
+		for _, n := range g.Nodes {
+			if n.Id > 4 && !node_ids[n.Id] {
+				t.Error("Unknown node ID", n.Id)
+			}
+		}
+		for _, gn := range g.Genes {
+			if gn.InnovationNum > 3 && !innov_nums[gn.InnovationNum] {
+				t.Error("Unknown gene innovation number", gn.InnovationNum)
+			}
+		}
+	}
+}
+
+// Creates population of random genomes for benchmarks
+func buildBenchmarkPopulation(b *testing.B, conf *neat.NeatContext) *Population {
+	rand.Seed(42)
+	neat.LogLevel = neat.LogLevelInfo
+	in, out, nmax, n := 10, 5, 20, 10
+	pop, err := NewPopulation(newGenomeRand(1, in, out, n, nmax, false, 0.5), conf)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return pop
+}
+
+func BenchmarkPopulation_speciate(b *testing.B) {
+	conf := neat.NeatContext{PopSize:150, CompatThreshold:3.0, DisjointCoeff:1.0, ExcessCoeff:1.0,
+		MutdiffCoeff:0.4, GenCompatMethod:1}
+	pop := buildBenchmarkPopulation(b, &conf)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pop.Species = nil
+		for _, org := range pop.Organisms {
+			org.Species = nil
+		}
+		if err := pop.speciate(pop.Organisms, &conf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPopulation_nextSpeciesId(t *testing.T) {
+	pop := newPopulation()
+	for i := 1; i <= 3; i++ {
+		if id := pop.nextSpeciesId(); id != i || pop.LastSpecies != i {
+			t.Error("Wrong species ID", id, pop.LastSpecies)
+		}
+	}
+	// the highest species number set explicitly should be honored
+	pop.LastSpecies = 10
+	if id := pop.nextSpeciesId(); id != 11 {
+		t.Error("Wrong species ID after LastSpecies update", id)
+	}
+
+	// the sequence created on demand
+	pop = &Population{LastSpecies:5}
+	if id := pop.nextSpeciesId(); id != 6 {
+		t.Error("Wrong species ID without sequence", id)
+	}
+}
+
+// The observer recording IDs of extinct species
+type testSpeciesIdsObserver struct {
+	BaseEpochObserver
+	extinct map[int]bool
+}
+
+func (o *testSpeciesIdsObserver) OnSpeciesExtinct(generation int, species *Species) {
+	o.extinct[species.Id] = true
+}
+
+func TestPopulation_speciesIdsNotReused(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DisjointCoeff:1.0,
+		ExcessCoeff:1.0,
+		MutdiffCoeff:0.4,
+		DropOffAge:1,
+		PopSize: 30,
+		BabiesStolen:10,
+		RecurOnlyProb:0.2,
+		MutateAddNodeProb:0.3,
+		MutateAddLinkProb:0.3,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	observer := &testSpeciesIdsObserver{extinct:make(map[int]bool)}
+	pop.AddObserver(observer)
+
+	executor := SequentialPopulationEpochExecutor{}
+	for generation := 1; generation <= 20; generation++ {
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64() * float64(generation)
+		}
+		if err = executor.NextEpoch(generation, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		ids := make(map[int]bool)
+		for _, sp := range pop.Species {
+			if ids[sp.Id] {
+				t.Error("Duplicate species ID", sp.Id)
+			}
+			if observer.extinct[sp.Id] {
+				t.Error("Extinct species ID reused", sp.Id)
+			}
+			if sp.Id > pop.LastSpecies {
+				t.Error("Species ID is greater than LastSpecies", sp.Id, pop.LastSpecies)
+			}
+			ids[sp.Id] = true
+		}
+	}
+	if len(observer.extinct) == 0 {
+		t.Error("No species extinct during the test run")
+	}
+}
+
+func TestReadPopulation_species(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		PopSize: 20,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen := newGenomeRand(1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// make gaps in species IDs as after extinction
+	pop.Species[0].Id = 3
+	pop.Species[0].Age = 7
+	pop.LastSpecies = 12
+
+	buf := bytes.NewBufferString("")
+	pop.WriteBySpecies(buf)
+
+	r_pop, err := ReadPopulation(buf, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if r_pop.LastSpecies != pop.LastSpecies {
+		t.Error("r_pop.LastSpecies != pop.LastSpecies", r_pop.LastSpecies, pop.LastSpecies)
+	}
+	if len(r_pop.Organisms) != len(pop.Organisms) {
+		t.Error("Wrong number of organisms", len(r_pop.Organisms))
+	}
+	if len(r_pop.Species) != len(pop.Species) {
+		t.Error("Wrong number of species", len(r_pop.Species))
+		return
+	}
+	for i, sp := range pop.Species {
+		r_sp := r_pop.Species[i]
+		if r_sp.Id != sp.Id {
+			t.Error("Wrong species ID", r_sp.Id, sp.Id)
+		}
+		if r_sp.Age != sp.Age {
+			t.Error("Wrong species age", r_sp.Age, sp.Age)
+		}
+		if len(r_sp.Organisms) != len(sp.Organisms) {
+			t.Error("Wrong species size", len(r_sp.Organisms), len(sp.Organisms))
+		}
+		for _, org := range r_sp.Organisms {
+			if org.Species != r_sp {
+				t.Error("Organism is not assigned to species", org.Genotype.Id)
+			}
+		}
+	}
+	// the new species continue numbering after the highest one
+	if id := r_pop.nextSpeciesId(); id != pop.LastSpecies + 1 {
+		t.Error("Wrong next species ID", id)
+	}
+}
@@ -0,0 +1,106 @@
+package genetics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// The names of reproduction operators counted in reproduction statistics besides the names of mutation operators
+const (
+	// The offspring is the exact copy of its parent, e.g. the clone of species champion
+	CloneReproduction             = "clone"
+	// The offspring is produced from the population champion which got stolen babies
+	SuperChampReproduction        = "superChamp"
+	// The offspring is produced by mutation of single parent without mating
+	MutateOnlyReproduction        = "mutateOnly"
+	// The offspring is produced by multipoint crossover
+	MateMultipointReproduction    = "mateMultipoint"
+	// The offspring is produced by multipoint crossover with averaging of matching genes
+	MateMultipointAvgReproduction = "mateMultipointAvg"
+	// The offspring is produced by singlepoint crossover
+	MateSinglepointReproduction   = "mateSinglepoint"
+	// The offspring is produced by mating with organism of foreign species
+	InterspeciesReproduction      = "interspeciesMate"
+)
+
+// The statistics of offspring produced by particular species during reproduction cycle of generation. It allows to
+// verify that frequencies of reproduction operators match probabilities configured by context.
+type ReproductionStats struct {
+	// The generation when offspring was produced
+	Generation int
+	// The ID of species which produced offspring
+	SpeciesId  int
+	// The number of offspring produced by species
+	Offspring  int
+	// The number of offspring produced with each reproduction operator keyed by operator name, see
+	// CloneReproduction and others. The mutations applied to the offspring are counted by names of mutation
+	// operators (e.g. mutateAddNode or mutateLinkWeights), thus one offspring may be counted by several operators,
+	// e.g. by the crossover type and the following mutations.
+	Operators  map[string]int
+}
+
+// Creates new empty reproduction statistics of species in given generation
+func newReproductionStats(generation, species_id int) *ReproductionStats {
+	return &ReproductionStats{Generation:generation, SpeciesId:species_id, Operators:make(map[string]int)}
+}
+
+// Counts offspring produced with reproduction operator with given name
+func (s *ReproductionStats) count(operator string) {
+	s.Operators[operator]++
+}
+
+// Returns the reproduction statistics of species during the last reproduction cycle ordered by species ID. The
+// returned slice is a copy and can be modified by caller.
+func (p *Population) ReproductionStats() []ReproductionStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	stats := make([]ReproductionStats, len(p.reproductionStats))
+	for i, st := range p.reproductionStats {
+		stats[i] = st
+		stats[i].Operators = make(map[string]int, len(st.Operators))
+		for op, count := range st.Operators {
+			stats[i].Operators[op] = count
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].SpeciesId < stats[j].SpeciesId
+	})
+	return stats
+}
+
+// Records reproduction statistics of species. The statistics of previous generations are discarded. It is safe to
+// invoke this method concurrently, e.g. during parallel reproduction cycle.
+func (p *Population) recordReproductionStats(stats *ReproductionStats) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.reproductionStats) > 0 && p.reproductionStats[0].Generation != stats.Generation {
+		p.reproductionStats = nil
+	}
+	p.reproductionStats = append(p.reproductionStats, *stats)
+}
+
+// Writes audit log of reproduction statistics as CSV with header: generation, species ID, the number of offspring,
+// the operator name and the number of offspring produced with operator. The operators of each species are written
+// in order of names.
+func WriteReproductionAudit(w io.Writer, stats []ReproductionStats) error {
+	wr := bufio.NewWriter(w)
+	if _, err := fmt.Fprintln(wr, "generation,species,offspring,operator,count"); err != nil {
+		return err
+	}
+	for _, st := range stats {
+		operators := make([]string, 0, len(st.Operators))
+		for op := range st.Operators {
+			operators = append(operators, op)
+		}
+		sort.Strings(operators)
+		for _, op := range operators {
+			if _, err := fmt.Fprintf(wr, "%d,%d,%d,%s,%d\n", st.Generation, st.SpeciesId, st.Offspring, op,
+				st.Operators[op]); err != nil {
+				return err
+			}
+		}
+	}
+	return wr.Flush()
+}
@@ -0,0 +1,109 @@
+package genetics
+
+import (
+	"testing"
+	"bytes"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestPopulation_ReproductionStats(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.5,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.5,
+		MutateOnlyProb:1.0,
+		MutateLinkWeightsProb:1.0,
+		WeightMutPower:2.5,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	for _, ex := range []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{},
+		&ParallelPopulationEpochExecutor{}, &SteadyStatePopulationEpochExecutor{}} {
+		pop, err := NewPopulation(gen, &conf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for generation := 1; generation <= 2; generation++ {
+			for _, org := range pop.Organisms {
+				org.Fitness = rand.Float64()
+			}
+			if err = ex.NextEpoch(generation, pop, &conf); err != nil {
+				t.Error(err)
+				return
+			}
+			stats := pop.ReproductionStats()
+			if len(stats) == 0 {
+				t.Error("Reproduction statistics expected", generation)
+				continue
+			}
+			total := 0
+			for i, st := range stats {
+				if st.Generation != generation {
+					t.Error("The statistics of previous generation should be discarded", st.Generation)
+				}
+				if i > 0 && stats[i - 1].SpeciesId >= st.SpeciesId {
+					t.Error("The statistics should be ordered by species ID")
+				}
+				// each offspring is a clone or a mutant with link weights mutated
+				mutants := st.Operators[MutateOnlyReproduction]
+				if mutants + st.Operators[CloneReproduction] != st.Offspring {
+					t.Error("Wrong operators counts", st.Offspring, st.Operators)
+				}
+				if st.Operators[LinkWeightsMutation.Name()] != mutants {
+					t.Error("Weights of each mutant should be mutated", st.Operators)
+				}
+				total += st.Offspring
+			}
+			if _, ok := ex.(*SteadyStatePopulationEpochExecutor); !ok && total != conf.PopSize {
+				t.Error("Wrong total number of offspring", total)
+			}
+		}
+	}
+}
+
+func TestPopulation_ReproductionStats_copy(t *testing.T) {
+	pop := newPopulation()
+	stats := newReproductionStats(1, 2)
+	stats.count(CloneReproduction)
+	pop.recordReproductionStats(stats)
+	pop.recordReproductionStats(newReproductionStats(1, 1))
+
+	copied := pop.ReproductionStats()
+	if len(copied) != 2 || copied[0].SpeciesId != 1 || copied[1].Operators[CloneReproduction] != 1 {
+		t.Error("Wrong reproduction statistics", copied)
+		return
+	}
+	copied[1].Operators[CloneReproduction] = 10
+	if pop.ReproductionStats()[1].Operators[CloneReproduction] != 1 {
+		t.Error("Reproduction statistics should be copied")
+	}
+
+	pop.recordReproductionStats(newReproductionStats(2, 3))
+	if copied = pop.ReproductionStats(); len(copied) != 1 || copied[0].SpeciesId != 3 {
+		t.Error("Only statistics of the last generation expected", copied)
+	}
+}
+
+func TestWriteReproductionAudit(t *testing.T) {
+	stats := newReproductionStats(3, 1)
+	stats.Offspring = 4
+	stats.count(MutateOnlyReproduction)
+	stats.count(CloneReproduction)
+	stats.count(MutateOnlyReproduction)
+
+	var buf bytes.Buffer
+	if err := WriteReproductionAudit(&buf, []ReproductionStats{*stats}); err != nil {
+		t.Error(err)
+		return
+	}
+	expected := "generation,species,offspring,operator,count\n3,1,4,clone,1\n3,1,4,mutateOnly,2\n"
+	if buf.String() != expected {
+		t.Error("Wrong audit log", buf.String())
+	}
+}
@@ -0,0 +1,223 @@
+package genetics
+
+import (
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The survival selection type definition, i.e. the method to select parents for reproduction within species
+type SurvivalSelectionType int
+
+const (
+	// The truncation selection - only top SurvivalThresh part of species organisms allowed to reproduce and parents
+	// selected among them with uniform probability
+	TruncationSelection SurvivalSelectionType = iota
+	// The tournament selection - the fittest organism among TournamentSize randomly chosen organisms of species
+	// selected as parent
+	TournamentSelection
+	// The fitness-proportional (roulette wheel) selection - organism selected as parent with probability proportional
+	// to its fitness
+	RouletteSelection
+	// The stochastic universal sampling - the parents selected with probability proportional to fitness by evenly
+	// spaced pointers over the roulette wheel spun once, which reduces selection noise of independent roulette draws
+	StochasticUniversalSampling
+)
+
+// The strategy to select parent organisms for reproduction among organisms of particular species
+type ParentSelector interface {
+	// Selects parent organism among provided ones. It is assumed that provided organisms is not empty.
+	SelectParent(organisms Organisms) *Organism
+}
+
+// The interspecies mate selection type definition, i.e. the method to select mate in foreign species
+type InterspeciesMateSelectionType int
+
+const (
+	// The champion of foreign species selected as mate as in the original NEAT
+	ChampionInterspeciesMate InterspeciesMateSelectionType = iota
+	// The mate selected among survivors of foreign species with uniform probability
+	RandomInterspeciesMate
+	// The fittest organism among TournamentSize randomly chosen survivors of foreign species selected as mate
+	TournamentInterspeciesMate
+)
+
+// Returns appropriate selector of mate in foreign species for given context
+func interspeciesMateSelectorForContext(context *neat.NeatContext) (ParentSelector, error) {
+	switch InterspeciesMateSelectionType(context.InterspeciesMateSelectionType) {
+	case ChampionInterspeciesMate:
+		return championSelector{}, nil
+	case RandomInterspeciesMate:
+		return truncationSelector{rng:context.Rand()}, nil
+	case TournamentInterspeciesMate:
+		if context.TournamentSize <= 0 {
+			return nil, newError(ErrInvalidParameter,
+				"SELECTION: Wrong tournament size: %d", context.TournamentSize)
+		}
+		return tournamentSelector{size:context.TournamentSize, rng:context.Rand()}, nil
+	default:
+		return nil, newError(ErrUnsupportedType,
+			"SELECTION: Unsupported interspecies mate selection type: %d",
+				context.InterspeciesMateSelectionType)
+	}
+}
+
+// Returns appropriate parent selector for given context
+func parentSelectorForContext(context *neat.NeatContext) (ParentSelector, error) {
+	switch SurvivalSelectionType(context.SurvivalSelectionType) {
+	case TruncationSelection:
+		return truncationSelector{rng:context.Rand()}, nil
+	case TournamentSelection:
+		if context.TournamentSize <= 0 {
+			return nil, newError(ErrInvalidParameter,
+				"SELECTION: Wrong tournament size: %d", context.TournamentSize)
+		}
+		return tournamentSelector{size:context.TournamentSize, rng:context.Rand()}, nil
+	case RouletteSelection:
+		return rouletteSelector{rng:context.Rand()}, nil
+	case StochasticUniversalSampling:
+		return &susSelector{rng:context.Rand()}, nil
+	default:
+		return nil, newError(ErrUnsupportedType,
+			"SELECTION: Unsupported survival selection type: %d", context.SurvivalSelectionType)
+	}
+}
+
+// The truncation selector which selects parent among survived organisms with uniform probability
+type truncationSelector struct {
+	// The generator of random numbers
+	rng *rand.Rand
+}
+
+func (ts truncationSelector) SelectParent(organisms Organisms) *Organism {
+	return organisms[ts.rng.Intn(len(organisms))]
+}
+
+// The champion selector which selects the most fit organism, the first one in case of ties
+type championSelector struct{}
+
+func (championSelector) SelectParent(organisms Organisms) *Organism {
+	best := organisms[0]
+	for _, org := range organisms[1:] {
+		if org.Fitness > best.Fitness {
+			best = org
+		}
+	}
+	return best
+}
+
+// The tournament selector which selects the most fit organism among randomly chosen tournament participants
+type tournamentSelector struct {
+	// The number of participants in tournament
+	size int
+	// The generator of random numbers
+	rng  *rand.Rand
+}
+
+func (ts tournamentSelector) SelectParent(organisms Organisms) *Organism {
+	var best *Organism
+	for i := 0; i < ts.size; i++ {
+		org := organisms[ts.rng.Intn(len(organisms))]
+		if best == nil || org.Fitness > best.Fitness {
+			best = org
+		}
+	}
+	return best
+}
+
+// The roulette wheel selector which selects organism with probability proportional to its fitness
+type rouletteSelector struct {
+	// The generator of random numbers
+	rng *rand.Rand
+}
+
+func (rs rouletteSelector) SelectParent(organisms Organisms) *Organism {
+	total := 0.0
+	for _, org := range organisms {
+		total += org.Fitness
+	}
+	if total <= 0 {
+		// no fitness information - fallback to uniform selection
+		return organisms[rs.rng.Intn(len(organisms))]
+	}
+	throw_value := rs.rng.Float64() * total
+	accumulator := 0.0
+	for _, org := range organisms {
+		accumulator += org.Fitness
+		if throw_value <= accumulator {
+			return org
+		}
+	}
+	return organisms[len(organisms) - 1]
+}
+
+// The stochastic universal sampling selector. For each batch of selections the roulette wheel is spun once and
+// len(organisms) evenly spaced pointers select organisms with probability proportional to their fitness, thus the
+// number of times each organism selected within the batch deviates from its expected value at most by one. The batch
+// is shuffled and handed out one by one by subsequent calls, new batch is sampled when it's exhausted or when other
+// organisms provided. The selector has state and should not be shared between species.
+type susSelector struct {
+	// The organisms the current batch sampled from
+	organisms Organisms
+	// The selected organisms not handed out yet
+	batch     Organisms
+	// The generator of random numbers
+	rng       *rand.Rand
+}
+
+func (ss *susSelector) SelectParent(organisms Organisms) *Organism {
+	if len(ss.batch) == 0 || !ss.sampledFrom(organisms) {
+		ss.organisms = organisms
+		ss.batch = stochasticUniversalSample(organisms, len(organisms), ss.rng)
+	}
+	parent := ss.batch[len(ss.batch) - 1]
+	ss.batch = ss.batch[:len(ss.batch) - 1]
+	return parent
+}
+
+// Checks whether the current batch was sampled from given organisms
+func (ss *susSelector) sampledFrom(organisms Organisms) bool {
+	if len(ss.organisms) != len(organisms) {
+		return false
+	}
+	for i, org := range organisms {
+		if ss.organisms[i] != org {
+			return false
+		}
+	}
+	return true
+}
+
+// Selects n organisms with probability proportional to their fitness using n evenly spaced pointers over the roulette
+// wheel. The selected organisms returned in random order.
+func stochasticUniversalSample(organisms Organisms, n int, rng *rand.Rand) Organisms {
+	selected := make(Organisms, 0, n)
+	total := 0.0
+	for _, org := range organisms {
+		total += org.Fitness
+	}
+	if total <= 0 {
+		// no fitness information - fallback to uniform selection
+		for i := 0; i < n; i++ {
+			selected = append(selected, organisms[rng.Intn(len(organisms))])
+		}
+		return selected
+	}
+	step := total / float64(n)
+	pointer := rng.Float64() * step
+	accumulator := 0.0
+	for _, org := range organisms {
+		accumulator += org.Fitness
+		for pointer < accumulator && len(selected) < n {
+			selected = append(selected, org)
+			pointer += step
+		}
+	}
+	// guard against rounding errors
+	for len(selected) < n {
+		selected = append(selected, organisms[len(organisms) - 1])
+	}
+	rng.Shuffle(len(selected), func(i, j int) {
+		selected[i], selected[j] = selected[j], selected[i]
+	})
+	return selected
+}
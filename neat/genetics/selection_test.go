@@ -0,0 +1,199 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func buildOrganismsWithFitness(fitness ...float64) Organisms {
+	orgs := make(Organisms, len(fitness))
+	for i, f := range fitness {
+		orgs[i] = &Organism{Fitness:f}
+	}
+	return orgs
+}
+
+// Tests parentSelectorForContext
+func TestParentSelectorForContext(t *testing.T) {
+	conf := neat.NeatContext{SurvivalSelectionType:int(TruncationSelection)}
+	if sel, err := parentSelectorForContext(&conf); err != nil {
+		t.Error(err)
+	} else if _, ok := sel.(truncationSelector); !ok {
+		t.Errorf("Wrong selector type: %T", sel)
+	}
+
+	conf = neat.NeatContext{SurvivalSelectionType:int(TournamentSelection), TournamentSize:3}
+	if sel, err := parentSelectorForContext(&conf); err != nil {
+		t.Error(err)
+	} else if ts, ok := sel.(tournamentSelector); !ok || ts.size != 3 {
+		t.Errorf("Wrong selector: %v", sel)
+	}
+
+	conf = neat.NeatContext{SurvivalSelectionType:int(RouletteSelection)}
+	if sel, err := parentSelectorForContext(&conf); err != nil {
+		t.Error(err)
+	} else if _, ok := sel.(rouletteSelector); !ok {
+		t.Errorf("Wrong selector type: %T", sel)
+	}
+
+	conf = neat.NeatContext{SurvivalSelectionType:int(StochasticUniversalSampling)}
+	if sel, err := parentSelectorForContext(&conf); err != nil {
+		t.Error(err)
+	} else if _, ok := sel.(*susSelector); !ok {
+		t.Errorf("Wrong selector type: %T", sel)
+	}
+
+	conf = neat.NeatContext{SurvivalSelectionType:int(TournamentSelection)}
+	if _, err := parentSelectorForContext(&conf); err == nil {
+		t.Error("Error expected for zero tournament size")
+	}
+
+	conf = neat.NeatContext{SurvivalSelectionType:100}
+	if _, err := parentSelectorForContext(&conf); err == nil {
+		t.Error("Error expected for unsupported selection type")
+	}
+}
+
+// Tests tournamentSelector SelectParent
+func TestTournamentSelector_SelectParent(t *testing.T) {
+	rand.Seed(42)
+	orgs := buildOrganismsWithFitness(1.0, 2.0, 3.0, 4.0)
+
+	// tournament with huge number of participants should almost surely find the best one
+	sel := tournamentSelector{size:100, rng:utils.GlobalRand()}
+	if parent := sel.SelectParent(orgs); parent != orgs[3] {
+		t.Error("The best organism expected", orgs[3].Fitness, parent.Fitness)
+	}
+
+	// with single participant it should be uniform selection
+	sel = tournamentSelector{size:1, rng:utils.GlobalRand()}
+	counts := make(map[*Organism]int)
+	for i := 0; i < 1000; i++ {
+		counts[sel.SelectParent(orgs)]++
+	}
+	if len(counts) != len(orgs) {
+		t.Error("All organisms should be selected at least once", len(counts))
+	}
+}
+
+// Tests rouletteSelector SelectParent
+func TestRouletteSelector_SelectParent(t *testing.T) {
+	rand.Seed(42)
+	orgs := buildOrganismsWithFitness(0.0, 1.0, 9.0)
+
+	sel := rouletteSelector{rng:utils.GlobalRand()}
+	counts := make(map[*Organism]int)
+	for i := 0; i < 1000; i++ {
+		counts[sel.SelectParent(orgs)]++
+	}
+	if counts[orgs[0]] != 0 {
+		t.Error("Organism with zero fitness should never be selected", counts[orgs[0]])
+	}
+	if counts[orgs[2]] <= counts[orgs[1]] {
+		t.Error("The fittest organism should be selected more often", counts[orgs[2]], counts[orgs[1]])
+	}
+
+	// zero fitness - uniform selection
+	orgs = buildOrganismsWithFitness(0.0, 0.0)
+	if parent := sel.SelectParent(orgs); parent == nil {
+		t.Error("parent == nil")
+	}
+}
+
+// Tests susSelector SelectParent
+func TestSusSelector_SelectParent(t *testing.T) {
+	rand.Seed(42)
+	orgs := buildOrganismsWithFitness(0.0, 1.0, 2.0)
+
+	// each batch of selections follows the fitness proportions exactly
+	sel := &susSelector{rng:utils.GlobalRand()}
+	counts := make(map[*Organism]int)
+	for i := 0; i < 40 * len(orgs); i++ {
+		counts[sel.SelectParent(orgs)]++
+	}
+	if counts[orgs[0]] != 0 {
+		t.Error("Organism with zero fitness should never be selected", counts[orgs[0]])
+	}
+	if counts[orgs[1]] != 40 || counts[orgs[2]] != 80 {
+		t.Error("Wrong number of selections", counts[orgs[1]], counts[orgs[2]])
+	}
+
+	// the new batch sampled for other organisms
+	others := buildOrganismsWithFitness(1.0)
+	sel.SelectParent(orgs)
+	if parent := sel.SelectParent(others); parent != others[0] {
+		t.Error("The parent should be selected among provided organisms")
+	}
+
+	// zero fitness - uniform selection
+	orgs = buildOrganismsWithFitness(0.0, 0.0)
+	if parent := sel.SelectParent(orgs); parent == nil {
+		t.Error("parent == nil")
+	}
+}
+
+// Tests stochasticUniversalSample
+func TestStochasticUniversalSample(t *testing.T) {
+	rand.Seed(42)
+	orgs := buildOrganismsWithFitness(1.0, 2.0, 5.0)
+	for trial := 0; trial < 100; trial++ {
+		selected := stochasticUniversalSample(orgs, 4, utils.GlobalRand())
+		if len(selected) != 4 {
+			t.Error("len(selected) != 4", len(selected))
+			return
+		}
+		counts := make(map[*Organism]int)
+		for _, org := range selected {
+			counts[org]++
+		}
+		// the expected number of selections: 0.5, 1.0 and 2.5
+		if counts[orgs[0]] > 1 || counts[orgs[1]] != 1 || counts[orgs[2]] < 2 || counts[orgs[2]] > 3 {
+			t.Error("Selections deviate from expected", counts[orgs[0]], counts[orgs[1]], counts[orgs[2]])
+			return
+		}
+	}
+}
+
+// Tests interspeciesMateSelectorForContext
+func TestInterspeciesMateSelectorForContext(t *testing.T) {
+	conf := neat.NeatContext{InterspeciesMateSelectionType:int(ChampionInterspeciesMate)}
+	if sel, err := interspeciesMateSelectorForContext(&conf); err != nil {
+		t.Error(err)
+	} else if _, ok := sel.(championSelector); !ok {
+		t.Errorf("Wrong selector type: %T", sel)
+	}
+
+	conf = neat.NeatContext{InterspeciesMateSelectionType:int(RandomInterspeciesMate)}
+	if sel, err := interspeciesMateSelectorForContext(&conf); err != nil {
+		t.Error(err)
+	} else if _, ok := sel.(truncationSelector); !ok {
+		t.Errorf("Wrong selector type: %T", sel)
+	}
+
+	conf = neat.NeatContext{InterspeciesMateSelectionType:int(TournamentInterspeciesMate), TournamentSize:2}
+	if sel, err := interspeciesMateSelectorForContext(&conf); err != nil {
+		t.Error(err)
+	} else if ts, ok := sel.(tournamentSelector); !ok || ts.size != 2 {
+		t.Errorf("Wrong selector: %v", sel)
+	}
+
+	conf = neat.NeatContext{InterspeciesMateSelectionType:int(TournamentInterspeciesMate)}
+	if _, err := interspeciesMateSelectorForContext(&conf); err == nil {
+		t.Error("Error expected for zero tournament size")
+	}
+
+	conf = neat.NeatContext{InterspeciesMateSelectionType:100}
+	if _, err := interspeciesMateSelectorForContext(&conf); err == nil {
+		t.Error("Error expected for unsupported interspecies mate selection type")
+	}
+}
+
+// Tests championSelector SelectParent
+func TestChampionSelector_SelectParent(t *testing.T) {
+	orgs := buildOrganismsWithFitness(1.0, 4.0, 3.0, 4.0)
+	if parent := (championSelector{}).SelectParent(orgs); parent != orgs[1] {
+		t.Error("The first of the best organisms expected", parent.Fitness)
+	}
+}
@@ -0,0 +1,434 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"github.com/yaricom/goNEAT/neat"
+)
+
+// The speciation method type definition, i.e. the method to separate organisms into species
+type SpeciationType int
+
+const (
+	// The original NEAT speciation - organism joins the most compatible species if its compatibility distance to the
+	// species representative is less than compatibility threshold, otherwise new species created. The resulting
+	// species depend on the order in which organisms are speciated.
+	ThresholdSpeciation SpeciationType = iota
+	// The organisms are clustered by compatibility distance into fixed number of species using k-medoids (Voronoi
+	// iteration) algorithm. The clusters are seeded by representatives of existing species, thus species persist
+	// between generations, and the missing seeds are selected by farthest-first traversal.
+	KMedoidsSpeciation
+	// The speciation is disabled - all organisms join the single species of population, thus parents are selected
+	// globally over the whole population by survival selection configured by context (e.g. truncation or tournament)
+	// while NEAT structural mutations and crossover by historical markings are kept. It is useful for ablation studies
+	// and simple problems. The population level stagnation handling (delta coding and stolen babies) still applies.
+	NoSpeciation
+)
+
+// The maximal number of k-medoids iterations to find stable clusters
+const kMedoidsMaxIterations = 20
+
+// The maximal number of genome pairs sampled to tune compatibility coefficients
+const compatTuneMaxPairs = 1000
+
+// The coefficients of compatibility distance between genomes
+type CompatCoefficients struct {
+	// The coefficient of disjoint genes
+	Disjoint float64
+	// The coefficient of excess genes
+	Excess   float64
+	// The coefficient of mutational difference within matching genes
+	Mutdiff  float64
+}
+
+// The characterizing variables of compatibility between pair of genomes
+type compatTermsSample struct {
+	disjoint float64
+	excess   float64
+	mutdiff  float64
+}
+
+// Separates given organisms into species of this population using speciation method configured by context
+func (p *Population) speciate(organisms []*Organism, context *neat.NeatContext) error {
+	if len(organisms) == 0 {
+		return newError(ErrEmptyPopulation, "There is no organisms to speciate from")
+	}
+	if context.CompatAutoTune {
+		p.tuneCompatCoefficients(organisms, context)
+	}
+	context = p.compatContext(context)
+	var err error
+	switch SpeciationType(context.SpeciationType) {
+	case ThresholdSpeciation:
+		err = p.speciateByThreshold(organisms, context)
+	case KMedoidsSpeciation:
+		err = p.speciateByKMedoids(organisms, context)
+	case NoSpeciation:
+		err = p.speciateIntoSingle(organisms, context)
+	default:
+		return newError(ErrUnsupportedType, "POPULATION: Unsupported speciation type: %d", context.SpeciationType)
+	}
+	if err == nil && context.SpeciesMergeThreshold > 0 {
+		p.mergeSpecies(context)
+	}
+	return err
+}
+
+// Merges species of this population which representatives are closer by compatibility distance than
+// context.SpeciesMergeThreshold. The organisms of the later created species are moved into the earlier created one,
+// which keeps its ID and representative, and the merged species are removed from population. Returns the number of
+// merged species.
+func (p *Population) mergeSpecies(context *neat.NeatContext) int {
+	merged := make(map[*Species]bool)
+	for i, target := range p.Species {
+		target_rep := target.firstOrganism()
+		if target_rep == nil {
+			continue
+		}
+		for _, other := range p.Species[i + 1:] {
+			rep := other.firstOrganism()
+			if rep == nil {
+				continue
+			}
+			if target_rep.Genotype.compatibility(rep.Genotype, context) < context.SpeciesMergeThreshold {
+				neat.DebugLog(fmt.Sprintf("POPULATION: Species [%d] with %d organisms merged into species [%d]",
+					other.Id, len(other.Organisms), target.Id))
+				target.absorb(other, context)
+				merged[other] = true
+			}
+		}
+	}
+	if len(merged) > 0 {
+		species := make([]*Species, 0, len(p.Species) - len(merged))
+		for _, sp := range p.Species {
+			if !merged[sp] {
+				species = append(species, sp)
+			}
+		}
+		p.Species = species
+	}
+	return len(merged)
+}
+
+// Moves all organisms of other species into this species. The fitness record of other species is kept if it is better
+// than the record of this species, thus merge does not reset stagnation of the improving species.
+func (s *Species) absorb(other *Species, context *neat.NeatContext) {
+	for _, org := range other.Organisms {
+		org.Species = s
+		s.addOrganism(org)
+	}
+	other.Organisms = make([]*Organism, 0)
+	if !math.IsInf(other.MaxFitnessEver, -1) && objectiveDirection(context).IsRecord(other.MaxFitnessEver, s.MaxFitnessEver) {
+		s.MaxFitnessEver = other.MaxFitnessEver
+		s.AgeOfLastImprovement = s.Age - other.lastImproved()
+	}
+}
+
+// Returns the compatibility threshold of threshold speciation for this population, i.e. its own threshold if set or
+// the one configured by context otherwise
+func (p *Population) compatThreshold(context *neat.NeatContext) float64 {
+	if p.CompatThreshold > 0 {
+		return p.CompatThreshold
+	}
+	return context.CompatThreshold
+}
+
+// Adjusts compatibility threshold of this population by context.CompatAdjustStep to keep the number of species close
+// to context.SpeciesTarget: the threshold decreased if there are fewer species than target and increased if there are
+// more. The adjusted threshold is kept within [context.CompatThresholdMin, context.CompatThresholdMax] bounds, thus
+// the automatic tuning can not collapse all organisms into one species or split them into hundreds. If minimal bound
+// is not set, the threshold is not allowed to go below adjustment step, if maximal bound is not set, there is no
+// ceiling. The threshold is adjusted only for threshold speciation and if both the step and target are set.
+func (p *Population) adjustCompatThreshold(context *neat.NeatContext) error {
+	if SpeciationType(context.SpeciationType) != ThresholdSpeciation || context.CompatAdjustStep <= 0 ||
+		context.SpeciesTarget <= 0 {
+		return nil
+	}
+	min_threshold, max_threshold := context.CompatThresholdMin, context.CompatThresholdMax
+	if min_threshold <= 0 {
+		min_threshold = context.CompatAdjustStep
+	}
+	if max_threshold > 0 && min_threshold > max_threshold {
+		return newError(ErrInvalidParameter,
+			"POPULATION: Minimal compatibility threshold: %f is greater than maximal: %f",
+				min_threshold, max_threshold)
+	}
+
+	// count only species with organisms, the empty ones will be purged
+	species_count := 0
+	for _, sp := range p.Species {
+		if len(sp.Organisms) > 0 {
+			species_count++
+		}
+	}
+
+	threshold := p.compatThreshold(context)
+	if species_count < context.SpeciesTarget {
+		threshold -= context.CompatAdjustStep
+	} else if species_count > context.SpeciesTarget {
+		threshold += context.CompatAdjustStep
+	}
+	threshold = math.Max(threshold, min_threshold)
+	if max_threshold > 0 {
+		threshold = math.Min(threshold, max_threshold)
+	}
+	if threshold != p.compatThreshold(context) {
+		neat.DebugLog(fmt.Sprintf("POPULATION: Compatibility threshold adjusted to: %f, species: %d, target: %d",
+			threshold, species_count, context.SpeciesTarget))
+	}
+	p.CompatThreshold = threshold
+	return nil
+}
+
+// Returns the context with compatibility coefficients of this population if set or provided context otherwise
+func (p *Population) compatContext(context *neat.NeatContext) *neat.NeatContext {
+	if p.CompatCoefficients == nil {
+		return context
+	}
+	c := *context
+	c.DisjointCoeff = p.CompatCoefficients.Disjoint
+	c.ExcessCoeff = p.CompatCoefficients.Excess
+	c.MutdiffCoeff = p.CompatCoefficients.Mutdiff
+	return &c
+}
+
+// Tunes compatibility coefficients of this population from the compatibility terms observed between pairs of given
+// organisms. All pairs are used if there are not more than compatTuneMaxPairs of them, otherwise random pairs are
+// sampled by generator of context. The coefficients are kept unchanged if there are less than two organisms, e.g. in
+// real-time evolution.
+func (p *Population) tuneCompatCoefficients(organisms []*Organism, context *neat.NeatContext) {
+	count := len(organisms)
+	if count < 2 {
+		return
+	}
+	samples := make([]compatTermsSample, 0, compatTuneMaxPairs)
+	add_sample := func(org1, org2 *Organism) {
+		disjoint, excess, mutdiff := org1.Genotype.compatTerms(org2.Genotype)
+		if math.IsNaN(mutdiff) || math.IsInf(mutdiff, 0) {
+			// no matching genes
+			mutdiff = 0.0
+		}
+		samples = append(samples, compatTermsSample{disjoint:disjoint, excess:excess, mutdiff:mutdiff})
+	}
+	if count * (count - 1) / 2 <= compatTuneMaxPairs {
+		for i := 0; i < count; i++ {
+			for j := i + 1; j < count; j++ {
+				add_sample(organisms[i], organisms[j])
+			}
+		}
+	} else {
+		for len(samples) < compatTuneMaxPairs {
+			i, j := context.Rand().Intn(count), context.Rand().Intn(count)
+			if i != j {
+				add_sample(organisms[i], organisms[j])
+			}
+		}
+	}
+
+	if coeffs := fitCompatCoefficients(samples, context); coeffs != nil {
+		p.CompatCoefficients = coeffs
+		neat.DebugLog(fmt.Sprintf("POPULATION: Compatibility coefficients tuned to disjoint: %f, excess: %f, mutdiff: %f",
+			coeffs.Disjoint, coeffs.Excess, coeffs.Mutdiff))
+	}
+}
+
+// Fits compatibility coefficients to given samples of compatibility terms. Each term is normalized by its standard
+// deviation over samples, thus all terms have comparable spread, and weighted by the coefficient configured by context
+// as its relative importance. The terms without spread are not normalized. Afterwards all coefficients are scaled to
+// keep the mean compatibility distance over samples the same as with coefficients of context, thus compatibility
+// threshold stays meaningful. Returns nil if coefficients can not be fitted, e.g. all samples have zero distance.
+func fitCompatCoefficients(samples []compatTermsSample, context *neat.NeatContext) *CompatCoefficients {
+	if len(samples) == 0 {
+		return nil
+	}
+	n := float64(len(samples))
+	var mean, mean_sq compatTermsSample
+	for _, s := range samples {
+		mean.disjoint += s.disjoint / n
+		mean.excess += s.excess / n
+		mean.mutdiff += s.mutdiff / n
+		mean_sq.disjoint += s.disjoint * s.disjoint / n
+		mean_sq.excess += s.excess * s.excess / n
+		mean_sq.mutdiff += s.mutdiff * s.mutdiff / n
+	}
+	normalized := func(coeff, mean, mean_sq float64) float64 {
+		if std := math.Sqrt(math.Max(mean_sq - mean * mean, 0.0)); std > 0 {
+			return coeff / std
+		}
+		return coeff
+	}
+	coeffs := &CompatCoefficients{
+		Disjoint:normalized(context.DisjointCoeff, mean.disjoint, mean_sq.disjoint),
+		Excess:normalized(context.ExcessCoeff, mean.excess, mean_sq.excess),
+		Mutdiff:normalized(context.MutdiffCoeff, mean.mutdiff, mean_sq.mutdiff),
+	}
+
+	base_distance := context.DisjointCoeff * mean.disjoint + context.ExcessCoeff * mean.excess +
+		context.MutdiffCoeff * mean.mutdiff
+	distance := coeffs.Disjoint * mean.disjoint + coeffs.Excess * mean.excess + coeffs.Mutdiff * mean.mutdiff
+	if base_distance <= 0 || distance <= 0 {
+		return nil
+	}
+	scale := base_distance / distance
+	coeffs.Disjoint *= scale
+	coeffs.Excess *= scale
+	coeffs.Mutdiff *= scale
+	return coeffs
+}
+
+// Speciate separates given organisms into species of this population by clustering them with k-medoids algorithm
+// into context.SpeciesTarget clusters. The existing species of population with organisms are used as initial clusters,
+// thus if population already has enough species, organisms are only distributed among them. Otherwise, new species
+// are created for clusters seeded by given organisms.
+func (p *Population) speciateByKMedoids(organisms []*Organism, context *neat.NeatContext) error {
+	if context.SpeciesTarget <= 0 {
+		return newError(ErrInvalidParameter,
+			"POPULATION: The target number of species should be positive for k-medoids speciation, found: %d",
+			context.SpeciesTarget)
+	}
+
+	// the memoized compatibility distances between organisms
+	distances := make(map[[2]*Organism]float64)
+	distance := func(a, b *Organism) float64 {
+		if a == b {
+			return 0.0
+		}
+		key := [2]*Organism{a, b}
+		if a.Genotype.Id > b.Genotype.Id {
+			key = [2]*Organism{b, a}
+		}
+		d, ok := distances[key]
+		if !ok {
+			d = a.Genotype.compatibility(b.Genotype, context)
+			distances[key] = d
+		}
+		return d
+	}
+
+	// seed clusters by representatives of existing species
+	medoids := make([]*Organism, 0, context.SpeciesTarget)
+	species := make([]*Species, 0, context.SpeciesTarget)
+	for _, sp := range p.Species {
+		if len(medoids) == context.SpeciesTarget {
+			break
+		}
+		if rep := sp.firstOrganism(); rep != nil {
+			medoids = append(medoids, rep)
+			species = append(species, sp)
+		}
+	}
+	// seed the rest of clusters by the farthest organisms from already selected medoids
+	for len(medoids) < context.SpeciesTarget && len(medoids) < len(organisms) {
+		var farthest *Organism
+		farthest_dist := -1.0
+		for _, org := range organisms {
+			min_dist := math.MaxFloat64
+			for _, m := range medoids {
+				min_dist = math.Min(min_dist, distance(org, m))
+			}
+			if min_dist > farthest_dist {
+				farthest, farthest_dist = org, min_dist
+			}
+		}
+		if farthest_dist == 0 && len(medoids) > 0 {
+			// all organisms are identical to the selected medoids
+			break
+		}
+		medoids = append(medoids, farthest)
+		species = append(species, nil)
+	}
+
+	// do Voronoi iteration until clusters become stable
+	assignment := make([]int, len(organisms))
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	for iter := 0; iter < kMedoidsMaxIterations; iter++ {
+		changed := false
+		for i, org := range organisms {
+			nearest, nearest_dist := 0, math.MaxFloat64
+			for j, m := range medoids {
+				if d := distance(org, m); d < nearest_dist {
+					nearest, nearest_dist = j, d
+				}
+			}
+			if assignment[i] != nearest {
+				assignment[i] = nearest
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		// select new medoid of each cluster as the member with the least total distance to other members
+		clusters := make([][]*Organism, len(medoids))
+		for i, org := range organisms {
+			clusters[assignment[i]] = append(clusters[assignment[i]], org)
+		}
+		for j, members := range clusters {
+			best_total := math.MaxFloat64
+			for _, candidate := range members {
+				total := 0.0
+				for _, org := range members {
+					total += distance(candidate, org)
+				}
+				if total < best_total {
+					medoids[j], best_total = candidate, total
+				}
+			}
+		}
+	}
+
+	// add organisms to species of their clusters, the medoid goes first to become the species representative
+	clusters := make([][]*Organism, len(medoids))
+	for i, org := range organisms {
+		j := assignment[i]
+		if organisms[i] == medoids[j] {
+			clusters[j] = append([]*Organism{org}, clusters[j]...)
+		} else {
+			clusters[j] = append(clusters[j], org)
+		}
+	}
+	for j, members := range clusters {
+		if len(members) == 0 {
+			continue
+		}
+		start := 0
+		if species[j] == nil {
+			createFirstSpecies(p, members[0], context)
+			species[j] = members[0].Species
+			start = 1
+		}
+		for _, org := range members[start:] {
+			species[j].addOrganism(org)
+			org.Species = species[j]
+		}
+		neat.DebugLog(fmt.Sprintf("POPULATION: %d organisms clustered into species [%d]",
+			len(members), species[j].Id))
+	}
+	return nil
+}
+
+// Adds given organisms into the single species of this population, i.e. the first species with organisms, which is
+// created if there is no such species yet
+func (p *Population) speciateIntoSingle(organisms []*Organism, context *neat.NeatContext) error {
+	var species *Species
+	for _, sp := range p.Species {
+		if len(sp.Organisms) > 0 {
+			species = sp
+			break
+		}
+	}
+	start := 0
+	if species == nil {
+		createFirstSpecies(p, organisms[0], context)
+		species = organisms[0].Species
+		start = 1
+	}
+	for _, org := range organisms[start:] {
+		species.addOrganism(org)
+		org.Species = species
+	}
+	neat.DebugLog(fmt.Sprintf("POPULATION: %d organisms added into single species [%d]", len(organisms), species.Id))
+	return nil
+}
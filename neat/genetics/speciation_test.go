@@ -0,0 +1,551 @@
+package genetics
+
+import (
+	"testing"
+	"math/rand"
+	"github.com/yaricom/goNEAT/neat"
+	"github.com/yaricom/goNEAT/neat/utils"
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// Builds organisms forming groups with link weights around given centers
+func buildClusteredOrganisms(per_group int, centers ...float64) Organisms {
+	orgs := make(Organisms, 0, per_group * len(centers))
+	for _, c := range centers {
+		for i := 0; i < per_group; i++ {
+			gnome := buildTestGenome(len(orgs) + 1)
+			for _, gene := range gnome.Genes {
+				gene.Link.Weight = c + float64(i) * 0.1
+				gene.MutationNum = gene.Link.Weight
+			}
+			orgs = append(orgs, &Organism{Genotype:gnome})
+		}
+	}
+	return orgs
+}
+
+// Returns the group index of organism built by buildClusteredOrganisms
+func clusterGroup(org *Organism, per_group int) int {
+	return (org.Genotype.Id - 1) / per_group
+}
+
+func TestPopulation_speciate_unsupported(t *testing.T) {
+	pop := newPopulation()
+	conf := neat.NeatContext{SpeciationType:100}
+	if err := pop.speciate(buildClusteredOrganisms(2, 0.0), &conf); err == nil {
+		t.Error("Error expected for unsupported speciation type")
+	}
+	conf.SpeciationType = int(KMedoidsSpeciation)
+	if err := pop.speciate(buildClusteredOrganisms(2, 0.0), &conf); err == nil {
+		t.Error("Error expected for zero target number of species")
+	}
+}
+
+func TestPopulation_speciateByKMedoids(t *testing.T) {
+	per_group := 5
+	conf := neat.NeatContext{SpeciationType:int(KMedoidsSpeciation), SpeciesTarget:3, MutdiffCoeff:1.0}
+	for _, seed := range []int64{1, 2, 3} {
+		orgs := buildClusteredOrganisms(per_group, 0.0, 10.0, 20.0)
+		// the clustering should not depend on the order of organisms
+		rand.Seed(seed)
+		rand.Shuffle(len(orgs), func(i, j int) {
+			orgs[i], orgs[j] = orgs[j], orgs[i]
+		})
+
+		pop := newPopulation()
+		if err := pop.speciate(orgs, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(pop.Species) != 3 {
+			t.Error("Wrong number of species", len(pop.Species))
+			continue
+		}
+		for _, sp := range pop.Species {
+			if len(sp.Organisms) != per_group {
+				t.Error("Wrong species size", sp.Id, len(sp.Organisms))
+			}
+			group := clusterGroup(sp.Organisms[0], per_group)
+			for _, org := range sp.Organisms {
+				if clusterGroup(org, per_group) != group {
+					t.Error("Organisms of different groups in the same species", sp.Id, org.Genotype.Id)
+				}
+				if org.Species != sp {
+					t.Error("Organism should point to its species", org.Genotype.Id)
+				}
+			}
+		}
+	}
+}
+
+func TestPopulation_speciateByKMedoids_existingSpecies(t *testing.T) {
+	per_group := 4
+	conf := neat.NeatContext{SpeciationType:int(KMedoidsSpeciation), SpeciesTarget:2, MutdiffCoeff:1.0}
+	pop := newPopulation()
+	if err := pop.speciate(buildClusteredOrganisms(per_group, 0.0, 10.0), &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	ids := make(map[int]int)
+	for _, sp := range pop.Species {
+		ids[clusterGroup(sp.Organisms[0], per_group)] = sp.Id
+	}
+
+	// the next generation should be distributed among existing species
+	babies := buildClusteredOrganisms(per_group, 10.5, 0.5)
+	if err := pop.speciate(babies, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 2 {
+		t.Error("No new species expected", len(pop.Species))
+	}
+	for i, baby := range babies {
+		// the groups of babies are swapped
+		if expected := ids[1 - clusterGroup(baby, per_group)]; baby.Species.Id != expected {
+			t.Error("Baby joined wrong species", i, baby.Species.Id, expected)
+		}
+	}
+
+	// the new species created when target number of species increased
+	conf.SpeciesTarget = 3
+	if err := pop.speciate(buildClusteredOrganisms(per_group, 30.0), &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 3 {
+		t.Error("New species expected", len(pop.Species))
+	}
+}
+
+func TestPopulation_speciateByKMedoids_identical(t *testing.T) {
+	conf := neat.NeatContext{SpeciationType:int(KMedoidsSpeciation), SpeciesTarget:5, MutdiffCoeff:1.0}
+	orgs := make(Organisms, 0)
+	for i := 0; i < 6; i++ {
+		orgs = append(orgs, &Organism{Genotype:buildTestGenome(i + 1)})
+	}
+	pop := newPopulation()
+	if err := pop.speciate(orgs, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 1 || len(pop.Species[0].Organisms) != len(orgs) {
+		t.Error("The identical organisms should form single species", len(pop.Species))
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_kMedoids(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		SpeciationType:int(KMedoidsSpeciation),
+		SpeciesTarget:4,
+		DisjointCoeff:1.0,
+		ExcessCoeff:1.0,
+		MutdiffCoeff:0.4,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.2,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ex := SequentialPopulationEpochExecutor{}
+	for i := 0; i < 10; i++ {
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64()
+		}
+		if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(pop.Species) > conf.SpeciesTarget {
+			t.Error("Too many species", len(pop.Species))
+		}
+		if len(pop.Organisms) != conf.PopSize {
+			t.Error("Wrong population size", len(pop.Organisms))
+		}
+	}
+}
+
+func TestPopulation_adjustCompatThreshold(t *testing.T) {
+	testCases := []struct {
+		species   int
+		threshold float64
+		min, max  float64
+		expected  float64
+	}{
+		{2, 3.0, 0, 0, 2.5},
+		{6, 3.0, 0, 0, 3.5},
+		{4, 3.0, 0, 0, 3.0},
+		// the floor and ceiling
+		{2, 1.2, 1.0, 4.0, 1.0},
+		{6, 3.8, 1.0, 4.0, 4.0},
+		// the adjustment step is the floor by default
+		{2, 0.6, 0, 0, 0.5},
+		// the threshold out of bounds is clamped
+		{4, 10.0, 1.0, 4.0, 4.0},
+	}
+	for _, tc := range testCases {
+		pop := newPopulation()
+		for i := 0; i < tc.species; i++ {
+			sp := NewSpecies(i + 1)
+			sp.addOrganism(&Organism{Genotype:buildTestGenome(i + 1)})
+			pop.Species = append(pop.Species, sp)
+		}
+		// empty species are not counted
+		pop.Species = append(pop.Species, NewSpecies(tc.species + 1))
+		conf := neat.NeatContext{CompatThreshold:tc.threshold, SpeciesTarget:4, CompatAdjustStep:0.5,
+			CompatThresholdMin:tc.min, CompatThresholdMax:tc.max}
+		if err := pop.adjustCompatThreshold(&conf); err != nil {
+			t.Error(err)
+			continue
+		}
+		if pop.CompatThreshold != tc.expected {
+			t.Error("Wrong compatibility threshold", tc, pop.CompatThreshold)
+		}
+		// the population threshold should be adjusted further, not the context one
+		if conf.CompatThreshold != tc.threshold {
+			t.Error("Context threshold should not be changed", conf.CompatThreshold)
+		}
+	}
+
+	pop := newPopulation()
+	conf := neat.NeatContext{CompatThreshold:3.0, SpeciesTarget:4, CompatAdjustStep:0.5, CompatThresholdMin:5.0,
+		CompatThresholdMax:4.0}
+	if err := pop.adjustCompatThreshold(&conf); err == nil {
+		t.Error("Error expected for minimal bound greater than maximal")
+	}
+
+	// no adjustment if not configured or for other speciation types
+	conf = neat.NeatContext{CompatThreshold:3.0, SpeciesTarget:4}
+	if err := pop.adjustCompatThreshold(&conf); err != nil || pop.CompatThreshold != 0 {
+		t.Error("Threshold should not be adjusted without step", pop.CompatThreshold, err)
+	}
+	conf = neat.NeatContext{CompatThreshold:3.0, SpeciesTarget:4, CompatAdjustStep:0.5,
+		SpeciationType:int(KMedoidsSpeciation)}
+	if err := pop.adjustCompatThreshold(&conf); err != nil || pop.CompatThreshold != 0 {
+		t.Error("Threshold should not be adjusted for k-medoids speciation", pop.CompatThreshold, err)
+	}
+}
+
+func TestPopulation_speciateByThreshold_override(t *testing.T) {
+	orgs := buildClusteredOrganisms(2, 0.0, 10.0)
+	conf := neat.NeatContext{CompatThreshold:100.0, DisjointCoeff:1.0, ExcessCoeff:1.0, MutdiffCoeff:0.4}
+	pop := newPopulation()
+	if err := pop.speciate(orgs, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 1 {
+		t.Error("Single species expected by context threshold", len(pop.Species))
+	}
+
+	// the threshold of population overrides context
+	pop = newPopulation()
+	pop.CompatThreshold = 1.0
+	if err := pop.speciate(buildClusteredOrganisms(2, 0.0, 10.0), &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 2 {
+		t.Error("Two species expected by population threshold", len(pop.Species))
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_compatAdjustment(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		CompatThreshold:0.1,
+		SpeciesTarget:3,
+		CompatAdjustStep:0.2,
+		CompatThresholdMax:1.0,
+		DisjointCoeff:1.0,
+		ExcessCoeff:1.0,
+		MutdiffCoeff:0.4,
+		DropOffAge:15,
+		PopSize:30,
+		SurvivalThresh:0.2,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) <= conf.SpeciesTarget {
+		t.Error("Too low initial threshold expected to produce many species", len(pop.Species))
+	}
+	ex := SequentialPopulationEpochExecutor{}
+	for i := 0; i < 5; i++ {
+		for _, org := range pop.Organisms {
+			org.Fitness = rand.Float64()
+		}
+		if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+			t.Error(err)
+			return
+		}
+		if pop.CompatThreshold < conf.CompatAdjustStep || pop.CompatThreshold > conf.CompatThresholdMax {
+			t.Error("Compatibility threshold out of bounds", pop.CompatThreshold)
+		}
+	}
+	if pop.CompatThreshold <= conf.CompatThreshold {
+		t.Error("Compatibility threshold should be increased", pop.CompatThreshold)
+	}
+}
+
+func TestPopulation_speciateIntoSingle(t *testing.T) {
+	pop := newPopulation()
+	conf := neat.NeatContext{SpeciationType:int(NoSpeciation)}
+	if err := pop.speciate(buildClusteredOrganisms(3, 0.0, 10.0, 30.0), &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 1 || len(pop.Species[0].Organisms) != 9 {
+		t.Error("All organisms should join single species", len(pop.Species))
+		return
+	}
+	// the new organisms join existing species, the empty species are ignored
+	pop.Species = append([]*Species{NewSpecies(100)}, pop.Species...)
+	babies := buildClusteredOrganisms(2, 50.0)
+	if err := pop.speciate(babies, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 2 || len(pop.Species[1].Organisms) != 11 {
+		t.Error("Organisms should join the first species with organisms", len(pop.Species))
+	}
+	for _, org := range babies {
+		if org.Species != pop.Species[1] {
+			t.Error("Wrong species of organism", org.Genotype.Id)
+		}
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_noSpeciation(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.NeatContext{
+		SpeciationType:int(NoSpeciation),
+		SurvivalSelectionType:int(TournamentSelection),
+		TournamentSize:3,
+		DropOffAge:15,
+		PopSize:30,
+		MutateAddLinkProb:0.1,
+		MutateAddNodeProb:0.05,
+		MutateLinkWeightsProb:0.9,
+		MateMultipointProb:0.5,
+		WeightMutPower:2.5,
+		NewLinkTries:20,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+	executors := []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{}, &ParallelPopulationEpochExecutor{},
+		&SteadyStatePopulationEpochExecutor{}}
+	for _, ex := range executors {
+		pop, err := NewPopulation(gen, &conf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for i := 0; i < 5; i++ {
+			for _, org := range pop.Organisms {
+				org.Fitness = rand.Float64()
+			}
+			if err = ex.NextEpoch(i + 1, pop, &conf); err != nil {
+				t.Error(err)
+				return
+			}
+			if len(pop.Species) != 1 || len(pop.Species[0].Organisms) != conf.PopSize {
+				t.Error("Single species with all organisms expected", len(pop.Species))
+			}
+			if len(pop.Organisms) != conf.PopSize {
+				t.Error("Wrong population size", len(pop.Organisms))
+			}
+		}
+	}
+}
+
+func TestPopulation_mergeSpecies(t *testing.T) {
+	per_group := 5
+	conf := neat.NeatContext{CompatThreshold:0.05, MutdiffCoeff:1.0}
+	pop := newPopulation()
+	if err := pop.speciate(buildClusteredOrganisms(per_group, 0.0, 10.0, 20.0), &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 3 * per_group {
+		t.Error("Each organism expected in own species", len(pop.Species))
+		return
+	}
+
+	// the species within each group merged into the first one
+	conf.SpeciesMergeThreshold = 1.0
+	if merged := pop.mergeSpecies(&conf); merged != 3 * (per_group - 1) {
+		t.Error("Wrong number of merged species", merged)
+	}
+	if len(pop.Species) != 3 {
+		t.Error("Wrong number of species after merge", len(pop.Species))
+		return
+	}
+	for i, sp := range pop.Species {
+		if sp.Id != i * per_group + 1 || len(sp.Organisms) != per_group {
+			t.Error("Wrong merged species", sp.Id, len(sp.Organisms))
+		}
+		for _, org := range sp.Organisms {
+			if org.Species != sp || clusterGroup(org, per_group) != i {
+				t.Error("Wrong species of organism", org.Genotype.Id)
+			}
+		}
+	}
+
+	// the distant species are kept
+	if merged := pop.mergeSpecies(&conf); merged != 0 || len(pop.Species) != 3 {
+		t.Error("No species should be merged", merged, len(pop.Species))
+	}
+}
+
+func TestPopulation_speciate_merge(t *testing.T) {
+	conf := neat.NeatContext{CompatThreshold:0.05, MutdiffCoeff:1.0, SpeciesMergeThreshold:1.0}
+	pop := newPopulation()
+	if err := pop.speciate(buildClusteredOrganisms(4, 0.0, 10.0), &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(pop.Species) != 2 {
+		t.Error("The converged species should be merged after speciation", len(pop.Species))
+	}
+}
+
+func TestPopulation_speciate_compatAutoTune(t *testing.T) {
+	per_group := 4
+	orgs := buildClusteredOrganisms(per_group, 0.0, 10.0)
+	// the organisms of the second group have excess gene
+	for _, org := range orgs[per_group:] {
+		gnome := org.Genotype
+		gnome.Genes = append(gnome.Genes, newGene(network.NewLinkWithTrait(gnome.Traits[0], 1.0, gnome.Nodes[1],
+			gnome.Nodes[3], false), 4, 0, true))
+	}
+	conf := neat.NeatContext{CompatThreshold:3.0, DisjointCoeff:1.0, ExcessCoeff:1.0, MutdiffCoeff:1.0}
+	pop := newPopulation()
+	if err := pop.speciate(orgs, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	if pop.CompatCoefficients != nil {
+		t.Error("The coefficients should not be tuned if disabled", pop.CompatCoefficients)
+	}
+
+	conf.CompatAutoTune = true
+	pop = newPopulation()
+	if err := pop.speciate(orgs, &conf); err != nil {
+		t.Error(err)
+		return
+	}
+	coeffs := pop.CompatCoefficients
+	if coeffs == nil {
+		t.Error("The coefficients should be tuned")
+		return
+	}
+	// the excess term has much smaller spread than mutational difference
+	if coeffs.Excess <= coeffs.Mutdiff || coeffs.Disjoint <= 0 {
+		t.Error("Wrong tuned coefficients", coeffs)
+	}
+	if len(pop.Species) != 2 {
+		t.Error("Wrong number of species", len(pop.Species))
+	}
+	tuned := pop.compatContext(&conf)
+	if tuned.ExcessCoeff != coeffs.Excess || tuned.MutdiffCoeff != coeffs.Mutdiff || conf.ExcessCoeff != 1.0 {
+		t.Error("The tuned coefficients should be applied to copy of context", tuned.ExcessCoeff, conf.ExcessCoeff)
+	}
+
+	// the single organism can not tune coefficients
+	if err := pop.speciate(buildClusteredOrganisms(1, 0.0), &conf); err != nil || pop.CompatCoefficients != coeffs {
+		t.Error("The coefficients should not change", pop.CompatCoefficients, err)
+	}
+}
+
+func TestPopulation_tuneCompatCoefficients_sampled(t *testing.T) {
+	// there are more pairs of organisms than compatTuneMaxPairs, thus pairs are sampled
+	orgs := buildClusteredOrganisms(25, 0.0, 10.0)
+	conf := neat.NeatContext{DisjointCoeff:1.0, ExcessCoeff:1.0, MutdiffCoeff:1.0}
+	pop := newPopulation()
+	pop.tuneCompatCoefficients(orgs, conf.WithRand(rand.New(rand.NewSource(42))))
+	other := newPopulation()
+	other.tuneCompatCoefficients(orgs, conf.WithRand(rand.New(rand.NewSource(42))))
+	if pop.CompatCoefficients == nil || other.CompatCoefficients == nil {
+		t.Error("The coefficients should be tuned")
+		return
+	}
+	// the pairs should be sampled by generator of context
+	if *pop.CompatCoefficients != *other.CompatCoefficients {
+		t.Error("The coefficients tuned with the same random stream differ", pop.CompatCoefficients,
+			other.CompatCoefficients)
+	}
+}
+
+func TestFitCompatCoefficients(t *testing.T) {
+	conf := neat.NeatContext{DisjointCoeff:1.0, ExcessCoeff:1.0, MutdiffCoeff:1.0}
+	samples := []compatTermsSample{{disjoint:0.0, mutdiff:1.0}, {disjoint:4.0, mutdiff:2.0}}
+	coeffs := fitCompatCoefficients(samples, &conf)
+	if coeffs == nil || coeffs.Disjoint != 0.4375 || coeffs.Excess != 0.875 || coeffs.Mutdiff != 1.75 {
+		t.Error("Wrong fitted coefficients", coeffs)
+	}
+
+	// the terms with the same spread keep coefficients of context
+	samples = []compatTermsSample{{disjoint:0.0, mutdiff:0.0}, {disjoint:2.0, mutdiff:2.0}}
+	if coeffs = fitCompatCoefficients(samples, &conf); coeffs == nil || coeffs.Disjoint != 1.0 ||
+		coeffs.Mutdiff != 1.0 {
+		t.Error("The coefficients should not change", coeffs)
+	}
+
+	// nothing to fit
+	if coeffs = fitCompatCoefficients([]compatTermsSample{{}, {}}, &conf); coeffs != nil {
+		t.Error("No coefficients expected for zero distances", coeffs)
+	}
+	if coeffs = fitCompatCoefficients(nil, &conf); coeffs != nil {
+		t.Error("No coefficients expected for empty samples", coeffs)
+	}
+}
+
+func TestSpecies_absorb(t *testing.T) {
+	conf := neat.NeatContext{}
+	orgs := buildClusteredOrganisms(2, 0.0)
+	target := newSpecies(1)
+	target.Age, target.MaxFitnessEver, target.AgeOfLastImprovement = 10, 1.0, 2
+	target.addOrganism(orgs[0])
+	other := newSpecies(2)
+	other.Age, other.MaxFitnessEver, other.AgeOfLastImprovement = 3, 2.0, 2
+	other.addOrganism(orgs[1])
+
+	target.absorb(other, &conf)
+	if len(target.Organisms) != 2 || len(other.Organisms) != 0 || orgs[1].Species != target {
+		t.Error("The organisms should be moved")
+	}
+	if target.MaxFitnessEver != 2.0 || target.lastImproved() != 1 {
+		t.Error("The better fitness record should be kept", target.MaxFitnessEver, target.lastImproved())
+	}
+
+	// the worse fitness record is ignored
+	other.MaxFitnessEver, other.AgeOfLastImprovement = 0.5, 3
+	target.absorb(other, &conf)
+	if target.MaxFitnessEver != 2.0 || target.lastImproved() != 1 {
+		t.Error("The fitness record should not change", target.MaxFitnessEver, target.lastImproved())
+	}
+}
@@ -2,11 +2,8 @@ package genetics
 
 import (
 	"github.com/yaricom/goNEAT/neat"
-	"sort"
 	"math"
 	"fmt"
-	"errors"
-	"math/rand"
 	"io"
 )
 
@@ -32,6 +29,9 @@ type Species struct {
 
 	// Flag used for search optimization
 	IsChecked            bool
+
+	// The self-adapting mutation rates of this species used when adaptive mutation is enabled
+	MutationRates        *MutationRates
 }
 
 // Construct new species with specified ID
@@ -53,6 +53,8 @@ func newSpecies(id int) *Species {
 	return &Species{
 		Id:id,
 		Age:1,
+		// the fitness may be negative or minimized, thus any fitness of the first generation is an improvement
+		MaxFitnessEver:math.Inf(-1),
 		Organisms:make([]*Organism, 0),
 	}
 }
@@ -67,7 +69,7 @@ func (s Species) Write(w io.Writer) {
 	// Sort organisms - best fitness first
 	sorted_organisms := make(Organisms, len(s.Organisms))
 	copy(sorted_organisms, s.Organisms)
-	sort.Sort(sort.Reverse(sorted_organisms))
+	sortByFitnessDesc(sorted_organisms)
 
 	// Print all the Organisms' Genomes to the outFile
 	for _, org := range sorted_organisms {
@@ -85,7 +87,7 @@ func (s Species) Write(w io.Writer) {
 func (s *Species) addOrganism(o *Organism) {
 	s.Organisms = append(s.Organisms, o)
 }
-// Removes an organism from Species
+// Removes an organism from Species and archives it
 func (s *Species) removeOrganism(org *Organism) (bool, error) {
 	orgs := make([]*Organism, 0)
 	for _, o := range s.Organisms {
@@ -94,26 +96,56 @@ func (s *Species) removeOrganism(org *Organism) (bool, error) {
 		}
 	}
 	if len(orgs) != len(s.Organisms) - 1 {
-		return false, errors.New(
-			fmt.Sprintf("SPECIES: Attempt to remove nonexistent Organism from Species with #of organisms: %d", len(s.Organisms)))
+		return false, newError(ErrOrganismNotFound,
+			"SPECIES: Attempt to remove nonexistent Organism from Species with #of organisms: %d", len(s.Organisms))
 	} else {
 		s.Organisms = orgs
-		return true, nil
+		// the organism removed from species is not part of population anymore
+		return true, org.setState(OrganismArchived)
 	}
 }
 
 // Can change the fitness of the organisms in the Species to be higher for very new species (to protect them).
 // Divides the fitness by the size of the Species, so that fitness is "shared" by the species.
 // NOTE: Invocation of this method will result of species organisms sorted by fitness in descending order, i.e. most fit will be first.
-func (s *Species) adjustFitness(context *neat.NeatContext) {
+// The organisms are considered evaluated, the champion is marked as such and organisms ranked too low are marked for
+// death. Returns error if any organism of species is already archived.
+func (s *Species) adjustFitness(context *neat.NeatContext) error {
+	return s.adjustScaledFitness(context, nil)
+}
+
+// Adjusts fitness of the organisms in the Species like adjustFitness, but starting from provided scaled fitness values
+// instead of raw fitness if scaled is not nil. The raw fitness is still remembered as the original fitness. The negative
+// raw fitness is rank scaled within species if scaled is nil, because fitness sharing needs positive fitness.
+func (s *Species) adjustScaledFitness(context *neat.NeatContext, scaled map[*Organism]float64) error {
+	if len(s.Organisms) == 0 {
+		// nothing to adjust, the empty species should be removed from population
+		return nil
+	}
+	if scaled == nil {
+		scaled, _ = scaleObjectiveFitness(s.Organisms, context, func(organisms Organisms) (map[*Organism]float64, error) {
+			if hasNegativeFitness(organisms) {
+				return rankScaledFitness(organisms), nil
+			}
+			return nil, nil
+		})
+	}
 	age_debt := (s.Age - s.AgeOfLastImprovement + 1) - context.DropOffAge
 	if age_debt == 0 {
 		age_debt = 1
 	}
 
 	for _, org := range s.Organisms {
+		// The organisms are ranked anew, thus marks of previous epoch are discarded
+		if err := org.setState(OrganismEvaluated); err != nil {
+			return err
+		}
+
 		// Remember the original fitness before it gets modified
 		org.originalFitness = org.Fitness
+		if scaled != nil {
+			org.Fitness = scaled[org]
+		}
 
 		// Make fitness decrease after a stagnation point dropoff_age
 		// Added as if to keep species pristine until the dropoff point
@@ -138,46 +170,61 @@ func (s *Species) adjustFitness(context *neat.NeatContext) {
 	}
 
 	// Sort the population (most fit first) and mark for death those after : survival_thresh * pop_size
-	sort.Sort(sort.Reverse(s.Organisms))
+	sortByFitnessDesc(s.Organisms)
 
 	// Update age_of_last_improvement here
-	if s.Organisms[0].originalFitness > s.MaxFitnessEver {
+	if objectiveDirection(context).IsRecord(s.Organisms[0].originalFitness, s.MaxFitnessEver) {
 		s.AgeOfLastImprovement = s.Age
 		s.MaxFitnessEver = s.Organisms[0].originalFitness
 	}
 
+	// Mark the champ as such
+	if err := s.Organisms[0].setState(OrganismChampion); err != nil {
+		return err
+	}
+
+	// Only truncation selection removes organisms ranked too low to be parents, other selection methods
+	// use the whole species as a pool of parents
+	if SurvivalSelectionType(context.SurvivalSelectionType) != TruncationSelection {
+		return nil
+	}
+
 	// Decide how many get to reproduce based on survival_thresh * pop_size
 	// Adding 1.0 ensures that at least one will survive
 	num_parents := int(math.Floor(context.SurvivalThresh * float64(len(s.Organisms)) + 1.0))
 
 	// Mark for death those who are ranked too low to be parents
-	s.Organisms[0].isChampion = true // Mark the champ as such
 	for c := num_parents; c < len(s.Organisms); c++ {
-		s.Organisms[c].toEliminate = true
+		if err := s.Organisms[c].markForDeath(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-// Computes maximal and average fitness of species
+// Computes maximal and average fitness of species, both are zero for empty species
 func (s Species) ComputeMaxAndAvgFitness() (max, avg float64) {
+	if len(s.Organisms) == 0 {
+		return 0.0, 0.0
+	}
 	total := 0.0
+	max = math.Inf(-1)
 	for _, o := range s.Organisms {
 		total += o.Fitness
 		if o.Fitness > max {
 			max = o.Fitness
 		}
 	}
-	if len(s.Organisms) > 0 {
-		avg = total / float64(len(s.Organisms))
-	}
+	avg = total / float64(len(s.Organisms))
 	return max, avg
 }
 
 // Returns most fit organism for this species
 func (s Species) FindChampion() *Organism {
-	champ_fitness := -1.0
+	champ_fitness := math.Inf(-1)
 	var champion *Organism
 	for _, org := range s.Organisms {
-		if org.Fitness > champ_fitness {
+		if champion == nil || org.Fitness > champ_fitness {
 			champ_fitness = org.Fitness
 			champion = org
 		}
@@ -232,10 +279,10 @@ func (s Species) size() int {
 	return len(s.Organisms)
 }
 
-// Returns Organism - champion among others (best fitness)
+// Returns Organism - champion among others (best fitness) or nil if species is empty
 func (s Species) findChampion() *Organism {
-	sort.Sort(sort.Reverse(s.Organisms))
-	return s.Organisms[0]
+	sortByFitnessDesc(s.Organisms)
+	return s.firstOrganism()
 }
 
 // Perform mating and mutation to form next generation. The sorted_species is ordered to have best species in the beginning.
@@ -243,13 +290,46 @@ func (s Species) findChampion() *Organism {
 func (s Species) reproduce(generation int, pop *Population, sorted_species []*Species, context *neat.NeatContext) ([]*Organism, error) {
 	//Check for a mistake
 	if s.ExpectedOffspring > 0 && len(s.Organisms) == 0 {
-		return nil, errors.New("SPECIES: ATTEMPT TO REPRODUCE OUT OF EMPTY SPECIES")
+		return nil, newError(ErrEmptySpecies, "SPECIES: ATTEMPT TO REPRODUCE OUT OF EMPTY SPECIES")
+	} else if len(s.Organisms) == 0 {
+		// the empty species without offspring has nothing to do
+		return nil, nil
+	}
+
+	// The strategy to select parents among organisms of this species
+	selector, err := parentSelectorForContext(context)
+	if err != nil {
+		return nil, err
+	}
+	// The strategy to select mate in foreign species for interspecies mating
+	mate_selector, err := interspeciesMateSelectorForContext(context)
+	if err != nil {
+		return nil, err
+	}
+
+	// The species own mutation rates adapted and used instead of global ones
+	if context.AdaptiveMutation {
+		if s.MutationRates == nil {
+			return nil, newError(ErrInvalidParameter, "SPECIES: Species [%d] has no mutation rates to adapt", s.Id)
+		}
+		s.MutationRates.mutate(context.AdaptiveMutationPower, context.Rand())
+		context = s.MutationRates.applyTo(context)
+	}
+
+	// The pipeline of mutations to apply to offspring
+	var pipeline *MutationPipeline
+	if pop != nil && pop.MutationPipeline != nil {
+		pipeline = pop.MutationPipeline
+	} else {
+		pipeline = DefaultMutationPipeline(context)
 	}
 
 	// The number of Organisms in the old generation
 	pool_size := len(s.Organisms)
 	// The champion of the 'this' specie is the first element of the specie;
 	the_champ := s.Organisms[0]
+	// The direction of fitness objective to choose the fitter parent when mating
+	direction := objectiveDirection(context)
 
 	// The species babies
 	babies := make([]*Organism, 0)
@@ -257,12 +337,28 @@ func (s Species) reproduce(generation int, pop *Population, sorted_species []*Sp
 	// Flag the preservation of the champion
 	champ_clone_done := false
 
+	// The statistics of reproduction operators applied to produce offspring
+	stats := newReproductionStats(generation, s.Id)
+
+	// The hashes of existing organisms to detect duplicate offspring
+	var hashes map[uint64]bool
+	if context.DeduplicateOffspring {
+		hashes = make(map[uint64]bool)
+		if pop != nil {
+			for _, org := range pop.Organisms {
+				hashes[org.Genotype.Hash()] = true
+			}
+		}
+	}
+
 	// Create the designated number of offspring for the Species one at a time
 	for count := 0; count < s.ExpectedOffspring; count++ {
 		neat.DebugLog(fmt.Sprintf("SPECIES: Offspring #%d from %d, (species: %d)",
 			count, s.ExpectedOffspring, s.Id))
 
 		mut_struct_baby, mate_baby := false, false
+		// The flag to indicate that baby is intended to be the exact copy of its parent
+		clone_baby := false
 
 		// Debug Trap
 		if s.ExpectedOffspring > context.PopSize {
@@ -271,13 +367,15 @@ func (s Species) reproduce(generation int, pop *Population, sorted_species []*Sp
 		}
 
 		var baby *Organism
+		// The parents of baby, the first one is the primary parent (mom)
+		var parents []*Organism
 
 		if the_champ.superChampOffspring > 0 {
 			neat.DebugLog("SPECIES: Reproduce super champion")
 
 			// If we have a super_champ (Population champion), finish off some special clones
 			mom := the_champ;
-			new_genome, err := mom.Genotype.duplicate(count)
+			new_genome, err := mom.Genotype.cloneWithId(count)
 			if err != nil {
 				return nil, err
 			}
@@ -286,20 +384,38 @@ func (s Species) reproduce(generation int, pop *Population, sorted_species []*Sp
 			// The last offspring will be an exact duplicate of this super_champ
 			// Note: Superchamp offspring only occur with stolen babies!
 			//      Settings used for published experiments did not use this
+			stats.count(SuperChampReproduction)
 			if the_champ.superChampOffspring > 1 {
-				if rand.Float64() < 0.8 || context.MutateAddLinkProb == 0.0 {
+				if context.Rand().Float64() < 0.8 || context.MutateAddLinkProb == 0.0 {
 					// Make sure no links get added when the system has link adding disabled
-					new_genome.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator)
+					if mutated, _ := new_genome.mutateLinkWeightsForContext(context); mutated {
+						stats.count(LinkWeightsMutation.Name())
+					}
 				} else {
 					// Sometimes we add a link to a superchamp
-					new_genome.Genesis(generation)
-					if _, err = new_genome.mutateAddLink(pop, context); err != nil {
+					mutated, err := new_genome.mutateAddLink(pop, context)
+					if err != nil {
 						return nil, err
 					}
+					if mutated {
+						stats.count(AddLinkMutation.Name())
+					}
 					mut_struct_baby = true;
 				}
 			}
 
+			// guard against values which are not finite numbers
+			if new_genome, err = guardOffspringGenome(new_genome, mom.Genotype, context); err != nil {
+				return nil, err
+			}
+			// keep link weights within configured bounds
+			if _, err = new_genome.boundLinkWeights(context); err != nil {
+				return nil, err
+			}
+			// remove loops introduced by mutations or crossover
+			if context.FeedForwardOnly {
+				new_genome.disableRecurrentGenes()
+			}
 			// Create the new baby organism
 			baby, err = NewOrganism(0.0, new_genome, generation)
 			if err != nil {
@@ -307,141 +423,122 @@ func (s Species) reproduce(generation int, pop *Population, sorted_species []*Sp
 			}
 
 			if the_champ.superChampOffspring == 1 {
+				clone_baby = true
+				stats.count(CloneReproduction)
 				if the_champ.isPopulationChampion {
 					baby.isPopulationChampionChild = true
 					baby.highestFitness = mom.originalFitness
 				}
 			}
 
+			parents = []*Organism{mom}
 			the_champ.superChampOffspring--
 		} else if !champ_clone_done && s.ExpectedOffspring > 5 {
 			neat.DebugLog("SPECIES: Clone species champion")
 
 			// If we have a Species champion, just clone it
 			mom := the_champ // Mom is the champ
-			new_genome, err := mom.Genotype.duplicate(count)
+			new_genome, err := mom.Genotype.cloneWithId(count)
 			if err != nil {
 				return nil, err
 			}
 			// Baby is just like mommy
 			champ_clone_done = true
+			clone_baby = true
+			stats.count(CloneReproduction)
 
 			// Create the new baby organism
 			baby, err = NewOrganism(0.0, new_genome, generation)
 			if err != nil {
 				return nil, err
 			}
-
-		} else if rand.Float64() < context.MutateOnlyProb || pool_size == 1 {
+			parents = []*Organism{mom}
+		} else if context.Rand().Float64() < context.MutateOnlyProb || pool_size == 1 {
 			neat.DebugLog("SPECIES: Reproduce by applying random mutation:")
 
 			// Apply mutations
-			org_num := rand.Int31n(int32(pool_size)) // select random mom
-			mom := s.Organisms[org_num]
-			new_genome, err := mom.Genotype.duplicate(count)
+			mom := selector.SelectParent(s.Organisms) // select mom
+			new_genome, err := mom.Genotype.cloneWithId(count)
 			if err != nil {
 				return nil, err
 			}
 
 			// Do the mutation depending on probabilities of various mutations
-			if rand.Float64() < context.MutateAddNodeProb {
-				neat.DebugLog("SPECIES: ---> mutateAddNode")
-
-				// Mutate add node
-				if _, err = new_genome.mutateAddNode(pop, context); err != nil {
-					return nil, err
-				}
-				mut_struct_baby = true
-			} else if rand.Float64() < context.MutateAddLinkProb {
-				neat.DebugLog("SPECIES: ---> mutateAddLink")
-
-				// Mutate add link
-				new_genome.Genesis(generation)
-				if _, err = new_genome.mutateAddLink(pop, context); err != nil {
-					return nil, err
-				}
-				mut_struct_baby = true
-			} else if rand.Float64() < context.MutateConnectSensors {
-				neat.DebugLog("SPECIES: ---> mutateConnectSensors")
-				if link_added, err := new_genome.mutateConnectSensors(pop, context); err != nil {
-					return nil, err
-				} else {
-					mut_struct_baby = link_added
-				}
+			stats.count(MutateOnlyReproduction)
+			if mut_struct_baby, err = pipeline.apply(new_genome, pop, context, stats.count); err != nil {
+				return nil, err
 			}
 
-			if !mut_struct_baby {
-				neat.DebugLog("SPECIES: ---> mutateAllNonstructural")
-
-				// If we didn't do a structural mutation, we do the other kinds
-				if _, err = new_genome.mutateAllNonstructural(context); err != nil {
-					return nil, err
-				}
+			// guard against values which are not finite numbers
+			if new_genome, err = guardOffspringGenome(new_genome, mom.Genotype, context); err != nil {
+				return nil, err
+			}
+			// keep link weights within configured bounds
+			if _, err = new_genome.boundLinkWeights(context); err != nil {
+				return nil, err
+			}
+			// remove loops introduced by mutations or crossover
+			if context.FeedForwardOnly {
+				new_genome.disableRecurrentGenes()
 			}
-
 			// Create the new baby organism
 			baby, err = NewOrganism(0.0, new_genome, generation);
 			if err != nil {
 				return nil, err
 			}
+			parents = []*Organism{mom}
 		} else {
 			neat.DebugLog("SPECIES: Reproduce by mating:")
 
 			// Otherwise we should mate
-			org_num := rand.Int31n(int32(pool_size)) // select random mom
-			mom := s.Organisms[org_num]
+			mom := selector.SelectParent(s.Organisms) // select mom
 
 			// Choose random dad
 			var dad *Organism
-			if rand.Float64() > context.InterspeciesMateRate {
+			if context.Rand().Float64() > context.InterspeciesMateRate {
 				neat.DebugLog("SPECIES: ---> mate within species")
 
 				// Mate within Species
-				org_num = rand.Int31n(int32(pool_size))
-				dad = s.Organisms[org_num]
+				dad = selector.SelectParent(s.Organisms)
 			} else {
 				neat.DebugLog("SPECIES: ---> mate outside species")
 
 				// Mate outside Species
-				rand_species := s
-
-				// Select a random species
-				giveup := 0
-				for ; rand_species.Id == s.Id && giveup < 5; {
-					// Choose a random species tending towards better species
-					rand_mult := rand.Float64() / 4.0
-					// This tends to select better species
-					rand_species_num := int(math.Floor(rand_mult * float64(len(sorted_species))))
-					rand_species = *sorted_species[rand_species_num]
-
-					giveup++
+				rand_species := s.selectForeignSpecies(sorted_species, context)
+				dad = mate_selector.SelectParent(rand_species.survivors())
+				if rand_species.Id != s.Id {
+					stats.count(InterspeciesReproduction)
 				}
-				dad = rand_species.Organisms[0]
 			}
 
 			// Perform mating based on probabilities of different mating types
 			var new_genome *Genome
 			var err error
-			if rand.Float64() < context.MateMultipointProb {
+			if context.Rand().Float64() < context.MateMultipointProb {
 				neat.DebugLog("SPECIES: ------> mateMultipoint")
+				stats.count(MateMultipointReproduction)
 
 				// mate multipoint baby
-				new_genome, err = mom.Genotype.mateMultipoint(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				new_genome, err = mom.Genotype.mateMultipoint(dad.Genotype, count, direction.maximized(mom.originalFitness),
+					direction.maximized(dad.originalFitness), context)
 				if err != nil {
 					return nil, err
 				}
-			} else if rand.Float64() < context.MateMultipointAvgProb / (context.MateMultipointAvgProb + context.MateSinglepointProb) {
+			} else if context.Rand().Float64() < context.MateMultipointAvgProb / (context.MateMultipointAvgProb + context.MateSinglepointProb) {
 				neat.DebugLog("SPECIES: ------> mateMultipointAvg")
+				stats.count(MateMultipointAvgReproduction)
 
 				// mate multipoint_avg baby
-				new_genome, err = mom.Genotype.mateMultipointAvg(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				new_genome, err = mom.Genotype.mateMultipointAvg(dad.Genotype, count, direction.maximized(mom.originalFitness),
+					direction.maximized(dad.originalFitness), context)
 				if err != nil {
 					return nil, err
 				}
 			} else {
 				neat.DebugLog("SPECIES: ------> mateSinglepoint")
+				stats.count(MateSinglepointReproduction)
 
-				new_genome, err = mom.Genotype.mateSinglepoint(dad.Genotype, count)
+				new_genome, err = mom.Genotype.mateSinglepoint(dad.Genotype, count, context)
 				if err != nil {
 					return nil, err
 				}
@@ -451,68 +548,117 @@ func (s Species) reproduce(generation int, pop *Population, sorted_species []*Sp
 
 			// Determine whether to mutate the baby's Genome
 			// This is done randomly or if the mom and dad are the same organism
-			if rand.Float64() > context.MateOnlyProb ||
+			if context.Rand().Float64() > context.MateOnlyProb ||
 				dad.Genotype.Id == mom.Genotype.Id ||
 				dad.Genotype.compatibility(mom.Genotype, context) == 0.0 {
 				neat.DebugLog("SPECIES: ------> Mutatte baby genome:")
 
 				// Do the mutation depending on probabilities of  various mutations
-				if rand.Float64() < context.MutateAddNodeProb {
-					neat.DebugLog("SPECIES: ---------> mutateAddNode")
-
-					// mutate_add_node
-					if _, err = new_genome.mutateAddNode(pop, context); err != nil {
-						return nil, err
-					}
-					mut_struct_baby = true
-				} else if rand.Float64() < context.MutateAddLinkProb {
-					neat.DebugLog("SPECIES: ---------> mutateAddLink")
-
-					// mutate_add_link
-					new_genome.Genesis(generation)
-					if _, err = new_genome.mutateAddLink(pop, context); err != nil {
-						return nil, err
-					}
-					mut_struct_baby = true
-				} else if rand.Float64() < context.MutateConnectSensors {
-					neat.DebugLog("SPECIES: ---> mutateConnectSensors")
-					if link_added, err := new_genome.mutateConnectSensors(pop, context); err != nil {
-						return nil, err
-					} else {
-						mut_struct_baby = link_added
-					}
-				}
-
-				if !mut_struct_baby {
-					neat.DebugLog("SPECIES: ---> mutateAllNonstructural")
-
-					// If we didn't do a structural mutation, we do the other kinds
-					if _, err := new_genome.mutateAllNonstructural(context); err != nil {
-						return nil, err
-					}
+				if mut_struct_baby, err = pipeline.apply(new_genome, pop, context, stats.count); err != nil {
+					return nil, err
 				}
 			}
+			// guard against values which are not finite numbers
+			if new_genome, err = guardOffspringGenome(new_genome, mom.Genotype, context); err != nil {
+				return nil, err
+			}
+			// keep link weights within configured bounds
+			if _, err = new_genome.boundLinkWeights(context); err != nil {
+				return nil, err
+			}
+			// remove loops introduced by mutations or crossover
+			if context.FeedForwardOnly {
+				new_genome.disableRecurrentGenes()
+			}
 			// Create the new baby organism
 			baby, err = NewOrganism(0.0, new_genome, generation)
 			if err != nil {
 				return nil, err
 			}
+			parents = []*Organism{mom, dad}
 		} // end else
 
 		baby.mutationStructBaby = mut_struct_baby
 		baby.mateBaby = mate_baby
+		baby.inheritTags(parents, context)
+		baby.inheritLineage(parents)
+
+		if context.DeduplicateOffspring {
+			if !clone_baby {
+				if unique, err := deduplicateOrganism(baby, hashes, context); err != nil {
+					return nil, err
+				} else if !unique {
+					neat.DebugLog(fmt.Sprintf("SPECIES: Failed to make unique offspring #%d of species [%d]",
+						count, s.Id))
+				}
+			}
+			hashes[baby.Genotype.Hash()] = true
+		}
+		if s.MutationRates != nil {
+			baby.mutationRates = s.MutationRates.copy()
+		}
 
 		babies = append(babies, baby)
 
 	} // end for count := 0
+
+	stats.Offspring = len(babies)
+	if pop != nil && stats.Offspring > 0 {
+		pop.recordReproductionStats(stats)
+	}
 	return babies, nil
 }
 
-func createFirstSpecies(pop *Population, baby *Organism) {
+// Selects random species among given ones sorted from the best to the worst tending towards better species. The rank
+// of species is drawn from half-normal distribution with context.InterspeciesMateSpread deviation (as fraction of
+// species count) or uniformly among the best quarter of species if spread is not set. Makes up to five attempts to
+// select species other than this one, if all failed this species is returned.
+func (s Species) selectForeignSpecies(sorted_species []*Species, context *neat.NeatContext) *Species {
+	rand_species := &s
+	for giveup := 0; rand_species.Id == s.Id && giveup < 5; giveup++ {
+		var rand_mult float64
+		if context.InterspeciesMateSpread > 0 {
+			rand_mult = math.Abs(context.Rand().NormFloat64()) * context.InterspeciesMateSpread
+		} else {
+			rand_mult = context.Rand().Float64() / 4.0
+		}
+		// This tends to select better species
+		rand_species_num := int(math.Floor(rand_mult * float64(len(sorted_species))))
+		if rand_species_num >= len(sorted_species) {
+			rand_species_num = len(sorted_species) - 1
+		}
+		rand_species = sorted_species[rand_species_num]
+	}
+	return rand_species
+}
+
+// Returns organisms of this species which are allowed to reproduce, i.e. not marked for elimination. If all organisms
+// are marked, all of them returned.
+func (s *Species) survivors() Organisms {
+	survivors := make(Organisms, 0, len(s.Organisms))
+	for _, org := range s.Organisms {
+		if !org.IsMarkedForDeath() {
+			survivors = append(survivors, org)
+		}
+	}
+	if len(survivors) == 0 {
+		return s.Organisms
+	}
+	return survivors
+}
+
+func createFirstSpecies(pop *Population, baby *Organism, context *neat.NeatContext) {
 	neat.DebugLog(fmt.Sprintf("SPECIES: Create first species for baby organism [%d]", baby.Genotype.Id))
 
-	pop.LastSpecies++
-	new_species := NewSpeciesNovel(pop.LastSpecies, true)
+	new_species := NewSpeciesNovel(pop.nextSpeciesId(), true)
+	if context.AdaptiveMutation {
+		// inherit mutation rates from parent species if any
+		if baby.mutationRates != nil {
+			new_species.MutationRates = baby.mutationRates.copy()
+		} else {
+			new_species.MutationRates = NewMutationRates(context)
+		}
+	}
 	pop.Species = append(pop.Species, new_species)
 	new_species.addOrganism(baby) // Add the baby
 	baby.Species = new_species // Point baby to its species
@@ -533,31 +679,42 @@ func (s Species) String() string {
 }
 
 // This is used for list sorting of Species by original fitness of best organism highest fitness first
-// It implements sort.Interface for []Species based on the OriginalFitness of first Organism field in descending order,
-// i.e. the max fitness goes first
-type byOrganismOrigFitness []*Species
+// It implements sort.Interface for []Species based on the OriginalFitness of first Organism field in ascending order
+// of fitness in given objective direction, i.e. the most fit goes last
+type byOrganismOrigFitness struct {
+	species   []*Species
+	direction ObjectiveDirection
+}
 
 func (f byOrganismOrigFitness) Len() int {
-	return len(f)
+	return len(f.species)
 }
 func (f byOrganismOrigFitness) Swap(i, j int) {
-	f[i], f[j] = f[j], f[i]
+	f.species[i], f.species[j] = f.species[j], f.species[i]
 }
 func (f byOrganismOrigFitness) Less(i, j int) bool {
-	org1 := f[i].Organisms[0]
-	org2 := f[j].Organisms[0]
-	if org1.originalFitness < org2.originalFitness {
+	org1 := f.species[i].firstOrganism()
+	org2 := f.species[j].firstOrganism()
+	if org1 == nil || org2 == nil {
+		// the empty species is less than any other
+		return org1 == nil && org2 != nil
+	}
+	fitness1, fitness2 := f.direction.maximized(org1.originalFitness), f.direction.maximized(org2.originalFitness)
+	if fitness1 < fitness2 {
 		// try to promote most fit species
 		return true // Lower fitness is less
-	} else if org1.originalFitness == org2.originalFitness {
+	} else if fitness1 == fitness2 {
 		// try to promote less complex species
-		c1 := org1.Phenotype.Complexity()
-		c2 := org2.Phenotype.Complexity()
+		c1 := org1.Complexity()
+		c2 := org2.Complexity()
 		if c1 > c2 {
 			return true // Higher complexity is "less"
 		} else if c1 == c2 {
 			// try to promote younger species
-			return f[i].Age > f[j].Age // Higher Age is Less
+			if f.species[i].Age != f.species[j].Age {
+				return f.species[i].Age > f.species[j].Age // Higher Age is Less
+			}
+			return f.species[i].Id > f.species[j].Id // Older species with lower ID is greater
 		}
 	}
 	return false
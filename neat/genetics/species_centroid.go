@@ -0,0 +1,86 @@
+package genetics
+
+import (
+	"github.com/yaricom/goNEAT/neat/network"
+)
+
+// Returns the "average" genome of this species, which can be used as species representative, to seed new populations
+// or to study convergence within species. The centroid genome is built from the genome of the first organism of species
+// and has only genes matching by innovation number in genomes of all organisms, i.e. the intersection of gene pools,
+// with weights averaged over organisms. The matching gene is enabled if it is enabled in at least half of genomes. The
+// input and output nodes of the first genome are always kept, the hidden nodes are kept only if connected by matching
+// genes, and the MIMO control genes are kept only if all their IO nodes kept. The centroid genome has the same ID as
+// the first genome. Returns error if species is empty or there are no matching genes.
+func (s *Species) CentroidGenome() (*Genome, error) {
+	if len(s.Organisms) == 0 {
+		return nil, newError(ErrEmptySpecies, "SPECIES: Species [%d] has no organisms to find centroid genome", s.Id)
+	}
+	centroid, err := s.Organisms[0].Genotype.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	// collect the weights and the enabled flags of genes per innovation number
+	weights := make(map[int64]float64, len(centroid.Genes))
+	counts, enabled := make(map[int64]int, len(centroid.Genes)), make(map[int64]int, len(centroid.Genes))
+	for _, org := range s.Organisms {
+		seen := make(map[int64]bool, len(org.Genotype.Genes))
+		for _, gn := range org.Genotype.Genes {
+			if seen[gn.InnovationNum] {
+				continue
+			}
+			seen[gn.InnovationNum] = true
+			weights[gn.InnovationNum] += gn.Link.Weight
+			counts[gn.InnovationNum]++
+			if gn.IsEnabled {
+				enabled[gn.InnovationNum]++
+			}
+		}
+	}
+
+	keep := make(map[int]bool, len(centroid.Nodes))
+	for _, n := range centroid.Nodes {
+		if n.IsSensor() || n.NeuronType == network.OutputNeuron {
+			keep[n.Id] = true
+		}
+	}
+	genes := make([]*Gene, 0, len(centroid.Genes))
+	for _, gn := range centroid.Genes {
+		count := counts[gn.InnovationNum]
+		if count != len(s.Organisms) {
+			continue
+		}
+		gn.Link.Weight = weights[gn.InnovationNum] / float64(count)
+		gn.IsEnabled = enabled[gn.InnovationNum] * 2 >= count
+		// skip the duplicates of gene in the first genome
+		counts[gn.InnovationNum] = 0
+		genes = append(genes, gn)
+		keep[gn.Link.InNode.Id], keep[gn.Link.OutNode.Id] = true, true
+	}
+	if len(genes) == 0 {
+		return nil, newError(ErrIncompatibleGenomes, "SPECIES: Species [%d] has no matching genes to find centroid genome", s.Id)
+	}
+
+	control_genes := make([]*MIMOControlGene, 0, len(centroid.ControlGenes))
+	for _, cg := range centroid.ControlGenes {
+		io_kept := true
+		for _, l := range cg.ControlNode.Incoming {
+			io_kept = io_kept && keep[l.InNode.Id]
+		}
+		for _, l := range cg.ControlNode.Outgoing {
+			io_kept = io_kept && keep[l.OutNode.Id]
+		}
+		if io_kept {
+			control_genes = append(control_genes, cg)
+		}
+	}
+	nodes := make([]*network.NNode, 0, len(centroid.Nodes))
+	for _, n := range centroid.Nodes {
+		if keep[n.Id] {
+			nodes = append(nodes, n)
+		}
+	}
+	centroid.Nodes, centroid.Genes, centroid.ControlGenes = nodes, genes, control_genes
+
+	return centroid, nil
+}
@@ -0,0 +1,88 @@
+package genetics
+
+import (
+	"errors"
+	"testing"
+	"github.com/yaricom/goNEAT/neat/network"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestSpecies_CentroidGenome(t *testing.T) {
+	sp := NewSpecies(1)
+	for i := 0; i < 3; i++ {
+		gnome := buildTestGenome(i + 1)
+		for _, gn := range gnome.Genes {
+			gn.Link.Weight = float64(i + 1) * float64(gn.InnovationNum)
+		}
+		org, err := NewOrganism(float64(i), gnome, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sp.addOrganism(org)
+	}
+	// the first genome has gene with hidden node not found in other genomes
+	first := sp.Organisms[0].Genotype
+	hidden := &network.NNode{Id:5, NeuronType:network.HiddenNeuron, ActivationType:utils.SigmoidSteepenedActivation,
+		Incoming:make([]*network.Link, 0), Outgoing:make([]*network.Link, 0)}
+	first.Nodes = append(first.Nodes, hidden)
+	first.Genes = append(first.Genes, newGene(network.NewLinkWithTrait(first.Traits[0], 1.0, first.Nodes[0], hidden, false), 4, 0, true))
+	// the gene disabled in two genomes of three
+	sp.Organisms[1].Genotype.Genes[1].IsEnabled = false
+	sp.Organisms[2].Genotype.Genes[1].IsEnabled = false
+
+	centroid, err := sp.CentroidGenome()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if centroid.Id != first.Id {
+		t.Error("Wrong ID of centroid genome", centroid.Id)
+	}
+	if len(centroid.Genes) != 3 {
+		t.Error("Only matching genes expected", len(centroid.Genes))
+		return
+	}
+	for _, gn := range centroid.Genes {
+		// the average of weights: (1 + 2 + 3) * innovation / 3
+		if expected := 2.0 * float64(gn.InnovationNum); gn.Link.Weight != expected {
+			t.Error("Wrong average weight of gene", gn.InnovationNum, gn.Link.Weight, expected)
+		}
+		if gn.IsEnabled != (gn.InnovationNum != 2) {
+			t.Error("Wrong enabled flag of gene", gn.InnovationNum, gn.IsEnabled)
+		}
+	}
+	if len(centroid.Nodes) != 4 {
+		t.Error("The hidden node without matching genes should be removed", len(centroid.Nodes))
+	}
+	if _, err = centroid.verify(); err != nil {
+		t.Error(err)
+	}
+	// the genomes of species stay intact
+	if first.Genes[0].Link.Weight != 1.0 || len(first.Genes) != 4 || len(first.Nodes) != 5 {
+		t.Error("The genome of species organism changed")
+	}
+}
+
+func TestSpecies_CentroidGenome_errors(t *testing.T) {
+	if _, err := NewSpecies(1).CentroidGenome(); !errors.Is(err, ErrEmptySpecies) {
+		t.Error("!errors.Is(err, ErrEmptySpecies)", err)
+	}
+
+	sp := NewSpecies(2)
+	for i, innovation := range []int64{1, 10} {
+		gnome := buildTestGenome(i + 1)
+		for _, gn := range gnome.Genes {
+			gn.InnovationNum += innovation
+		}
+		org, err := NewOrganism(1.0, gnome, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sp.addOrganism(org)
+	}
+	if _, err := sp.CentroidGenome(); !errors.Is(err, ErrIncompatibleGenomes) {
+		t.Error("!errors.Is(err, ErrIncompatibleGenomes)", err)
+	}
+}
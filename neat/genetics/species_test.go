@@ -1,11 +1,13 @@
 package genetics
 
 import (
+	"errors"
 	"math/rand"
 	"testing"
 	"github.com/yaricom/goNEAT/neat"
 	"sort"
 	"bytes"
+	"github.com/yaricom/goNEAT/neat/utils"
 )
 
 func buildSpeciesWithOrganisms(id int) (*Species, error) {
@@ -53,8 +55,8 @@ func TestSpecies_adjustFitness(t *testing.T)  {
 	sp.adjustFitness(&conf)
 
 	// test results
-	if sp.Organisms[0].isChampion != true {
-		t.Error("sp.Organisms[0].IsChampion", true, sp.Organisms[0].isChampion)
+	if sp.Organisms[0].IsChampion() != true {
+		t.Error("sp.Organisms[0].IsChampion", true, sp.Organisms[0].IsChampion())
 	}
 	if sp.AgeOfLastImprovement != 1 {
 		t.Error("sp.AgeOfLastImprovement", 1, sp.AgeOfLastImprovement)
@@ -62,8 +64,41 @@ func TestSpecies_adjustFitness(t *testing.T)  {
 	if sp.MaxFitnessEver != 15.0 {
 		t.Error("sp.MaxFitnessEver", 15.0, sp.MaxFitnessEver)
 	}
-	if sp.Organisms[2].toEliminate != true {
-		t.Error("sp.Organisms[2].ToEliminate", true, sp.Organisms[2].toEliminate)
+	if sp.Organisms[2].IsMarkedForDeath() != true {
+		t.Error("sp.Organisms[2].IsMarkedForDeath", true, sp.Organisms[2].State())
+	}
+	if sp.Organisms[1].State() != OrganismEvaluated {
+		t.Error("The organisms should be evaluated", sp.Organisms[1].State())
+	}
+}
+
+// Tests Species adjustFitness with non truncating survival selection
+func TestSpecies_adjustFitness_tournament(t *testing.T)  {
+	sp, err := buildSpeciesWithOrganisms(1)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// Configuration
+	conf := neat.NeatContext{
+		DropOffAge:5,
+		SurvivalThresh:0.5,
+		AgeSignificance:0.5,
+		SurvivalSelectionType:int(TournamentSelection),
+		TournamentSize:2,
+	}
+	sp.adjustFitness(&conf)
+
+	// test results
+	if sp.Organisms[0].IsChampion() != true {
+		t.Error("sp.Organisms[0].IsChampion", true, sp.Organisms[0].IsChampion())
+	}
+	for i, org := range sp.Organisms {
+		if org.IsMarkedForDeath() {
+			t.Errorf("sp.Organisms[%d].IsMarkedForDeath should be false", i)
+		}
 	}
 }
 
@@ -133,6 +168,31 @@ func TestSpecies_computeMaxFitness(t *testing.T) {
 	}
 }
 
+// Tests that fitness of species is computed correctly for negative fitness of organisms
+func TestSpecies_negativeFitness(t *testing.T) {
+	sp := NewSpecies(1)
+	for _, org := range buildOrganismsForSorting(-5.0, -1.5, -3.0) {
+		sp.addOrganism(org)
+	}
+	max, avg := sp.ComputeMaxAndAvgFitness()
+	if max != -1.5 || avg != -9.5 / 3.0 {
+		t.Error("Wrong max and average of negative fitness", max, avg)
+	}
+	if champ := sp.FindChampion(); champ == nil || champ.Fitness != -1.5 {
+		t.Error("The organism with the highest negative fitness is champion", champ)
+	}
+
+	// the first adjustment of fitness is always an improvement
+	sp.Age, sp.AgeOfLastImprovement = 5, 1
+	sp.adjustFitness(&neat.NeatContext{DropOffAge:15, AgeSignificance:1.0, SurvivalThresh:0.5})
+	if sp.MaxFitnessEver != -1.5 || sp.AgeOfLastImprovement != 5 {
+		t.Error("Negative fitness should be improvement", sp.MaxFitnessEver, sp.AgeOfLastImprovement)
+	}
+	if max, _ = (&Species{}).ComputeMaxAndAvgFitness(); max != 0.0 {
+		t.Error("Zero max fitness of empty species expected", max)
+	}
+}
+
 func TestSpecies_findChampion(t *testing.T) {
 	sp, err := buildSpeciesWithOrganisms(1)
 	if err != nil {
@@ -179,8 +239,8 @@ func TestSpecies_removeOrganism(t *testing.T) {
 	if res == true {
 		t.Error("res == true", res, err)
 	}
-	if err == nil {
-		t.Error("err == nil", res, err)
+	if !errors.Is(err, ErrOrganismNotFound) {
+		t.Error("!errors.Is(err, ErrOrganismNotFound)", res, err)
 	}
 	if size != len(sp.Organisms) {
 		t.Error("size != len(sp.Organisms)", size, len(sp.Organisms))
@@ -197,8 +257,8 @@ func TestSpecies_reproduce_fail(t *testing.T) {
 	if babies != nil {
 		t.Error("babies != nil")
 	}
-	if err == nil {
-		t.Error("err == nil")
+	if !errors.Is(err, ErrEmptySpecies) {
+		t.Error("!errors.Is(err, ErrEmptySpecies)", err)
 	}
 }
 
@@ -233,7 +293,7 @@ func TestSpecies_reproduce(t *testing.T) {
 	copy(sorted_species, pop.Species)
 
 	// Sort the Species by max original fitness of its first organism
-	sort.Sort(byOrganismOrigFitness(sorted_species))
+	sort.Sort(byOrganismOrigFitness{sorted_species, MaximizeObjective})
 
 	pop.Species[0].ExpectedOffspring = 11
 
@@ -249,3 +309,333 @@ func TestSpecies_reproduce(t *testing.T) {
 		t.Error("Wrong number of babies was created", len(babies))
 	}
 }
+
+// Tests Species reproduce with adaptive mutation rates
+func TestSpecies_reproduce_feedForwardOnly(t *testing.T) {
+	rand.Seed(42)
+	in, out, nmax, n := 3, 2, 15, 3
+
+	// Configuration
+	conf := neat.NeatContext {
+		DropOffAge:5,
+		SurvivalThresh:0.5,
+		AgeSignificance:0.5,
+		PopSize:30,
+		CompatThreshold:0.6,
+		MutateAddLinkProb:0.5,
+		MutateToggleEnableProb:0.2,
+		MutateGeneReenableProb:0.2,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		RecurOnlyProb:0.5,
+		NewLinkTries:20,
+		MateMultipointProb:0.5,
+		MateSinglepointProb:0.5,
+		FeedForwardOnly:true,
+	}
+	neat.LogLevel = neat.LogLevelInfo
+
+	gen := newGenomeRand(1, in, out, n, nmax, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	sorted_species := make([]*Species, len(pop.Species))
+	copy(sorted_species, pop.Species)
+	sort.Sort(byOrganismOrigFitness{sorted_species, MaximizeObjective})
+
+	sp := pop.Species[0]
+	sp.ExpectedOffspring = 30
+	babies, err := sp.reproduce(1, pop, sorted_species, &conf)
+	if err != nil {
+		t.Error("err != nil", err)
+		return
+	}
+	for _, baby := range babies {
+		if phenotype, err := baby.Phenotype(); err != nil {
+			t.Error(err)
+		} else if !baby.Genotype.IsFeedForward() || phenotype.IsRecurrent() {
+			t.Error("Baby is recurrent", baby.Genotype)
+		}
+	}
+}
+
+func TestSpecies_reproduce_adaptiveMutation(t *testing.T) {
+	rand.Seed(42)
+	in, out, nmax, n := 3, 2, 15, 3
+
+	// Configuration
+	conf := neat.NeatContext {
+		DropOffAge:5,
+		SurvivalThresh:0.5,
+		AgeSignificance:0.5,
+		PopSize:30,
+		CompatThreshold:0.6,
+		MutateAddNodeProb:0.03,
+		MutateAddLinkProb:0.08,
+		MutateLinkWeightsProb:0.9,
+		WeightMutPower:2.5,
+		AdaptiveMutation:true,
+		AdaptiveMutationPower:0.2,
+		NodeActivators:[]utils.NodeActivationType{utils.SigmoidSteepenedActivation},
+		NodeActivatorsProb:[]float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+
+	gen := newGenomeRand(1, in, out, n, nmax, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, sp := range pop.Species {
+		if sp.MutationRates == nil {
+			t.Error("Species has no mutation rates", sp.Id)
+			return
+		}
+	}
+
+	sorted_species := make([]*Species, len(pop.Species))
+	copy(sorted_species, pop.Species)
+	sort.Sort(byOrganismOrigFitness{sorted_species, MaximizeObjective})
+
+	sp := pop.Species[0]
+	sp.ExpectedOffspring = 11
+	init_rates := *sp.MutationRates
+
+	babies, err := sp.reproduce(1, pop, sorted_species, &conf)
+	if err != nil {
+		t.Error("err != nil", err)
+		return
+	}
+	if *sp.MutationRates == init_rates {
+		t.Error("Species mutation rates was not adapted")
+	}
+	for _, baby := range babies {
+		if baby.mutationRates == nil || *baby.mutationRates != *sp.MutationRates {
+			t.Error("Baby should inherit mutation rates of species", baby.mutationRates)
+		}
+	}
+	// the global rates should stay intact
+	if conf.MutateAddNodeProb != 0.03 || conf.WeightMutPower != 2.5 {
+		t.Error("Global mutation rates was changed")
+	}
+
+	// the new species should inherit rates of the parent species
+	createFirstSpecies(pop, babies[0], &conf)
+	new_species := pop.Species[len(pop.Species) - 1]
+	if *new_species.MutationRates != *babies[0].mutationRates {
+		t.Error("New species should inherit mutation rates", new_species.MutationRates)
+	}
+	if new_species.MutationRates == babies[0].mutationRates {
+		t.Error("Mutation rates should be copied")
+	}
+}
+
+func BenchmarkSpecies_reproduce(b *testing.B) {
+	conf := neat.NeatContext{PopSize:150, CompatThreshold:3.0, DisjointCoeff:1.0, ExcessCoeff:1.0,
+		MutdiffCoeff:0.4, GenCompatMethod:1, SurvivalThresh:0.5, DropOffAge:15, AgeSignificance:1.0,
+		MutateLinkWeightsProb:0.9, WeightMutPower:2.5, MutateAddLinkProb:0.1, NewLinkTries:20,
+		MateMultipointProb:0.6, MateMultipointAvgProb:0.4, MateOnlyProb:0.2, MutateOnlyProb:0.25}
+	pop := buildBenchmarkPopulation(b, &conf)
+	sorted_species := make([]*Species, len(pop.Species))
+	copy(sorted_species, pop.Species)
+	sp := pop.Species[0]
+	sp.ExpectedOffspring = 50
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sp.reproduce(1, pop, sorted_species, &conf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Tests Species adjustScaledFitness
+func TestSpecies_adjustScaledFitness(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	conf := neat.NeatContext{
+		DropOffAge:5,
+		SurvivalThresh:0.5,
+		AgeSignificance:1.0,
+	}
+	scaled := rankScaledFitness(sp.Organisms)
+	sp.adjustScaledFitness(&conf, scaled)
+
+	for _, org := range sp.Organisms {
+		// the original fitness is raw one and the fitness is the shared scaled fitness
+		if org.Fitness != scaled[org] / float64(len(sp.Organisms)) {
+			t.Error("Wrong adjusted fitness", org.Fitness, scaled[org])
+		}
+	}
+	if sp.Organisms[0].originalFitness != 15.0 {
+		t.Error("Wrong original fitness of champion", sp.Organisms[0].originalFitness)
+	}
+	if sp.MaxFitnessEver != 15.0 {
+		t.Error("sp.MaxFitnessEver", 15.0, sp.MaxFitnessEver)
+	}
+}
+
+func TestSpecies_selectForeignSpecies(t *testing.T) {
+	sorted_species := make([]*Species, 10)
+	for i := range sorted_species {
+		sorted_species[i] = NewSpecies(i + 1)
+	}
+	worst := sorted_species[9]
+	count := func(context *neat.NeatContext) []int {
+		// the seeded generator of context makes selection deterministic
+		context = context.WithRand(rand.New(rand.NewSource(42)))
+		counts := make([]int, len(sorted_species))
+		for i := 0; i < 1000; i++ {
+			sp := worst.selectForeignSpecies(sorted_species, context)
+			counts[sp.Id - 1]++
+		}
+		return counts
+	}
+
+	// uniformly among the best quarter by default
+	counts := count(&neat.NeatContext{})
+	for i, c := range counts {
+		if i < 3 && c == 0 || i >= 3 && c != 0 {
+			t.Error("Wrong species selection by default", counts)
+			break
+		}
+	}
+
+	// the small spread gives strong bias toward the best species
+	if counts = count(&neat.NeatContext{InterspeciesMateSpread:0.05}); counts[0] < 900 {
+		t.Error("The best species should be selected mostly", counts)
+	}
+
+	// the huge spread can not select species out of range, the clamped worst species is the species itself, thus it
+	// returned mostly after attempts to find other species exhausted
+	if counts = count(&neat.NeatContext{InterspeciesMateSpread:100}); counts[9] < 900 {
+		t.Error("The species itself should be selected mostly", counts)
+	}
+
+	// the species itself returned if there are no other species
+	if sp := worst.selectForeignSpecies([]*Species{worst}, &neat.NeatContext{}); sp.Id != worst.Id {
+		t.Error("The species itself expected", sp.Id)
+	}
+}
+
+func TestSpecies_survivors(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	sp.Organisms[1].state = OrganismMarkedForDeath
+	if survivors := sp.survivors(); len(survivors) != 2 || survivors[1] != sp.Organisms[2] {
+		t.Error("Wrong survivors", survivors)
+	}
+	for _, org := range sp.Organisms {
+		org.state = OrganismMarkedForDeath
+	}
+	if survivors := sp.survivors(); len(survivors) != 3 {
+		t.Error("All organisms expected if all are marked for elimination", survivors)
+	}
+}
+
+func TestSpecies_reproduce_interspeciesMate(t *testing.T) {
+	for _, selection := range []InterspeciesMateSelectionType{
+		ChampionInterspeciesMate, RandomInterspeciesMate, TournamentInterspeciesMate} {
+		rand.Seed(42)
+		conf := neat.NeatContext{
+			DropOffAge:5,
+			SurvivalThresh:0.5,
+			AgeSignificance:0.5,
+			PopSize:30,
+			CompatThreshold:0.1,
+			DisjointCoeff:1.0,
+			ExcessCoeff:1.0,
+			MutdiffCoeff:0.4,
+			InterspeciesMateRate:1.0,
+			InterspeciesMateSelectionType:int(selection),
+			InterspeciesMateSpread:1.0,
+			TournamentSize:2,
+			MateMultipointProb:1.0,
+			MateOnlyProb:1.0,
+		}
+		gen := newGenomeRand(1, 3, 2, 3, 5, false, 0.8)
+		pop, err := NewPopulation(gen, &conf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(pop.Species) < 2 {
+			t.Error("Several species expected", len(pop.Species))
+			return
+		}
+		sorted_species := make([]*Species, len(pop.Species))
+		copy(sorted_species, pop.Species)
+
+		// find species with several organisms to mate
+		var mom_species *Species
+		for _, sp := range pop.Species {
+			if len(sp.Organisms) > 1 {
+				mom_species = sp
+				break
+			}
+		}
+		if mom_species == nil {
+			mom_species = pop.Species[0]
+			mom_species.addOrganism(pop.Species[1].Organisms[0])
+		}
+
+		mom_species.ExpectedOffspring = 5
+		babies, err := mom_species.reproduce(1, pop, sorted_species, &conf)
+		if err != nil {
+			t.Error(selection, err)
+			return
+		}
+		foreign := 0
+		for _, baby := range babies {
+			if !baby.mateBaby || len(baby.ParentLineageIds) != 2 {
+				continue
+			}
+			for _, org := range pop.Organisms {
+				if org.LineageId == baby.ParentLineageIds[1] && org.Species != mom_species {
+					foreign++
+				}
+			}
+		}
+		if foreign == 0 {
+			t.Error("Babies of interspecies mating expected", selection)
+		}
+	}
+}
+
+// Tests that methods of Species are safe to be invoked on empty species
+func TestSpecies_empty(t *testing.T) {
+	sp := NewSpecies(1)
+	sp.adjustFitness(&neat.NeatContext{})
+	if champ := sp.findChampion(); champ != nil {
+		t.Error("Empty species has no champion", champ)
+	}
+	babies, err := sp.reproduce(1, nil, nil, nil)
+	if babies != nil || err != nil {
+		t.Error("Empty species without expected offspring should produce nothing", babies, err)
+	}
+
+	other, err := buildSpeciesWithOrganisms(2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	species := []*Species{sp, other, NewSpecies(3)}
+	sort.Stable(sort.Reverse(byOrganismOrigFitness{species, MaximizeObjective}))
+	if species[0] != other {
+		t.Error("The empty species should be sorted last", species[0].Id)
+	}
+	sort.Stable(sort.Reverse(byOrganismOrigFitness{species, MinimizeObjective}))
+	if species[0] != other {
+		t.Error("The empty species should be sorted last for minimized objective", species[0].Id)
+	}
+}
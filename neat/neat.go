@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand"
 	"os"
 	"github.com/spf13/viper"
 	"errors"
@@ -72,6 +73,21 @@ type NeatContext struct {
 	TraitMutationPower     float64
 				       // The power of a link weight mutation
 	WeightMutPower         float64
+				       // The bounds of link weights applied to offspring after mutation and mating. Ignored if
+				       // WeightMin is not less than WeightMax
+	WeightMin              float64
+	WeightMax              float64
+				       // The method to keep link weights within bounds (0 - clamp, 1 - bounce back)
+	WeightBoundType        int
+				       // The probability distribution of link weight perturbations (0 - uniform, 1 - gaussian,
+				       // 2 - cauchy, 3 - laplace, 4 - self-adaptive per gene gaussian)
+	WeightPerturbationType int
+				       // If true the perturbations of link weights will be scaled inversely to the sensitivity of
+				       // network outputs to each weight estimated over random input samples (safe mutations)
+	SafeMutation           bool
+				       // The number of random input samples to estimate the sensitivity of network outputs to the
+				       // link weights. If not set 10 samples will be used.
+	SafeMutationSamples    int
 
 				       // These 3 global coefficients are used to determine the formula for
 				       // computing the compatibility between 2 genomes.  The formula is:
@@ -87,6 +103,25 @@ type NeatContext struct {
 				       // This global tells compatibility threshold under which
 				       // two Genomes are considered the same species
 	CompatThreshold        float64
+				       // The method to separate organisms into species (0 - compatibility threshold,
+				       // 1 - k-medoids clustering, 2 - no speciation, i.e. global selection over population)
+	SpeciationType         int
+				       // The number of species to maintain by clustering speciation
+	SpeciesTarget          int
+				       // The step to adjust compatibility threshold of population between generations to keep
+				       // the number of species close to SpeciesTarget by threshold speciation (0 - no adjustment)
+	CompatAdjustStep       float64
+				       // The minimal and maximal bounds of dynamic compatibility threshold adjustment. If minimal
+				       // is not set the threshold is kept above adjustment step, if maximal is not set there is no ceiling
+	CompatThresholdMin     float64
+	CompatThresholdMax     float64
+				       // The compatibility distance between representatives of species under which the species are
+				       // merged after speciation to avoid duplicate species fragmenting offspring allocation (0 - no merging)
+	SpeciesMergeThreshold  float64
+				       // If true the disjoint, excess and mutational difference coefficients are tuned each generation
+				       // by normalizing every compatibility term by its spread over population, the coefficients above
+				       // are used as relative importance of terms
+	CompatAutoTune         bool
 
 				       /* Globals involved in the epoch cycle - mating, reproduction, etc.. */
 
@@ -95,6 +130,30 @@ type NeatContext struct {
 	AgeSignificance        float64
 				       // Percent of average fitness for survival, how many get to reproduce based on survival_thresh * pop_size
 	SurvivalThresh         float64
+				       // The method to select parents within species [0 - truncation, 1 - tournament, 2 - roulette,
+				       // 3 - stochastic universal sampling]
+	SurvivalSelectionType  int
+				       // The number of organisms competing in a tournament when tournament selection is used
+	TournamentSize         int
+				       // The method to allocate offspring among species [0 - skim, 1 - fitness proportional,
+				       // 2 - rank, 3 - equal with champion bonus]
+	OffspringAllocationType int
+				       // The method to transform raw fitness of organisms before selection and offspring allocation
+				       // [0 - none, 1 - rank, 2 - sigma, 3 - Boltzmann]
+	FitnessScalingType     int
+				       // The direction of fitness objective [0 - maximize, 1 - minimize], the minimized fitness,
+				       // e.g. error or cost, is set by evaluators as is without inversion
+	ObjectiveDirection     int
+				       // The initial temperature of Boltzmann fitness scaling, the lower temperature the higher
+				       // selection pressure
+	BoltzmannTemperature   float64
+				       // The factor to multiply Boltzmann temperature by each generation (0 or 1 - no cooling)
+	BoltzmannCooling       float64
+				       // The method to estimate cost of network connections used as the secondary objective of
+				       // selection to encourage modular networks [0 - none, 1 - link count, 2 - link length]
+	ConnectionCostType     int
+				       // The probability to take connection cost into account when comparing organisms (0 - always)
+	ConnectionCostProb     float64
 
 				       // Probabilities of a non-mating reproduction
 	MutateOnlyProb         float64
@@ -104,25 +163,93 @@ type NeatContext struct {
 	MutateLinkWeightsProb  float64
 	MutateToggleEnableProb float64
 	MutateGeneReenableProb float64
+				       // The probability of perturbation of initial activations of neurons
+	MutateInitActivationProb float64
+				       // The probability of changing the delay (in activation steps) of random link by one step
+	MutateLinkDelayProb    float64
+				       // The maximal delay of link signal in activation steps, zero means that links are not delayed
+	MaxLinkDelay           int
+				       // The probability of adding gating node to random link or removing it if link already gated
+	MutateLinkGateProb     float64
+				       // The probability of turning random hidden neuron into memory cell or memory cell back into neuron
+	MutateMemoryNodeProb   float64
+				       // The probability of perturbation of forget gates of memory cells
+	MutateMemoryRetentionProb float64
 	MutateAddNodeProb      float64
 	MutateAddLinkProb      float64
 	MutateConnectSensors   float64 // probability of mutation involving disconnected inputs connection
+	MutateDupModuleProb    float64 // probability of duplication of genome sub-network (module)
+	MutateDupNodeProb      float64 // probability of duplication of hidden node with all its connections
+				       // If true than each species carries its own self-adapting mutation rates which replace
+				       // the global mutation probabilities above during reproduction
+	AdaptiveMutation       bool
+				       // The power (standard deviation of log-normal perturbation) of species mutation rates
+				       // adaptation applied each generation when AdaptiveMutation enabled
+	AdaptiveMutationPower  float64
+				       // If true than offspring which is exact duplicate of existing organism will be mutated until
+				       // it becomes unique (except of champion clones)
+	DeduplicateOffspring   bool
+				       // If true than offspring inherits user defined tags of its parents, otherwise offspring
+				       // starts with empty tags
+	InheritOrganismTags    bool
 
 				       // Probabilities of a mate being outside species
 	InterspeciesMateRate   float64
+				       // The method to select mate in foreign species for interspecies mating [0 - champion,
+				       // 1 - random survivor, 2 - tournament of TournamentSize survivors]
+	InterspeciesMateSelectionType int
+				       // The spread of half-normal distribution of foreign species rank (as fraction of species
+				       // count) for interspecies mating, the smaller spread the more bias toward the best species.
+				       // If zero the foreign species chosen uniformly among the best quarter of species.
+	InterspeciesMateSpread float64
 	MateMultipointProb     float64
 	MateMultipointAvgProb  float64
 	MateSinglepointProb    float64
 
 				       // Prob. of mating without mutation
 	MateOnlyProb           float64
+				       // Probability that gene disabled in either parent will be disabled in offspring, otherwise
+				       // it will be re-enabled. If zero than 0.75 used, negative value means always re-enable
+	MateKeepDisabledProb   float64
 				       // Probability of forcing selection of ONLY links that are naturally recurrent
 	RecurOnlyProb          float64
+				       // If true than only feed-forward networks evolved, i.e. neither mutations nor crossover
+				       // introduce recurrent links or loops. The RecurOnlyProb is ignored in this case.
+	FeedForwardOnly        bool
 
 				       // Size of population
 	PopSize                int
 				       // Age when Species starts to be penalized
 	DropOffAge             int
+				       // The number of generations without population's fitness record after which stagnated population
+				       // refocused by delta coding. If zero than DropOffAge + 5 used, negative value disables delta coding
+	PopulationStagnationLimit int
+				       // The number of generations in a row when the best organism of population is worse than the
+				       // preserved champion (the last record holder) after which the champion clones are reinjected
+				       // into population. If zero than champion regression detection is disabled
+	ChampionRegressionGenerations int
+				       // The fitness difference allowed between the best organism and the preserved champion before
+				       // it is regarded as regression
+	ChampionRegressionTolerance float64
+				       // The number of champion clones reinjected into population after regression, if zero than one
+	ChampionRegressionClones int
+				       // The type of extinction events applied to population at the start of epoch [0 - none,
+				       // 1 - the worst species killed, 2 - random catastrophe sparing champions of species]
+	ExtinctionType         int
+				       // The fraction of species killed by species extinction or the probability of each organism to
+				       // be killed by catastrophe
+	ExtinctionRate         float64
+				       // The period in generations of periodic extinction events (0 - no periodic events)
+	ExtinctionPeriod       int
+				       // The number of generations without population's fitness record which triggers extinction
+				       // event (0 - no triggered events)
+	ExtinctionStagnation   int
+				       // The number of warm-up rounds of mutations applied to each organism of initial population spawned
+				       // off of the start genome to decorrelate its clones (0 - only initial weights perturbation)
+	InitialMutationRounds  int
+				       // If true than warm-up rounds apply mutation pipeline of population including structural
+				       // mutations, otherwise only link weights are mutated
+	InitialStructuralMutation bool
 				       // Number of tries mutate_add_link will attempt to find an open link
 	NewLinkTries           int
 
@@ -137,15 +264,49 @@ type NeatContext struct {
 
 				       // The number of epochs (generations) to execute training
 	NumGenerations         int
+				       // The maximal number of organism evaluations per run after which evolution is stopped, i.e.
+				       // the evaluation budget of run (0 - unlimited)
+	MaxEvaluations         int
+				       // The minimal number of evaluation time steps organism should be alive in real-time mode
+				       // before it became eligible for removal
+	TimeAliveMinimum       int
 				       // The epoch's executor type to apply
 	EpochExecutorType      int
+				       // The fraction of population replaced by offspring per epoch by steady-state executor, the
+				       // rest of organisms survive unchanged (0 - default fraction)
+	ReplacementFraction    float64
 				       // The genome compatibility testing method to use (0 - linear, 1 - fast (make sense for large genomes))
 	GenCompatMethod        int
 
+				       // The number of times each organism evaluated to get its fitness if fitness is stochastic
+	FitnessEvalRepeats     int
+				       // The method to aggregate fitness values from repeated evaluations (0 - mean, 1 - median,
+				       // 2 - lower bound of 95% confidence interval of mean)
+	FitnessAggregationType int
+				       // The number of additional evaluations the champion should pass before being declared a winner
+	ChampionRevalidations  int
+				       // The number of evaluations of each species champion in the final tournament at the end of
+				       // trial, which picks the declared winner of trial (0 - no tournament)
+	FinalTournamentEvaluations int
+				       // The seed of the run, which seeds the global random number generator and derives the random
+				       // number streams of organism evaluations (0 - seeded by current time, the run is not reproducible)
+	RandomSeed             int64
+				       // The policy to handle errors of organism evaluation (0 - abort run, 1 - assign minimal fitness,
+				       // 2 - retry evaluation)
+	EvalErrorPolicy        int
+				       // The maximal number of evaluation retries when retry policy is used
+	EvalErrorRetries       int
+				       // The policy to handle weights, fitness and activations which are not finite numbers
+				       // (0 - ignore, i.e. keep them as is, 1 - clamp, 2 - reject organism, 3 - error)
+	NonFinitePolicy        int
+
 				       // The neuron nodes activation functions list to choose from
 	NodeActivators         []utils.NodeActivationType
 				       // The probabilities of selection of the specific node activator function
 	NodeActivatorsProb     []float64
+
+				       // The random number generator of evolution, if not set the global one is used
+	rng                    *rand.Rand
 }
 
 // Creates new empty NEAT context
@@ -170,10 +331,20 @@ func (c *NeatContext) LoadContext(r io.Reader) error {
 	c.TraitParamMutProb = v.GetFloat64("trait_param_mut_prob")
 	c.TraitMutationPower = v.GetFloat64("trait_mutation_power")
 	c.WeightMutPower = v.GetFloat64("weight_mut_power")
+	c.WeightMin = v.GetFloat64("weight_min")
+	c.WeightMax = v.GetFloat64("weight_max")
+	c.SafeMutation = v.GetBool("safe_mutation")
+	c.SafeMutationSamples = v.GetInt("safe_mutation_samples")
 	c.DisjointCoeff = v.GetFloat64("disjoint_coeff")
 	c.ExcessCoeff = v.GetFloat64("excess_coeff")
 	c.MutdiffCoeff = v.GetFloat64("mutdiff_coeff")
 	c.CompatThreshold = v.GetFloat64("compat_threshold")
+	c.SpeciesTarget = v.GetInt("species_target")
+	c.CompatAdjustStep = v.GetFloat64("compat_adjust_step")
+	c.CompatThresholdMin = v.GetFloat64("compat_threshold_min")
+	c.CompatThresholdMax = v.GetFloat64("compat_threshold_max")
+	c.SpeciesMergeThreshold = v.GetFloat64("species_merge_threshold")
+	c.CompatAutoTune = v.GetBool("compat_auto_tune")
 	c.AgeSignificance = v.GetFloat64("age_significance")
 	c.SurvivalThresh = v.GetFloat64("survival_thresh")
 	c.MutateOnlyProb = v.GetFloat64("mutate_only_prob")
@@ -183,30 +354,69 @@ func (c *NeatContext) LoadContext(r io.Reader) error {
 	c.MutateLinkWeightsProb = v.GetFloat64("mutate_link_weights_prob")
 	c.MutateToggleEnableProb = v.GetFloat64("mutate_toggle_enable_prob")
 	c.MutateGeneReenableProb = v.GetFloat64("mutate_gene_reenable_prob")
+	c.MutateInitActivationProb = v.GetFloat64("mutate_init_activation_prob")
+	c.MutateLinkDelayProb = v.GetFloat64("mutate_link_delay_prob")
+	c.MaxLinkDelay = v.GetInt("max_link_delay")
+	c.MutateLinkGateProb = v.GetFloat64("mutate_link_gate_prob")
+	c.MutateMemoryNodeProb = v.GetFloat64("mutate_memory_node_prob")
+	c.MutateMemoryRetentionProb = v.GetFloat64("mutate_memory_retention_prob")
 	c.MutateAddNodeProb = v.GetFloat64("mutate_add_node_prob")
 	c.MutateAddLinkProb = v.GetFloat64("mutate_add_link_prob")
 	c.MutateConnectSensors = v.GetFloat64("mutate_connect_sensors")
+	c.MutateDupModuleProb = v.GetFloat64("mutate_dup_module_prob")
+	c.MutateDupNodeProb = v.GetFloat64("mutate_dup_node_prob")
+	c.AdaptiveMutation = v.GetBool("adaptive_mutation")
+	c.AdaptiveMutationPower = v.GetFloat64("adaptive_mutation_power")
+	c.DeduplicateOffspring = v.GetBool("deduplicate_offspring")
+	c.InheritOrganismTags = v.GetBool("inherit_organism_tags")
 	c.InterspeciesMateRate = v.GetFloat64("interspecies_mate_rate")
+	c.InterspeciesMateSpread = v.GetFloat64("interspecies_mate_spread")
 	c.MateMultipointProb = v.GetFloat64("mate_multipoint_prob")
 	c.MateMultipointAvgProb = v.GetFloat64("mate_multipoint_avg_prob")
 	c.MateSinglepointProb = v.GetFloat64("mate_singlepoint_prob")
 	c.MateOnlyProb = v.GetFloat64("mate_only_prob")
+	c.MateKeepDisabledProb = v.GetFloat64("mate_keep_disabled_prob")
 	c.RecurOnlyProb = v.GetFloat64("recur_only_prob")
+	c.FeedForwardOnly = v.GetBool("feed_forward_only")
 
 	c.PopSize = v.GetInt("pop_size")
 	c.DropOffAge = v.GetInt("dropoff_age")
+	c.PopulationStagnationLimit = v.GetInt("pop_stagnation_limit")
+	c.ChampionRegressionGenerations = v.GetInt("champion_regression_generations")
+	c.ChampionRegressionTolerance = v.GetFloat64("champion_regression_tolerance")
+	c.ChampionRegressionClones = v.GetInt("champion_regression_clones")
+	c.ExtinctionType = v.GetInt("extinction_type")
+	c.ExtinctionRate = v.GetFloat64("extinction_rate")
+	c.ExtinctionPeriod = v.GetInt("extinction_period")
+	c.ExtinctionStagnation = v.GetInt("extinction_stagnation")
+	c.InitialMutationRounds = v.GetInt("initial_mutation_rounds")
+	c.InitialStructuralMutation = v.GetBool("initial_structural_mutation")
 	c.NewLinkTries = v.GetInt("newlink_tries")
 	c.PrintEvery = v.GetInt("print_every")
 	c.BabiesStolen = v.GetInt("babies_stolen")
 	c.NumRuns = v.GetInt("num_runs")
 	c.NumGenerations = v.GetInt("num_generations")
-
-	// read epoch executor type [sequential, parallel]
+	c.MaxEvaluations = v.GetInt("max_evaluations")
+	c.TimeAliveMinimum = v.GetInt("time_alive_minimum")
+	c.ReplacementFraction = v.GetFloat64("replacement_fraction")
+	c.TournamentSize = v.GetInt("tournament_size")
+	c.BoltzmannTemperature = v.GetFloat64("boltzmann_temperature")
+	c.BoltzmannCooling = v.GetFloat64("boltzmann_cooling")
+	c.ConnectionCostProb = v.GetFloat64("connection_cost_prob")
+	c.FitnessEvalRepeats = v.GetInt("fitness_eval_repeats")
+	c.ChampionRevalidations = v.GetInt("champion_revalidations")
+	c.FinalTournamentEvaluations = v.GetInt("final_tournament_evaluations")
+	c.RandomSeed = v.GetInt64("random_seed")
+	c.EvalErrorRetries = v.GetInt("eval_error_retries")
+
+	// read epoch executor type [sequential, parallel, steady_state]
 	ep_exec := v.GetString("epoch_executor")
 	if ep_exec == "sequential" {
 		c.EpochExecutorType = 0 //genetics.SequentialExecutorType
 	} else if ep_exec == "parallel" {
 		c.EpochExecutorType = 1 //genetics.ParallelExecutorType
+	} else if ep_exec == "steady_state" {
+		c.EpochExecutorType = 2 //genetics.SteadyStateExecutorType
 	} else {
 		return errors.New(fmt.Sprintf("Unsupported epoch executor type: %s", ep_exec))
 	}
@@ -221,6 +431,158 @@ func (c *NeatContext) LoadContext(r io.Reader) error {
 		return errors.New(fmt.Sprintf("Unsupported genome compatibility method: %s", gen_compat))
 	}
 
+	// read speciation method [threshold, kmedoids, none]
+	speciation := v.GetString("speciation_type")
+	if speciation == "" || speciation == "threshold" {
+		c.SpeciationType = 0 //genetics.ThresholdSpeciation
+	} else if speciation == "kmedoids" {
+		c.SpeciationType = 1 //genetics.KMedoidsSpeciation
+	} else if speciation == "none" {
+		c.SpeciationType = 2 //genetics.NoSpeciation
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported speciation type: %s", speciation))
+	}
+
+	// read survival selection type [truncation, tournament, roulette, sus]
+	surv_select := v.GetString("survival_selection")
+	if surv_select == "" || surv_select == "truncation" {
+		c.SurvivalSelectionType = 0 //genetics.TruncationSelection
+	} else if surv_select == "tournament" {
+		c.SurvivalSelectionType = 1 //genetics.TournamentSelection
+	} else if surv_select == "roulette" {
+		c.SurvivalSelectionType = 2 //genetics.RouletteSelection
+	} else if surv_select == "sus" {
+		c.SurvivalSelectionType = 3 //genetics.StochasticUniversalSampling
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported survival selection type: %s", surv_select))
+	}
+
+	// read interspecies mate selection type [champion, random, tournament]
+	mate_select := v.GetString("interspecies_mate_selection")
+	if mate_select == "" || mate_select == "champion" {
+		c.InterspeciesMateSelectionType = 0 //genetics.ChampionInterspeciesMate
+	} else if mate_select == "random" {
+		c.InterspeciesMateSelectionType = 1 //genetics.RandomInterspeciesMate
+	} else if mate_select == "tournament" {
+		c.InterspeciesMateSelectionType = 2 //genetics.TournamentInterspeciesMate
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported interspecies mate selection type: %s", mate_select))
+	}
+
+	// read offspring allocation type [skim, fitness, rank, equal]
+	allocation := v.GetString("offspring_allocation")
+	if allocation == "" || allocation == "skim" {
+		c.OffspringAllocationType = 0 //genetics.SkimOffspringAllocation
+	} else if allocation == "fitness" {
+		c.OffspringAllocationType = 1 //genetics.FitnessOffspringAllocation
+	} else if allocation == "rank" {
+		c.OffspringAllocationType = 2 //genetics.RankOffspringAllocation
+	} else if allocation == "equal" {
+		c.OffspringAllocationType = 3 //genetics.EqualOffspringAllocation
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported offspring allocation type: %s", allocation))
+	}
+
+	// read fitness scaling type [none, rank, sigma, boltzmann]
+	fit_scaling := v.GetString("fitness_scaling")
+	if fit_scaling == "" || fit_scaling == "none" {
+		c.FitnessScalingType = 0 //genetics.NoFitnessScaling
+	} else if fit_scaling == "rank" {
+		c.FitnessScalingType = 1 //genetics.RankFitnessScaling
+	} else if fit_scaling == "sigma" {
+		c.FitnessScalingType = 2 //genetics.SigmaFitnessScaling
+	} else if fit_scaling == "boltzmann" {
+		c.FitnessScalingType = 3 //genetics.BoltzmannFitnessScaling
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported fitness scaling type: %s", fit_scaling))
+	}
+
+	// read objective direction [maximize, minimize]
+	direction := v.GetString("objective_direction")
+	if direction == "" || direction == "maximize" {
+		c.ObjectiveDirection = 0 //genetics.MaximizeObjective
+	} else if direction == "minimize" {
+		c.ObjectiveDirection = 1 //genetics.MinimizeObjective
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported objective direction: %s", direction))
+	}
+
+	// read connection cost type [none, link_count, link_length]
+	conn_cost := v.GetString("connection_cost")
+	if conn_cost == "" || conn_cost == "none" {
+		c.ConnectionCostType = 0 //genetics.NoConnectionCost
+	} else if conn_cost == "link_count" {
+		c.ConnectionCostType = 1 //genetics.LinkCountConnectionCost
+	} else if conn_cost == "link_length" {
+		c.ConnectionCostType = 2 //genetics.LinkLengthConnectionCost
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported connection cost type: %s", conn_cost))
+	}
+
+	// read weight bound type [clamp, bounce]
+	w_bound := v.GetString("weight_bound")
+	if w_bound == "" || w_bound == "clamp" {
+		c.WeightBoundType = 0 //genetics.WeightBoundClamp
+	} else if w_bound == "bounce" {
+		c.WeightBoundType = 1 //genetics.WeightBoundBounce
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported weight bound type: %s", w_bound))
+	}
+
+	// read weight perturbation type [uniform, gaussian, cauchy, laplace, adaptive]
+	w_perturb := v.GetString("weight_perturbation")
+	if w_perturb == "" || w_perturb == "uniform" {
+		c.WeightPerturbationType = 0 //genetics.UniformWeightPerturbation
+	} else if w_perturb == "gaussian" {
+		c.WeightPerturbationType = 1 //genetics.GaussianWeightPerturbation
+	} else if w_perturb == "cauchy" {
+		c.WeightPerturbationType = 2 //genetics.CauchyWeightPerturbation
+	} else if w_perturb == "laplace" {
+		c.WeightPerturbationType = 3 //genetics.LaplaceWeightPerturbation
+	} else if w_perturb == "adaptive" {
+		c.WeightPerturbationType = 4 //genetics.AdaptiveWeightPerturbation
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported weight perturbation type: %s", w_perturb))
+	}
+
+	// read fitness aggregation type [mean, median, confidence]
+	fit_aggr := v.GetString("fitness_aggregation")
+	if fit_aggr == "" || fit_aggr == "mean" {
+		c.FitnessAggregationType = 0 //experiments.MeanFitnessAggregation
+	} else if fit_aggr == "median" {
+		c.FitnessAggregationType = 1 //experiments.MedianFitnessAggregation
+	} else if fit_aggr == "confidence" {
+		c.FitnessAggregationType = 2 //experiments.ConfidenceFitnessAggregation
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported fitness aggregation type: %s", fit_aggr))
+	}
+
+	// read evaluation error policy [abort, minimal_fitness, retry]
+	eval_err := v.GetString("eval_error_policy")
+	if eval_err == "" || eval_err == "abort" {
+		c.EvalErrorPolicy = 0 //experiments.AbortOnEvaluationError
+	} else if eval_err == "minimal_fitness" {
+		c.EvalErrorPolicy = 1 //experiments.MinimalFitnessOnEvaluationError
+	} else if eval_err == "retry" {
+		c.EvalErrorPolicy = 2 //experiments.RetryOnEvaluationError
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported evaluation error policy: %s", eval_err))
+	}
+
+	// read policy to handle values which are not finite numbers [ignore, clamp, reject, error]
+	non_finite := v.GetString("non_finite_policy")
+	if non_finite == "" || non_finite == "ignore" {
+		c.NonFinitePolicy = 0 //network.IgnoreNonFinite
+	} else if non_finite == "clamp" {
+		c.NonFinitePolicy = 1 //network.ClampNonFinite
+	} else if non_finite == "reject" {
+		c.NonFinitePolicy = 2 //network.RejectNonFinite
+	} else if non_finite == "error" {
+		c.NonFinitePolicy = 3 //network.ErrorOnNonFinite
+	} else {
+		return errors.New(fmt.Sprintf("Unsupported non-finite values policy: %s", non_finite))
+	}
+
 	// read log level [Debug, Info, Warning, Error]
 	l_level := v.GetString("log_level")
 	switch l_level {
@@ -260,6 +622,23 @@ func (c *NeatContext) LoadContext(r io.Reader) error {
 	return nil
 }
 
+// Returns the random number generator of evolution with this context, i.e. the one provided by WithRand or the
+// generator drawing from the global source of math/rand if not set
+func (c *NeatContext) Rand() *rand.Rand {
+	if c == nil || c.rng == nil {
+		return utils.GlobalRand()
+	}
+	return c.rng
+}
+
+// Returns the copy of this context using provided random number generator of evolution, e.g. to make reproduction of
+// particular species independent of other ones running concurrently
+func (c *NeatContext) WithRand(rng *rand.Rand) *NeatContext {
+	nc := *c
+	nc.rng = rng
+	return &nc
+}
+
 // Returns next random node activation type among registered with this context
 func (c *NeatContext) RandomNodeActivationType() (utils.NodeActivationType, error) {
 	// quick check for the most cases
@@ -267,7 +646,7 @@ func (c *NeatContext) RandomNodeActivationType() (utils.NodeActivationType, erro
 		return c.NodeActivators[0], nil
 	}
 	// find next random
-	index := utils.SingleRouletteThrow(c.NodeActivatorsProb)
+	index := utils.SingleRouletteThrowWith(c.Rand(), c.NodeActivatorsProb)
 	if index < 0 || index >= len(c.NodeActivators){
 		return 0, errors.New(
 			fmt.Sprintf("unexpected error when trying to find random node activator, activator index: %d", index))
@@ -286,78 +665,7 @@ func LoadContext(r io.Reader) *NeatContext {
 		if err == io.EOF {
 			break
 		}
-		switch name {
-		case "trait_param_mut_prob":
-			c.TraitParamMutProb = param
-		case "trait_mutation_power":
-			c.TraitMutationPower = param
-		case "weight_mut_power":
-			c.WeightMutPower = param
-		case "disjoint_coeff":
-			c.DisjointCoeff = param
-		case "excess_coeff":
-			c.ExcessCoeff = param
-		case "mutdiff_coeff":
-			c.MutdiffCoeff = param
-		case "compat_threshold":
-			c.CompatThreshold = param
-		case "age_significance":
-			c.AgeSignificance = param
-		case "survival_thresh":
-			c.SurvivalThresh = param
-		case "mutate_only_prob":
-			c.MutateOnlyProb = param
-		case "mutate_random_trait_prob":
-			c.MutateRandomTraitProb = param
-		case "mutate_link_trait_prob":
-			c.MutateLinkTraitProb = param
-		case "mutate_node_trait_prob":
-			c.MutateNodeTraitProb = param
-		case "mutate_link_weights_prob":
-			c.MutateLinkWeightsProb = param
-		case "mutate_toggle_enable_prob":
-			c.MutateToggleEnableProb = param
-		case "mutate_gene_reenable_prob":
-			c.MutateGeneReenableProb = param
-		case "mutate_add_node_prob":
-			c.MutateAddNodeProb = param
-		case "mutate_add_link_prob":
-			c.MutateAddLinkProb = param
-		case "mutate_connect_sensors":
-			c.MutateConnectSensors = param
-		case "interspecies_mate_rate":
-			c.InterspeciesMateRate = param
-		case "mate_multipoint_prob":
-			c.MateMultipointProb = param
-		case "mate_multipoint_avg_prob":
-			c.MateMultipointAvgProb = param
-		case "mate_singlepoint_prob":
-			c.MateSinglepointProb = param
-		case "mate_only_prob":
-			c.MateOnlyProb = param
-		case "recur_only_prob":
-			c.RecurOnlyProb = param
-		case "pop_size":
-			c.PopSize = int(param)
-		case "dropoff_age":
-			c.DropOffAge = int(param)
-		case "newlink_tries":
-			c.NewLinkTries = int(param)
-		case "print_every":
-			c.PrintEvery = int(param)
-		case "babies_stolen":
-			c.BabiesStolen = int(param)
-		case "num_runs":
-			c.NumRuns = int(param)
-		case "num_generations":
-			c.NumGenerations = int(param)
-		case "epoch_executor":
-			c.EpochExecutorType = int(param)
-		case "genome_compat_method":
-			c.GenCompatMethod = int(param)
-		case "log_level":
-			LogLevel = LoggerLevel(param)
-		default:
+		if err = c.SetParameter(name, param); err != nil {
 			fmt.Printf("WARNING! Unknown configuration parameter found: %s = %f\n", name, param)
 		}
 	}
@@ -367,6 +675,205 @@ func LoadContext(r io.Reader) *NeatContext {
 	return &c
 }
 
+// Sets the value of context parameter with given name as used in plain text configuration file, e.g. "pop_size". The
+// integer and boolean parameters are converted from provided value the same way as in configuration file. Returns
+// error if parameter with given name is unknown.
+func (c *NeatContext) SetParameter(name string, param float64) error {
+	switch name {
+	case "trait_param_mut_prob":
+		c.TraitParamMutProb = param
+	case "trait_mutation_power":
+		c.TraitMutationPower = param
+	case "weight_mut_power":
+		c.WeightMutPower = param
+	case "weight_min":
+		c.WeightMin = param
+	case "weight_max":
+		c.WeightMax = param
+	case "weight_bound":
+		c.WeightBoundType = int(param)
+	case "weight_perturbation":
+		c.WeightPerturbationType = int(param)
+	case "safe_mutation":
+		c.SafeMutation = param != 0
+	case "safe_mutation_samples":
+		c.SafeMutationSamples = int(param)
+	case "disjoint_coeff":
+		c.DisjointCoeff = param
+	case "excess_coeff":
+		c.ExcessCoeff = param
+	case "mutdiff_coeff":
+		c.MutdiffCoeff = param
+	case "compat_threshold":
+		c.CompatThreshold = param
+	case "speciation_type":
+		c.SpeciationType = int(param)
+	case "species_target":
+		c.SpeciesTarget = int(param)
+	case "compat_adjust_step":
+		c.CompatAdjustStep = param
+	case "compat_threshold_min":
+		c.CompatThresholdMin = param
+	case "compat_threshold_max":
+		c.CompatThresholdMax = param
+	case "species_merge_threshold":
+		c.SpeciesMergeThreshold = param
+	case "compat_auto_tune":
+		c.CompatAutoTune = param != 0
+	case "age_significance":
+		c.AgeSignificance = param
+	case "survival_thresh":
+		c.SurvivalThresh = param
+	case "mutate_only_prob":
+		c.MutateOnlyProb = param
+	case "mutate_random_trait_prob":
+		c.MutateRandomTraitProb = param
+	case "mutate_link_trait_prob":
+		c.MutateLinkTraitProb = param
+	case "mutate_node_trait_prob":
+		c.MutateNodeTraitProb = param
+	case "mutate_link_weights_prob":
+		c.MutateLinkWeightsProb = param
+	case "mutate_toggle_enable_prob":
+		c.MutateToggleEnableProb = param
+	case "mutate_gene_reenable_prob":
+		c.MutateGeneReenableProb = param
+	case "mutate_init_activation_prob":
+		c.MutateInitActivationProb = param
+	case "mutate_link_delay_prob":
+		c.MutateLinkDelayProb = param
+	case "max_link_delay":
+		c.MaxLinkDelay = int(param)
+	case "mutate_link_gate_prob":
+		c.MutateLinkGateProb = param
+	case "mutate_memory_node_prob":
+		c.MutateMemoryNodeProb = param
+	case "mutate_memory_retention_prob":
+		c.MutateMemoryRetentionProb = param
+	case "mutate_add_node_prob":
+		c.MutateAddNodeProb = param
+	case "mutate_add_link_prob":
+		c.MutateAddLinkProb = param
+	case "mutate_connect_sensors":
+		c.MutateConnectSensors = param
+	case "mutate_dup_module_prob":
+		c.MutateDupModuleProb = param
+	case "mutate_dup_node_prob":
+		c.MutateDupNodeProb = param
+	case "adaptive_mutation":
+		c.AdaptiveMutation = param != 0
+	case "adaptive_mutation_power":
+		c.AdaptiveMutationPower = param
+	case "deduplicate_offspring":
+		c.DeduplicateOffspring = param != 0
+	case "inherit_organism_tags":
+		c.InheritOrganismTags = param != 0
+	case "interspecies_mate_rate":
+		c.InterspeciesMateRate = param
+	case "interspecies_mate_selection":
+		c.InterspeciesMateSelectionType = int(param)
+	case "interspecies_mate_spread":
+		c.InterspeciesMateSpread = param
+	case "mate_multipoint_prob":
+		c.MateMultipointProb = param
+	case "mate_multipoint_avg_prob":
+		c.MateMultipointAvgProb = param
+	case "mate_singlepoint_prob":
+		c.MateSinglepointProb = param
+	case "mate_only_prob":
+		c.MateOnlyProb = param
+	case "mate_keep_disabled_prob":
+		c.MateKeepDisabledProb = param
+	case "recur_only_prob":
+		c.RecurOnlyProb = param
+	case "feed_forward_only":
+		c.FeedForwardOnly = param != 0
+	case "pop_size":
+		c.PopSize = int(param)
+	case "dropoff_age":
+		c.DropOffAge = int(param)
+	case "pop_stagnation_limit":
+		c.PopulationStagnationLimit = int(param)
+	case "champion_regression_generations":
+		c.ChampionRegressionGenerations = int(param)
+	case "champion_regression_tolerance":
+		c.ChampionRegressionTolerance = param
+	case "champion_regression_clones":
+		c.ChampionRegressionClones = int(param)
+	case "extinction_type":
+		c.ExtinctionType = int(param)
+	case "extinction_rate":
+		c.ExtinctionRate = param
+	case "extinction_period":
+		c.ExtinctionPeriod = int(param)
+	case "extinction_stagnation":
+		c.ExtinctionStagnation = int(param)
+	case "initial_mutation_rounds":
+		c.InitialMutationRounds = int(param)
+	case "initial_structural_mutation":
+		c.InitialStructuralMutation = param != 0
+	case "newlink_tries":
+		c.NewLinkTries = int(param)
+	case "print_every":
+		c.PrintEvery = int(param)
+	case "babies_stolen":
+		c.BabiesStolen = int(param)
+	case "num_runs":
+		c.NumRuns = int(param)
+	case "num_generations":
+		c.NumGenerations = int(param)
+	case "max_evaluations":
+		c.MaxEvaluations = int(param)
+	case "time_alive_minimum":
+		c.TimeAliveMinimum = int(param)
+	case "epoch_executor":
+		c.EpochExecutorType = int(param)
+	case "replacement_fraction":
+		c.ReplacementFraction = param
+	case "genome_compat_method":
+		c.GenCompatMethod = int(param)
+	case "survival_selection":
+		c.SurvivalSelectionType = int(param)
+	case "tournament_size":
+		c.TournamentSize = int(param)
+	case "offspring_allocation":
+		c.OffspringAllocationType = int(param)
+	case "fitness_scaling":
+		c.FitnessScalingType = int(param)
+	case "objective_direction":
+		c.ObjectiveDirection = int(param)
+	case "boltzmann_temperature":
+		c.BoltzmannTemperature = param
+	case "boltzmann_cooling":
+		c.BoltzmannCooling = param
+	case "connection_cost":
+		c.ConnectionCostType = int(param)
+	case "connection_cost_prob":
+		c.ConnectionCostProb = param
+	case "fitness_eval_repeats":
+		c.FitnessEvalRepeats = int(param)
+	case "fitness_aggregation":
+		c.FitnessAggregationType = int(param)
+	case "champion_revalidations":
+		c.ChampionRevalidations = int(param)
+	case "final_tournament_evaluations":
+		c.FinalTournamentEvaluations = int(param)
+	case "random_seed":
+		c.RandomSeed = int64(param)
+	case "eval_error_policy":
+		c.EvalErrorPolicy = int(param)
+	case "eval_error_retries":
+		c.EvalErrorRetries = int(param)
+	case "non_finite_policy":
+		c.NonFinitePolicy = int(param)
+	case "log_level":
+		LogLevel = LoggerLevel(param)
+	default:
+		return errors.New(fmt.Sprintf("Unknown configuration parameter: %s", name))
+	}
+	return nil
+}
+
 // set default values for activator type and its probability of selection
 func (c *NeatContext) initDefaultNodeActivators() {
 	c.NodeActivators = []utils.NodeActivationType{utils.SigmoidSteepenedActivation}
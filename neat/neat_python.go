@@ -0,0 +1,204 @@
+package neat
+
+import (
+	"io"
+	"bufio"
+	"strings"
+	"strconv"
+	"errors"
+	"fmt"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Loads context configuration from provided reader with NEAT-Python configuration file in INI format. Only parameters
+// which have counterparts in this context are loaded, all other parameters of this context are left untouched.
+// Returns number of inputs and outputs of genome as defined by NEAT-Python configuration.
+func (c *NeatContext) LoadNeatPythonConfig(r io.Reader) (num_inputs, num_outputs int, err error) {
+	sections, err := readIniSections(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	params := []struct {
+		section, name string
+		set           func(value string) error
+	}{
+		{"NEAT", "pop_size", intSetter(&c.PopSize)},
+		{"DefaultGenome", "num_inputs", intSetter(&num_inputs)},
+		{"DefaultGenome", "num_outputs", intSetter(&num_outputs)},
+		{"DefaultGenome", "feed_forward", boolSetter(&c.FeedForwardOnly)},
+		// NEAT-Python doesn't distinguish between disjoint and excess genes
+		{"DefaultGenome", "compatibility_disjoint_coefficient", floatSetter(&c.DisjointCoeff, &c.ExcessCoeff)},
+		{"DefaultGenome", "compatibility_weight_coefficient", floatSetter(&c.MutdiffCoeff)},
+		{"DefaultGenome", "conn_add_prob", floatSetter(&c.MutateAddLinkProb)},
+		{"DefaultGenome", "node_add_prob", floatSetter(&c.MutateAddNodeProb)},
+		{"DefaultGenome", "weight_mutate_rate", floatSetter(&c.MutateLinkWeightsProb)},
+		{"DefaultGenome", "weight_mutate_power", floatSetter(&c.WeightMutPower)},
+		{"DefaultGenome", "enabled_mutate_rate", floatSetter(&c.MutateToggleEnableProb)},
+		{"DefaultGenome", "activation_options", c.neatPythonActivatorsSetter()},
+		{"DefaultSpeciesSet", "compatibility_threshold", floatSetter(&c.CompatThreshold)},
+		{"DefaultStagnation", "max_stagnation", intSetter(&c.DropOffAge)},
+		{"DefaultReproduction", "survival_threshold", floatSetter(&c.SurvivalThresh)},
+	}
+	for _, p := range params {
+		if value, ok := sections[p.section][p.name]; ok {
+			if err = p.set(value); err != nil {
+				return 0, 0, errors.New(
+					fmt.Sprintf("Failed to read NEAT-Python parameter [%s] %s = %s, reason: %s",
+						p.section, p.name, value, err))
+			}
+		}
+	}
+	return num_inputs, num_outputs, nil
+}
+
+// Writes this context as NEAT-Python configuration file in INI format for genome with given number of inputs and
+// outputs. The parameters of NEAT-Python which have no counterparts in this context are set to the NEAT-Python
+// recommended values.
+func (c *NeatContext) WriteNeatPythonConfig(w io.Writer, num_inputs, num_outputs int, fitness_threshold float64) error {
+	activations := make([]string, 0)
+	for _, a_type := range c.NodeActivators {
+		if name, err := utils.ActivationTypeToNeatPython(a_type); err == nil {
+			activations = append(activations, name)
+		} else {
+			WarnLog(fmt.Sprintf("Skipping node activator for NEAT-Python config, reason: %s", err))
+		}
+	}
+	if len(activations) == 0 {
+		activations = append(activations, "sigmoid")
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "[NEAT]\nfitness_criterion = max\nfitness_threshold = %g\npop_size = %d\nreset_on_extinction = False\n\n",
+		fitness_threshold, c.PopSize)
+
+	fmt.Fprintf(b, "[DefaultGenome]\nnum_inputs = %d\nnum_outputs = %d\nnum_hidden = 0\nfeed_forward = %s\n" +
+		"initial_connection = full\n", num_inputs, num_outputs, pythonBool(c.FeedForwardOnly))
+	fmt.Fprintf(b, "compatibility_disjoint_coefficient = %g\ncompatibility_weight_coefficient = %g\n",
+		c.DisjointCoeff, c.MutdiffCoeff)
+	fmt.Fprintf(b, "conn_add_prob = %g\nconn_delete_prob = 0.0\nnode_add_prob = %g\nnode_delete_prob = 0.0\n",
+		c.MutateAddLinkProb, c.MutateAddNodeProb)
+	fmt.Fprintf(b, "activation_default = %s\nactivation_options = %s\nactivation_mutate_rate = 0.0\n",
+		activations[0], strings.Join(activations, " "))
+	fmt.Fprint(b, "aggregation_default = sum\naggregation_options = sum\naggregation_mutate_rate = 0.0\n")
+	fmt.Fprint(b, "bias_init_mean = 0.0\nbias_init_stdev = 1.0\nbias_max_value = 30.0\nbias_min_value = -30.0\n")
+	fmt.Fprintf(b, "bias_mutate_power = %g\nbias_mutate_rate = %g\nbias_replace_rate = 0.1\n",
+		c.WeightMutPower, c.MutateLinkWeightsProb)
+	fmt.Fprint(b, "response_init_mean = 1.0\nresponse_init_stdev = 0.0\nresponse_max_value = 30.0\n" +
+		"response_min_value = -30.0\nresponse_mutate_power = 0.0\nresponse_mutate_rate = 0.0\nresponse_replace_rate = 0.0\n")
+	fmt.Fprintf(b, "weight_init_mean = 0.0\nweight_init_stdev = 1.0\nweight_max_value = 30\nweight_min_value = -30\n" +
+		"weight_mutate_power = %g\nweight_mutate_rate = %g\nweight_replace_rate = 0.1\n",
+		c.WeightMutPower, c.MutateLinkWeightsProb)
+	fmt.Fprintf(b, "enabled_default = True\nenabled_mutate_rate = %g\n\n", c.MutateToggleEnableProb)
+
+	fmt.Fprintf(b, "[DefaultSpeciesSet]\ncompatibility_threshold = %g\n\n", c.CompatThreshold)
+	fmt.Fprintf(b, "[DefaultStagnation]\nspecies_fitness_func = max\nmax_stagnation = %d\nspecies_elitism = 2\n\n",
+		c.DropOffAge)
+	fmt.Fprintf(b, "[DefaultReproduction]\nelitism = 2\nsurvival_threshold = %g\n", c.SurvivalThresh)
+
+	return b.Flush()
+}
+
+// Returns setter to parse NEAT-Python activation options into node activators of this context with equal probabilities
+func (c *NeatContext) neatPythonActivatorsSetter() func(string) error {
+	return func(value string) error {
+		names := strings.Fields(value)
+		if len(names) == 0 {
+			return errors.New("empty activation options")
+		}
+		activators := make([]utils.NodeActivationType, len(names))
+		probs := make([]float64, len(names))
+		for i, name := range names {
+			a_type, err := utils.ActivationTypeFromNeatPython(name)
+			if err != nil {
+				return err
+			}
+			activators[i] = a_type
+			probs[i] = 1.0 / float64(len(names))
+		}
+		c.NodeActivators, c.NodeActivatorsProb = activators, probs
+		return nil
+	}
+}
+
+// Reads INI formatted data into map of sections with parameters. The parameter name is separated from value by the
+// first '=' or ':' as in Python configparser. The continuation lines are joined with preceding parameter value.
+func readIniSections(r io.Reader) (map[string]map[string]string, error) {
+	sections := make(map[string]map[string]string)
+	var section map[string]string
+	last_name := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = make(map[string]string)
+			sections[strings.TrimSpace(line[1:len(line) - 1])] = section
+			last_name = ""
+			continue
+		}
+		if section == nil {
+			return nil, errors.New(fmt.Sprintf("Parameter outside of section: %s", line))
+		}
+		if (raw[0] == ' ' || raw[0] == '\t') && last_name != "" {
+			// continuation of multi-line value
+			section[last_name] += " " + line
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return nil, errors.New(fmt.Sprintf("Malformed line: %s", line))
+		}
+		last_name = strings.TrimSpace(line[:sep])
+		section[last_name] = strings.TrimSpace(line[sep + 1:])
+	}
+	return sections, scanner.Err()
+}
+
+func intSetter(fields ...*int) func(string) error {
+	return func(value string) error {
+		v, err := strconv.Atoi(value)
+		if err == nil {
+			for _, f := range fields {
+				*f = v
+			}
+		}
+		return err
+	}
+}
+
+func floatSetter(fields ...*float64) func(string) error {
+	return func(value string) error {
+		v, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			for _, f := range fields {
+				*f = v
+			}
+		}
+		return err
+	}
+}
+
+// Returns setter to parse boolean value the same way as Python configparser does
+func boolSetter(field *bool) func(string) error {
+	return func(value string) error {
+		switch strings.ToLower(value) {
+		case "1", "yes", "true", "on":
+			*field = true
+		case "0", "no", "false", "off":
+			*field = false
+		default:
+			return errors.New(fmt.Sprintf("not a boolean: %s", value))
+		}
+		return nil
+	}
+}
+
+func pythonBool(b bool) string {
+	if b {
+		return "True"
+	}
+	return "False"
+}
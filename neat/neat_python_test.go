@@ -0,0 +1,149 @@
+package neat
+
+import (
+	"testing"
+	"strings"
+	"bytes"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+const neatPythonConfigStr = `# NEAT-Python configuration
+[NEAT]
+fitness_criterion     = max
+fitness_threshold     = 3.9
+pop_size              = 150
+reset_on_extinction   = False
+
+[DefaultGenome]
+# node activation options
+activation_default      = sigmoid
+activation_options      = sigmoid
+                          tanh
+num_inputs              = 2
+num_outputs             = 1
+feed_forward            = True
+compatibility_disjoint_coefficient = 1.0
+compatibility_weight_coefficient   = 0.5
+conn_add_prob           = 0.5
+node_add_prob           = 0.2
+weight_mutate_rate      = 0.8
+weight_mutate_power     = 0.5
+enabled_mutate_rate     = 0.01
+
+[DefaultSpeciesSet]
+compatibility_threshold = 3.0
+
+[DefaultStagnation]
+max_stagnation       = 20
+
+[DefaultReproduction]
+survival_threshold = 0.2
+`
+
+func TestNeatContext_LoadNeatPythonConfig(t *testing.T) {
+	nc := NewNeatContext()
+	inputs, outputs, err := nc.LoadNeatPythonConfig(strings.NewReader(neatPythonConfigStr))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	checkNeatPythonContext(nc, inputs, outputs, t)
+}
+
+func TestNeatContext_LoadNeatPythonConfig_colonSeparator(t *testing.T) {
+	nc := NewNeatContext()
+	config := strings.Replace(neatPythonConfigStr, "=", ":", -1)
+	inputs, outputs, err := nc.LoadNeatPythonConfig(strings.NewReader(config))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	checkNeatPythonContext(nc, inputs, outputs, t)
+}
+
+func TestNeatContext_WriteNeatPythonConfig(t *testing.T) {
+	nc := NewNeatContext()
+	if _, _, err := nc.LoadNeatPythonConfig(strings.NewReader(neatPythonConfigStr)); err != nil {
+		t.Error(err)
+		return
+	}
+	out_buf := bytes.NewBufferString("")
+	if err := nc.WriteNeatPythonConfig(out_buf, 2, 1, 3.9); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// read it back
+	rnc := NewNeatContext()
+	inputs, outputs, err := rnc.LoadNeatPythonConfig(out_buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	checkNeatPythonContext(rnc, inputs, outputs, t)
+}
+
+func TestNeatContext_LoadNeatPythonConfig_error(t *testing.T) {
+	nc := NewNeatContext()
+	config := "[DefaultGenome]\nactivation_options = sigmoid relu\n"
+	if _, _, err := nc.LoadNeatPythonConfig(strings.NewReader(config)); err == nil {
+		t.Error("Error expected for unsupported activation")
+	}
+	config = "[DefaultGenome]\nfeed_forward = maybe\n"
+	if _, _, err := nc.LoadNeatPythonConfig(strings.NewReader(config)); err == nil {
+		t.Error("Error expected for malformed boolean")
+	}
+	config = "pop_size = 10\n"
+	if _, _, err := nc.LoadNeatPythonConfig(strings.NewReader(config)); err == nil {
+		t.Error("Error expected for parameter outside of section")
+	}
+}
+
+func checkNeatPythonContext(nc *NeatContext, inputs, outputs int, t *testing.T) {
+	if inputs != 2 || outputs != 1 {
+		t.Error("Wrong genome layout", inputs, outputs)
+	}
+	if !nc.FeedForwardOnly {
+		t.Error("FeedForwardOnly", nc.FeedForwardOnly)
+	}
+	if nc.PopSize != 150 {
+		t.Error("PopSize", nc.PopSize)
+	}
+	if nc.DisjointCoeff != 1.0 || nc.ExcessCoeff != 1.0 {
+		t.Error("DisjointCoeff/ExcessCoeff", nc.DisjointCoeff, nc.ExcessCoeff)
+	}
+	if nc.MutdiffCoeff != 0.5 {
+		t.Error("MutdiffCoeff", nc.MutdiffCoeff)
+	}
+	if nc.MutateAddLinkProb != 0.5 {
+		t.Error("MutateAddLinkProb", nc.MutateAddLinkProb)
+	}
+	if nc.MutateAddNodeProb != 0.2 {
+		t.Error("MutateAddNodeProb", nc.MutateAddNodeProb)
+	}
+	if nc.MutateLinkWeightsProb != 0.8 {
+		t.Error("MutateLinkWeightsProb", nc.MutateLinkWeightsProb)
+	}
+	if nc.WeightMutPower != 0.5 {
+		t.Error("WeightMutPower", nc.WeightMutPower)
+	}
+	if nc.MutateToggleEnableProb != 0.01 {
+		t.Error("MutateToggleEnableProb", nc.MutateToggleEnableProb)
+	}
+	if nc.CompatThreshold != 3.0 {
+		t.Error("CompatThreshold", nc.CompatThreshold)
+	}
+	if nc.DropOffAge != 20 {
+		t.Error("DropOffAge", nc.DropOffAge)
+	}
+	if nc.SurvivalThresh != 0.2 {
+		t.Error("SurvivalThresh", nc.SurvivalThresh)
+	}
+	if len(nc.NodeActivators) != 2 || nc.NodeActivators[0] != utils.SigmoidSteepenedActivation ||
+		nc.NodeActivators[1] != utils.TanhActivation {
+		t.Error("NodeActivators", nc.NodeActivators)
+	}
+	if len(nc.NodeActivatorsProb) != 2 || nc.NodeActivatorsProb[0] != 0.5 {
+		t.Error("NodeActivatorsProb", nc.NodeActivatorsProb)
+	}
+}
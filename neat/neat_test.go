@@ -18,6 +18,22 @@ func TestLoadContext(t *testing.T) {
 	checkNeatContext(nc, t)
 }
 
+func TestNeatContext_SetParameter(t *testing.T) {
+	nc := NewNeatContext()
+	if err := nc.SetParameter("compat_threshold", 2.5); err != nil || nc.CompatThreshold != 2.5 {
+		t.Error("Wrong CompatThreshold", nc.CompatThreshold, err)
+	}
+	if err := nc.SetParameter("pop_size", 150.0); err != nil || nc.PopSize != 150 {
+		t.Error("Wrong PopSize", nc.PopSize, err)
+	}
+	if err := nc.SetParameter("safe_mutation", 1.0); err != nil || !nc.SafeMutation {
+		t.Error("Wrong SafeMutation", nc.SafeMutation, err)
+	}
+	if err := nc.SetParameter("unknown_param", 1.0); err == nil {
+		t.Error("Error expected for unknown parameter")
+	}
+}
+
 func TestNeatContext_LoadContext(t *testing.T) {
 	config, err := os.Open("../data/xor_test.neat.yml")
 	if err != nil {
@@ -61,6 +77,24 @@ func checkNeatContext(nc *NeatContext, t *testing.T) {
 	if nc.WeightMutPower != 2.5 {
 		t.Error("nc.WeightMutPower != 2.5", nc.WeightMutPower != 2.5)
 	}
+	if nc.WeightMin != -8.0 {
+		t.Error("WeightMin", nc.WeightMin)
+	}
+	if nc.WeightMax != 8.0 {
+		t.Error("WeightMax", nc.WeightMax)
+	}
+	if nc.WeightBoundType != 1 {
+		t.Error("WeightBoundType", nc.WeightBoundType)
+	}
+	if nc.WeightPerturbationType != 2 {
+		t.Error("WeightPerturbationType", nc.WeightPerturbationType)
+	}
+	if !nc.SafeMutation {
+		t.Error("SafeMutation", nc.SafeMutation)
+	}
+	if nc.SafeMutationSamples != 5 {
+		t.Error("SafeMutationSamples", nc.SafeMutationSamples)
+	}
 	if nc.DisjointCoeff != 1.0 {
 		t.Error("nc.DisjointCoeff != 1.0", nc.DisjointCoeff)
 	}
@@ -79,6 +113,33 @@ func checkNeatContext(nc *NeatContext, t *testing.T) {
 	if nc.SurvivalThresh != 0.2 {
 		t.Error("SurvivalThresh", nc.SurvivalThresh)
 	}
+	if nc.SurvivalSelectionType != 1 {
+		t.Error("SurvivalSelectionType", nc.SurvivalSelectionType)
+	}
+	if nc.TournamentSize != 3 {
+		t.Error("TournamentSize", nc.TournamentSize)
+	}
+	if nc.OffspringAllocationType != 2 {
+		t.Error("OffspringAllocationType", nc.OffspringAllocationType)
+	}
+	if nc.FitnessScalingType != 3 {
+		t.Error("FitnessScalingType", nc.FitnessScalingType)
+	}
+	if nc.BoltzmannTemperature != 2.0 {
+		t.Error("BoltzmannTemperature", nc.BoltzmannTemperature)
+	}
+	if nc.BoltzmannCooling != 0.95 {
+		t.Error("BoltzmannCooling", nc.BoltzmannCooling)
+	}
+	if nc.ObjectiveDirection != 1 {
+		t.Error("ObjectiveDirection", nc.ObjectiveDirection)
+	}
+	if nc.ConnectionCostType != 2 {
+		t.Error("ConnectionCostType", nc.ConnectionCostType)
+	}
+	if nc.ConnectionCostProb != 0.25 {
+		t.Error("ConnectionCostProb", nc.ConnectionCostProb)
+	}
 	if nc.MutateOnlyProb != 0.25 {
 		t.Error("MutateOnlyProb", nc.MutateOnlyProb)
 	}
@@ -100,6 +161,24 @@ func checkNeatContext(nc *NeatContext, t *testing.T) {
 	if nc.MutateGeneReenableProb != 0.0 {
 		t.Error("MutateGeneReenableProb", nc.MutateGeneReenableProb)
 	}
+	if nc.MutateInitActivationProb != 0.1 {
+		t.Error("MutateInitActivationProb", nc.MutateInitActivationProb)
+	}
+	if nc.MutateLinkDelayProb != 0.05 {
+		t.Error("MutateLinkDelayProb", nc.MutateLinkDelayProb)
+	}
+	if nc.MaxLinkDelay != 3 {
+		t.Error("MaxLinkDelay", nc.MaxLinkDelay)
+	}
+	if nc.MutateLinkGateProb != 0.02 {
+		t.Error("MutateLinkGateProb", nc.MutateLinkGateProb)
+	}
+	if nc.MutateMemoryNodeProb != 0.01 {
+		t.Error("MutateMemoryNodeProb", nc.MutateMemoryNodeProb)
+	}
+	if nc.MutateMemoryRetentionProb != 0.1 {
+		t.Error("MutateMemoryRetentionProb", nc.MutateMemoryRetentionProb)
+	}
 	if nc.MutateAddNodeProb != 0.03 {
 		t.Error("MutateAddNodeProb", nc.MutateAddNodeProb)
 	}
@@ -109,9 +188,33 @@ func checkNeatContext(nc *NeatContext, t *testing.T) {
 	if nc.MutateConnectSensors != 0.5 {
 		t.Error("MutateConnectSensors", nc.MutateConnectSensors)
 	}
+	if nc.MutateDupModuleProb != 0.01 {
+		t.Error("MutateDupModuleProb", nc.MutateDupModuleProb)
+	}
+	if nc.MutateDupNodeProb != 0.02 {
+		t.Error("MutateDupNodeProb", nc.MutateDupNodeProb)
+	}
+	if !nc.AdaptiveMutation {
+		t.Error("AdaptiveMutation", nc.AdaptiveMutation)
+	}
+	if nc.AdaptiveMutationPower != 0.2 {
+		t.Error("AdaptiveMutationPower", nc.AdaptiveMutationPower)
+	}
+	if !nc.DeduplicateOffspring {
+		t.Error("DeduplicateOffspring", nc.DeduplicateOffspring)
+	}
+	if !nc.InheritOrganismTags {
+		t.Error("InheritOrganismTags", nc.InheritOrganismTags)
+	}
 	if nc.InterspeciesMateRate != 0.001 {
 		t.Error("InterspeciesMateRate", nc.InterspeciesMateRate)
 	}
+	if nc.InterspeciesMateSelectionType != 2 {
+		t.Error("InterspeciesMateSelectionType", nc.InterspeciesMateSelectionType)
+	}
+	if nc.InterspeciesMateSpread != 0.3 {
+		t.Error("InterspeciesMateSpread", nc.InterspeciesMateSpread)
+	}
 	if nc.MateMultipointProb != 0.3 {
 		t.Error("MateMultipointProb", nc.MateMultipointProb)
 	}
@@ -124,15 +227,51 @@ func checkNeatContext(nc *NeatContext, t *testing.T) {
 	if nc.MateOnlyProb != 0.2 {
 		t.Error("MateOnlyProb", nc.MateOnlyProb)
 	}
+	if nc.MateKeepDisabledProb != 0.75 {
+		t.Error("MateKeepDisabledProb", nc.MateKeepDisabledProb)
+	}
 	if nc.RecurOnlyProb != 0.0 {
 		t.Error("RecurOnlyProb", nc.RecurOnlyProb)
 	}
+	if !nc.FeedForwardOnly {
+		t.Error("FeedForwardOnly", nc.FeedForwardOnly)
+	}
 	if nc.PopSize != 200 {
 		t.Error("PopSize", nc.PopSize)
 	}
 	if nc.DropOffAge != 50 {
 		t.Error("DropOffAge", nc.DropOffAge)
 	}
+	if nc.PopulationStagnationLimit != 60 {
+		t.Error("PopulationStagnationLimit", nc.PopulationStagnationLimit)
+	}
+	if nc.ChampionRegressionGenerations != 3 {
+		t.Error("ChampionRegressionGenerations", nc.ChampionRegressionGenerations)
+	}
+	if nc.ChampionRegressionTolerance != 0.05 {
+		t.Error("ChampionRegressionTolerance", nc.ChampionRegressionTolerance)
+	}
+	if nc.ChampionRegressionClones != 2 {
+		t.Error("ChampionRegressionClones", nc.ChampionRegressionClones)
+	}
+	if nc.ExtinctionType != 1 {
+		t.Error("ExtinctionType", nc.ExtinctionType)
+	}
+	if nc.ExtinctionRate != 0.3 {
+		t.Error("ExtinctionRate", nc.ExtinctionRate)
+	}
+	if nc.ExtinctionPeriod != 50 {
+		t.Error("ExtinctionPeriod", nc.ExtinctionPeriod)
+	}
+	if nc.ExtinctionStagnation != 20 {
+		t.Error("ExtinctionStagnation", nc.ExtinctionStagnation)
+	}
+	if nc.InitialMutationRounds != 3 {
+		t.Error("InitialMutationRounds", nc.InitialMutationRounds)
+	}
+	if !nc.InitialStructuralMutation {
+		t.Error("InitialStructuralMutation", nc.InitialStructuralMutation)
+	}
 	if nc.NewLinkTries != 50 {
 		t.Error("NewLinkTries", nc.NewLinkTries)
 	}
@@ -148,10 +287,64 @@ func checkNeatContext(nc *NeatContext, t *testing.T) {
 	if nc.NumGenerations != 100 {
 		t.Error("NumGenerations", nc.NumGenerations)
 	}
+	if nc.MaxEvaluations != 1000000 {
+		t.Error("MaxEvaluations", nc.MaxEvaluations)
+	}
 	if nc.EpochExecutorType != 0 {
 		t.Error("EpochExecutorType", nc.EpochExecutorType)
 	}
+	if nc.ReplacementFraction != 0.25 {
+		t.Error("ReplacementFraction", nc.ReplacementFraction)
+	}
 	if nc.GenCompatMethod != 1 {
 		t.Error("GenCompatMethod", nc.GenCompatMethod)
 	}
+	if nc.TimeAliveMinimum != 20 {
+		t.Error("TimeAliveMinimum", nc.TimeAliveMinimum)
+	}
+	if nc.FitnessEvalRepeats != 3 {
+		t.Error("FitnessEvalRepeats", nc.FitnessEvalRepeats)
+	}
+	if nc.FitnessAggregationType != 1 {
+		t.Error("FitnessAggregationType", nc.FitnessAggregationType)
+	}
+	if nc.ChampionRevalidations != 2 {
+		t.Error("ChampionRevalidations", nc.ChampionRevalidations)
+	}
+	if nc.FinalTournamentEvaluations != 5 {
+		t.Error("FinalTournamentEvaluations", nc.FinalTournamentEvaluations)
+	}
+	if nc.RandomSeed != 42 {
+		t.Error("RandomSeed", nc.RandomSeed)
+	}
+	if nc.EvalErrorPolicy != 2 {
+		t.Error("EvalErrorPolicy", nc.EvalErrorPolicy)
+	}
+	if nc.EvalErrorRetries != 3 {
+		t.Error("EvalErrorRetries", nc.EvalErrorRetries)
+	}
+	if nc.NonFinitePolicy != 2 {
+		t.Error("NonFinitePolicy", nc.NonFinitePolicy)
+	}
+	if nc.SpeciationType != 1 {
+		t.Error("SpeciationType", nc.SpeciationType)
+	}
+	if nc.SpeciesTarget != 5 {
+		t.Error("SpeciesTarget", nc.SpeciesTarget)
+	}
+	if nc.CompatAdjustStep != 0.3 {
+		t.Error("CompatAdjustStep", nc.CompatAdjustStep)
+	}
+	if nc.CompatThresholdMin != 0.6 {
+		t.Error("CompatThresholdMin", nc.CompatThresholdMin)
+	}
+	if nc.CompatThresholdMax != 6.0 {
+		t.Error("CompatThresholdMax", nc.CompatThresholdMax)
+	}
+	if nc.SpeciesMergeThreshold != 0.5 {
+		t.Error("SpeciesMergeThreshold", nc.SpeciesMergeThreshold)
+	}
+	if !nc.CompatAutoTune {
+		t.Error("CompatAutoTune", nc.CompatAutoTune)
+	}
 }
\ No newline at end of file
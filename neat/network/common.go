@@ -5,6 +5,7 @@ import (
 	"math"
 	"fmt"
 	"errors"
+	"sync/atomic"
 	"github.com/yaricom/goNEAT/neat/utils"
 )
 
@@ -15,8 +16,25 @@ var (
 	NetErrUnsupportedSensorsArraySize = errors.New("the sensors array size is unsupported by network solver")
 	// The error to be raised when depth calculation failed due to the loop in network
 	NetErrDepthCalculationFailedLoopDetected = errors.New("depth can not be determined for network with loop")
+	// The error to be raised when network activation produced value which is not finite number
+	NetErrNonFiniteActivation = errors.New("network activation produced value which is not finite number")
 )
 
+// The total number of network activation steps done by all networks and network solvers
+var activationsTotal int64
+
+// Returns the total number of network activation steps done by all networks and network solvers since program start.
+// It is safe to call it concurrently with networks activation, thus it can be used to account computational budget
+// of evolutionary run as the difference of values returned before and after run.
+func ActivationsTotal() int64 {
+	return atomic.LoadInt64(&activationsTotal)
+}
+
+// Records one network activation step
+func countActivation() {
+	atomic.AddInt64(&activationsTotal, 1)
+}
+
 // Defines network solver interface which describes neural network structures with methods to run activation waves through
 // them.
 type NetworkSolver interface {
@@ -57,6 +75,8 @@ const (
 	NeuronNode NodeType = iota
 	// The sensor type
 	SensorNode
+	// The memory cell type
+	MemoryNode
 )
 
 // Returns human readable NNode type name for given constant value
@@ -66,6 +86,8 @@ func NodeTypeName(ntype NodeType) string {
 		return "NEURON"
 	case SensorNode:
 		return "SENSOR"
+	case MemoryNode:
+		return "MEMORY"
 	default:
 		return "!!! UNKNOWN NODE TYPE !!!"
 	}
@@ -118,10 +140,15 @@ func NeuronTypeByName(name string) (NodeNeuronType, error) {
 	}
 }
 
-// Method to calculate activation for specified neuron node based on it's ActivationType field value.
-// Will return error and set -0.0 activation if unsupported activation type requested.
+// Method to calculate activation for specified neuron node based on it's ActivationType field value. The memory cell
+// node is activated by its accumulated state. Will return error and set -0.0 activation if unsupported activation type
+// requested.
 func ActivateNode(node *NNode, a *utils.NodeActivatorsFactory) (err error) {
-	out, err := a.ActivateByType(node.ActivationSum, node.Params, node.ActivationType)
+	sum := node.ActivationSum
+	if node.IsMemory {
+		sum = node.accumulate(sum)
+	}
+	out, err := a.ActivateByType(sum, node.Params, node.ActivationType)
 	if err == nil {
 		node.setActivation(out)
 	}
@@ -133,7 +160,10 @@ func ActivateNode(node *NNode, a *utils.NodeActivatorsFactory) (err error) {
 // input nodes will be processed by corresponding activation function and corresponding activation values of output nodes
 // will be set. Will panic if unsupported activation type requested.
 func ActivateModule(module *NNode, a *utils.NodeActivatorsFactory) error {
-	inputs := make([]float64, len(module.Incoming))
+	if cap(module.moduleInputs) < len(module.Incoming) {
+		module.moduleInputs = make([]float64, len(module.Incoming))
+	}
+	inputs := module.moduleInputs[:len(module.Incoming)]
 	for i, v := range module.Incoming {
 		inputs[i] = v.InNode.GetActiveOut()
 	}
@@ -0,0 +1,230 @@
+package network
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"errors"
+	"runtime"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The name of default executor which runs networks on CPU
+const CPUExecutorName = "cpu"
+
+// The compiled representation of network as flat arrays, which is the contract between networks and executor
+// backends. The neurons are indexed in order: bias, input, output and hidden, and network is activated with the same
+// semantics as FastModularNetworkSolver, i.e. at each forward step the signals of all connections are summed in target
+// neurons together with biases and passed through activation functions of neurons, and then through modules. The
+// backend offloading computations to accelerator (CUDA, OpenCL, WebGPU, etc.) should upload these arrays once and
+// reuse them for all input rows.
+type CompiledNetwork struct {
+	// A network id
+	Id                  int
+	// Is a name of this network
+	Name                string
+	// The policy to handle activation values which are not finite numbers
+	NonFinitePolicy     NonFinitePolicy
+
+	// The number of bias, input, output neurons and the total number of neurons
+	BiasNeuronCount     int
+	InputNeuronCount    int
+	OutputNeuronCount   int
+	TotalNeuronCount    int
+
+	// The activation functions per neuron
+	ActivationFunctions []utils.NodeActivationType
+	// The bias values per neuron
+	Biases              []float64
+	// The initial activation values per neuron set before activation with each input row, nil if all neurons start
+	// from zero
+	InitialSignals      []float64
+	// The connections stored as parallel arrays of source indexes, target indexes and weights
+	SourceIndxs         []int
+	TargetIndxs         []int
+	Weights             []float64
+	// The control nodes relaying between network modules
+	Modules             []*FastControlNode
+}
+
+// Compiles this network into flat representation to be applied by executor. Returns error if network can not be
+// represented by fast network solver.
+func (n *Network) Compile() (*CompiledNetwork, error) {
+	solver, err := n.FastNetworkSolver()
+	if err != nil {
+		return nil, err
+	}
+	fmm := solver.(*FastModularNetworkSolver)
+	compiled := CompiledNetwork{
+		Id:n.Id,
+		Name:n.Name,
+		NonFinitePolicy:fmm.NonFinitePolicy,
+		BiasNeuronCount:fmm.biasNeuronCount,
+		InputNeuronCount:fmm.inputNeuronCount,
+		OutputNeuronCount:fmm.outputNeuronCount,
+		TotalNeuronCount:fmm.totalNeuronCount,
+		ActivationFunctions:fmm.activationFunctions,
+		Biases:fmm.biasList,
+		InitialSignals:fmm.initialSignals,
+		SourceIndxs:make([]int, len(fmm.connections)),
+		TargetIndxs:make([]int, len(fmm.connections)),
+		Weights:make([]float64, len(fmm.connections)),
+		Modules:fmm.modules,
+	}
+	for i, conn := range fmm.connections {
+		compiled.SourceIndxs[i], compiled.TargetIndxs[i], compiled.Weights[i] = conn.SourceIndx, conn.TargetIndx, conn.Weight
+	}
+	return &compiled, nil
+}
+
+// Creates new fast network solver for this compiled network
+func (c *CompiledNetwork) FastNetworkSolver() (*FastModularNetworkSolver, error) {
+	connections := make([]*FastNetworkLink, len(c.Weights))
+	for i, weight := range c.Weights {
+		connections[i] = &FastNetworkLink{SourceIndx:c.SourceIndxs[i], TargetIndx:c.TargetIndxs[i], Weight:weight}
+	}
+	// the modules have own buffers, thus should not be shared between solvers
+	modules := make([]*FastControlNode, len(c.Modules))
+	for i, module := range c.Modules {
+		modules[i] = &FastControlNode{ActivationType:module.ActivationType,
+			InputIndxs:module.InputIndxs, OutputIndxs:module.OutputIndxs}
+	}
+	solver := NewFastModularNetworkSolver(c.BiasNeuronCount, c.InputNeuronCount, c.OutputNeuronCount, c.TotalNeuronCount,
+		c.ActivationFunctions, connections, c.Biases, modules)
+	solver.Id, solver.Name, solver.NonFinitePolicy = c.Id, c.Name, c.NonFinitePolicy
+	if c.InitialSignals != nil {
+		if err := solver.SetInitialSignals(c.InitialSignals); err != nil {
+			return nil, err
+		}
+	}
+	return solver, nil
+}
+
+// The executor applying compiled network to many input rows, e.g. to evaluate fitness over large dataset. The
+// executors are pluggable backends registered by name with RegisterExecutor, the default CPUExecutor is always
+// available.
+type Executor interface {
+	// Applies compiled network to each of provided input rows independently: the network state is reset to initial
+	// signals, the row is loaded into sensors and network activated given number of forward steps. Returns outputs
+	// of network per input row. The executor should be safe for concurrent use.
+	Execute(network *CompiledNetwork, inputs [][]float64, steps int) ([][]float64, error)
+}
+
+// The factory to create executor backend
+type ExecutorFactory func() (Executor, error)
+
+var (
+	// The registered executor factories by name
+	executorFactories = map[string]ExecutorFactory{
+		CPUExecutorName:func() (Executor, error) {
+			return &CPUExecutor{}, nil
+		},
+	}
+	executorFactoriesLock sync.RWMutex
+)
+
+// Registers factory of executor backend with given name, which can be used afterwards to create executor with
+// NewExecutor. Returns error if name is empty or executor with the same name already registered.
+func RegisterExecutor(name string, factory ExecutorFactory) error {
+	if len(name) == 0 || factory == nil {
+		return errors.New("executor name and factory should be provided")
+	}
+	executorFactoriesLock.Lock()
+	defer executorFactoriesLock.Unlock()
+	if _, ok := executorFactories[name]; ok {
+		return errors.New(fmt.Sprintf("executor already registered: %s", name))
+	}
+	executorFactories[name] = factory
+	return nil
+}
+
+// Creates executor backend registered with given name. Returns error if no executor registered with such name or
+// backend failed to initialize, e.g. when accelerator is not available.
+func NewExecutor(name string) (Executor, error) {
+	executorFactoriesLock.RLock()
+	factory, ok := executorFactories[name]
+	executorFactoriesLock.RUnlock()
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unknown executor: %s", name))
+	}
+	return factory()
+}
+
+// Returns sorted names of registered executor backends
+func ExecutorNames() []string {
+	executorFactoriesLock.RLock()
+	defer executorFactoriesLock.RUnlock()
+	names := make([]string, 0, len(executorFactories))
+	for name := range executorFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// The default executor which applies network to input rows on CPU. The rows are split into contiguous chunks
+// processed in parallel, each with its own fast network solver.
+type CPUExecutor struct {
+	// The number of parallel workers, if zero or negative the number of CPUs is used
+	Workers int
+}
+
+// Applies compiled network to each of provided input rows and returns outputs per input row
+func (e *CPUExecutor) Execute(network *CompiledNetwork, inputs [][]float64, steps int) ([][]float64, error) {
+	workers := e.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	outputs := make([][]float64, len(inputs))
+	if len(inputs) == 0 {
+		return outputs, nil
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	chunk := (len(inputs) + workers - 1) / workers
+	for w := 0; w < workers; w++ {
+		start, end := w * chunk, (w + 1) * chunk
+		if start >= len(inputs) {
+			break
+		}
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			errs[w] = executeRows(network, inputs[start:end], outputs[start:end], steps)
+		}(w, start, end)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+// Applies compiled network to provided input rows with single fast network solver and stores outputs per row
+func executeRows(network *CompiledNetwork, inputs, outputs [][]float64, steps int) error {
+	solver, err := network.FastNetworkSolver()
+	if err != nil {
+		return err
+	}
+	for i, in := range inputs {
+		if _, err = solver.Flush(); err != nil {
+			return err
+		}
+		if err = solver.LoadSensors(in); err != nil {
+			return err
+		}
+		if _, err = solver.ForwardSteps(steps); err != nil {
+			return err
+		}
+		outputs[i] = append([]float64(nil), solver.ReadOutputs()...)
+	}
+	return nil
+}
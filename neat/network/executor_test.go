@@ -0,0 +1,173 @@
+package network
+
+import (
+	"testing"
+	"errors"
+)
+
+// The executor backend stub which counts executions and delegates them to CPU executor
+type countingExecutor struct {
+	CPUExecutor
+	executions int
+}
+
+func (e *countingExecutor) Execute(network *CompiledNetwork, inputs [][]float64, steps int) ([][]float64, error) {
+	e.executions++
+	return e.CPUExecutor.Execute(network, inputs, steps)
+}
+
+func TestNetwork_Compile(t *testing.T) {
+	netw := buildModularNetwork()
+	compiled, err := netw.Compile()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if compiled.InputNeuronCount != 2 || compiled.BiasNeuronCount != 1 || compiled.OutputNeuronCount != 2 ||
+		compiled.TotalNeuronCount != 8 {
+		t.Error("Wrong neurons count", compiled.InputNeuronCount, compiled.BiasNeuronCount,
+			compiled.OutputNeuronCount, compiled.TotalNeuronCount)
+	}
+	if len(compiled.Weights) != len(compiled.SourceIndxs) || len(compiled.Weights) != len(compiled.TargetIndxs) {
+		t.Error("The connections arrays should be parallel")
+	}
+	if len(compiled.Modules) != 1 {
+		t.Error("Wrong number of modules", len(compiled.Modules))
+	}
+
+	// the solver of compiled network is equivalent to the fast solver of network
+	solver, err := compiled.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	solver.LoadSensors([]float64{1.0, 2.0})
+	if _, err = solver.ForwardSteps(5); err != nil {
+		t.Error(err)
+		return
+	}
+	checkBatchOutputs(t, activateFast(t, netw, []float64{1.0, 2.0}, 5), solver.ReadOutputs())
+}
+
+func TestCPUExecutor_Execute(t *testing.T) {
+	inputs := make([][]float64, 37)
+	for i := range inputs {
+		inputs[i] = []float64{float64(i) * 0.1, 1.0 - float64(i) * 0.05}
+	}
+	for _, netw := range []*Network{buildNetwork(), buildModularNetwork(), buildRecurrentNetwork(-3.0)} {
+		compiled, err := netw.Compile()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		rows := inputs
+		if compiled.InputNeuronCount == 1 {
+			rows = make([][]float64, len(inputs))
+			for i, in := range inputs {
+				rows[i] = in[:1]
+			}
+		}
+		for _, workers := range []int{0, 1, 4, 100} {
+			executor := CPUExecutor{Workers:workers}
+			outputs, err := executor.Execute(compiled, rows, 3)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(outputs) != len(rows) {
+				t.Error("Wrong number of output rows", len(outputs))
+				return
+			}
+			for i, in := range rows {
+				// the fresh network for each row to compare with the state reset by executor
+				checkBatchOutputs(t, activateFast(t, netw, in, 3), outputs[i])
+			}
+		}
+	}
+
+	compiled, _ := buildNetwork().Compile()
+	executor := CPUExecutor{Workers:2}
+	if outputs, err := executor.Execute(compiled, nil, 3); err != nil || len(outputs) != 0 {
+		t.Error("No outputs expected for empty inputs", outputs, err)
+	}
+	if _, err := executor.Execute(compiled, [][]float64{{1.0, 2.0}, {1.0}}, 3); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+}
+
+func TestRegisterExecutor(t *testing.T) {
+	if executor, err := NewExecutor(CPUExecutorName); err != nil {
+		t.Error(err)
+	} else if _, ok := executor.(*CPUExecutor); !ok {
+		t.Error("CPU executor expected", executor)
+	}
+
+	backend := &countingExecutor{}
+	if err := RegisterExecutor("test_counting", func() (Executor, error) {
+		return backend, nil
+	}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := RegisterExecutor("test_counting", func() (Executor, error) {
+		return backend, nil
+	}); err == nil {
+		t.Error("Error expected for duplicate executor name")
+	}
+	if err := RegisterExecutor("", nil); err == nil {
+		t.Error("Error expected for empty name and factory")
+	}
+	found := false
+	for _, name := range ExecutorNames() {
+		found = found || name == "test_counting"
+	}
+	if !found {
+		t.Error("Registered executor name expected", ExecutorNames())
+	}
+
+	executor, err := NewExecutor("test_counting")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	compiled, _ := buildNetwork().Compile()
+	if _, err = executor.Execute(compiled, [][]float64{{1.0, 2.0}}, 2); err != nil {
+		t.Error(err)
+	} else if backend.executions != 1 {
+		t.Error("The registered backend should be used", backend.executions)
+	}
+
+	if _, err = NewExecutor("unknown"); err == nil {
+		t.Error("Error expected for unknown executor")
+	}
+	init_err := errors.New("accelerator not available")
+	if err = RegisterExecutor("test_failing", func() (Executor, error) {
+		return nil, init_err
+	}); err != nil {
+		t.Error(err)
+	} else if _, err = NewExecutor("test_failing"); err != init_err {
+		t.Error("Backend initialization error expected", err)
+	}
+}
+
+func BenchmarkCPUExecutor_Execute(b *testing.B) {
+	compiled, err := buildBenchmarkNetwork(10, 20, 5).Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	inputs := make([][]float64, 10000)
+	for i := range inputs {
+		inputs[i] = make([]float64, 10)
+		for j := range inputs[i] {
+			inputs[i][j] = float64(i + j) * 0.001
+		}
+	}
+	executor := CPUExecutor{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := executor.Execute(compiled, inputs, 2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -26,6 +26,41 @@ type FastControlNode struct {
 	InputIndxs     []int
 	// The indexes of the output nodes
 	OutputIndxs    []int
+
+	// The buffer of input values reused between activations to avoid allocations
+	inputs         []float64
+}
+
+// The topology of fast network shared by network solvers with different representation of signals and weights
+type fastNetworkTopology struct {
+	// The activation functions per neuron, must be in the same order as neuron signals. Has nil entries for
+	// neurons that are inputs or outputs of a module.
+	activationFunctions []utils.NodeActivationType
+	// The control nodes relaying between network modules
+	modules             []*FastControlNode
+
+	// The number of input neurons
+	inputNeuronCount    int
+	// The total number of sensors in the network (input + bias). This is also the index of the first output neuron in the neuron signals.
+	sensorNeuronCount   int
+	// The number of output neurons
+	outputNeuronCount   int
+	// The bias neuron count (usually one). This is also the index of the first input neuron in the neuron signals.
+	biasNeuronCount     int
+	// The total number of neurons in network
+	totalNeuronCount    int
+}
+
+// Returns copy of this topology to be used by another solver. The modules have own buffers, thus they are copied
+// to not be shared between solvers.
+func (t *fastNetworkTopology) copyTopology() fastNetworkTopology {
+	res := *t
+	res.modules = make([]*FastControlNode, len(t.modules))
+	for i, module := range t.modules {
+		res.modules[i] = &FastControlNode{ActivationType:module.ActivationType,
+			InputIndxs:module.InputIndxs, OutputIndxs:module.OutputIndxs}
+	}
+	return res
 }
 
 // The fast modular network solver implementation to be used for big neural networks simulation.
@@ -34,33 +69,25 @@ type FastModularNetworkSolver struct {
 	Id                          int
 	// Is a name of this network */
 	Name                        string
+	// The policy to handle activation values which are not finite numbers
+	NonFinitePolicy             NonFinitePolicy
+	// The optional fixed preprocessor of sensor values applied by LoadSensors
+	Preprocessor                *InputPreprocessor
 
 	// The current activation values per each neuron
 	neuronSignals               []float64
 	// This array is a parallel of neuronSignals and used to test network relaxation
 	neuronSignalsBeingProcessed []float64
+	// The initial activation values per each neuron set after flush, nil if all neurons start from zero
+	initialSignals              []float64
 
-	// The activation functions per neuron, must be in the same order as neuronSignals. Has nil entries for
-	// neurons that are inputs or outputs of a module.
-	activationFunctions         []utils.NodeActivationType
+	// The layout of neurons, their activation functions and modules
+	fastNetworkTopology
 	// The bias values associated with neurons
 	biasList                    []float64
-	// The control nodes relaying between network modules
-	modules                     []*FastControlNode
 	// The connections
 	connections                 []*FastNetworkLink
 
-	// The number of input neurons
-	inputNeuronCount            int
-	// The total number of sensors in the network (input + bias). This is also the index of the first output neuron in the neuron signals.
-	sensorNeuronCount           int
-	// The number of output neurons
-	outputNeuronCount           int
-	// The bias neuron count (usually one). This is also the index of the first input neuron in the neuron signals.
-	biasNeuronCount             int
-	// The total number of neurons in network
-	totalNeuronCount            int
-
 	// For recursive activation, marks whether we have finished this node yet
 	activated                   []bool
 	// For recursive activation, makes whether a node is currently being calculated (recurrent connections processing)
@@ -82,14 +109,16 @@ activationFunctions []utils.NodeActivationType, connections []*FastNetworkLink,
 biasList []float64, modules []*FastControlNode) *FastModularNetworkSolver {
 
 	fmm := FastModularNetworkSolver{
-		biasNeuronCount:biasNeuronCount,
-		inputNeuronCount:inputNeuronCount,
-		sensorNeuronCount:biasNeuronCount + inputNeuronCount,
-		outputNeuronCount:outputNeuronCount,
-		totalNeuronCount:totalNeuronCount,
-		activationFunctions:activationFunctions,
+		fastNetworkTopology:fastNetworkTopology{
+			biasNeuronCount:biasNeuronCount,
+			inputNeuronCount:inputNeuronCount,
+			sensorNeuronCount:biasNeuronCount + inputNeuronCount,
+			outputNeuronCount:outputNeuronCount,
+			totalNeuronCount:totalNeuronCount,
+			activationFunctions:activationFunctions,
+			modules:modules,
+		},
 		biasList:biasList,
-		modules:modules,
 		connections:connections,
 	}
 
@@ -149,6 +178,7 @@ func (fmm *FastModularNetworkSolver) RecursiveSteps() (res bool, err error) {
 	if len(fmm.modules) > 0 {
 		return false, errors.New("recursive activation can not be used for network with defined modules")
 	}
+	countActivation()
 
 	// Initialize boolean arrays and set the last activation signal for output/hidden neurons
 	for i := 0; i < fmm.totalNeuronCount; i++ {
@@ -221,6 +251,8 @@ func (fmm *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res
 		fmm.activationFunctions[currentNode]); err != nil {
 		// failed to activate
 		res = false
+	} else if err = guardActivation(&fmm.neuronSignals[currentNode], fmm.NonFinitePolicy); err != nil {
+		res = false
 	}
 	return res, err
 }
@@ -243,6 +275,7 @@ func (fmm *FastModularNetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta f
 // when absolute value of the change at any given point is less than maxAllowedSignalDelta during activation waves propagation.
 func (fmm *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (isRelaxed bool, err error) {
 	isRelaxed = true
+	countActivation()
 
 	// Calculate output signal per each connection and add the signals to the target neurons
 	for _, conn := range fmm.connections {
@@ -261,11 +294,17 @@ func (fmm *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64)
 			signal, nil, fmm.activationFunctions[i]); err != nil {
 			return false, err
 		}
+		if err = guardActivation(&fmm.neuronSignalsBeingProcessed[i], fmm.NonFinitePolicy); err != nil {
+			return false, err
+		}
 	}
 
 	// Pass the signals through each module (activation function with more than one input or output)
 	for _, module := range fmm.modules {
-		inputs := make([]float64, len(module.InputIndxs))
+		if cap(module.inputs) < len(module.InputIndxs) {
+			module.inputs = make([]float64, len(module.InputIndxs))
+		}
+		inputs := module.inputs[:len(module.InputIndxs)]
 		for i, in_index := range module.InputIndxs {
 			inputs[i] = fmm.neuronSignalsBeingProcessed[in_index]
 		}
@@ -273,6 +312,9 @@ func (fmm *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64)
 			// save outputs
 			for i, out_index := range module.OutputIndxs {
 				fmm.neuronSignalsBeingProcessed[out_index] = outputs[i]
+				if err = guardActivation(&fmm.neuronSignalsBeingProcessed[out_index], fmm.NonFinitePolicy); err != nil {
+					return false, err
+				}
 			}
 		} else {
 			return false, err
@@ -304,13 +346,36 @@ func (fmm *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64)
 // false in case of error.
 func (fmm *FastModularNetworkSolver) Flush() (bool, error) {
 	for i := fmm.biasNeuronCount; i < fmm.totalNeuronCount; i++ {
-		fmm.neuronSignals[i] = 0.0
+		if fmm.initialSignals != nil {
+			fmm.neuronSignals[i] = fmm.initialSignals[i]
+		} else {
+			fmm.neuronSignals[i] = 0.0
+		}
 	}
 	return true, nil
 }
 
+// Sets the initial activation values of neurons in order of neuron signals which are restored by each flush. The
+// sensors are not affected.
+func (fmm *FastModularNetworkSolver) SetInitialSignals(signals []float64) error {
+	if len(signals) != fmm.totalNeuronCount {
+		return errors.New(fmt.Sprintf("the number of initial signals %d is not equal to the number of neurons %d",
+			len(signals), fmm.totalNeuronCount))
+	}
+	fmm.initialSignals = append([]float64(nil), signals...)
+	for i := fmm.sensorNeuronCount; i < fmm.totalNeuronCount; i++ {
+		fmm.neuronSignals[i] = fmm.initialSignals[i]
+	}
+	return nil
+}
+
 // Set sensors values to the input nodes of the network
-func (fmm *FastModularNetworkSolver) LoadSensors(inputs []float64) error {
+func (fmm *FastModularNetworkSolver) LoadSensors(inputs []float64) (err error) {
+	if fmm.Preprocessor != nil {
+		if inputs, err = fmm.Preprocessor.Process(inputs); err != nil {
+			return err
+		}
+	}
 	if len(inputs) == fmm.inputNeuronCount {
 		// only inputs should be provided
 		for i := 0; i < fmm.inputNeuronCount; i++ {
@@ -0,0 +1,378 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"errors"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The single-precision network solver implementation. It has the same layout of neurons and the same semantics of
+// activation as FastModularNetworkSolver, but stores signals, weights and biases as float32 and connections as flat
+// arrays of int32 indexes, thus it takes about half of the memory and its inner loops are friendly to vectorization.
+// It's aimed for deployment of evolved networks into embedded environments where single precision is sufficient.
+// The activation functions are computed in double precision and their results rounded to single precision.
+type Float32NetworkSolver struct {
+	// A network id
+	Id                          int
+	// Is a name of this network
+	Name                        string
+	// The policy to handle activation values which are not finite numbers
+	NonFinitePolicy             NonFinitePolicy
+
+	// The current activation values per each neuron
+	neuronSignals               []float32
+	// This array is a parallel of neuronSignals and used to test network relaxation
+	neuronSignalsBeingProcessed []float32
+	// The initial activation values per each neuron set after flush, nil if all neurons start from zero
+	initialSignals              []float32
+
+	// The layout of neurons, their activation functions and modules
+	fastNetworkTopology
+	// The bias values associated with neurons
+	biasList                    []float32
+
+	// The connections stored as parallel arrays of source indexes, target indexes and weights
+	sourceIndxs                 []int32
+	targetIndxs                 []int32
+	weights                     []float32
+
+	// The incoming connections of each neuron for recursive activation. The incoming connections of neuron i are
+	// stored in the range [incomingStart[i], incomingStart[i + 1]) of incomingSources and incomingWeights.
+	incomingStart               []int32
+	incomingSources             []int32
+	incomingWeights             []float32
+
+	// For recursive activation, marks whether we have finished this node yet
+	activated                   []bool
+	// For recursive activation, makes whether a node is currently being calculated (recurrent connections processing)
+	inActivation                []bool
+	// For recursive activation, the previous activation values of recurrent connections (recurrent connections processing)
+	lastActivation              []float32
+
+	// The buffer of output values in double precision reused between reads to avoid allocations
+	outputs                     []float64
+}
+
+// Creates single-precision network solver based on the architecture of this network. The weights and biases of
+// network are converted to float32, the error returned if any of them is out of range of float32.
+func (n *Network) Float32NetworkSolver() (*Float32NetworkSolver, error) {
+	solver, err := n.FastNetworkSolver()
+	if err != nil {
+		return nil, err
+	}
+	return NewFloat32NetworkSolver(solver.(*FastModularNetworkSolver))
+}
+
+// Creates single-precision network solver with the same architecture as provided double-precision fast network
+// solver. The weights and biases are converted to float32, the error returned if any of them is out of range of float32.
+func NewFloat32NetworkSolver(fmm *FastModularNetworkSolver) (*Float32NetworkSolver, error) {
+	total := fmm.totalNeuronCount
+	solver := Float32NetworkSolver{
+		Id:fmm.Id,
+		Name:fmm.Name,
+		NonFinitePolicy:fmm.NonFinitePolicy,
+		fastNetworkTopology:fmm.copyTopology(),
+	}
+
+	var err error
+	if solver.biasList, err = toFloat32(fmm.biasList, "bias"); err != nil {
+		return nil, err
+	}
+
+	// Store connections as parallel arrays
+	count := len(fmm.connections)
+	solver.sourceIndxs = make([]int32, count)
+	solver.targetIndxs = make([]int32, count)
+	weights := make([]float64, count)
+	for i, conn := range fmm.connections {
+		solver.sourceIndxs[i] = int32(conn.SourceIndx)
+		solver.targetIndxs[i] = int32(conn.TargetIndx)
+		weights[i] = conn.Weight
+	}
+	if solver.weights, err = toFloat32(weights, "weight"); err != nil {
+		return nil, err
+	}
+
+	// Group incoming connections per target neuron keeping order of connections
+	solver.incomingStart = make([]int32, total + 1)
+	for _, target := range solver.targetIndxs {
+		solver.incomingStart[target + 1]++
+	}
+	for i := 0; i < total; i++ {
+		solver.incomingStart[i + 1] += solver.incomingStart[i]
+	}
+	solver.incomingSources = make([]int32, count)
+	solver.incomingWeights = make([]float32, count)
+	next := make([]int32, total)
+	copy(next, solver.incomingStart[:total])
+	for i, target := range solver.targetIndxs {
+		solver.incomingSources[next[target]] = solver.sourceIndxs[i]
+		solver.incomingWeights[next[target]] = solver.weights[i]
+		next[target]++
+	}
+
+	// Allocate the arrays that store the states at different points in the neural network.
+	solver.neuronSignals = make([]float32, total)
+	solver.neuronSignalsBeingProcessed = make([]float32, total)
+	for i := 0; i < solver.biasNeuronCount; i++ {
+		solver.neuronSignals[i] = 1.0 // BIAS neuron signal
+	}
+	if fmm.initialSignals != nil {
+		if solver.initialSignals, err = toFloat32(fmm.initialSignals, "initial signal"); err != nil {
+			return nil, err
+		}
+		for i := solver.sensorNeuronCount; i < total; i++ {
+			solver.neuronSignals[i] = solver.initialSignals[i]
+		}
+	}
+	solver.activated = make([]bool, total)
+	solver.inActivation = make([]bool, total)
+	solver.lastActivation = make([]float32, total)
+	solver.outputs = make([]float64, solver.outputNeuronCount)
+
+	return &solver, nil
+}
+
+// Converts provided values to single precision. Returns error if any of finite values is out of range of float32.
+func toFloat32(values []float64, name string) ([]float32, error) {
+	res := make([]float32, len(values))
+	for i, v := range values {
+		res[i] = float32(v)
+		if IsFinite(v) && math.IsInf(float64(res[i]), 0) {
+			return nil, errors.New(fmt.Sprintf("the %s value is out of range of float32: %f", name, v))
+		}
+	}
+	return res, nil
+}
+
+// Propagates activation wave through all network nodes provided number of steps in forward direction.
+// Returns true if activation wave passed from all inputs to the outputs.
+func (s *Float32NetworkSolver) ForwardSteps(steps int) (res bool, err error) {
+	for i := 0; i < steps; i++ {
+		if res, err = s.forwardStep(0); err != nil {
+			return false, err
+		}
+	}
+	return res, nil
+}
+
+// Propagates activation wave through all network nodes provided number of steps by recursion from output nodes
+// Returns true if activation wave passed from all inputs to the outputs. Can not be used for network with modules.
+func (s *Float32NetworkSolver) RecursiveSteps() (res bool, err error) {
+	if len(s.modules) > 0 {
+		return false, errors.New("recursive activation can not be used for network with defined modules")
+	}
+	countActivation()
+
+	for i := 0; i < s.totalNeuronCount; i++ {
+		s.activated[i] = i < s.sensorNeuronCount
+		s.inActivation[i] = false
+		if i >= s.sensorNeuronCount {
+			s.lastActivation[i] = s.neuronSignals[i]
+		}
+	}
+
+	for i := 0; i < s.outputNeuronCount; i++ {
+		if res, err = s.recursiveActivateNode(s.sensorNeuronCount + i); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Propagate activation wave by recursively looking for input signals graph for a given output neuron
+func (s *Float32NetworkSolver) recursiveActivateNode(currentNode int) (res bool, err error) {
+	if s.activated[currentNode] {
+		s.inActivation[currentNode] = false
+		return true, nil
+	}
+	s.inActivation[currentNode] = true
+	s.neuronSignalsBeingProcessed[currentNode] = 0
+
+	for i := s.incomingStart[currentNode]; i < s.incomingStart[currentNode + 1]; i++ {
+		source := s.incomingSources[i]
+		if s.inActivation[source] {
+			// the recurrent connection, use the previous activation
+			s.neuronSignalsBeingProcessed[currentNode] += s.lastActivation[source] * s.incomingWeights[i]
+		} else {
+			if !s.activated[source] {
+				if res, err = s.recursiveActivateNode(int(source)); err != nil {
+					return false, err
+				}
+			}
+			s.neuronSignalsBeingProcessed[currentNode] += s.neuronSignals[source] * s.incomingWeights[i]
+		}
+	}
+
+	s.activated[currentNode] = true
+	s.inActivation[currentNode] = false
+
+	if s.neuronSignals[currentNode], err = s.activate(
+		s.neuronSignalsBeingProcessed[currentNode], s.activationFunctions[currentNode]); err != nil {
+		res = false
+	}
+	return res, err
+}
+
+// Attempts to relax network given amount of steps until giving up. The network considered relaxed when absolute
+// value of the change at any given point is less than maxAllowedSignalDelta during activation waves propagation.
+// If maxAllowedSignalDelta value is less than or equal to 0, the method will return true without checking for relaxation.
+func (s *Float32NetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
+	for i := 0; i < maxSteps; i++ {
+		if relaxed, err = s.forwardStep(maxAllowedSignalDelta); err != nil {
+			return false, err
+		} else if relaxed {
+			break
+		}
+	}
+	return relaxed, nil
+}
+
+// Performs single forward step through the network and tests if network become relaxed.
+func (s *Float32NetworkSolver) forwardStep(maxAllowedSignalDelta float64) (isRelaxed bool, err error) {
+	isRelaxed = true
+	countActivation()
+
+	// Calculate output signal per each connection and add the signals to the target neurons
+	signals, processed := s.neuronSignals, s.neuronSignalsBeingProcessed
+	for i, weight := range s.weights {
+		processed[s.targetIndxs[i]] += signals[s.sourceIndxs[i]] * weight
+	}
+
+	// Pass the signals through the single-valued activation functions
+	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		signal := processed[i]
+		if s.biasNeuronCount > 0 {
+			signal += s.biasList[i]
+		}
+		if processed[i], err = s.activate(signal, s.activationFunctions[i]); err != nil {
+			return false, err
+		}
+	}
+
+	// Pass the signals through each module in double precision
+	for _, module := range s.modules {
+		if cap(module.inputs) < len(module.InputIndxs) {
+			module.inputs = make([]float64, len(module.InputIndxs))
+		}
+		inputs := module.inputs[:len(module.InputIndxs)]
+		for i, in_index := range module.InputIndxs {
+			inputs[i] = float64(processed[in_index])
+		}
+		outputs, err := utils.NodeActivators.ActivateModuleByType(inputs, nil, module.ActivationType)
+		if err != nil {
+			return false, err
+		}
+		for i, out_index := range module.OutputIndxs {
+			if processed[out_index], err = s.guard(outputs[i]); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// Move all the neuron signals we changed while processing this network activation into storage.
+	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		if maxAllowedSignalDelta > 0 {
+			isRelaxed = isRelaxed && !(math.Abs(float64(signals[i] - processed[i])) > maxAllowedSignalDelta)
+		}
+		signals[i] = processed[i]
+		processed[i] = 0
+	}
+
+	return isRelaxed, nil
+}
+
+// Applies activation function of given type to the signal in double precision and returns its result rounded to
+// single precision and checked according to the non-finite values policy.
+func (s *Float32NetworkSolver) activate(signal float32, activation utils.NodeActivationType) (float32, error) {
+	value, err := utils.NodeActivators.ActivateByType(float64(signal), nil, activation)
+	if err != nil {
+		return 0, err
+	}
+	return s.guard(value)
+}
+
+// Rounds provided value to single precision and checks it according to the non-finite values policy
+func (s *Float32NetworkSolver) guard(value float64) (float32, error) {
+	value = float64(float32(value))
+	if err := guardActivation(&value, s.NonFinitePolicy); err != nil {
+		return 0, err
+	}
+	return float32(value), nil
+}
+
+// Flushes network state by removing all current activations. Returns true if network flushed successfully or
+// false in case of error.
+func (s *Float32NetworkSolver) Flush() (bool, error) {
+	for i := s.biasNeuronCount; i < s.totalNeuronCount; i++ {
+		if s.initialSignals != nil {
+			s.neuronSignals[i] = s.initialSignals[i]
+		} else {
+			s.neuronSignals[i] = 0.0
+		}
+	}
+	return true, nil
+}
+
+// Set sensors values to the input nodes of the network, the values are rounded to single precision
+func (s *Float32NetworkSolver) LoadSensors(inputs []float64) error {
+	if len(inputs) != s.inputNeuronCount {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	for i, v := range inputs {
+		s.neuronSignals[s.biasNeuronCount + i] = float32(v)
+	}
+	return nil
+}
+
+// Set single-precision sensors values to the input nodes of the network
+func (s *Float32NetworkSolver) LoadSensors32(inputs []float32) error {
+	if len(inputs) != s.inputNeuronCount {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	copy(s.neuronSignals[s.biasNeuronCount:], inputs)
+	return nil
+}
+
+// Read output values from the output nodes of the network converted to double precision. The returned slice is reused
+// by subsequent reads.
+func (s *Float32NetworkSolver) ReadOutputs() []float64 {
+	for i, v := range s.ReadOutputs32() {
+		s.outputs[i] = float64(v)
+	}
+	return s.outputs
+}
+
+// Read single-precision output values from the output nodes of the network
+func (s *Float32NetworkSolver) ReadOutputs32() []float32 {
+	return s.neuronSignals[s.sensorNeuronCount:s.sensorNeuronCount + s.outputNeuronCount]
+}
+
+// Returns the total number of neural units in the network
+func (s *Float32NetworkSolver) NodeCount() int {
+	return s.totalNeuronCount + len(s.modules)
+}
+
+// Returns the total number of links between nodes in the network
+func (s *Float32NetworkSolver) LinkCount() int {
+	num_links := len(s.weights)
+	if s.biasNeuronCount > 0 {
+		for _, b := range s.biasList {
+			if b != 0 {
+				num_links++
+			}
+		}
+	}
+	for _, module := range s.modules {
+		num_links += len(module.InputIndxs) + len(module.OutputIndxs)
+	}
+	return num_links
+}
+
+// Stringer
+func (s *Float32NetworkSolver) String() string {
+	return fmt.Sprintf("Float32Network, id: %d, name: [%s], neurons: %d,\n\tinputs: %d,\tbias: %d,\toutputs:%d,\t hidden: %d",
+		s.Id, s.Name, s.totalNeuronCount, s.inputNeuronCount, s.biasNeuronCount, s.outputNeuronCount,
+		s.totalNeuronCount - s.sensorNeuronCount - s.outputNeuronCount)
+}
@@ -0,0 +1,259 @@
+package network
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The maximal difference between outputs of single and double precision solvers
+const float32Tolerance = 1e-5
+
+func buildFloat32Solvers(t *testing.T, netw *Network) (*FastModularNetworkSolver, *Float32NetworkSolver) {
+	fmm, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return nil, nil
+	}
+	solver, err := netw.Float32NetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return nil, nil
+	}
+	return fmm.(*FastModularNetworkSolver), solver
+}
+
+func checkFloat32Outputs(t *testing.T, expected []float64, solver *Float32NetworkSolver) {
+	outputs := solver.ReadOutputs()
+	if len(outputs) != len(expected) {
+		t.Error("Wrong number of outputs", len(outputs), len(expected))
+		return
+	}
+	for i, out := range outputs {
+		if math.Abs(out - expected[i]) > float32Tolerance {
+			t.Error("Output differs from double precision solver at:", i, out, "!=", expected[i])
+		}
+		if float64(solver.ReadOutputs32()[i]) != out {
+			t.Error("Single precision output differs at:", i)
+		}
+	}
+}
+
+func TestFloat32NetworkSolver_ForwardSteps(t *testing.T) {
+	for _, netw := range []*Network{buildNetwork(), buildModularNetwork()} {
+		fmm, solver := buildFloat32Solvers(t, netw)
+		if solver == nil {
+			return
+		}
+		data := []float64{1.0, 2.0}
+		if err := fmm.LoadSensors(data); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := solver.LoadSensors(data); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := fmm.ForwardSteps(5); err != nil {
+			t.Error(err)
+			return
+		}
+		if res, err := solver.ForwardSteps(5); err != nil {
+			t.Error(err)
+		} else if !res {
+			t.Error("forward steps returned false")
+		} else {
+			checkFloat32Outputs(t, fmm.ReadOutputs(), solver)
+		}
+	}
+}
+
+func TestFloat32NetworkSolver_RecursiveSteps(t *testing.T) {
+	fmm, solver := buildFloat32Solvers(t, buildNetwork())
+	if solver == nil {
+		return
+	}
+	if err := fmm.LoadSensors([]float64{0.0, 1.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := solver.LoadSensors32([]float32{0.0, 1.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := fmm.RecursiveSteps(); err != nil {
+		t.Error(err)
+		return
+	}
+	if res, err := solver.RecursiveSteps(); err != nil {
+		t.Error(err)
+	} else if !res {
+		t.Error("recursive activation returned false")
+	} else {
+		checkFloat32Outputs(t, fmm.ReadOutputs(), solver)
+	}
+
+	// the modular network can not be activated recursively
+	if _, solver = buildFloat32Solvers(t, buildModularNetwork()); solver == nil {
+		return
+	}
+	if _, err := solver.RecursiveSteps(); err == nil {
+		t.Error("Error expected for recursive activation of modular network")
+	}
+}
+
+func TestFloat32NetworkSolver_Relax(t *testing.T) {
+	fmm, solver := buildFloat32Solvers(t, buildModularNetwork())
+	if solver == nil {
+		return
+	}
+	data := []float64{1.5, 2.0}
+	fmm.LoadSensors(data)
+	solver.LoadSensors(data)
+	if _, err := fmm.Relax(5, 1); err != nil {
+		t.Error(err)
+		return
+	}
+	if res, err := solver.Relax(5, 1); err != nil {
+		t.Error(err)
+	} else if !res {
+		t.Error("failed to relax within given maximal steps number")
+	} else {
+		checkFloat32Outputs(t, fmm.ReadOutputs(), solver)
+	}
+}
+
+func TestFloat32NetworkSolver_Flush(t *testing.T) {
+	_, solver := buildFloat32Solvers(t, buildModularNetwork())
+	if solver == nil {
+		return
+	}
+	solver.LoadSensors([]float64{1.5, 2.0})
+	solver.ForwardSteps(3)
+	if res, err := solver.Flush(); err != nil || !res {
+		t.Error("failed to flush network", err)
+	}
+	for i := solver.biasNeuronCount; i < solver.totalNeuronCount; i++ {
+		if solver.neuronSignals[i] != 0 {
+			t.Error("after flush the active signal still present at:", i)
+		}
+	}
+	if solver.neuronSignals[0] != 1.0 {
+		t.Error("BIAS signal should be kept")
+	}
+}
+
+func TestFloat32NetworkSolver_InitialSignals(t *testing.T) {
+	_, solver := buildFloat32Solvers(t, buildRecurrentNetwork(-3.0))
+	if solver == nil {
+		return
+	}
+	if solver.neuronSignals[2] != -3.0 {
+		t.Error("Initial signal of hidden neuron expected", solver.neuronSignals[2])
+	}
+	solver.LoadSensors([]float64{0.5})
+	solver.ForwardSteps(2)
+	if res, err := solver.Flush(); err != nil || !res {
+		t.Error("failed to flush network", err)
+	}
+	if solver.neuronSignals[2] != -3.0 || solver.neuronSignals[1] != 0.0 {
+		t.Error("Initial signals expected after flush", solver.neuronSignals)
+	}
+}
+
+func TestFloat32NetworkSolver_counts(t *testing.T) {
+	fmm, solver := buildFloat32Solvers(t, buildModularNetwork())
+	if solver == nil {
+		return
+	}
+	var _ NetworkSolver = solver
+	if solver.NodeCount() != fmm.NodeCount() {
+		t.Error("Wrong node count", solver.NodeCount(), fmm.NodeCount())
+	}
+	if solver.LinkCount() != fmm.LinkCount() {
+		t.Error("Wrong link count", solver.LinkCount(), fmm.LinkCount())
+	}
+	if err := solver.LoadSensors([]float64{1.0}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+	if err := solver.LoadSensors32([]float32{1.0, 2.0, 3.0}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+}
+
+func TestNetwork_Float32NetworkSolver_outOfRange(t *testing.T) {
+	netw := buildNetwork()
+	netw.Outputs[0].Incoming[0].Weight = math.MaxFloat64
+	if _, err := netw.Float32NetworkSolver(); err == nil {
+		t.Error("Error expected for weight out of range of float32")
+	}
+}
+
+func TestFloat32NetworkSolver_nonFinite(t *testing.T) {
+	for _, policy := range []NonFinitePolicy{ClampNonFinite, ErrorOnNonFinite} {
+		netw := buildNetwork()
+		netw.NonFinitePolicy = policy
+		// the sum of incoming signals of linear hidden node overflows float32
+		hidden := netw.all_nodes[3]
+		hidden.ActivationType = utils.LinearActivation
+		for _, link := range hidden.Incoming {
+			link.Weight = math.MaxFloat32
+		}
+		_, solver := buildFloat32Solvers(t, netw)
+		if solver == nil {
+			return
+		}
+		solver.LoadSensors([]float64{1.0, 1.0})
+		_, err := solver.ForwardSteps(1)
+		if policy == ErrorOnNonFinite {
+			if err != NetErrNonFiniteActivation {
+				t.Error("Non finite activation error expected", err)
+			}
+		} else if err != nil {
+			t.Error(err)
+		} else if solver.neuronSignals[5] != math.MaxFloat32 {
+			t.Error("The overflow should be clamped", solver.neuronSignals[5])
+		}
+	}
+}
+
+// Tests that single precision solver activation does not allocate memory
+func TestFloat32NetworkSolver_allocations(t *testing.T) {
+	_, solver := buildFloat32Solvers(t, buildNetwork())
+	if solver == nil {
+		return
+	}
+	data := []float32{1.0, 2.0}
+	allocs := testing.AllocsPerRun(100, func() {
+		solver.LoadSensors32(data)
+		solver.ForwardSteps(3)
+		solver.RecursiveSteps()
+		solver.Relax(3, 0.1)
+		solver.ReadOutputs()
+		solver.Flush()
+	})
+	if allocs != 0 {
+		t.Error("Single precision solver activation allocates", allocs)
+	}
+}
+
+func BenchmarkFloat32NetworkSolver_ForwardSteps(b *testing.B) {
+	solver, err := buildBenchmarkNetwork(10, 20, 5).Float32NetworkSolver()
+	if err != nil {
+		b.Fatal(err)
+	}
+	inputs := make([]float32, 10)
+	for i := range inputs {
+		inputs[i] = float32(i) * 0.1
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := solver.LoadSensors32(inputs); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := solver.ForwardSteps(2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
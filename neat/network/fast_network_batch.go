@@ -0,0 +1,363 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"bytes"
+	"errors"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The network solver activating together the batch of networks with identical topology, i.e. with the same layout of
+// neurons, activation functions, connections and modules, but with different weights, biases and initial signals, e.g.
+// the clones of the same genome with perturbed weights, which dominate in the early generations of evolution. The
+// signals, weights and biases of batch members are packed into matrices having one row per neuron or connection and one
+// column per batch member, thus propagation of signals through each connection is the tight loop over contiguous
+// columns friendly to vectorization. It has the same semantics of activation as FastModularNetworkSolver.
+type BatchNetworkSolver struct {
+	// The policy to handle activation values which are not finite numbers
+	NonFinitePolicy             NonFinitePolicy
+
+	// The number of networks in the batch
+	size                        int
+
+	// The current activation values per each neuron and batch member
+	neuronSignals               []float64
+	// This matrix is a parallel of neuronSignals and used to test network relaxation
+	neuronSignalsBeingProcessed []float64
+	// The initial activation values per each neuron and batch member set after flush, nil if all neurons start from zero
+	initialSignals              []float64
+
+	// The layout of neurons, their activation functions and modules shared by batch members
+	fastNetworkTopology
+	// The bias values per each neuron and batch member
+	biasList                    []float64
+
+	// The connections shared by batch members stored as parallel arrays of source and target indexes
+	sourceIndxs                 []int
+	targetIndxs                 []int
+	// The weights per each connection and batch member
+	weights                     []float64
+
+	// The buffers of output values per batch member reused between reads to avoid allocations
+	outputs                     [][]float64
+}
+
+// Creates batch network solver for provided networks which should have identical topology. The non-finite values
+// policy of the first network is used for the whole batch. Returns error if no networks provided, any of networks can
+// not be represented by fast network solver, or topologies of networks differ.
+func NewBatchNetworkSolver(networks []*Network) (*BatchNetworkSolver, error) {
+	if len(networks) == 0 {
+		return nil, errors.New("no networks provided to create batch network solver")
+	}
+	solvers := make([]*FastModularNetworkSolver, len(networks))
+	for i, n := range networks {
+		solver, err := n.FastNetworkSolver()
+		if err != nil {
+			return nil, err
+		}
+		solvers[i] = solver.(*FastModularNetworkSolver)
+	}
+	return newBatchNetworkSolver(solvers)
+}
+
+// Creates batch network solver from provided fast network solvers
+func newBatchNetworkSolver(solvers []*FastModularNetworkSolver) (*BatchNetworkSolver, error) {
+	first := solvers[0]
+	key := topologyKey(first)
+	for i, fmm := range solvers[1:] {
+		if topologyKey(fmm) != key {
+			return nil, errors.New(fmt.Sprintf(
+				"the topology of network at index %d differs from the topology of the first network in batch", i + 1))
+		}
+	}
+	size, total, count := len(solvers), first.totalNeuronCount, len(first.connections)
+	solver := BatchNetworkSolver{
+		NonFinitePolicy:first.NonFinitePolicy,
+		size:size,
+		fastNetworkTopology:first.copyTopology(),
+		sourceIndxs:make([]int, count),
+		targetIndxs:make([]int, count),
+		weights:make([]float64, count * size),
+		biasList:make([]float64, total * size),
+		neuronSignals:make([]float64, total * size),
+		neuronSignalsBeingProcessed:make([]float64, total * size),
+		outputs:make([][]float64, size),
+	}
+	for i, conn := range first.connections {
+		solver.sourceIndxs[i], solver.targetIndxs[i] = conn.SourceIndx, conn.TargetIndx
+	}
+
+	with_initial := false
+	for _, fmm := range solvers {
+		with_initial = with_initial || fmm.initialSignals != nil
+	}
+	if with_initial {
+		solver.initialSignals = make([]float64, total * size)
+	}
+	for b, fmm := range solvers {
+		for i, conn := range fmm.connections {
+			solver.weights[i * size + b] = conn.Weight
+		}
+		for i, bias := range fmm.biasList {
+			solver.biasList[i * size + b] = bias
+		}
+		if fmm.initialSignals != nil {
+			for i, v := range fmm.initialSignals {
+				solver.initialSignals[i * size + b] = v
+			}
+		}
+		solver.outputs[b] = make([]float64, solver.outputNeuronCount)
+	}
+	solver.Flush()
+	for i := 0; i < solver.biasNeuronCount * size; i++ {
+		solver.neuronSignals[i] = 1.0 // BIAS neuron signal
+	}
+
+	return &solver, nil
+}
+
+// Returns the key which is equal for fast network solvers with identical topology
+func topologyKey(fmm *FastModularNetworkSolver) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d %d %d %d|", fmm.biasNeuronCount, fmm.inputNeuronCount, fmm.outputNeuronCount, fmm.totalNeuronCount)
+	for _, activation := range fmm.activationFunctions {
+		fmt.Fprintf(&buf, "%d ", activation)
+	}
+	buf.WriteByte('|')
+	for _, conn := range fmm.connections {
+		fmt.Fprintf(&buf, "%d:%d ", conn.SourceIndx, conn.TargetIndx)
+	}
+	for _, module := range fmm.modules {
+		fmt.Fprintf(&buf, "|%d %v %v", module.ActivationType, module.InputIndxs, module.OutputIndxs)
+	}
+	return buf.String()
+}
+
+// Returns the number of networks in this batch
+func (s *BatchNetworkSolver) Size() int {
+	return s.size
+}
+
+// Propagates activation wave through all networks of batch provided number of steps in forward direction.
+// Returns true if activation wave passed from all inputs to the outputs.
+func (s *BatchNetworkSolver) ForwardSteps(steps int) (res bool, err error) {
+	for i := 0; i < steps; i++ {
+		if res, err = s.forwardStep(0); err != nil {
+			return false, err
+		}
+	}
+	return res, nil
+}
+
+// Attempts to relax all networks of batch given amount of steps until giving up. The batch considered relaxed when
+// absolute value of the change at any given point of every network is less than maxAllowedSignalDelta during activation
+// waves propagation. If maxAllowedSignalDelta value is less than or equal to 0, the method will return true without
+// checking for relaxation.
+func (s *BatchNetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
+	for i := 0; i < maxSteps; i++ {
+		if relaxed, err = s.forwardStep(maxAllowedSignalDelta); err != nil {
+			return false, err
+		} else if relaxed {
+			break
+		}
+	}
+	return relaxed, nil
+}
+
+// Performs single forward step through all networks of batch and tests if they become relaxed.
+func (s *BatchNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (isRelaxed bool, err error) {
+	isRelaxed = true
+	countActivation()
+	size := s.size
+
+	// Calculate output signal per each connection and add the signals to the target neurons of all batch members
+	signals, processed := s.neuronSignals, s.neuronSignalsBeingProcessed
+	for i, source := range s.sourceIndxs {
+		src := signals[source * size:source * size + size]
+		dst := processed[s.targetIndxs[i] * size:s.targetIndxs[i] * size + size]
+		weights := s.weights[i * size:i * size + size]
+		for b, w := range weights {
+			dst[b] += src[b] * w
+		}
+	}
+
+	// Pass the signals through the single-valued activation functions
+	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		row := processed[i * size:i * size + size]
+		if s.biasNeuronCount > 0 {
+			for b, bias := range s.biasList[i * size:i * size + size] {
+				row[b] += bias
+			}
+		}
+		for b, signal := range row {
+			if row[b], err = utils.NodeActivators.ActivateByType(signal, nil, s.activationFunctions[i]); err != nil {
+				return false, err
+			}
+			if err = guardActivation(&row[b], s.NonFinitePolicy); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// Pass the signals through each module of every batch member
+	for _, module := range s.modules {
+		if cap(module.inputs) < len(module.InputIndxs) {
+			module.inputs = make([]float64, len(module.InputIndxs))
+		}
+		inputs := module.inputs[:len(module.InputIndxs)]
+		for b := 0; b < size; b++ {
+			for i, in_index := range module.InputIndxs {
+				inputs[i] = processed[in_index * size + b]
+			}
+			outputs, err := utils.NodeActivators.ActivateModuleByType(inputs, nil, module.ActivationType)
+			if err != nil {
+				return false, err
+			}
+			for i, out_index := range module.OutputIndxs {
+				processed[out_index * size + b] = outputs[i]
+				if err = guardActivation(&processed[out_index * size + b], s.NonFinitePolicy); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	// Move all the neuron signals we changed while processing this activation into storage.
+	for i := s.sensorNeuronCount * size; i < s.totalNeuronCount * size; i++ {
+		if maxAllowedSignalDelta > 0 {
+			isRelaxed = isRelaxed && !(math.Abs(signals[i] - processed[i]) > maxAllowedSignalDelta)
+		}
+		signals[i] = processed[i]
+		processed[i] = 0
+	}
+
+	return isRelaxed, nil
+}
+
+// Flushes state of all networks of batch by removing all current activations. Returns true if networks flushed
+// successfully or false in case of error.
+func (s *BatchNetworkSolver) Flush() (bool, error) {
+	for i := s.biasNeuronCount * s.size; i < s.totalNeuronCount * s.size; i++ {
+		if s.initialSignals != nil {
+			s.neuronSignals[i] = s.initialSignals[i]
+		} else {
+			s.neuronSignals[i] = 0.0
+		}
+	}
+	return true, nil
+}
+
+// Set sensors values to the input nodes of batch member with given index
+func (s *BatchNetworkSolver) LoadSensors(member int, inputs []float64) error {
+	if len(inputs) != s.inputNeuronCount {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	if member < 0 || member >= s.size {
+		return errors.New(fmt.Sprintf("batch member index is out of range [0, %d): %d", s.size, member))
+	}
+	for i, v := range inputs {
+		s.neuronSignals[(s.biasNeuronCount + i) * s.size + member] = v
+	}
+	return nil
+}
+
+// Set the same sensors values to the input nodes of all batch members
+func (s *BatchNetworkSolver) LoadSensorsAll(inputs []float64) error {
+	if len(inputs) != s.inputNeuronCount {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	for i, v := range inputs {
+		row := s.neuronSignals[(s.biasNeuronCount + i) * s.size:(s.biasNeuronCount + i + 1) * s.size]
+		for b := range row {
+			row[b] = v
+		}
+	}
+	return nil
+}
+
+// Read output values from the output nodes of batch member with given index. The returned slice is reused by
+// subsequent reads of the same member.
+func (s *BatchNetworkSolver) ReadOutputs(member int) []float64 {
+	outputs := s.outputs[member]
+	for i := range outputs {
+		outputs[i] = s.neuronSignals[(s.sensorNeuronCount + i) * s.size + member]
+	}
+	return outputs
+}
+
+// Stringer
+func (s *BatchNetworkSolver) String() string {
+	return fmt.Sprintf("BatchNetwork, size: %d, neurons: %d,\n\tinputs: %d,\tbias: %d,\toutputs:%d,\t hidden: %d",
+		s.size, s.totalNeuronCount, s.inputNeuronCount, s.biasNeuronCount, s.outputNeuronCount,
+		s.totalNeuronCount - s.sensorNeuronCount - s.outputNeuronCount)
+}
+
+// The executor activating networks in batches of identical topology. The networks are grouped by topology and
+// each group is activated by single BatchNetworkSolver, thus the more networks share the same topology, the larger
+// speedup is gained compared to activation of each network separately.
+type BatchActivationExecutor struct {
+	// The number of forward steps per activation of networks with each input row
+	ActivationSteps int
+}
+
+// Groups provided networks by topology and returns batch solvers for each group along with the indexes of networks
+// in each batch, i.e. the network networks[indexes[i][j]] is the member j of batch solver i. The batches are ordered
+// by the index of the first network in the batch. Returns error if any of networks can not be represented by fast
+// network solver.
+func (e BatchActivationExecutor) Batches(networks []*Network) (batches []*BatchNetworkSolver, indexes [][]int, err error) {
+	groups, grouped := make(map[string]int), make([][]*FastModularNetworkSolver, 0)
+	for i, n := range networks {
+		solver, err := n.FastNetworkSolver()
+		if err != nil {
+			return nil, nil, err
+		}
+		fmm := solver.(*FastModularNetworkSolver)
+		key := topologyKey(fmm)
+		group, ok := groups[key]
+		if !ok {
+			group = len(grouped)
+			groups[key] = group
+			grouped = append(grouped, nil)
+			indexes = append(indexes, nil)
+		}
+		grouped[group] = append(grouped[group], fmm)
+		indexes[group] = append(indexes[group], i)
+	}
+	batches = make([]*BatchNetworkSolver, len(grouped))
+	for i, solvers := range grouped {
+		if batches[i], err = newBatchNetworkSolver(solvers); err != nil {
+			return nil, nil, err
+		}
+	}
+	return batches, indexes, nil
+}
+
+// Activates each of provided networks with every input row. Before each row all networks are flushed, the row is
+// loaded into sensors, and networks activated ActivationSteps forward steps. Returns outputs of networks per network
+// per input row, i.e. outputs[i][j] holds outputs of networks[i] for inputs[j].
+func (e BatchActivationExecutor) Activate(networks []*Network, inputs [][]float64) (outputs [][][]float64, err error) {
+	batches, indexes, err := e.Batches(networks)
+	if err != nil {
+		return nil, err
+	}
+	outputs = make([][][]float64, len(networks))
+	for i := range outputs {
+		outputs[i] = make([][]float64, len(inputs))
+	}
+	for i, batch := range batches {
+		for row, in := range inputs {
+			batch.Flush()
+			if err = batch.LoadSensorsAll(in); err != nil {
+				return nil, err
+			}
+			if _, err = batch.ForwardSteps(e.ActivationSteps); err != nil {
+				return nil, err
+			}
+			for member, index := range indexes[i] {
+				outputs[index][row] = append([]float64(nil), batch.ReadOutputs(member)...)
+			}
+		}
+	}
+	return outputs, nil
+}
@@ -0,0 +1,218 @@
+package network
+
+import (
+	"testing"
+	"math"
+)
+
+// Creates networks with the same topology as network built by given function but with weights scaled by different factors
+func buildBatchNetworks(build func() *Network, size int) []*Network {
+	networks := make([]*Network, size)
+	for i := range networks {
+		networks[i] = build()
+		for _, node := range networks[i].all_nodes {
+			for _, link := range node.Incoming {
+				link.Weight *= 1.0 - 0.1 * float64(i)
+			}
+		}
+	}
+	return networks
+}
+
+// Activates given network by fast network solver and returns its outputs
+func activateFast(t *testing.T, netw *Network, inputs []float64, steps int) []float64 {
+	fmm, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	if err = fmm.LoadSensors(inputs); err != nil {
+		t.Error(err)
+		return nil
+	}
+	if _, err = fmm.ForwardSteps(steps); err != nil {
+		t.Error(err)
+		return nil
+	}
+	return fmm.ReadOutputs()
+}
+
+func checkBatchOutputs(t *testing.T, expected, outputs []float64) {
+	if len(outputs) != len(expected) {
+		t.Error("Wrong number of outputs", len(outputs), len(expected))
+		return
+	}
+	for i, out := range outputs {
+		if math.Abs(out - expected[i]) > 1e-12 {
+			t.Error("Output differs from fast network solver at:", i, out, "!=", expected[i])
+		}
+	}
+}
+
+func TestBatchNetworkSolver_ForwardSteps(t *testing.T) {
+	for _, build := range []func() *Network{buildNetwork, buildModularNetwork} {
+		networks := buildBatchNetworks(build, 4)
+		batch, err := NewBatchNetworkSolver(networks)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if batch.Size() != len(networks) {
+			t.Error("Wrong batch size", batch.Size())
+		}
+		for i := range networks {
+			if err := batch.LoadSensors(i, []float64{1.0, 0.5 * float64(i)}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if res, err := batch.ForwardSteps(5); err != nil {
+			t.Error(err)
+			return
+		} else if !res {
+			t.Error("forward steps returned false")
+		}
+		for i, netw := range networks {
+			expected := activateFast(t, netw, []float64{1.0, 0.5 * float64(i)}, 5)
+			checkBatchOutputs(t, expected, batch.ReadOutputs(i))
+		}
+	}
+}
+
+func TestBatchNetworkSolver_Relax(t *testing.T) {
+	networks := buildBatchNetworks(buildModularNetwork, 3)
+	batch, err := NewBatchNetworkSolver(networks)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data := []float64{1.5, 2.0}
+	if err := batch.LoadSensorsAll(data); err != nil {
+		t.Error(err)
+		return
+	}
+	if res, err := batch.Relax(5, 1); err != nil {
+		t.Error(err)
+		return
+	} else if !res {
+		t.Error("failed to relax within given maximal steps number")
+	}
+	for i, netw := range networks {
+		fmm, _ := netw.FastNetworkSolver()
+		fmm.LoadSensors(data)
+		if _, err := fmm.Relax(5, 1); err != nil {
+			t.Error(err)
+			return
+		}
+		checkBatchOutputs(t, fmm.ReadOutputs(), batch.ReadOutputs(i))
+	}
+}
+
+func TestBatchNetworkSolver_Flush(t *testing.T) {
+	networks := []*Network{buildRecurrentNetwork(-3.0), buildRecurrentNetwork(0.0)}
+	batch, err := NewBatchNetworkSolver(networks)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	batch.LoadSensorsAll([]float64{0.5})
+	batch.ForwardSteps(2)
+	for i, netw := range networks {
+		checkBatchOutputs(t, activateFast(t, netw, []float64{0.5}, 2), batch.ReadOutputs(i))
+	}
+	if res, err := batch.Flush(); err != nil || !res {
+		t.Error("failed to flush network", err)
+	}
+	// the hidden neuron has index 2 in both networks
+	if batch.neuronSignals[2 * 2] != -3.0 || batch.neuronSignals[2 * 2 + 1] != 0.0 {
+		t.Error("Initial signals expected after flush", batch.neuronSignals)
+	}
+	for i := 0; i < 2; i++ {
+		if batch.neuronSignals[i] != 0.0 {
+			t.Error("Input signal expected to be flushed", batch.neuronSignals)
+		}
+	}
+}
+
+func TestNewBatchNetworkSolver_errors(t *testing.T) {
+	if _, err := NewBatchNetworkSolver(nil); err == nil {
+		t.Error("Error expected for empty batch")
+	}
+	if _, err := NewBatchNetworkSolver([]*Network{buildNetwork(), buildModularNetwork()}); err == nil {
+		t.Error("Error expected for networks with different topologies")
+	}
+	batch, err := NewBatchNetworkSolver([]*Network{buildNetwork()})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := batch.LoadSensors(0, []float64{1.0}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+	if err := batch.LoadSensorsAll([]float64{1.0}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+	if err := batch.LoadSensors(1, []float64{1.0, 2.0}); err == nil {
+		t.Error("Error expected for batch member index out of range")
+	}
+}
+
+func TestBatchActivationExecutor_Activate(t *testing.T) {
+	networks := append(buildBatchNetworks(buildNetwork, 3), buildBatchNetworks(buildModularNetwork, 2)...)
+	networks = append(networks, buildNetwork())
+	executor := BatchActivationExecutor{ActivationSteps:5}
+
+	batches, indexes, err := executor.Batches(networks)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(batches) != 2 {
+		t.Error("Two batches expected", len(batches))
+		return
+	}
+	if len(indexes[0]) != 4 || indexes[0][3] != 5 || len(indexes[1]) != 2 || indexes[1][0] != 3 {
+		t.Error("Wrong networks grouping", indexes)
+	}
+
+	inputs := [][]float64{{1.0, 2.0}, {0.0, 1.0}, {-1.0, 0.5}}
+	outputs, err := executor.Activate(networks, inputs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(outputs) != len(networks) {
+		t.Error("Wrong number of outputs", len(outputs))
+		return
+	}
+	for i, netw := range networks {
+		for row, in := range inputs {
+			checkBatchOutputs(t, activateFast(t, netw, in, 5), outputs[i][row])
+		}
+	}
+}
+
+func BenchmarkBatchNetworkSolver_ForwardSteps(b *testing.B) {
+	networks := make([]*Network, 100)
+	for i := range networks {
+		networks[i] = buildBenchmarkNetwork(10, 20, 5)
+	}
+	batch, err := NewBatchNetworkSolver(networks)
+	if err != nil {
+		b.Fatal(err)
+	}
+	inputs := make([]float64, 10)
+	for i := range inputs {
+		inputs[i] = float64(i) * 0.1
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := batch.LoadSensorsAll(inputs); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := batch.ForwardSteps(2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"errors"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The number of fractional bits of fixed-point neuron signals used by QuantizedNetworkSolver, i.e. the signals are
+// stored as signed Q15.16 numbers.
+const FixedPointFractionBits = 16
+
+// The fixed-point representation of one
+const fixedPointOne = 1 << FixedPointFractionBits
+
+// The minimal and maximal number of bits per quantized weight
+const (
+	MinQuantizationBits = 2
+	MaxQuantizationBits = 16
+)
+
+// The quantized network solver implementation aimed for deployment of evolved networks into microcontrollers without
+// floating point unit. It has the same layout of neurons and the same semantics of activation as FastModularNetworkSolver,
+// but stores weights and biases as signed integers of given bit width (int8 or int16 for the most of targets) with
+// per-network scale factors, and neuron signals as fixed-point numbers with FixedPointFractionBits fractional bits. The
+// weighted sums of neuron inputs are accumulated in integer arithmetic and scaled back only before activation. The
+// activation functions are computed in double precision and their results rounded to fixed-point, saturating at the
+// bounds of fixed-point range; the embedded targets usually replace them by lookup tables with the same semantics.
+type QuantizedNetworkSolver struct {
+	// A network id
+	Id                          int
+	// Is a name of this network
+	Name                        string
+	// The policy to handle activation values which are not finite numbers
+	NonFinitePolicy             NonFinitePolicy
+	// The number of bits per quantized weight and bias
+	Bits                        int
+	// The scale factors to convert quantized weights and biases into real values, i.e. weight = quantized * WeightScale
+	WeightScale                 float64
+	BiasScale                   float64
+
+	// The current fixed-point activation values per each neuron
+	neuronSignals               []int32
+	// This array is a parallel of neuronSignals and used to accumulate weighted sums of neuron inputs
+	neuronSignalsBeingProcessed []int64
+	// The initial fixed-point activation values per each neuron set after flush, nil if all neurons start from zero
+	initialSignals              []int32
+
+	// The layout of neurons, their activation functions and modules
+	fastNetworkTopology
+	// The quantized bias values associated with neurons
+	biasList                    []int16
+
+	// The connections stored as parallel arrays of source indexes, target indexes and quantized weights
+	sourceIndxs                 []int32
+	targetIndxs                 []int32
+	weights                     []int16
+
+	// The incoming connections of each neuron for recursive activation. The incoming connections of neuron i are
+	// stored in the range [incomingStart[i], incomingStart[i + 1]) of incomingSources and incomingWeights.
+	incomingStart               []int32
+	incomingSources             []int32
+	incomingWeights             []int16
+
+	// For recursive activation, marks whether we have finished this node yet
+	activated                   []bool
+	// For recursive activation, makes whether a node is currently being calculated (recurrent connections processing)
+	inActivation                []bool
+	// For recursive activation, the previous activation values of recurrent connections (recurrent connections processing)
+	lastActivation              []int32
+
+	// The buffer of output values in double precision reused between reads to avoid allocations
+	outputs                     []float64
+}
+
+// Creates quantized network solver based on the architecture of this network with weights and biases quantized to
+// signed integers of given bit width. Returns error if bit width is out of range [MinQuantizationBits, MaxQuantizationBits]
+// or network has weights or biases which are not finite numbers.
+func (n *Network) Quantize(bits int) (*QuantizedNetworkSolver, error) {
+	solver, err := n.FastNetworkSolver()
+	if err != nil {
+		return nil, err
+	}
+	return NewQuantizedNetworkSolver(solver.(*FastModularNetworkSolver), bits)
+}
+
+// Creates quantized network solver with the same architecture as provided double-precision fast network solver. The
+// weights and biases are quantized to signed integers of given bit width using symmetric per-network scale factors,
+// which map the largest magnitude to the largest quantized value. Returns error if bit width is out of range
+// [MinQuantizationBits, MaxQuantizationBits] or any of weights or biases is not finite number.
+func NewQuantizedNetworkSolver(fmm *FastModularNetworkSolver, bits int) (*QuantizedNetworkSolver, error) {
+	if bits < MinQuantizationBits || bits > MaxQuantizationBits {
+		return nil, errors.New(fmt.Sprintf("the number of quantization bits is out of range [%d, %d]: %d",
+			MinQuantizationBits, MaxQuantizationBits, bits))
+	}
+	total := fmm.totalNeuronCount
+	solver := QuantizedNetworkSolver{
+		Id:fmm.Id,
+		Name:fmm.Name,
+		NonFinitePolicy:fmm.NonFinitePolicy,
+		Bits:bits,
+		fastNetworkTopology:fmm.copyTopology(),
+	}
+
+	var err error
+	if solver.biasList, solver.BiasScale, err = quantize(fmm.biasList, bits, "bias"); err != nil {
+		return nil, err
+	}
+
+	// Store connections as parallel arrays
+	count := len(fmm.connections)
+	solver.sourceIndxs = make([]int32, count)
+	solver.targetIndxs = make([]int32, count)
+	weights := make([]float64, count)
+	for i, conn := range fmm.connections {
+		solver.sourceIndxs[i] = int32(conn.SourceIndx)
+		solver.targetIndxs[i] = int32(conn.TargetIndx)
+		weights[i] = conn.Weight
+	}
+	if solver.weights, solver.WeightScale, err = quantize(weights, bits, "weight"); err != nil {
+		return nil, err
+	}
+
+	// Group incoming connections per target neuron keeping order of connections
+	solver.incomingStart = make([]int32, total + 1)
+	for _, target := range solver.targetIndxs {
+		solver.incomingStart[target + 1]++
+	}
+	for i := 0; i < total; i++ {
+		solver.incomingStart[i + 1] += solver.incomingStart[i]
+	}
+	solver.incomingSources = make([]int32, count)
+	solver.incomingWeights = make([]int16, count)
+	next := make([]int32, total)
+	copy(next, solver.incomingStart[:total])
+	for i, target := range solver.targetIndxs {
+		solver.incomingSources[next[target]] = solver.sourceIndxs[i]
+		solver.incomingWeights[next[target]] = solver.weights[i]
+		next[target]++
+	}
+
+	// Allocate the arrays that store the states at different points in the neural network.
+	solver.neuronSignals = make([]int32, total)
+	solver.neuronSignalsBeingProcessed = make([]int64, total)
+	for i := 0; i < solver.biasNeuronCount; i++ {
+		solver.neuronSignals[i] = fixedPointOne // BIAS neuron signal
+	}
+	if fmm.initialSignals != nil {
+		solver.initialSignals = make([]int32, total)
+		for i, v := range fmm.initialSignals {
+			solver.initialSignals[i] = ToFixedPoint(v)
+		}
+		for i := solver.sensorNeuronCount; i < total; i++ {
+			solver.neuronSignals[i] = solver.initialSignals[i]
+		}
+	}
+	solver.activated = make([]bool, total)
+	solver.inActivation = make([]bool, total)
+	solver.lastActivation = make([]int32, total)
+	solver.outputs = make([]float64, solver.outputNeuronCount)
+
+	return &solver, nil
+}
+
+// Quantizes provided values to signed integers of given bit width with symmetric scale factor. Returns quantized values
+// and the scale factor to restore them. Returns error if any of values is not finite number.
+func quantize(values []float64, bits int, name string) ([]int16, float64, error) {
+	max_abs := 0.0
+	for _, v := range values {
+		if !IsFinite(v) {
+			return nil, 0, errors.New(fmt.Sprintf("the %s value can not be quantized: %f", name, v))
+		}
+		max_abs = math.Max(max_abs, math.Abs(v))
+	}
+	limit := float64(int(1) << uint(bits - 1) - 1)
+	scale := 1.0
+	if max_abs > 0 {
+		scale = max_abs / limit
+	}
+	res := make([]int16, len(values))
+	for i, v := range values {
+		res[i] = int16(math.Max(-limit, math.Min(limit, math.Round(v / scale))))
+	}
+	return res, scale, nil
+}
+
+// Converts provided value to fixed-point number with FixedPointFractionBits fractional bits. The values out of range
+// of fixed-point numbers are saturated, NaN is converted to zero.
+func ToFixedPoint(v float64) int32 {
+	v = math.Round(v * fixedPointOne)
+	switch {
+	case math.IsNaN(v):
+		return 0
+	case v >= math.MaxInt32:
+		return math.MaxInt32
+	case v <= math.MinInt32:
+		return math.MinInt32
+	default:
+		return int32(v)
+	}
+}
+
+// Converts provided fixed-point number with FixedPointFractionBits fractional bits to double precision value
+func FromFixedPoint(v int32) float64 {
+	return float64(v) / fixedPointOne
+}
+
+// Propagates activation wave through all network nodes provided number of steps in forward direction.
+// Returns true if activation wave passed from all inputs to the outputs.
+func (s *QuantizedNetworkSolver) ForwardSteps(steps int) (res bool, err error) {
+	for i := 0; i < steps; i++ {
+		if res, err = s.forwardStep(0); err != nil {
+			return false, err
+		}
+	}
+	return res, nil
+}
+
+// Propagates activation wave through all network nodes provided number of steps by recursion from output nodes
+// Returns true if activation wave passed from all inputs to the outputs. Can not be used for network with modules.
+func (s *QuantizedNetworkSolver) RecursiveSteps() (res bool, err error) {
+	if len(s.modules) > 0 {
+		return false, errors.New("recursive activation can not be used for network with defined modules")
+	}
+	countActivation()
+
+	for i := 0; i < s.totalNeuronCount; i++ {
+		s.activated[i] = i < s.sensorNeuronCount
+		s.inActivation[i] = false
+		if i >= s.sensorNeuronCount {
+			s.lastActivation[i] = s.neuronSignals[i]
+		}
+	}
+
+	for i := 0; i < s.outputNeuronCount; i++ {
+		if res, err = s.recursiveActivateNode(s.sensorNeuronCount + i); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Propagate activation wave by recursively looking for input signals graph for a given output neuron
+func (s *QuantizedNetworkSolver) recursiveActivateNode(currentNode int) (res bool, err error) {
+	if s.activated[currentNode] {
+		s.inActivation[currentNode] = false
+		return true, nil
+	}
+	s.inActivation[currentNode] = true
+	s.neuronSignalsBeingProcessed[currentNode] = 0
+
+	for i := s.incomingStart[currentNode]; i < s.incomingStart[currentNode + 1]; i++ {
+		source := s.incomingSources[i]
+		if s.inActivation[source] {
+			// the recurrent connection, use the previous activation
+			s.neuronSignalsBeingProcessed[currentNode] += int64(s.lastActivation[source]) * int64(s.incomingWeights[i])
+		} else {
+			if !s.activated[source] {
+				if res, err = s.recursiveActivateNode(int(source)); err != nil {
+					return false, err
+				}
+			}
+			s.neuronSignalsBeingProcessed[currentNode] += int64(s.neuronSignals[source]) * int64(s.incomingWeights[i])
+		}
+	}
+
+	s.activated[currentNode] = true
+	s.inActivation[currentNode] = false
+
+	if s.neuronSignals[currentNode], err = s.activate(
+		s.neuronSignalsBeingProcessed[currentNode], currentNode); err != nil {
+		res = false
+	}
+	return res, err
+}
+
+// Attempts to relax network given amount of steps until giving up. The network considered relaxed when absolute
+// value of the change at any given point is less than maxAllowedSignalDelta during activation waves propagation.
+// If maxAllowedSignalDelta value is less than or equal to 0, the method will return true without checking for relaxation.
+func (s *QuantizedNetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
+	for i := 0; i < maxSteps; i++ {
+		if relaxed, err = s.forwardStep(maxAllowedSignalDelta); err != nil {
+			return false, err
+		} else if relaxed {
+			break
+		}
+	}
+	return relaxed, nil
+}
+
+// Performs single forward step through the network and tests if network become relaxed.
+func (s *QuantizedNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (isRelaxed bool, err error) {
+	isRelaxed = true
+	countActivation()
+
+	// Accumulate weighted signals of each connection in the target neurons using integer arithmetic
+	signals, processed := s.neuronSignals, s.neuronSignalsBeingProcessed
+	for i, weight := range s.weights {
+		processed[s.targetIndxs[i]] += int64(signals[s.sourceIndxs[i]]) * int64(weight)
+	}
+
+	// Pass the signals through the single-valued activation functions, the results are stored in the accumulators
+	// as fixed-point numbers till all neurons processed
+	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		signal, err := s.activate(processed[i], i)
+		if err != nil {
+			return false, err
+		}
+		processed[i] = int64(signal)
+	}
+
+	// Pass the signals through each module in double precision
+	for _, module := range s.modules {
+		if cap(module.inputs) < len(module.InputIndxs) {
+			module.inputs = make([]float64, len(module.InputIndxs))
+		}
+		inputs := module.inputs[:len(module.InputIndxs)]
+		for i, in_index := range module.InputIndxs {
+			inputs[i] = FromFixedPoint(int32(processed[in_index]))
+		}
+		outputs, err := utils.NodeActivators.ActivateModuleByType(inputs, nil, module.ActivationType)
+		if err != nil {
+			return false, err
+		}
+		for i, out_index := range module.OutputIndxs {
+			signal, err := s.guard(outputs[i])
+			if err != nil {
+				return false, err
+			}
+			processed[out_index] = int64(signal)
+		}
+	}
+
+	// Move all the neuron signals we changed while processing this network activation into storage.
+	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		signal := int32(processed[i])
+		if maxAllowedSignalDelta > 0 {
+			isRelaxed = isRelaxed && !(math.Abs(FromFixedPoint(signals[i]) - FromFixedPoint(signal)) > maxAllowedSignalDelta)
+		}
+		signals[i] = signal
+		processed[i] = 0
+	}
+
+	return isRelaxed, nil
+}
+
+// Scales the accumulated weighted sum of inputs of given neuron back to real value, adds the bias of neuron, and applies
+// activation function of neuron in double precision. Returns result converted to fixed-point and checked according to
+// the non-finite values policy.
+func (s *QuantizedNetworkSolver) activate(sum int64, neuron int) (int32, error) {
+	signal := float64(sum) * s.WeightScale / fixedPointOne
+	if s.biasNeuronCount > 0 {
+		signal += float64(s.biasList[neuron]) * s.BiasScale
+	}
+	value, err := utils.NodeActivators.ActivateByType(signal, nil, s.activationFunctions[neuron])
+	if err != nil {
+		return 0, err
+	}
+	return s.guard(value)
+}
+
+// Checks provided value according to the non-finite values policy and converts it to fixed-point
+func (s *QuantizedNetworkSolver) guard(value float64) (int32, error) {
+	if err := guardActivation(&value, s.NonFinitePolicy); err != nil {
+		return 0, err
+	}
+	return ToFixedPoint(value), nil
+}
+
+// Flushes network state by removing all current activations. Returns true if network flushed successfully or
+// false in case of error.
+func (s *QuantizedNetworkSolver) Flush() (bool, error) {
+	for i := s.biasNeuronCount; i < s.totalNeuronCount; i++ {
+		if s.initialSignals != nil {
+			s.neuronSignals[i] = s.initialSignals[i]
+		} else {
+			s.neuronSignals[i] = 0
+		}
+	}
+	return true, nil
+}
+
+// Set sensors values to the input nodes of the network, the values are converted to fixed-point
+func (s *QuantizedNetworkSolver) LoadSensors(inputs []float64) error {
+	if len(inputs) != s.inputNeuronCount {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	for i, v := range inputs {
+		s.neuronSignals[s.biasNeuronCount + i] = ToFixedPoint(v)
+	}
+	return nil
+}
+
+// Set fixed-point sensors values to the input nodes of the network
+func (s *QuantizedNetworkSolver) LoadSensorsFixed(inputs []int32) error {
+	if len(inputs) != s.inputNeuronCount {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	copy(s.neuronSignals[s.biasNeuronCount:], inputs)
+	return nil
+}
+
+// Read output values from the output nodes of the network converted to double precision. The returned slice is reused
+// by subsequent reads.
+func (s *QuantizedNetworkSolver) ReadOutputs() []float64 {
+	for i, v := range s.ReadOutputsFixed() {
+		s.outputs[i] = FromFixedPoint(v)
+	}
+	return s.outputs
+}
+
+// Read fixed-point output values from the output nodes of the network
+func (s *QuantizedNetworkSolver) ReadOutputsFixed() []int32 {
+	return s.neuronSignals[s.sensorNeuronCount:s.sensorNeuronCount + s.outputNeuronCount]
+}
+
+// Returns the total number of neural units in the network
+func (s *QuantizedNetworkSolver) NodeCount() int {
+	return s.totalNeuronCount + len(s.modules)
+}
+
+// Returns the total number of links between nodes in the network
+func (s *QuantizedNetworkSolver) LinkCount() int {
+	num_links := len(s.weights)
+	if s.biasNeuronCount > 0 {
+		for _, b := range s.biasList {
+			if b != 0 {
+				num_links++
+			}
+		}
+	}
+	for _, module := range s.modules {
+		num_links += len(module.InputIndxs) + len(module.OutputIndxs)
+	}
+	return num_links
+}
+
+// Returns the maximal absolute difference between the original weights and biases of network and their quantized values
+// restored by scale factors
+func (s *QuantizedNetworkSolver) maxQuantizationError(fmm *FastModularNetworkSolver) float64 {
+	max_err := 0.0
+	for i, conn := range fmm.connections {
+		max_err = math.Max(max_err, math.Abs(conn.Weight - float64(s.weights[i]) * s.WeightScale))
+	}
+	for i, bias := range fmm.biasList {
+		max_err = math.Max(max_err, math.Abs(bias - float64(s.biasList[i]) * s.BiasScale))
+	}
+	return max_err
+}
+
+// Stringer
+func (s *QuantizedNetworkSolver) String() string {
+	return fmt.Sprintf("QuantizedNetwork, id: %d, name: [%s], bits: %d, neurons: %d,\n\tinputs: %d,\tbias: %d,\toutputs:%d,\t hidden: %d",
+		s.Id, s.Name, s.Bits, s.totalNeuronCount, s.inputNeuronCount, s.biasNeuronCount, s.outputNeuronCount,
+		s.totalNeuronCount - s.sensorNeuronCount - s.outputNeuronCount)
+}
+
+// The report about the effect of quantization on the performance of network
+type QuantizationReport struct {
+	// The number of bits per quantized weight and bias
+	Bits             int
+	// The scale factors of quantized weights and biases
+	WeightScale      float64
+	BiasScale        float64
+	// The maximal absolute error of quantized weights and biases
+	MaxWeightError   float64
+	// The fitness of original network and the fitness of its quantized copy
+	Fitness          float64
+	QuantizedFitness float64
+	// The change of fitness caused by quantization, i.e. QuantizedFitness - Fitness
+	FitnessDelta     float64
+}
+
+// Quantizes this network with given bit width and evaluates both the double-precision fast solver of network and its
+// quantized copy with provided fitness function to report the fitness delta caused by quantization. Returns error if
+// quantization failed or fitness function returned error.
+func (n *Network) QuantizationReport(bits int, fitness func(solver NetworkSolver) (float64, error)) (*QuantizationReport, error) {
+	solver, err := n.FastNetworkSolver()
+	if err != nil {
+		return nil, err
+	}
+	fmm := solver.(*FastModularNetworkSolver)
+	quantized, err := NewQuantizedNetworkSolver(fmm, bits)
+	if err != nil {
+		return nil, err
+	}
+	report := QuantizationReport{
+		Bits:bits,
+		WeightScale:quantized.WeightScale,
+		BiasScale:quantized.BiasScale,
+		MaxWeightError:quantized.maxQuantizationError(fmm),
+	}
+	if report.Fitness, err = fitness(fmm); err != nil {
+		return nil, err
+	}
+	if report.QuantizedFitness, err = fitness(quantized); err != nil {
+		return nil, err
+	}
+	report.FitnessDelta = report.QuantizedFitness - report.Fitness
+	return &report, nil
+}
+
+// Stringer
+func (r *QuantizationReport) String() string {
+	return fmt.Sprintf("Quantization bits: %d, weight scale: %g, bias scale: %g, max weight error: %g, fitness: %f -> %f, delta: %f",
+		r.Bits, r.WeightScale, r.BiasScale, r.MaxWeightError, r.Fitness, r.QuantizedFitness, r.FitnessDelta)
+}
@@ -0,0 +1,325 @@
+package network
+
+import (
+	"testing"
+	"math"
+	"errors"
+)
+
+// The maximal relative difference between outputs of quantized and double precision solvers with 16 bits weights
+const quantizedTolerance = 1e-3
+
+func buildQuantizedSolvers(t *testing.T, netw *Network, bits int) (*FastModularNetworkSolver, *QuantizedNetworkSolver) {
+	fmm, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return nil, nil
+	}
+	solver, err := netw.Quantize(bits)
+	if err != nil {
+		t.Error(err)
+		return nil, nil
+	}
+	return fmm.(*FastModularNetworkSolver), solver
+}
+
+func checkQuantizedOutputs(t *testing.T, expected []float64, solver *QuantizedNetworkSolver, tolerance float64) {
+	outputs := solver.ReadOutputs()
+	if len(outputs) != len(expected) {
+		t.Error("Wrong number of outputs", len(outputs), len(expected))
+		return
+	}
+	for i, out := range outputs {
+		if math.Abs(out - expected[i]) > tolerance * math.Max(1.0, math.Abs(expected[i])) {
+			t.Error("Output differs from double precision solver at:", i, out, "!=", expected[i])
+		}
+		if FromFixedPoint(solver.ReadOutputsFixed()[i]) != out {
+			t.Error("Fixed-point output differs at:", i)
+		}
+	}
+}
+
+func TestNetwork_Quantize(t *testing.T) {
+	_, solver := buildQuantizedSolvers(t, buildNetwork(), 8)
+	if solver == nil {
+		return
+	}
+	if solver.Bits != 8 {
+		t.Error("Wrong number of bits", solver.Bits)
+	}
+	// the largest weight is mapped to the largest int8 value
+	if solver.WeightScale != 17.0 / 127 {
+		t.Error("Wrong weight scale", solver.WeightScale)
+	}
+	found := false
+	for i, w := range solver.weights {
+		if w > 127 || w < -127 {
+			t.Error("Weight out of int8 range at:", i, w)
+		}
+		found = found || w == 127
+	}
+	if !found {
+		t.Error("The largest weight should be quantized to 127", solver.weights)
+	}
+
+	for _, bits := range []int{MinQuantizationBits - 1, MaxQuantizationBits + 1} {
+		if _, err := buildNetwork().Quantize(bits); err == nil {
+			t.Error("Error expected for number of bits:", bits)
+		}
+	}
+	netw := buildNetwork()
+	netw.Outputs[0].Incoming[0].Weight = math.NaN()
+	if _, err := netw.Quantize(16); err == nil {
+		t.Error("Error expected for non-finite weight")
+	}
+}
+
+func TestFixedPoint(t *testing.T) {
+	for _, v := range []float64{0, 1, -1, 0.5, -2.25, 1000.125} {
+		if res := FromFixedPoint(ToFixedPoint(v)); res != v {
+			t.Error("Wrong fixed-point conversion", v, res)
+		}
+	}
+	if ToFixedPoint(1.0) != 1 << FixedPointFractionBits {
+		t.Error("Wrong fixed-point one", ToFixedPoint(1.0))
+	}
+	if ToFixedPoint(math.Inf(1)) != math.MaxInt32 || ToFixedPoint(-1e10) != math.MinInt32 {
+		t.Error("The fixed-point conversion should saturate")
+	}
+	if ToFixedPoint(math.NaN()) != 0 {
+		t.Error("NaN should be converted to zero")
+	}
+}
+
+func TestQuantizedNetworkSolver_ForwardSteps(t *testing.T) {
+	for _, netw := range []*Network{buildNetwork(), buildModularNetwork()} {
+		fmm, solver := buildQuantizedSolvers(t, netw, 16)
+		if solver == nil {
+			return
+		}
+		data := []float64{1.0, 2.0}
+		if err := fmm.LoadSensors(data); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := solver.LoadSensors(data); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := fmm.ForwardSteps(5); err != nil {
+			t.Error(err)
+			return
+		}
+		if res, err := solver.ForwardSteps(5); err != nil {
+			t.Error(err)
+		} else if !res {
+			t.Error("forward steps returned false")
+		} else {
+			checkQuantizedOutputs(t, fmm.ReadOutputs(), solver, quantizedTolerance)
+		}
+	}
+}
+
+func TestQuantizedNetworkSolver_RecursiveSteps(t *testing.T) {
+	fmm, solver := buildQuantizedSolvers(t, buildNetwork(), 16)
+	if solver == nil {
+		return
+	}
+	if err := fmm.LoadSensors([]float64{0.0, 1.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := solver.LoadSensorsFixed([]int32{0, ToFixedPoint(1.0)}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := fmm.RecursiveSteps(); err != nil {
+		t.Error(err)
+		return
+	}
+	if res, err := solver.RecursiveSteps(); err != nil {
+		t.Error(err)
+	} else if !res {
+		t.Error("recursive activation returned false")
+	} else {
+		checkQuantizedOutputs(t, fmm.ReadOutputs(), solver, quantizedTolerance)
+	}
+
+	// the modular network can not be activated recursively
+	if _, solver = buildQuantizedSolvers(t, buildModularNetwork(), 16); solver == nil {
+		return
+	}
+	if _, err := solver.RecursiveSteps(); err == nil {
+		t.Error("Error expected for recursive activation of modular network")
+	}
+}
+
+func TestQuantizedNetworkSolver_Relax(t *testing.T) {
+	fmm, solver := buildQuantizedSolvers(t, buildModularNetwork(), 16)
+	if solver == nil {
+		return
+	}
+	data := []float64{1.5, 2.0}
+	fmm.LoadSensors(data)
+	solver.LoadSensors(data)
+	if _, err := fmm.Relax(5, 1); err != nil {
+		t.Error(err)
+		return
+	}
+	if res, err := solver.Relax(5, 1); err != nil {
+		t.Error(err)
+	} else if !res {
+		t.Error("failed to relax within given maximal steps number")
+	} else {
+		checkQuantizedOutputs(t, fmm.ReadOutputs(), solver, quantizedTolerance)
+	}
+}
+
+func TestQuantizedNetworkSolver_Flush(t *testing.T) {
+	_, solver := buildQuantizedSolvers(t, buildRecurrentNetwork(-3.0), 8)
+	if solver == nil {
+		return
+	}
+	if solver.neuronSignals[2] != ToFixedPoint(-3.0) {
+		t.Error("Initial signal of hidden neuron expected", solver.neuronSignals[2])
+	}
+	solver.LoadSensors([]float64{0.5})
+	solver.ForwardSteps(2)
+	if res, err := solver.Flush(); err != nil || !res {
+		t.Error("failed to flush network", err)
+	}
+	if solver.neuronSignals[2] != ToFixedPoint(-3.0) || solver.neuronSignals[1] != 0 {
+		t.Error("Initial signals expected after flush", solver.neuronSignals)
+	}
+}
+
+func TestQuantizedNetworkSolver_counts(t *testing.T) {
+	fmm, solver := buildQuantizedSolvers(t, buildModularNetwork(), 8)
+	if solver == nil {
+		return
+	}
+	var _ NetworkSolver = solver
+	if solver.neuronSignals[0] != ToFixedPoint(1.0) {
+		t.Error("BIAS signal expected", solver.neuronSignals[0])
+	}
+	if solver.NodeCount() != fmm.NodeCount() {
+		t.Error("Wrong node count", solver.NodeCount(), fmm.NodeCount())
+	}
+	if solver.LinkCount() != fmm.LinkCount() {
+		t.Error("Wrong link count", solver.LinkCount(), fmm.LinkCount())
+	}
+	if err := solver.LoadSensors([]float64{1.0}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+	if err := solver.LoadSensorsFixed([]int32{1, 2, 3}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+}
+
+func TestQuantizedNetworkSolver_nonFinite(t *testing.T) {
+	netw := buildNetwork()
+	netw.NonFinitePolicy = ErrorOnNonFinite
+	_, solver := buildQuantizedSolvers(t, netw, 8)
+	if solver == nil {
+		return
+	}
+	if _, err := solver.guard(math.NaN()); err != NetErrNonFiniteActivation {
+		t.Error("Non finite activation error expected", err)
+	}
+	solver.NonFinitePolicy = ClampNonFinite
+	if v, err := solver.guard(math.Inf(1)); err != nil {
+		t.Error(err)
+	} else if v != math.MaxInt32 {
+		t.Error("The overflow should be saturated", v)
+	}
+}
+
+func TestNetwork_QuantizationReport(t *testing.T) {
+	// the fitness is the sum of outputs after activation
+	fitness := func(solver NetworkSolver) (float64, error) {
+		if err := solver.LoadSensors([]float64{0.3, 0.7}); err != nil {
+			return 0, err
+		}
+		if _, err := solver.ForwardSteps(5); err != nil {
+			return 0, err
+		}
+		sum := 0.0
+		for _, out := range solver.ReadOutputs() {
+			sum += out
+		}
+		return sum, nil
+	}
+	report8, err := buildModularNetwork().QuantizationReport(8, fitness)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	report16, err := buildModularNetwork().QuantizationReport(16, fitness)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if report8.FitnessDelta != report8.QuantizedFitness - report8.Fitness {
+		t.Error("Wrong fitness delta", report8)
+	}
+	if report8.Fitness != report16.Fitness {
+		t.Error("The fitness of original network should not depend on quantization", report8.Fitness, report16.Fitness)
+	}
+	if math.Abs(report16.FitnessDelta) > math.Abs(report8.FitnessDelta) {
+		t.Error("The fitness delta should decrease with number of bits", report8.FitnessDelta, report16.FitnessDelta)
+	}
+	if report8.MaxWeightError <= report16.MaxWeightError || report8.MaxWeightError > report8.WeightScale / 2 {
+		t.Error("Wrong max weight error", report8.MaxWeightError, report16.MaxWeightError)
+	}
+	if len(report8.String()) == 0 {
+		t.Error("Empty report string")
+	}
+
+	fitness_err := errors.New("fitness error")
+	if _, err := buildNetwork().QuantizationReport(8, func(NetworkSolver) (float64, error) {
+		return 0, fitness_err
+	}); err != fitness_err {
+		t.Error("Fitness error expected", err)
+	}
+}
+
+// Tests that quantized solver activation does not allocate memory
+func TestQuantizedNetworkSolver_allocations(t *testing.T) {
+	_, solver := buildQuantizedSolvers(t, buildNetwork(), 8)
+	if solver == nil {
+		return
+	}
+	data := []int32{ToFixedPoint(1.0), ToFixedPoint(2.0)}
+	allocs := testing.AllocsPerRun(100, func() {
+		solver.LoadSensorsFixed(data)
+		solver.ForwardSteps(3)
+		solver.RecursiveSteps()
+		solver.Relax(3, 0.1)
+		solver.ReadOutputs()
+		solver.Flush()
+	})
+	if allocs != 0 {
+		t.Error("Quantized solver activation allocates", allocs)
+	}
+}
+
+func BenchmarkQuantizedNetworkSolver_ForwardSteps(b *testing.B) {
+	solver, err := buildBenchmarkNetwork(10, 20, 5).Quantize(8)
+	if err != nil {
+		b.Fatal(err)
+	}
+	inputs := make([]int32, 10)
+	for i := range inputs {
+		inputs[i] = ToFixedPoint(float64(i) * 0.1)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := solver.LoadSensorsFixed(inputs); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := solver.ForwardSteps(2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
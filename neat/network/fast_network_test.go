@@ -187,6 +187,38 @@ func TestFastModularNetworkSolver_Flush(t *testing.T) {
 	}
 }
 
+func TestFastModularNetworkSolver_InitialSignals(t *testing.T) {
+	netw := buildRecurrentNetwork(-3.0)
+	solver, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	fmm := solver.(*FastModularNetworkSolver)
+	// the order of neurons: input, output, hidden
+	if fmm.neuronSignals[2] != -3.0 {
+		t.Error("Initial signal of hidden neuron expected", fmm.neuronSignals[2])
+	}
+	fmm.LoadSensors([]float64{0.5})
+	if _, err = fmm.ForwardSteps(2); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = fmm.Flush(); err != nil {
+		t.Error(err)
+		return
+	}
+	for i, expected := range []float64{0.0, 0.0, -3.0} {
+		if fmm.neuronSignals[i] != expected {
+			t.Error("Wrong signal after flush at:", i, fmm.neuronSignals[i], expected)
+		}
+	}
+
+	if err = fmm.SetInitialSignals([]float64{1.0}); err == nil {
+		t.Error("Error expected for wrong number of initial signals")
+	}
+}
+
 func TestFastModularNetworkSolver_NodeCount(t *testing.T) {
 	netw := buildModularNetwork()
 
@@ -218,3 +250,38 @@ func countActiveSignals(fmm_impl *FastModularNetworkSolver) int {
 	}
 	return active
 }
+
+// Tests that fast network solver activation does not allocate memory except of module activation functions outputs
+func TestFastModularNetworkSolver_allocations(t *testing.T) {
+	netw := buildNetwork()
+	solver, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data := []float64{1.0, 2.0}
+	allocs := testing.AllocsPerRun(100, func() {
+		solver.LoadSensors(data)
+		solver.ForwardSteps(3)
+		solver.RecursiveSteps()
+		solver.Relax(3, 0.1)
+		solver.ReadOutputs()
+		solver.Flush()
+	})
+	if allocs != 0 {
+		t.Error("Fast network solver activation allocates", allocs)
+	}
+
+	modular, err := buildModularNetwork().FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	modular.ForwardSteps(1) // warm up buffers
+	allocs = testing.AllocsPerRun(100, func() {
+		modular.ForwardSteps(1)
+	})
+	if allocs > 1 {
+		t.Error("Modular fast network solver activation allocates", allocs)
+	}
+}
@@ -18,6 +18,10 @@ type Link struct {
 	IsRecurrent   bool
 	// If TRUE the link is time delayed
 	IsTimeDelayed bool
+	// The number of additional activation steps the signal is delayed by this link, zero if not delayed
+	Delay         int
+	// The node which activation multiplies the signal relayed by this link, nil if link is not gated
+	GateNode      *NNode
 
 	// Points to a trait of parameters for genetic creation
 	Trait         *neat.Trait
@@ -28,6 +32,10 @@ type Link struct {
 	Params        []float64
 	// The amount of weight adjustment
 	AddedWeight   float64
+
+	// The ring buffer of signals delayed by this link and the index of the oldest one
+	delayed       []float64
+	delayedIdx    int
 }
 
 // Creates new link with specified weight, input and output neurons connected reccurently or not.
@@ -58,6 +66,8 @@ func NewLinkCopy(l *Link, innode, outnode *NNode) *Link {
 	link.Trait = l.Trait
 	link.deriveTrait(l.Trait)
 	link.IsRecurrent = l.IsRecurrent
+	link.Delay = l.Delay
+	link.GateNode = l.GateNode
 	return link
 }
 
@@ -68,6 +78,30 @@ func newLink(weight float64) *Link {
 	}
 }
 
+// Returns the signal relayed by this link for given output of its input node at current activation step. The output
+// is delayed by Delay activation steps and multiplied by the activation of gating node if any.
+func (l *Link) relay(input float64) float64 {
+	if l.Delay > 0 {
+		if len(l.delayed) != l.Delay {
+			l.delayed, l.delayedIdx = make([]float64, l.Delay), 0
+		}
+		input, l.delayed[l.delayedIdx] = l.delayed[l.delayedIdx], input
+		l.delayedIdx = (l.delayedIdx + 1) % l.Delay
+	}
+	if l.GateNode != nil {
+		input *= l.GateNode.GetActiveOut()
+	}
+	return input
+}
+
+// Removes all signals delayed by this link
+func (l *Link) flush() {
+	for i := range l.delayed {
+		l.delayed[i] = 0.0
+	}
+	l.delayedIdx = 0
+}
+
 // Checks if this link is genetically equal to provided one, i.e. connects nodes with the same IDs and has equal
 // recurrent flag. I.e. if both links represent the same Gene.
 func (l *Link) IsEqualGenetically(ol *Link) bool {
@@ -80,8 +114,12 @@ func (l *Link) IsEqualGenetically(ol *Link) bool {
 
 // The Link methods implementation
 func (l *Link) String() string {
-	return fmt.Sprintf("[Link: (%s <-> %s), weight: %.3f, recurrent: %t, time delayed: %t]",
-		l.InNode, l.OutNode, l.Weight, l.IsRecurrent, l.IsTimeDelayed)
+	gate := ""
+	if l.GateNode != nil {
+		gate = fmt.Sprintf(", gate: %d", l.GateNode.Id)
+	}
+	return fmt.Sprintf("[Link: (%s <-> %s), weight: %.3f, recurrent: %t, time delayed: %t, delay: %d%s]",
+		l.InNode, l.OutNode, l.Weight, l.IsRecurrent, l.IsTimeDelayed, l.Delay, gate)
 }
 
 // Copy trait parameters into this link's parameters
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"bytes"
 	"errors"
+	"math"
 	"github.com/yaricom/goNEAT/neat/utils"
 )
 
@@ -28,6 +29,11 @@ type Network struct {
 
 	// NNodes that connect network modules
 	control_nodes []*NNode
+
+	// The policy to handle activation values which are not finite numbers
+	NonFinitePolicy NonFinitePolicy
+	// The optional fixed preprocessor of sensor values, see SetPreprocessor
+	Preprocessor    *InputPreprocessor
 }
 
 // Creates new network
@@ -60,6 +66,9 @@ func (n *Network) FastNetworkSolver() (NetworkSolver, error) {
 	bias_list := make([]*NNode, 0)
 	hidn_list := make([]*NNode, 0)
 	for _, ne := range n.all_nodes {
+		if ne.IsMemory {
+			return nil, errors.New(fmt.Sprintf("Memory nodes are not supported by fast network solver: %s", ne))
+		}
 		switch ne.NeuronType {
 		case BiasNeuron:
 			biasNeuronCount += 1
@@ -133,8 +142,26 @@ func (n *Network) FastNetworkSolver() (NetworkSolver, error) {
 		modules[i] = &FastControlNode{InputIndxs:inputs, OutputIndxs:outputs, ActivationType:cn.ActivationType}
 	}
 
-	return NewFastModularNetworkSolver(biasNeuronCount, inputNeuronCount, outputNeuronCount, totalNeuronCount,
-		activations, connections, biases, modules), nil
+	solver := NewFastModularNetworkSolver(biasNeuronCount, inputNeuronCount, outputNeuronCount, totalNeuronCount,
+		activations, connections, biases, modules)
+	solver.NonFinitePolicy, solver.Preprocessor = n.NonFinitePolicy, n.Preprocessor
+
+	// set initial activations of neurons if any
+	var initial []float64
+	for _, ne := range n.all_nodes {
+		if ne.IsNeuron() && ne.InitialActivation != 0 {
+			if initial == nil {
+				initial = make([]float64, totalNeuronCount)
+			}
+			initial[neuronLookup[ne.Id]] = ne.InitialActivation
+		}
+	}
+	if initial != nil {
+		if err := solver.SetInitialSignals(initial); err != nil {
+			return nil, err
+		}
+	}
+	return solver, nil
 }
 
 func processList(startIndex int, nList []*NNode, activations[]utils.NodeActivationType, neuronLookup map[int]int) int {
@@ -151,6 +178,11 @@ func processIncomingConnections(nList []*NNode, biases []float64, neuronLookup m
 	for _, ne := range nList {
 		if targetIndex, ok := neuronLookup[ne.Id]; ok {
 			for _, in := range ne.Incoming {
+				if in.Delay > 0 || in.GateNode != nil {
+					err = errors.New(
+						fmt.Sprintf("Delayed or gated links are not supported by fast network solver: %s", in))
+					break
+				}
 				if sourceIndex, ok := neuronLookup[in.InNode.Id]; ok {
 					if in.InNode.NeuronType == BiasNeuron {
 						// store bias for target neuron
@@ -187,6 +219,9 @@ func (n *Network) Flush() (res bool, err error) {
 	// Flush back recursively
 	for _, node := range n.all_nodes {
 		node.Flushback()
+		for _, link := range node.Incoming {
+			link.flush()
+		}
 		err = node.FlushbackCheck()
 		if err != nil {
 			// failed - no need to continue
@@ -254,12 +289,12 @@ func (n *Network) ActivateSteps(max_steps int) (bool, error) {
 				for _, link := range np.Incoming {
 					// Handle possible time delays
 					if !link.IsTimeDelayed {
-						add_amount = link.Weight * link.InNode.GetActiveOut()
+						add_amount = link.Weight * link.relay(link.InNode.GetActiveOut())
 						if link.InNode.isActive || link.InNode.IsSensor() {
 							np.isActive = true
 						}
 					} else {
-						add_amount = link.Weight * link.InNode.GetActiveOutTd()
+						add_amount = link.Weight * link.relay(link.InNode.GetActiveOutTd())
 					}
 					np.ActivationSum += add_amount
 				} // End {for} over incoming links
@@ -276,6 +311,9 @@ func (n *Network) ActivateSteps(max_steps int) (bool, error) {
 					if err != nil {
 						return false, err
 					}
+					if err = guardActivation(&np.Activation, n.NonFinitePolicy); err != nil {
+						return false, err
+					}
 				}
 			}
 		}
@@ -288,12 +326,18 @@ func (n *Network) ActivateSteps(max_steps int) (bool, error) {
 			if err != nil {
 				return false, err
 			}
+			for _, out := range cn.Outgoing {
+				if err = guardActivation(&out.OutNode.Activation, n.NonFinitePolicy); err != nil {
+					return false, err
+				}
+			}
 			// mark control node as active
 			cn.isActive = true
 		}
 
 		one_time = true
 		abort_count += 1
+		countActivation()
 	}
 	return true, nil
 }
@@ -329,8 +373,14 @@ func (n *Network) Relax(maxSteps int, maxAllowedSignalDelta float64) (bool, erro
 	return false, errors.New("Relax Not Implemented")
 }
 
-// Takes an array of sensor values and loads it into SENSOR inputs ONLY
-func (n *Network) LoadSensors(sensors []float64) error {
+// Takes an array of sensor values and loads it into SENSOR inputs ONLY. If input preprocessor attached, the sensor
+// values are processed by it first.
+func (n *Network) LoadSensors(sensors []float64) (err error) {
+	if n.Preprocessor != nil {
+		if sensors, err = n.Preprocessor.Process(sensors); err != nil {
+			return err
+		}
+	}
 	counter := 0
 	if len(sensors) == len(n.inputs) {
 		// BIAS value provided as input
@@ -357,13 +407,68 @@ func (n *Network) LoadSensors(sensors []float64) error {
 
 // Read output values from the output nodes of the network
 func (n *Network) ReadOutputs() []float64 {
-	outs := make([]float64, len(n.Outputs))
+	return n.ReadOutputsInto(nil)
+}
+
+// Read output values from the output nodes of the network into provided slice and returns it resliced to the number
+// of outputs. The new slice allocated only if provided one has not enough capacity, thus reusing the same slice between
+// activations allows to avoid allocations.
+func (n *Network) ReadOutputsInto(outs []float64) []float64 {
+	if cap(outs) < len(n.Outputs) {
+		outs = make([]float64, len(n.Outputs))
+	}
+	outs = outs[:len(n.Outputs)]
 	for i, o := range n.Outputs {
 		outs[i] = o.Activation
 	}
 	return outs
 }
 
+// Read output values from the output nodes of the network normalized with softmax function, i.e. as probabilities
+// which sum up to one. Useful for classification tasks with one output per class.
+func (n *Network) ReadOutputsSoftmax() []float64 {
+	outs := n.ReadOutputs()
+	if len(outs) == 0 {
+		return outs
+	}
+	// subtract maximal value for numerical stability
+	max := outs[0]
+	for _, o := range outs {
+		max = math.Max(max, o)
+	}
+	sum := 0.0
+	for i, o := range outs {
+		outs[i] = math.Exp(o - max)
+		sum += outs[i]
+	}
+	for i := range outs {
+		outs[i] /= sum
+	}
+	return outs
+}
+
+// Returns index of the output node with the highest activation value or -1 if network has no outputs. If several
+// outputs has the same highest value the first one returned.
+func (n *Network) ReadOutputsArgmax() int {
+	index := -1
+	for i, o := range n.Outputs {
+		if index < 0 || o.Activation > n.Outputs[index].Activation {
+			index = i
+		}
+	}
+	return index
+}
+
+// Read output values from the output nodes of the network as binary values, i.e. output is true if its activation
+// value is greater or equal to the given threshold
+func (n *Network) ReadOutputsBinary(threshold float64) []bool {
+	outs := make([]bool, len(n.Outputs))
+	for i, o := range n.Outputs {
+		outs[i] = o.Activation >= threshold
+	}
+	return outs
+}
+
 // Counts the number of nodes in the net
 func (n *Network) NodeCount() int {
 	if len(n.control_nodes) == 0 {
@@ -393,35 +498,68 @@ func (n *Network) Complexity() int {
 	return n.NodeCount() + n.LinkCount()
 }
 
-// This checks a POTENTIAL link between a potential in_node
-// and potential out_node to see if it must be recurrent.
-// Use count and thresh to jump out in the case of an infinite loop.
-func (n *Network) IsRecurrent(in_node, out_node *NNode, count *int, thresh int) bool {
-	// Count the node as visited
-	*count++
+// Checks whether this network has recurrent topology, i.e. has links marked as recurrent or its forward links have
+// loops. The recurrent network needs more than one activation step per input to produce meaningful output.
+func (n *Network) IsRecurrent() bool {
+	// The states of nodes during the depth-first search: absent - not visited, true - on current search path,
+	// false - all its ancestors already visited
+	on_path := make(map[*NNode]bool)
+	var has_loop func(node *NNode) bool
+	has_loop = func(node *NNode) bool {
+		on_path[node] = true
+		for _, link := range node.Incoming {
+			if link.IsRecurrent {
+				return true
+			}
+			if in_path, visited := on_path[link.InNode]; in_path {
+				return true
+			} else if !visited && has_loop(link.InNode) {
+				return true
+			}
+		}
+		on_path[node] = false
+		return false
+	}
 
-	if *count > thresh {
-		return false // Short out the whole thing - loop detected
+	for _, node := range n.all_nodes {
+		if _, visited := on_path[node]; !visited && has_loop(node) {
+			return true
+		}
 	}
+	return false
+}
 
-	if in_node == out_node {
+// Checks whether the signal can flow from one node to another through the forward (not recurrent) links of this network.
+// It can be used to check whether POTENTIAL link between in_node and out_node must be recurrent: it is the case when
+// path exists from out_node to in_node. Every node considered to have path to itself.
+func (n *Network) PathExists(from, to *NNode) bool {
+	if from == to {
 		return true
-	} else {
-		// Check back on all links ...
-		for _, link := range in_node.Incoming {
-			// But skip links that are already recurrent -
-			// We want to check back through the forward flow of signals only
-			if !link.IsRecurrent {
-				if n.IsRecurrent(link.InNode, out_node, count, thresh) {
-					return true
-				}
+	}
+	// search backward from the destination node through incoming links
+	visited := map[*NNode]bool{to:true}
+	stack := []*NNode{to}
+	for len(stack) > 0 {
+		node := stack[len(stack) - 1]
+		stack = stack[:len(stack) - 1]
+		for _, link := range node.Incoming {
+			// skip links that are already recurrent - we check the forward flow of signals only
+			if link.IsRecurrent || visited[link.InNode] {
+				continue
+			}
+			if link.InNode == from {
+				return true
 			}
+			visited[link.InNode] = true
+			stack = append(stack, link.InNode)
 		}
 	}
 	return false
 }
 
-// Find the maximum number of neurons between an output and an input
+// Find the maximum number of neurons between an output and an input. Only forward (not recurrent) links considered and
+// the links closing loops are skipped, thus it is safe to call for any network topology. The result can be used as
+// the number of activation steps required to propagate input signals to the outputs.
 func (n *Network) MaxDepth() (int, error) {
 	if len(n.control_nodes) > 0 {
 		return -1, errors.New("unsupported for modular networks")
@@ -431,13 +569,11 @@ func (n *Network) MaxDepth() (int, error) {
 		return 1, nil // just one layer depth
 	}
 
+	depths := make(map[*NNode]int)
+	on_path := make(map[*NNode]bool)
 	max := 0 // The max depth
 	for _, node := range n.Outputs {
-		curr_depth, err := node.Depth(0)
-		if err != nil {
-			return curr_depth, err
-		}
-		if curr_depth > max {
+		if curr_depth := forwardDepth(node, depths, on_path); curr_depth > max {
 			max = curr_depth
 		}
 	}
@@ -445,6 +581,30 @@ func (n *Network) MaxDepth() (int, error) {
 	return max, nil
 }
 
+// Finds the greatest number of forward links between given node and sensors. The depths of already processed nodes are
+// memorized and the nodes on the current search path are skipped to avoid infinite recursion.
+func forwardDepth(node *NNode, depths map[*NNode]int, on_path map[*NNode]bool) int {
+	if depth, ok := depths[node]; ok {
+		return depth
+	}
+	if node.IsSensor() {
+		return 0
+	}
+	on_path[node] = true
+	max := 0
+	for _, link := range node.Incoming {
+		if link.IsRecurrent || on_path[link.InNode] {
+			continue
+		}
+		if depth := forwardDepth(link.InNode, depths, on_path) + 1; depth > max {
+			max = depth
+		}
+	}
+	delete(on_path, node)
+	depths[node] = max
+	return max
+}
+
 // Returns all nodes in the network
 func (n *Network) AllNodes() []*NNode {
 	return n.all_nodes
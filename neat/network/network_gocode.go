@@ -0,0 +1,348 @@
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"math"
+	"sort"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The source code of activation function to be included into generated code
+type goCodeActivator struct {
+	// The name of generated function
+	name     string
+	// The body of function with input argument x (or inputs slice for module activators)
+	body     string
+	// Indicates whether function uses math package
+	usesMath bool
+	// Indicates whether function is module activator
+	module   bool
+}
+
+// The source code of supported neuron activation functions, the same as implementations in utils package
+var goCodeNodeActivators = map[utils.NodeActivationType]goCodeActivator{
+	utils.SigmoidPlainActivation:{"sigmoidPlain",
+		"return (1 / (1 + math.Exp(-x)))", true, false},
+	utils.SigmoidReducedActivation:{"sigmoidReduced",
+		"return (1 / (1 + math.Exp(-0.5 * x)))", true, false},
+	utils.SigmoidSteepenedActivation:{"sigmoidSteepened",
+		"return 1.0 / (1.0 + math.Exp(-4.924273 * x))", true, false},
+	utils.SigmoidBipolarActivation:{"sigmoidBipolar",
+		"return (2.0 / (1.0 + math.Exp(-4.924273 * x))) - 1.0", true, false},
+	utils.SigmoidApproximationActivation:{"sigmoidApproximation", `four, one_32nd := float64(4.0), float64(0.03125)
+	if x < -4.0 {
+		return 0.0
+	} else if x < 0.0 {
+		return (x + four) * (x + four) * one_32nd
+	} else if x < 4.0 {
+		return 1.0 - (x - four) * (x - four) * one_32nd
+	}
+	return 1.0`, false, false},
+	utils.SigmoidSteepenedApproximationActivation:{"sigmoidSteepenedApproximation", `one, one_half := 1.0, 0.5
+	if x < -1.0 {
+		return 0.0
+	} else if x < 0.0 {
+		return (x + one) * (x + one) * one_half
+	} else if x < 1.0 {
+		return 1.0 - (x - one) * (x - one) * one_half
+	}
+	return 1.0`, false, false},
+	utils.SigmoidInverseAbsoluteActivation:{"sigmoidInverseAbsolute",
+		"return 0.5 + (x / (1.0 + math.Abs(x))) * 0.5", true, false},
+	utils.SigmoidLeftShiftedActivation:{"sigmoidLeftShifted",
+		"return 1.0 / (1.0 + math.Exp(-x - 2.4621365))", true, false},
+	utils.SigmoidLeftShiftedSteepenedActivation:{"sigmoidLeftShiftedSteepened",
+		"return 1.0 / (1.0 + math.Exp(-(4.924273 * x + 2.4621365)))", true, false},
+	utils.SigmoidRightShiftedSteepenedActivation:{"sigmoidRightShiftedSteepened",
+		"return 1.0 / (1.0 + math.Exp(-(4.924273 * x - 2.4621365)))", true, false},
+	utils.TanhActivation:{"tanh",
+		"return math.Tanh(0.9 * x)", true, false},
+	utils.GaussianBipolarActivation:{"gaussianBipolar",
+		"return 2.0 * math.Exp(-math.Pow(x * 2.5, 2.0)) - 1.0", true, false},
+	utils.LinearActivation:{"linear",
+		"return x", false, false},
+	utils.LinearAbsActivation:{"linearAbs",
+		"return math.Abs(x)", true, false},
+	utils.LinearClippedActivation:{"linearClipped", `if x < -1.0 {
+		return -1.0
+	}
+	if x > 1.0 {
+		return 1.0
+	}
+	return x`, false, false},
+	utils.NullActivation:{"null",
+		"return 0.0", false, false},
+	utils.SignActivation:{"sign", `if math.IsNaN(x) || x == 0.0 {
+		return 0.0
+	} else if math.Signbit(x) {
+		return -1.0
+	}
+	return 1.0`, true, false},
+	utils.SineActivation:{"sine",
+		"return math.Sin(2.0 * x)", true, false},
+	utils.StepActivation:{"step", `if math.Signbit(x) {
+		return 0.0
+	}
+	return 1.0`, true, false},
+}
+
+// The source code of supported modules activation functions, the same as implementations in utils package
+var goCodeModuleActivators = map[utils.NodeActivationType]goCodeActivator{
+	utils.MultiplyModuleActivation:{"multiplyModule", `ret := 1.0
+	for _, v := range inputs {
+		ret *= v
+	}
+	return []float64{ret}`, false, true},
+	utils.MaxModuleActivation:{"maxModule", `max := float64(math.MinInt64)
+	for _, v := range inputs {
+		max = math.Max(max, v)
+	}
+	return []float64{max}`, true, true},
+	utils.MinModuleActivation:{"minModule", `min := math.MaxFloat64
+	for _, v := range inputs {
+		min = math.Min(min, v)
+	}
+	return []float64{min}`, true, true},
+}
+
+// The maximal number of activation waves to propagate until all outputs are active, the same as in Network.Activate
+const goCodeMaxActivationAttempts = 20
+
+// Writes Go source code of package with given name implementing the function Predict(inputs []float64) []float64
+// which computes outputs of this network for given inputs. The generated code has no dependencies except standard
+// library and reproduces this network activation: the sensors are loaded the same way as by LoadSensors, after that
+// the network is activated given number of times as by Activate (e.g. network depth times for feed-forward networks)
+// starting from flushed state, i.e. the neurons start from their initial activations. The handling of values which are not finite numbers is not reproduced.
+func (n *Network) WriteGoCode(w io.Writer, package_name string, steps int) error {
+	if !token.IsIdentifier(package_name) || token.Lookup(package_name).IsKeyword() {
+		return errors.New(fmt.Sprintf("invalid package name: %q", package_name))
+	}
+	if steps <= 0 {
+		return errors.New(fmt.Sprintf("the number of activation steps should be positive, found: %d", steps))
+	}
+
+	// index nodes in order of network activation
+	index := make(map[*NNode]int, len(n.all_nodes))
+	for i, node := range n.all_nodes {
+		index[node] = i
+	}
+	lookup := func(node *NNode) (int, error) {
+		if i, ok := index[node]; ok {
+			return i, nil
+		}
+		return -1, errors.New(fmt.Sprintf("node [%d] is not in network", node.Id))
+	}
+	weight := func(l *Link) (string, error) {
+		if math.IsNaN(l.Weight) || math.IsInf(l.Weight, 0) {
+			return "", errors.New(fmt.Sprintf("link weight is not a finite number: %s", l))
+		}
+		return fmt.Sprintf("%v", l.Weight), nil
+	}
+
+	// find used activation functions
+	used := make(map[string]goCodeActivator)
+	node_activators := make(map[*NNode]goCodeActivator)
+	for _, node := range n.all_nodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		activator, ok := goCodeNodeActivators[node.ActivationType]
+		if !ok {
+			return errors.New(fmt.Sprintf("unsupported activation type of node [%d]: %d", node.Id, node.ActivationType))
+		}
+		node_activators[node] = activator
+		used[activator.name] = activator
+	}
+	for _, cn := range n.control_nodes {
+		activator, ok := goCodeModuleActivators[cn.ActivationType]
+		if !ok {
+			return errors.New(fmt.Sprintf("unsupported activation type of module [%d]: %d", cn.Id, cn.ActivationType))
+		}
+		if len(cn.Outgoing) != 1 {
+			return errors.New(fmt.Sprintf("module [%d] should have exactly one output, found: %d", cn.Id, len(cn.Outgoing)))
+		}
+		node_activators[cn] = activator
+		used[activator.name] = activator
+	}
+	names := make([]string, 0, len(used))
+	uses_math := false
+	for name, activator := range used {
+		names = append(names, name)
+		uses_math = uses_math || activator.usesMath
+	}
+	sort.Strings(names)
+
+	nodes_count := len(n.all_nodes)
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "// Code generated by goNEAT from network %d. DO NOT EDIT.\n\n", n.Id)
+	fmt.Fprintf(b, "// Package %s implements the neural network evolved by goNEAT.\n", package_name)
+	fmt.Fprintf(b, "package %s\n\n", package_name)
+	if uses_math {
+		fmt.Fprint(b, "import \"math\"\n\n")
+	}
+	fmt.Fprint(b, "// The number of network activations per prediction\n")
+	fmt.Fprintf(b, "const activationSteps = %d\n\n", steps)
+	fmt.Fprint(b, "// The state of network nodes: the current and previous activations and whether node was activated\n")
+	fmt.Fprint(b, "type networkState struct {\n")
+	fmt.Fprintf(b, "\tact    [%d]float64\n", nodes_count)
+	fmt.Fprintf(b, "\tlast   [%d]float64\n", nodes_count)
+	fmt.Fprintf(b, "\tactive [%d]bool\n", nodes_count)
+	fmt.Fprint(b, "}\n\n")
+
+	// the prediction function
+	sensors, input_sensors := make([]int, 0), make([]int, 0)
+	for _, node := range n.inputs {
+		i, err := lookup(node)
+		if err != nil {
+			return err
+		}
+		if node.IsSensor() {
+			sensors = append(sensors, i)
+		}
+		if node.NeuronType == InputNeuron {
+			input_sensors = append(input_sensors, i)
+		}
+	}
+	fmt.Fprintf(b, "// Returns the outputs of network for given inputs. The inputs may include the values of bias nodes, if" +
+		" they are\n// not included the bias value is 1.0. The network has %d input and %d bias nodes, and %d outputs.\n",
+		len(input_sensors), len(sensors) - len(input_sensors), len(n.Outputs))
+	fmt.Fprint(b, "func Predict(inputs []float64) []float64 {\n")
+	fmt.Fprint(b, "\ts := &networkState{}\n")
+	for _, node := range n.all_nodes {
+		if node.IsNeuron() && node.InitialActivation != 0 {
+			if math.IsNaN(node.InitialActivation) || math.IsInf(node.InitialActivation, 0) {
+				return errors.New(fmt.Sprintf("initial activation is not a finite number: %s", node))
+			}
+			i := index[node]
+			fmt.Fprintf(b, "\ts.act[%d], s.last[%d] = %v, %v\n", i, i, node.InitialActivation, node.InitialActivation)
+		}
+	}
+	fmt.Fprintf(b, "\tif len(inputs) == %d {\n", len(sensors))
+	for i, node := range sensors {
+		fmt.Fprintf(b, "\t\ts.load(%d, inputs[%d])\n", node, i)
+	}
+	fmt.Fprint(b, "\t} else {\n")
+	counter := 0
+	for _, node := range n.inputs {
+		i, _ := lookup(node)
+		if node.NeuronType == InputNeuron {
+			fmt.Fprintf(b, "\t\ts.load(%d, inputs[%d])\n", i, counter)
+			counter++
+		} else {
+			fmt.Fprintf(b, "\t\ts.load(%d, 1.0)\n", i)
+		}
+	}
+	fmt.Fprint(b, "\t}\n")
+	fmt.Fprint(b, "\tfor i := 0; i < activationSteps; i++ {\n\t\ts.activate()\n\t}\n")
+	fmt.Fprint(b, "\treturn []float64{")
+	for i, node := range n.Outputs {
+		o, err := lookup(node)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprint(b, ", ")
+		}
+		fmt.Fprintf(b, "s.act[%d]", o)
+	}
+	fmt.Fprint(b, "}\n}\n\n")
+
+	fmt.Fprint(b, "// Loads sensor value into the node with given index\n")
+	fmt.Fprint(b, "func (s *networkState) load(i int, value float64) {\n")
+	fmt.Fprint(b, "\ts.last[i], s.act[i], s.active[i] = s.act[i], value, true\n}\n\n")
+
+	fmt.Fprint(b, "// Sets activation of the node with given index\n")
+	fmt.Fprint(b, "func (s *networkState) set(i int, value float64) {\n")
+	fmt.Fprint(b, "\ts.last[i], s.act[i] = s.act[i], value\n}\n\n")
+
+	// the activation wave propagation
+	fmt.Fprint(b, "// Propagates activation waves through the network until all outputs are active, at least once\n")
+	fmt.Fprint(b, "func (s *networkState) activate() {\n")
+	fmt.Fprintf(b, "\tfor attempt := 0; attempt < %d && (attempt == 0 || s.outputIsOff()); attempt++ {\n",
+		goCodeMaxActivationAttempts)
+	fmt.Fprintf(b, "\t\tvar sum [%d]float64\n", nodes_count)
+	for _, node := range n.all_nodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		if node.IsMemory {
+			return errors.New(fmt.Sprintf("memory nodes are not supported: %s", node))
+		}
+		i := index[node]
+		for _, l := range node.Incoming {
+			if l.Delay > 0 || l.GateNode != nil {
+				return errors.New(fmt.Sprintf("delayed or gated links are not supported: %s", l))
+			}
+			in, err := lookup(l.InNode)
+			if err != nil {
+				return err
+			}
+			w, err := weight(l)
+			if err != nil {
+				return err
+			}
+			if !l.IsTimeDelayed {
+				// the explicit conversion prevents fused multiply-add to get the same result as network
+				fmt.Fprintf(b, "\t\tsum[%d] += float64(%s * s.act[%d])\n", i, w, in)
+				fmt.Fprintf(b, "\t\ts.active[%d] = s.active[%d] || s.active[%d]\n", i, i, in)
+			} else {
+				fmt.Fprintf(b, "\t\tsum[%d] += float64(%s * s.last[%d])\n", i, w, in)
+			}
+		}
+	}
+	for _, node := range n.all_nodes {
+		if node.IsNeuron() {
+			i := index[node]
+			fmt.Fprintf(b, "\t\tif s.active[%d] {\n\t\t\ts.set(%d, %s(sum[%d]))\n\t\t}\n",
+				i, i, node_activators[node].name, i)
+		}
+	}
+	for _, cn := range n.control_nodes {
+		out, err := lookup(cn.Outgoing[0].OutNode)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "\t\ts.set(%d, %s(", out, node_activators[cn].name)
+		for j, l := range cn.Incoming {
+			in, err := lookup(l.InNode)
+			if err != nil {
+				return err
+			}
+			if j > 0 {
+				fmt.Fprint(b, ", ")
+			}
+			fmt.Fprintf(b, "s.act[%d]", in)
+		}
+		fmt.Fprintf(b, ")[0])\n\t\ts.active[%d] = true\n", out)
+	}
+	fmt.Fprint(b, "\t}\n}\n\n")
+
+	fmt.Fprint(b, "// Returns true if any output node was not activated yet\n")
+	fmt.Fprint(b, "func (s *networkState) outputIsOff() bool {\n\treturn ")
+	for i, node := range n.Outputs {
+		if i > 0 {
+			fmt.Fprint(b, " || ")
+		}
+		fmt.Fprintf(b, "!s.active[%d]", index[node])
+	}
+	if len(n.Outputs) == 0 {
+		fmt.Fprint(b, "false")
+	}
+	fmt.Fprint(b, "\n}\n")
+
+	// the activation functions
+	for _, name := range names {
+		activator := used[name]
+		if activator.module {
+			fmt.Fprintf(b, "\nfunc %s(inputs ...float64) []float64 {\n\t%s\n}\n", name, activator.body)
+		} else {
+			fmt.Fprintf(b, "\nfunc %s(x float64) float64 {\n\t%s\n}\n", name, activator.body)
+		}
+	}
+
+	return b.Flush()
+}
@@ -0,0 +1,157 @@
+package network
+
+import (
+	"testing"
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"strings"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Parses and type checks generated source code, returns the parsed file
+func checkGeneratedCode(t *testing.T, source string) *ast.File {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "predict.go", source, parser.ParseComments)
+	if err != nil {
+		t.Error("Failed to parse generated code", err, source)
+		return nil
+	}
+	conf := types.Config{Importer:importer.ForCompiler(fset, "source", nil)}
+	if _, err = conf.Check(file.Name.Name, fset, []*ast.File{file}, nil); err != nil {
+		t.Error("Failed to type check generated code", err, source)
+	}
+	for _, imp := range file.Imports {
+		if imp.Path.Value != "\"math\"" {
+			t.Error("Unexpected import in generated code", imp.Path.Value)
+		}
+	}
+	return file
+}
+
+func TestNetwork_WriteGoCode(t *testing.T) {
+	net := buildNetwork()
+	var buf bytes.Buffer
+	if err := net.WriteGoCode(&buf, "predict", 3); err != nil {
+		t.Error(err)
+		return
+	}
+	file := checkGeneratedCode(t, buf.String())
+	if file == nil {
+		return
+	}
+	if file.Name.Name != "predict" {
+		t.Error("Wrong package name", file.Name.Name)
+	}
+	if obj := file.Scope.Lookup("Predict"); obj == nil || obj.Kind != ast.Fun {
+		t.Error("Predict function not found")
+	}
+	for _, expected := range []string{"const activationSteps = 3", "return []float64{s.act[6], s.act[7]}",
+		"sum[3] += float64(15 * s.act[0])", "func sigmoidSteepened(x float64) float64"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Error("Generated code should contain", expected)
+		}
+	}
+}
+
+func TestNetwork_WriteGoCode_initialActivation(t *testing.T) {
+	net := buildRecurrentNetwork(-3.0)
+	var buf bytes.Buffer
+	if err := net.WriteGoCode(&buf, "predict", 2); err != nil {
+		t.Error(err)
+		return
+	}
+	if checkGeneratedCode(t, buf.String()) == nil {
+		return
+	}
+	if expected := "s.act[1], s.last[1] = -3, -3"; !strings.Contains(buf.String(), expected) {
+		t.Error("Generated code should contain", expected)
+	}
+}
+
+func TestNetwork_WriteGoCode_modular(t *testing.T) {
+	net := buildModularNetwork()
+	var buf bytes.Buffer
+	if err := net.WriteGoCode(&buf, "predict", 3); err != nil {
+		t.Error(err)
+		return
+	}
+	checkGeneratedCode(t, buf.String())
+	for _, expected := range []string{"s.set(5, multiplyModule(s.act[3], s.act[4])[0])", "func linear(x float64) float64",
+		"func null(x float64) float64"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Error("Generated code should contain", expected)
+		}
+	}
+	// the multiply and linear activations don't use math package
+	if strings.Contains(buf.String(), "import \"math\"") {
+		t.Error("The math package should not be imported")
+	}
+}
+
+func TestNetwork_WriteGoCode_activators(t *testing.T) {
+	// all supported activation functions should compile
+	for a_type := range goCodeNodeActivators {
+		net := buildNetwork()
+		for _, node := range net.all_nodes {
+			if node.IsNeuron() {
+				node.ActivationType = a_type
+			}
+		}
+		var buf bytes.Buffer
+		if err := net.WriteGoCode(&buf, "predict", 1); err != nil {
+			t.Error(err)
+			continue
+		}
+		checkGeneratedCode(t, buf.String())
+	}
+	for _, a_type := range []utils.NodeActivationType{utils.MaxModuleActivation, utils.MinModuleActivation} {
+		net := buildModularNetwork()
+		net.control_nodes[0].ActivationType = a_type
+		var buf bytes.Buffer
+		if err := net.WriteGoCode(&buf, "predict", 1); err != nil {
+			t.Error(err)
+			continue
+		}
+		checkGeneratedCode(t, buf.String())
+	}
+}
+
+func TestNetwork_WriteGoCode_errors(t *testing.T) {
+	var buf bytes.Buffer
+	net := buildNetwork()
+	if err := net.WriteGoCode(&buf, "func", 1); err == nil {
+		t.Error("Error expected for keyword package name")
+	}
+	if err := net.WriteGoCode(&buf, "my-package", 1); err == nil {
+		t.Error("Error expected for invalid package name")
+	}
+	if err := net.WriteGoCode(&buf, "predict", 0); err == nil {
+		t.Error("Error expected for zero activation steps")
+	}
+
+	net.all_nodes[3].ActivationType = utils.MultiplyModuleActivation
+	if err := net.WriteGoCode(&buf, "predict", 1); err == nil {
+		t.Error("Error expected for unsupported activation type of node")
+	}
+
+	net = buildNetwork()
+	net.all_nodes[3].Incoming[0].Weight = math.NaN()
+	if err := net.WriteGoCode(&buf, "predict", 1); err == nil {
+		t.Error("Error expected for link weight which is not a number")
+	}
+
+	if err := buildDelayedNetwork(1, true).WriteGoCode(&buf, "predict", 1); err == nil {
+		t.Error("Error expected for delayed and gated link")
+	}
+
+	net = buildNetwork()
+	net.all_nodes[3].IsMemory = true
+	if err := net.WriteGoCode(&buf, "predict", 1); err == nil {
+		t.Error("Error expected for memory node")
+	}
+}
@@ -0,0 +1,243 @@
+package network
+
+import (
+	"fmt"
+	"errors"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The feed-forward network prepared for reverse-mode gradient computation (backpropagation) over its evolved topology.
+// It allows to fine-tune weights of links by gradient descent, e.g. to refine champion after evolution. The network is
+// activated in one pass over the neurons sorted in topological order, which gives the same outputs as activation of
+// Network with the number of steps not less than its depth. The weights of all links, including ones from bias
+// neurons, are the parameters to be optimized and are updated in place in the links of network.
+type GradientNetwork struct {
+	// The network which weights are optimized
+	network     *Network
+
+	// The nodes sorted in topological order, i.e. each node follows the sources of all its incoming links
+	nodes       []*NNode
+	// The links of network, which weights are parameters
+	links       []*Link
+	// The indexes of source nodes per link
+	sources     []int
+	// The indexes of incoming links per node
+	incoming    [][]int
+	// The indexes of input sensors as ordered in network inputs
+	inputs      []int
+	// The indexes of output nodes
+	outputs     []int
+
+	// The sums of incoming signals and activations of nodes computed by the last forward pass
+	sums        []float64
+	activations []float64
+	// The derivatives of loss by activations of nodes computed by the last backward pass
+	deltas      []float64
+	// The derivatives of loss by weights of links accumulated by backward passes
+	gradients   []float64
+}
+
+// Creates gradient network for this network. Returns error if network is recurrent or modular, has delayed or gated
+// links, memory cells, or neurons with activation functions which have no derivative.
+func (n *Network) GradientNetwork() (*GradientNetwork, error) {
+	if len(n.control_nodes) > 0 {
+		return nil, errors.New("gradient unsupported for modular networks")
+	}
+	if n.IsRecurrent() {
+		return nil, errors.New("gradient unsupported for recurrent networks")
+	}
+	g := GradientNetwork{network:n}
+
+	// sort nodes in topological order by depth-first search through incoming links
+	indexes := make(map[*NNode]int)
+	var visit func(node *NNode)
+	visit = func(node *NNode) {
+		if _, ok := indexes[node]; ok {
+			return
+		}
+		for _, link := range node.Incoming {
+			visit(link.InNode)
+		}
+		indexes[node] = len(g.nodes)
+		g.nodes = append(g.nodes, node)
+	}
+	for _, node := range n.inputs {
+		visit(node)
+	}
+	for _, node := range n.all_nodes {
+		visit(node)
+	}
+
+	g.incoming = make([][]int, len(g.nodes))
+	for i, node := range g.nodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		if node.IsMemory {
+			return nil, errors.New(fmt.Sprintf("gradient unsupported for memory cell: %s", node))
+		}
+		if len(node.Incoming) > 0 {
+			if _, err := utils.NodeActivators.DerivativeByType(0.0, node.Params, node.ActivationType); err != nil {
+				return nil, err
+			}
+		}
+		for _, link := range node.Incoming {
+			if link.IsTimeDelayed || link.Delay > 0 || link.GateNode != nil {
+				return nil, errors.New(fmt.Sprintf("gradient unsupported for delayed or gated link: %s", link))
+			}
+			g.incoming[i] = append(g.incoming[i], len(g.links))
+			g.links = append(g.links, link)
+			g.sources = append(g.sources, indexes[link.InNode])
+		}
+	}
+	for _, node := range n.inputs {
+		g.inputs = append(g.inputs, indexes[node])
+	}
+	for _, node := range n.Outputs {
+		g.outputs = append(g.outputs, indexes[node])
+	}
+
+	g.sums = make([]float64, len(g.nodes))
+	g.activations = make([]float64, len(g.nodes))
+	g.deltas = make([]float64, len(g.nodes))
+	g.gradients = make([]float64, len(g.links))
+	return &g, nil
+}
+
+// Activates network with provided sensors values and returns its outputs. The sensors values are loaded as by
+// Network.LoadSensors, i.e. the value of bias may be omitted. The sums and activations of nodes are stored to be used
+// by the following backward pass.
+func (g *GradientNetwork) Forward(sensors []float64) ([]float64, error) {
+	counter := 0
+	for _, i := range g.inputs {
+		node := g.nodes[i]
+		if len(sensors) == len(g.inputs) || node.NeuronType == InputNeuron {
+			if counter >= len(sensors) {
+				return nil, NetErrUnsupportedSensorsArraySize
+			}
+			g.activations[i] = sensors[counter]
+			counter++
+		} else {
+			g.activations[i] = 1.0 // default BIAS value
+		}
+	}
+	if counter != len(sensors) {
+		return nil, NetErrUnsupportedSensorsArraySize
+	}
+
+	for i, node := range g.nodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		if len(g.incoming[i]) == 0 {
+			// the neuron without incoming links is never activated
+			g.sums[i], g.activations[i] = 0.0, node.InitialActivation
+			continue
+		}
+		sum := 0.0
+		for _, l := range g.incoming[i] {
+			sum += g.links[l].Weight * g.activations[g.sources[l]]
+		}
+		out, err := utils.NodeActivators.ActivateByType(sum, node.Params, node.ActivationType)
+		if err != nil {
+			return nil, err
+		}
+		if err = guardActivation(&out, g.network.NonFinitePolicy); err != nil {
+			return nil, err
+		}
+		g.sums[i], g.activations[i] = sum, out
+	}
+	countActivation()
+
+	outputs := make([]float64, len(g.outputs))
+	for j, i := range g.outputs {
+		outputs[j] = g.activations[i]
+	}
+	return outputs, nil
+}
+
+// Propagates provided derivatives of loss by outputs of the last forward pass backward through the network and adds
+// the derivatives of loss by weights of links to accumulated gradients. Returns error if number of derivatives differs
+// from the number of outputs.
+func (g *GradientNetwork) Backward(output_gradients []float64) error {
+	if len(output_gradients) != len(g.outputs) {
+		return errors.New(fmt.Sprintf("the number of output gradients %d differs from the number of outputs %d",
+			len(output_gradients), len(g.outputs)))
+	}
+	for i := range g.deltas {
+		g.deltas[i] = 0.0
+	}
+	for j, i := range g.outputs {
+		g.deltas[i] += output_gradients[j]
+	}
+	for i := len(g.nodes) - 1; i >= 0; i-- {
+		if len(g.incoming[i]) == 0 || g.deltas[i] == 0.0 {
+			continue
+		}
+		node := g.nodes[i]
+		derivative, err := utils.NodeActivators.DerivativeByType(g.sums[i], node.Params, node.ActivationType)
+		if err != nil {
+			return err
+		}
+		delta := g.deltas[i] * derivative
+		for _, l := range g.incoming[i] {
+			g.gradients[l] += delta * g.activations[g.sources[l]]
+			g.deltas[g.sources[l]] += delta * g.links[l].Weight
+		}
+	}
+	return nil
+}
+
+// Returns the links of network which weights are optimized, in the same order as weights and gradients
+func (g *GradientNetwork) Links() []*Link {
+	return g.links
+}
+
+// Returns the gradients of loss by weights of links accumulated by backward passes since the last reset
+func (g *GradientNetwork) Gradients() []float64 {
+	return g.gradients
+}
+
+// Resets accumulated gradients to zero
+func (g *GradientNetwork) ZeroGradients() {
+	for i := range g.gradients {
+		g.gradients[i] = 0.0
+	}
+}
+
+// Returns the copy of weights of links
+func (g *GradientNetwork) Weights() []float64 {
+	weights := make([]float64, len(g.links))
+	for i, link := range g.links {
+		weights[i] = link.Weight
+	}
+	return weights
+}
+
+// Sets weights of links. Returns error if number of weights differs from the number of links.
+func (g *GradientNetwork) SetWeights(weights []float64) error {
+	if len(weights) != len(g.links) {
+		return errors.New(fmt.Sprintf("the number of weights %d differs from the number of links %d",
+			len(weights), len(g.links)))
+	}
+	for i, link := range g.links {
+		link.Weight = weights[i]
+	}
+	return nil
+}
+
+// Updates weights of links by provided optimizer with the accumulated gradients scaled by given factor, e.g. one
+// divided by the number of samples in batch to get the mean gradient, and resets gradients afterwards.
+func (g *GradientNetwork) Step(optimizer Optimizer, scale float64) error {
+	if scale != 1.0 {
+		for i := range g.gradients {
+			g.gradients[i] *= scale
+		}
+	}
+	weights := g.Weights()
+	if err := optimizer.Update(weights, g.gradients); err != nil {
+		return err
+	}
+	g.ZeroGradients()
+	return g.SetWeights(weights)
+}
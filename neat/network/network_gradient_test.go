@@ -0,0 +1,174 @@
+package network
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Creates small feed-forward network with mixed differentiable activation functions and bias
+func buildGradientNetwork() *Network {
+	netw := buildBenchmarkNetwork(3, 4, 2)
+	activations := []utils.NodeActivationType{utils.TanhActivation, utils.SigmoidPlainActivation,
+		utils.SigmoidSteepenedActivation, utils.LinearActivation, utils.SineActivation, utils.SigmoidBipolarActivation}
+	for i, node := range netw.AllNodes()[3:] {
+		node.ActivationType = activations[i]
+	}
+	// the last input is bias
+	netw.AllNodes()[2].NeuronType = BiasNeuron
+	return netw
+}
+
+func TestNetwork_GradientNetwork_Forward(t *testing.T) {
+	for _, netw := range []*Network{buildNetwork(), buildGradientNetwork()} {
+		grad, err := netw.GradientNetwork()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		outputs, err := grad.Forward([]float64{0.5, -0.3})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		// the single pass gives the same outputs as activation of network by its depth
+		depth, _ := netw.MaxDepth()
+		netw.LoadSensors([]float64{0.5, -0.3})
+		if _, err = netw.ForwardSteps(depth); err != nil {
+			t.Error(err)
+			return
+		}
+		for i, out := range netw.ReadOutputs() {
+			if math.Abs(out - outputs[i]) > 1e-12 {
+				t.Error("Wrong output at:", i, outputs[i], out)
+			}
+		}
+	}
+
+	grad, _ := buildNetwork().GradientNetwork()
+	if _, err := grad.Forward([]float64{1.0}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong sensors array size", err)
+	}
+	if _, err := grad.Forward([]float64{1.0, 2.0, 1.0}); err != nil {
+		t.Error("Sensors with bias value expected to be loaded", err)
+	}
+}
+
+func TestGradientNetwork_Backward(t *testing.T) {
+	netw := buildGradientNetwork()
+	grad, err := netw.GradientNetwork()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	sensors, coefficients := []float64{0.7, -0.4}, []float64{1.5, -0.8}
+	// the loss is linear combination of outputs, thus its derivatives by outputs are the coefficients
+	loss := func() float64 {
+		outputs, err := grad.Forward(sensors)
+		if err != nil {
+			t.Error(err)
+		}
+		return coefficients[0] * outputs[0] + coefficients[1] * outputs[1]
+	}
+	loss()
+	if err = grad.Backward(coefficients); err != nil {
+		t.Error(err)
+		return
+	}
+	gradients := append([]float64(nil), grad.Gradients()...)
+	if len(gradients) != netw.LinkCount() {
+		t.Error("Wrong number of gradients", len(gradients))
+		return
+	}
+	h := 1e-6
+	for i, link := range grad.Links() {
+		weight := link.Weight
+		link.Weight = weight + h
+		upper := loss()
+		link.Weight = weight - h
+		lower := loss()
+		link.Weight = weight
+		if numeric := (upper - lower) / (2 * h); math.Abs(numeric - gradients[i]) > 1e-6 {
+			t.Error("Wrong gradient of link:", i, gradients[i], numeric)
+		}
+	}
+
+	// gradients accumulated until reset
+	loss()
+	grad.Backward(coefficients)
+	if grad.Gradients()[0] != 2 * gradients[0] {
+		t.Error("Gradients should be accumulated", grad.Gradients()[0], gradients[0])
+	}
+	grad.ZeroGradients()
+	for _, g := range grad.Gradients() {
+		if g != 0.0 {
+			t.Error("Gradients should be reset")
+			break
+		}
+	}
+	if err = grad.Backward([]float64{1.0}); err == nil {
+		t.Error("Error expected for wrong number of output gradients")
+	}
+}
+
+func TestGradientNetwork_Step(t *testing.T) {
+	for _, optimizer := range []Optimizer{NewSGDOptimizer(0.1, 0.5), NewAdamOptimizer(0.01)} {
+		netw := buildGradientNetwork()
+		grad, err := netw.GradientNetwork()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		// fit outputs to constant targets by mean squared error
+		sensors, targets := []float64{0.2, 0.9}, []float64{0.3, -0.2}
+		mse := func() float64 {
+			outputs, _ := grad.Forward(sensors)
+			res := 0.0
+			for i, out := range outputs {
+				res += (out - targets[i]) * (out - targets[i])
+				outputs[i] = 2.0 * (out - targets[i])
+			}
+			grad.Backward(outputs)
+			return res
+		}
+		initial := mse()
+		grad.ZeroGradients()
+		final := initial
+		for i := 0; i < 100; i++ {
+			final = mse()
+			if err = grad.Step(optimizer, 1.0); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if final >= initial * 0.1 {
+			t.Error("The loss should decrease", initial, final)
+		}
+		// the weights are updated in links of network
+		if weights := grad.Weights(); weights[0] != netw.AllNodes()[3].Incoming[0].Weight {
+			t.Error("The weights should be stored in network links")
+		}
+	}
+
+	grad, _ := buildNetwork().GradientNetwork()
+	if err := grad.SetWeights([]float64{1.0}); err == nil {
+		t.Error("Error expected for wrong number of weights")
+	}
+}
+
+func TestNetwork_GradientNetwork_unsupported(t *testing.T) {
+	memory := buildNetwork()
+	memory.AllNodes()[4].IsMemory = true
+	modules := []*NNode{NewNNode(1, InputNeuron), NewNNode(2, OutputNeuron)}
+	modules[1].ActivationType = utils.MultiplyModuleActivation
+	modules[1].addIncoming(modules[0], 1.0)
+	for i, netw := range []*Network{buildRecurrentNetwork(0.0), buildModularNetwork(), buildDelayedNetwork(1, false),
+		buildDelayedNetwork(0, true), memory, NewNetwork(modules[0:1], modules[1:2], modules, 0)} {
+		if _, err := netw.GradientNetwork(); err == nil {
+			t.Error("Error expected for unsupported network at:", i)
+		}
+	}
+	if _, err := buildDelayedNetwork(0, false).GradientNetwork(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,142 @@
+package network
+
+import (
+	"fmt"
+	"errors"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The fully connected layer of input preprocessor with fixed weights, i.e. not subject to evolution
+type PreprocessingLayer struct {
+	// The weights of connections with one row per layer output and one column per layer input
+	Weights    [][]float64
+	// The bias values of layer outputs
+	Biases     []float64
+	// The activation function of layer outputs
+	Activation utils.NodeActivationType
+}
+
+// The fixed, non-evolved network applied to the raw sensor values before they are loaded into the input nodes of
+// evolved network, e.g. inputs normalization or fixed feature extractor. The preprocessor is plain data holder, thus
+// it can be serialized with experiment and shared between networks of all organisms.
+type InputPreprocessor struct {
+	// The name of preprocessor
+	Name   string
+	// The layers applied in order, the outputs of the last layer are loaded into network sensors
+	Layers []PreprocessingLayer
+}
+
+// Creates new input preprocessor which normalizes each input by subtracting its mean and dividing by its standard
+// deviation. Returns error if sizes of provided arrays differ or any standard deviation is zero.
+func NewNormalizationPreprocessor(means, stds []float64) (*InputPreprocessor, error) {
+	if len(means) != len(stds) {
+		return nil, errors.New(fmt.Sprintf("the number of means: %d differs from number of standard deviations: %d",
+			len(means), len(stds)))
+	}
+	layer := PreprocessingLayer{
+		Weights:make([][]float64, len(means)),
+		Biases:make([]float64, len(means)),
+		Activation:utils.LinearActivation,
+	}
+	for i, std := range stds {
+		if std == 0 {
+			return nil, errors.New(fmt.Sprintf("zero standard deviation of input: %d", i))
+		}
+		layer.Weights[i] = make([]float64, len(means))
+		layer.Weights[i][i] = 1.0 / std
+		layer.Biases[i] = -means[i] / std
+	}
+	return &InputPreprocessor{Name:"normalization", Layers:[]PreprocessingLayer{layer}}, nil
+}
+
+// Returns the number of raw inputs expected by this preprocessor
+func (p *InputPreprocessor) InputSize() int {
+	if len(p.Layers) == 0 || len(p.Layers[0].Weights) == 0 {
+		return 0
+	}
+	return len(p.Layers[0].Weights[0])
+}
+
+// Returns the number of values produced by this preprocessor
+func (p *InputPreprocessor) OutputSize() int {
+	if len(p.Layers) == 0 {
+		return 0
+	}
+	return len(p.Layers[len(p.Layers) - 1].Weights)
+}
+
+// Checks that layers of this preprocessor are consistent, i.e. each layer has bias per output, all rows of weights
+// have the same length and the number of inputs of each layer equals to the number of outputs of previous one
+func (p *InputPreprocessor) Validate() error {
+	if len(p.Layers) == 0 {
+		return errors.New("input preprocessor has no layers")
+	}
+	inputs := p.InputSize()
+	for i, layer := range p.Layers {
+		if len(layer.Weights) == 0 {
+			return errors.New(fmt.Sprintf("layer: %d of input preprocessor has no outputs", i))
+		}
+		if len(layer.Biases) != len(layer.Weights) {
+			return errors.New(fmt.Sprintf("layer: %d of input preprocessor has %d biases for %d outputs",
+				i, len(layer.Biases), len(layer.Weights)))
+		}
+		for j, row := range layer.Weights {
+			if len(row) != inputs {
+				return errors.New(fmt.Sprintf("output: %d of layer: %d of input preprocessor has %d weights, expected: %d",
+					j, i, len(row), inputs))
+			}
+		}
+		if _, err := utils.NodeActivators.ActivationNameFromType(layer.Activation); err != nil {
+			return err
+		}
+		inputs = len(layer.Weights)
+	}
+	return nil
+}
+
+// Applies this preprocessor to the raw sensor values and returns values to be loaded into network sensors
+func (p *InputPreprocessor) Process(inputs []float64) ([]float64, error) {
+	if len(inputs) != p.InputSize() {
+		return nil, NetErrUnsupportedSensorsArraySize
+	}
+	values := inputs
+	for _, layer := range p.Layers {
+		outputs := make([]float64, len(layer.Weights))
+		for i, row := range layer.Weights {
+			sum := layer.Biases[i]
+			for j, w := range row {
+				sum += w * values[j]
+			}
+			out, err := utils.NodeActivators.ActivateByType(sum, nil, layer.Activation)
+			if err != nil {
+				return nil, err
+			}
+			outputs[i] = out
+		}
+		values = outputs
+	}
+	return values, nil
+}
+
+// Attaches the fixed input preprocessor in front of this network, the raw sensor values passed to LoadSensors will
+// be processed by it before loading into sensors. The nil value detaches preprocessor. Returns error if preprocessor is
+// not valid or the number of its outputs differs from the number of network sensors.
+func (n *Network) SetPreprocessor(p *InputPreprocessor) error {
+	if p != nil {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		sensors := 0
+		for _, node := range n.inputs {
+			if node.NeuronType == InputNeuron {
+				sensors++
+			}
+		}
+		if out := p.OutputSize(); out != sensors {
+			return errors.New(fmt.Sprintf("input preprocessor produces %d values, but network has %d sensors",
+				out, sensors))
+		}
+	}
+	n.Preprocessor = p
+	return nil
+}
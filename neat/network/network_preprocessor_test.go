@@ -0,0 +1,116 @@
+package network
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+func TestNewNormalizationPreprocessor(t *testing.T) {
+	prep, err := NewNormalizationPreprocessor([]float64{1.0, -2.0}, []float64{2.0, 0.5})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if prep.InputSize() != 2 || prep.OutputSize() != 2 {
+		t.Error("Wrong preprocessor size", prep.InputSize(), prep.OutputSize())
+	}
+	out, err := prep.Process([]float64{3.0, -1.0})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if out[0] != 1.0 || out[1] != 2.0 {
+		t.Error("Wrong normalized values", out)
+	}
+
+	if _, err = NewNormalizationPreprocessor([]float64{1.0}, []float64{1.0, 2.0}); err == nil {
+		t.Error("Error expected for different sizes")
+	}
+	if _, err = NewNormalizationPreprocessor([]float64{1.0}, []float64{0.0}); err == nil {
+		t.Error("Error expected for zero standard deviation")
+	}
+	if _, err = prep.Process([]float64{1.0}); err != NetErrUnsupportedSensorsArraySize {
+		t.Error("Error expected for wrong number of inputs", err)
+	}
+}
+
+func TestInputPreprocessor_Process_layers(t *testing.T) {
+	// the feature extractor of three inputs into one hidden feature and two outputs
+	prep := &InputPreprocessor{Layers:[]PreprocessingLayer{
+		{Weights:[][]float64{{1.0, 1.0, 1.0}}, Biases:[]float64{-3.0}, Activation:utils.LinearActivation},
+		{Weights:[][]float64{{2.0}, {-1.0}}, Biases:[]float64{0.0, 0.5}, Activation:utils.LinearActivation},
+	}}
+	if err := prep.Validate(); err != nil {
+		t.Error(err)
+		return
+	}
+	if prep.InputSize() != 3 || prep.OutputSize() != 2 {
+		t.Error("Wrong preprocessor size", prep.InputSize(), prep.OutputSize())
+	}
+	out, err := prep.Process([]float64{1.0, 2.0, 3.0})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if math.Abs(out[0] - 6.0) > 1e-9 || math.Abs(out[1] + 2.5) > 1e-9 {
+		t.Error("Wrong processed values", out)
+	}
+
+	for _, invalid := range []*InputPreprocessor{
+		{},
+		{Layers:[]PreprocessingLayer{{Biases:[]float64{}}}},
+		{Layers:[]PreprocessingLayer{{Weights:[][]float64{{1.0}}, Biases:[]float64{}}}},
+		{Layers:[]PreprocessingLayer{{Weights:[][]float64{{1.0}, {1.0, 2.0}}, Biases:[]float64{0, 0}}}},
+		{Layers:[]PreprocessingLayer{
+			{Weights:[][]float64{{1.0}}, Biases:[]float64{0}},
+			{Weights:[][]float64{{1.0, 2.0}}, Biases:[]float64{0}},
+		}},
+	} {
+		if err = invalid.Validate(); err == nil {
+			t.Error("Error expected for invalid preprocessor", invalid)
+		}
+	}
+}
+
+func TestNetwork_SetPreprocessor(t *testing.T) {
+	netw := buildNetwork()
+	prep, _ := NewNormalizationPreprocessor([]float64{1.0, 1.0}, []float64{2.0, 4.0})
+	if err := netw.SetPreprocessor(prep); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := netw.LoadSensors([]float64{3.0, 5.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if netw.inputs[0].Activation != 1.0 || netw.inputs[1].Activation != 1.0 || netw.inputs[2].Activation != 1.0 {
+		t.Error("Wrong sensor values", netw.inputs[0].Activation, netw.inputs[1].Activation)
+	}
+	if err := netw.LoadSensors([]float64{3.0, 5.0, 1.0}); err == nil {
+		t.Error("Error expected for wrong number of raw sensor values")
+	}
+
+	// the fast network solver applies the same preprocessor
+	solver, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = solver.LoadSensors([]float64{3.0, 5.0}); err != nil {
+		t.Error(err)
+	}
+	fmm := solver.(*FastModularNetworkSolver)
+	if fmm.neuronSignals[1] != 1.0 || fmm.neuronSignals[2] != 1.0 {
+		t.Error("Wrong sensor values of fast solver", fmm.neuronSignals[:3])
+	}
+
+	// the number of preprocessor outputs should match the number of sensors
+	wide, _ := NewNormalizationPreprocessor([]float64{0, 0, 0}, []float64{1, 1, 1})
+	if err = netw.SetPreprocessor(wide); err == nil {
+		t.Error("Error expected for wrong number of preprocessor outputs")
+	}
+	if err = netw.SetPreprocessor(nil); err != nil || netw.Preprocessor != nil {
+		t.Error("The preprocessor should be detached", err)
+	}
+}
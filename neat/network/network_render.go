@@ -0,0 +1,345 @@
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// The options of network rendering
+type RenderOptions struct {
+	// The width and height of the image in pixels
+	Width        int
+	Height       int
+	// The radius of the node circle in pixels
+	NodeRadius   float64
+	// The width in pixels of the link with the greatest absolute weight, the widths of other links are proportional
+	// to their absolute weights
+	MaxLinkWidth float64
+	// If true than node IDs are drawn as labels. It is supported by SVG rendering only.
+	ShowLabels   bool
+}
+
+// Returns default network rendering options
+func DefaultRenderOptions() *RenderOptions {
+	return &RenderOptions{
+		Width:640,
+		Height:480,
+		NodeRadius:12.0,
+		MaxLinkWidth:6.0,
+		ShowLabels:true,
+	}
+}
+
+// The colors used for rendering
+var (
+	renderPositiveLinkColor = color.RGBA{R:0x1f, G:0x77, B:0xb4, A:0xff}
+	renderNegativeLinkColor = color.RGBA{R:0xd6, G:0x27, B:0x28, A:0xff}
+	renderInputNodeColor = color.RGBA{R:0xa1, G:0xd9, B:0x9b, A:0xff}
+	renderBiasNodeColor = color.RGBA{R:0xbd, G:0xbd, B:0xbd, A:0xff}
+	renderHiddenNodeColor = color.RGBA{R:0xff, G:0xff, B:0xff, A:0xff}
+	renderOutputNodeColor = color.RGBA{R:0xfe, G:0xd9, B:0x76, A:0xff}
+	renderControlNodeColor = color.RGBA{R:0xbc, G:0xbd, B:0xdc, A:0xff}
+	renderStrokeColor = color.RGBA{R:0x25, G:0x25, B:0x25, A:0xff}
+)
+
+// The minimal width of rendered link in pixels to keep links with tiny weights visible
+const minRenderLinkWidth = 0.5
+
+// The node placed on the canvas
+type renderNode struct {
+	node    *NNode
+	x, y    float64
+	control bool
+}
+
+// The link placed on the canvas
+type renderLink struct {
+	from, to  *renderNode
+	weight    float64
+	width     float64
+	recurrent bool
+}
+
+// Renders this network as SVG image using layered layout: the input nodes are placed at the bottom layer, the output
+// nodes at the top one and the hidden nodes in between according to the longest forward path from inputs. The link
+// width is proportional to the absolute value of its weight, the positive weights are drawn in blue and the negative
+// ones in red, the recurrent links are dashed. If options are nil than default ones used.
+func (n *Network) RenderSVG(w io.Writer, opts *RenderOptions) error {
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	nodes, links, err := n.renderLayout(opts)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		opts.Width, opts.Height, opts.Width, opts.Height)
+	fmt.Fprintf(b, "<title>network %d</title>\n", n.Id)
+	fmt.Fprintf(b, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+	for _, l := range links {
+		dash := ""
+		if l.recurrent {
+			dash = " stroke-dasharray=\"4,3\""
+		}
+		stroke := svgColor(linkRenderColor(l.weight))
+		if l.from == l.to {
+			// the self-loop drawn as circle above the node
+			r := opts.NodeRadius * 0.75
+			fmt.Fprintf(b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"none\" stroke=\"%s\" stroke-width=\"%.2f\"%s/>\n",
+				l.from.x, l.from.y - opts.NodeRadius - r, r, stroke, l.width, dash)
+		} else {
+			fmt.Fprintf(b, "<line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"%s\" stroke-width=\"%.2f\"%s/>\n",
+				l.from.x, l.from.y, l.to.x, l.to.y, stroke, l.width, dash)
+		}
+	}
+	for _, rn := range nodes {
+		fill := svgColor(nodeRenderColor(rn))
+		if rn.control {
+			fmt.Fprintf(b, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%s\" stroke=\"%s\"/>\n",
+				rn.x - opts.NodeRadius, rn.y - opts.NodeRadius, opts.NodeRadius * 2, opts.NodeRadius * 2,
+				fill, svgColor(renderStrokeColor))
+		} else {
+			fmt.Fprintf(b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"%s\" stroke=\"%s\"/>\n",
+				rn.x, rn.y, opts.NodeRadius, fill, svgColor(renderStrokeColor))
+		}
+		if opts.ShowLabels {
+			fmt.Fprintf(b, "<text x=\"%.1f\" y=\"%.1f\" font-size=\"%.0f\" text-anchor=\"middle\" dominant-baseline=\"central\">%d</text>\n",
+				rn.x, rn.y, opts.NodeRadius, rn.node.Id)
+		}
+	}
+	fmt.Fprintln(b, "</svg>")
+
+	return b.Flush()
+}
+
+// Renders this network as PNG image with the same layout and styling as RenderSVG. The node labels and dashing of
+// recurrent links are not rendered, the control nodes are drawn as circles. If options are nil than default ones used.
+func (n *Network) RenderPNG(w io.Writer, opts *RenderOptions) error {
+	if opts == nil {
+		opts = DefaultRenderOptions()
+	}
+	nodes, links, err := n.renderLayout(opts)
+	if err != nil {
+		return err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for _, l := range links {
+		c := linkRenderColor(l.weight)
+		if l.from == l.to {
+			r := opts.NodeRadius * 0.75
+			drawRing(img, l.from.x, l.from.y - opts.NodeRadius - r, r, l.width, c)
+		} else {
+			drawLine(img, l.from.x, l.from.y, l.to.x, l.to.y, l.width, c)
+		}
+	}
+	for _, rn := range nodes {
+		drawDisc(img, rn.x, rn.y, opts.NodeRadius, renderStrokeColor)
+		drawDisc(img, rn.x, rn.y, opts.NodeRadius - 1, nodeRenderColor(rn))
+	}
+
+	return png.Encode(w, img)
+}
+
+// Places nodes and links of this network on the canvas
+func (n *Network) renderLayout(opts *RenderOptions) ([]*renderNode, []*renderLink, error) {
+	if opts.Width <= 0 || opts.Height <= 0 {
+		return nil, nil, errors.New(fmt.Sprintf("wrong image size: %dx%d", opts.Width, opts.Height))
+	}
+	if len(n.all_nodes) == 0 {
+		return nil, nil, errors.New("no nodes to render")
+	}
+
+	// collect the forward sources of each node including connections through control nodes
+	sources := make(map[*NNode][]*NNode)
+	for _, node := range n.all_nodes {
+		for _, l := range node.Incoming {
+			if !l.IsRecurrent {
+				sources[node] = append(sources[node], l.InNode)
+			}
+		}
+	}
+	for _, cn := range n.control_nodes {
+		for _, l := range cn.Incoming {
+			sources[cn] = append(sources[cn], l.InNode)
+		}
+		for _, l := range cn.Outgoing {
+			sources[l.OutNode] = append(sources[l.OutNode], cn)
+		}
+	}
+
+	// find layer of each node as the length of the longest forward path from inputs
+	layers := make(map[*NNode]int)
+	on_path := make(map[*NNode]bool)
+	var layer func(node *NNode) int
+	layer = func(node *NNode) int {
+		if l, ok := layers[node]; ok {
+			return l
+		}
+		if node.IsSensor() {
+			return 0
+		}
+		on_path[node] = true
+		max := 0
+		for _, src := range sources[node] {
+			if on_path[src] {
+				continue
+			}
+			if l := layer(src) + 1; l > max {
+				max = l
+			}
+		}
+		delete(on_path, node)
+		layers[node] = max
+		return max
+	}
+	all := make([]*NNode, 0, len(n.all_nodes) + len(n.control_nodes))
+	all = append(all, n.all_nodes...)
+	all = append(all, n.control_nodes...)
+	// all outputs placed at the top layer above all other nodes
+	top := 1
+	for _, node := range all {
+		if l := layer(node); node.NeuronType != OutputNeuron && l + 1 > top {
+			top = l + 1
+		}
+	}
+	for _, node := range n.Outputs {
+		layers[node] = top
+	}
+
+	// place nodes of each layer evenly
+	by_layer := make([][]*NNode, top + 1)
+	for _, node := range all {
+		by_layer[layers[node]] = append(by_layer[layers[node]], node)
+	}
+	margin := opts.NodeRadius * 2
+	layer_height := (float64(opts.Height) - 2 * margin) / float64(top)
+	placed := make(map[*NNode]*renderNode, len(all))
+	nodes := make([]*renderNode, 0, len(all))
+	for l, layer_nodes := range by_layer {
+		step := (float64(opts.Width) - 2 * margin) / float64(len(layer_nodes) + 1)
+		for i, node := range layer_nodes {
+			rn := &renderNode{
+				node:node,
+				x:margin + step * float64(i + 1),
+				y:float64(opts.Height) - margin - layer_height * float64(l),
+			}
+			placed[node] = rn
+			nodes = append(nodes, rn)
+		}
+	}
+	for _, cn := range n.control_nodes {
+		placed[cn].control = true
+	}
+
+	// collect links and scale their widths
+	links := make([]*renderLink, 0)
+	max_weight := 0.0
+	add_link := func(l *Link, recurrent bool) {
+		links = append(links, &renderLink{
+			from:placed[l.InNode],
+			to:placed[l.OutNode],
+			weight:l.Weight,
+			recurrent:recurrent,
+		})
+		max_weight = math.Max(max_weight, math.Abs(l.Weight))
+	}
+	for _, node := range n.all_nodes {
+		for _, l := range node.Incoming {
+			add_link(l, l.IsRecurrent)
+		}
+	}
+	for _, cn := range n.control_nodes {
+		for _, l := range cn.Incoming {
+			add_link(l, false)
+		}
+		for _, l := range cn.Outgoing {
+			add_link(l, false)
+		}
+	}
+	for _, l := range links {
+		if l.from == nil || l.to == nil {
+			return nil, nil, errors.New("link refers to the node which is not in network")
+		}
+		l.width = opts.MaxLinkWidth
+		if max_weight > 0 {
+			l.width = math.Max(minRenderLinkWidth, opts.MaxLinkWidth * math.Abs(l.weight) / max_weight)
+		}
+	}
+	return nodes, links, nil
+}
+
+// Returns the color of the link with given weight
+func linkRenderColor(weight float64) color.RGBA {
+	if weight < 0 {
+		return renderNegativeLinkColor
+	}
+	return renderPositiveLinkColor
+}
+
+// Returns the fill color of given node
+func nodeRenderColor(rn *renderNode) color.RGBA {
+	if rn.control {
+		return renderControlNodeColor
+	}
+	switch rn.node.NeuronType {
+	case InputNeuron:
+		return renderInputNodeColor
+	case BiasNeuron:
+		return renderBiasNodeColor
+	case OutputNeuron:
+		return renderOutputNodeColor
+	default:
+		return renderHiddenNodeColor
+	}
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// Draws filled disc with center at (cx, cy)
+func drawDisc(img *image.RGBA, cx, cy, r float64, c color.RGBA) {
+	for y := int(math.Floor(cy - r)); y <= int(math.Ceil(cy + r)); y++ {
+		for x := int(math.Floor(cx - r)); x <= int(math.Ceil(cx + r)); x++ {
+			dx, dy := float64(x) - cx, float64(y) - cy
+			if dx * dx + dy * dy <= r * r && image.Pt(x, y).In(img.Rect) {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// Draws line of given width between two points by stamping discs along it
+func drawLine(img *image.RGBA, x1, y1, x2, y2, width float64, c color.RGBA) {
+	length := math.Hypot(x2 - x1, y2 - y1)
+	steps := int(math.Ceil(length * 2))
+	r := math.Max(0.5, width / 2)
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		drawDisc(img, x1 + (x2 - x1) * t, y1 + (y2 - y1) * t, r, c)
+	}
+}
+
+// Draws circle outline of given width
+func drawRing(img *image.RGBA, cx, cy, r, width float64, c color.RGBA) {
+	steps := int(math.Ceil(2 * math.Pi * r * 2))
+	for i := 0; i <= steps; i++ {
+		a := 2 * math.Pi * float64(i) / float64(steps)
+		drawDisc(img, cx + r * math.Cos(a), cy + r * math.Sin(a), math.Max(0.5, width / 2), c)
+	}
+}
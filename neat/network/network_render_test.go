@@ -0,0 +1,110 @@
+package network
+
+import (
+	"testing"
+	"bytes"
+	"strings"
+	"image/png"
+)
+
+func TestNetwork_RenderSVG(t *testing.T) {
+	netw := buildNetwork()
+	buf := bytes.NewBufferString("")
+	if err := netw.RenderSVG(buf, nil); err != nil {
+		t.Error(err)
+		return
+	}
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Error("Wrong SVG document", svg)
+	}
+	if count := strings.Count(svg, "<circle "); count != 8 {
+		t.Error("Wrong number of nodes rendered", count)
+	}
+	if count := strings.Count(svg, "<line "); count != 8 {
+		t.Error("Wrong number of links rendered", count)
+	}
+	// the link with the greatest weight has maximal width
+	if !strings.Contains(svg, "stroke-width=\"6.00\"") {
+		t.Error("Link with maximal width not found")
+	}
+	if count := strings.Count(svg, "<text "); count != 8 {
+		t.Error("Wrong number of labels", count)
+	}
+}
+
+func TestNetwork_renderLayout(t *testing.T) {
+	netw := buildNetwork()
+	opts := DefaultRenderOptions()
+	nodes, links, err := netw.renderLayout(opts)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(nodes) != 8 || len(links) != 8 {
+		t.Error("Wrong layout size", len(nodes), len(links))
+		return
+	}
+	y := make(map[int]float64)
+	for _, rn := range nodes {
+		y[rn.node.Id] = rn.y
+	}
+	// inputs at the bottom, then hidden by depth, outputs at the top
+	if y[1] != y[2] || y[1] != y[3] {
+		t.Error("Inputs should be in the same layer", y)
+	}
+	if !(y[1] > y[5] && y[5] > y[6] && y[6] > y[7]) {
+		t.Error("Wrong layers order", y)
+	}
+	if y[4] != y[5] || y[7] != y[8] {
+		t.Error("Wrong layer of nodes", y)
+	}
+	for _, rn := range nodes {
+		if rn.x < 0 || rn.x > float64(opts.Width) || rn.y < 0 || rn.y > float64(opts.Height) {
+			t.Error("Node is out of canvas", rn.node.Id, rn.x, rn.y)
+		}
+	}
+
+	opts.Width = 0
+	if _, _, err = netw.renderLayout(opts); err == nil {
+		t.Error("Error expected for wrong image size")
+	}
+}
+
+func TestNetwork_RenderSVG_modular(t *testing.T) {
+	netw := buildModularNetwork()
+	buf := bytes.NewBufferString("")
+	opts := DefaultRenderOptions()
+	opts.ShowLabels = false
+	if err := netw.RenderSVG(buf, opts); err != nil {
+		t.Error(err)
+		return
+	}
+	svg := buf.String()
+	if count := strings.Count(svg, "<rect "); count != 2 {
+		// the background and the control node
+		t.Error("Control node is not rendered", count)
+	}
+	if strings.Contains(svg, "<text ") {
+		t.Error("Labels should not be rendered")
+	}
+}
+
+func TestNetwork_RenderPNG(t *testing.T) {
+	netw := buildNetwork()
+	buf := bytes.NewBuffer(nil)
+	opts := DefaultRenderOptions()
+	opts.Width, opts.Height = 200, 150
+	if err := netw.RenderPNG(buf, opts); err != nil {
+		t.Error(err)
+		return
+	}
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if img.Bounds().Dx() != 200 || img.Bounds().Dy() != 150 {
+		t.Error("Wrong image size", img.Bounds())
+	}
+}
@@ -0,0 +1,264 @@
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The snapshot of network state, i.e. the current weights of links and activation states of nodes. The weights may
+// differ from the genome if network adapted during its lifetime, thus the snapshot allows to save adapted controller
+// exactly as it ended an episode and to restore it into the network built later from the same genome.
+type Snapshot struct {
+	// The ID of network
+	NetworkId int
+	// The states of nodes in order of network nodes followed by control nodes
+	Nodes     []NodeState
+	// The states of links in order of incoming links of nodes
+	Links     []LinkState
+}
+
+// The activation state of network node
+type NodeState struct {
+	// The ID of node
+	Id               int
+	// The activation value of node
+	Activation       float64
+	// The number of activations of node
+	ActivationsCount int32
+	// The activation sum of node
+	ActivationSum    float64
+	// The activation values of node at time t-1 and t-2
+	LastActivation   float64
+	LastActivation2  float64
+	// The state accumulated by memory cell
+	MemoryState      float64
+}
+
+// The state of network link
+type LinkState struct {
+	// The IDs of nodes connected by link
+	InNodeId    int
+	OutNodeId   int
+	// The weight of link
+	Weight      float64
+	// The amount of weight adjustment
+	AddedWeight float64
+	// The signals delayed by link starting from the oldest one
+	Delayed     []float64
+}
+
+// Takes snapshot of the current state of this network
+func (n *Network) Snapshot() *Snapshot {
+	snapshot := &Snapshot{NetworkId:n.Id}
+	for _, node := range n.snapshotNodes() {
+		snapshot.Nodes = append(snapshot.Nodes, NodeState{
+			Id:node.Id,
+			Activation:node.Activation,
+			ActivationsCount:node.ActivationsCount,
+			ActivationSum:node.ActivationSum,
+			LastActivation:node.lastActivation,
+			LastActivation2:node.lastActivation2,
+			MemoryState:node.memoryState,
+		})
+		for _, link := range node.Incoming {
+			state := LinkState{
+				InNodeId:link.InNode.Id,
+				OutNodeId:link.OutNode.Id,
+				Weight:link.Weight,
+				AddedWeight:link.AddedWeight,
+			}
+			for i := range link.delayed {
+				state.Delayed = append(state.Delayed, link.delayed[(link.delayedIdx + i) % len(link.delayed)])
+			}
+			snapshot.Links = append(snapshot.Links, state)
+		}
+	}
+	return snapshot
+}
+
+// Restores the state of this network from given snapshot. The network should have the same topology as the network
+// which snapshot was taken, e.g. it should be built from the same genome, otherwise error returned and network is
+// not changed.
+func (n *Network) Restore(snapshot *Snapshot) error {
+	nodes := n.snapshotNodes()
+	if len(nodes) != len(snapshot.Nodes) {
+		return errors.New(fmt.Sprintf("NETWORK: Snapshot nodes count mismatch: %d != %d",
+			len(snapshot.Nodes), len(nodes)))
+	}
+	links := make([]*Link, 0, len(snapshot.Links))
+	for i, node := range nodes {
+		if node.Id != snapshot.Nodes[i].Id {
+			return errors.New(fmt.Sprintf("NETWORK: Snapshot node ID mismatch: %d != %d",
+				snapshot.Nodes[i].Id, node.Id))
+		}
+		links = append(links, node.Incoming...)
+	}
+	if len(links) != len(snapshot.Links) {
+		return errors.New(fmt.Sprintf("NETWORK: Snapshot links count mismatch: %d != %d",
+			len(snapshot.Links), len(links)))
+	}
+	for i, link := range links {
+		state := snapshot.Links[i]
+		if link.InNode.Id != state.InNodeId || link.OutNode.Id != state.OutNodeId {
+			return errors.New(fmt.Sprintf("NETWORK: Snapshot link mismatch: (%d -> %d) != (%d -> %d)",
+				state.InNodeId, state.OutNodeId, link.InNode.Id, link.OutNode.Id))
+		}
+		if len(state.Delayed) != 0 && len(state.Delayed) != link.Delay {
+			return errors.New(fmt.Sprintf("NETWORK: Snapshot link (%d -> %d) delay mismatch: %d != %d",
+				state.InNodeId, state.OutNodeId, len(state.Delayed), link.Delay))
+		}
+	}
+
+	for i, node := range nodes {
+		state := snapshot.Nodes[i]
+		node.Activation = state.Activation
+		node.ActivationsCount = state.ActivationsCount
+		node.ActivationSum = state.ActivationSum
+		node.lastActivation = state.LastActivation
+		node.lastActivation2 = state.LastActivation2
+		node.memoryState = state.MemoryState
+	}
+	for i, link := range links {
+		state := snapshot.Links[i]
+		link.Weight = state.Weight
+		link.AddedWeight = state.AddedWeight
+		link.delayed, link.delayedIdx = append([]float64(nil), state.Delayed...), 0
+	}
+	return nil
+}
+
+// Returns nodes of network which state is stored in snapshot, i.e. all nodes followed by control nodes
+func (n *Network) snapshotNodes() []*NNode {
+	nodes := make([]*NNode, 0, len(n.all_nodes) + len(n.control_nodes))
+	nodes = append(nodes, n.all_nodes...)
+	return append(nodes, n.control_nodes...)
+}
+
+// Writes this snapshot in plain text format, the values are written with full precision to be restored exactly
+func (s *Snapshot) Write(w io.Writer) error {
+	wr := bufio.NewWriter(w)
+	fmt.Fprintf(wr, "snapshotstart %d\n", s.NetworkId)
+	for _, node := range s.Nodes {
+		fmt.Fprintf(wr, "node %d %s %d %s %s %s %s\n", node.Id, formatFloat(node.Activation), node.ActivationsCount,
+			formatFloat(node.ActivationSum), formatFloat(node.LastActivation), formatFloat(node.LastActivation2),
+			formatFloat(node.MemoryState))
+	}
+	for _, link := range s.Links {
+		fmt.Fprintf(wr, "link %d %d %s %s %d", link.InNodeId, link.OutNodeId, formatFloat(link.Weight),
+			formatFloat(link.AddedWeight), len(link.Delayed))
+		for _, v := range link.Delayed {
+			fmt.Fprintf(wr, " %s", formatFloat(v))
+		}
+		fmt.Fprintln(wr)
+	}
+	fmt.Fprintf(wr, "snapshotend %d\n", s.NetworkId)
+	return wr.Flush()
+}
+
+// Reads network snapshot written in plain text format by Snapshot.Write
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	scanner := bufio.NewScanner(r)
+	snapshot := &Snapshot{}
+	started := false
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch {
+		case fields[0] == "snapshotstart" && len(fields) == 2:
+			snapshot.NetworkId, err = strconv.Atoi(fields[1])
+			started = true
+		case fields[0] == "snapshotend" && started:
+			return snapshot, nil
+		case fields[0] == "node" && started && len(fields) == 8:
+			var node NodeState
+			if node, err = parseNodeState(fields[1:]); err == nil {
+				snapshot.Nodes = append(snapshot.Nodes, node)
+			}
+		case fields[0] == "link" && started && len(fields) >= 6:
+			var link LinkState
+			if link, err = parseLinkState(fields[1:]); err == nil {
+				snapshot.Links = append(snapshot.Links, link)
+			}
+		default:
+			err = errors.New(fmt.Sprintf("NETWORK: Unexpected snapshot line: %s", scanner.Text()))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("NETWORK: Snapshot end not found")
+}
+
+// Parses node state from fields of node line
+func parseNodeState(fields []string) (node NodeState, err error) {
+	var count int64
+	if node.Id, err = strconv.Atoi(fields[0]); err != nil {
+		return node, err
+	}
+	if count, err = strconv.ParseInt(fields[2], 10, 32); err != nil {
+		return node, err
+	}
+	node.ActivationsCount = int32(count)
+	values, err := parseFloats(append([]string{fields[1]}, fields[3:]...))
+	if err != nil {
+		return node, err
+	}
+	node.Activation, node.ActivationSum, node.LastActivation, node.LastActivation2, node.MemoryState =
+		values[0], values[1], values[2], values[3], values[4]
+	return node, nil
+}
+
+// Parses link state from fields of link line
+func parseLinkState(fields []string) (link LinkState, err error) {
+	var delayed int
+	if link.InNodeId, err = strconv.Atoi(fields[0]); err != nil {
+		return link, err
+	}
+	if link.OutNodeId, err = strconv.Atoi(fields[1]); err != nil {
+		return link, err
+	}
+	if delayed, err = strconv.Atoi(fields[4]); err != nil {
+		return link, err
+	}
+	if delayed != len(fields) - 5 {
+		return link, errors.New(fmt.Sprintf("NETWORK: Wrong number of delayed signals of link (%d -> %d): %d",
+			link.InNodeId, link.OutNodeId, len(fields) - 5))
+	}
+	values, err := parseFloats(append(fields[2:4:4], fields[5:]...))
+	if err != nil {
+		return link, err
+	}
+	link.Weight, link.AddedWeight = values[0], values[1]
+	if delayed > 0 {
+		link.Delayed = values[2:]
+	}
+	return link, nil
+}
+
+// Parses given strings into float values
+func parseFloats(fields []string) ([]float64, error) {
+	values := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// Formats float value with the minimal precision needed to parse it exactly
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,109 @@
+package network
+
+import (
+	"testing"
+	"bytes"
+	"strings"
+)
+
+func TestNetwork_Snapshot_Restore(t *testing.T) {
+	netw := buildDelayedNetwork(2, false)
+	inputs := []float64{0.5, 0.25, 0.125, 1.0}
+	activate := func(netw *Network, in float64) float64 {
+		netw.LoadSensors([]float64{in, 0.0})
+		if _, err := netw.Activate(); err != nil {
+			t.Error(err)
+		}
+		return netw.Outputs[0].Activation
+	}
+	activate(netw, inputs[0])
+	activate(netw, inputs[1])
+	// the weight adapted during lifetime
+	netw.Outputs[0].Incoming[0].Weight = 0.3
+
+	var buf bytes.Buffer
+	if err := netw.Snapshot().Write(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	snapshot, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(snapshot.Nodes) != 3 || len(snapshot.Links) != 1 || snapshot.Links[0].Weight != 0.3 {
+		t.Error("Wrong snapshot", snapshot)
+		return
+	}
+	if delayed := snapshot.Links[0].Delayed; len(delayed) != 2 || delayed[0] != 0.5 || delayed[1] != 0.25 {
+		t.Error("Wrong delayed signals, the oldest should go first", delayed)
+	}
+
+	restored := buildDelayedNetwork(2, false)
+	if err = restored.Restore(snapshot); err != nil {
+		t.Error(err)
+		return
+	}
+	for _, in := range inputs[2:] {
+		if out, expected := activate(restored, in), activate(netw, in); out != expected {
+			t.Error("Restored network should continue as original one", out, expected)
+		}
+	}
+}
+
+func TestNetwork_Restore_mismatch(t *testing.T) {
+	snapshot := buildDelayedNetwork(2, false).Snapshot()
+	if err := buildNetwork().Restore(snapshot); err == nil {
+		t.Error("Error expected for different topology")
+	}
+	if err := buildDelayedNetwork(3, false).Restore(snapshot); err != nil {
+		t.Error("No delayed signals in snapshot should be restored for any delay", err)
+	}
+	snapshot.Links[0].Delayed = []float64{1.0, 2.0}
+	netw := buildDelayedNetwork(3, false)
+	if err := netw.Restore(snapshot); err == nil {
+		t.Error("Error expected for delay mismatch")
+	}
+	if netw.Outputs[0].Incoming[0].delayed != nil {
+		t.Error("Network should not be changed after failed restoration")
+	}
+}
+
+func TestSnapshot_Write_precision(t *testing.T) {
+	netw := buildRecurrentNetwork(-3.0)
+	netw.LoadSensors([]float64{0.1})
+	if _, err := netw.ForwardSteps(3); err != nil {
+		t.Error(err)
+		return
+	}
+	var buf bytes.Buffer
+	expected := netw.Snapshot()
+	if err := expected.Write(&buf); err != nil {
+		t.Error(err)
+		return
+	}
+	snapshot, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i, node := range snapshot.Nodes {
+		if node != expected.Nodes[i] {
+			t.Error("Wrong node state", node, expected.Nodes[i])
+		}
+	}
+}
+
+func TestReadSnapshot_malformed(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"snapshotstart 1\nnode 1 0 0 0 0 0 0\n",
+		"snapshotstart 1\nnode 1 0 0 0 0 0\nsnapshotend 1\n",
+		"snapshotstart 1\nlink 1 2 0.5 0 2 0.1\nsnapshotend 1\n",
+		"node 1 0 0 0 0 0 0\n",
+	} {
+		if _, err := ReadSnapshot(strings.NewReader(data)); err == nil {
+			t.Error("Error expected for malformed snapshot", data)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package network
 import (
 	"testing"
 	"github.com/yaricom/goNEAT/neat/utils"
+	"math"
 )
 
 func buildNetwork() *Network {
@@ -109,6 +110,32 @@ func TestNetwork_MaxDepth(t *testing.T) {
 	if depth != 3 {
 		t.Error("MaxDepth", 3, depth)
 	}
+
+	// repeated call gives the same result
+	depth, err = netw.MaxDepth()
+	if err != nil {
+		t.Error(err)
+	}
+	if depth != 3 {
+		t.Error("MaxDepth", 3, depth)
+	}
+}
+
+// Tests Network MaxDepth with loops in network
+func TestNetwork_MaxDepthWithLoop(t *testing.T) {
+	netw := buildNetwork()
+	nodes := netw.AllNodes()
+	// Introduce loop 5 -> 6 -> 8 -> 5 and self loop of 4
+	nodes[4].addIncoming(nodes[7], 3.0)
+	nodes[3].addIncoming(nodes[3], 1.0)
+
+	depth, err := netw.MaxDepth()
+	if err != nil {
+		t.Error(err)
+	}
+	if depth != 3 {
+		t.Error("MaxDepth", 3, depth)
+	}
 }
 
 // Tests Network OutputIsOff
@@ -121,6 +148,58 @@ func TestNetwork_OutputIsOff(t *testing.T) {
 	}
 }
 
+func TestNetwork_ReadOutputsSoftmax(t *testing.T) {
+	netw := buildNetwork()
+	netw.Outputs[0].Activation = 1.0
+	netw.Outputs[1].Activation = 3.0
+
+	outs := netw.ReadOutputsSoftmax()
+	expected := 1.0 / (1.0 + math.Exp(2.0))
+	if math.Abs(outs[0] - expected) > 1e-9 {
+		t.Error("outs[0]", expected, outs[0])
+	}
+	if math.Abs(outs[0] + outs[1] - 1.0) > 1e-9 {
+		t.Error("Softmax outputs should sum up to one", outs)
+	}
+
+	// big values should not overflow
+	netw.Outputs[0].Activation = 1000.0
+	netw.Outputs[1].Activation = 1000.0
+	outs = netw.ReadOutputsSoftmax()
+	if outs[0] != 0.5 || outs[1] != 0.5 {
+		t.Error("Wrong softmax of big values", outs)
+	}
+}
+
+func TestNetwork_ReadOutputsArgmax(t *testing.T) {
+	netw := buildNetwork()
+	netw.Outputs[0].Activation = 0.1
+	netw.Outputs[1].Activation = 0.7
+	if index := netw.ReadOutputsArgmax(); index != 1 {
+		t.Error("ReadOutputsArgmax", 1, index)
+	}
+	netw.Outputs[0].Activation = 0.7
+	if index := netw.ReadOutputsArgmax(); index != 0 {
+		t.Error("The first of equal outputs expected", 0, index)
+	}
+
+	netw.Outputs = nil
+	if index := netw.ReadOutputsArgmax(); index != -1 {
+		t.Error("ReadOutputsArgmax without outputs", -1, index)
+	}
+}
+
+func TestNetwork_ReadOutputsBinary(t *testing.T) {
+	netw := buildNetwork()
+	netw.Outputs[0].Activation = 0.2
+	netw.Outputs[1].Activation = 0.5
+
+	outs := netw.ReadOutputsBinary(0.5)
+	if outs[0] || !outs[1] {
+		t.Error("ReadOutputsBinary", outs)
+	}
+}
+
 // Tests Network Activate
 func TestNetwork_Activate(t *testing.T) {
 	netw := buildNetwork()
@@ -212,6 +291,159 @@ func TestNetwork_Flush(t *testing.T) {
 	}
 }
 
+// Creates network with hidden node having recurrent self-loop and given initial activation
+func buildRecurrentNetwork(initial float64) *Network {
+	all_nodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, HiddenNeuron),
+		NewNNode(3, OutputNeuron),
+	}
+	all_nodes[1].InitialActivation = initial
+	all_nodes[1].addIncoming(all_nodes[0], 1.0)
+	loop := NewLink(2.0, all_nodes[1], all_nodes[1], true)
+	all_nodes[1].Incoming = append(all_nodes[1].Incoming, loop)
+	all_nodes[1].Outgoing = append(all_nodes[1].Outgoing, loop)
+	all_nodes[2].addIncoming(all_nodes[1], 1.0)
+
+	return NewNetwork(all_nodes[0:1], all_nodes[2:3], all_nodes, 0)
+}
+
+func TestNetwork_InitialActivation(t *testing.T) {
+	activate := func(netw *Network) float64 {
+		if _, err := netw.Flush(); err != nil {
+			t.Error(err)
+		}
+		netw.LoadSensors([]float64{0.5})
+		if _, err := netw.ForwardSteps(2); err != nil {
+			t.Error(err)
+		}
+		return netw.Outputs[0].Activation
+	}
+	zero, initial := buildRecurrentNetwork(0.0), buildRecurrentNetwork(-3.0)
+	expected := activate(zero)
+	out := activate(initial)
+	if out == expected {
+		t.Error("Initial activation should change the output", out)
+	}
+	// the initial state restored by flush
+	if again := activate(initial); again != out {
+		t.Error("The same output expected after flush", again, out)
+	}
+	hidden := initial.AllNodes()[1]
+	initial.Flush()
+	if hidden.GetActiveOut() != -3.0 || hidden.GetActiveOutTd() != -3.0 {
+		t.Error("Initial activation expected after flush", hidden.GetActiveOut(), hidden.GetActiveOutTd())
+	}
+}
+
+// Creates network with two inputs and linear output connected to the first input
+func buildDelayedNetwork(delay int, gated bool) *Network {
+	all_nodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, InputNeuron),
+		NewNNode(3, OutputNeuron),
+	}
+	all_nodes[2].ActivationType = utils.LinearActivation
+	all_nodes[2].addIncoming(all_nodes[0], 1.0)
+	link := all_nodes[2].Incoming[0]
+	link.Delay = delay
+	if gated {
+		link.GateNode = all_nodes[1]
+	}
+	return NewNetwork(all_nodes[0:2], all_nodes[2:3], all_nodes, 0)
+}
+
+func TestNetwork_DelayedLink(t *testing.T) {
+	netw := buildDelayedNetwork(2, false)
+	inputs := []float64{0.5, 0.25, 0.125, 1.0}
+	expected := []float64{0.0, 0.0, 0.5, 0.25}
+	for i, in := range inputs {
+		netw.LoadSensors([]float64{in, 0.0})
+		if _, err := netw.Activate(); err != nil {
+			t.Error(err)
+			return
+		}
+		if out := netw.Outputs[0].Activation; out != expected[i] {
+			t.Error("Wrong delayed output at step", i, out, expected[i])
+		}
+	}
+
+	// the delayed signals removed by flush
+	if _, err := netw.Flush(); err != nil {
+		t.Error(err)
+		return
+	}
+	netw.LoadSensors([]float64{1.0, 0.0})
+	netw.Activate()
+	if out := netw.Outputs[0].Activation; out != 0.0 {
+		t.Error("No delayed signals expected after flush", out)
+	}
+}
+
+func TestNetwork_GatedLink(t *testing.T) {
+	netw := buildDelayedNetwork(0, true)
+	netw.LoadSensors([]float64{0.5, 0.25})
+	if _, err := netw.Activate(); err != nil {
+		t.Error(err)
+		return
+	}
+	if out := netw.Outputs[0].Activation; out != 0.125 {
+		t.Error("Signal should be multiplied by activation of gating node", out)
+	}
+	netw.LoadSensors([]float64{0.5, 0.0})
+	netw.Activate()
+	if out := netw.Outputs[0].Activation; out != 0.0 {
+		t.Error("Signal should be blocked by inactive gating node", out)
+	}
+
+	// the fast solver does not support neither gated nor delayed links
+	if _, err := netw.FastNetworkSolver(); err == nil {
+		t.Error("Error expected for gated link")
+	}
+	if _, err := buildDelayedNetwork(1, false).FastNetworkSolver(); err == nil {
+		t.Error("Error expected for delayed link")
+	}
+}
+
+func TestNetwork_MemoryNode(t *testing.T) {
+	all_nodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, HiddenNeuron),
+		NewNNode(3, OutputNeuron),
+	}
+	all_nodes[1].IsMemory, all_nodes[1].MemoryRetention = true, 1.0
+	all_nodes[1].ActivationType = utils.LinearActivation
+	all_nodes[1].addIncoming(all_nodes[0], 1.0)
+	all_nodes[2].ActivationType = utils.LinearActivation
+	all_nodes[2].addIncoming(all_nodes[1], 1.0)
+	netw := NewNetwork(all_nodes[0:1], all_nodes[2:3], all_nodes, 0)
+
+	// the memory cell remembers signal after input is gone
+	netw.LoadSensors([]float64{1.0})
+	if _, err := netw.Activate(); err != nil {
+		t.Error(err)
+		return
+	}
+	remembered := all_nodes[1].Activation
+	if remembered == 0.0 {
+		t.Error("Memory cell should accumulate input signal")
+	}
+	for i := 0; i < 3; i++ {
+		netw.LoadSensors([]float64{0.0})
+		if _, err := netw.Activate(); err != nil {
+			t.Error(err)
+			return
+		}
+		if out := netw.Outputs[0].Activation; out != remembered {
+			t.Error("Memory cell should retain accumulated signal at step", i, out, remembered)
+		}
+	}
+
+	if _, err := netw.FastNetworkSolver(); err == nil {
+		t.Error("Error expected for memory node")
+	}
+}
+
 // Tests Network NodeCount
 func TestNetwork_NodeCount(t *testing.T) {
 	netw := buildNetwork()
@@ -235,21 +467,58 @@ func TestNetwork_LinkCount(t *testing.T) {
 // Tests Network IsRecurrent
 func TestNetwork_IsRecurrent(t *testing.T) {
 	netw := buildNetwork()
+	if netw.IsRecurrent() {
+		t.Error("Network is not recurrent")
+	}
 
+	// Introduce recurrence
 	nodes := netw.AllNodes()
+	nodes[4].addIncoming(nodes[7], 3.0)
+	if !netw.IsRecurrent() {
+		t.Error("Network is actually recurrent now")
+	}
 
-	count := 0
-	recur := netw.IsRecurrent(nodes[0], nodes[7], &count, 32)
-	if recur {
-		t.Error("Network is not recurrent")
+	// Recurrent link without loop
+	netw = buildNetwork()
+	nodes = netw.AllNodes()
+	link := NewLink(1.0, nodes[6], nodes[3], true)
+	nodes[3].Incoming = append(nodes[3].Incoming, link)
+	if !netw.IsRecurrent() {
+		t.Error("Network with recurrent link is recurrent")
+	}
+}
+
+// Tests Network PathExists
+func TestNetwork_PathExists(t *testing.T) {
+	netw := buildNetwork()
+	nodes := netw.AllNodes()
+
+	if !netw.PathExists(nodes[1], nodes[7]) {
+		t.Error("Path exists from 2 to 8")
+	}
+	if netw.PathExists(nodes[0], nodes[7]) {
+		t.Error("No path from 1 to 8")
+	}
+	if netw.PathExists(nodes[7], nodes[5]) {
+		t.Error("No path from 8 to 6")
+	}
+	if !netw.PathExists(nodes[3], nodes[3]) {
+		t.Error("Node always has path to itself")
 	}
 
 	// Introduce recurrence
 	nodes[4].addIncoming(nodes[7], 3.0)
+	if !netw.PathExists(nodes[7], nodes[5]) {
+		t.Error("Path exists from 8 to 6 now")
+	}
 
-	recur = netw.IsRecurrent(nodes[5], nodes[7], &count, 32)
-	if !recur {
-		t.Error("Network is actually recurrent now")
+	// Recurrent links are skipped
+	netw = buildNetwork()
+	nodes = netw.AllNodes()
+	link := NewLink(1.0, nodes[7], nodes[4], true)
+	nodes[4].Incoming = append(nodes[4].Incoming, link)
+	if netw.PathExists(nodes[7], nodes[5]) {
+		t.Error("Path through recurrent link should not be found")
 	}
 }
 
@@ -271,3 +540,145 @@ func TestNetwork_FastNetworkSolver(t *testing.T) {
 		t.Error("solver.LinkCount() != netw.LinkCount()", solver.LinkCount(), netw.LinkCount())
 	}
 }
+
+// Creates fully connected layered network for benchmarks
+func buildBenchmarkNetwork(inputs, hidden, outputs int) *Network {
+	in_list, hidden_list, out_list := make([]*NNode, inputs), make([]*NNode, hidden), make([]*NNode, outputs)
+	all_nodes := make([]*NNode, 0, inputs + hidden + outputs)
+	id := 1
+	for i := range in_list {
+		in_list[i] = NewNNode(id, InputNeuron)
+		id++
+	}
+	for i := range hidden_list {
+		hidden_list[i] = NewNNode(id, HiddenNeuron)
+		for j, in := range in_list {
+			hidden_list[i].addIncoming(in, float64(i - j) * 0.1)
+		}
+		id++
+	}
+	for i := range out_list {
+		out_list[i] = NewNNode(id, OutputNeuron)
+		for j, h := range hidden_list {
+			out_list[i].addIncoming(h, float64(j - i) * 0.1)
+		}
+		id++
+	}
+	all_nodes = append(all_nodes, in_list...)
+	all_nodes = append(all_nodes, hidden_list...)
+	all_nodes = append(all_nodes, out_list...)
+	return NewNetwork(in_list, out_list, all_nodes, 0)
+}
+
+func BenchmarkNetwork_Activate(b *testing.B) {
+	netw := buildBenchmarkNetwork(10, 20, 5)
+	inputs := make([]float64, 10)
+	for i := range inputs {
+		inputs[i] = float64(i) * 0.1
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := netw.LoadSensors(inputs); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := netw.Activate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Tests that network activation cycle does not allocate memory
+func TestNetwork_Activate_noAllocations(t *testing.T) {
+	netw := buildBenchmarkNetwork(10, 20, 5)
+	inputs := make([]float64, 10)
+	outputs := make([]float64, 5)
+	allocs := testing.AllocsPerRun(100, func() {
+		netw.LoadSensors(inputs)
+		netw.Activate()
+		outputs = netw.ReadOutputsInto(outputs)
+		netw.Flush()
+	})
+	if allocs != 0 {
+		t.Error("Network activation allocates", allocs)
+	}
+}
+
+// Tests that modular network activation allocates only outputs of module activation functions
+func TestModularNetwork_Activate_allocations(t *testing.T) {
+	netw := buildModularNetwork()
+	netw.LoadSensors([]float64{1.0, 2.0, 0.5})
+	// warm up buffers
+	if _, err := netw.Activate(); err != nil {
+		t.Error(err)
+		return
+	}
+	// outputs are active after first activation, thus every next one is single step
+	allocs := testing.AllocsPerRun(100, func() {
+		netw.Activate()
+	})
+	if allocs > float64(len(netw.control_nodes)) {
+		t.Error("Modular network activation allocates", allocs)
+	}
+}
+
+func TestNetwork_ReadOutputsInto(t *testing.T) {
+	netw := buildNetwork()
+	netw.Outputs[0].Activation = 0.5
+	netw.Outputs[1].Activation = 1.5
+
+	buf := make([]float64, 0, 5)
+	outs := netw.ReadOutputsInto(buf)
+	if len(outs) != 2 || outs[0] != 0.5 || outs[1] != 1.5 {
+		t.Error("Wrong outputs", outs)
+	}
+	if &outs[0] != &buf[:1][0] {
+		t.Error("Provided buffer was not reused")
+	}
+	// not enough capacity
+	if outs = netw.ReadOutputsInto(nil); len(outs) != 2 || outs[1] != 1.5 {
+		t.Error("Wrong outputs", outs)
+	}
+}
+
+func TestActivationsTotal(t *testing.T) {
+	netw := buildNetwork()
+	netw.LoadSensors([]float64{1.0, 2.0, 0.5})
+
+	// the first activation may take several steps until all outputs become active
+	start := ActivationsTotal()
+	if _, err := netw.Activate(); err != nil {
+		t.Error(err)
+		return
+	}
+	if count := ActivationsTotal() - start; count < 1 {
+		t.Error("Wrong number of the first network activation steps", count)
+	}
+
+	start = ActivationsTotal()
+	if _, err := netw.ForwardSteps(3); err != nil {
+		t.Error(err)
+		return
+	}
+	if count := ActivationsTotal() - start; count != 3 {
+		t.Error("Wrong number of network activations", count)
+	}
+
+	fmm, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	start = ActivationsTotal()
+	if _, err = fmm.ForwardSteps(2); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = fmm.RecursiveSteps(); err != nil {
+		t.Error(err)
+		return
+	}
+	if count := ActivationsTotal() - start; count != 3 {
+		t.Error("Wrong number of solver activations", count)
+	}
+}
@@ -27,6 +27,14 @@ type NNode struct {
 	ActivationsCount  int32
 	// The activation sum
 	ActivationSum     float64
+	// The initial activation value of neuron, i.e. the value it outputs before its first activation after network
+	// flush. It allows recurrent networks to start in non-zero state.
+	InitialActivation float64
+	// If true the neuron is memory cell which accumulates its input signals between activations and outputs the
+	// activation of accumulated state. It gives recurrent networks explicit long-term memory.
+	IsMemory          bool
+	// The forget gate of memory cell, i.e. fraction of accumulated state retained between activations in [0, 1] range
+	MemoryRetention   float64
 
 	// The list of all incoming connections
 	Incoming          []*Link
@@ -55,6 +63,12 @@ type NNode struct {
 
 	// If true the node is active - used during node activation
 	isActive          bool
+
+	// The buffer of input values reused between activations of control node to avoid allocations
+	moduleInputs      []float64
+
+	// The state accumulated by memory cell
+	memoryState       float64
 }
 
 // Creates new node with specified ID and neuron type associated (INPUT, HIDDEN, OUTPUT, BIAS)
@@ -72,6 +86,10 @@ func NewNNodeCopy(n *NNode, t *neat.Trait) *NNode {
 	node.NeuronType = n.NeuronType
 	node.ActivationType = n.ActivationType
 	node.Trait = t
+	node.Params = append([]float64(nil), n.Params...)
+	node.InitialActivation = n.InitialActivation
+	node.IsMemory = n.IsMemory
+	node.MemoryRetention = n.MemoryRetention
 	return node
 }
 
@@ -95,27 +113,34 @@ func (n *NNode) setActivation(input float64) {
 	n.ActivationsCount++
 }
 
+// Accumulates given input signal within state of memory cell and returns the new state. The accumulated state is
+// attenuated by forget gate of memory cell before new signal added.
+func (n *NNode) accumulate(input float64) float64 {
+	n.memoryState = n.MemoryRetention * n.memoryState + input
+	return n.memoryState
+}
+
 // Saves current node's activations for potential time delayed connections
 func (n *NNode) saveActivations() {
 	n.lastActivation2 = n.lastActivation
 	n.lastActivation = n.Activation
 }
 
-// Returns activation for a current step
+// Returns activation for a current step or initial activation if node was not activated yet
 func (n *NNode) GetActiveOut() float64 {
 	if n.ActivationsCount > 0 {
 		return n.Activation
 	} else {
-		return 0.0
+		return n.InitialActivation
 	}
 }
 
-// Returns activation from PREVIOUS time step
+// Returns activation from PREVIOUS time step or initial activation if there was no previous activation
 func (n *NNode) GetActiveOutTd() float64 {
 	if n.ActivationsCount > 1 {
 		return n.lastActivation
 	} else {
-		return 0.0
+		return n.InitialActivation
 	}
 }
 
@@ -161,6 +186,7 @@ func (n *NNode) Flushback() {
 	n.Activation = 0
 	n.lastActivation = 0
 	n.lastActivation2 = 0
+	n.memoryState = 0
 	n.isActive = false
 	n.visited = false
 }
@@ -213,10 +239,12 @@ func (n *NNode) Depth(d int) (int, error) {
 
 }
 
-// Convenient method to check network's node type (SENSOR, NEURON)
+// Convenient method to check network's node type (SENSOR, NEURON, MEMORY)
 func (n *NNode) NodeType() NodeType {
 	if n.IsSensor() {
 		return SensorNode
+	} else if n.IsMemory {
+		return MemoryNode
 	}
 	return NeuronNode
 }
@@ -244,6 +272,9 @@ func (n *NNode) Print() string {
 	fmt.Fprintf(b, "\tNeuronType: %d\n", n.NeuronType)
 	fmt.Fprintf(b, "\tActivationsCount: %d\n", n.ActivationsCount)
 	fmt.Fprintf(b, "\tActivationSum: %f\n", n.ActivationSum)
+	fmt.Fprintf(b, "\tInitialActivation: %f\n", n.InitialActivation)
+	fmt.Fprintf(b, "\tIsMemory: %t\n", n.IsMemory)
+	fmt.Fprintf(b, "\tMemoryRetention: %f\n", n.MemoryRetention)
 	fmt.Fprintf(b, "\tIncoming: %s\n", n.Incoming)
 	fmt.Fprintf(b, "\tOutgoing: %s\n", n.Outgoing)
 	fmt.Fprintf(b, "\tTrait: %s\n", n.Trait)
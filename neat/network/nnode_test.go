@@ -2,6 +2,7 @@ package network
 
 import (
 	"testing"
+	"github.com/yaricom/goNEAT/neat/utils"
 )
 
 // Tests NNode SensorLoad
@@ -151,3 +152,56 @@ func TestNNode_Flushback(t *testing.T) {
 		t.Error("GetActiveOutTd", 0, node.GetActiveOutTd())
 	}
 }
+
+func TestNNode_InitialActivation(t *testing.T) {
+	node := NewNNode(1, HiddenNeuron)
+	node.InitialActivation = 0.5
+	if node.GetActiveOut() != 0.5 || node.GetActiveOutTd() != 0.5 {
+		t.Error("Initial activation expected before activation", node.GetActiveOut(), node.GetActiveOutTd())
+	}
+	node.setActivation(2.0)
+	if node.GetActiveOut() != 2.0 || node.GetActiveOutTd() != 0.5 {
+		t.Error("Wrong activation after the first step", node.GetActiveOut(), node.GetActiveOutTd())
+	}
+	node.Flushback()
+	if node.GetActiveOut() != 0.5 {
+		t.Error("Initial activation expected after flush", node.GetActiveOut())
+	}
+	if err := node.FlushbackCheck(); err != nil {
+		t.Error(err)
+	}
+
+	if copy := NewNNodeCopy(node, nil); copy.InitialActivation != 0.5 {
+		t.Error("Initial activation should be copied", copy.InitialActivation)
+	}
+}
+
+func TestNNode_MemoryCell(t *testing.T) {
+	node := NewNNode(1, HiddenNeuron)
+	node.IsMemory, node.MemoryRetention = true, 0.5
+	if node.NodeType() != MemoryNode {
+		t.Error("Memory node type expected", node.NodeType())
+	}
+	node.ActivationType = utils.LinearActivation
+	expected := []float64{1.0, 1.5, 1.75}
+	for i, e := range expected {
+		node.ActivationSum = 1.0
+		if err := ActivateNode(node, utils.NodeActivators); err != nil {
+			t.Error(err)
+			return
+		}
+		if node.Activation != e {
+			t.Error("Wrong activation of memory cell at step", i, node.Activation, e)
+		}
+	}
+	node.Flushback()
+	node.ActivationSum = 1.0
+	ActivateNode(node, utils.NodeActivators)
+	if node.Activation != 1.0 {
+		t.Error("Memory cell state should be cleared by flush", node.Activation)
+	}
+
+	if copy := NewNNodeCopy(node, nil); !copy.IsMemory || copy.MemoryRetention != 0.5 {
+		t.Error("Memory cell should be copied", copy.IsMemory, copy.MemoryRetention)
+	}
+}
@@ -0,0 +1,57 @@
+package network
+
+import "math"
+
+// The policy to handle values which are not finite numbers (NaN or infinity) produced by network activation or found
+// in genetic information
+type NonFinitePolicy int
+
+// The supported policies to handle values which are not finite numbers
+const (
+	// The non-finite values are passed through unchanged, i.e. they are handled as without any guards
+	IgnoreNonFinite NonFinitePolicy = iota
+	// The NaN value is replaced by zero and infinite values are clamped to the nearest bound
+	ClampNonFinite
+	// The organism with non-finite value is rejected, e.g. its activation fails leading to evaluation error or its
+	// genome is replaced by the genome of parent
+	RejectNonFinite
+	// The non-finite value is reported as error which aborts evolution
+	ErrorOnNonFinite
+)
+
+// The maximal magnitude to which infinite values are clamped if no other bounds defined. It is small enough to keep
+// subsequent multiplications and sums of clamped values finite.
+const MaxFiniteValue = math.MaxFloat32
+
+// Checks whether provided value is finite number, i.e. neither NaN nor infinity
+func IsFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// Returns provided value if it is finite. Otherwise, the positive infinity replaced by max, the negative infinity
+// replaced by min, and NaN replaced by zero bounded within [min, max].
+func ClampToFinite(v, min, max float64) float64 {
+	switch {
+	case math.IsInf(v, 1):
+		return max
+	case math.IsInf(v, -1):
+		return min
+	case math.IsNaN(v):
+		return math.Max(min, math.Min(0.0, max))
+	default:
+		return v
+	}
+}
+
+// Checks activation value stored under provided pointer according to given policy. The non-finite value is kept by
+// IgnoreNonFinite policy, clamped in place by ClampNonFinite policy, otherwise NetErrNonFiniteActivation returned.
+func guardActivation(v *float64, policy NonFinitePolicy) error {
+	if policy == IgnoreNonFinite || IsFinite(*v) {
+		return nil
+	}
+	if policy == ClampNonFinite {
+		*v = ClampToFinite(*v, -MaxFiniteValue, MaxFiniteValue)
+		return nil
+	}
+	return NetErrNonFiniteActivation
+}
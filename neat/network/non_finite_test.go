@@ -0,0 +1,133 @@
+package network
+
+import (
+	"testing"
+	"math"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// Builds network with single linear output connected to input by link with infinite weight
+func buildNonFiniteNetwork() *Network {
+	all_nodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, OutputNeuron),
+	}
+	all_nodes[1].ActivationType = utils.LinearActivation
+	all_nodes[1].addIncoming(all_nodes[0], math.Inf(1))
+
+	return NewNetwork(all_nodes[0:1], all_nodes[1:2], all_nodes, 0)
+}
+
+func TestIsFinite(t *testing.T) {
+	if !IsFinite(0) || !IsFinite(-MaxFiniteValue) || !IsFinite(math.MaxFloat64) {
+		t.Error("Finite values expected")
+	}
+	if IsFinite(math.NaN()) || IsFinite(math.Inf(1)) || IsFinite(math.Inf(-1)) {
+		t.Error("Non-finite values expected")
+	}
+}
+
+func TestClampToFinite(t *testing.T) {
+	tests := []struct {
+		v, min, max, expected float64
+	}{
+		{v:1.5, min:-1, max:1, expected:1.5},
+		{v:math.Inf(1), min:-1, max:1, expected:1},
+		{v:math.Inf(-1), min:-1, max:1, expected:-1},
+		{v:math.NaN(), min:-1, max:1, expected:0},
+		{v:math.NaN(), min:0.5, max:1, expected:0.5},
+		{v:math.NaN(), min:-1, max:-0.5, expected:-0.5},
+	}
+	for i, test := range tests {
+		if res := ClampToFinite(test.v, test.min, test.max); res != test.expected {
+			t.Error("Wrong clamped value at:", i, res, test.expected)
+		}
+	}
+}
+
+func TestNetwork_Activate_nonFiniteIgnore(t *testing.T) {
+	// the non-finite activations are passed through by default
+	netw := buildNonFiniteNetwork()
+	if err := netw.LoadSensors([]float64{1.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := netw.Activate(); err != nil {
+		t.Error(err)
+		return
+	}
+	if out := netw.Outputs[0].Activation; !math.IsInf(out, 1) {
+		t.Error("Infinite activation should be kept", out)
+	}
+}
+
+func TestNetwork_Activate_nonFiniteClamp(t *testing.T) {
+	netw := buildNonFiniteNetwork()
+	netw.NonFinitePolicy = ClampNonFinite
+	if err := netw.LoadSensors([]float64{1.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := netw.Activate(); err != nil {
+		t.Error(err)
+		return
+	}
+	if out := netw.Outputs[0].Activation; out != MaxFiniteValue {
+		t.Error("Infinite activation should be clamped", out)
+	}
+
+	// zero input multiplied by infinite weight gives NaN
+	if err := netw.LoadSensors([]float64{0.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := netw.Activate(); err != nil {
+		t.Error(err)
+		return
+	}
+	if out := netw.Outputs[0].Activation; out != 0 {
+		t.Error("NaN activation should be replaced by zero", out)
+	}
+}
+
+func TestNetwork_Activate_nonFiniteError(t *testing.T) {
+	for _, policy := range []NonFinitePolicy{RejectNonFinite, ErrorOnNonFinite} {
+		netw := buildNonFiniteNetwork()
+		netw.NonFinitePolicy = policy
+		if err := netw.LoadSensors([]float64{1.0}); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := netw.Activate(); err != NetErrNonFiniteActivation {
+			t.Error("NetErrNonFiniteActivation expected", policy, err)
+		}
+	}
+}
+
+func TestFastModularNetworkSolver_nonFinite(t *testing.T) {
+	netw := buildNonFiniteNetwork()
+	netw.NonFinitePolicy = ErrorOnNonFinite
+	solver, err := netw.FastNetworkSolver()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = solver.LoadSensors([]float64{1.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = solver.ForwardSteps(1); err != NetErrNonFiniteActivation {
+		t.Error("NetErrNonFiniteActivation expected", err)
+	}
+	if _, err = solver.RecursiveSteps(); err != NetErrNonFiniteActivation {
+		t.Error("NetErrNonFiniteActivation expected", err)
+	}
+
+	solver.(*FastModularNetworkSolver).NonFinitePolicy = ClampNonFinite
+	if _, err = solver.ForwardSteps(1); err != nil {
+		t.Error(err)
+	}
+	if out := solver.ReadOutputs()[0]; out != MaxFiniteValue {
+		t.Error("Infinite activation should be clamped", out)
+	}
+}
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"errors"
+)
+
+// The optimizer updating weights of network by gradient descent, see GradientNetwork
+type Optimizer interface {
+	// Updates provided weights in place by their gradients of loss function. The optimizer may keep state between
+	// updates, e.g. momentum, thus it should be applied to the same weights vector at each update.
+	Update(weights, gradients []float64) error
+}
+
+// The stochastic gradient descent optimizer with optional momentum
+type SGDOptimizer struct {
+	// The learning rate, i.e. the step size along negative gradient
+	LearningRate float64
+	// The momentum in [0, 1) range, i.e. fraction of previous update added to the current one, zero for plain SGD
+	Momentum     float64
+
+	// The previous updates per weight
+	velocity     []float64
+}
+
+// Creates new SGD optimizer with given learning rate and momentum
+func NewSGDOptimizer(learning_rate, momentum float64) *SGDOptimizer {
+	return &SGDOptimizer{LearningRate:learning_rate, Momentum:momentum}
+}
+
+// Updates weights by gradients with momentum
+func (o *SGDOptimizer) Update(weights, gradients []float64) error {
+	if len(weights) != len(gradients) {
+		return errors.New(fmt.Sprintf("the number of weights %d differs from the number of gradients %d",
+			len(weights), len(gradients)))
+	}
+	if len(o.velocity) != len(weights) {
+		o.velocity = make([]float64, len(weights))
+	}
+	for i, grad := range gradients {
+		o.velocity[i] = o.Momentum * o.velocity[i] - o.LearningRate * grad
+		weights[i] += o.velocity[i]
+	}
+	return nil
+}
+
+// The Adam optimizer with adaptive learning rate per weight estimated from the first and second moments of gradients
+// (Kingma and Ba, 2014)
+type AdamOptimizer struct {
+	// The learning rate, i.e. the maximal step size
+	LearningRate float64
+	// The exponential decay rates of the first and second moments estimates
+	Beta1, Beta2 float64
+	// The small constant for numerical stability
+	Epsilon      float64
+
+	// The moments estimates per weight and the number of updates done
+	m, v         []float64
+	step         int
+}
+
+// Creates new Adam optimizer with given learning rate and default decay rates 0.9 and 0.999
+func NewAdamOptimizer(learning_rate float64) *AdamOptimizer {
+	return &AdamOptimizer{LearningRate:learning_rate, Beta1:0.9, Beta2:0.999, Epsilon:1e-8}
+}
+
+// Updates weights by gradients with bias-corrected moments estimates
+func (o *AdamOptimizer) Update(weights, gradients []float64) error {
+	if len(weights) != len(gradients) {
+		return errors.New(fmt.Sprintf("the number of weights %d differs from the number of gradients %d",
+			len(weights), len(gradients)))
+	}
+	if len(o.m) != len(weights) {
+		o.m, o.v, o.step = make([]float64, len(weights)), make([]float64, len(weights)), 0
+	}
+	o.step++
+	correction1 := 1.0 - math.Pow(o.Beta1, float64(o.step))
+	correction2 := 1.0 - math.Pow(o.Beta2, float64(o.step))
+	for i, grad := range gradients {
+		o.m[i] = o.Beta1 * o.m[i] + (1.0 - o.Beta1) * grad
+		o.v[i] = o.Beta2 * o.v[i] + (1.0 - o.Beta2) * grad * grad
+		weights[i] -= o.LearningRate * (o.m[i] / correction1) / (math.Sqrt(o.v[i] / correction2) + o.Epsilon)
+	}
+	return nil
+}
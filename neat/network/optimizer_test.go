@@ -0,0 +1,45 @@
+package network
+
+import (
+	"testing"
+	"math"
+)
+
+func TestSGDOptimizer_Update(t *testing.T) {
+	optimizer := NewSGDOptimizer(0.1, 0.5)
+	weights := []float64{1.0, -2.0}
+	if err := optimizer.Update(weights, []float64{1.0, -2.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if math.Abs(weights[0] - 0.9) > 1e-12 || math.Abs(weights[1] + 1.8) > 1e-12 {
+		t.Error("Wrong weights after first update", weights)
+	}
+	// the half of previous update added by momentum
+	optimizer.Update(weights, []float64{1.0, -2.0})
+	if math.Abs(weights[0] - 0.75) > 1e-12 || math.Abs(weights[1] + 1.5) > 1e-12 {
+		t.Error("Wrong weights after second update", weights)
+	}
+	if err := optimizer.Update(weights, []float64{1.0}); err == nil {
+		t.Error("Error expected for wrong number of gradients")
+	}
+}
+
+func TestAdamOptimizer_Update(t *testing.T) {
+	optimizer := NewAdamOptimizer(0.01)
+	weights := []float64{1.0, -2.0, 0.5}
+	// the first step of bias-corrected Adam is the learning rate along the sign of gradient
+	if err := optimizer.Update(weights, []float64{100.0, -0.001, 0.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	expected := []float64{0.99, -1.99, 0.5}
+	for i, w := range weights {
+		if math.Abs(w - expected[i]) > 1e-6 {
+			t.Error("Wrong weight at:", i, w, expected[i])
+		}
+	}
+	if err := optimizer.Update(weights, nil); err == nil {
+		t.Error("Error expected for wrong number of gradients")
+	}
+}
@@ -0,0 +1,160 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"errors"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The layout of HyperNEAT substrate, i.e. the coordinates of its nodes in the hypercube. All nodes of substrate
+// should have coordinates of the same dimension.
+type Substrate struct {
+	// The coordinates of input nodes
+	Inputs           [][]float64
+	// The coordinates of hidden nodes, if empty the inputs connected directly to the outputs
+	Hidden           [][]float64
+	// The coordinates of output nodes
+	Outputs          [][]float64
+
+	// The activation function of hidden nodes
+	HiddenActivation utils.NodeActivationType
+	// The activation function of output nodes
+	OutputActivation utils.NodeActivationType
+}
+
+// Returns the dimension of substrate coordinates or error if nodes have coordinates of different dimensions
+func (s *Substrate) Dimension() (int, error) {
+	if len(s.Inputs) == 0 || len(s.Outputs) == 0 {
+		return 0, errors.New("substrate should have input and output nodes")
+	}
+	dim := len(s.Inputs[0])
+	for _, layer := range [][][]float64{s.Inputs, s.Hidden, s.Outputs} {
+		for _, coords := range layer {
+			if len(coords) != dim {
+				return 0, errors.New(fmt.Sprintf(
+					"substrate node coordinates have different dimensions: %d and %d", dim, len(coords)))
+			}
+		}
+	}
+	return dim, nil
+}
+
+// The decoder of substrate network from CPPN. The CPPN queried for each pair of source and target nodes of adjacent
+// substrate layers with coordinates of source node followed by coordinates of target node as inputs, and its first
+// output gives the weight of connection. If link expression output (LEO) enabled, the second output of CPPN decides
+// whether connection expressed, which allows CPPN to draw modular connectivity patterns independently of weights.
+// Otherwise, the connection expressed only if magnitude of weight output exceeds the weight threshold.
+type SubstrateDecoder struct {
+	// The flag to indicate whether the second output of CPPN is link expression output gating connections
+	UseLEO          bool
+	// The value of link expression output above which connection expressed
+	LEOThreshold    float64
+	// The magnitude of weight output below which connection is not expressed, used only if LEO is not enabled
+	WeightThreshold float64
+	// The maximal magnitude of substrate connection weight, the weight output of CPPN is scaled to this value
+	MaxWeight       float64
+	// The number of forward steps to activate CPPN, if zero the recursive activation used
+	CPPNSteps       int
+}
+
+// Creates network solver of provided substrate with connections and weights queried from given CPPN
+func (d *SubstrateDecoder) Decode(cppn NetworkSolver, substrate *Substrate) (*FastModularNetworkSolver, error) {
+	dim, err := substrate.Dimension()
+	if err != nil {
+		return nil, err
+	}
+	if d.UseLEO && d.WeightThreshold != 0 {
+		return nil, errors.New("weight threshold can not be used along with link expression output")
+	}
+	if d.WeightThreshold < 0 || d.WeightThreshold >= 1 {
+		return nil, errors.New(fmt.Sprintf("weight threshold is out of range [0, 1): %f", d.WeightThreshold))
+	}
+
+	// the neurons stored in order: inputs, outputs, hidden
+	in_count, out_count, hidden_count := len(substrate.Inputs), len(substrate.Outputs), len(substrate.Hidden)
+	total := in_count + out_count + hidden_count
+	activations := make([]utils.NodeActivationType, total)
+	for i := range activations {
+		switch {
+		case i < in_count:
+			activations[i] = utils.NullActivation
+		case i < in_count + out_count:
+			activations[i] = substrate.OutputActivation
+		default:
+			activations[i] = substrate.HiddenActivation
+		}
+	}
+
+	connections := make([]*FastNetworkLink, 0)
+	query := func(sources, targets [][]float64, source_start, target_start int) error {
+		for i, source := range sources {
+			for j, target := range targets {
+				weight, expressed, err := d.queryConnection(cppn, source, target, dim)
+				if err != nil {
+					return err
+				}
+				if expressed {
+					connections = append(connections, &FastNetworkLink{
+						SourceIndx:source_start + i,
+						TargetIndx:target_start + j,
+						Weight:weight,
+					})
+				}
+			}
+		}
+		return nil
+	}
+	if hidden_count == 0 {
+		err = query(substrate.Inputs, substrate.Outputs, 0, in_count)
+	} else if err = query(substrate.Inputs, substrate.Hidden, 0, in_count + out_count); err == nil {
+		err = query(substrate.Hidden, substrate.Outputs, in_count + out_count, in_count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFastModularNetworkSolver(0, in_count, out_count, total, activations, connections,
+		make([]float64, total), nil), nil
+}
+
+// Queries CPPN for connection between nodes with given coordinates. Returns the weight of connection and flag to
+// indicate whether connection is expressed.
+func (d *SubstrateDecoder) queryConnection(cppn NetworkSolver, source, target []float64, dim int) (float64, bool, error) {
+	inputs := make([]float64, 0, dim * 2)
+	inputs = append(inputs, source...)
+	inputs = append(inputs, target...)
+	if _, err := cppn.Flush(); err != nil {
+		return 0, false, err
+	}
+	if err := cppn.LoadSensors(inputs); err != nil {
+		return 0, false, err
+	}
+	var err error
+	if d.CPPNSteps > 0 {
+		_, err = cppn.ForwardSteps(d.CPPNSteps)
+	} else {
+		_, err = cppn.RecursiveSteps()
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	outputs := cppn.ReadOutputs()
+	if d.UseLEO {
+		if len(outputs) < 2 {
+			return 0, false, errors.New("CPPN should have weight and link expression outputs")
+		}
+		return outputs[0] * d.MaxWeight, outputs[1] > d.LEOThreshold, nil
+	}
+	if len(outputs) < 1 {
+		return 0, false, errors.New("CPPN should have weight output")
+	}
+	magnitude := math.Abs(outputs[0])
+	if magnitude <= d.WeightThreshold {
+		return 0, false, nil
+	}
+	// scale the weights above threshold to the range (0, MaxWeight]
+	weight := (magnitude - d.WeightThreshold) / (1 - d.WeightThreshold) * d.MaxWeight
+	return math.Copysign(weight, outputs[0]), true, nil
+}
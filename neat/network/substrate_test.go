@@ -0,0 +1,157 @@
+package network
+
+import (
+	"testing"
+	"github.com/yaricom/goNEAT/neat/utils"
+)
+
+// The CPPN returning outputs computed by provided function of coordinates of source and target nodes
+type funcCPPN struct {
+	inputs  []float64
+	outputs []float64
+	query   func(inputs []float64) []float64
+}
+
+func (c *funcCPPN) ForwardSteps(steps int) (bool, error) {
+	c.outputs = c.query(c.inputs)
+	return true, nil
+}
+func (c *funcCPPN) RecursiveSteps() (bool, error) {
+	return c.ForwardSteps(1)
+}
+func (c *funcCPPN) Relax(maxSteps int, maxAllowedSignalDelta float64) (bool, error) {
+	return c.ForwardSteps(maxSteps)
+}
+func (c *funcCPPN) Flush() (bool, error) {
+	c.inputs, c.outputs = nil, nil
+	return true, nil
+}
+func (c *funcCPPN) LoadSensors(inputs []float64) error {
+	if len(inputs) != 4 {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	c.inputs = inputs
+	return nil
+}
+func (c *funcCPPN) ReadOutputs() []float64 {
+	return c.outputs
+}
+func (c *funcCPPN) NodeCount() int {
+	return 0
+}
+func (c *funcCPPN) LinkCount() int {
+	return 0
+}
+
+func buildTestSubstrate() *Substrate {
+	return &Substrate{
+		Inputs:[][]float64{{-1, -1}, {1, -1}},
+		Outputs:[][]float64{{-1, 1}, {1, 1}},
+		OutputActivation:utils.LinearActivation,
+	}
+}
+
+func TestSubstrateDecoder_Decode_weightThreshold(t *testing.T) {
+	// the weight is positive between nodes at the same side of substrate and negative otherwise
+	cppn := &funcCPPN{query:func(in []float64) []float64 {
+		return []float64{in[0] * in[2] * 0.6, 0}
+	}}
+	decoder := SubstrateDecoder{WeightThreshold:0.2, MaxWeight:3.0}
+	solver, err := decoder.Decode(cppn, buildTestSubstrate())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if solver.LinkCount() != 4 {
+		t.Error("Wrong number of links", solver.LinkCount())
+	}
+
+	decoder.WeightThreshold = 0.7
+	if solver, err = decoder.Decode(cppn, buildTestSubstrate()); err != nil {
+		t.Error(err)
+		return
+	}
+	if solver.LinkCount() != 0 {
+		t.Error("No links expected below threshold", solver.LinkCount())
+	}
+}
+
+func TestSubstrateDecoder_Decode_LEO(t *testing.T) {
+	// the strong weight everywhere, but link expressed only between nodes at the same side of substrate
+	cppn := &funcCPPN{query:func(in []float64) []float64 {
+		return []float64{0.5, in[0] * in[2]}
+	}}
+	decoder := SubstrateDecoder{UseLEO:true, LEOThreshold:0.0, MaxWeight:2.0}
+	solver, err := decoder.Decode(cppn, buildTestSubstrate())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if solver.LinkCount() != 2 {
+		t.Error("Wrong number of links", solver.LinkCount())
+	}
+	if err = solver.LoadSensors([]float64{1.0, 2.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = solver.ForwardSteps(1); err != nil {
+		t.Error(err)
+		return
+	}
+	outputs := solver.ReadOutputs()
+	if outputs[0] != 1.0 || outputs[1] != 2.0 {
+		t.Error("Wrong outputs", outputs)
+	}
+}
+
+func TestSubstrateDecoder_Decode_hidden(t *testing.T) {
+	cppn := &funcCPPN{query:func(in []float64) []float64 {
+		return []float64{1.0, 1.0}
+	}}
+	substrate := buildTestSubstrate()
+	substrate.Hidden = [][]float64{{0, 0}}
+	substrate.HiddenActivation = utils.LinearActivation
+	decoder := SubstrateDecoder{UseLEO:true, MaxWeight:1.0}
+	solver, err := decoder.Decode(cppn, substrate)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// inputs connected to hidden and hidden to outputs
+	if solver.LinkCount() != 4 {
+		t.Error("Wrong number of links", solver.LinkCount())
+	}
+	if err = solver.LoadSensors([]float64{1.0, 2.0}); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = solver.ForwardSteps(2); err != nil {
+		t.Error(err)
+		return
+	}
+	if outputs := solver.ReadOutputs(); outputs[0] != 3.0 || outputs[1] != 3.0 {
+		t.Error("Wrong outputs", outputs)
+	}
+}
+
+func TestSubstrateDecoder_Decode_errors(t *testing.T) {
+	cppn := &funcCPPN{query:func(in []float64) []float64 {
+		return []float64{1.0}
+	}}
+	decoder := SubstrateDecoder{UseLEO:true, MaxWeight:1.0}
+	if _, err := decoder.Decode(cppn, buildTestSubstrate()); err == nil {
+		t.Error("Error expected for CPPN without link expression output")
+	}
+
+	decoder = SubstrateDecoder{UseLEO:true, WeightThreshold:0.2}
+	if _, err := decoder.Decode(cppn, buildTestSubstrate()); err == nil {
+		t.Error("Error expected for weight threshold along with LEO")
+	}
+
+	substrate := buildTestSubstrate()
+	substrate.Outputs = append(substrate.Outputs, []float64{1})
+	decoder = SubstrateDecoder{MaxWeight:1.0}
+	if _, err := decoder.Decode(cppn, substrate); err == nil {
+		t.Error("Error expected for coordinates of different dimensions")
+	}
+}
@@ -62,9 +62,14 @@ func newTrait(lenght int) *Trait {
 
 // Perturb the trait parameters slightly
 func (t *Trait) Mutate(trait_mutation_power, trait_param_mut_prob float64) {
+	t.MutateWith(utils.GlobalRand(), trait_mutation_power, trait_param_mut_prob)
+}
+
+// Perturb the trait parameters slightly drawing random numbers from provided generator
+func (t *Trait) MutateWith(rng *rand.Rand, trait_mutation_power, trait_param_mut_prob float64) {
 	for i := 0; i < len(t.Params); i++ {
-		if rand.Float64() > trait_param_mut_prob {
-			t.Params[i] += float64(utils.RandSign()) * rand.Float64() * trait_mutation_power
+		if rng.Float64() > trait_param_mut_prob {
+			t.Params[i] += float64(utils.RandSignWith(rng)) * rng.Float64() * trait_mutation_power
 			if t.Params[i] < 0 {
 				t.Params[i] = 0
 			}
@@ -54,6 +54,8 @@ type NodeActivatorsFactory struct {
 	activators       map[NodeActivationType]ActivationFunction
 	// The map of registered neurons module activators by type
 	moduleActivators map[NodeActivationType]ModuleActivationFunction
+	// The map of registered derivatives of neuron node activators by type
+	derivatives      map[NodeActivationType]ActivationFunction
 
 	// The forward and inverse maps of activator type and function name
 	forward          map[NodeActivationType]string
@@ -65,6 +67,7 @@ func NewNodeActivatorsFactory() *NodeActivatorsFactory {
 	af := &NodeActivatorsFactory{
 		activators:make(map[NodeActivationType]ActivationFunction),
 		moduleActivators:make(map[NodeActivationType]ModuleActivationFunction),
+		derivatives:make(map[NodeActivationType]ActivationFunction),
 		forward:make(map[NodeActivationType]string),
 		inverse:make(map[string]NodeActivationType),
 	}
@@ -90,6 +93,28 @@ func NewNodeActivatorsFactory() *NodeActivatorsFactory {
 	af.Register(SineActivation, sineFunction, "SineActivation")
 	af.Register(StepActivation, stepFunction, "StepActivation")
 
+	// Register derivatives of neuron node activators
+	af.RegisterDerivative(SigmoidPlainActivation, plainSigmoidDerivative)
+	af.RegisterDerivative(SigmoidReducedActivation, reducedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidSteepenedActivation, steepenedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidBipolarActivation, bipolarSigmoidDerivative)
+	af.RegisterDerivative(SigmoidApproximationActivation, approximationSigmoidDerivative)
+	af.RegisterDerivative(SigmoidSteepenedApproximationActivation, approximationSteepenedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidInverseAbsoluteActivation, inverseAbsoluteSigmoidDerivative)
+	af.RegisterDerivative(SigmoidLeftShiftedActivation, leftShiftedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidLeftShiftedSteepenedActivation, leftShiftedSteepenedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidRightShiftedSteepenedActivation, rightShiftedSteepenedSigmoidDerivative)
+
+	af.RegisterDerivative(TanhActivation, hyperbolicTangentDerivative)
+	af.RegisterDerivative(GaussianBipolarActivation, bipolarGaussianDerivative)
+	af.RegisterDerivative(LinearActivation, linearDerivative)
+	af.RegisterDerivative(LinearAbsActivation, absoluteLinearDerivative)
+	af.RegisterDerivative(LinearClippedActivation, clippedLinearDerivative)
+	af.RegisterDerivative(NullActivation, zeroDerivative)
+	af.RegisterDerivative(SignActivation, zeroDerivative)
+	af.RegisterDerivative(SineActivation, sineFunctionDerivative)
+	af.RegisterDerivative(StepActivation, zeroDerivative)
+
 	// register neuron modules activators
 	af.RegisterModule(MultiplyModuleActivation, multiplyModule, "MultiplyModuleActivation")
 	af.RegisterModule(MaxModuleActivation, maxModule, "MaxModuleActivation")
@@ -118,6 +143,17 @@ func (a *NodeActivatorsFactory) ActivateModuleByType(inputs[] float64, aux_param
 	}
 }
 
+// Method to calculate derivative of activation function with specified type at given input, i.e. its rate of change
+// with respect to the input, which is used to compute gradients by backpropagation. Will return error and zero
+// derivative if no derivative registered for activation type.
+func (a *NodeActivatorsFactory) DerivativeByType(input float64, aux_params[]float64, a_type NodeActivationType) (float64, error) {
+	if fn, ok := a.derivatives[a_type]; ok {
+		return fn(input, aux_params), nil
+	} else {
+		return 0.0, errors.New(fmt.Sprintf("No derivative of neuron activation type: %d", a_type))
+	}
+}
+
 // Registers given neuron activation function with provided type and name into the factory
 func (a *NodeActivatorsFactory) Register(a_type NodeActivationType, a_func ActivationFunction, f_name string) {
 	// store function
@@ -127,6 +163,12 @@ func (a *NodeActivatorsFactory) Register(a_type NodeActivationType, a_func Activ
 	a.inverse[f_name] = a_type
 }
 
+// Registers derivative of neuron activation function with provided type, the activation function should be
+// registered with Register
+func (a *NodeActivatorsFactory) RegisterDerivative(a_type NodeActivationType, a_func ActivationFunction) {
+	a.derivatives[a_type] = a_func
+}
+
 // Registers given neuron module activation function with provided type and name into the factory
 func (a *NodeActivatorsFactory) RegisterModule(a_type NodeActivationType, a_func ModuleActivationFunction, f_name string) {
 	// store function
@@ -272,6 +314,89 @@ var (
 	}
 )
 
+// The derivatives of activation functions with respect to input. The piecewise functions have derivatives of their
+// pieces, and the step functions have zero derivative.
+var (
+	// Returns derivative of sigmoid 1 / (1 + exp(-(slope * input + shift)))
+	sigmoidDerivative = func(input, slope, shift float64) float64 {
+		s := 1.0 / (1.0 + math.Exp(-(slope * input + shift)))
+		return slope * s * (1.0 - s)
+	}
+	plainSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		return sigmoidDerivative(input, 1.0, 0.0)
+	}
+	reducedSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		return sigmoidDerivative(input, 0.5, 0.0)
+	}
+	steepenedSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		return sigmoidDerivative(input, 4.924273, 0.0)
+	}
+	bipolarSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		return 2.0 * sigmoidDerivative(input, 4.924273, 0.0)
+	}
+	approximationSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		if input < -4.0 || input >= 4.0 {
+			return 0.0
+		} else if input < 0.0 {
+			return (input + 4.0) * 0.0625
+		} else {
+			return (4.0 - input) * 0.0625
+		}
+	}
+	approximationSteepenedSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		if input < -1.0 || input >= 1.0 {
+			return 0.0
+		} else if input < 0.0 {
+			return input + 1.0
+		} else {
+			return 1.0 - input
+		}
+	}
+	inverseAbsoluteSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		d := 1.0 + math.Abs(input)
+		return 0.5 / (d * d)
+	}
+	leftShiftedSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		return sigmoidDerivative(input, 1.0, 2.4621365)
+	}
+	leftShiftedSteepenedSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		return sigmoidDerivative(input, 4.924273, 2.4621365)
+	}
+	rightShiftedSteepenedSigmoidDerivative = func(input float64, aux_params[]float64) float64 {
+		return sigmoidDerivative(input, 4.924273, -2.4621365)
+	}
+
+	hyperbolicTangentDerivative = func(input float64, aux_params[]float64) float64 {
+		t := math.Tanh(0.9 * input)
+		return 0.9 * (1.0 - t * t)
+	}
+	bipolarGaussianDerivative = func(input float64, aux_params[]float64) float64 {
+		return -25.0 * input * math.Exp(-math.Pow(input * 2.5, 2.0))
+	}
+	absoluteLinearDerivative = func(input float64, aux_params[]float64) float64 {
+		if math.Signbit(input) {
+			return -1.0
+		}
+		return 1.0
+	}
+	clippedLinearDerivative = func(input float64, aux_params[]float64) float64 {
+		if input < -1.0 || input > 1.0 {
+			return 0.0
+		}
+		return 1.0
+	}
+	linearDerivative = func(input float64, aux_params[]float64) float64 {
+		return 1.0
+	}
+	// The derivative of constant and step functions, which is zero almost everywhere
+	zeroDerivative = func(input float64, aux_params[]float64) float64 {
+		return 0.0
+	}
+	sineFunctionDerivative = func(input float64, aux_params[]float64) float64 {
+		return 2.0 * math.Cos(2.0 * input)
+	}
+)
+
 // The modular activators
 var (
 	// Multiplies input values and returns multiplication result
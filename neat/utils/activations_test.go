@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+	"math"
+)
+
+func TestNodeActivatorsFactory_DerivativeByType(t *testing.T) {
+	// the inputs are chosen off the breaks of piecewise functions
+	inputs := []float64{-4.5, -2.3, -0.7, -0.2, 0.3, 0.6, 1.7, 3.1, 5.2}
+	h := 1e-6
+	for a_type := SigmoidPlainActivation; a_type <= StepActivation; a_type++ {
+		name, _ := NodeActivators.ActivationNameFromType(a_type)
+		for _, input := range inputs {
+			derivative, err := NodeActivators.DerivativeByType(input, nil, a_type)
+			if err != nil {
+				t.Error(name, err)
+				return
+			}
+			upper, _ := NodeActivators.ActivateByType(input + h, nil, a_type)
+			lower, _ := NodeActivators.ActivateByType(input - h, nil, a_type)
+			if numeric := (upper - lower) / (2 * h); math.Abs(numeric - derivative) > 1e-5 {
+				t.Error("Wrong derivative of", name, "at", input, derivative, numeric)
+			}
+		}
+	}
+
+	if _, err := NodeActivators.DerivativeByType(0.0, nil, MultiplyModuleActivation); err == nil {
+		t.Error("Error expected for activation type without derivative")
+	}
+}
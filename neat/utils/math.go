@@ -4,9 +4,37 @@ import (
 	"math/rand"
 )
 
+// The source of random numbers backed by the top-level functions of math/rand
+type globalSource struct{}
+
+func (globalSource) Int63() int64 {
+	return rand.Int63()
+}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// The global source can not be seeded through generator, the top-level functions of math/rand should be used instead
+func (globalSource) Seed(int64) {}
+
+// The random number generator drawing from the global source of math/rand
+var globalRand = rand.New(globalSource{})
+
+// Returns the random number generator drawing from the global source of math/rand, i.e. producing the same sequence
+// as the top-level functions. It is safe for concurrent use.
+func GlobalRand() *rand.Rand {
+	return globalRand
+}
+
 // Returns subsequent random positive or negative integer value (1 or -1) to randomize value sign
 func RandSign() int32 {
-	v := rand.Int()
+	return RandSignWith(globalRand)
+}
+
+// Returns subsequent random positive or negative integer value (1 or -1) drawn from provided generator
+func RandSignWith(rng *rand.Rand) int32 {
+	v := rng.Int()
 	if (v % 2) == 0 {
 		return -1
 	} else {
@@ -18,6 +46,11 @@ func RandSign() int32 {
 // The probability that a segment will be selected is given by that segment's value in the probabilities array.
 // Returns segment index or -1 if something goes awfully wrong
 func SingleRouletteThrow(probabilities []float64) int  {
+	return SingleRouletteThrowWith(globalRand, probabilities)
+}
+
+// The same as SingleRouletteThrow but the ball is thrown by provided random number generator
+func SingleRouletteThrowWith(rng *rand.Rand, probabilities []float64) int  {
 	total := 0.0
 
 	// collect all probabilities
@@ -26,7 +59,7 @@ func SingleRouletteThrow(probabilities []float64) int  {
 	}
 
 	// throw the ball and collect result
-	throwValue := rand.Float64() * total
+	throwValue := rng.Float64() * total
 
 	accumulator := 0.0
 	for i, v := range probabilities {
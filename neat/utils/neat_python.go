@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The mapping of NEAT-Python activation function names to the closest node activation types. Note that some functions
+// differ in steepness from their NEAT-Python counterparts, e.g. NEAT-Python sigmoid is 1/(1 + exp(-5x)) while
+// SigmoidSteepenedActivation is 1/(1 + exp(-4.924273x)).
+var neatPythonActivations = map[string]NodeActivationType{
+	"sigmoid":SigmoidSteepenedActivation,
+	"tanh":TanhActivation,
+	"gauss":GaussianBipolarActivation,
+	"identity":LinearActivation,
+	"abs":LinearAbsActivation,
+	"clamped":LinearClippedActivation,
+	"sin":SineActivation,
+}
+
+// Returns node activation type corresponding to the given NEAT-Python activation function name
+func ActivationTypeFromNeatPython(name string) (NodeActivationType, error) {
+	if t, ok := neatPythonActivations[name]; ok {
+		return t, nil
+	}
+	return 0, errors.New("Unsupported NEAT-Python activation function: " + name)
+}
+
+// Returns the name of NEAT-Python activation function corresponding to the given node activation type
+func ActivationTypeToNeatPython(a_type NodeActivationType) (string, error) {
+	for name, t := range neatPythonActivations {
+		if t == a_type {
+			return name, nil
+		}
+	}
+	return "", errors.New(fmt.Sprintf("Activation type: %d has no NEAT-Python counterpart", a_type))
+}